@@ -59,6 +59,9 @@ type ConnectionArgs struct {
 	// OpenID Connect tokens
 	OIDCTokens *oidc.Tokens[*oidc.IDTokenClaims]
 
+	// API token sent as bearer token in place of a TLS client certificate
+	BearerToken string
+
 	// Skip automatic GetServer request upon connection
 	SkipGetServer bool
 
@@ -371,6 +374,7 @@ func httpsIncus(ctx context.Context, requestURL string, args *ConnectionArgs) (I
 		httpBaseURL:        *httpBaseURL,
 		httpProtocol:       "https",
 		httpUserAgent:      args.UserAgent,
+		bearerToken:        args.BearerToken,
 		ctxConnected:       ctxConnected,
 		ctxConnectedCancel: ctxConnectedCancel,
 		eventConns:         make(map[string]*websocket.Conn),
@@ -51,6 +51,8 @@ type ProtocolIncus struct {
 	project       string
 
 	oidcClient *oidcClient
+
+	bearerToken string
 }
 
 // Disconnect gets rid of any background goroutines.
@@ -195,6 +197,8 @@ func (r *ProtocolIncus) addClientHeaders(req *http.Request) {
 
 	if r.oidcClient != nil {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.oidcClient.getAccessToken()))
+	} else if r.bearerToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.bearerToken))
 	}
 }
 
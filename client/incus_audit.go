@@ -0,0 +1,38 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Audit log functions
+
+// GetAuditEntries returns the most recent authentication decisions of the server.
+func (r *ProtocolIncus) GetAuditEntries() ([]api.AuditEntry, error) {
+	return r.GetAuditEntriesWithFilter(nil)
+}
+
+// GetAuditEntriesWithFilter returns the most recent authentication decisions of the server matching the filters.
+func (r *ProtocolIncus) GetAuditEntriesWithFilter(filters []string) ([]api.AuditEntry, error) {
+	if !r.HasExtension("auth_audit") {
+		return nil, errors.New("The server is missing the required \"auth_audit\" API extension")
+	}
+
+	entries := []api.AuditEntry{}
+
+	v := url.Values{}
+	if len(filters) > 0 {
+		v.Set("filter", parseFilters(filters))
+	}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/audit?%s", v.Encode()), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
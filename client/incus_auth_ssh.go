@@ -0,0 +1,84 @@
+package incus
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// SSH key authentication functions
+
+// CreateSSHChallenge requests a challenge to be signed by the SSH public key.
+func (r *ProtocolIncus) CreateSSHChallenge(publicKey string) (*api.AuthSSHChallenge, error) {
+	if !r.HasExtension("auth_ssh") {
+		return nil, errors.New("The server is missing the required \"auth_ssh\" API extension")
+	}
+
+	challenge := api.AuthSSHChallenge{}
+
+	// Send the request
+	_, err := r.queryStruct("POST", "/auth/ssh", api.AuthSSHPost{PublicKey: publicKey}, "", &challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// CreateSSHSession exchanges a signed challenge for a session token.
+func (r *ProtocolIncus) CreateSSHSession(req api.AuthSSHPost) (*api.AuthSSHSession, error) {
+	if !r.HasExtension("auth_ssh") {
+		return nil, errors.New("The server is missing the required \"auth_ssh\" API extension")
+	}
+
+	session := api.AuthSSHSession{}
+
+	// Send the request
+	_, err := r.queryStruct("POST", "/auth/ssh", req, "", &session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// AuthenticateSSH authenticates with the SSH signer (typically provided by an SSH agent)
+// and uses the resulting session for all subsequent requests.
+func (r *ProtocolIncus) AuthenticateSSH(signer ssh.Signer) (*api.AuthSSHSession, error) {
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+
+	challenge, err := r.CreateSSHChallenge(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// RSA keys default to SHA-1 signatures which the server rejects, so request SHA-512 ones instead.
+	var signature *ssh.Signature
+	algoSigner, ok := signer.(ssh.AlgorithmSigner)
+	if ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		signature, err = algoSigner.SignWithAlgorithm(rand.Reader, []byte(challenge.Challenge), ssh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = signer.Sign(rand.Reader, []byte(challenge.Challenge))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := r.CreateSSHSession(api.AuthSSHPost{
+		PublicKey: publicKey,
+		Challenge: challenge.Challenge,
+		Signature: &api.AuthSSHSignature{Format: signature.Format, Blob: signature.Blob},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.bearerToken = session.Token
+
+	return session, nil
+}
@@ -0,0 +1,110 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// API token handling functions
+
+// GetAuthTokenNames returns a list of API token names.
+func (r *ProtocolIncus) GetAuthTokenNames() ([]string, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/auth/tokens"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetAuthTokens returns a list of API tokens.
+func (r *ProtocolIncus) GetAuthTokens() ([]api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	tokens := []api.AuthToken{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/auth/tokens?recursion=1", nil, "", &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns the API token with the provided name.
+func (r *ProtocolIncus) GetAuthToken(name string) (*api.AuthToken, string, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, "", errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	token := api.AuthToken{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "", &token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &token, etag, nil
+}
+
+// CreateAuthToken issues a new API token and returns its secret.
+func (r *ProtocolIncus) CreateAuthToken(token api.AuthTokensPost) (*api.AuthTokenSecret, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	secret := api.AuthTokenSecret{}
+
+	// Send the request
+	_, err := r.queryStruct("POST", "/auth/tokens", token, "", &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// UpdateAuthToken updates the API token definition.
+func (r *ProtocolIncus) UpdateAuthToken(name string, token api.AuthTokenPut, ETag string) error {
+	if !r.HasExtension("auth_tokens") {
+		return errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), token, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthToken revokes an API token.
+func (r *ProtocolIncus) DeleteAuthToken(name string) error {
+	if !r.HasExtension("auth_tokens") {
+		return errors.New("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package incus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/cancel"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// GetBackupGroupNames returns a list of backup group names.
+func (r *ProtocolIncus) GetBackupGroupNames() ([]string, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/backup-groups"
+	_, err = r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetBackupGroups returns a list of backup groups.
+func (r *ProtocolIncus) GetBackupGroups() ([]api.BackupGroup, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []api.BackupGroup{}
+
+	_, err = r.queryStruct("GET", "/backup-groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetBackupGroup returns a backup group.
+func (r *ProtocolIncus) GetBackupGroup(name string) (*api.BackupGroup, string, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, "", err
+	}
+
+	group := api.BackupGroup{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/backup-groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateBackupGroup snapshots a set of volumes (and optionally an instance) at the same point in time and bundles their backups.
+func (r *ProtocolIncus) CreateBackupGroup(group api.BackupGroupsPost) (Operation, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := r.queryOperation("POST", "/backup-groups", group, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteBackupGroup deletes a backup group.
+func (r *ProtocolIncus) DeleteBackupGroup(name string) error {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query("DELETE", fmt.Sprintf("/backup-groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetBackupGroupFile requests the backup group bundle.
+func (r *ProtocolIncus) GetBackupGroupFile(name string, req *BackupFileRequest) (*BackupFileResponse, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the URL
+	uri := fmt.Sprintf("%s/1.0/backup-groups/%s/export", r.httpBaseURL.String(), url.PathEscape(name))
+
+	// Add project/target
+	uri, err = r.setQueryAttributes(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the download request
+	request, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.httpUserAgent != "" {
+		request.Header.Set("User-Agent", r.httpUserAgent)
+	}
+
+	// Start the request
+	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = response.Body.Close() }()
+	defer close(doneCh)
+
+	if response.StatusCode != http.StatusOK {
+		_, _, err := incusParseResponse(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle the data
+	body := response.Body
+	if req.ProgressHandler != nil {
+		body = &ioprogress.ProgressReader{
+			ReadCloser: response.Body,
+			Tracker: &ioprogress.ProgressTracker{
+				Length: response.ContentLength,
+				Handler: func(percent int64, speed int64) {
+					req.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+				},
+			},
+		}
+	}
+
+	size, err := io.Copy(req.BackupFile, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := BackupFileResponse{}
+	resp.Size = size
+
+	return &resp, nil
+}
+
+// CreateBackupGroupFromFile restores all the members of a backup group bundle.
+func (r *ProtocolIncus) CreateBackupGroupFromFile(bundle io.Reader) (Operation, error) {
+	err := r.CheckExtension("backup_groups")
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request.
+	reqURL, err := r.setQueryAttributes(fmt.Sprintf("%s/1.0/backup-groups", r.httpBaseURL.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", reqURL, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	// Send the request.
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Handle errors.
+	response, _, err := incusParseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get to the operation.
+	respOperation, err := response.MetadataAsOperation()
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup an Operation wrapper.
+	op := operation{
+		Operation: *respOperation,
+		r:         r,
+		chActive:  make(chan bool),
+	}
+
+	return &op, nil
+}
@@ -0,0 +1,123 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Backup target handling functions
+
+// GetBackupTargetNames returns a list of backup target names.
+func (r *ProtocolIncus) GetBackupTargetNames() ([]string, error) {
+	if !r.HasExtension("backup_targets") {
+		return nil, errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/backup-targets"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetBackupTargets returns a list of backup targets.
+func (r *ProtocolIncus) GetBackupTargets() ([]api.NamedBackupTarget, error) {
+	if !r.HasExtension("backup_targets") {
+		return nil, errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	targets := []api.NamedBackupTarget{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/backup-targets?recursion=1", nil, "", &targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// GetBackupTarget returns the backup target with the provided name.
+func (r *ProtocolIncus) GetBackupTarget(name string) (*api.NamedBackupTarget, string, error) {
+	if !r.HasExtension("backup_targets") {
+		return nil, "", errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	target := api.NamedBackupTarget{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/backup-targets/%s", url.PathEscape(name)), nil, "", &target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &target, etag, nil
+}
+
+// CreateBackupTarget defines a new backup target.
+func (r *ProtocolIncus) CreateBackupTarget(target api.NamedBackupTargetsPost) error {
+	if !r.HasExtension("backup_targets") {
+		return errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", "/backup-targets", target, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBackupTarget updates the backup target definition.
+func (r *ProtocolIncus) UpdateBackupTarget(name string, target api.NamedBackupTargetPut, ETag string) error {
+	if !r.HasExtension("backup_targets") {
+		return errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/backup-targets/%s", url.PathEscape(name)), target, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameBackupTarget renames a backup target.
+func (r *ProtocolIncus) RenameBackupTarget(name string, target api.NamedBackupTargetPost) error {
+	if !r.HasExtension("backup_targets") {
+		return errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", fmt.Sprintf("/backup-targets/%s", url.PathEscape(name)), target, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBackupTarget removes a backup target.
+func (r *ProtocolIncus) DeleteBackupTarget(name string) error {
+	if !r.HasExtension("backup_targets") {
+		return errors.New("The server is missing the required \"backup_targets\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/backup-targets/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
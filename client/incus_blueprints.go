@@ -0,0 +1,170 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Blueprint handling functions
+
+// GetBlueprintNames returns a list of available blueprint names.
+func (r *ProtocolIncus) GetBlueprintNames() ([]string, error) {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/blueprints"
+	_, err = r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetBlueprints returns a list of available Blueprint structs.
+func (r *ProtocolIncus) GetBlueprints() ([]api.Blueprint, error) {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return nil, err
+	}
+
+	blueprints := []api.Blueprint{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct("GET", "/blueprints?recursion=1", nil, "", &blueprints)
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprints, nil
+}
+
+// GetBlueprintsWithFilter returns a filtered list of available Blueprint structs.
+func (r *ProtocolIncus) GetBlueprintsWithFilter(filters []string) ([]api.Blueprint, error) {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return nil, err
+	}
+
+	blueprints := []api.Blueprint{}
+
+	v := url.Values{}
+	v.Set("recursion", "1")
+	v.Set("filter", parseFilters(filters))
+
+	_, err = r.queryStruct("GET", fmt.Sprintf("/blueprints?%s", v.Encode()), nil, "", &blueprints)
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprints, nil
+}
+
+// GetBlueprint returns a Blueprint entry for the provided name.
+func (r *ProtocolIncus) GetBlueprint(name string) (*api.Blueprint, string, error) {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return nil, "", err
+	}
+
+	blueprint := api.Blueprint{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/blueprints/%s", url.PathEscape(name)), nil, "", &blueprint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &blueprint, etag, nil
+}
+
+// GetBlueprintVersion returns a retained version of a blueprint.
+func (r *ProtocolIncus) GetBlueprintVersion(name string, version int64) (*api.Blueprint, error) {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return nil, err
+	}
+
+	blueprint := api.Blueprint{}
+
+	// Fetch the raw value
+	u := api.NewURL().Path("blueprints", name).WithQuery("version", strconv.FormatInt(version, 10))
+	_, err = r.queryStruct("GET", u.String(), nil, "", &blueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blueprint, nil
+}
+
+// CreateBlueprint defines a new blueprint.
+func (r *ProtocolIncus) CreateBlueprint(blueprint api.BlueprintsPost) error {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query("POST", "/blueprints", blueprint, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateBlueprint updates the blueprint to match the provided BlueprintPut struct.
+func (r *ProtocolIncus) UpdateBlueprint(name string, blueprint api.BlueprintPut, ETag string) error {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query("PUT", fmt.Sprintf("/blueprints/%s", url.PathEscape(name)), blueprint, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameBlueprint renames an existing blueprint entry.
+func (r *ProtocolIncus) RenameBlueprint(name string, blueprint api.BlueprintPost) error {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query("POST", fmt.Sprintf("/blueprints/%s", url.PathEscape(name)), blueprint, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteBlueprint deletes a blueprint.
+func (r *ProtocolIncus) DeleteBlueprint(name string) error {
+	err := r.CheckExtension("instance_blueprints")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query("DELETE", fmt.Sprintf("/blueprints/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Certificate group handling functions
+
+// GetCertificateGroupNames returns a list of certificate group names.
+func (r *ProtocolIncus) GetCertificateGroupNames() ([]string, error) {
+	if !r.HasExtension("certificate_groups") {
+		return nil, errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/certificates/groups"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetCertificateGroups returns a list of certificate groups.
+func (r *ProtocolIncus) GetCertificateGroups() ([]api.CertificateGroup, error) {
+	if !r.HasExtension("certificate_groups") {
+		return nil, errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	groups := []api.CertificateGroup{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/certificates/groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetCertificateGroup returns the certificate group with the provided name.
+func (r *ProtocolIncus) GetCertificateGroup(name string) (*api.CertificateGroup, string, error) {
+	if !r.HasExtension("certificate_groups") {
+		return nil, "", errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	group := api.CertificateGroup{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/certificates/groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateCertificateGroup creates a new certificate group.
+func (r *ProtocolIncus) CreateCertificateGroup(group api.CertificateGroupsPost) error {
+	if !r.HasExtension("certificate_groups") {
+		return errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", "/certificates/groups", group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCertificateGroup updates the certificate group definition.
+func (r *ProtocolIncus) UpdateCertificateGroup(name string, group api.CertificateGroupPut, ETag string) error {
+	if !r.HasExtension("certificate_groups") {
+		return errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/certificates/groups/%s", url.PathEscape(name)), group, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameCertificateGroup renames an existing certificate group.
+func (r *ProtocolIncus) RenameCertificateGroup(name string, group api.CertificateGroupPost) error {
+	if !r.HasExtension("certificate_groups") {
+		return errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", fmt.Sprintf("/certificates/groups/%s", url.PathEscape(name)), group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCertificateGroup removes a certificate group.
+func (r *ProtocolIncus) DeleteCertificateGroup(name string) error {
+	if !r.HasExtension("certificate_groups") {
+		return errors.New("The server is missing the required \"certificate_groups\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/certificates/groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
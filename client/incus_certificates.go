@@ -93,6 +93,22 @@ func (r *ProtocolIncus) UpdateCertificate(fingerprint string, certificate api.Ce
 	return nil
 }
 
+// RotateCertificate replaces a certificate in the Incus trust store with a new one.
+// The request must carry a signature of the rotation made with the new key (see tls.SignCertificateRotation).
+func (r *ProtocolIncus) RotateCertificate(fingerprint string, certificate api.CertificateRotatePost) error {
+	if !r.HasExtension("certificate_rotation") {
+		return errors.New("The server is missing the required \"certificate_rotation\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("POST", fmt.Sprintf("/certificates/%s/rotate", url.PathEscape(fingerprint)), certificate, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteCertificate removes a certificate from the Incus trust store.
 func (r *ProtocolIncus) DeleteCertificate(fingerprint string) error {
 	// Send the request
@@ -122,3 +138,86 @@ func (r *ProtocolIncus) CreateCertificateToken(certificate api.CertificatesPost)
 
 	return op, nil
 }
+
+// GetCertificateTokens returns the pending certificate add tokens.
+func (r *ProtocolIncus) GetCertificateTokens() ([]api.CertificateToken, error) {
+	if !r.HasExtension("certificate_tokens") {
+		return nil, errors.New("The server is missing the required \"certificate_tokens\" API extension")
+	}
+
+	tokens := []api.CertificateToken{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/certificates/tokens?recursion=1", nil, "", &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetCertificateToken returns the pending certificate add token with the provided ID.
+func (r *ProtocolIncus) GetCertificateToken(id string) (*api.CertificateToken, error) {
+	if !r.HasExtension("certificate_tokens") {
+		return nil, errors.New("The server is missing the required \"certificate_tokens\" API extension")
+	}
+
+	token := api.CertificateToken{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/certificates/tokens/%s", url.PathEscape(id)), nil, "", &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// DeleteCertificateToken revokes a pending certificate add token.
+func (r *ProtocolIncus) DeleteCertificateToken(id string) error {
+	if !r.HasExtension("certificate_tokens") {
+		return errors.New("The server is missing the required \"certificate_tokens\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/certificates/tokens/%s", url.PathEscape(id)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExportCertificates returns a bundle of all the certificates of the trust store.
+func (r *ProtocolIncus) ExportCertificates() (*api.CertificatesBundle, error) {
+	if !r.HasExtension("certificates_bundle") {
+		return nil, errors.New("The server is missing the required \"certificates_bundle\" API extension")
+	}
+
+	bundle := api.CertificatesBundle{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", "/certificates/export", nil, "", &bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// ImportCertificates adds a bundle of certificates to the trust store.
+func (r *ProtocolIncus) ImportCertificates(bundle api.CertificatesImportPost) (*api.CertificatesImportResult, error) {
+	if !r.HasExtension("certificates_bundle") {
+		return nil, errors.New("The server is missing the required \"certificates_bundle\" API extension")
+	}
+
+	result := api.CertificatesImportResult{}
+
+	// Send the request
+	_, err := r.queryStruct("POST", "/certificates/import", bundle, "", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
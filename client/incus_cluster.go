@@ -224,6 +224,39 @@ func (r *ProtocolIncus) UpdateClusterMemberState(name string, state api.ClusterM
 	return op, nil
 }
 
+// GetClusterRebalancePlan computes the instance moves a cluster rebalance would perform, without moving any instance.
+func (r *ProtocolIncus) GetClusterRebalancePlan(rebalance api.ClusterRebalancePost) (*api.ClusterRebalancePlan, error) {
+	if !r.HasExtension("cluster_rebalance_operation") {
+		return nil, errors.New("The server is missing the required \"cluster_rebalance_operation\" API extension")
+	}
+
+	rebalance.DryRun = true
+
+	plan := api.ClusterRebalancePlan{}
+	_, err := r.queryStruct("POST", "/cluster/rebalance", rebalance, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// RebalanceCluster redistributes the instances across the cluster members.
+func (r *ProtocolIncus) RebalanceCluster(rebalance api.ClusterRebalancePost) (Operation, error) {
+	if !r.HasExtension("cluster_rebalance_operation") {
+		return nil, errors.New("The server is missing the required \"cluster_rebalance_operation\" API extension")
+	}
+
+	rebalance.DryRun = false
+
+	op, _, err := r.queryOperation("POST", "/cluster/rebalance", rebalance, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolIncus) GetClusterGroups() ([]api.ClusterGroup, error) {
 	if !r.HasExtension("clustering_groups") {
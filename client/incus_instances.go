@@ -3,6 +3,8 @@ package incus
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -318,6 +320,27 @@ func (r *ProtocolIncus) RebuildInstanceFromImage(source ImageServer, image api.I
 	return r.tryRebuildInstance(instanceName, req, info.Addresses, nil)
 }
 
+// RebuildInstanceFromBackup rebuilds an instance from a backup file.
+func (r *ProtocolIncus) RebuildInstanceFromBackup(instanceName string, backupFile io.Reader) (Operation, error) {
+	err := r.CheckExtension("instance_rebuild_backup")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/rebuild", path, url.PathEscape(instanceName)), backupFile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // RebuildInstance rebuilds an instance as empty.
 func (r *ProtocolIncus) RebuildInstance(instanceName string, instance api.InstanceRebuildPost) (op Operation, err error) {
 	err = r.CheckExtension("instances_rebuild")
@@ -444,6 +467,53 @@ func (r *ProtocolIncus) GetInstancesFullAllProjectsWithFilter(instanceType api.I
 	return instances, nil
 }
 
+// GetInstancesWithState returns a list of instances including their state, optionally filtered.
+func (r *ProtocolIncus) GetInstancesWithState(instanceType api.InstanceType, filters []string) ([]api.InstanceFull, error) {
+	return r.getInstancesWithState(instanceType, filters, false)
+}
+
+// GetInstancesWithStateAllProjects returns a list of instances including their state from all projects, optionally filtered.
+func (r *ProtocolIncus) GetInstancesWithStateAllProjects(instanceType api.InstanceType, filters []string) ([]api.InstanceFull, error) {
+	if !r.HasExtension("instance_all_projects") {
+		return nil, errors.New("The server is missing the required \"instance_all_projects\" API extension")
+	}
+
+	return r.getInstancesWithState(instanceType, filters, true)
+}
+
+func (r *ProtocolIncus) getInstancesWithState(instanceType api.InstanceType, filters []string, allProjects bool) ([]api.InstanceFull, error) {
+	err := r.CheckExtension("instances_state_bulk")
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []api.InstanceFull{}
+
+	path, v, err := r.instanceTypeToPath(instanceType)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Set("recursion", "1")
+	v.Set("state", "true")
+
+	if allProjects {
+		v.Set("all-projects", "true")
+	}
+
+	if len(filters) > 0 {
+		v.Set("filter", parseFilters(filters))
+	}
+
+	// Fetch the raw value
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s?%s", path, v.Encode()), nil, "", &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
 // GetInstance returns the instance entry for the provided name.
 func (r *ProtocolIncus) GetInstance(name string) (*api.Instance, string, error) {
 	instance := api.Instance{}
@@ -604,6 +674,13 @@ func (r *ProtocolIncus) CreateInstance(instance api.InstancesPost) (Operation, e
 		}
 	}
 
+	if instance.Source.Type == "backup" {
+		err := r.CheckExtension("backup_import_url")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	op, _, err := r.queryOperation("POST", path, instance, "")
 	if err != nil {
@@ -793,6 +870,10 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 			}
 		}
 
+		if args.AllowRunning && !r.HasExtension("instance_copy_allow_running") {
+			return nil, errors.New("The server is missing the required \"instance_copy_allow_running\" API extension")
+		}
+
 		// Allow overriding the target name
 		if args.Name != "" {
 			req.Name = args.Name
@@ -803,6 +884,7 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 		req.Source.Refresh = args.Refresh
 		req.Source.RefreshExcludeOlder = args.RefreshExcludeOlder
 		req.Source.AllowInconsistent = args.AllowInconsistent
+		req.Source.AllowRunning = args.AllowRunning
 	}
 
 	if req.Source.Live {
@@ -854,6 +936,10 @@ func (r *ProtocolIncus) CopyInstance(source InstanceServer, instance api.Instanc
 		return &rop, nil
 	}
 
+	if req.Source.AllowRunning {
+		return nil, errors.New("Copying running instances from a temporary snapshot is only supported within the same server")
+	}
+
 	// Source request
 	sourceReq := api.InstancePost{
 		Migration:         true,
@@ -1628,6 +1714,90 @@ func (r *ProtocolIncus) DeleteInstanceFile(instanceName string, filePath string)
 	return nil
 }
 
+// GetInstanceFileRecursive retrieves the provided path and everything below it from the instance as a tarball.
+func (r *ProtocolIncus) GetInstanceFileRecursive(instanceName string, filePath string) (io.ReadCloser, error) {
+	err := r.CheckExtension("instance_file_recursive")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the HTTP request
+	requestURL := fmt.Sprintf("%s/1.0%s/%s/files?path=%s&recursive=true", r.httpBaseURL.String(), path, url.PathEscape(instanceName), url.QueryEscape(filePath))
+
+	requestURL, err = r.setQueryAttributes(requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := incusParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
+// CreateInstanceFileRecursive unpacks the provided tarball into the given directory of the instance.
+func (r *ProtocolIncus) CreateInstanceFileRecursive(instanceName string, filePath string, content io.Reader) error {
+	err := r.CheckExtension("instance_file_recursive")
+	if err != nil {
+		return err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return err
+	}
+
+	// Prepare the HTTP request
+	requestURL := fmt.Sprintf("%s/1.0%s/%s/files?path=%s&recursive=true", r.httpBaseURL.String(), path, url.PathEscape(instanceName), url.QueryEscape(filePath))
+
+	requestURL, err = r.setQueryAttributes(requestURL)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", requestURL, content)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-tar")
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+
+	// Check the return value for a cleaner error
+	_, _, err = incusParseResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // rawSFTPConn connects to the apiURL, upgrades to an SFTP raw connection and returns it.
 func (r *ProtocolIncus) rawSFTPConn(apiURL *url.URL) (net.Conn, error) {
 	// Get the HTTP transport.
@@ -2199,6 +2369,37 @@ func (r *ProtocolIncus) GetInstanceState(name string) (*api.InstanceState, strin
 	return &state, etag, nil
 }
 
+// GetInstanceProcesses returns the list of processes running inside the instance.
+func (r *ProtocolIncus) GetInstanceProcesses(name string) ([]api.InstanceProcess, error) {
+	err := r.CheckExtension("instance_processes")
+	if err != nil {
+		return nil, err
+	}
+
+	var uri string
+
+	if r.IsAgent() {
+		uri = "/processes"
+	} else {
+		path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+		if err != nil {
+			return nil, err
+		}
+
+		uri = fmt.Sprintf("%s/%s/processes", path, url.PathEscape(name))
+	}
+
+	processes := []api.InstanceProcess{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct("GET", uri, nil, "", &processes)
+	if err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
 // UpdateInstanceState updates the instance to match the requested state.
 func (r *ProtocolIncus) UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2232,6 +2433,23 @@ func (r *ProtocolIncus) GetInstanceAccess(name string) (api.Access, error) {
 	return access, nil
 }
 
+// GetInstanceCloudInit returns the cloud-init status of the instance.
+func (r *ProtocolIncus) GetInstanceCloudInit(name string) (*api.InstanceCloudInit, error) {
+	if !r.HasExtension("instance_cloud_init_status") {
+		return nil, errors.New("The server is missing the required \"instance_cloud_init_status\" API extension")
+	}
+
+	status := api.InstanceCloudInit{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/instances/%s/cloud-init", url.PathEscape(name)), nil, "", &status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
 // GetInstanceLogfiles returns a list of logfiles for the instance.
 func (r *ProtocolIncus) GetInstanceLogfiles(name string) ([]string, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2555,6 +2773,10 @@ func (r *ProtocolIncus) ConsoleInstance(instanceName string, console api.Instanc
 		return nil, errors.New("The server is missing the required \"console_vga_type\" API extension")
 	}
 
+	if console.Type == "vnc" && !r.HasExtension("console_vnc_type") {
+		return nil, errors.New(`The server is missing the required "console_vnc_type" API extension`)
+	}
+
 	if console.Force && !r.HasExtension("console_force") {
 		return nil, errors.New(`The server is missing the required "console_force" API extension`)
 	}
@@ -2652,6 +2874,10 @@ func (r *ProtocolIncus) ConsoleInstanceDynamic(instanceName string, console api.
 		return nil, nil, errors.New("The server is missing the required \"console_vga_type\" API extension")
 	}
 
+	if console.Type == "vnc" && !r.HasExtension("console_vnc_type") {
+		return nil, nil, errors.New(`The server is missing the required "console_vnc_type" API extension`)
+	}
+
 	if console.Force && !r.HasExtension("console_force") {
 		return nil, nil, errors.New(`The server is missing the required "console_force" API extension`)
 	}
@@ -2856,6 +3082,47 @@ func (r *ProtocolIncus) GetInstanceBackup(instanceName string, name string) (*ap
 	return &backup, etag, nil
 }
 
+// GetInstanceBackupFiles returns the list of files contained in an instance backup.
+func (r *ProtocolIncus) GetInstanceBackupFiles(instanceName string, name string) ([]api.BackupFile, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("backup_files") {
+		return nil, errors.New("The server is missing the required \"backup_files\" API extension")
+	}
+
+	// Fetch the raw value
+	files := []api.BackupFile{}
+	_, err = r.queryStruct("GET", fmt.Sprintf("%s/%s/backups/%s/files", path, url.PathEscape(instanceName), url.PathEscape(name)), nil, "", &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// RestoreInstanceBackupFiles extracts a path from an instance backup into the instance.
+func (r *ProtocolIncus) RestoreInstanceBackupFiles(instanceName string, name string, req api.BackupFilesPost) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("backup_file_restore") {
+		return nil, errors.New("The server is missing the required \"backup_file_restore\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups/%s/files", path, url.PathEscape(instanceName), url.PathEscape(name)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateInstanceBackup requests that Incus creates a new backup for the instance.
 func (r *ProtocolIncus) CreateInstanceBackup(instanceName string, backup api.InstanceBackupsPost) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2896,6 +3163,26 @@ func (r *ProtocolIncus) RenameInstanceBackup(instanceName string, name string, b
 	return op, nil
 }
 
+// CreateInstanceBackupSecret requests a secret allowing untrusted clients to download the instance backup.
+func (r *ProtocolIncus) CreateInstanceBackupSecret(instanceName string, name string, req api.BackupSecretPost) (Operation, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.HasExtension("backup_secret") {
+		return nil, errors.New("The server is missing the required \"backup_secret\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/backups/%s/secret", path, url.PathEscape(instanceName), url.PathEscape(name)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // DeleteInstanceBackup requests that Incus deletes the instance backup.
 func (r *ProtocolIncus) DeleteInstanceBackup(instanceName string, name string) (Operation, error) {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
@@ -2929,53 +3216,146 @@ func (r *ProtocolIncus) GetInstanceBackupFile(instanceName string, name string,
 
 	// Build the URL
 	uri := fmt.Sprintf("%s/1.0%s/%s/backups/%s/export", r.httpBaseURL.String(), path, url.PathEscape(instanceName), url.PathEscape(name))
+
+	values := url.Values{}
 	if r.project != "" {
-		uri += fmt.Sprintf("?project=%s", url.QueryEscape(r.project))
+		values.Set("project", r.project)
 	}
 
-	// Prepare the download request
-	request, err := http.NewRequest("GET", uri, nil)
-	if err != nil {
-		return nil, err
+	if req.Format != "" {
+		if !r.HasExtension("backup_disk_export") {
+			return nil, errors.New("The server is missing the required \"backup_disk_export\" API extension")
+		}
+
+		values.Set("format", req.Format)
+	}
+
+	if req.Throttle != "" {
+		if !r.HasExtension("backup_throttle") {
+			return nil, errors.New("The server is missing the required \"backup_throttle\" API extension")
+		}
+
+		values.Set("throttle", req.Throttle)
 	}
 
-	if r.httpUserAgent != "" {
-		request.Header.Set("User-Agent", r.httpUserAgent)
+	if len(values) > 0 {
+		uri += "?" + values.Encode()
 	}
 
-	// Start the request
-	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
+	return r.downloadBackupFile(uri, req)
+}
+
+// backupFileResumeAttempts is the number of times an interrupted backup download is resumed.
+const backupFileResumeAttempts = 5
+
+// downloadBackupFile downloads the backup file at the given URL.
+// When the server advertises the checksum of the file, interrupted downloads are resumed through range requests
+// and the downloaded file is verified against the checksum.
+func (r *ProtocolIncus) downloadBackupFile(uri string, req *BackupFileRequest) (*BackupFileResponse, error) {
+	start, err := req.BackupFile.Seek(0, io.SeekCurrent)
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() { _ = response.Body.Close() }()
-	defer close(doneCh)
+	var size int64
+	var etag string
+	var checksum string
+	var tracker *ioprogress.ProgressTracker
+	hasher := sha256.New()
 
-	if response.StatusCode != http.StatusOK {
-		_, _, err := incusParseResponse(response)
+	download := func() error {
+		// Prepare the download request
+		request, err := http.NewRequest("GET", uri, nil)
 		if err != nil {
-			return nil, err
+			return err
 		}
-	}
 
-	// Handle the data
-	body := response.Body
-	if req.ProgressHandler != nil {
-		body = &ioprogress.ProgressReader{
-			ReadCloser: response.Body,
-			Tracker: &ioprogress.ProgressTracker{
+		if r.httpUserAgent != "" {
+			request.Header.Set("User-Agent", r.httpUserAgent)
+		}
+
+		// Resume from where the previous attempt stopped, unless the file changed since.
+		if size > 0 {
+			request.Header.Set("Range", fmt.Sprintf("bytes=%d-", size))
+			request.Header.Set("If-Range", etag)
+		}
+
+		// Start the request
+		response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = response.Body.Close() }()
+		defer close(doneCh)
+
+		if response.StatusCode != http.StatusPartialContent || size == 0 {
+			if response.StatusCode != http.StatusOK {
+				_, _, err := incusParseResponse(response)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Start over, the file having changed since the previous attempt.
+			if size > 0 {
+				_, err = req.BackupFile.Seek(start, io.SeekStart)
+				if err != nil {
+					return err
+				}
+
+				truncater, ok := req.BackupFile.(interface{ Truncate(size int64) error })
+				if ok {
+					err = truncater.Truncate(start)
+					if err != nil {
+						return err
+					}
+				}
+
+				size = 0
+				hasher.Reset()
+			}
+
+			etag = response.Header.Get("ETag")
+			checksum = response.Header.Get("X-Incus-Checksum")
+			tracker = &ioprogress.ProgressTracker{
 				Length: response.ContentLength,
 				Handler: func(percent int64, speed int64) {
 					req.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
 				},
-			},
+			}
+		}
+
+		// Handle the data
+		body := response.Body
+		if req.ProgressHandler != nil {
+			body = &ioprogress.ProgressReader{
+				ReadCloser: response.Body,
+				Tracker:    tracker,
+			}
 		}
+
+		n, err := io.Copy(io.MultiWriter(req.BackupFile, hasher), body)
+		size += n
+
+		return err
 	}
 
-	size, err := io.Copy(req.BackupFile, body)
-	if err != nil {
-		return nil, err
+	for attempt := 0; ; attempt++ {
+		err = download()
+		if err == nil {
+			break
+		}
+
+		// Only resume files the server advertises a checksum for, and not after a cancellation.
+		if etag == "" || attempt >= backupFileResumeAttempts || errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+
+	value, ok := strings.CutPrefix(checksum, "sha256:")
+	if ok && value != hex.EncodeToString(hasher.Sum(nil)) {
+		return nil, errors.New("The downloaded backup file doesn't match its checksum")
 	}
 
 	resp := BackupFileResponse{}
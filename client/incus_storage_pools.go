@@ -123,3 +123,35 @@ func (r *ProtocolIncus) GetStoragePoolResources(name string) (*api.ResourcesStor
 
 	return &res, nil
 }
+
+// GetStoragePoolState gets the usage and health of a given storage pool.
+func (r *ProtocolIncus) GetStoragePoolState(name string) (*api.StoragePoolState, error) {
+	if !r.HasExtension("storage_pool_state") {
+		return nil, errors.New("The server is missing the required \"storage_pool_state\" API extension")
+	}
+
+	state := api.StoragePoolState{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/storage-pools/%s/state", url.PathEscape(name)), nil, "", &state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// UpdateStoragePoolState triggers a maintenance action on a given storage pool.
+func (r *ProtocolIncus) UpdateStoragePoolState(name string, state api.StoragePoolStatePut) error {
+	if !r.HasExtension("storage_pool_state") {
+		return errors.New("The server is missing the required \"storage_pool_state\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("PUT", fmt.Sprintf("/storage-pools/%s/state", url.PathEscape(name)), state, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
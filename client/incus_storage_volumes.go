@@ -3,19 +3,16 @@ package incus
 import (
 	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/sftp"
 
 	"github.com/lxc/incus/v6/shared/api"
-	"github.com/lxc/incus/v6/shared/cancel"
-	"github.com/lxc/incus/v6/shared/ioprogress"
 	localtls "github.com/lxc/incus/v6/shared/tls"
-	"github.com/lxc/incus/v6/shared/units"
 )
 
 // Storage volumes handling function
@@ -208,6 +205,22 @@ func (r *ProtocolIncus) GetStoragePoolVolumeState(pool string, volType string, n
 	return &state, nil
 }
 
+// UpdateStoragePoolVolumeState changes the mirroring state of the provided pool and volume name.
+func (r *ProtocolIncus) UpdateStoragePoolVolumeState(pool string, volType string, name string, state api.StorageVolumeStatePut) error {
+	if !r.HasExtension("storage_ceph_rbd_mirror") {
+		return errors.New("The server is missing the required \"storage_ceph_rbd_mirror\" API extension")
+	}
+
+	// Send the request
+	path := fmt.Sprintf("/storage-pools/%s/volumes/%s/%s/state", url.PathEscape(pool), url.PathEscape(volType), url.PathEscape(name))
+	_, _, err := r.query("PUT", path, state, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateStoragePoolVolume defines a new storage volume.
 func (r *ProtocolIncus) CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) error {
 	if !r.HasExtension("storage") {
@@ -867,6 +880,40 @@ func (r *ProtocolIncus) GetStorageVolumeBackups(pool string, volName string) ([]
 	return backups, nil
 }
 
+// GetStorageVolumeBackupsWithFilter returns a page of the custom volume backups matching the filters.
+// A limit of zero returns all the backups past the offset.
+func (r *ProtocolIncus) GetStorageVolumeBackupsWithFilter(pool string, volName string, filters []string, offset int, limit int) ([]api.StorageVolumeBackup, error) {
+	err := r.CheckExtension("custom_volume_backup_list_filter")
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []api.StorageVolumeBackup{}
+
+	v := url.Values{}
+	v.Set("recursion", "1")
+
+	if len(filters) > 0 {
+		v.Set("filter", parseFilters(filters))
+	}
+
+	if offset > 0 {
+		v.Set("offset", strconv.Itoa(offset))
+	}
+
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	// Fetch the raw value
+	_, err = r.queryStruct("GET", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups?%s", url.PathEscape(pool), url.PathEscape(volName), v.Encode()), nil, "", &backups)
+	if err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
 // GetStorageVolumeBackup returns a custom volume backup.
 func (r *ProtocolIncus) GetStorageVolumeBackup(pool string, volName string, name string) (*api.StorageVolumeBackup, string, error) {
 	if !r.HasExtension("custom_volume_backup") {
@@ -883,6 +930,37 @@ func (r *ProtocolIncus) GetStorageVolumeBackup(pool string, volName string, name
 	return &backup, etag, nil
 }
 
+// GetStorageVolumeBackupFiles returns the list of files contained in a custom volume backup.
+func (r *ProtocolIncus) GetStorageVolumeBackupFiles(pool string, volName string, name string) ([]api.BackupFile, error) {
+	if !r.HasExtension("backup_files") {
+		return nil, errors.New("The server is missing the required \"backup_files\" API extension")
+	}
+
+	// Fetch the raw value
+	files := []api.BackupFile{}
+	_, err := r.queryStruct("GET", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups/%s/files", url.PathEscape(pool), url.PathEscape(volName), url.PathEscape(name)), nil, "", &files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// RestoreStorageVolumeBackupFiles extracts a path from a custom volume backup onto the volume.
+func (r *ProtocolIncus) RestoreStorageVolumeBackupFiles(pool string, volName string, name string, req api.BackupFilesPost) (Operation, error) {
+	if !r.HasExtension("backup_file_restore") {
+		return nil, errors.New("The server is missing the required \"backup_file_restore\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups/%s/files", url.PathEscape(pool), url.PathEscape(volName), url.PathEscape(name)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // CreateStorageVolumeBackup creates new custom volume backup.
 func (r *ProtocolIncus) CreateStorageVolumeBackup(pool string, volName string, backup api.StorageVolumeBackupsPost) (Operation, error) {
 	if !r.HasExtension("custom_volume_backup") {
@@ -913,6 +991,21 @@ func (r *ProtocolIncus) RenameStorageVolumeBackup(pool string, volName string, n
 	return op, nil
 }
 
+// CreateStorageVolumeBackupSecret requests a secret allowing untrusted clients to download the custom volume backup.
+func (r *ProtocolIncus) CreateStorageVolumeBackupSecret(pool string, volName string, name string, req api.BackupSecretPost) (Operation, error) {
+	if !r.HasExtension("backup_secret") {
+		return nil, errors.New("The server is missing the required \"backup_secret\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/storage-pools/%s/volumes/custom/%s/backups/%s/secret", url.PathEscape(pool), url.PathEscape(volName), url.PathEscape(name)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // DeleteStorageVolumeBackup deletes a custom volume backup.
 func (r *ProtocolIncus) DeleteStorageVolumeBackup(pool string, volName string, name string) (Operation, error) {
 	if !r.HasExtension("custom_volume_backup") {
@@ -937,66 +1030,46 @@ func (r *ProtocolIncus) GetStorageVolumeBackupFile(pool string, volName string,
 	// Build the URL
 	uri := fmt.Sprintf("%s/1.0/storage-pools/%s/volumes/custom/%s/backups/%s/export", r.httpBaseURL.String(), url.PathEscape(pool), url.PathEscape(volName), url.PathEscape(name))
 
-	// Add project/target
-	uri, err := r.setQueryAttributes(uri)
-	if err != nil {
-		return nil, err
-	}
-
-	// Prepare the download request
-	request, err := http.NewRequest("GET", uri, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if r.httpUserAgent != "" {
-		request.Header.Set("User-Agent", r.httpUserAgent)
-	}
+	values := url.Values{}
+	if req.Format != "" {
+		if !r.HasExtension("backup_disk_export") {
+			return nil, errors.New("The server is missing the required \"backup_disk_export\" API extension")
+		}
 
-	// Start the request
-	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
-	if err != nil {
-		return nil, err
+		values.Set("format", req.Format)
 	}
 
-	defer func() { _ = response.Body.Close() }()
-	defer close(doneCh)
-
-	if response.StatusCode != http.StatusOK {
-		_, _, err := incusParseResponse(response)
-		if err != nil {
-			return nil, err
+	if req.Throttle != "" {
+		if !r.HasExtension("backup_throttle") {
+			return nil, errors.New("The server is missing the required \"backup_throttle\" API extension")
 		}
+
+		values.Set("throttle", req.Throttle)
 	}
 
-	// Handle the data
-	body := response.Body
-	if req.ProgressHandler != nil {
-		body = &ioprogress.ProgressReader{
-			ReadCloser: response.Body,
-			Tracker: &ioprogress.ProgressTracker{
-				Length: response.ContentLength,
-				Handler: func(percent int64, speed int64) {
-					req.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
-				},
-			},
-		}
+	if len(values) > 0 {
+		uri += "?" + values.Encode()
 	}
 
-	size, err := io.Copy(req.BackupFile, body)
+	// Add project/target
+	uri, err := r.setQueryAttributes(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	resp := BackupFileResponse{}
-	resp.Size = size
-
-	return &resp, nil
+	return r.downloadBackupFile(uri, req)
 }
 
 // CreateStoragePoolVolumeFromMigration defines a new storage volume.
 // In contrast to CreateStoragePoolVolume, it also returns an operation object.
 func (r *ProtocolIncus) CreateStoragePoolVolumeFromMigration(pool string, volume api.StorageVolumesPost) (Operation, error) {
+	if volume.Source.Type == "backup" {
+		err := r.CheckExtension("backup_import_url")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	path := fmt.Sprintf("/storage-pools/%s/volumes/%s", url.PathEscape(pool), url.PathEscape(volume.Type))
 	op, _, err := r.queryOperation("POST", path, volume, "")
@@ -1065,6 +1138,68 @@ func (r *ProtocolIncus) CreateStoragePoolVolumeFromISO(pool string, args Storage
 	return &op, nil
 }
 
+// CreateStoragePoolVolumeFromDiskImage creates a custom block volume from a raw, qcow2 or vmdk disk image.
+func (r *ProtocolIncus) CreateStoragePoolVolumeFromDiskImage(pool string, args StorageVolumeDiskImageArgs) (Operation, error) {
+	err := r.CheckExtension("custom_volume_disk_import")
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Name == "" {
+		return nil, errors.New("Missing volume name")
+	}
+
+	path := fmt.Sprintf("/storage-pools/%s/volumes/custom", url.PathEscape(pool))
+
+	// Prepare the HTTP request.
+	reqURL, err := r.setQueryAttributes(fmt.Sprintf("%s/1.0%s", r.httpBaseURL.String(), path))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", reqURL, args.DiskImageFile)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Incus-name", args.Name)
+	req.Header.Set("X-Incus-type", "disk")
+
+	if args.Format != "" {
+		req.Header.Set("X-Incus-format", args.Format)
+	}
+
+	// Send the request.
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// Handle errors.
+	response, _, err := incusParseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get to the operation.
+	respOperation, err := response.MetadataAsOperation()
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup an Operation wrapper.
+	op := operation{
+		Operation: *respOperation,
+		r:         r,
+		chActive:  make(chan bool),
+	}
+
+	return &op, nil
+}
+
 // CreateStoragePoolVolumeFromBackup creates a custom volume from a backup file.
 func (r *ProtocolIncus) CreateStoragePoolVolumeFromBackup(pool string, args StorageVolumeBackupArgs) (Operation, error) {
 	if !r.HasExtension("custom_volume_backup") {
@@ -0,0 +1,91 @@
+package incus
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Trash handling functions
+
+// GetTrashEntryUUIDs returns a list of trash entry uuids.
+func (r *ProtocolIncus) GetTrashEntryUUIDs() ([]string, error) {
+	if !r.HasExtension("trash") {
+		return nil, errors.New("The server is missing the required \"trash\" API extension")
+	}
+
+	// Fetch the raw values.
+	urls := []string{}
+	baseURL := "/trash"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetTrashEntries returns a list of trash entries.
+func (r *ProtocolIncus) GetTrashEntries() ([]api.TrashEntry, error) {
+	if !r.HasExtension("trash") {
+		return nil, errors.New("The server is missing the required \"trash\" API extension")
+	}
+
+	entries := []api.TrashEntry{}
+
+	_, err := r.queryStruct("GET", "/trash?recursion=1", nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetTrashEntry returns the trash entry with the given UUID.
+func (r *ProtocolIncus) GetTrashEntry(UUID string) (*api.TrashEntry, string, error) {
+	if !r.HasExtension("trash") {
+		return nil, "", errors.New("The server is missing the required \"trash\" API extension")
+	}
+
+	entry := api.TrashEntry{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/trash/%s", url.PathEscape(UUID)), nil, "", &entry)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &entry, etag, nil
+}
+
+// RestoreTrashEntry restores the instance or custom volume held by the trash entry with the given UUID.
+func (r *ProtocolIncus) RestoreTrashEntry(UUID string, entry api.TrashEntryPost) (Operation, error) {
+	if !r.HasExtension("trash") {
+		return nil, errors.New("The server is missing the required \"trash\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("/trash/%s", url.PathEscape(UUID)), entry, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteTrashEntry permanently removes the trash entry with the given UUID.
+func (r *ProtocolIncus) DeleteTrashEntry(UUID string) error {
+	if !r.HasExtension("trash") {
+		return errors.New("The server is missing the required \"trash\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/trash/%s", url.PathEscape(UUID)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/cancel"
@@ -94,7 +95,41 @@ type InstanceServer interface {
 	CreateCertificate(certificate api.CertificatesPost) (err error)
 	UpdateCertificate(fingerprint string, certificate api.CertificatePut, ETag string) (err error)
 	DeleteCertificate(fingerprint string) (err error)
+	RotateCertificate(fingerprint string, certificate api.CertificateRotatePost) (err error)
 	CreateCertificateToken(certificate api.CertificatesPost) (op Operation, err error)
+	GetCertificateTokens() (tokens []api.CertificateToken, err error)
+	GetCertificateToken(id string) (token *api.CertificateToken, err error)
+	DeleteCertificateToken(id string) (err error)
+
+	// Certificate bundle functions ("certificates_bundle" API extension)
+	ExportCertificates() (bundle *api.CertificatesBundle, err error)
+	ImportCertificates(bundle api.CertificatesImportPost) (result *api.CertificatesImportResult, err error)
+
+	// Certificate group functions ("certificate_groups" API extension)
+	GetCertificateGroupNames() (names []string, err error)
+	GetCertificateGroups() (groups []api.CertificateGroup, err error)
+	GetCertificateGroup(name string) (group *api.CertificateGroup, ETag string, err error)
+	CreateCertificateGroup(group api.CertificateGroupsPost) (err error)
+	UpdateCertificateGroup(name string, group api.CertificateGroupPut, ETag string) (err error)
+	RenameCertificateGroup(name string, group api.CertificateGroupPost) (err error)
+	DeleteCertificateGroup(name string) (err error)
+
+	// API token functions ("auth_tokens" API extension)
+	GetAuthTokenNames() (names []string, err error)
+	GetAuthTokens() (tokens []api.AuthToken, err error)
+	GetAuthToken(name string) (token *api.AuthToken, ETag string, err error)
+	CreateAuthToken(token api.AuthTokensPost) (secret *api.AuthTokenSecret, err error)
+	UpdateAuthToken(name string, token api.AuthTokenPut, ETag string) (err error)
+	DeleteAuthToken(name string) (err error)
+
+	// Audit log functions ("auth_audit" API extension)
+	GetAuditEntries() (entries []api.AuditEntry, err error)
+	GetAuditEntriesWithFilter(filters []string) (entries []api.AuditEntry, err error)
+
+	// SSH key authentication functions ("auth_ssh" API extension)
+	CreateSSHChallenge(publicKey string) (challenge *api.AuthSSHChallenge, err error)
+	CreateSSHSession(req api.AuthSSHPost) (session *api.AuthSSHSession, err error)
+	AuthenticateSSH(signer ssh.Signer) (session *api.AuthSSHSession, err error)
 
 	// Instance functions.
 	GetInstanceNames(instanceType api.InstanceType) (names []string, err error)
@@ -107,6 +142,8 @@ type InstanceServer interface {
 	GetInstancesFullWithFilter(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
 	GetInstancesAllProjectsWithFilter(instanceType api.InstanceType, filters []string) (instances []api.Instance, err error)
 	GetInstancesFullAllProjectsWithFilter(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
+	GetInstancesWithState(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
+	GetInstancesWithStateAllProjects(instanceType api.InstanceType, filters []string) (instances []api.InstanceFull, err error)
 	GetInstance(name string) (instance *api.Instance, ETag string, err error)
 	GetInstanceFull(name string) (instance *api.InstanceFull, ETag string, err error)
 	CreateInstance(instance api.InstancesPost) (op Operation, err error)
@@ -119,6 +156,7 @@ type InstanceServer interface {
 	UpdateInstances(state api.InstancesPut, ETag string) (op Operation, err error)
 	RebuildInstance(instanceName string, req api.InstanceRebuildPost) (op Operation, err error)
 	RebuildInstanceFromImage(source ImageServer, image api.Image, instanceName string, req api.InstanceRebuildPost) (op RemoteOperation, err error)
+	RebuildInstanceFromBackup(instanceName string, backupFile io.Reader) (op Operation, err error)
 
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
@@ -130,6 +168,8 @@ type InstanceServer interface {
 	GetInstanceFile(instanceName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
 	CreateInstanceFile(instanceName string, path string, args InstanceFileArgs) (err error)
 	DeleteInstanceFile(instanceName string, path string) (err error)
+	GetInstanceFileRecursive(instanceName string, path string) (content io.ReadCloser, err error)
+	CreateInstanceFileRecursive(instanceName string, path string, content io.Reader) (err error)
 
 	GetInstanceFileSFTPConn(instanceName string) (net.Conn, error)
 	GetInstanceFileSFTP(instanceName string) (*sftp.Client, error)
@@ -147,16 +187,21 @@ type InstanceServer interface {
 	GetInstanceBackupNames(instanceName string) (names []string, err error)
 	GetInstanceBackups(instanceName string) (backups []api.InstanceBackup, err error)
 	GetInstanceBackup(instanceName string, name string) (backup *api.InstanceBackup, ETag string, err error)
+	GetInstanceBackupFiles(instanceName string, name string) (files []api.BackupFile, err error)
+	RestoreInstanceBackupFiles(instanceName string, name string, req api.BackupFilesPost) (op Operation, err error)
 	CreateInstanceBackup(instanceName string, backup api.InstanceBackupsPost) (op Operation, err error)
 	RenameInstanceBackup(instanceName string, name string, backup api.InstanceBackupPost) (op Operation, err error)
 	DeleteInstanceBackup(instanceName string, name string) (op Operation, err error)
+	CreateInstanceBackupSecret(instanceName string, name string, req api.BackupSecretPost) (op Operation, err error)
 	GetInstanceBackupFile(instanceName string, name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
 	CreateInstanceFromBackup(args InstanceBackupArgs) (op Operation, err error)
 
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
+	GetInstanceProcesses(name string) (processes []api.InstanceProcess, err error)
 
 	GetInstanceAccess(name string) (access api.Access, err error)
+	GetInstanceCloudInit(name string) (status *api.InstanceCloudInit, err error)
 
 	GetInstanceLogfiles(name string) (logfiles []string, err error)
 	GetInstanceLogfile(name string, filename string) (content io.ReadCloser, err error)
@@ -303,6 +348,17 @@ type InstanceServer interface {
 	RenameProfile(name string, profile api.ProfilePost) (err error)
 	DeleteProfile(name string) (err error)
 
+	// Blueprint functions ("instance_blueprints" API extension)
+	GetBlueprintNames() (names []string, err error)
+	GetBlueprints() (blueprints []api.Blueprint, err error)
+	GetBlueprintsWithFilter(filters []string) (blueprints []api.Blueprint, err error)
+	GetBlueprint(name string) (blueprint *api.Blueprint, ETag string, err error)
+	GetBlueprintVersion(name string, version int64) (blueprint *api.Blueprint, err error)
+	CreateBlueprint(blueprint api.BlueprintsPost) (err error)
+	UpdateBlueprint(name string, blueprint api.BlueprintPut, ETag string) (err error)
+	RenameBlueprint(name string, blueprint api.BlueprintPost) (err error)
+	DeleteBlueprint(name string) (err error)
+
 	// Project functions
 	GetProjectNames() (names []string, err error)
 	GetProjects() (projects []api.Project, err error)
@@ -321,6 +377,8 @@ type InstanceServer interface {
 	GetStoragePools() (pools []api.StoragePool, err error)
 	GetStoragePool(name string) (pool *api.StoragePool, ETag string, err error)
 	GetStoragePoolResources(name string) (resources *api.ResourcesStoragePool, err error)
+	GetStoragePoolState(name string) (state *api.StoragePoolState, err error)
+	UpdateStoragePoolState(name string, state api.StoragePoolStatePut) (err error)
 	CreateStoragePool(pool api.StoragePoolsPost) (err error)
 	UpdateStoragePool(name string, pool api.StoragePoolPut, ETag string) (err error)
 	DeleteStoragePool(name string) (err error)
@@ -357,6 +415,7 @@ type InstanceServer interface {
 	GetStoragePoolVolumesWithFilterAllProjects(pool string, filters []string) (volumes []api.StorageVolume, err error)
 	GetStoragePoolVolume(pool string, volType string, name string) (volume *api.StorageVolume, ETag string, err error)
 	GetStoragePoolVolumeState(pool string, volType string, name string) (state *api.StorageVolumeState, err error)
+	UpdateStoragePoolVolumeState(pool string, volType string, name string, state api.StorageVolumeStatePut) (err error)
 	CreateStoragePoolVolume(pool string, volume api.StorageVolumesPost) (err error)
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume api.StorageVolumePut, ETag string) (err error)
 	DeleteStoragePoolVolume(pool string, volType string, name string) (err error)
@@ -377,15 +436,38 @@ type InstanceServer interface {
 	// Storage volume backup functions ("custom_volume_backup" API extension)
 	GetStorageVolumeBackupNames(pool string, volName string) (names []string, err error)
 	GetStorageVolumeBackups(pool string, volName string) (backups []api.StorageVolumeBackup, err error)
+	GetStorageVolumeBackupsWithFilter(pool string, volName string, filters []string, offset int, limit int) (backups []api.StorageVolumeBackup, err error)
 	GetStorageVolumeBackup(pool string, volName string, name string) (backup *api.StorageVolumeBackup, ETag string, err error)
+	GetStorageVolumeBackupFiles(pool string, volName string, name string) (files []api.BackupFile, err error)
+	RestoreStorageVolumeBackupFiles(pool string, volName string, name string, req api.BackupFilesPost) (op Operation, err error)
 	CreateStorageVolumeBackup(pool string, volName string, backup api.StorageVolumeBackupsPost) (op Operation, err error)
 	RenameStorageVolumeBackup(pool string, volName string, name string, backup api.StorageVolumeBackupPost) (op Operation, err error)
 	DeleteStorageVolumeBackup(pool string, volName string, name string) (op Operation, err error)
+	CreateStorageVolumeBackupSecret(pool string, volName string, name string, req api.BackupSecretPost) (op Operation, err error)
 	GetStorageVolumeBackupFile(pool string, volName string, name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
 	CreateStoragePoolVolumeFromBackup(pool string, args StorageVolumeBackupArgs) (op Operation, err error)
 
+	// Backup group functions ("backup_groups" API extension)
+	GetBackupGroupNames() (names []string, err error)
+	GetBackupGroups() (groups []api.BackupGroup, err error)
+	GetBackupGroup(name string) (group *api.BackupGroup, ETag string, err error)
+	CreateBackupGroup(group api.BackupGroupsPost) (op Operation, err error)
+	DeleteBackupGroup(name string) (err error)
+	GetBackupGroupFile(name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
+	CreateBackupGroupFromFile(bundle io.Reader) (op Operation, err error)
+
+	// Backup target functions ("backup_targets" API extension)
+	GetBackupTargetNames() (names []string, err error)
+	GetBackupTargets() (targets []api.NamedBackupTarget, err error)
+	GetBackupTarget(name string) (target *api.NamedBackupTarget, ETag string, err error)
+	CreateBackupTarget(target api.NamedBackupTargetsPost) (err error)
+	UpdateBackupTarget(name string, target api.NamedBackupTargetPut, ETag string) (err error)
+	RenameBackupTarget(name string, target api.NamedBackupTargetPost) (err error)
+	DeleteBackupTarget(name string) (err error)
+
 	// Storage volume ISO import function ("custom_volume_iso" API extension)
 	CreateStoragePoolVolumeFromISO(pool string, args StorageVolumeBackupArgs) (op Operation, err error)
+	CreateStoragePoolVolumeFromDiskImage(pool string, args StorageVolumeDiskImageArgs) (op Operation, err error)
 	CreateStoragePoolVolumeFromMigration(pool string, volume api.StorageVolumesPost) (op Operation, err error)
 
 	// Storage volume SFTP functions ("custom_volume_sftp" API extension)
@@ -406,6 +488,8 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	GetClusterRebalancePlan(rebalance api.ClusterRebalancePost) (plan *api.ClusterRebalancePlan, err error)
+	RebalanceCluster(rebalance api.ClusterRebalancePost) (op Operation, err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -414,6 +498,13 @@ type InstanceServer interface {
 	UpdateClusterGroup(name string, group api.ClusterGroupPut, ETag string) error
 	GetClusterGroup(name string) (*api.ClusterGroup, string, error)
 
+	// Trash functions
+	GetTrashEntryUUIDs() (uuids []string, err error)
+	GetTrashEntries() (entries []api.TrashEntry, err error)
+	GetTrashEntry(UUID string) (entry *api.TrashEntry, ETag string, err error)
+	RestoreTrashEntry(UUID string, entry api.TrashEntryPost) (op Operation, err error)
+	DeleteTrashEntry(UUID string) (err error)
+
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
 	GetWarnings() (warnings []api.Warning, err error)
@@ -448,6 +539,16 @@ type BackupFileRequest struct {
 
 	// A canceler that can be used to interrupt some part of the image download request
 	Canceler *cancel.HTTPRequestCanceller
+
+	// Disk image format (qcow2 or vmdk) to convert the disk of the backup to instead of getting the tarball
+	//
+	// API extension: backup_disk_export
+	Format string
+
+	// Rate limit for the download in bytes per second (overrides the server setting)
+	//
+	// API extension: backup_throttle
+	Throttle string
 }
 
 // The BackupFileResponse struct is used as the response for backup downloads.
@@ -573,6 +674,19 @@ type StorageVolumeBackupArgs struct {
 	Name string
 }
 
+// The StorageVolumeDiskImageArgs struct is used when creating a custom block volume from a disk image.
+// API extension: custom_volume_disk_import.
+type StorageVolumeDiskImageArgs struct {
+	// The disk image file
+	DiskImageFile io.Reader
+
+	// Format of the disk image (raw, qcow2 or vmdk)
+	Format string
+
+	// Name of the new volume
+	Name string
+}
+
 // The InstanceBackupArgs struct is used when creating a instance from a backup.
 type InstanceBackupArgs struct {
 	// The backup file
@@ -608,6 +722,9 @@ type InstanceCopyArgs struct {
 
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool
+
+	// API extension: instance_copy_allow_running
+	AllowRunning bool
 }
 
 // The InstanceSnapshotCopyArgs struct is used to pass additional options during instance copy.
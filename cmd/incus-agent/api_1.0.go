@@ -32,8 +32,10 @@ var api10 = []APIEndpoint{
 	operationCmd,
 	operationWebsocket,
 	operationWait,
+	processesCmd,
 	sftpCmd,
 	stateCmd,
+	timeCmd,
 }
 
 func api10Get(d *Daemon, r *http.Request) response.Response {
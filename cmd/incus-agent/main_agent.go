@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"os/signal"
 	"slices"
 	"sync"
 	"time"
@@ -172,7 +171,7 @@ func (c *cmdAgent) Run(cmd *cobra.Command, args []string) error {
 
 	// Cancel context on shutdown signal.
 	chSignal := make(chan os.Signal, 1)
-	signal.Notify(chSignal, osShutdownSignal)
+	osNotifyShutdown(chSignal)
 
 	exitStatus := 0
 
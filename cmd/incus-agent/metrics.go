@@ -49,6 +49,13 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		out.Network = netStats
 	}
 
+	pressureStats, err := osGetPressureMetrics(d)
+	if err != nil {
+		logger.Debug("Failed to get pressure metrics", logger.Ctx{"err": err})
+	} else {
+		out.Pressure = pressureStats
+	}
+
 	out.ProcessesTotal = uint64(osGetProcessesState())
 
 	cpuStats, err := osGetCPUMetrics(d)
@@ -14,6 +14,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"regexp"
 	"slices"
@@ -45,7 +46,10 @@ var (
 	osMetricsExcludeMountpoints = regexp.MustCompile(`^/(?:dev|proc|sys|var/lib/docker/.+)(?:$|/)`)
 	osMetricsExcludeFilesystems = []string{"autofs", "binfmt_misc", "bpf", "cgroup", "cgroup2", "configfs", "debugfs", "devpts", "devtmpfs", "fusectl", "hugetlbfs", "iso9660", "mqueue", "nsfs", "overlay", "proc", "procfs", "pstore", "rpc_pipefs", "securityfs", "selinuxfs", "squashfs", "sysfs", "tracefs"}
 
-	osShutdownSignal       = unix.SIGTERM
+	// Filesystem freeze ioctls (FIFREEZE and FITHAW).
+	osIoctlFIFreeze = uint(0xC0045877)
+	osIoctlFIThaw   = uint(0xC0045878)
+
 	osExitStatus           = linux.ExitStatus
 	osBaseWorkingDirectory = "/"
 	osMetricsSupported     = true
@@ -491,6 +495,18 @@ func osGetMemoryState() api.InstanceStateMemory {
 		memory.UsagePeak = valueInt
 	}
 
+	// Out of memory kills
+	vmstat, err := os.ReadFile("/proc/vmstat")
+	if err == nil {
+		for _, line := range strings.Split(string(vmstat), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[0] == "oom_kill" {
+				memory.OOMKills, _ = strconv.ParseInt(fields[1], 10, 64)
+				break
+			}
+		}
+	}
+
 	return memory
 }
 
@@ -620,6 +636,44 @@ func osGetProcessesState() int64 {
 	return int64(len(pids))
 }
 
+func osGetProcesses() ([]api.InstanceProcess, error) {
+	return linux.ProcessList(nil, 0)
+}
+
+func osSetTime(t time.Time) error {
+	ts := unix.NsecToTimespec(t.UnixNano())
+
+	err := unix.ClockSettime(unix.CLOCK_REALTIME, &ts)
+	if err != nil {
+		return fmt.Errorf("Failed setting the system clock: %w", err)
+	}
+
+	return nil
+}
+
+func osGetPressureState() *api.InstanceStatePressure {
+	return linux.PressureState(linux.GetPressure)
+}
+
+func osGetPressureMetrics(d *Daemon) ([]metrics.PressureMetrics, error) {
+	out := []metrics.PressureMetrics{}
+
+	for _, resource := range linux.PressureResources {
+		pressure, err := linux.GetPressure(resource)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get %s pressure: %w", resource, err)
+		}
+
+		out = append(out, metrics.PressureMetrics{
+			Resource:    resource,
+			SomeSeconds: float64(pressure.Some.Total) / 1000000,
+			FullSeconds: float64(pressure.Full.Total) / 1000000,
+		})
+	}
+
+	return out, nil
+}
+
 func osGetOSState() *api.InstanceStateOSInfo {
 	osInfo := &api.InstanceStateOSInfo{}
 
@@ -868,6 +922,10 @@ func osExecWrapper(ctx context.Context, pty io.ReadWriteCloser) io.ReadWriteClos
 	return linux.NewExecWrapper(ctx, osFile)
 }
 
+func osNotifyShutdown(chShutdown chan os.Signal) {
+	signal.Notify(chShutdown, unix.SIGTERM)
+}
+
 func osGetListener(port int64) (net.Listener, error) {
 	const CIDAny uint32 = 4294967295 // Equivalent to VMADDR_CID_ANY.
 
@@ -925,3 +983,79 @@ func osSetEnv(post *api.InstanceExecPost, env map[string]string) {
 		}
 	}
 }
+
+// osFreezeFilesystems freezes all writable filesystems and returns the list of frozen mountpoints.
+func osFreezeFilesystems() ([]string, error) {
+	mounts, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read /proc/mounts: %w", err)
+	}
+
+	frozen := []string{}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = osThawFilesystems(frozen) })
+
+	scanner := bufio.NewScanner(bytes.NewReader(mounts))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("Invalid /proc/mounts content: %q", line)
+		}
+
+		// Skip virtual, read-only and already handled filesystems.
+		if slices.Contains(osMetricsExcludeFilesystems, fields[2]) || osMetricsExcludeMountpoints.MatchString(fields[1]) {
+			continue
+		}
+
+		if slices.Contains(strings.Split(fields[3], ","), "ro") || slices.Contains(frozen, fields[1]) {
+			continue
+		}
+
+		err := osFilesystemIoctl(fields[1], osIoctlFIFreeze)
+		if err != nil {
+			// Some filesystems (like tmpfs) don't support freezing.
+			if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) {
+				continue
+			}
+
+			return nil, fmt.Errorf("Failed freezing %q: %w", fields[1], err)
+		}
+
+		frozen = append(frozen, fields[1])
+	}
+
+	reverter.Success()
+
+	return frozen, nil
+}
+
+// osThawFilesystems thaws the provided mountpoints in reverse order.
+func osThawFilesystems(mountpoints []string) error {
+	var errs []error
+
+	for i := len(mountpoints) - 1; i >= 0; i-- {
+		err := osFilesystemIoctl(mountpoints[i], osIoctlFIThaw)
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			errs = append(errs, fmt.Errorf("Failed thawing %q: %w", mountpoints[i], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// osFilesystemIoctl runs a filesystem level ioctl against the given mountpoint.
+func osFilesystemIoctl(mountpoint string, req uint) error {
+	f, err := os.Open(mountpoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return unix.IoctlSetInt(int(f.Fd()), req, 0)
+}
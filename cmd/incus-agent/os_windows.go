@@ -10,11 +10,17 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"os/signal"
 	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+	"unsafe"
 
 	"golang.org/x/sys/windows"
 	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
 
 	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/version"
@@ -23,12 +29,77 @@ import (
 )
 
 var (
-	osShutdownSignal       = os.Interrupt
 	osBaseWorkingDirectory = "C:\\"
 	osMetricsSupported     = false
 	osGuestAPISupport      = false
+
+	// Not exposed by golang.org/x/sys/windows.
+	osKernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	osProcGlobalMemoryStatusEx = osKernel32.NewProc("GlobalMemoryStatusEx")
+	osProcGetSystemTimes       = osKernel32.NewProc("GetSystemTimes")
+	osProcSetSystemTime        = osKernel32.NewProc("SetSystemTime")
 )
 
+// osMemoryStatusEx is the MEMORYSTATUSEX structure.
+type osMemoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// osConsole is the console handed to interactive exec sessions.
+// Windows pseudo consoles can't be attached through os/exec, so the process gets a pair of pipes instead,
+// with its output and errors going to the same pipe like they would on a terminal.
+type osConsole struct {
+	reader *os.File
+	writer *os.File
+}
+
+// Read reads from the console.
+func (c *osConsole) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// Write writes to the console.
+func (c *osConsole) Write(p []byte) (int, error) {
+	return c.writer.Write(p)
+}
+
+// Close closes both ends of the console.
+func (c *osConsole) Close() error {
+	return errors.Join(c.reader.Close(), c.writer.Close())
+}
+
+// osAgentService handles the requests of the Windows service control manager.
+type osAgentService struct {
+	chShutdown chan<- os.Signal
+}
+
+// Execute reports the agent as running and requests a shutdown when the service is stopped.
+func (s *osAgentService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for req := range r {
+		switch req.Cmd {
+		case svc.Interrogate:
+			changes <- req.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			s.chShutdown <- os.Interrupt
+
+			return false, 0
+		}
+	}
+
+	return false, 0
+}
+
 func osGetEnvironment() (*api.ServerEnvironment, error) {
 	serverName, err := os.Hostname()
 	if err != nil {
@@ -72,16 +143,141 @@ func osGetMemoryMetrics(d *Daemon) (metrics.MemoryMetrics, error) {
 	return metrics.MemoryMetrics{}, errors.New("Metrics aren't supported on Windows")
 }
 
+func osGetPressureMetrics(d *Daemon) ([]metrics.PressureMetrics, error) {
+	return []metrics.PressureMetrics{}, errors.New("Metrics aren't supported on Windows")
+}
+
 func osGetCPUState() api.InstanceStateCPU {
-	return api.InstanceStateCPU{}
+	cpu := api.InstanceStateCPU{}
+
+	var idleTime, kernelTime, userTime windows.Filetime
+	ret, _, err := osProcGetSystemTimes.Call(uintptr(unsafe.Pointer(&idleTime)), uintptr(unsafe.Pointer(&kernelTime)), uintptr(unsafe.Pointer(&userTime)))
+	if ret == 0 {
+		logger.Debug("Failed to get system times", logger.Ctx{"err": err})
+		cpu.Usage = -1
+		return cpu
+	}
+
+	// The kernel time includes the idle time, all values are in 100ns units.
+	filetime := func(ft windows.Filetime) int64 {
+		return int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)
+	}
+
+	cpu.Usage = (filetime(kernelTime) - filetime(idleTime) + filetime(userTime)) * 100
+
+	return cpu
 }
 
 func osGetMemoryState() api.InstanceStateMemory {
-	return api.InstanceStateMemory{}
+	memory := api.InstanceStateMemory{}
+
+	stats := osMemoryStatusEx{}
+	stats.Length = uint32(unsafe.Sizeof(stats))
+
+	ret, _, err := osProcGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&stats)))
+	if ret == 0 {
+		logger.Debug("Failed to get memory status", logger.Ctx{"err": err})
+		return memory
+	}
+
+	memory.Usage = int64(stats.TotalPhys) - int64(stats.AvailPhys)
+	memory.Total = int64(stats.TotalPhys)
+	memory.SwapUsage = (int64(stats.TotalPageFile) - int64(stats.AvailPageFile)) - memory.Usage
+	if memory.SwapUsage < 0 {
+		memory.SwapUsage = 0
+	}
+
+	return memory
 }
 
 func osGetNetworkState() map[string]api.InstanceStateNetwork {
-	return map[string]api.InstanceStateNetwork{}
+	result := map[string]api.InstanceStateNetwork{}
+
+	ifs, err := net.Interfaces()
+	if err != nil {
+		logger.Errorf("Failed to retrieve network interfaces: %v", err)
+		return result
+	}
+
+	for _, iface := range ifs {
+		network := api.InstanceStateNetwork{
+			Addresses: []api.InstanceStateNetworkAddress{},
+			Counters:  api.InstanceStateNetworkCounters{},
+		}
+
+		network.Hwaddr = iface.HardwareAddr.String()
+		network.Mtu = iface.MTU
+
+		if iface.Flags&net.FlagUp != 0 {
+			network.State = "up"
+		} else {
+			network.State = "down"
+		}
+
+		if iface.Flags&net.FlagBroadcast != 0 {
+			network.Type = "broadcast"
+		} else if iface.Flags&net.FlagLoopback != 0 {
+			network.Type = "loopback"
+		} else if iface.Flags&net.FlagPointToPoint != 0 {
+			network.Type = "point-to-point"
+		} else {
+			network.Type = "unknown"
+		}
+
+		// Counters
+		row := windows.MibIfRow2{InterfaceIndex: uint32(iface.Index)}
+		err = windows.GetIfEntry2Ex(windows.MibIfEntryNormal, &row)
+		if err == nil {
+			network.Counters.BytesSent = int64(row.OutOctets)
+			network.Counters.BytesReceived = int64(row.InOctets)
+			network.Counters.PacketsSent = int64(row.OutUcastPkts + row.OutNUcastPkts)
+			network.Counters.PacketsReceived = int64(row.InUcastPkts + row.InNUcastPkts)
+			network.Counters.ErrorsSent = int64(row.OutErrors)
+			network.Counters.ErrorsReceived = int64(row.InErrors)
+			network.Counters.PacketsDroppedOutbound = int64(row.OutDiscards)
+			network.Counters.PacketsDroppedInbound = int64(row.InDiscards)
+		}
+
+		// Addresses
+		addrs, err := iface.Addrs()
+		if err != nil {
+			addrs = nil
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+
+			ones, _ := ipNet.Mask.Size()
+
+			networkAddress := api.InstanceStateNetworkAddress{
+				Address: ipNet.IP.String(),
+				Netmask: strconv.Itoa(ones),
+			}
+
+			if ipNet.IP.IsLoopback() {
+				networkAddress.Scope = "local"
+			} else if ipNet.IP.IsLinkLocalUnicast() {
+				networkAddress.Scope = "link"
+			} else {
+				networkAddress.Scope = "global"
+			}
+
+			if ipNet.IP.To4() == nil {
+				networkAddress.Family = "inet6"
+			} else {
+				networkAddress.Family = "inet"
+			}
+
+			network.Addresses = append(network.Addresses, networkAddress)
+		}
+
+		result[iface.Name] = network
+	}
+
+	return result
 }
 
 func osGetProcessesState() int64 {
@@ -96,6 +292,35 @@ func osGetProcessesState() int64 {
 	return int64(pidBytes / 4)
 }
 
+func osGetProcesses() ([]api.InstanceProcess, error) {
+	return nil, errors.New("Listing processes isn't supported on Windows")
+}
+
+func osSetTime(t time.Time) error {
+	t = t.UTC()
+	systemTime := windows.Systemtime{
+		Year:         uint16(t.Year()),
+		Month:        uint16(t.Month()),
+		DayOfWeek:    uint16(t.Weekday()),
+		Day:          uint16(t.Day()),
+		Hour:         uint16(t.Hour()),
+		Minute:       uint16(t.Minute()),
+		Second:       uint16(t.Second()),
+		Milliseconds: uint16(t.Nanosecond() / 1000000),
+	}
+
+	ret, _, err := osProcSetSystemTime.Call(uintptr(unsafe.Pointer(&systemTime)))
+	if ret == 0 {
+		return fmt.Errorf("Failed setting the system clock: %w", err)
+	}
+
+	return nil
+}
+
+func osGetPressureState() *api.InstanceStatePressure {
+	return nil
+}
+
 func osGetOSState() *api.InstanceStateOSInfo {
 	// Get Windows registry.
 	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
@@ -153,7 +378,23 @@ func osReconfigureNetworkInterfaces() {
 }
 
 func osGetInteractiveConsole(s *execWs) (io.ReadWriteCloser, io.ReadWriteCloser, error) {
-	return nil, nil, errors.New("Only non-interactive exec sessions are currently supported on Windows")
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		_ = stdinReader.Close()
+		_ = stdinWriter.Close()
+
+		return nil, nil, err
+	}
+
+	pty := &osConsole{reader: stdoutReader, writer: stdinWriter}
+	tty := &osConsole{reader: stdinReader, writer: stdoutWriter}
+
+	return pty, tty, nil
 }
 
 func osPrepareExecCommand(s *execWs, cmd *exec.Cmd) {
@@ -161,16 +402,61 @@ func osPrepareExecCommand(s *execWs, cmd *exec.Cmd) {
 		cmd.Dir = osBaseWorkingDirectory
 	}
 
-	return
+	// Hand the console pipes directly to the process so no copy goroutines outlive it.
+	tty, ok := cmd.Stdin.(*osConsole)
+	if ok {
+		cmd.Stdin = tty.reader
+		cmd.Stdout = tty.writer
+		cmd.Stderr = tty.writer
+	}
+
+	// Run the command in its own process group so it can be stopped without affecting the agent.
+	cmd.SysProcAttr = &windows.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP,
+	}
 }
 
 func osHandleExecControl(control api.InstanceExecControl, s *execWs, pty io.ReadWriteCloser, cmd *exec.Cmd, l logger.Logger) {
-	// Ignore control messages.
-	return
+	if control.Command != "signal" {
+		// Window resizing isn't supported without a pseudo console.
+		return
+	}
+
+	// Windows has no signals, stop the whole process tree instead.
+	switch control.Signal {
+	case 1, 2, 3, 9, 15: // SIGHUP, SIGINT, SIGQUIT, SIGKILL and SIGTERM.
+	default:
+		l.Debug("Ignoring unsupported signal", logger.Ctx{"signal": control.Signal})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := exec.CommandContext(ctx, "taskkill.exe", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+	if err != nil {
+		// Fallback to only killing the process itself.
+		err = cmd.Process.Kill()
+		if err != nil {
+			l.Debug("Failed forwarding signal", logger.Ctx{"err": err, "signal": control.Signal})
+			return
+		}
+	}
+
+	l.Info("Forwarded signal", logger.Ctx{"signal": control.Signal})
 }
 
 func osExitStatus(err error) (int, error) {
-	return 0, err
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+
+	return -1, err
 }
 
 func osExecWrapper(ctx context.Context, pty io.ReadWriteCloser) io.ReadWriteCloser {
@@ -189,5 +475,48 @@ func osGetListener(port int64) (net.Listener, error) {
 }
 
 func osSetEnv(post *api.InstanceExecPost, env map[string]string) {
-	env["PATH"] = "C:\\WINDOWS\\system32;C:\\WINDOWS"
+	// Set default value for PATH.
+	_, ok := env["PATH"]
+	if !ok {
+		env["PATH"] = "C:\\WINDOWS\\system32;C:\\WINDOWS;C:\\WINDOWS\\System32\\Wbem;C:\\WINDOWS\\System32\\WindowsPowerShell\\v1.0"
+	}
+
+	// Most Windows programs expect these to be set, inherit them from the agent.
+	for _, key := range []string{"ComSpec", "PATHEXT", "ProgramData", "ProgramFiles", "SystemDrive", "SystemRoot", "TEMP", "TMP", "USERPROFILE", "windir"} {
+		_, ok := env[key]
+		if ok {
+			continue
+		}
+
+		value := os.Getenv(key)
+		if value != "" {
+			env[key] = value
+		}
+	}
+}
+
+func osNotifyShutdown(chShutdown chan os.Signal) {
+	// Console events (including system shutdown) are delivered as signals.
+	signal.Notify(chShutdown, os.Interrupt, syscall.SIGTERM)
+
+	// When running as a service, stop requests come from the service control manager instead.
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return
+	}
+
+	go func() {
+		err := svc.Run("incus-agent", &osAgentService{chShutdown: chShutdown})
+		if err != nil {
+			logger.Error("Failed to run as a Windows service", logger.Ctx{"err": err})
+		}
+	}()
+}
+
+func osFreezeFilesystems() ([]string, error) {
+	return nil, errors.New("Filesystem freezing isn't supported on Windows")
+}
+
+func osThawFilesystems(mountpoints []string) error {
+	return errors.New("Filesystem freezing isn't supported on Windows")
 }
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+)
+
+var processesCmd = APIEndpoint{
+	Name: "processes",
+	Path: "processes",
+
+	Get: APIEndpointAction{Handler: processesGet},
+}
+
+func processesGet(d *Daemon, r *http.Request) response.Response {
+	processes, err := osGetProcesses()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, processes)
+}
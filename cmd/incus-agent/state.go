@@ -1,12 +1,27 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
+// stateFrozenMu protects the list of frozen filesystems.
+var stateFrozenMu sync.Mutex
+
+// stateFrozen is the list of filesystems currently frozen by the host.
+var stateFrozen []string
+
+// stateFrozenTimer thaws the filesystems should the host not do so in time.
+var stateFrozenTimer *time.Timer
+
 var stateCmd = APIEndpoint{
 	Name: "state",
 	Path: "state",
@@ -20,7 +35,70 @@ func stateGet(d *Daemon, r *http.Request) response.Response {
 }
 
 func statePut(d *Daemon, r *http.Request) response.Response {
-	return response.NotImplemented(nil)
+	req := api.InstanceStatePut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	stateFrozenMu.Lock()
+	defer stateFrozenMu.Unlock()
+
+	switch req.Action {
+	case "freeze":
+		if stateFrozen != nil {
+			return response.BadRequest(errors.New("Filesystems are already frozen"))
+		}
+
+		frozen, err := osFreezeFilesystems()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		stateFrozen = frozen
+
+		// Never leave the filesystems frozen for longer than requested.
+		timeout := time.Duration(req.Timeout) * time.Second
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+
+		stateFrozenTimer = time.AfterFunc(timeout, func() {
+			stateFrozenMu.Lock()
+			defer stateFrozenMu.Unlock()
+
+			logger.Warn("Thawing filesystems after timeout", logger.Ctx{"timeout": timeout})
+			_ = stateThaw()
+		})
+	case "unfreeze":
+		if stateFrozen == nil {
+			return response.EmptySyncResponse
+		}
+
+		err = stateThaw()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// stateThaw thaws the filesystems frozen by the host.
+// The caller must hold stateFrozenMu.
+func stateThaw() error {
+	if stateFrozenTimer != nil {
+		stateFrozenTimer.Stop()
+		stateFrozenTimer = nil
+	}
+
+	err := osThawFilesystems(stateFrozen)
+	stateFrozen = nil
+
+	return err
 }
 
 func renderState() *api.InstanceState {
@@ -31,5 +109,6 @@ func renderState() *api.InstanceState {
 		Pid:       1,
 		Processes: osGetProcessesState(),
 		OSInfo:    osGetOSState(),
+		Pressure:  osGetPressureState(),
 	}
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/response"
+	agentAPI "github.com/lxc/incus/v6/shared/api/agent"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var timeCmd = APIEndpoint{
+	Name: "time",
+	Path: "time",
+
+	Put: APIEndpointAction{Handler: timePut},
+}
+
+func timePut(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.TimePut{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	offset := time.Until(req.Time)
+
+	err = osSetTime(req.Time)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	logger.Info("Synchronized the guest clock with the host", logger.Ctx{"offset": offset})
+
+	return response.EmptySyncResponse
+}
@@ -41,6 +41,10 @@ func (c *cmdAdmin) Command() *cobra.Command {
 	sqlCmd := cmdAdminSQL{global: c.global}
 	cmd.AddCommand(sqlCmd.Command())
 
+	// trash sub-command
+	adminTrashCmd := cmdAdminTrash{global: c.global}
+	cmd.AddCommand(adminTrashCmd.Command())
+
 	// waitready sub-command
 	adminWaitreadyCmd := cmdAdminWaitready{global: c.global}
 	cmd.AddCommand(adminWaitreadyCmd.Command())
@@ -235,7 +235,7 @@ func (c *cmdAdminInit) askClustering(config *api.InitPreseed, server *api.Server
 			}
 
 			// Connect to existing cluster
-			serverCert, err := internalUtil.LoadServerCert(internalUtil.VarPath(""))
+			serverCert, err := internalUtil.LoadServerCert(internalUtil.VarPath(""), server.Config["core.server_key_uri"])
 			if err != nil {
 				return err
 			}
@@ -251,12 +251,12 @@ func (c *cmdAdminInit) askClustering(config *api.InitPreseed, server *api.Server
 
 			// Client parameters to connect to the target cluster member.
 			args := &incus.ConnectionArgs{
-				TLSClientCert: string(serverCert.PublicKey()),
-				TLSClientKey:  string(serverCert.PrivateKey()),
 				TLSServerCert: string(config.Cluster.ClusterCertificate),
 				UserAgent:     version.UserAgent,
 			}
 
+			internalUtil.SetClientCertificate(args, serverCert)
+
 			client, err := incus.ConnectIncus(fmt.Sprintf("https://%s", config.Cluster.ClusterAddress), args)
 			if err != nil {
 				return err
@@ -650,6 +650,20 @@ func (c *cmdAdminInit) askStoragePool(config *api.InitPreseed, d incus.InstanceS
 					return err
 				}
 
+			case "nfs":
+				// Ask for the NFS export
+				pool.Config["source"], err = c.global.asker.AskString(i18n.G("NFS export to use (<host>:<path>):")+" ", "", nil)
+				if err != nil {
+					return err
+				}
+
+			case "glusterfs":
+				// Ask for the GlusterFS volume
+				pool.Config["source"], err = c.global.asker.AskString(i18n.G("GlusterFS volume to use (<host>:/<volume>):")+" ", "", nil)
+				if err != nil {
+					return err
+				}
+
 			case "lvmcluster":
 				// Ask for the volume group
 				pool.Config["source"], err = c.global.asker.AskString(i18n.G("Name of the shared LVM volume group:")+" ", "", nil)
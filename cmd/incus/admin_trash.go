@@ -0,0 +1,301 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type trashColumn struct {
+	Name string
+	Data func(api.TrashEntry) string
+}
+
+type cmdAdminTrash struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminTrash) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("trash")
+	cmd.Short = i18n.G("Manage deleted instances and custom volumes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage deleted instances and custom volumes
+
+When the server has "storage.trash_expiry" set, deleted instances and custom storage
+volumes are kept in the trash for that many days and can be restored.`))
+
+	// List
+	trashListCmd := cmdAdminTrashList{global: c.global, trash: c}
+	cmd.AddCommand(trashListCmd.Command())
+
+	// Restore
+	trashRestoreCmd := cmdAdminTrashRestore{global: c.global, trash: c}
+	cmd.AddCommand(trashRestoreCmd.Command())
+
+	// Purge
+	trashPurgeCmd := cmdAdminTrashPurge{global: c.global, trash: c}
+	cmd.AddCommand(trashPurgeCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdAdminTrashList struct {
+	global *cmdGlobal
+	trash  *cmdAdminTrash
+
+	flagColumns string
+	flagFormat  string
+}
+
+const defaultTrashColumns = "utnpPLde"
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminTrashList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List trash entries")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List trash entries
+
+The -c option takes a (optionally comma-separated) list of arguments
+that control which trash entry attributes to output when displaying in table
+or csv format.
+
+Default column layout is: utnpPLde
+
+Column shorthand chars:
+
+    d - Deletion date
+    e - Expiry date
+    L - Location
+    n - Name
+    p - Project
+    P - Storage pool
+    t - Type
+    u - UUID`))
+
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultTrashColumns, i18n.G("Columns")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminTrashList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	remoteName, _, err := c.global.conf.ParseRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	entries, err := remoteServer.GetTrashEntries()
+	if err != nil {
+		return err
+	}
+
+	// Process the columns
+	columns, err := c.parseColumns(remoteServer.IsClustered())
+	if err != nil {
+		return err
+	}
+
+	// Render the table
+	data := [][]string{}
+	for _, entry := range entries {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, column.Data(entry))
+		}
+
+		data = append(data, row)
+	}
+
+	sort.Sort(cli.StringList(data))
+
+	rawData := make([]*api.TrashEntry, len(entries))
+	for i := range entries {
+		rawData[i] = &entries[i]
+	}
+
+	headers := []string{}
+	for _, column := range columns {
+		headers = append(headers, column.Name)
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, headers, data, rawData)
+}
+
+func (c *cmdAdminTrashList) parseColumns(clustered bool) ([]trashColumn, error) {
+	columnsShorthandMap := map[rune]trashColumn{
+		'd': {i18n.G("DELETED AT"), func(entry api.TrashEntry) string { return entry.DeletedAt.Local().Format(dateLayout) }},
+		'e': {i18n.G("EXPIRES AT"), func(entry api.TrashEntry) string { return entry.ExpiresAt.Local().Format(dateLayout) }},
+		'n': {i18n.G("NAME"), func(entry api.TrashEntry) string { return entry.Name }},
+		'p': {i18n.G("PROJECT"), func(entry api.TrashEntry) string { return entry.Project }},
+		'P': {i18n.G("STORAGE POOL"), func(entry api.TrashEntry) string { return entry.Pool }},
+		't': {i18n.G("TYPE"), func(entry api.TrashEntry) string { return entry.Type }},
+		'u': {i18n.G("UUID"), func(entry api.TrashEntry) string { return entry.UUID }},
+	}
+
+	if clustered {
+		columnsShorthandMap['L'] = trashColumn{i18n.G("LOCATION"), func(entry api.TrashEntry) string { return entry.Location }}
+	} else {
+		if c.flagColumns != defaultTrashColumns {
+			if strings.ContainsAny(c.flagColumns, "L") {
+				return nil, errors.New(i18n.G("Can't specify column L when not clustered"))
+			}
+		}
+
+		c.flagColumns = strings.ReplaceAll(c.flagColumns, "L", "")
+	}
+
+	columnList := strings.Split(c.flagColumns, ",")
+
+	columns := []trashColumn{}
+	for _, columnEntry := range columnList {
+		if columnEntry == "" {
+			return nil, fmt.Errorf(i18n.G("Empty column entry (redundant, leading or trailing command) in '%s'"), c.flagColumns)
+		}
+
+		for _, columnRune := range columnEntry {
+			column, ok := columnsShorthandMap[columnRune]
+			if !ok {
+				return nil, fmt.Errorf(i18n.G("Unknown column shorthand char '%c' in '%s'"), columnRune, columnEntry)
+			}
+
+			columns = append(columns, column)
+		}
+	}
+
+	return columns, nil
+}
+
+// Restore.
+type cmdAdminTrashRestore struct {
+	global *cmdGlobal
+	trash  *cmdAdminTrash
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminTrashRestore) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("restore", i18n.G("[<remote>:]<uuid> [<new-name>]"))
+	cmd.Short = i18n.G("Restore a deleted instance or custom volume")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Restore a deleted instance or custom volume
+
+The entry is restored under its original name unless a new name is given.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminTrashRestore) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, UUID, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	req := api.TrashEntryPost{}
+	if len(args) > 1 {
+		req.Name = args[1]
+	}
+
+	op, err := remoteServer.RestoreTrashEntry(UUID, req)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// Purge.
+type cmdAdminTrashPurge struct {
+	global *cmdGlobal
+	trash  *cmdAdminTrash
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdAdminTrashPurge) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("purge", i18n.G("[<remote>:]<uuid>"))
+	cmd.Short = i18n.G("Permanently remove a trash entry")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Permanently remove a trash entry`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdAdminTrashPurge) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remoteName, UUID, err := c.global.conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	return remoteServer.DeleteTrashEntry(UUID)
+}
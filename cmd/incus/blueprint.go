@@ -0,0 +1,543 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdBlueprint struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprint) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("blueprint")
+	cmd.Short = i18n.G("Manage instance blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance blueprints
+
+Blueprints are named, versioned instance definitions (type, image, profiles,
+configuration and devices) which instances can be created from.`))
+
+	// Create
+	blueprintCreateCmd := cmdBlueprintCreate{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintCreateCmd.Command())
+
+	// Delete
+	blueprintDeleteCmd := cmdBlueprintDelete{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintDeleteCmd.Command())
+
+	// Edit
+	blueprintEditCmd := cmdBlueprintEdit{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintEditCmd.Command())
+
+	// List
+	blueprintListCmd := cmdBlueprintList{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintListCmd.Command())
+
+	// Rename
+	blueprintRenameCmd := cmdBlueprintRename{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintRenameCmd.Command())
+
+	// Show
+	blueprintShowCmd := cmdBlueprintShow{global: c.global, blueprint: c}
+	cmd.AddCommand(blueprintShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, _ []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdBlueprintCreate struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+
+	flagDescription string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<blueprint>"))
+	cmd.Short = i18n.G("Create blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create blueprints`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus blueprint create web < web.yaml
+    Create a blueprint named web with the definition from web.yaml`))
+
+	cmd.RunE = c.Run
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Blueprint description")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.BlueprintPut
+
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.Unmarshal(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing blueprint name"))
+	}
+
+	// Create the blueprint
+	blueprint := api.BlueprintsPost{}
+	blueprint.Name = resource.name
+	blueprint.BlueprintPut = stdinData
+
+	if c.flagDescription != "" {
+		blueprint.Description = c.flagDescription
+	}
+
+	err = resource.server.CreateBlueprint(blueprint)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Blueprint %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdBlueprintDelete struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<blueprint>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete blueprints`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing blueprint name"))
+	}
+
+	// Delete the blueprint
+	err = resource.server.DeleteBlueprint(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Blueprint %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdBlueprintEdit struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<blueprint>"))
+	cmd.Short = i18n.G("Edit blueprints as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit blueprints as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus blueprint edit <blueprint> < blueprint.yaml
+    Update a blueprint using the content of blueprint.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdBlueprintEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the blueprint.
+### Any line starting with a '# will be ignored.
+###
+### A blueprint describes the instances created from it.
+###
+### An example would look like:
+### name: web
+### type: container
+### source:
+###   type: image
+###   alias: debian/12
+###   server: https://images.linuxcontainers.org
+###   protocol: simplestreams
+### profiles:
+### - default
+### config:
+###   limits.cpu: "2"
+### devices: {}
+###
+### Note that the name, project and version are shown but cannot be changed`)
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing blueprint name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.BlueprintPut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateBlueprint(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	blueprint, etag, err := resource.server.GetBlueprint(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&blueprint)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.BlueprintPut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateBlueprint(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdBlueprintList struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+
+	flagFormat string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List blueprints`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G("Format (csv|json|table|yaml|compact)")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the blueprints
+	blueprints, err := resource.server.GetBlueprints()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, blueprint := range blueprints {
+		source := blueprint.Source.Alias
+		if source == "" {
+			source = blueprint.Source.Fingerprint
+		}
+
+		data = append(data, []string{
+			blueprint.Name,
+			blueprint.Description,
+			string(blueprint.Type),
+			source,
+			strings.Join(blueprint.Profiles, "\n"),
+			fmt.Sprintf("%d", blueprint.Version),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("TYPE"),
+		i18n.G("SOURCE"),
+		i18n.G("PROFILES"),
+		i18n.G("VERSION"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, blueprints)
+}
+
+// Rename.
+type cmdBlueprintRename struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<blueprint> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename blueprints`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing blueprint name"))
+	}
+
+	// Rename the blueprint
+	err = resource.server.RenameBlueprint(resource.name, api.BlueprintPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Blueprint %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdBlueprintShow struct {
+	global    *cmdGlobal
+	blueprint *cmdBlueprint
+
+	flagVersion int64
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdBlueprintShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<blueprint>"))
+	cmd.Short = i18n.G("Show blueprints")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show blueprints`))
+
+	cmd.Flags().Int64Var(&c.flagVersion, "version", 0, i18n.G("Show a previous version of the blueprint")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdBlueprintShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.parseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New(i18n.G("Missing blueprint name"))
+	}
+
+	// Show the blueprint
+	var blueprint *api.Blueprint
+	if c.flagVersion != 0 {
+		blueprint, err = resource.server.GetBlueprintVersion(resource.name, c.flagVersion)
+	} else {
+		blueprint, _, err = resource.server.GetBlueprint(resource.name)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&blueprint)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
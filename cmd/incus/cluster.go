@@ -102,6 +102,10 @@ func (c *cmdCluster) Command() *cobra.Command {
 	cmdClusterRestore := cmdClusterRestore{global: c.global, cluster: c}
 	cmd.AddCommand(cmdClusterRestore.Command())
 
+	// Rebalance cluster
+	cmdClusterRebalance := cmdClusterRebalance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterRebalance.Command())
+
 	clusterGroupCmd := cmdClusterGroup{global: c.global, cluster: c}
 	cmd.AddCommand(clusterGroupCmd.Command())
 
@@ -1523,6 +1527,142 @@ func (c *cmdClusterRestore) Command() *cobra.Command {
 	return cmd
 }
 
+// Cluster rebalance.
+type cmdClusterRebalance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagDryRun bool
+	flagForce  bool
+	flagFormat string
+	flagGroup  string
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdClusterRebalance) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rebalance", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Rebalance instances across the cluster members")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rebalance instances across the cluster members
+
+Running instances are live-migrated while stopped instances are moved.
+Instances which can't be live-migrated while running are left in place.
+
+Use --dry-run to only show the instance moves which would be performed.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus cluster rebalance --dry-run
+    Show the instance moves needed to rebalance the cluster.
+
+incus cluster rebalance --group=gpu
+    Rebalance the instances across the members of the "gpu" cluster group.`))
+
+	cmd.RunE = c.Run
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, i18n.G("Only show the instance moves without performing them"))
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G("Rebalance without user confirmation"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", c.global.defaultListFormat(), i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+	cmd.Flags().StringVar(&c.flagGroup, "group", "", i18n.G("Only rebalance the members of a cluster group")+"``")
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	_ = cmd.RegisterFlagCompletionFunc("group", func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return c.global.cmpClusterGroupNames(toComplete)
+	})
+
+	return cmd
+}
+
+// Run runs the actual command logic.
+func (c *cmdClusterRebalance) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.checkArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.parseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	req := api.ClusterRebalancePost{Group: c.flagGroup}
+
+	if c.flagDryRun {
+		plan, err := resource.server.GetClusterRebalancePlan(req)
+		if err != nil {
+			return err
+		}
+
+		data := [][]string{}
+		for _, move := range plan.Moves {
+			live := i18n.G("NO")
+			if move.Live {
+				live = i18n.G("YES")
+			}
+
+			data = append(data, []string{move.Project, move.Instance, move.Source, move.Target, live})
+		}
+
+		header := []string{
+			i18n.G("PROJECT"),
+			i18n.G("INSTANCE"),
+			i18n.G("SOURCE"),
+			i18n.G("TARGET"),
+			i18n.G("LIVE"),
+		}
+
+		return cli.RenderTable(os.Stdout, c.flagFormat, header, data, plan.Moves)
+	}
+
+	if !c.flagForce {
+		rebalance, err := c.global.asker.AskBool(i18n.G("Are you sure you want to rebalance the cluster? (yes/no) [default=no]: "), "no")
+		if err != nil {
+			return err
+		}
+
+		if !rebalance {
+			return nil
+		}
+	}
+
+	op, err := resource.server.RebalanceCluster(req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Rebalancing cluster: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+	return nil
+}
+
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
 func (c *cmdClusterEvacuateAction) Command() *cobra.Command {
 	cmd := &cobra.Command{}
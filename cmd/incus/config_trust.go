@@ -83,6 +83,7 @@ type cmdConfigTrustAdd struct {
 
 	flagProjects   string
 	flagRestricted bool
+	flagExpiry     string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -98,6 +99,7 @@ This will issue a trust token to be used by the client to add itself to the trus
 
 	cmd.Flags().BoolVar(&c.flagRestricted, "restricted", false, i18n.G("Restrict the certificate to one or more projects"))
 	cmd.Flags().StringVar(&c.flagProjects, "projects", "", i18n.G("List of projects to restrict the certificate to")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G("Time after which the token expires (overrides core.remote_token_expiry)")+"``")
 
 	cmd.RunE = c.Run
 
@@ -134,6 +136,14 @@ func (c *cmdConfigTrustAdd) Run(cmd *cobra.Command, args []string) error {
 		cert.Projects = strings.Split(c.flagProjects, ",")
 	}
 
+	if c.flagExpiry != "" {
+		if !resource.server.HasExtension("certificate_token_expiry") {
+			return errors.New(i18n.G("The server doesn't support setting a token expiry"))
+		}
+
+		cert.TokenExpiry = c.flagExpiry
+	}
+
 	// Create the token.
 	op, err := resource.server.CreateCertificateToken(cert)
 	if err != nil {
@@ -179,6 +189,9 @@ func (c *cmdConfigTrustAddCertificate) Command() *cobra.Command {
 The following certificate types are supported:
 - client (default)
 - metrics
+- ca (clients with a certificate issued by the CA are trusted)
+
+A CA certificate may be followed by its intermediate certificates in the same file.
 `))
 
 	cmd.Flags().BoolVar(&c.flagRestricted, "restricted", false, i18n.G("Restrict the certificate to one or more projects"))
@@ -201,7 +214,7 @@ func (c *cmdConfigTrustAddCertificate) Run(cmd *cobra.Command, args []string) er
 	}
 
 	// Validate flags.
-	if !slices.Contains([]string{"client", "metrics"}, c.flagType) {
+	if !slices.Contains([]string{"client", "metrics", "ca"}, c.flagType) {
 		return fmt.Errorf(i18n.G("Unknown certificate type %q"), c.flagType)
 	}
 
@@ -236,6 +249,11 @@ func (c *cmdConfigTrustAddCertificate) Run(cmd *cobra.Command, args []string) er
 		return errors.New("The server doesn't implement metrics")
 	}
 
+	// Validate server support for trusted CAs.
+	if c.flagType == "ca" && !resource.server.HasExtension("certificate_ca") {
+		return errors.New(i18n.G("The server doesn't support trusted CAs"))
+	}
+
 	// Load the certificate.
 	x509Cert, err := localtls.ReadCert(path)
 	if err != nil {
@@ -260,6 +278,15 @@ func (c *cmdConfigTrustAddCertificate) Run(cmd *cobra.Command, args []string) er
 		cert.Type = api.CertificateTypeClient
 	case "metrics":
 		cert.Type = api.CertificateTypeMetrics
+	case "ca":
+		// Send the whole file to include the intermediates.
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		cert.Type = api.CertificateTypeCA
+		cert.Certificate = string(content)
 	}
 
 	cert.Restricted = c.flagRestricted
@@ -485,6 +512,10 @@ func (c *cmdConfigTrustList) nameColumnData(rowData rowData) string {
 }
 
 func (c *cmdConfigTrustList) commonNameColumnData(rowData rowData) string {
+	if rowData.TLSCert == nil {
+		return ""
+	}
+
 	return rowData.TLSCert.Subject.CommonName
 }
 
@@ -497,10 +528,18 @@ func (c *cmdConfigTrustList) descriptionColumnData(rowData rowData) string {
 }
 
 func (c *cmdConfigTrustList) issueDateColumnData(rowData rowData) string {
+	if rowData.TLSCert == nil {
+		return ""
+	}
+
 	return rowData.TLSCert.NotBefore.Local().Format(dateLayout)
 }
 
 func (c *cmdConfigTrustList) expiryDateColumnData(rowData rowData) string {
+	if rowData.TLSCert == nil {
+		return ""
+	}
+
 	return rowData.TLSCert.NotAfter.Local().Format(dateLayout)
 }
 
@@ -563,14 +602,18 @@ func (c *cmdConfigTrustList) Run(cmd *cobra.Command, args []string) error {
 
 	data := [][]string{}
 	for _, cert := range trust {
-		certBlock, _ := pem.Decode([]byte(cert.Certificate))
-		if certBlock == nil {
-			return errors.New(i18n.G("Invalid certificate"))
-		}
+		// SSH public keys don't have any X509 details.
+		var tlsCert *x509.Certificate
+		if cert.Type != api.CertificateTypeSSH {
+			certBlock, _ := pem.Decode([]byte(cert.Certificate))
+			if certBlock == nil {
+				return errors.New(i18n.G("Invalid certificate"))
+			}
 
-		tlsCert, err := x509.ParseCertificate(certBlock.Bytes)
-		if err != nil {
-			return err
+			tlsCert, err = x509.ParseCertificate(certBlock.Bytes)
+			if err != nil {
+				return err
+			}
 		}
 
 		rowData := rowData{cert, tlsCert}
@@ -46,7 +46,7 @@ as well as retrieve past log entries from it.`))
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Forces a connection to the console, even if there is already an active session"))
 	cmd.Flags().BoolVar(&c.flagShowLog, "show-log", false, i18n.G("Retrieve the instance's console log"))
-	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output")+"``")
+	cmd.Flags().StringVarP(&c.flagType, "type", "t", "console", i18n.G("Type of connection to establish: 'console' for serial console, 'vga' for SPICE graphical output, 'vnc' for VNC graphical output")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return c.global.cmpInstances(toComplete)
@@ -114,7 +114,7 @@ func (c *cmdConsole) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate flags.
-	if !slices.Contains([]string{"console", "vga"}, c.flagType) {
+	if !slices.Contains([]string{"console", "vga", "vnc"}, c.flagType) {
 		return fmt.Errorf(i18n.G("Unknown output type %q"), c.flagType)
 	}
 
@@ -162,8 +162,8 @@ func (c *cmdConsole) console(d incus.InstanceServer, name string) error {
 	switch c.flagType {
 	case "console":
 		return c.text(d, name)
-	case "vga":
-		return c.vga(d, name)
+	case "vga", "vnc":
+		return c.vga(d, name, c.flagType)
 	}
 
 	return fmt.Errorf(i18n.G("Unknown console type %q"), c.flagType)
@@ -240,10 +240,16 @@ func (c *cmdConsole) text(d incus.InstanceServer, name string) error {
 	return nil
 }
 
-func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
+func (c *cmdConsole) vga(d incus.InstanceServer, name string, consoleType string) error {
 	var err error
 	conf := c.global.conf
 
+	// Determine the URI scheme used by the viewers.
+	scheme := "spice"
+	if consoleType == "vnc" {
+		scheme = "vnc"
+	}
+
 	// We currently use the control websocket just to abort in case of errors.
 	controlDone := make(chan struct{}, 1)
 	handler := func(control *websocket.Conn) {
@@ -254,7 +260,7 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 
 	// Prepare the remote console.
 	req := api.InstanceConsolePost{
-		Type:  "vga",
+		Type:  consoleType,
 		Force: c.flagForce,
 	}
 
@@ -270,7 +276,7 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 	var socket string
 	var listener net.Listener
 	if runtime.GOOS != "windows" {
-		// Create a temporary unix socket mirroring the instance's spice socket.
+		// Create a temporary unix socket mirroring the instance's spice or vnc socket.
 		if !util.PathExists(conf.ConfigPath("sockets")) {
 			err := os.MkdirAll(conf.ConfigPath("sockets"), 0o700)
 			if err != nil {
@@ -279,7 +285,7 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 		}
 
 		// Generate a random file name.
-		path, err := os.CreateTemp(conf.ConfigPath("sockets"), "*.spice")
+		path, err := os.CreateTemp(conf.ConfigPath("sockets"), "*."+scheme)
 		if err != nil {
 			return err
 		}
@@ -299,7 +305,7 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 
 		defer func() { _ = os.Remove(path.Name()) }()
 
-		socket = fmt.Sprintf("spice+unix://%s", path.Name())
+		socket = fmt.Sprintf("%s+unix://%s", scheme, path.Name())
 	} else {
 		listener, err = net.Listen("tcp", "127.0.0.1:0")
 		if err != nil {
@@ -311,7 +317,7 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 			return errors.New("Bad TCP listener")
 		}
 
-		socket = fmt.Sprintf("spice://127.0.0.1:%d", addr.Port)
+		socket = fmt.Sprintf("%s://127.0.0.1:%d", scheme, addr.Port)
 	}
 
 	// Clean everything up when the viewer is done.
@@ -358,9 +364,12 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 		}
 	}()
 
-	// Use either spicy or remote-viewer if available.
+	// Use either spicy or remote-viewer if available (spicy only supports SPICE).
 	remoteViewer := c.findCommand("remote-viewer")
-	spicy := c.findCommand("spicy")
+	spicy := ""
+	if scheme == "spice" {
+		spicy = c.findCommand("spicy")
+	}
 
 	if remoteViewer != "" || spicy != "" {
 		var cmd *exec.Cmd
@@ -396,8 +405,14 @@ func (c *cmdConsole) vga(d incus.InstanceServer, name string) error {
 			_ = cmd.Process.Kill()
 		}()
 	} else {
-		fmt.Println(i18n.G("The client automatically uses either spicy or remote-viewer when present."))
-		fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
+		if scheme == "vnc" {
+			fmt.Println(i18n.G("The client automatically uses remote-viewer when present."))
+			fmt.Println(i18n.G("As it couldn't be found, the raw VNC socket can be found at:"))
+		} else {
+			fmt.Println(i18n.G("The client automatically uses either spicy or remote-viewer when present."))
+			fmt.Println(i18n.G("As neither could be found, the raw SPICE socket can be found at:"))
+		}
+
 		fmt.Printf("  %s\n", socket)
 
 		// Wait for all connections to complete.
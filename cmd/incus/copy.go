@@ -33,6 +33,7 @@ type cmdCopy struct {
 	flagRefresh             bool
 	flagRefreshExcludeOlder bool
 	flagAllowInconsistent   bool
+	flagAllowRunning        bool
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -67,6 +68,7 @@ The pull transfer mode is the default as it is compatible with all server versio
 	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, i18n.G("Perform an incremental copy"))
 	cmd.Flags().BoolVar(&c.flagRefreshExcludeOlder, "refresh-exclude-older", false, i18n.G("During incremental copy, exclude source snapshots earlier than latest target snapshot"))
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, i18n.G("Ignore copy errors for volatile files"))
+	cmd.Flags().BoolVar(&c.flagAllowRunning, "allow-running", false, i18n.G("Copy a running instance from a quiesced temporary snapshot"))
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -268,6 +270,7 @@ func (c *cmdCopy) copyInstance(conf *config.Config, sourceResource string, destR
 			Refresh:             c.flagRefresh,
 			RefreshExcludeOlder: c.flagRefreshExcludeOlder,
 			AllowInconsistent:   c.flagAllowInconsistent,
+			AllowRunning:        c.flagAllowRunning,
 		}
 
 		// Copy of an instance into a new instance
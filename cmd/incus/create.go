@@ -24,19 +24,21 @@ import (
 type cmdCreate struct {
 	global *cmdGlobal
 
-	flagConfig          []string
-	flagDevice          []string
-	flagEnvironmentFile string
-	flagEphemeral       bool
-	flagNetwork         string
-	flagProfile         []string
-	flagStorage         string
-	flagTarget          string
-	flagType            string
-	flagNoProfiles      bool
-	flagEmpty           bool
-	flagVM              bool
-	flagDescription     string
+	flagConfig           []string
+	flagDevice           []string
+	flagEnvironmentFile  string
+	flagEphemeral        bool
+	flagNetwork          string
+	flagProfile          []string
+	flagStorage          string
+	flagTarget           string
+	flagType             string
+	flagNoProfiles       bool
+	flagEmpty            bool
+	flagVM               bool
+	flagDescription      string
+	flagBlueprint        string
+	flagBlueprintVersion int64
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -51,7 +53,10 @@ incus create images:debian/12 u1 < config.yaml
     Create the instance with configuration from config.yaml
 
 incus launch images:debian/12 v2 --vm -d root,size=50GiB -d root,io.bus=nvme
-    Create and start a virtual machine, overriding the disk size and bus`))
+    Create and start a virtual machine, overriding the disk size and bus
+
+incus create --blueprint web w1
+    Create the instance from the "web" blueprint`))
 
 	cmd.Aliases = []string{"init"}
 	cmd.RunE = c.Run
@@ -68,6 +73,8 @@ incus launch images:debian/12 v2 --vm -d root,size=50GiB -d root,io.bus=nvme
 	cmd.Flags().BoolVar(&c.flagEmpty, "empty", false, i18n.G("Create an empty instance"))
 	cmd.Flags().BoolVar(&c.flagVM, "vm", false, i18n.G("Create a virtual machine"))
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Instance description")+"``")
+	cmd.Flags().StringVar(&c.flagBlueprint, "blueprint", "", i18n.G("Blueprint to create the instance from")+"``")
+	cmd.Flags().Int64Var(&c.flagBlueprintVersion, "blueprint-version", 0, i18n.G("Version of the blueprint to create the instance from")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) != 0 {
@@ -88,7 +95,7 @@ func (c *cmdCreate) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(args) == 0 && !c.flagEmpty {
+	if len(args) == 0 && !c.flagEmpty && c.flagBlueprint == "" {
 		_ = cmd.Usage()
 		return nil
 	}
@@ -140,8 +147,12 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 		}
 	}
 
-	if c.flagEmpty {
+	if c.flagEmpty || c.flagBlueprint != "" {
 		if len(args) > 1 {
+			if c.flagBlueprint != "" {
+				return nil, "", errors.New(i18n.G("--blueprint cannot be combined with an image name"))
+			}
+
 			return nil, "", errors.New(i18n.G("--empty cannot be combined with an image name"))
 		}
 
@@ -272,6 +283,9 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 	instanceDBType := api.InstanceTypeContainer
 	if c.flagVM {
 		instanceDBType = api.InstanceTypeVM
+	} else if c.flagBlueprint != "" {
+		// Let the blueprint decide.
+		instanceDBType = ""
 	}
 
 	// Set the target if provided.
@@ -281,10 +295,12 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 
 	// Setup instance creation request
 	req := api.InstancesPost{
-		Name:         name,
-		InstanceType: c.flagType,
-		Type:         instanceDBType,
-		Start:        launch,
+		Name:             name,
+		InstanceType:     c.flagType,
+		Type:             instanceDBType,
+		Start:            launch,
+		Blueprint:        c.flagBlueprint,
+		BlueprintVersion: c.flagBlueprintVersion,
 	}
 
 	req.Config = configMap
@@ -367,7 +383,23 @@ func (c *cmdCreate) create(conf *config.Config, args []string, launch bool) (inc
 	req.Devices = devicesMap
 
 	var opInfo api.Operation
-	if !c.flagEmpty {
+	if c.flagBlueprint != "" {
+		if !d.HasExtension("instance_blueprints") {
+			return nil, "", errors.New(i18n.G("The server doesn't support blueprints"))
+		}
+
+		op, err := d.CreateInstance(req)
+		if err != nil {
+			return nil, "", err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return nil, "", err
+		}
+
+		opInfo = op.Get()
+	} else if !c.flagEmpty {
 		// Get the image server and image info
 		iremote, image = guessImage(conf, d, remote, iremote, image)
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -23,6 +24,8 @@ type cmdExport struct {
 	flagInstanceOnly         bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagFormat               string
+	flagThrottle             string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -34,7 +37,10 @@ func (c *cmdExport) Command() *cobra.Command {
 		`Export instances as backup tarballs.`))
 	cmd.Example = cli.FormatSection("", i18n.G(
 		`incus export u1 backup0.tar.gz
-    Download a backup tarball of the u1 instance.`))
+    Download a backup tarball of the u1 instance.
+
+incus export v1 --format=qcow2
+    Download the disk of the v1 virtual machine as a qcow2 image.`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagInstanceOnly, "instance-only", false,
@@ -42,6 +48,8 @@ func (c *cmdExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (none for uncompressed)")+"``")
+	cmd.Flags().StringVar(&c.flagFormat, "format", "", i18n.G("Export the virtual machine disk as a qcow2 or vmdk image instead of a backup tarball")+"``")
+	cmd.Flags().StringVar(&c.flagThrottle, "throttle", "", i18n.G("Rate limit for the backup creation and download in bytes per second (e.g. 50MB)")+"``")
 
 	return cmd
 }
@@ -69,12 +77,22 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 
 	instanceOnly := c.flagInstanceOnly
 
+	// Disk images don't include snapshots.
+	if c.flagFormat != "" {
+		if c.flagOptimizedStorage {
+			return errors.New(i18n.G("--format can't be used with --optimized-storage"))
+		}
+
+		instanceOnly = true
+	}
+
 	req := api.InstanceBackupsPost{
 		Name:                 "",
 		ExpiresAt:            time.Now().Add(24 * time.Hour),
 		InstanceOnly:         instanceOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		Throttle:             c.flagThrottle,
 	}
 
 	op, err := d.CreateInstanceBackup(name, req)
@@ -131,6 +149,8 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	var targetName string
 	if len(args) > 1 {
 		targetName = args[1]
+	} else if c.flagFormat != "" {
+		targetName = name + "." + c.flagFormat
 	} else {
 		targetName = name + ".backup"
 	}
@@ -157,6 +177,8 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	backupFileRequest := incus.BackupFileRequest{
 		BackupFile:      io.WriteSeeker(target),
 		ProgressHandler: progress.UpdateProgress,
+		Format:          c.flagFormat,
+		Throttle:        c.flagThrottle,
 	}
 
 	// Export tarball
@@ -168,7 +190,7 @@ func (c *cmdExport) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Detect backup file type and rename file accordingly
-	if len(args) <= 1 {
+	if len(args) <= 1 && c.flagFormat == "" {
 		_, err := target.Seek(0, io.SeekStart)
 		if err != nil {
 			return err
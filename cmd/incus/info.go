@@ -194,6 +194,10 @@ func (c *cmdInfo) renderGPU(gpu api.ResourcesGPUCard, prefix string, initial boo
 					fmt.Printf(prefix+"      %s\n", line)
 				}
 			}
+
+			if len(v.UsedBy) > 0 {
+				fmt.Printf(prefix+"      "+i18n.G("Used by: %s")+"\n", strings.Join(v.UsedBy, ", "))
+			}
 		}
 	}
 }
@@ -500,13 +504,24 @@ func (c *cmdInfo) remoteInfo(d incus.InstanceServer) error {
 		}
 
 		// CPU
+		renderVirtualization := func() {
+			if resources.CPU.Virtualization == nil {
+				return
+			}
+
+			fmt.Printf("  "+i18n.G("Virtualization: %s")+"\n", resources.CPU.Virtualization.Extension)
+			fmt.Printf("  "+i18n.G("Nested virtualization: %v")+"\n", resources.CPU.Virtualization.Nested)
+		}
+
 		if len(resources.CPU.Sockets) == 1 {
 			fmt.Print("\n" + i18n.G("CPU:") + "\n")
 			fmt.Printf("  "+i18n.G("Architecture: %s")+"\n", resources.CPU.Architecture)
+			renderVirtualization()
 			c.renderCPU(resources.CPU.Sockets[0], "  ")
 		} else if len(resources.CPU.Sockets) > 1 {
 			fmt.Print(i18n.G("CPUs:") + "\n")
 			fmt.Printf("  "+i18n.G("Architecture: %s")+"\n", resources.CPU.Architecture)
+			renderVirtualization()
 			for _, cpu := range resources.CPU.Sockets {
 				fmt.Printf("  "+i18n.G("Socket %d:")+"\n", cpu.Socket)
 				c.renderCPU(cpu, "    ")
@@ -662,6 +677,10 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog bool
 		fmt.Printf(i18n.G("PID: %d")+"\n", inst.State.Pid)
 	}
 
+	if len(inst.State.BootOrder) > 0 {
+		fmt.Printf(i18n.G("Boot order: %s")+"\n", strings.Join(inst.State.BootOrder, ", "))
+	}
+
 	if !inst.CreatedAt.IsZero() {
 		fmt.Printf(i18n.G("Created: %s")+"\n", inst.CreatedAt.Local().Format(dateLayout))
 	}
@@ -705,6 +724,35 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog bool
 			fmt.Print(diskInfo)
 		}
 
+		// Disk I/O
+		diskIOInfo := ""
+		if inst.State.Disk != nil {
+			diskNames := make([]string, 0, len(inst.State.Disk))
+			for diskName := range inst.State.Disk {
+				diskNames = append(diskNames, diskName)
+			}
+
+			sort.Strings(diskNames)
+
+			for _, diskName := range diskNames {
+				disk := inst.State.Disk[diskName]
+				if disk.ReadOps == 0 && disk.WriteOps == 0 {
+					continue
+				}
+
+				diskIOInfo += fmt.Sprintf("    %s:\n", diskName)
+				diskIOInfo += fmt.Sprintf("      %s: %s\n", i18n.G("Bytes read"), units.GetByteSizeStringIEC(disk.ReadBytes, 2))
+				diskIOInfo += fmt.Sprintf("      %s: %s\n", i18n.G("Bytes written"), units.GetByteSizeStringIEC(disk.WriteBytes, 2))
+				diskIOInfo += fmt.Sprintf("      %s: %d\n", i18n.G("Read operations"), disk.ReadOps)
+				diskIOInfo += fmt.Sprintf("      %s: %d\n", i18n.G("Write operations"), disk.WriteOps)
+			}
+		}
+
+		if diskIOInfo != "" {
+			fmt.Printf("  %s\n", i18n.G("Disk I/O:"))
+			fmt.Print(diskIOInfo)
+		}
+
 		// CPU usage
 		cpuInfo := ""
 		if inst.State.CPU.Usage != 0 {
@@ -734,6 +782,14 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, name string, showLog bool
 			memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Swap (peak)"), units.GetByteSizeStringIEC(inst.State.Memory.SwapUsagePeak, 2))
 		}
 
+		if inst.State.Memory.HugepagesUsage != 0 {
+			memoryInfo += fmt.Sprintf("    %s: %s\n", i18n.G("Huge pages"), units.GetByteSizeStringIEC(inst.State.Memory.HugepagesUsage, 2))
+		}
+
+		if inst.State.Memory.OOMKills != 0 {
+			memoryInfo += fmt.Sprintf("    %s: %d\n", i18n.G("OOM kills"), inst.State.Memory.OOMKills)
+		}
+
 		if memoryInfo != "" {
 			fmt.Printf("  %s\n", i18n.G("Memory usage:"))
 			fmt.Print(memoryInfo)
@@ -451,6 +451,28 @@ func (c *cmdList) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	needsSnapshots := slices.ContainsFunc(columns, func(column column) bool { return column.NeedsSnapshots })
+
+	if needsData && !needsSnapshots && d.HasExtension("instances_state_bulk") {
+		// Using the GetInstancesWithState shortcut, skipping the snapshots.
+		var instances []api.InstanceFull
+
+		serverFilters, clientFilters := getServerSupportedFilters(filters, []string{"ipv4", "ipv6"}, true)
+		serverFilters = prepareInstanceServerFilters(serverFilters, api.InstanceFull{})
+
+		if c.flagAllProjects {
+			instances, err = d.GetInstancesWithStateAllProjects(api.InstanceTypeAny, serverFilters)
+		} else {
+			instances, err = d.GetInstancesWithState(api.InstanceTypeAny, serverFilters)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return c.showInstances(instances, clientFilters, columns)
+	}
+
 	if needsData && d.HasExtension("container_full") {
 		// Using the GetInstancesFull shortcut
 		var instances []api.InstanceFull
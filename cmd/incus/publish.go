@@ -24,6 +24,7 @@ type cmdPublish struct {
 	flagMakePublic           bool
 	flagForce                bool
 	flagReuse                bool
+	flagStateful             bool
 	flagFormat               string
 }
 
@@ -41,6 +42,7 @@ func (c *cmdPublish) Command() *cobra.Command {
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Stop the instance if currently running"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Compression algorithm to use (`none` for uncompressed)"))
 	cmd.Flags().StringVar(&c.flagExpiresAt, "expire", "", i18n.G("Image expiration date (format: rfc3339)")+"``")
+	cmd.Flags().BoolVar(&c.flagStateful, "stateful", false, i18n.G("Include the state of the running container in the image"))
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, i18n.G("If the image alias already exists, delete and create a new one"))
 	cmd.Flags().StringVar(&c.flagFormat, "format", "unified", i18n.G("Image format")+"``")
 
@@ -113,7 +115,17 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if !instance.IsSnapshot(cName) {
+	if c.flagStateful {
+		if instance.IsSnapshot(cName) {
+			return errors.New(i18n.G("Snapshots can't be published with their state"))
+		}
+
+		if !s.HasExtension("instance_publish_stateful") {
+			return errors.New(i18n.G("The server doesn't support publishing instances with their state"))
+		}
+	}
+
+	if !instance.IsSnapshot(cName) && !c.flagStateful {
 		ct, etag, err := s.GetInstance(cName)
 		if err != nil {
 			return err
@@ -260,6 +272,7 @@ func (c *cmdPublish) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	req.Format = c.flagFormat
+	req.Stateful = c.flagStateful
 
 	op, err := s.CreateImage(req, nil)
 	if err != nil {
@@ -3,22 +3,28 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	incus "github.com/lxc/incus/v6/client"
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
 	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/shared/api"
 	config "github.com/lxc/incus/v6/shared/cliconfig"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/units"
 )
 
 // Rebuild.
 type cmdRebuild struct {
-	global    *cmdGlobal
-	flagEmpty bool
-	flagForce bool
+	global         *cmdGlobal
+	flagEmpty      bool
+	flagForce      bool
+	flagBackup     string
+	flagBackupFile string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -27,11 +33,25 @@ func (c *cmdRebuild) Command() *cobra.Command {
 	cmd.Use = usage("rebuild", i18n.G("[<remote>:]<image> [<remote>:]<instance>"))
 	cmd.Short = i18n.G("Rebuild instances")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
-		`Wipe the instance root disk and re-initialize with a new image (or empty volume).`))
+		`Wipe the instance root disk and re-initialize with a new image (or empty volume).
+
+The root disk can also be restored from one of the instance's backups or from a backup file,
+keeping the instance configuration (including network addresses) as is.`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus rebuild images:debian/12 c1
+    Rebuild c1 from the Debian 12 image
+
+incus rebuild c1 --backup backup0
+    Restore the root disk of c1 from its backup0 backup
+
+incus rebuild c1 --backup-file c1.tar.gz
+    Restore the root disk of c1 from the c1.tar.gz backup file`))
 
 	cmd.RunE = c.Run
 	cmd.Flags().BoolVar(&c.flagEmpty, "empty", false, i18n.G("Rebuild as an empty instance"))
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("If an instance is running, stop it and then rebuild it"))
+	cmd.Flags().StringVar(&c.flagBackup, "backup", "", i18n.G("Rebuild from an existing backup of the instance")+"``")
+	cmd.Flags().StringVar(&c.flagBackupFile, "backup-file", "", i18n.G("Rebuild from a backup file")+"``")
 
 	return cmd
 }
@@ -67,6 +87,16 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 		}
 	}
 
+	if c.flagBackup != "" || c.flagBackupFile != "" {
+		if len(args) > 1 {
+			return errors.New(i18n.G("--backup and --backup-file cannot be combined with an image name"))
+		}
+
+		if c.flagEmpty || (c.flagBackup != "" && c.flagBackupFile != "") {
+			return errors.New(i18n.G("Only one of --empty, --backup or --backup-file can be used"))
+		}
+	}
+
 	d, err := conf.GetInstanceServer(remote)
 	if err != nil {
 		return err
@@ -117,7 +147,12 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 		Source: api.InstanceSource{},
 	}
 
-	if !c.flagEmpty {
+	if c.flagBackup != "" || c.flagBackupFile != "" {
+		err = c.rebuildFromBackup(d, name)
+		if err != nil {
+			return err
+		}
+	} else if !c.flagEmpty {
 		if image == "" && iremote == "" {
 			return errors.New(i18n.G("You need to specify an image name or use --empty"))
 		}
@@ -207,6 +242,71 @@ func (c *cmdRebuild) rebuild(conf *config.Config, args []string) error {
 	return nil
 }
 
+// rebuildFromBackup rebuilds the instance from one of its backups or from a backup file.
+func (c *cmdRebuild) rebuildFromBackup(d incus.InstanceServer, name string) error {
+	progress := cli.ProgressRenderer{
+		Quiet: c.global.flagQuiet,
+	}
+
+	var op incus.Operation
+	if c.flagBackupFile != "" {
+		file, err := os.Open(c.flagBackupFile)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = file.Close() }()
+
+		fstat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		progress.Format = i18n.G("Uploading backup: %s")
+
+		op, err = d.RebuildInstanceFromBackup(name, &ioprogress.ProgressReader{
+			ReadCloser: file,
+			Tracker: &ioprogress.ProgressTracker{
+				Length: fstat.Size(),
+				Handler: func(percent int64, speed int64) {
+					progress.UpdateProgress(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		if !d.HasExtension("instance_rebuild_backup") {
+			return errors.New(i18n.G("The server doesn't support rebuilding instances from a backup"))
+		}
+
+		req := api.InstanceRebuildPost{
+			Source: api.InstanceSource{
+				Type:   "backup",
+				Source: c.flagBackup,
+			},
+		}
+
+		var err error
+		op, err = d.RebuildInstance(name, req)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Wait for operation to finish
+	err := cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return nil
+}
+
 // Run runs the actual command logic.
 func (c *cmdRebuild) Run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
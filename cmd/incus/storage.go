@@ -653,6 +653,23 @@ func (c *cmdStorageInfo) Run(cmd *cobra.Command, args []string) error {
 		poolinfo[infostring][spaceusedstring] = units.GetByteSizeStringIEC(int64(res.Space.Used), 2)
 	}
 
+	// Add the health information when reported by the driver.
+	if resource.server.HasExtension("storage_pool_state") {
+		state, err := resource.server.GetStoragePoolState(resource.name)
+		if err != nil {
+			return err
+		}
+
+		if state.Health != nil {
+			poolinfo[infostring][i18n.G("health")] = state.Health.Status
+			poolinfo[infostring][i18n.G("errors")] = strconv.FormatUint(state.Health.Errors, 10)
+
+			if state.Health.ScrubStatus != "" {
+				poolinfo[infostring][i18n.G("scrub status")] = state.Health.ScrubStatus
+			}
+		}
+	}
+
 	poolinfodata, err := yaml.Marshal(poolinfo)
 	if err != nil {
 		return err
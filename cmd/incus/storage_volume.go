@@ -1483,6 +1483,20 @@ func (c *cmdStorageVolumeInfo) Run(cmd *cobra.Command, args []string) error {
 		fmt.Printf(i18n.G("Created: %s")+"\n", vol.CreatedAt.Local().Format(dateLayout))
 	}
 
+	if volState != nil && volState.Mirror != nil {
+		fmt.Println("\n" + i18n.G("Mirroring:"))
+		fmt.Printf("  "+i18n.G("Mode: %s")+"\n", volState.Mirror.Mode)
+		fmt.Printf("  "+i18n.G("Primary: %v")+"\n", volState.Mirror.Primary)
+
+		if volState.Mirror.State != "" {
+			fmt.Printf("  "+i18n.G("State: %s")+"\n", volState.Mirror.State)
+		}
+
+		if volState.Mirror.Description != "" {
+			fmt.Printf("  "+i18n.G("Description: %s")+"\n", volState.Mirror.Description)
+		}
+	}
+
 	// List snapshots
 	firstSnapshot := true
 	if len(volSnapshots) > 0 {
@@ -3192,6 +3206,8 @@ type cmdStorageVolumeExport struct {
 	flagVolumeOnly           bool
 	flagOptimizedStorage     bool
 	flagCompressionAlgorithm string
+	flagFormat               string
+	flagThrottle             string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -3206,6 +3222,8 @@ func (c *cmdStorageVolumeExport) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&c.flagOptimizedStorage, "optimized-storage", false,
 		i18n.G("Use storage driver optimized format (can only be restored on a similar pool)"))
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", i18n.G("Define a compression algorithm: for backup or none")+"``")
+	cmd.Flags().StringVar(&c.flagFormat, "format", "", i18n.G("Export the block volume as a qcow2 or vmdk image instead of a backup tarball")+"``")
+	cmd.Flags().StringVar(&c.flagThrottle, "throttle", "", i18n.G("Rate limit for the backup creation and download in bytes per second (e.g. 50MB)")+"``")
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
 
@@ -3252,6 +3270,15 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 
 	volumeOnly := c.flagVolumeOnly
 
+	// Disk images don't include snapshots.
+	if c.flagFormat != "" {
+		if c.flagOptimizedStorage {
+			return errors.New(i18n.G("--format can't be used with --optimized-storage"))
+		}
+
+		volumeOnly = true
+	}
+
 	volName, volType := parseVolume("custom", args[1])
 	if volType != "custom" {
 		return errors.New(i18n.G("Only \"custom\" volumes can be exported"))
@@ -3263,6 +3290,7 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 		VolumeOnly:           volumeOnly,
 		OptimizedStorage:     c.flagOptimizedStorage,
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		Throttle:             c.flagThrottle,
 	}
 
 	op, err := d.CreateStorageVolumeBackup(name, volName, req)
@@ -3319,6 +3347,8 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 	var targetName string
 	if len(args) > 2 {
 		targetName = args[2]
+	} else if c.flagFormat != "" {
+		targetName = volName + "." + c.flagFormat
 	} else {
 		targetName = "backup.tar.gz"
 	}
@@ -3339,6 +3369,8 @@ func (c *cmdStorageVolumeExport) Run(cmd *cobra.Command, args []string) error {
 	backupFileRequest := incus.BackupFileRequest{
 		BackupFile:      io.WriteSeeker(target),
 		ProgressHandler: progress.UpdateProgress,
+		Format:          c.flagFormat,
+		Throttle:        c.flagThrottle,
 	}
 
 	// Export tarball
@@ -3359,7 +3391,8 @@ type cmdStorageVolumeImport struct {
 	storage       *cmdStorage
 	storageVolume *cmdStorageVolume
 
-	flagType string
+	flagType   string
+	flagFormat string
 }
 
 // Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
@@ -3373,10 +3406,14 @@ func (c *cmdStorageVolumeImport) Command() *cobra.Command {
     Create a new custom volume using backup0.tar.gz as the source
 
 incus storage volume import default some-installer.iso installer --type=iso
-    Create a new custom volume storing some-installer.iso for use as a CD-ROM image`))
+    Create a new custom volume storing some-installer.iso for use as a CD-ROM image
+
+incus storage volume import default disk.vmdk data --type=disk
+    Create a new custom block volume from the content of disk.vmdk`))
 	cmd.Flags().StringVar(&c.storage.flagTarget, "target", "", i18n.G("Cluster member name")+"``")
 	cmd.RunE = c.Run
-	cmd.Flags().StringVar(&c.flagType, "type", "", i18n.G("Import type, backup or iso (default \"backup\")")+"``")
+	cmd.Flags().StringVar(&c.flagType, "type", "", i18n.G("Import type, backup, iso or disk (default \"backup\")")+"``")
+	cmd.Flags().StringVar(&c.flagFormat, "format", "", i18n.G("Disk image format, raw, qcow2 or vmdk (detected from the file extension by default)")+"``")
 
 	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -3432,17 +3469,22 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 		volName = args[2]
 	}
 
+	fileExt := strings.ToLower(filepath.Ext(file.Name()))
+	diskExts := []string{".img", ".raw", ".qcow2", ".vmdk"}
+
 	if c.flagType == "" {
-		// Set type to iso if filename suffix is .iso
-		if strings.HasSuffix(file.Name(), ".iso") {
+		// Set type to iso or disk based on the filename suffix.
+		if fileExt == ".iso" {
 			c.flagType = "iso"
+		} else if slices.Contains(diskExts, fileExt) || c.flagFormat != "" {
+			c.flagType = "disk"
 		} else {
 			c.flagType = "backup"
 		}
 	} else {
 		// Validate type flag
-		if !slices.Contains([]string{"backup", "iso"}, c.flagType) {
-			return errors.New(i18n.G("Import type needs to be \"backup\" or \"iso\""))
+		if !slices.Contains([]string{"backup", "iso", "disk"}, c.flagType) {
+			return errors.New(i18n.G("Import type needs to be \"backup\", \"iso\" or \"disk\""))
 		}
 	}
 
@@ -3450,6 +3492,28 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 		return errors.New(i18n.G("Importing ISO images requires a volume name to be set"))
 	}
 
+	if c.flagType == "disk" {
+		if volName == "" {
+			return errors.New(i18n.G("Importing disk images requires a volume name to be set"))
+		}
+
+		if c.flagFormat == "" {
+			// Detect the format from the filename suffix.
+			switch fileExt {
+			case ".qcow2":
+				c.flagFormat = "qcow2"
+			case ".vmdk":
+				c.flagFormat = "vmdk"
+			default:
+				c.flagFormat = "raw"
+			}
+		} else if !slices.Contains([]string{"raw", "qcow2", "vmdk"}, c.flagFormat) {
+			return errors.New(i18n.G("Disk image format needs to be \"raw\", \"qcow2\" or \"vmdk\""))
+		}
+	} else if c.flagFormat != "" {
+		return errors.New(i18n.G("A format can only be set when importing disk images"))
+	}
+
 	progress := cli.ProgressRenderer{
 		Format: i18n.G("Importing custom volume: %s"),
 		Quiet:  c.global.flagQuiet,
@@ -3472,6 +3536,12 @@ func (c *cmdStorageVolumeImport) Run(cmd *cobra.Command, args []string) error {
 
 	if c.flagType == "iso" {
 		op, err = d.CreateStoragePoolVolumeFromISO(pool, createArgs)
+	} else if c.flagType == "disk" {
+		op, err = d.CreateStoragePoolVolumeFromDiskImage(pool, incus.StorageVolumeDiskImageArgs{
+			DiskImageFile: createArgs.BackupFile,
+			Format:        c.flagFormat,
+			Name:          volName,
+		})
 	} else {
 		op, err = d.CreateStoragePoolVolumeFromBackup(pool, createArgs)
 	}
@@ -33,16 +33,23 @@ type cmdTop struct {
 	flagColumns     string
 	flagFormat      string
 	flagRefresh     int
+
+	// Previous CPU usage of the processes (by PID), used to compute their current CPU usage.
+	processesCPU  map[int64]int64
+	processesTime time.Time
 }
 
 // Command is a method of the cmdTop structure that returns a new cobra Command for displaying resource usage per instance.
 func (c *cmdTop) Command() *cobra.Command {
 	cmd := &cobra.Command{}
-	cmd.Use = usage("top", i18n.G("[<remote>:]"))
+	cmd.Use = usage("top", i18n.G("[<remote>:][<instance>]"))
 	cmd.Short = i18n.G("Display resource usage info per instance")
 	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
 		`Displays CPU usage, memory usage, and disk usage per instance
 
+When an instance is specified, displays the processes running inside of it
+along with their CPU and memory usage instead.
+
 Default column layout: numD
 
 == Columns ==
@@ -59,6 +66,12 @@ Column shorthand chars:
   m - Memory usage
   n - Instance name
   u - CPU usage (in seconds)`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus top
+    Display the resource usage of the instances in the current project.
+
+incus top c1 --refresh 2
+    Display the processes running in instance "c1", refreshed every 2 seconds.`))
 
 	cmd.Flags().BoolVar(&c.flagAllProjects, "all-projects", false, i18n.G("Display instances from all projects"))
 	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", defaultTopColumns, i18n.G("Columns")+"``")
@@ -66,6 +79,15 @@ Column shorthand chars:
 	cmd.Flags().IntVar(&c.flagRefresh, "refresh", 10, i18n.G("Configure the refresh delay in seconds")+"``")
 
 	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
 	return cmd
 }
 
@@ -154,7 +176,7 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 		remoteInput = args[0]
 	}
 
-	remote, _, err := conf.ParseRemote(remoteInput)
+	remote, instanceName, err := conf.ParseRemote(remoteInput)
 	if err != nil {
 		return err
 	}
@@ -169,7 +191,19 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("Invalid format %q"), c.flagFormat)
 	}
 
-	if c.flagRefresh < 10 {
+	if instanceName != "" {
+		if c.flagAllProjects {
+			return errors.New(i18n.G("--all-projects can't be used when displaying the processes of an instance"))
+		}
+
+		if c.flagRefresh < 1 {
+			return errors.New(i18n.G("The minimum refresh rate is 1s"))
+		}
+
+		if !d.HasExtension("instance_processes") {
+			return errors.New(i18n.G("The server doesn't support listing the processes of an instance"))
+		}
+	} else if c.flagRefresh < 10 {
 		return errors.New(i18n.G("The minimum refresh rate is 10s"))
 	}
 
@@ -186,7 +220,7 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 	}
 
 	// If clustered, get a list of targets.
-	if d.IsClustered() {
+	if d.IsClustered() && instanceName == "" {
 		c.targets, err = d.GetClusterMemberNames()
 		if err != nil {
 			return err
@@ -197,12 +231,20 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 	refreshInterval := time.Duration(c.flagRefresh) * time.Second
 	sortingMethod := alphabetical // default is alphabetical, could change this to a flag
 
+	updateDisplay := func(refreshInterval time.Duration, sortingMethod sortType) error {
+		if instanceName != "" {
+			return c.updateProcessesDisplay(d, instanceName, refreshInterval, sortingMethod)
+		}
+
+		return c.updateDisplay(d, refreshInterval, sortingMethod)
+	}
+
 	// Start the ticker for periodic updates
 	ticker := time.NewTicker(refreshInterval)
 	defer ticker.Stop()
 
 	// Call the update once before the loop
-	err = c.updateDisplay(d, refreshInterval, sortingMethod)
+	err = updateDisplay(refreshInterval, sortingMethod)
 	if err != nil {
 		return err
 	}
@@ -219,7 +261,7 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 			if shouldStop {
 				ticker.Stop()
 			} else {
-				err = c.updateDisplay(d, refreshInterval, sortingMethod)
+				err = updateDisplay(refreshInterval, sortingMethod)
 				if err != nil {
 					return err
 				}
@@ -228,7 +270,7 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 			}
 
 		case <-ticker.C:
-			err = c.updateDisplay(d, refreshInterval, sortingMethod)
+			err = updateDisplay(refreshInterval, sortingMethod)
 			if err != nil {
 				return err
 			}
@@ -251,7 +293,7 @@ func (c *cmdTop) Run(cmd *cobra.Command, args []string) error {
 			fmt.Printf(i18n.G("Updated interval to %v")+"\n", duration)
 
 			// Update display
-			err = c.updateDisplay(d, refreshInterval, sortingMethod)
+			err = updateDisplay(refreshInterval, sortingMethod)
 			if err != nil {
 				return err
 			}
@@ -454,6 +496,95 @@ func (c *cmdTop) updateDisplay(d incus.InstanceServer, refreshInterval time.Dura
 	return nil
 }
 
+func (c *cmdTop) updateProcessesDisplay(d incus.InstanceServer, instanceName string, refreshInterval time.Duration, sortingType sortType) error {
+	processes, err := d.GetInstanceProcesses(instanceName)
+	if err != nil {
+		return err
+	}
+
+	// The CPU usage is computed from the CPU time consumed since the previous update.
+	now := time.Now()
+	elapsed := now.Sub(c.processesTime)
+	cpuUsages := make(map[int64]float64, len(processes))
+	processesCPU := make(map[int64]int64, len(processes))
+
+	for _, process := range processes {
+		processesCPU[process.PID] = process.CPUUsage
+
+		previous, ok := c.processesCPU[process.PID]
+		if ok && elapsed > 0 && process.CPUUsage >= previous {
+			cpuUsages[process.PID] = float64(process.CPUUsage-previous) / float64(elapsed.Nanoseconds()) * 100
+		}
+	}
+
+	c.processesCPU = processesCPU
+	c.processesTime = now
+
+	sortFuncs := map[sortType]func(i, j int) bool{
+		alphabetical: func(i, j int) bool {
+			if processes[i].Command != processes[j].Command {
+				return processes[i].Command < processes[j].Command
+			}
+
+			return processes[i].PID < processes[j].PID
+		},
+		cpuUsage: func(i, j int) bool {
+			return cpuUsages[processes[i].PID] > cpuUsages[processes[j].PID]
+		},
+		memoryUsage: func(i, j int) bool {
+			return processes[i].MemoryUsage > processes[j].MemoryUsage
+		},
+	}
+
+	sortFunc, ok := sortFuncs[sortingType]
+	if !ok {
+		sortFunc = func(i, j int) bool { return processes[i].PID < processes[j].PID }
+	}
+
+	sort.Slice(processes, sortFunc)
+
+	data := make([][]string, 0, len(processes))
+	for _, process := range processes {
+		command := strings.Join(process.CommandLine, " ")
+		if command == "" {
+			command = "[" + process.Command + "]"
+		}
+
+		data = append(data, []string{
+			strconv.FormatInt(process.PID, 10),
+			strconv.FormatInt(process.UID, 10),
+			process.State,
+			fmt.Sprintf("%.1f", cpuUsages[process.PID]),
+			units.GetByteSizeStringIEC(process.MemoryUsage, 2),
+			command,
+		})
+	}
+
+	headers := []string{
+		i18n.G("PID"),
+		i18n.G("UID"),
+		i18n.G("STATE"),
+		i18n.G("CPU %"),
+		i18n.G("MEMORY"),
+		i18n.G("COMMAND"),
+	}
+
+	fmt.Print("\033[H\033[2J") // Clear the terminal on each tick
+	err = cli.RenderTable(os.Stdout, c.flagFormat, headers, data, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(i18n.G("Press 'd' + ENTER to change delay"))
+	fmt.Println(i18n.G("Press 's' + ENTER to change sorting method"))
+	fmt.Println(i18n.G("Press CTRL-C to exit"))
+	fmt.Println()
+	fmt.Println(i18n.G("Delay:"), refreshInterval)
+	fmt.Println(i18n.G("Sorting Method:"), sortingType)
+
+	return nil
+}
+
 type sample struct {
 	labels map[string]string
 	value  float64
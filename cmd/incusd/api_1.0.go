@@ -42,6 +42,24 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	auditCmd,
+	authSSHCmd,
+	authTokenCmd,
+	authTokensCmd,
+	backupGroupCmd,
+	backupGroupExportCmd,
+	backupGroupsCmd,
+	backupTargetCmd,
+	backupTargetsCmd,
+	blueprintCmd,
+	blueprintsCmd,
+	certificateGroupCmd,
+	certificateGroupsCmd,
+	certificateTokenCmd,
+	certificateTokensCmd,
+	certificateRotateCmd,
+	certificatesExportCmd,
+	certificatesImportCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
@@ -50,9 +68,12 @@ var api10 = []APIEndpoint{
 	clusterNodeCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
+	clusterRebalanceCmd,
 	clusterCertificateCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
+	instanceBackupFilesCmd,
+	instanceBackupSecretCmd,
 	instanceBackupsCmd,
 	instanceCmd,
 	instanceConsoleCmd,
@@ -71,6 +92,8 @@ var api10 = []APIEndpoint{
 	instanceSnapshotsCmd,
 	instanceStateCmd,
 	instanceAccessCmd,
+	instanceCloudInitCmd,
+	instanceProcessesCmd,
 	instanceDebugMemoryCmd,
 	eventsCmd,
 	imageAliasCmd,
@@ -116,6 +139,7 @@ var api10 = []APIEndpoint{
 	projectAccessCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
+	storagePoolStateCmd,
 	storagePoolsCmd,
 	storagePoolBucketsCmd,
 	storagePoolBucketCmd,
@@ -133,7 +157,11 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeCustomBackupsCmd,
 	storagePoolVolumeTypeCustomBackupCmd,
 	storagePoolVolumeTypeCustomBackupExportCmd,
+	storagePoolVolumeTypeCustomBackupFilesCmd,
+	storagePoolVolumeTypeCustomBackupSecretCmd,
 	storagePoolVolumeTypeStateCmd,
+	trashEntriesCmd,
+	trashEntryCmd,
 	warningsCmd,
 	warningCmd,
 	metricsCmd,
@@ -236,6 +264,11 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		authMethods = append(authMethods, api.AuthenticationMethodOIDC)
 	}
 
+	// Kerberos may be disabled on this member if the keytab is missing.
+	if d.kerberosVerifier != nil {
+		authMethods = append(authMethods, api.AuthenticationMethodKerberos)
+	}
+
 	srv := api.ServerUntrusted{
 		APIExtensions: version.APIExtensions[:d.apiExtensions],
 		APIStatus:     "stable",
@@ -706,7 +739,17 @@ func doApi10Update(d *Daemon, r *http.Request, req api.ServerPut, patch bool) re
 			clusterChanged, err = newClusterConfig.Replace(req.Config)
 		}
 
-		return err
+		if err != nil {
+			return err
+		}
+
+		// ACME replaces the server key on disk, which would defeat a key held in a PKCS#11 token or TPM.
+		domain, _, _, _, _ := newClusterConfig.ACME()
+		if !s.ServerClustered && domain != "" && newNodeConfig.ServerKeyURI() != "" {
+			return api.StatusErrorf(http.StatusBadRequest, "ACME can't be used together with %q", "core.server_key_uri")
+		}
+
+		return nil
 	})
 	if err != nil {
 		var errorList *config.ErrorList
@@ -792,8 +835,10 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	s := d.State()
 
 	acmeChanged := false
+	auditChanged := false
 	bgpChanged := false
 	dnsChanged := false
+	kerberosChanged := false
 	oidcChanged := false
 	openFGAChanged := false
 	ovnChanged := false
@@ -817,6 +862,9 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			d.gateway.HeartbeatOfflineThreshold = clusterConfig.OfflineThreshold()
 			d.taskClusterHeartbeat.Reset()
 
+		case "core.audit_file", "core.audit_syslog":
+			auditChanged = true
+
 		case "core.bgp_asn":
 			bgpChanged = true
 
@@ -835,13 +883,16 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 			// Notify the logging mechanism about changes to the deprecated keys for backward compatibility.
 			loggingChanges["loki"] = struct{}{}
 
+		case "kerberos.keytab", "kerberos.realms":
+			kerberosChanged = true
+
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
 		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
 			oidcChanged = true
 
-		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
+		case "openfga.api.url", "openfga.api.token", "openfga.store.id", "openfga.ldap.url", "openfga.ldap.bind_dn", "openfga.ldap.bind_password", "openfga.ldap.base_dn", "openfga.ldap.group_filter", "openfga.ldap.group_attribute", "openfga.ldap.user_attribute", "openfga.ldap.sync_interval":
 			openFGAChanged = true
 
 		case "storage.linstor.controller_connection", "storage.linstor.ca_cert", "storage.linstor.client_cert", "storage.linstor.client_key":
@@ -984,6 +1035,20 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if auditChanged {
+		err := d.audit.Configure(clusterConfig.AuditSinks())
+		if err != nil {
+			return err
+		}
+	}
+
+	if kerberosChanged {
+		err := d.setupKerberos(clusterConfig.Kerberos())
+		if err != nil {
+			return fmt.Errorf("Failed creating Kerberos verifier: %w", err)
+		}
+	}
+
 	if openFGAChanged {
 		openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 		err := d.setupOpenFGA(openfgaAPIURL, openfgaAPIToken, openfgaStoreID)
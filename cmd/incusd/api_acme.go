@@ -108,6 +108,12 @@ func autoRenewCertificate(ctx context.Context, d *Daemon, force bool) error {
 		return nil
 	}
 
+	// The server key is held in a PKCS#11 token or TPM and can't be replaced.
+	if !s.ServerClustered && s.LocalConfig.ServerKeyURI() != "" {
+		logger.Warn("Skipping automatic server certificate renewal as the server key is held externally", logger.Ctx{"uri": s.LocalConfig.ServerKeyURI()})
+		return nil
+	}
+
 	// If we are clustered, let the leader handle the certificate renewal.
 	if s.ServerClustered {
 		leader, err := s.Cluster.LeaderAddress()
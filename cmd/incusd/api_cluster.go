@@ -485,12 +485,12 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 	// Client parameters to connect to the target cluster node.
 	serverCert := s.ServerCert()
 	args := &incus.ConnectionArgs{
-		TLSClientCert: string(serverCert.PublicKey()),
-		TLSClientKey:  string(serverCert.PrivateKey()),
 		TLSServerCert: string(req.ClusterCertificate),
 		UserAgent:     version.UserAgent,
 	}
 
+	internalUtil.SetClientCertificate(args, serverCert)
+
 	// Asynchronously join the cluster.
 	run := func(op *operations.Operation) error {
 		logger.Debug("Running cluster join operation")
@@ -849,7 +849,7 @@ func clusterPutDisable(d *Daemon, r *http.Request, req api.ClusterPut) response.
 		}
 	}
 
-	networkCert, err := internalUtil.LoadCert(s.OS.VarDir)
+	networkCert, err := internalUtil.LoadCert(s.OS.VarDir, s.LocalConfig.ServerKeyURI())
 	if err != nil {
 		return response.InternalError(fmt.Errorf("Failed to parse member certificate: %w", err))
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
 	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
@@ -36,6 +37,7 @@ import (
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type (
@@ -196,6 +198,11 @@ func evacuateInstancesFunc(ctx context.Context, inst instance.Instance, opts eva
 	// Apply overrides.
 	if opts.mode != "" {
 		if opts.mode == "heal" {
+			// Only heal the instances which opted in.
+			if !util.IsTrue(inst.ExpandedConfig()["cluster.healing"]) {
+				return nil
+			}
+
 			// Source server is dead, live-migration isn't an option.
 			if action == "live-migrate" {
 				action = "migrate"
@@ -572,6 +579,17 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		return nil, nil, err
 	}
 
+	// Rank the candidate members according to the placement policy.
+	placementReq, err := instancePlacementRequest(inst.Project().Name, inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative(), api.InstanceType(inst.Type().String()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting resource usage for instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+	}
+
+	candidateMembers, err = instancePlacementSort(ctx, s, candidateMembers, placementReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Run instance placement scriptlet if enabled.
 	if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 		leaderAddress, err := s.Cluster.LeaderAddress()
@@ -612,7 +630,7 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		cancel()
 	}
 
-	// If target member not specified yet, then find the least loaded cluster member which
+	// If target member not specified yet, then use the best ranked cluster member which
 	// supports the instance's architecture.
 	if targetMemberInfo == nil && len(candidateMembers) > 0 {
 		targetMemberInfo = &candidateMembers[0]
@@ -622,6 +640,8 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		return nil, nil, errors.New("Couldn't find a cluster member for the instance")
 	}
 
+	instancePlacementReserve(targetMemberInfo.Name, placementReq)
+
 	return sourceMemberInfo, targetMemberInfo, nil
 }
 
@@ -750,6 +770,18 @@ func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 			return nil
 		}
 
+		// Make sure the offline server can't access the instance volume anymore.
+		// Instances on storage drivers which don't support fencing are left alone.
+		err = pool.FenceInstance(inst, sourceMemberInfo.Name)
+		if err != nil {
+			if errors.Is(err, storageDrivers.ErrNotSupported) {
+				err = fmt.Errorf("Storage driver %q doesn't support fencing", pool.Driver().Info().Name)
+			}
+
+			logger.Warn("Not healing instance which couldn't be fenced", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "server": name, "err": err})
+			return nil
+		}
+
 		// Migrate the instance.
 		req := api.InstancePost{
 			Migration: true,
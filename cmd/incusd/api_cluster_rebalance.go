@@ -2,20 +2,28 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
@@ -389,3 +397,461 @@ func autoRebalanceClusterTask(d *Daemon) (task.Func, task.Schedule) {
 
 	return f, task.Every(time.Minute)
 }
+
+var clusterRebalanceCmd = APIEndpoint{
+	Path: "cluster/rebalance",
+
+	Post: APIEndpointAction{Handler: clusterRebalancePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// rebalanceInstance represents an instance which may be moved by a cluster rebalance.
+type rebalanceInstance struct {
+	Project  string
+	Name     string
+	Location string
+	Running  bool
+	Request  *placementRequest
+
+	// Cluster members the instance may be moved to.
+	Candidates []string
+}
+
+// swagger:operation POST /1.0/cluster/rebalance cluster cluster_rebalance_post
+//
+//	Rebalance the cluster
+//
+//	Computes a plan redistributing the instances across the cluster members according to the
+//	placement policy and executes it, live-migrating running instances.
+//
+//	When `dry_run` is set, the plan is returned without moving any instance.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: rebalance
+//	    description: Rebalance request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterRebalancePost"
+//	responses:
+//	  "200":
+//	    description: Rebalance plan (dry run)
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalancePlan"
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	// Parse the request.
+	req := api.ClusterRebalancePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	plan, err := clusterRebalancePlan(r.Context(), s, req.Group)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if req.DryRun {
+		return response.SyncResponse(true, plan)
+	}
+
+	run := func(op *operations.Operation) error {
+		return clusterRebalanceExecute(context.Background(), s, op, plan)
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, map[string]any{"moves": plan.Moves}, run, nil, nil, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// clusterRebalanceMembers returns the members taking part in the rebalance along with the instances they host.
+func clusterRebalanceMembers(ctx context.Context, s *state.State, group string) ([]db.NodeInfo, []*rebalanceInstance, map[string]map[string]int, error) {
+	var members []db.NodeInfo
+	var instances []*rebalanceInstance
+
+	// Count of instances per member and per project anti-affinity group.
+	antiAffinity := map[string]map[string]int{}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		// Restrict to the requested cluster group.
+		if group != "" {
+			_, err = dbCluster.GetClusterGroup(ctx, tx.Tx(), group)
+			if err != nil {
+				return fmt.Errorf("Failed loading cluster group %q: %w", group, err)
+			}
+
+			groupMembers := make([]db.NodeInfo, 0, len(allMembers))
+			for _, member := range allMembers {
+				if slices.Contains(member.Groups, group) {
+					groupMembers = append(groupMembers, member)
+				}
+			}
+
+			allMembers = groupMembers
+		}
+
+		members, err = tx.GetCandidateMembers(ctx, allMembers, nil, group, nil, s.GlobalConfig.OfflineThreshold())
+		if err != nil {
+			return fmt.Errorf("Failed getting online cluster members: %w", err)
+		}
+
+		// Cache the candidate members per architecture, cluster group and project restrictions.
+		candidatesCache := map[string][]string{}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			config := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+
+			antiAffinityGroup := config["cluster.anti_affinity"]
+			if antiAffinityGroup != "" {
+				if antiAffinity[inst.Node] == nil {
+					antiAffinity[inst.Node] = map[string]int{}
+				}
+
+				antiAffinity[inst.Node][inst.Project+"/"+antiAffinityGroup]++
+			}
+
+			// Only consider instances currently placed on a member taking part in the rebalance.
+			if !slices.ContainsFunc(members, func(member db.NodeInfo) bool { return member.Name == inst.Node }) {
+				return nil
+			}
+
+			instGroup := inst.Config["volatile.cluster.group"]
+			if group != "" && instGroup != "" && instGroup != group {
+				return nil
+			}
+
+			allowedGroups := project.GetRestrictedClusterGroups(&p)
+			cacheKey := fmt.Sprintf("%d/%s/%s", inst.Architecture, instGroup, strings.Join(allowedGroups, ","))
+
+			candidates, ok := candidatesCache[cacheKey]
+			if !ok {
+				candidateMembers, err := tx.GetCandidateMembers(ctx, members, []int{inst.Architecture}, instGroup, allowedGroups, s.GlobalConfig.OfflineThreshold())
+				if err != nil {
+					return err
+				}
+
+				candidates = make([]string, 0, len(candidateMembers))
+				for _, member := range candidateMembers {
+					candidates = append(candidates, member.Name)
+				}
+
+				candidatesCache[cacheKey] = candidates
+			}
+
+			devices := db.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+			placementReq, err := instancePlacementRequest(inst.Project, config, devices.CloneNative(), api.InstanceType(inst.Type.String()))
+			if err != nil {
+				logger.Warn("Skipping instance during cluster rebalance", logger.Ctx{"project": inst.Project, "instance": inst.Name, "err": err})
+				return nil
+			}
+
+			// Don't consider the storage pool usage when rebalancing.
+			placementReq.Pool = ""
+
+			instances = append(instances, &rebalanceInstance{
+				Project:    inst.Project,
+				Name:       inst.Name,
+				Location:   inst.Node,
+				Running:    config["volatile.last_state.power"] == instance.PowerStateRunning,
+				Request:    placementReq,
+				Candidates: candidates,
+			})
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Filter the instances which can't be moved.
+	movable := make([]*rebalanceInstance, 0, len(instances))
+	for _, entry := range instances {
+		inst, err := instance.LoadByProjectAndName(s, entry.Project, entry.Name)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed loading instance %q in project %q: %w", entry.Name, entry.Project, err)
+		}
+
+		migration := inst.CanMigrate()
+		if entry.Running && migration != "live-migrate" {
+			// Running instances are only moved through live migration.
+			continue
+		}
+
+		if !entry.Running && migration != "live-migrate" && migration != "migrate" {
+			continue
+		}
+
+		movable = append(movable, entry)
+	}
+
+	return members, movable, antiAffinity, nil
+}
+
+// clusterRebalanceUpdate updates the placement data of a member as if an instance was added (sign 1) or removed (sign -1).
+func clusterRebalanceUpdate(member *placementMember, inst *rebalanceInstance, sign int64) {
+	member.Instances += int(sign)
+	member.ReservedCPU += sign * inst.Request.CPU
+	member.ReservedMemory += sign * inst.Request.Memory
+
+	// Only running instances affect the live usage.
+	if member.Resources != nil && inst.Running {
+		member.Resources.Load.Average1Min = max(0, member.Resources.Load.Average1Min+float64(sign*inst.Request.CPU))
+		member.Resources.Memory.Used = uint64(max(0, int64(member.Resources.Memory.Used)+sign*inst.Request.Memory))
+	}
+}
+
+// clusterRebalancePlan computes the instance moves needed to balance the load across the cluster members.
+func clusterRebalancePlan(ctx context.Context, s *state.State, group string) (*api.ClusterRebalancePlan, error) {
+	plan := &api.ClusterRebalancePlan{Moves: []api.ClusterRebalanceMove{}}
+
+	policyName := s.GlobalConfig.InstancesPlacementPolicy()
+	policy, ok := placementPolicies[policyName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown instance placement policy %q", policyName)
+	}
+
+	nodes, instances, antiAffinity, err := clusterRebalanceMembers(ctx, s, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) < 2 || len(instances) == 0 {
+		return plan, nil
+	}
+
+	allMembers, err := instancePlacementMembers(ctx, s, nodes, &placementRequest{}, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the members whose resources couldn't be retrieved.
+	members := make(map[string]*placementMember, len(allMembers))
+	for _, member := range allMembers {
+		if policy.live && member.Resources == nil {
+			continue
+		}
+
+		members[member.NodeInfo.Name] = member
+	}
+
+	score := func(member *placementMember) int64 {
+		return policy.score(member, &placementRequest{})
+	}
+
+	plan.Moves = clusterRebalanceMoves(members, instances, antiAffinity, score)
+
+	return plan, nil
+}
+
+// clusterRebalanceMoves selects the instance moves balancing the score of the members.
+// Moves are selected greedily, each of them reducing the score of the most loaded member without making
+// the target member more loaded than the source was. The members, instances and anti-affinity counts are
+// updated as the moves are selected.
+func clusterRebalanceMoves(members map[string]*placementMember, instances []*rebalanceInstance, antiAffinity map[string]map[string]int, score func(member *placementMember) int64) []api.ClusterRebalanceMove {
+	moves := []api.ClusterRebalanceMove{}
+
+	moved := map[*rebalanceInstance]bool{}
+	for len(moved) < len(instances) {
+		// Go through the members from the most to the least loaded.
+		sources := make([]*placementMember, 0, len(members))
+		for _, member := range members {
+			sources = append(sources, member)
+		}
+
+		sort.Slice(sources, func(i int, j int) bool {
+			iScore := score(sources[i])
+			jScore := score(sources[j])
+			if iScore != jScore {
+				return iScore > jScore
+			}
+
+			return sources[i].NodeInfo.Name < sources[j].NodeInfo.Name
+		})
+
+		var bestInst *rebalanceInstance
+		var bestTarget *placementMember
+		var bestPeak int64
+		var bestTargetScore int64
+
+		for _, source := range sources {
+			sourceScore := score(source)
+
+			for _, inst := range instances {
+				if moved[inst] || inst.Location != source.NodeInfo.Name {
+					continue
+				}
+
+				antiAffinityKey := ""
+				if inst.Request.AntiAffinity != "" {
+					antiAffinityKey = inst.Project + "/" + inst.Request.AntiAffinity
+				}
+
+				for _, candidate := range inst.Candidates {
+					target, ok := members[candidate]
+					if !ok || target == source {
+						continue
+					}
+
+					// Never move an instance next to another one of its anti-affinity group.
+					if antiAffinityKey != "" && antiAffinity[candidate][antiAffinityKey] > 0 {
+						continue
+					}
+
+					clusterRebalanceUpdate(source, inst, -1)
+					clusterRebalanceUpdate(target, inst, 1)
+					targetScore := score(target)
+					peak := max(score(source), targetScore)
+					clusterRebalanceUpdate(source, inst, 1)
+					clusterRebalanceUpdate(target, inst, -1)
+
+					if peak >= sourceScore {
+						continue
+					}
+
+					// Prefer the move with the lowest peak, then the least loaded target to avoid needless moves.
+					if bestInst == nil || peak < bestPeak || (peak == bestPeak && targetScore < bestTargetScore) {
+						bestInst = inst
+						bestTarget = target
+						bestPeak = peak
+						bestTargetScore = targetScore
+					}
+				}
+			}
+
+			// Only move instances away from the most loaded member that can be improved.
+			if bestInst != nil {
+				break
+			}
+		}
+
+		if bestInst == nil {
+			break
+		}
+
+		// Record the move.
+		source := members[bestInst.Location]
+		clusterRebalanceUpdate(source, bestInst, -1)
+		clusterRebalanceUpdate(bestTarget, bestInst, 1)
+
+		if bestInst.Request.AntiAffinity != "" {
+			antiAffinityKey := bestInst.Project + "/" + bestInst.Request.AntiAffinity
+			antiAffinity[source.NodeInfo.Name][antiAffinityKey]--
+
+			if antiAffinity[bestTarget.NodeInfo.Name] == nil {
+				antiAffinity[bestTarget.NodeInfo.Name] = map[string]int{}
+			}
+
+			antiAffinity[bestTarget.NodeInfo.Name][antiAffinityKey]++
+		}
+
+		moves = append(moves, api.ClusterRebalanceMove{
+			Project:  bestInst.Project,
+			Instance: bestInst.Name,
+			Source:   source.NodeInfo.Name,
+			Target:   bestTarget.NodeInfo.Name,
+			Live:     bestInst.Running,
+		})
+
+		bestInst.Location = bestTarget.NodeInfo.Name
+		moved[bestInst] = true
+	}
+
+	return moves
+}
+
+// clusterRebalanceExecute performs the moves of a rebalance plan.
+func clusterRebalanceExecute(ctx context.Context, s *state.State, op *operations.Operation, plan *api.ClusterRebalancePlan) error {
+	// The moves invalidate the cached reservations used for placement.
+	defer instancePlacementReservedReset()
+
+	metadata := map[string]any{"moves": plan.Moves}
+
+	for i, move := range plan.Moves {
+		metadata["rebalance_progress"] = fmt.Sprintf("Moving %q in project %q to %q (%d/%d)", move.Instance, move.Project, move.Target, i+1, len(plan.Moves))
+		_ = op.UpdateMetadata(metadata)
+
+		var sourceMember db.NodeInfo
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+
+			sourceMember, err = tx.GetNodeByName(ctx, move.Source)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("Failed loading cluster member %q: %w", move.Source, err)
+		}
+
+		client, err := cluster.Connect(sourceMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return fmt.Errorf("Failed to connect to cluster member %q: %w", move.Source, err)
+		}
+
+		client = client.UseProject(move.Project).UseTarget(move.Target)
+
+		migrationOp, err := client.MigrateInstance(move.Instance, api.InstancePost{Migration: true, Live: move.Live})
+		if err != nil {
+			return fmt.Errorf("Failed to migrate instance %q in project %q: %w", move.Instance, move.Project, err)
+		}
+
+		err = migrationOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed to migrate instance %q in project %q: %w", move.Instance, move.Project, err)
+		}
+
+		// Record the migration in the instance volatile storage.
+		inst, err := instance.LoadByProjectAndName(s, move.Project, move.Instance)
+		if err != nil {
+			return err
+		}
+
+		err = inst.VolatileSet(map[string]string{"volatile.rebalance.last_move": strconv.FormatInt(time.Now().Unix(), 10)})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestClusterRebalanceMoves(t *testing.T) {
+	// newMembers returns members hosting the given number of instances.
+	newMembers := func(counts map[string]int) map[string]*placementMember {
+		members := map[string]*placementMember{}
+		for name, count := range counts {
+			members[name] = &placementMember{NodeInfo: db.NodeInfo{Name: name}, Instances: count}
+		}
+
+		return members
+	}
+
+	// newInstances returns the given number of instances located on the member.
+	newInstances := func(location string, count int, running bool, antiAffinity string, candidates ...string) []*rebalanceInstance {
+		instances := make([]*rebalanceInstance, 0, count)
+		for i := range count {
+			instances = append(instances, &rebalanceInstance{
+				Project:    api.ProjectDefaultName,
+				Name:       fmt.Sprintf("%s-c%d", location, i),
+				Location:   location,
+				Running:    running,
+				Request:    &placementRequest{Project: api.ProjectDefaultName, AntiAffinity: antiAffinity},
+				Candidates: candidates,
+			})
+		}
+
+		return instances
+	}
+
+	score := func(member *placementMember) int64 {
+		return placementPolicies["instance_count"].score(member, &placementRequest{})
+	}
+
+	tests := []struct {
+		name           string
+		members        map[string]*placementMember
+		instances      []*rebalanceInstance
+		antiAffinity   map[string]map[string]int
+		expectedMoves  []api.ClusterRebalanceMove
+		expectedCounts map[string]int
+	}{
+		{
+			name:           "Balanced",
+			members:        newMembers(map[string]int{"node1": 1, "node2": 1}),
+			instances:      append(newInstances("node1", 1, true, "", "node1", "node2"), newInstances("node2", 1, true, "", "node1", "node2")...),
+			expectedMoves:  []api.ClusterRebalanceMove{},
+			expectedCounts: map[string]int{"node1": 1, "node2": 1},
+		},
+		{
+			name:      "Spread over the least loaded members",
+			members:   newMembers(map[string]int{"node1": 4, "node2": 0, "node3": 0}),
+			instances: newInstances("node1", 4, true, "", "node1", "node2", "node3"),
+			expectedMoves: []api.ClusterRebalanceMove{
+				{Project: "default", Instance: "node1-c0", Source: "node1", Target: "node2", Live: true},
+				{Project: "default", Instance: "node1-c1", Source: "node1", Target: "node3", Live: true},
+			},
+			expectedCounts: map[string]int{"node1": 2, "node2": 1, "node3": 1},
+		},
+		{
+			name:      "Stopped instances are moved",
+			members:   newMembers(map[string]int{"node1": 2, "node2": 0}),
+			instances: newInstances("node1", 2, false, "", "node1", "node2"),
+			expectedMoves: []api.ClusterRebalanceMove{
+				{Project: "default", Instance: "node1-c0", Source: "node1", Target: "node2", Live: false},
+			},
+			expectedCounts: map[string]int{"node1": 1, "node2": 1},
+		},
+		{
+			name:           "Restricted candidates",
+			members:        newMembers(map[string]int{"node1": 3, "node2": 0}),
+			instances:      newInstances("node1", 3, true, "", "node1"),
+			expectedMoves:  []api.ClusterRebalanceMove{},
+			expectedCounts: map[string]int{"node1": 3, "node2": 0},
+		},
+		{
+			name:           "Unavailable candidates",
+			members:        newMembers(map[string]int{"node1": 3, "node2": 0}),
+			instances:      newInstances("node1", 3, true, "", "node1", "node3"),
+			expectedMoves:  []api.ClusterRebalanceMove{},
+			expectedCounts: map[string]int{"node1": 3, "node2": 0},
+		},
+		{
+			name:      "Anti-affinity",
+			members:   newMembers(map[string]int{"node1": 4, "node2": 1, "node3": 0}),
+			instances: append(newInstances("node1", 4, true, "web", "node1", "node2", "node3"), newInstances("node2", 1, true, "web", "node1", "node2", "node3")...),
+			antiAffinity: map[string]map[string]int{
+				"node1": {"default/web": 4},
+				"node2": {"default/web": 1},
+			},
+			expectedMoves: []api.ClusterRebalanceMove{
+				{Project: "default", Instance: "node1-c0", Source: "node1", Target: "node3", Live: true},
+			},
+			expectedCounts: map[string]int{"node1": 3, "node2": 1, "node3": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			antiAffinity := tt.antiAffinity
+			if antiAffinity == nil {
+				antiAffinity = map[string]map[string]int{}
+			}
+
+			moves := clusterRebalanceMoves(tt.members, tt.instances, antiAffinity, score)
+			assert.Equal(t, tt.expectedMoves, moves)
+
+			counts := map[string]int{}
+			for name, member := range tt.members {
+				counts[name] = member.Instances
+			}
+
+			assert.Equal(t, tt.expectedCounts, counts)
+		})
+	}
+}
@@ -62,6 +62,7 @@ var apiInternal = []APIEndpoint{
 	internalGarbageCollectorCmd,
 	internalImageOptimizeCmd,
 	internalImageRefreshCmd,
+	internalInstanceOnReloadCmd,
 	internalRAFTSnapshotCmd,
 	internalRebalanceLoadCmd,
 	internalReadyCmd,
@@ -153,6 +154,13 @@ var internalContainerOnStopCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: internalContainerOnStop, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
 }
 
+// Instance hooks.
+var internalInstanceOnReloadCmd = APIEndpoint{
+	Path: "instances/{instanceRef}/onreload",
+
+	Get: APIEndpointAction{Handler: internalInstanceOnReload, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
 // Virtual machine hooks.
 var internalVirtualMachineOnResizeCmd = APIEndpoint{
 	Path: "virtual-machines/{instanceRef}/onresize",
@@ -501,6 +509,43 @@ func internalVirtualMachineOnResize(d *Daemon, r *http.Request) response.Respons
 	return response.EmptySyncResponse
 }
 
+// internalInstanceOnReload reloads devices of a running instance, used to apply changes made to the volumes they use
+// from another cluster member.
+func internalInstanceOnReload(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the instance ID.
+	instanceID, err := strconv.Atoi(mux.Vars(r)["instanceRef"])
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get the devices list.
+	devices := request.QueryParam(r, "devices")
+	if devices == "" {
+		return response.BadRequest(errors.New("Reload hook requires a list of devices"))
+	}
+
+	// Load by ID.
+	inst, err := instance.LoadByID(s, instanceID)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !inst.IsRunning() {
+		return response.EmptySyncResponse
+	}
+
+	for _, devName := range strings.Split(devices, ",") {
+		err = inst.ReloadDevice(devName)
+		if err != nil {
+			return response.InternalError(fmt.Errorf("Failed reloading device %q: %w", devName, err))
+		}
+	}
+
+	return response.EmptySyncResponse
+}
+
 // Perform a database dump.
 func internalSQLGet(d *Daemon, r *http.Request) response.Response {
 	s := d.State()
@@ -23,9 +23,12 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	internalutil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 )
 
 type metricsCacheEntry struct {
@@ -58,7 +61,7 @@ func allowMetrics(d *Daemon, r *http.Request) response.Response {
 //
 //	Get metrics
 //
-//	Gets metrics of instances.
+//	Gets metrics of instances and custom storage volumes.
 //
 //	---
 //	produces:
@@ -262,6 +265,15 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	wg.Wait()
 	close(instMetricsCh)
 
+	// Add the custom storage volume metrics.
+	for projectName, volumeMetrics := range storageVolumeMetrics(r.Context(), s, projectsToFetch) {
+		if newMetrics[projectName] == nil {
+			newMetrics[projectName] = metrics.NewMetricSet(nil)
+		}
+
+		newMetrics[projectName].Merge(volumeMetrics)
+	}
+
 	// Put the new data in the global cache and in response.
 	metricsCacheLock.Lock()
 
@@ -295,6 +307,93 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 	return getFilteredMetrics(s, r, compress, metricSet)
 }
 
+// storageVolumeMetrics returns the usage, quota and I/O metrics of the custom storage volumes on this server, indexed
+// by project name.
+func storageVolumeMetrics(ctx context.Context, s *state.State, projectFilters []dbCluster.InstanceFilter) map[string]*metrics.MetricSet {
+	projectNames := make([]string, 0, len(projectFilters))
+	for _, filter := range projectFilters {
+		projectNames = append(projectNames, *filter.Project)
+	}
+
+	var volumes []db.StorageVolumeArgs
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		volumes, err = tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+
+		return err
+	})
+	if err != nil {
+		logger.Warn("Failed getting custom storage volumes", logger.Ctx{"err": err})
+		return nil
+	}
+
+	out := map[string]*metrics.MetricSet{}
+	pools := map[string]storagePools.Pool{}
+
+	for _, vol := range volumes {
+		if !slices.Contains(projectNames, vol.ProjectName) {
+			continue
+		}
+
+		pool, ok := pools[vol.PoolName]
+		if !ok {
+			pool, err = storagePools.LoadByName(s, vol.PoolName)
+			if err != nil {
+				logger.Warn("Failed loading storage pool", logger.Ctx{"pool": vol.PoolName, "err": err})
+			} else if pool.Status() == api.StoragePoolStatusPending || pool.LocalStatus() == api.StoragePoolStatusUnvailable {
+				pool = nil
+			}
+
+			pools[vol.PoolName] = pool
+		}
+
+		// Skip volumes on pools which can't be queried.
+		if pool == nil {
+			continue
+		}
+
+		labels := map[string]string{"project": vol.ProjectName, "pool": vol.PoolName, "volume": vol.Name}
+		if s.ServerClustered && !pool.Driver().Info().Remote {
+			labels["location"] = s.ServerName
+		}
+
+		volumeMetrics := metrics.NewMetricSet(labels)
+
+		usage, err := pool.GetCustomVolumeUsage(vol.ProjectName, vol.Name)
+		if err == nil {
+			volumeMetrics.AddSamples(metrics.StorageVolumeUsedBytes, metrics.Sample{Value: float64(usage.Used)})
+		} else if !errors.Is(err, storageDrivers.ErrNotSupported) {
+			logger.Warn("Failed getting storage volume usage", logger.Ctx{"volume": vol.Name, "pool": vol.PoolName, "project": vol.ProjectName, "err": err})
+		}
+
+		if vol.Config["size"] != "" {
+			size, err := units.ParseByteSizeString(vol.Config["size"])
+			if err == nil && size > 0 {
+				volumeMetrics.AddSamples(metrics.StorageVolumeQuotaBytes, metrics.Sample{Value: float64(size)})
+			}
+		}
+
+		ioStats, err := pool.GetCustomVolumeIOStats(vol.ProjectName, vol.Name)
+		if err == nil && ioStats != nil {
+			volumeMetrics.AddSamples(metrics.StorageVolumeReadBytesTotal, metrics.Sample{Value: float64(ioStats.ReadBytes)})
+			volumeMetrics.AddSamples(metrics.StorageVolumeReadsCompletedTotal, metrics.Sample{Value: float64(ioStats.ReadsCompleted)})
+			volumeMetrics.AddSamples(metrics.StorageVolumeWrittenBytesTotal, metrics.Sample{Value: float64(ioStats.WrittenBytes)})
+			volumeMetrics.AddSamples(metrics.StorageVolumeWritesCompletedTotal, metrics.Sample{Value: float64(ioStats.WritesCompleted)})
+		} else if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
+			logger.Warn("Failed getting storage volume I/O counters", logger.Ctx{"volume": vol.Name, "pool": vol.PoolName, "project": vol.ProjectName, "err": err})
+		}
+
+		if out[vol.ProjectName] == nil {
+			out[vol.ProjectName] = metrics.NewMetricSet(nil)
+		}
+
+		out[vol.ProjectName].Merge(volumeMetrics)
+	}
+
+	return out
+}
+
 func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSet *metrics.MetricSet) response.Response {
 	if !s.GlobalConfig.MetricsAuthentication() {
 		return response.SyncResponsePlain(true, compress, metricSet.String())
@@ -311,7 +410,19 @@ func getFilteredMetrics(s *state.State, r *http.Request, compress bool, metricSe
 		}
 	}
 
-	metricSet.FilterSamples(userHasPermission)
+	// Get storage volumes the user is allowed to view.
+	userHasVolumePermission, err := s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanView, auth.ObjectTypeStorageVolume)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	metricSet.FilterSamples(func(object auth.Object) bool {
+		if object.Type() == auth.ObjectTypeStorageVolume {
+			return userHasVolumePermission(object)
+		}
+
+		return userHasPermission(object)
+	})
 
 	return response.SyncResponsePlain(true, compress, metricSet.String())
 }
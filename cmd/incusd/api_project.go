@@ -973,6 +973,15 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			if !empty {
 				return errors.New("Only empty projects can be removed.")
 			}
+
+			trashEntries, err := tx.GetProjectTrashEntries(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			if len(trashEntries) > 0 {
+				return errors.New("Projects with entries in the trash can't be removed.")
+			}
 		} else {
 			usedBy, err = projectUsedBy(ctx, tx, project)
 			if err != nil {
@@ -1231,6 +1240,24 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			count--
 		}
 
+		// Purge the trash entries, including those of the instances and volumes deleted above.
+		var trashEntries []db.TrashEntry
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			trashEntries, err = tx.GetProjectTrashEntries(ctx, name)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		for _, entry := range trashEntries {
+			err := target.DeleteTrashEntry(entry.UUID)
+			if err != nil {
+				return response.InternalError(err)
+			}
+		}
+
 		// Check if anything is left.
 		if count != 0 {
 			return response.BadRequest(errors.New("Project couldn't be automatically emptied"))
@@ -1357,6 +1384,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Compression algorithm to use for backups
 		"backups.compression_algorithm": validate.IsCompressionAlgorithm,
 
+		// gendoc:generate(entity=project, group=specific, key=exec.capture)
+		// When enabled, the input and output of every command executed in the project's instances is recorded
+		// in the instance's `exec-output` logs and referenced in the `instance-exec-finished` lifecycle event.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to capture the input and output of exec sessions
+		"exec.capture": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=project, group=features, key=features.profiles)
 		//
 		// ---
@@ -1590,7 +1626,7 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 
 		// gendoc:generate(entity=project, group=restricted, key=restricted.containers.lowlevel)
 		// Possible values are `allow` or `block`.
-		// When set to `allow`, low-level container options like {config:option}`instance-raw:raw.lxc`, {config:option}`instance-raw:raw.idmap`, `volatile.*`, etc. can be used.
+		// When set to `allow`, low-level container options like {config:option}`instance-raw:raw.lxc`, {config:option}`instance-raw:raw.idmap`, `hooks.*`, `volatile.*`, etc. can be used.
 		// ---
 		//  type: string
 		//  defaultdesc: `block`
@@ -1611,7 +1647,7 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 
 		// gendoc:generate(entity=project, group=restricted, key=restricted.virtual-machines.lowlevel)
 		// Possible values are `allow` or `block`.
-		// When set to `allow`, low-level VM options like {config:option}`instance-raw:raw.qemu`, `volatile.*`, etc. can be used.
+		// When set to `allow`, low-level VM options like {config:option}`instance-raw:raw.qemu`, `hooks.*`, `volatile.*`, etc. can be used.
 		// ---
 		//  type: string
 		//  defaultdesc: `block`
@@ -1715,6 +1751,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Which `source` can be used for `disk` devices
 		"restricted.devices.disk.paths": validate.Optional(validate.IsListOf(validate.IsAbsFilePath)),
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.files.paths)
+		// Specify a comma-separated list of paths within the instances to which the file API and SFTP access is restricted.
+		// While set, symbolic links can neither be followed nor created through the file API and SFTP.
+		// If this option is left empty, the whole instance filesystem is accessible.
+		// ---
+		//  type: string
+		//  shortdesc: Which paths can be accessed through the file API and SFTP
+		"restricted.files.paths": validate.Optional(validate.IsListOf(validate.IsAbsFilePath)),
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.idmap.uid)
 		// This option specifies the host UID ranges that are allowed in the instance's {config:option}`instance-raw:raw.idmap` setting.
 		// ---
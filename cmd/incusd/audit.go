@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/server/audit"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// auditLogSize is the number of audit entries kept in memory.
+const auditLogSize = 10000
+
+var auditCmd = APIEndpoint{
+	Path: "audit",
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewPrivilegedEvents)},
+}
+
+// swagger:operation GET /1.0/audit server audit_get
+//
+//	Get the authentication audit log
+//
+//	Returns the most recent authentication decisions made by the server, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: success eq false
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Audit entries
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit entries
+//	          items:
+//	            $ref: "#/definitions/AuditEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	// Parse filter value.
+	clauses, err := filter.Parse(r.FormValue("filter"), filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	entries := s.Audit.Entries()
+	if clauses == nil || len(clauses.Clauses) == 0 {
+		return response.SyncResponse(true, entries)
+	}
+
+	results := make([]api.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		match, err := filter.Match(entry, *clauses)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if match {
+			results = append(results, entry)
+		}
+	}
+
+	return response.SyncResponse(true, results)
+}
+
+// auditRecord records an authentication decision for the request.
+// When the client isn't identified, the fingerprint of its TLS certificate is used instead.
+func auditRecord(auditLog *audit.Log, r *http.Request, entryType string, success bool, method string, identity string, reason string) {
+	// Internal cluster traffic isn't audited.
+	if method == "cluster" {
+		return
+	}
+
+	if identity == "" && r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		identity = localtls.CertFingerprint(r.TLS.PeerCertificates[0])
+	}
+
+	if method == "" && r.TLS != nil {
+		method = api.AuthenticationMethodTLS
+	}
+
+	auditLog.Record(api.AuditEntry{
+		Type:     entryType,
+		Success:  success,
+		Method:   method,
+		Identity: identity,
+		Address:  r.RemoteAddr,
+		Request:  fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+		Reason:   reason,
+	})
+}
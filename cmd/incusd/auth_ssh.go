@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var authSSHCmd = APIEndpoint{
+	Path: "auth/ssh",
+
+	Post: APIEndpointAction{Handler: authSSHPost, AllowUntrusted: true},
+}
+
+// swagger:operation POST /1.0/auth/ssh auth auth_ssh_post
+//
+//	Authenticate with an SSH key
+//
+//	Without a signature, issues a challenge to be signed by the trusted SSH key.
+//	With the signature of a previously issued challenge, returns a session token to be used as bearer token.
+//
+//	Challenges and sessions are only valid on the server which issued them, other cluster members reject them.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: request
+//	    description: SSH authentication request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthSSHPost"
+//	responses:
+//	  "200":
+//	    description: Challenge or session
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthSSHSession"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authSSHPost(d *Daemon, r *http.Request) response.Response {
+	req := api.AuthSSHPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.PublicKey))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid SSH public key: %w", err))
+	}
+
+	fingerprint := certificate.SSHFingerprint(key)
+	_, trusted := d.clientCerts.GetSSHKey(fingerprint)
+
+	// Issue a new challenge.
+	// Untrusted keys get one which can't be answered so that the response doesn't reveal whether a key is trusted.
+	if req.Signature == nil {
+		var challenge string
+		var expiresAt time.Time
+
+		if trusted {
+			challenge, expiresAt, err = d.sshSessions.NewChallenge(key)
+		} else {
+			challenge, expiresAt, err = d.sshSessions.NewDecoyChallenge()
+		}
+
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, api.AuthSSHChallenge{Challenge: challenge, ExpiresAt: expiresAt})
+	}
+
+	// Only trusted keys can get a session, all failures being reported the same way.
+	if !trusted {
+		err = errors.New("SSH public key isn't trusted")
+	} else {
+		signature := &ssh.Signature{Format: req.Signature.Format, Blob: req.Signature.Blob}
+
+		var token string
+		var expiresAt time.Time
+
+		token, expiresAt, err = d.sshSessions.NewSession(key, req.Challenge, signature)
+		if err == nil {
+			return response.SyncResponse(true, api.AuthSSHSession{Token: token, ExpiresAt: expiresAt})
+		}
+	}
+
+	logger.Warn("Rejected SSH authentication", logger.Ctx{"fingerprint": fingerprint, "remote": r.RemoteAddr, "err": err})
+	return response.Forbidden(errors.New("SSH authentication failed"))
+}
+
+// certificateSSHKeyAdd adds an SSH public key to the trust store.
+func certificateSSHKeyAdd(s *state.State, r *http.Request, req api.CertificatesPost) response.Response {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(req.Certificate))
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid SSH public key: %w", err))
+	}
+
+	err = certificateSSHKeyValidate(key)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	fingerprint := certificate.SSHFingerprint(key)
+
+	// Default to the key comment for the name.
+	name := req.Name
+	if name == "" {
+		name = comment
+	}
+
+	if name == "" {
+		return response.BadRequest(errors.New("No name provided for the SSH public key"))
+	}
+
+	if !isClusterNotification(r) {
+		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Check if we already have the key.
+			existingCert, _ := dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), fingerprint)
+			if existingCert != nil {
+				return api.StatusErrorf(http.StatusConflict, "SSH public key already in trust store")
+			}
+
+			dbCert := dbCluster.Certificate{
+				Fingerprint:     fingerprint,
+				Type:            certificate.TypeSSH,
+				Name:            name,
+				Certificate:     strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))),
+				Restricted:      req.Restricted,
+				Description:     req.Description,
+				Labels:          req.Labels,
+				Instances:       req.Instances,
+				AllowedNetworks: req.AllowedNetworks,
+			}
+
+			_, err := dbCluster.CreateCertificateWithProjects(ctx, tx.Tx(), dbCert, req.Projects)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the new key.
+		err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+			return client.CreateCertificate(api.CertificatesPost{
+				CertificatePut: api.CertificatePut{
+					Certificate: req.Certificate,
+					Name:        name,
+					Type:        api.CertificateTypeSSH,
+				},
+			})
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Add the certificate resource to the authorizer.
+		err = s.Authorizer.AddCertificate(r.Context(), fingerprint)
+		if err != nil {
+			logger.Error("Failed to add certificate to authorizer", logger.Ctx{"fingerprint": fingerprint, "error": err})
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.CertificateCreated.Event(fingerprint, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// certificateSSHKeyValidate checks that the SSH public key is of a supported type and strength.
+func certificateSSHKeyValidate(key ssh.PublicKey) error {
+	switch key.Type() {
+	case ssh.KeyAlgoED25519:
+		return nil
+	case ssh.KeyAlgoRSA:
+		cryptoKey, ok := key.(ssh.CryptoPublicKey)
+		if !ok {
+			return errors.New("Invalid RSA public key")
+		}
+
+		rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey)
+		if !ok || rsaKey.N.BitLen() < 2048 {
+			return errors.New("RSA public keys must be at least 2048 bits")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported SSH public key type %q", key.Type())
+}
@@ -0,0 +1,564 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var authTokensCmd = APIEndpoint{
+	Path: "auth/tokens",
+
+	Get:  APIEndpointAction{Handler: authTokensGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: authTokensPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authTokenCmd = APIEndpoint{
+	Path: "auth/tokens/{name}",
+
+	Get:    APIEndpointAction{Handler: authTokenGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: authTokenPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authTokenDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/auth/tokens auth auth_tokens_get
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/tokens/ci-runner",
+//	              "/1.0/auth/tokens/backup"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/tokens?recursion=1 auth auth_tokens_get_recursion1
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of API tokens
+//	          items:
+//	            $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var tokens []*api.AuthToken
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTokens, err := dbCluster.GetAuthTokens(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		tokens = make([]*api.AuthToken, 0, len(dbTokens))
+		for _, dbToken := range dbTokens {
+			if !recursion {
+				tokens = append(tokens, &api.AuthToken{Name: dbToken.Name})
+				continue
+			}
+
+			token, err := dbToken.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			tokens = append(tokens, token)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, tokens)
+	}
+
+	urls := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		urls = append(urls, token.URL(version.APIVersion).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/tokens auth auth_tokens_post
+//
+//	Issue an API token
+//
+//	Issues a new API token which can be sent as a bearer token in the
+//	Authorization header instead of using a TLS client certificate.
+//	The secret is only returned by this call.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: token
+//	    description: API token to issue
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokensPost"
+//	responses:
+//	  "200":
+//	    description: API token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthTokenSecret"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthTokensPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if isClusterNotification(r) {
+		// Reload the cache.
+		s.UpdateCertificateCache()
+
+		return response.EmptySyncResponse
+	}
+
+	// Quick checks.
+	err = authTokenValidate(req.Name, req.AuthTokenPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	secret, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		obj := dbCluster.AuthToken{
+			Name:         req.Name,
+			Description:  req.Description,
+			SecretHash:   authTokenHash(secret),
+			Restricted:   req.Restricted,
+			CreationDate: time.Now().UTC(),
+			ExpiryDate:   sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: !req.ExpiresAt.IsZero()},
+		}
+
+		tokenID, err := dbCluster.CreateAuthToken(ctx, tx.Tx(), obj)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateAuthTokenProjects(ctx, tx.Tx(), int(tokenID), req.Projects)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other nodes about the new token.
+	err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+		_, err := client.CreateAuthToken(api.AuthTokensPost{Name: req.Name})
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.AuthTokenCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, api.AuthTokenSecret{Name: req.Name, Secret: secret}, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/tokens/{name} auth auth_token_get
+//
+//	Get the API token
+//
+//	Gets a specific API token. The secret is never returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	token, err := authTokenLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, token, token.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/tokens/{name} auth auth_token_put
+//
+//	Update the API token
+//
+//	Updates the restrictions and expiry of the API token.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: token
+//	    description: API token configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokenPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		// Reload the cache.
+		s.UpdateCertificateCache()
+
+		return response.EmptySyncResponse
+	}
+
+	token, err := authTokenLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, token.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.AuthTokenPut{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authTokenValidate(name, req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbToken, err := dbCluster.GetAuthToken(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		obj := dbCluster.AuthToken{
+			Description: req.Description,
+			Restricted:  req.Restricted,
+			ExpiryDate:  sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: !req.ExpiresAt.IsZero()},
+		}
+
+		err = dbCluster.UpdateAuthToken(ctx, tx.Tx(), name, obj)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateAuthTokenProjects(ctx, tx.Tx(), dbToken.ID, req.Projects)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other nodes about the updated token.
+	err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+		return client.UpdateAuthToken(name, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/auth/tokens/{name} auth auth_token_delete
+//
+//	Revoke the API token
+//
+//	Removes the API token, immediately preventing its further use.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteAuthToken(ctx, tx.Tx(), name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the revoked token.
+		err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+			return client.DeleteAuthToken(name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// authTokenLoad returns the API representation of the API token with the given name.
+func authTokenLoad(ctx context.Context, s *state.State, name string) (*api.AuthToken, error) {
+	var token *api.AuthToken
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbToken, err := dbCluster.GetAuthToken(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		token, err = dbToken.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// authTokenValidate checks the name and configuration of an API token.
+func authTokenValidate(name string, token api.AuthTokenPut) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.ContainsAny(name, "/ '\"") {
+		return errors.New("API token names may not contain slashes, spaces or quotes")
+	}
+
+	if !token.ExpiresAt.IsZero() && token.ExpiresAt.Before(time.Now()) {
+		return errors.New("The expiry date must be in the future")
+	}
+
+	return nil
+}
+
+// authTokenHash returns the hash of an API token secret as stored in the database.
+func authTokenHash(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// authBearerFromRequest returns the bearer token of the request, if any.
+func authBearerFromRequest(r *http.Request) string {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return ""
+	}
+
+	return parts[1]
+}
+
+// authTokenFromRequest returns the API token used as bearer token in the request, if any.
+func authTokenFromRequest(r *http.Request, tokens *certificate.Cache) *certificate.Token {
+	bearer := authBearerFromRequest(r)
+	if bearer == "" {
+		return nil
+	}
+
+	token, ok := tokens.GetToken(authTokenHash(bearer))
+	if !ok || token.Expired() {
+		return nil
+	}
+
+	return token
+}
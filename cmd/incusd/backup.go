@@ -1,17 +1,37 @@
 package main
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/pkg/sftp"
 	"gopkg.in/yaml.v2"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/instancewriter"
+	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -21,10 +41,13 @@ import (
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 	"github.com/lxc/incus/v6/shared/ioprogress"
@@ -106,6 +129,14 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		}
 	}
 
+	// Detect the rate limit.
+	throttle, err := backupThrottle(s, args.Throttle)
+	if err != nil {
+		return err
+	}
+
+	b.SetThrottle(throttle)
+
 	// Create the target path if needed.
 	backupsPath := internalUtil.VarPath("backups", "instances", project.Instance(sourceInst.Project().Name, sourceInst.Name()))
 	if !util.PathExists(backupsPath) {
@@ -129,6 +160,9 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	defer func() { _ = tarFileWriter.Close() }()
 	reverter.Add(func() { _ = os.Remove(target) })
 
+	// Keep track of the checksum of the tarball so that its downloads can be verified.
+	hasher := sha256.New()
+
 	// Get IDMap to unshift container as the tarball is created.
 	var idmapSet *idmap.Set
 	if sourceInst.Type() == instancetype.Container {
@@ -143,6 +177,7 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
 	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, idmapSet)
+	tarReader := backup.NewThrottledReader(tarPipeReader, throttle)
 
 	// Setup tar writer go routine, with optional compression.
 	tarWriterRes := make(chan error)
@@ -166,17 +201,18 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 	go func(resCh chan<- error) {
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
+		backupProgressWriter.WriteCloser = tarFileWriter
+		tarOutput := io.MultiWriter(backupProgressWriter, hasher)
+
 		if compress != "none" {
-			backupProgressWriter.WriteCloser = tarFileWriter
-			compressErr = compressFile(compress, tarPipeReader, backupProgressWriter)
+			compressErr = compressFile(compress, tarReader, tarOutput)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
 			if compressErr != nil {
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			backupProgressWriter.WriteCloser = tarFileWriter
-			_, err = io.Copy(backupProgressWriter, tarPipeReader)
+			_, err = io.Copy(tarOutput, tarReader)
 		}
 
 		resCh <- err
@@ -223,6 +259,13 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateInstanceBackupChecksum(ctx, args.Name, hex.EncodeToString(hasher.Sum(nil)))
+	})
+	if err != nil {
+		return fmt.Errorf("Failed updating backup record: %w", err)
+	}
+
 	reverter.Success()
 	s.Events.SendLifecycle(sourceInst.Project().Name, lifecycle.InstanceBackupCreated.Event(args.Name, b.Instance(), nil))
 
@@ -230,6 +273,21 @@ func backupCreate(s *state.State, args db.InstanceBackup, sourceInst instance.In
 }
 
 // backupWriteIndex generates an index.yaml file and then writes it to the root of the backup tarball.
+// backupThrottle returns the rate limit (in bytes per second) to apply to a backup operation.
+// The value from the request takes precedence over the server's "backups.throttle" setting.
+func backupThrottle(s *state.State, value string) (int64, error) {
+	if value == "" {
+		value = s.LocalConfig.BackupsThrottle()
+	}
+
+	throttle, err := backup.ParseThrottle(value)
+	if err != nil {
+		return -1, fmt.Errorf("Invalid backup throttle %q: %w", value, err)
+	}
+
+	return throttle, nil
+}
+
 func backupWriteIndex(sourceInst instance.Instance, pool storagePools.Pool, optimized bool, snapshots bool, tarWriter *instancewriter.InstanceTarWriter) error {
 	// Indicate whether the driver will include a driver-specific optimized header.
 	poolDriverOptimizedHeader := false
@@ -318,6 +376,16 @@ func pruneExpiredBackupsTask(d *Daemon) (task.Func, task.Schedule) {
 				return fmt.Errorf("Failed pruning expired storage bucket backups: %w", err)
 			}
 
+			err = pruneRetainedInstanceBackups(ctx, s)
+			if err != nil {
+				return fmt.Errorf("Failed pruning instance backups outside of their retention policy: %w", err)
+			}
+
+			err = pruneRetainedStorageVolumeBackups(ctx, s)
+			if err != nil {
+				return fmt.Errorf("Failed pruning storage volume backups outside of their retention policy: %w", err)
+			}
+
 			return nil
 		}
 
@@ -389,7 +457,180 @@ func pruneExpiredInstanceBackups(ctx context.Context, s *state.State) error {
 	return nil
 }
 
-func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string) error {
+// backupsOutsideRetention returns the indexes of the backups, given by creation date, which fall outside of
+// the retention policy set through the backups.retention and backups.keep_last configuration keys.
+// Both policies apply independently, a backup is pruned as soon as either of them excludes it.
+func backupsOutsideRetention(creationDates []time.Time, config map[string]string) ([]int, error) {
+	keepLast := -1
+	if config["backups.keep_last"] != "" {
+		value, err := strconv.Atoi(config["backups.keep_last"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid backups.keep_last value: %w", err)
+		}
+
+		if value < 1 {
+			return nil, errors.New("Invalid backups.keep_last value: At least one backup must be kept")
+		}
+
+		keepLast = value
+	}
+
+	// Sort the backups from the most recent to the oldest.
+	order := make([]int, len(creationDates))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return creationDates[order[i]].After(creationDates[order[j]])
+	})
+
+	now := time.Now()
+	pruned := []int{}
+
+	for rank, i := range order {
+		if keepLast >= 0 && rank >= keepLast {
+			pruned = append(pruned, i)
+			continue
+		}
+
+		retainedUntil, err := internalInstance.GetExpiry(creationDates[i], config["backups.retention"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid backups.retention value: %w", err)
+		}
+
+		if !retainedUntil.IsZero() && retainedUntil.Before(now) {
+			pruned = append(pruned, i)
+		}
+	}
+
+	return pruned, nil
+}
+
+// pruneRetainedInstanceBackups deletes the backups of local instances which fall outside of their retention policy.
+func pruneRetainedInstanceBackups(ctx context.Context, s *state.State) error {
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	for _, inst := range instances {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		config := inst.ExpandedConfig()
+		if config["backups.retention"] == "" && config["backups.keep_last"] == "" {
+			continue
+		}
+
+		backups, err := inst.Backups()
+		if err != nil {
+			return fmt.Errorf("Failed loading backups of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		creationDates := make([]time.Time, 0, len(backups))
+		for _, b := range backups {
+			creationDates = append(creationDates, b.Render().CreatedAt)
+		}
+
+		pruned, err := backupsOutsideRetention(creationDates, config)
+		if err != nil {
+			return fmt.Errorf("Failed applying backup retention policy of instance %q (project %q): %w", inst.Name(), inst.Project().Name, err)
+		}
+
+		// Deleting an instance backup emits its own lifecycle event.
+		for _, i := range pruned {
+			err = backups[i].Delete()
+			if err != nil {
+				return fmt.Errorf("Error deleting instance backup %q: %w", backups[i].Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// pruneRetainedStorageVolumeBackups deletes the backups of custom volumes which fall outside of their retention policy.
+func pruneRetainedStorageVolumeBackups(ctx context.Context, s *state.State) error {
+	type prunedBackup struct {
+		backup *backup.VolumeBackup
+		volume db.StorageVolumeArgs
+	}
+
+	var volumeBackups []prunedBackup
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		nodeID := tx.GetNodeID()
+
+		volumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+		if err != nil {
+			return fmt.Errorf("Failed getting custom volumes: %w", err)
+		}
+
+		for _, vol := range volumes {
+			if vol.Config["backups.retention"] == "" && vol.Config["backups.keep_last"] == "" {
+				continue
+			}
+
+			// Ignore volumes on other nodes, but include remote pools (NodeID == -1).
+			if vol.NodeID != -1 && vol.NodeID != nodeID {
+				continue
+			}
+
+			poolID, err := tx.GetStoragePoolID(ctx, vol.PoolName)
+			if err != nil {
+				return fmt.Errorf("Failed getting storage pool %q: %w", vol.PoolName, err)
+			}
+
+			backups, err := tx.GetStoragePoolVolumeBackups(ctx, vol.ProjectName, vol.Name, poolID)
+			if err != nil {
+				return fmt.Errorf("Failed getting backups of storage volume %q (project %q, pool %q): %w", vol.Name, vol.ProjectName, vol.PoolName, err)
+			}
+
+			creationDates := make([]time.Time, 0, len(backups))
+			for _, b := range backups {
+				creationDates = append(creationDates, b.CreationDate)
+			}
+
+			pruned, err := backupsOutsideRetention(creationDates, vol.Config)
+			if err != nil {
+				return fmt.Errorf("Failed applying backup retention policy of storage volume %q (project %q, pool %q): %w", vol.Name, vol.ProjectName, vol.PoolName, err)
+			}
+
+			for _, i := range pruned {
+				b := backups[i]
+				volBackup := backup.NewVolumeBackup(s, vol.ProjectName, vol.PoolName, vol.Name, b.ID, b.Name, b.CreationDate, b.ExpiryDate, b.VolumeOnly, b.OptimizedStorage)
+
+				volumeBackups = append(volumeBackups, prunedBackup{backup: volBackup, volume: vol})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The deletion is done outside of the transaction to avoid any unnecessary IO while inside of
+	// the transaction.
+	for _, b := range volumeBackups {
+		err := b.backup.Delete()
+		if err != nil {
+			return fmt.Errorf("Error deleting storage volume backup %q: %w", b.backup.Name(), err)
+		}
+
+		s.Events.SendLifecycle(b.volume.ProjectName, lifecycle.StorageVolumeBackupDeleted.Event(b.volume.PoolName, db.StoragePoolVolumeTypeNameCustom, b.backup.Name(), b.volume.ProjectName, nil, nil))
+	}
+
+	return nil
+}
+
+// volumeBackupCreate creates a custom volume backup.
+// If a target is provided, the tarball is streamed to it rather than written to the local backups directory
+// and the backup record is only kept while the upload is in progress.
+func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, projectName string, poolName string, volumeName string, target *api.BackupTarget) error {
 	l := logger.AddContext(logger.Ctx{"project": projectName, "storage_volume": volumeName, "name": args.Name})
 	l.Debug("Volume backup started")
 	defer l.Debug("Volume backup finished")
@@ -443,37 +684,75 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 
 	if backupRow.CompressionAlgorithm != "" {
 		compress = backupRow.CompressionAlgorithm
+	} else if pool.Driver().Config()["backups.compression_algorithm"] != "" {
+		compress = pool.Driver().Config()["backups.compression_algorithm"]
 	} else {
 		compress = s.GlobalConfig.BackupsCompressionAlgorithm()
 	}
 
-	// Create the target path if needed.
-	backupsPath := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, volumeName))
-	if !util.PathExists(backupsPath) {
-		err := os.MkdirAll(backupsPath, 0o700)
+	// Detect the rate limit.
+	throttle, err := backupThrottle(s, args.Throttle)
+	if err != nil {
+		return err
+	}
+
+	var tarFileWriter io.WriteCloser
+
+	// Keep track of the checksum of local backups so that their downloads can be verified.
+	hasher := sha256.New()
+	tarOutput := io.Writer(hasher)
+
+	if target != nil {
+		// Stream the tarball to the backup target.
+		targetConfig, targetPath, err := backupTargetConfig(context.TODO(), s, projectName, volumeName, target)
 		if err != nil {
-			return err
+			return fmt.Errorf("Failed loading backup target: %w", err)
 		}
 
-		reverter.Add(func() { _ = os.Remove(backupsPath) })
-	}
+		l.Debug("Opening backup target for writing", logger.Ctx{"name": target.Name, "url": targetConfig["url"], "path": targetPath})
+		targetWriter, err := backup.NewTargetWriter(targetConfig, targetPath)
+		if err != nil {
+			return fmt.Errorf("Error opening backup target for writing: %w", err)
+		}
 
-	target := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
+		defer func() { _ = targetWriter.Close() }()
+		reverter.Add(func() { targetWriter.Abort(errors.New("Backup creation failed")) })
 
-	// Setup the tarball writer.
-	l.Debug("Opening backup tarball for writing", logger.Ctx{"path": target})
-	tarFileWriter, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o600)
-	if err != nil {
-		return fmt.Errorf("Error opening backup tarball for writing %q: %w", target, err)
-	}
+		tarFileWriter = targetWriter
+		tarOutput = targetWriter
+	} else {
+		// Create the target path if needed.
+		backupsPath := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, volumeName))
+		if !util.PathExists(backupsPath) {
+			err := os.MkdirAll(backupsPath, 0o700)
+			if err != nil {
+				return err
+			}
 
-	defer func() { _ = tarFileWriter.Close() }()
-	reverter.Add(func() { _ = os.Remove(target) })
+			reverter.Add(func() { _ = os.Remove(backupsPath) })
+		}
+
+		tarPath := internalUtil.VarPath("backups", "custom", pool.Name(), project.StorageVolume(projectName, backupRow.Name))
+
+		// Setup the tarball writer.
+		l.Debug("Opening backup tarball for writing", logger.Ctx{"path": tarPath})
+		tarFile, err := os.OpenFile(tarPath, os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("Error opening backup tarball for writing %q: %w", tarPath, err)
+		}
+
+		defer func() { _ = tarFile.Close() }()
+		reverter.Add(func() { _ = os.Remove(tarPath) })
+
+		tarFileWriter = tarFile
+		tarOutput = io.MultiWriter(tarFile, hasher)
+	}
 
 	// Create the tarball.
 	tarPipeReader, tarPipeWriter := io.Pipe()
 	defer func() { _ = tarPipeWriter.Close() }() // Ensure that go routine below always ends.
 	tarWriter := instancewriter.NewInstanceTarWriter(tarPipeWriter, nil)
+	tarReader := backup.NewThrottledReader(tarPipeReader, throttle)
 
 	// Setup tar writer go routine, with optional compression.
 	tarWriterRes := make(chan error)
@@ -483,14 +762,14 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		l.Debug("Started backup tarball writer")
 		defer l.Debug("Finished backup tarball writer")
 		if compress != "none" {
-			compressErr = compressFile(compress, tarPipeReader, tarFileWriter)
+			compressErr = compressFile(compress, tarReader, tarOutput)
 
 			// If a compression error occurred, close the tarPipeWriter to end the export.
 			if compressErr != nil {
 				_ = tarPipeWriter.Close()
 			}
 		} else {
-			_, err = io.Copy(tarFileWriter, tarPipeReader)
+			_, err = io.Copy(tarOutput, tarReader)
 		}
 
 		resCh <- err
@@ -537,6 +816,19 @@ func volumeBackupCreate(s *state.State, args db.StoragePoolVolumeBackup, project
 		return fmt.Errorf("Error closing tar file: %w", err)
 	}
 
+	// Streamed backups aren't kept locally, drop the record reserving their name.
+	// Otherwise record the checksum of the tarball.
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		if target != nil {
+			return tx.DeleteStoragePoolVolumeBackup(ctx, args.Name)
+		}
+
+		return tx.UpdateStoragePoolVolumeBackupChecksum(ctx, args.Name, hex.EncodeToString(hasher.Sum(nil)))
+	})
+	if err != nil {
+		return fmt.Errorf("Failed updating backup record: %w", err)
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -865,3 +1157,660 @@ func pruneExpiredStorageBucketBackups(ctx context.Context, s *state.State) error
 
 	return nil
 }
+
+// backupChecksumReader wraps a backup download, failing the final read if the data doesn't match the expected checksum.
+type backupChecksumReader struct {
+	io.ReadCloser
+
+	hasher   hash.Hash
+	expected string
+}
+
+// Read reads from the download while hashing the data.
+func (r *backupChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.hasher.Write(p[:n])
+
+	if errors.Is(err, io.EOF) {
+		checksum := hex.EncodeToString(r.hasher.Sum(nil))
+		if checksum != r.expected {
+			return n, fmt.Errorf("Backup checksum mismatch (expected %q, got %q)", r.expected, checksum)
+		}
+	}
+
+	return n, err
+}
+
+// backupDownloadCheck validates the URL and checksum of a backup to be downloaded on behalf of a client and returns
+// the hosts the download is restricted to (nil if unrestricted). Server administrators can download from any host
+// while other clients are limited to the hosts listed in backups.download_hosts.
+func backupDownloadCheck(s *state.State, r *http.Request, source string, checksum string) ([]string, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid backup URL: %v", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Unsupported backup URL scheme %q", u.Scheme)
+	}
+
+	checksum = strings.TrimPrefix(checksum, "sha256:")
+	if checksum != "" {
+		_, err := hex.DecodeString(checksum)
+		if err != nil || len(checksum) != sha256.Size*2 {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid backup checksum, a SHA256 hash is expected")
+		}
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectServer(), auth.EntitlementCanEdit)
+	if err == nil {
+		return nil, nil
+	} else if !api.StatusErrorCheck(err, http.StatusForbidden) {
+		return nil, err
+	}
+
+	allowedHosts := s.GlobalConfig.BackupsDownloadHosts()
+	if !slices.Contains(allowedHosts, u.Hostname()) {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Backup downloads from %q aren't allowed", u.Hostname())
+	}
+
+	return allowedHosts, nil
+}
+
+// backupDownloadCheckIP returns an error if the IP address isn't a valid destination for a backup download.
+func backupDownloadCheckIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("Backup downloads from %q aren't allowed", ip.String())
+	}
+
+	return nil
+}
+
+// backupDownloadDialer is used for direct backup downloads and checks the resolved address before connecting.
+var backupDownloadDialer = &net.Dialer{
+	Timeout:   10 * time.Second,
+	KeepAlive: 3 * time.Second,
+	Control: func(network string, address string, c syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return fmt.Errorf("Invalid backup download address %q", address)
+		}
+
+		return backupDownloadCheckIP(ip)
+	},
+}
+
+// backupDownloadTransport sends the backup download requests either directly or through the configured proxy.
+// As the proxy resolves the destination itself, its addresses are checked prior to sending the request.
+type backupDownloadTransport struct {
+	direct  *http.Transport
+	proxied *http.Transport
+	proxy   func(req *http.Request) (*url.URL, error)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *backupDownloadTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var proxyURL *url.URL
+	if t.proxy != nil {
+		var err error
+
+		proxyURL, err = t.proxy(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if proxyURL == nil {
+		return t.direct.RoundTrip(req)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		err := backupDownloadCheckIP(addr.IP)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.proxied.RoundTrip(req)
+}
+
+// backupDownload fetches a backup file from a HTTP(S) URL on behalf of a client.
+// The URL and checksum must have been validated by backupDownloadCheck, its allowed hosts restricting redirects.
+// Loopback and link-local destinations are always refused.
+// If a SHA256 checksum is provided (optionally prefixed with "sha256:"), the returned reader fails at the end of the data
+// if it doesn't match.
+func backupDownload(ctx context.Context, s *state.State, source string, headers map[string]string, checksum string, allowedHosts []string) (io.ReadCloser, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid backup URL: %w", err)
+	}
+
+	checksum = strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+
+	client, err := localUtil.HTTPClient("", s.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("Unexpected HTTP transport")
+	}
+
+	direct := base.Clone()
+	direct.Proxy = nil
+	direct.DialContext = backupDownloadDialer.DialContext
+
+	client.Transport = &backupDownloadTransport{direct: direct, proxied: base, proxy: base.Proxy}
+
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("Unsupported backup URL scheme %q", req.URL.Scheme)
+		}
+
+		if allowedHosts != nil && !slices.Contains(allowedHosts, req.URL.Hostname()) {
+			return fmt.Errorf("Backup downloads from %q aren't allowed", req.URL.Hostname())
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", version.UserAgent)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed downloading backup: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("Failed downloading backup: %s", resp.Status)
+	}
+
+	if checksum == "" {
+		return resp.Body, nil
+	}
+
+	return &backupChecksumReader{ReadCloser: resp.Body, hasher: sha256.New(), expected: checksum}, nil
+}
+
+// backupListFiles returns the content of the backup tarball at tarPath.
+func backupListFiles(s *state.State, tarPath string) ([]api.BackupFile, error) {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Backup tarball isn't stored on the server")
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return backup.ListFiles(f, s.OS, tarPath)
+}
+
+// backupExportDiskImage returns a file response entry for the raw disk at diskPath within the backup tarball at
+// tarPath, converted to a disk image of the given format. The temporary files are removed once the entry is sent.
+func backupExportDiskImage(s *state.State, tarPath string, diskPath string, format string, filename string) (*response.FileResponseEntry, error) {
+	if !slices.Contains(storagePools.DiskImageExportFormats, format) {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Unsupported export format %q", format)
+	}
+
+	f, err := os.Open(tarPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Backup tarball isn't stored on the server")
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	tr, cancelFunc, err := backup.TarReader(f, s.OS, tarPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cancelFunc()
+
+	tmpDir, err := os.MkdirTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_export_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	reverter.Add(func() { _ = os.RemoveAll(tmpDir) })
+
+	// Extract the raw disk.
+	rawPath := filepath.Join(tmpDir, "disk.raw")
+	found := false
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Error reading backup file: %w", err)
+		}
+
+		if hdr.Name != diskPath {
+			continue
+		}
+
+		rawFile, err := os.OpenFile(rawPath, os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.Copy(rawFile, tr)
+		if err != nil {
+			_ = rawFile.Close()
+			return nil, fmt.Errorf("Failed extracting disk from backup: %w", err)
+		}
+
+		err = rawFile.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		break
+	}
+
+	cancelFunc() // Done reading archive.
+
+	if !found {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Backup doesn't contain a raw disk (only non-optimized backups of block volumes can be exported as disk images)")
+	}
+
+	imgPath := filepath.Join(tmpDir, "disk."+format)
+	err = storagePools.DiskImageExport(s.OS, rawPath, format, imgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = os.Remove(rawPath)
+
+	imgFile, err := os.Open(imgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := imgFile.Stat()
+	if err != nil {
+		_ = imgFile.Close()
+		return nil, err
+	}
+
+	reverter.Success()
+
+	return &response.FileResponseEntry{
+		Identifier:   filename,
+		Filename:     filename,
+		File:         imgFile,
+		FileSize:     fi.Size(),
+		FileModified: fi.ModTime(),
+		Cleanup: func() {
+			_ = imgFile.Close()
+			_ = os.RemoveAll(tmpDir)
+		},
+	}, nil
+}
+
+// backupThrottleExport rate limits the download of an exported backup file.
+// The value from the request takes precedence over the server's "backups.throttle" setting.
+func backupThrottleExport(s *state.State, value string, ent *response.FileResponseEntry) error {
+	throttle, err := backupThrottle(s, value)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	if throttle == 0 {
+		return nil
+	}
+
+	if ent.File == nil {
+		f, err := os.Open(ent.Path)
+		if err != nil {
+			return err
+		}
+
+		fi, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		cleanup := ent.Cleanup
+
+		ent.File = f
+		ent.FileSize = fi.Size()
+		ent.FileModified = fi.ModTime()
+		ent.Cleanup = func() {
+			_ = f.Close()
+
+			if cleanup != nil {
+				cleanup()
+			}
+		}
+	}
+
+	ent.File = backup.NewThrottledReadSeeker(ent.File, throttle)
+
+	return nil
+}
+
+// backupRestoreFiles extracts the source path of the backup tarball at tarPath (a file, a symlink or a whole
+// directory tree) to the target path through the SFTP client.
+// If set, check is called for each entry before it gets restored and can refuse it by returning an error.
+func backupRestoreFiles(s *state.State, tarPath string, client *sftp.Client, source string, target string, check func(dest string, hdr *tar.Header) error) error {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return api.StatusErrorf(http.StatusNotFound, "Backup tarball isn't stored on the server")
+		}
+
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	tr, cancelFunc, err := backup.TarReader(f, s.OS, tarPath)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	source = strings.Trim(source, "/")
+	found := false
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return fmt.Errorf("Error reading backup file: %w", err)
+		}
+
+		name := strings.TrimSuffix(hdr.Name, "/")
+		if name != source && !strings.HasPrefix(name, source+"/") {
+			continue
+		}
+
+		found = true
+		dest := path.Join(target, strings.TrimPrefix(name, source))
+
+		err = backupRestoreCheckPath(client, target, dest)
+		if err != nil {
+			return err
+		}
+
+		if check != nil {
+			err = check(dest, hdr)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = client.MkdirAll(dest)
+			if err != nil {
+				return fmt.Errorf("Failed creating directory %q: %w", dest, err)
+			}
+
+		case tar.TypeReg:
+			err = client.MkdirAll(path.Dir(dest))
+			if err != nil {
+				return fmt.Errorf("Failed creating directory %q: %w", path.Dir(dest), err)
+			}
+
+			file, err := client.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+			if err != nil {
+				return fmt.Errorf("Failed creating file %q: %w", dest, err)
+			}
+
+			_, err = file.ReadFrom(tr)
+			if err != nil {
+				_ = file.Close()
+				return fmt.Errorf("Failed writing file %q: %w", dest, err)
+			}
+
+			err = file.Close()
+			if err != nil {
+				return err
+			}
+
+		case tar.TypeSymlink:
+			err = client.MkdirAll(path.Dir(dest))
+			if err != nil {
+				return fmt.Errorf("Failed creating directory %q: %w", path.Dir(dest), err)
+			}
+
+			err = client.Symlink(hdr.Linkname, dest)
+			if err != nil {
+				return fmt.Errorf("Failed creating symlink %q: %w", dest, err)
+			}
+
+			// Ownership, permissions and times would apply to the symlink target.
+			continue
+
+		default:
+			// Device nodes, fifos and hardlinks can't be restored on their own.
+			continue
+		}
+
+		err = client.Chmod(dest, fs.FileMode(hdr.Mode).Perm())
+		if err != nil {
+			return err
+		}
+
+		err = client.Chown(dest, hdr.Uid, hdr.Gid)
+		if err != nil {
+			return err
+		}
+
+		err = client.Chtimes(dest, hdr.ModTime, hdr.ModTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return api.StatusErrorf(http.StatusNotFound, "Path %q not found in backup", source)
+	}
+
+	return nil
+}
+
+// backupRestoreCheckPath checks that an entry restored at dest through the SFTP client can't be written outside of the
+// target path, whether through relative components or through symlinks, including those restored from the backup.
+// Symlinks found at dest are removed so that the entry replaces them rather than being written through them.
+func backupRestoreCheckPath(client *sftp.Client, target string, dest string) error {
+	target = path.Clean(target)
+	if dest != target && !strings.HasPrefix(dest, strings.TrimSuffix(target, "/")+"/") {
+		return fmt.Errorf("Refusing to restore %q outside of %q", dest, target)
+	}
+
+	// Check the parents of the entry below the target.
+	current := target
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(dest, target), "/"), "/")
+	for _, part := range parts[:len(parts)-1] {
+		current = path.Join(current, part)
+
+		fi, err := client.Lstat(current)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// The remaining parents get created as directories.
+				return nil
+			}
+
+			return err
+		}
+
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("Refusing to restore %q through symlink %q", dest, current)
+		}
+	}
+
+	fi, err := client.Lstat(dest)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		err = client.Remove(dest)
+		if err != nil {
+			return fmt.Errorf("Failed removing symlink %q: %w", dest, err)
+		}
+	}
+
+	return nil
+}
+
+// backupSecretDefaultExpiry is how long a backup download secret remains valid for when no expiry is requested.
+const backupSecretDefaultExpiry = "1H"
+
+// backupSecretCreate creates a token operation allowing untrusted clients to download the backup export at exportURL
+// until the secret expires. The returned operation's metadata contains the secret, its expiry and the full export URL.
+func backupSecretCreate(s *state.State, r *http.Request, projectName string, opType operationtype.Type, resources map[string][]api.URL, exportURL *api.URL, expiry string) response.Response {
+	if expiry == "" {
+		expiry = backupSecretDefaultExpiry
+	}
+
+	expiresAt, err := internalInstance.GetExpiry(time.Now(), expiry)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid expiry %q: %w", expiry, err))
+	}
+
+	secret, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	meta := jmap.Map{
+		"secret":    secret,
+		"expiresAt": expiresAt,
+		"url":       exportURL.WithQuery("secret", secret).String(),
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassToken, opType, resources, meta, nil, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// backupValidSecret checks whether the secret grants access to the backup at backupURL.
+// Unlike image secrets, backup secrets can be used any number of times until they expire or are cancelled.
+func backupValidSecret(s *state.State, r *http.Request, projectName string, opType operationtype.Type, backupURL *api.URL, secret string) (bool, error) {
+	if secret == "" {
+		return false, nil
+	}
+
+	ops, err := operationsGetByType(s, r, projectName, opType)
+	if err != nil {
+		return false, fmt.Errorf("Failed getting backup token operations: %w", err)
+	}
+
+	for _, op := range ops {
+		if op.Status != api.Running.String() || op.Resources == nil {
+			continue
+		}
+
+		if !backupSecretResourceMatch(backupURL, op.Resources["backups"]) {
+			continue
+		}
+
+		opSecret, ok := op.Metadata["secret"].(string)
+		if !ok || subtle.ConstantTimeCompare([]byte(opSecret), []byte(secret)) != 1 {
+			continue
+		}
+
+		// Depending on whether it's a local operation or not, expiry will either be a time.Time or a string.
+		var expiry time.Time
+		switch expiresAt := op.Metadata["expiresAt"].(type) {
+		case time.Time:
+			expiry = expiresAt
+		case string:
+			expiry, _ = time.Parse(time.RFC3339Nano, expiresAt)
+		}
+
+		if time.Now().After(expiry) {
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// backupSecretResourceMatch checks whether the backup at backupURL is one of the rendered operation resources.
+// The resources carry the project as a query parameter which is ignored as the operations are already filtered by project.
+func backupSecretResourceMatch(backupURL *api.URL, resources []string) bool {
+	for _, resource := range resources {
+		u, err := url.Parse(resource)
+		if err != nil {
+			continue
+		}
+
+		if u.Path == backupURL.URL.Path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowBackupSecret is an AccessHandler helper which allows untrusted requests carrying a valid backup secret.
+func allowBackupSecret(s *state.State, r *http.Request, projectName string, opType operationtype.Type, backupURL *api.URL) response.Response {
+	valid, err := backupValidSecret(s, r, projectName, opType, backupURL, r.FormValue("secret"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !valid {
+		return response.Forbidden(errors.New("Invalid or expired backup secret"))
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,1298 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/archive"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// backupGroupIndexPath is the path of the group index inside of a backup group bundle.
+const backupGroupIndexPath = "index.yaml"
+
+var backupGroupsCmd = APIEndpoint{
+	Path: "backup-groups",
+
+	Get:  APIEndpointAction{Handler: backupGroupsGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: backupGroupsPost, AccessHandler: allowAuthenticated},
+}
+
+var backupGroupCmd = APIEndpoint{
+	Path: "backup-groups/{name}",
+
+	Get:    APIEndpointAction{Handler: backupGroupGet, AccessHandler: allowAuthenticated},
+	Delete: APIEndpointAction{Handler: backupGroupDelete, AccessHandler: allowAuthenticated},
+}
+
+var backupGroupExportCmd = APIEndpoint{
+	Path: "backup-groups/{name}/export",
+
+	Get: APIEndpointAction{Handler: backupGroupExportGet, AccessHandler: allowAuthenticated},
+}
+
+// backupGroupPath returns the path of the bundle of a backup group.
+func backupGroupPath(projectName string, name string) string {
+	return internalUtil.VarPath("backups", "groups", project.Instance(projectName, name))
+}
+
+// backupGroupVolumeMember returns the path of a custom volume backup inside of a backup group bundle.
+func backupGroupVolumeMember(vol api.BackupGroupVolume) string {
+	return filepath.Join("volumes", vol.Pool, vol.Name)
+}
+
+// backupGroupReadIndex reads the index of the backup group bundle at the given path.
+func backupGroupReadIndex(path string) (*api.BackupGroup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	tr := tar.NewReader(f)
+
+	// The index is always the first entry of the bundle.
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading backup group bundle: %w", err)
+	}
+
+	if hdr.Name != backupGroupIndexPath {
+		return nil, errors.New("Backup group bundle is missing its index")
+	}
+
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	group := api.BackupGroup{}
+
+	err = yaml.Unmarshal(data, &group)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing backup group index: %w", err)
+	}
+
+	return &group, nil
+}
+
+// backupGroupCheckAccess checks that the requestor has the given entitlement on all members of the group.
+func backupGroupCheckAccess(s *state.State, r *http.Request, projectName string, volumeProjectName string, group *api.BackupGroup, entitlement auth.Entitlement) error {
+	if group.Instance != "" {
+		err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, group.Instance), entitlement)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, vol := range group.Volumes {
+		err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectStorageVolume(volumeProjectName, vol.Pool, db.StoragePoolVolumeTypeNameCustom, vol.Name, ""), entitlement)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backupGroupProjects returns the project used for the instance and for the custom volumes of a group.
+func backupGroupProjects(s *state.State, r *http.Request) (string, string, error) {
+	projectName := request.ProjectParam(r)
+
+	volumeProjectName, err := project.StorageVolumeProject(s.DB.Cluster, projectName, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return "", "", err
+	}
+
+	return projectName, volumeProjectName, nil
+}
+
+// swagger:operation GET /1.0/backup-groups backup-groups backup_groups_get
+//
+//	Get the backup groups
+//
+//	Returns a list of backup groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/backup-groups/nightly",
+//	              "/1.0/backup-groups/weekly"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/backup-groups?recursion=1 backup-groups backup_groups_get_recursion1
+//
+//	Get the backup groups
+//
+//	Returns a list of backup groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backup groups
+//	          items:
+//	            $ref: "#/definitions/BackupGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, volumeProjectName, err := backupGroupProjects(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	recursion := localUtil.IsRecursionRequest(r)
+	memberName := request.QueryParam(r, "target")
+
+	var groups []db.BackupGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		groups, err = tx.GetBackupGroups(ctx, projectName)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resultString := []string{}
+	resultMap := []*api.BackupGroup{}
+
+	for _, dbGroup := range groups {
+		if memberName != "" && dbGroup.Node != memberName {
+			continue
+		}
+
+		group := dbGroup.ToAPI()
+
+		// Only list the groups whose members are all visible to the requestor.
+		err = backupGroupCheckAccess(s, r, projectName, volumeProjectName, &group, auth.EntitlementCanView)
+		if err != nil {
+			continue
+		}
+
+		if !recursion {
+			resultString = append(resultString, api.NewURL().Path(version.APIVersion, "backup-groups", group.Name).String())
+		} else {
+			resultMap = append(resultMap, &group)
+		}
+	}
+
+	if !recursion {
+		return response.SyncResponse(true, resultString)
+	}
+
+	return response.SyncResponse(true, resultMap)
+}
+
+// swagger:operation POST /1.0/backup-groups backup-groups backup_groups_post
+//
+//	Create or restore a backup group
+//
+//	Takes a snapshot of all the listed custom volumes (and optionally of an instance
+//	along with its attached custom volumes) at the same point in time and bundles
+//	their backups into a single archive.
+//	When provided with a raw backup group bundle instead, restores all of its members
+//	as a unit, rolled back to the point in time at which the group was taken.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: group
+//	    description: Backup group request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/BackupGroupsPost"
+//	  - in: body
+//	    name: raw_backup
+//	    description: Raw backup group bundle
+//	    required: false
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	projectName, volumeProjectName, err := backupGroupProjects(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// A binary upload is a backup group bundle to restore.
+	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		return backupGroupRestore(s, r, projectName, volumeProjectName, r.Body)
+	}
+
+	req := api.BackupGroupsPost{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Name == "" {
+		return response.BadRequest(errors.New("No name provided"))
+	}
+
+	// The name is also used for the bundle file and for the snapshots of the members.
+	err = validate.IsURLSegmentSafe(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid backup group name: %w", err))
+	}
+
+	if req.Instance == "" && len(req.Volumes) == 0 {
+		return response.BadRequest(errors.New("A backup group needs at least one instance or volume"))
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.GetBackupGroup(ctx, projectName, req.Name)
+		if err == nil {
+			return api.StatusErrorf(http.StatusConflict, "Backup group %q already exists", req.Name)
+		}
+
+		if !response.IsNotFoundError(err) {
+			return err
+		}
+
+		return project.AllowBackupCreation(tx, projectName)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	group := api.BackupGroup{
+		Name:     req.Name,
+		Snapshot: req.Name,
+		Instance: req.Instance,
+		Volumes:  []api.BackupGroupVolume{},
+		Location: s.ServerName,
+	}
+
+	// Resolve the members of the group.
+	var inst instance.Instance
+	if req.Instance != "" {
+		inst, err = instance.LoadByProjectAndName(s, projectName, req.Instance)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if inst.Location() != "" && inst.Location() != s.ServerName {
+			return response.BadRequest(fmt.Errorf("Instance %q isn't located on this cluster member", inst.Name()))
+		}
+
+		err = instance.ValidName(inst.Name()+internalInstance.SnapshotDelimiter+group.Snapshot, true)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Include the custom volumes attached to the instance.
+		for _, dev := range inst.ExpandedDevices() {
+			if dev["type"] != "disk" || dev["pool"] == "" || dev["source"] == "" || internalInstance.IsRootDiskDevice(dev) {
+				continue
+			}
+
+			req.Volumes = append(req.Volumes, api.BackupGroupVolume{Pool: dev["pool"], Name: dev["source"]})
+		}
+	}
+
+	for _, vol := range req.Volumes {
+		if vol.Pool == "" || vol.Name == "" {
+			return response.BadRequest(errors.New("Backup group volumes require a pool and a name"))
+		}
+
+		duplicate := false
+		for _, existing := range group.Volumes {
+			if existing == vol {
+				duplicate = true
+				break
+			}
+		}
+
+		if duplicate {
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, vol.Pool)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = pool.ValidateName(group.Snapshot)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid snapshot name for custom volume %q on pool %q: %w", vol.Name, vol.Pool, err))
+		}
+
+		// The volume must be reachable from this cluster member.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			poolID, err := tx.GetStoragePoolID(ctx, vol.Pool)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.GetStoragePoolVolume(ctx, poolID, volumeProjectName, db.StoragePoolVolumeTypeCustom, vol.Name, true)
+			return err
+		})
+		if err != nil {
+			if response.IsNotFoundError(err) {
+				return response.BadRequest(fmt.Errorf("Custom volume %q on pool %q isn't available on this cluster member", vol.Name, vol.Pool))
+			}
+
+			return response.SmartError(err)
+		}
+
+		group.Volumes = append(group.Volumes, vol)
+	}
+
+	err = backupGroupCheckAccess(s, r, projectName, volumeProjectName, &group, auth.EntitlementCanManageBackups)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		group.CreatedAt = time.Now().UTC()
+
+		return backupGroupCreate(s, projectName, volumeProjectName, &group, inst, req.CompressionAlgorithm, op)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["backup_groups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "backup-groups", group.Name)}
+
+	if inst != nil {
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", inst.Name())}
+	}
+
+	for _, vol := range group.Volumes {
+		resources["storage_volumes"] = append(resources["storage_volumes"], *api.NewURL().Path(version.APIVersion, "storage-pools", vol.Pool, "volumes", db.StoragePoolVolumeTypeNameCustom, vol.Name))
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupGroupCreate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// backupGroupCreate snapshots all members of the group at the same point in time, then bundles their backups.
+func backupGroupCreate(s *state.State, projectName string, volumeProjectName string, group *api.BackupGroup, inst instance.Instance, compressionAlgorithm string, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": projectName, "backup_group": group.Name})
+	l.Debug("Backup group started")
+	defer l.Debug("Backup group finished")
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	pools := map[string]storagePools.Pool{}
+	for _, vol := range group.Volumes {
+		if pools[vol.Pool] != nil {
+			continue
+		}
+
+		pool, err := storagePools.LoadByName(s, vol.Pool)
+		if err != nil {
+			return fmt.Errorf("Failed loading storage pool %q: %w", vol.Pool, err)
+		}
+
+		pools[vol.Pool] = pool
+	}
+
+	// Freeze the instance and all other running users of the member volumes while the snapshots are taken
+	// so that they all reflect the same point in time.
+	users, err := backupGroupVolumeUsers(s, volumeProjectName, group, inst)
+	if err != nil {
+		return err
+	}
+
+	if inst != nil {
+		users = append([]instance.Instance{inst}, users...)
+	}
+
+	frozen := []instance.Instance{}
+	unfreeze := func() error {
+		var errs []error
+		for _, user := range frozen {
+			err := user.Unfreeze()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed unfreezing instance %q in project %q: %w", user.Name(), user.Project().Name, err))
+			}
+		}
+
+		frozen = nil
+
+		return errors.Join(errs...)
+	}
+
+	reverter.Add(func() { _ = unfreeze() })
+
+	for _, user := range users {
+		if !user.IsRunning() || user.IsFrozen() {
+			continue
+		}
+
+		err := user.Freeze()
+		if err != nil {
+			return fmt.Errorf("Failed freezing instance %q in project %q: %w", user.Name(), user.Project().Name, err)
+		}
+
+		frozen = append(frozen, user)
+	}
+
+	if inst != nil {
+		err := inst.Snapshot(group.Snapshot, time.Time{}, false)
+		if err != nil {
+			return fmt.Errorf("Failed snapshotting instance %q: %w", inst.Name(), err)
+		}
+	}
+
+	// The snapshots are only needed to produce the bundle.
+	defer func() {
+		if inst == nil {
+			return
+		}
+
+		snapInst, err := instance.LoadByProjectAndName(s, projectName, inst.Name()+internalInstance.SnapshotDelimiter+group.Snapshot)
+		if err == nil {
+			_ = snapInst.Delete(true)
+		}
+	}()
+
+	for _, vol := range group.Volumes {
+		err := pools[vol.Pool].CreateCustomVolumeSnapshot(volumeProjectName, vol.Name, group.Snapshot, time.Time{}, op)
+		if err != nil {
+			return fmt.Errorf("Failed snapshotting custom volume %q on pool %q: %w", vol.Name, vol.Pool, err)
+		}
+
+		defer func() {
+			_ = pools[vol.Pool].DeleteCustomVolumeSnapshot(volumeProjectName, vol.Name+internalInstance.SnapshotDelimiter+group.Snapshot, nil)
+		}()
+	}
+
+	err = unfreeze()
+	if err != nil {
+		return err
+	}
+
+	// Backup the members, including their snapshots so that the group snapshot can be restored.
+	memberName := func(name string) string {
+		return name + internalInstance.SnapshotDelimiter + group.Name
+	}
+
+	members := map[string]string{}
+
+	if inst != nil {
+		args := db.InstanceBackup{
+			Name:                 memberName(inst.Name()),
+			InstanceID:           inst.ID(),
+			CreationDate:         group.CreatedAt,
+			InstanceOnly:         false,
+			CompressionAlgorithm: compressionAlgorithm,
+		}
+
+		err := backupCreate(s, args, inst, op)
+		if err != nil {
+			return fmt.Errorf("Failed backing up instance %q: %w", inst.Name(), err)
+		}
+
+		defer func() {
+			b, err := instance.BackupLoadByName(s, projectName, args.Name)
+			if err == nil {
+				_ = b.Delete()
+			}
+		}()
+
+		members["instance"] = internalUtil.VarPath("backups", "instances", project.Instance(projectName, args.Name))
+	}
+
+	for _, vol := range group.Volumes {
+		var dbVolume *db.StorageVolume
+
+		err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			poolID, err := tx.GetStoragePoolID(ctx, vol.Pool)
+			if err != nil {
+				return err
+			}
+
+			dbVolume, err = tx.GetStoragePoolVolume(ctx, poolID, volumeProjectName, db.StoragePoolVolumeTypeCustom, vol.Name, true)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		args := db.StoragePoolVolumeBackup{
+			Name:                 memberName(vol.Name),
+			VolumeID:             dbVolume.ID,
+			CreationDate:         group.CreatedAt,
+			VolumeOnly:           false,
+			CompressionAlgorithm: compressionAlgorithm,
+		}
+
+		err = volumeBackupCreate(s, args, volumeProjectName, vol.Pool, vol.Name, nil)
+		if err != nil {
+			return fmt.Errorf("Failed backing up custom volume %q on pool %q: %w", vol.Name, vol.Pool, err)
+		}
+
+		defer func() {
+			b, err := storagePoolVolumeBackupLoadByName(context.TODO(), s, volumeProjectName, vol.Pool, args.Name)
+			if err == nil {
+				_ = b.Delete()
+			}
+		}()
+
+		members[backupGroupVolumeMember(vol)] = internalUtil.VarPath("backups", "custom", vol.Pool, project.StorageVolume(volumeProjectName, args.Name))
+	}
+
+	// Write the bundle.
+	groupsPath := internalUtil.VarPath("backups", "groups")
+	err = os.MkdirAll(groupsPath, 0o700)
+	if err != nil {
+		return err
+	}
+
+	target := backupGroupPath(projectName, group.Name)
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("Error opening backup group bundle for writing %q: %w", target, err)
+	}
+
+	defer func() { _ = f.Close() }()
+	reverter.Add(func() { _ = os.Remove(target) })
+
+	tw := tar.NewWriter(f)
+
+	index, err := yaml.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: backupGroupIndexPath, Mode: 0o600, Size: int64(len(index)), ModTime: group.CreatedAt})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(index)
+	if err != nil {
+		return err
+	}
+
+	memberPaths := []string{}
+	if inst != nil {
+		memberPaths = append(memberPaths, "instance")
+	}
+
+	for _, vol := range group.Volumes {
+		memberPaths = append(memberPaths, backupGroupVolumeMember(vol))
+	}
+
+	for _, name := range memberPaths {
+		err := backupGroupWriteMember(tw, name, members[name], group.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Failed adding %q to the backup group bundle: %w", name, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateBackupGroup(ctx, db.BackupGroup{
+			Project:      projectName,
+			Name:         group.Name,
+			Instance:     group.Instance,
+			Volumes:      group.Volumes,
+			CreationDate: group.CreatedAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Failed recording backup group: %w", err)
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// backupGroupVolumeUsers returns the instances, other than the group instance, using the custom volumes of the group.
+// Running instances on other cluster members can't be frozen, so an error is returned if any of them uses a member volume.
+func backupGroupVolumeUsers(s *state.State, volumeProjectName string, group *api.BackupGroup, inst instance.Instance) ([]instance.Instance, error) {
+	type instanceRef struct {
+		project string
+		name    string
+	}
+
+	refs := []instanceRef{}
+	for _, vol := range group.Volumes {
+		var dbVol *db.StorageVolume
+		err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+			poolID, err := tx.GetStoragePoolID(ctx, vol.Pool)
+			if err != nil {
+				return err
+			}
+
+			dbVol, err = tx.GetStoragePoolVolume(ctx, poolID, volumeProjectName, db.StoragePoolVolumeTypeCustom, vol.Name, true)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading custom volume %q on pool %q: %w", vol.Name, vol.Pool, err)
+		}
+
+		err = storagePools.VolumeUsedByInstanceDevices(s, vol.Pool, volumeProjectName, &dbVol.StorageVolume, true, func(dbInst db.InstanceArgs, p api.Project, usedByDevices []string) error {
+			if inst != nil && dbInst.Project == inst.Project().Name && dbInst.Name == inst.Name() {
+				return nil
+			}
+
+			ref := instanceRef{project: dbInst.Project, name: dbInst.Name}
+			if !slices.Contains(refs, ref) {
+				refs = append(refs, ref)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed finding the users of custom volume %q on pool %q: %w", vol.Name, vol.Pool, err)
+		}
+	}
+
+	users := make([]instance.Instance, 0, len(refs))
+	for _, ref := range refs {
+		user, err := instance.LoadByProjectAndName(s, ref.project, ref.name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading instance %q in project %q: %w", ref.name, ref.project, err)
+		}
+
+		if user.Location() != "" && user.Location() != s.ServerName {
+			if user.IsRunning() {
+				return nil, fmt.Errorf("Instance %q in project %q uses a member of the backup group and is running on another cluster member", user.Name(), user.Project().Name)
+			}
+
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// backupGroupWriteMember copies a member backup file into the bundle.
+func backupGroupWriteMember(tw *tar.Writer, name string, path string, modTime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: fi.Size(), ModTime: modTime})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// backupGroupRestore restores all the members of an uploaded backup group bundle.
+func backupGroupRestore(s *state.State, r *http.Request, projectName string, volumeProjectName string, data io.Reader) response.Response {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Store the uploaded bundle in a temporary file.
+	bundleFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_group_", backup.WorkingDirPrefix))
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Add(func() {
+		_ = bundleFile.Close()
+		_ = os.Remove(bundleFile.Name())
+	})
+
+	_, err = io.Copy(bundleFile, data)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	group, err := backupGroupReadIndex(bundleFile.Name())
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validate.IsURLSegmentSafe(group.Snapshot)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid backup group snapshot name: %w", err))
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(volumeProjectName), auth.EntitlementCanCreateStorageVolumes)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if group.Instance != "" {
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectProject(projectName), auth.EntitlementCanCreateInstances)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runReverter := reverter.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer runReverter.Fail()
+
+		err := backupGroupRestoreMembers(s, projectName, volumeProjectName, group, bundleFile, op)
+		if err != nil {
+			return err
+		}
+
+		runReverter.Success()
+
+		_ = bundleFile.Close()
+		_ = os.Remove(bundleFile.Name())
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+
+	if group.Instance != "" {
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", group.Instance)}
+	}
+
+	for _, vol := range group.Volumes {
+		resources["storage_volumes"] = append(resources["storage_volumes"], *api.NewURL().Path(version.APIVersion, "storage-pools", vol.Pool, "volumes", db.StoragePoolVolumeTypeNameCustom, vol.Name))
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupGroupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Success()
+	return operations.OperationResponse(op)
+}
+
+// backupGroupRestoreMembers restores the volumes and then the instance of a bundle, rolling each back to the group snapshot.
+func backupGroupRestoreMembers(s *state.State, projectName string, volumeProjectName string, group *api.BackupGroup, bundleFile *os.File, op *operations.Operation) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	_, err := bundleFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	volumes := map[string]api.BackupGroupVolume{}
+	for _, vol := range group.Volumes {
+		volumes[backupGroupVolumeMember(vol)] = vol
+	}
+
+	// The volumes must be restored before the instance which may be using them.
+	var instanceFile *os.File
+
+	tr := tar.NewReader(bundleFile)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed reading backup group bundle: %w", err)
+		}
+
+		if hdr.Name == backupGroupIndexPath {
+			continue
+		}
+
+		memberFile, err := backupGroupExtractMember(tr)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = memberFile.Close()
+			_ = os.Remove(memberFile.Name())
+		}()
+
+		if hdr.Name == "instance" {
+			instanceFile = memberFile
+			continue
+		}
+
+		vol, ok := volumes[hdr.Name]
+		if !ok {
+			return fmt.Errorf("Unexpected backup group member %q", hdr.Name)
+		}
+
+		bInfo, err := backup.GetInfo(memberFile, s.OS, memberFile.Name())
+		if err != nil {
+			return err
+		}
+
+		pool, err := storagePools.LoadByName(s, vol.Pool)
+		if err != nil {
+			return err
+		}
+
+		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
+			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+		}
+
+		bInfo.RemapVolume(volumeProjectName, vol.Pool, vol.Name)
+
+		err = pool.CreateCustomVolumeFromBackup(*bInfo, memberFile, op)
+		if err != nil {
+			return fmt.Errorf("Failed restoring custom volume %q on pool %q: %w", vol.Name, vol.Pool, err)
+		}
+
+		reverter.Add(func() { _ = pool.DeleteCustomVolume(volumeProjectName, vol.Name, nil) })
+
+		err = pool.RestoreCustomVolume(volumeProjectName, vol.Name, group.Snapshot, op)
+		if err != nil {
+			return fmt.Errorf("Failed rolling back custom volume %q to the group snapshot: %w", vol.Name, err)
+		}
+
+		err = pool.DeleteCustomVolumeSnapshot(volumeProjectName, vol.Name+internalInstance.SnapshotDelimiter+group.Snapshot, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	if group.Instance != "" {
+		if instanceFile == nil {
+			return errors.New("Backup group bundle is missing its instance backup")
+		}
+
+		bInfo, err := backup.GetInfo(instanceFile, s.OS, instanceFile.Name())
+		if err != nil {
+			return err
+		}
+
+		if bInfo.Config == nil {
+			return errors.New("Instance backup is missing required information")
+		}
+
+		bInfo.Project = projectName
+
+		_, err = instanceFile.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+
+		inst, err := instanceCreateFromBackupFile(s, bInfo, instanceFile, false)
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { _ = inst.Delete(true) })
+
+		snapInst, err := instance.LoadByProjectAndName(s, projectName, inst.Name()+internalInstance.SnapshotDelimiter+group.Snapshot)
+		if err != nil {
+			return err
+		}
+
+		err = inst.Restore(snapInst, false)
+		if err != nil {
+			return fmt.Errorf("Failed rolling back instance %q to the group snapshot: %w", inst.Name(), err)
+		}
+
+		err = snapInst.Delete(true)
+		if err != nil {
+			return err
+		}
+	}
+
+	reverter.Success()
+	return nil
+}
+
+// backupGroupExtractMember extracts a member backup of a bundle into a temporary tarball.
+func backupGroupExtractMember(r io.Reader) (*os.File, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	memberFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() {
+		_ = memberFile.Close()
+		_ = os.Remove(memberFile.Name())
+	})
+
+	_, err = io.Copy(memberFile, r)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = memberFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	_, algo, decomArgs, err := archive.DetectCompressionFile(memberFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert squashfs backups to a tarball.
+	if algo == ".squashfs" {
+		tarFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_decompress_", backup.WorkingDirPrefix))
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(func() {
+			_ = tarFile.Close()
+			_ = os.Remove(tarFile.Name())
+		})
+
+		err = archive.ExtractWithFds(decomArgs[0], append(decomArgs[1:], memberFile.Name()), nil, nil, tarFile)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = memberFile.Close()
+		_ = os.Remove(memberFile.Name())
+
+		memberFile = tarFile
+	}
+
+	_, err = memberFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Success()
+	return memberFile, nil
+}
+
+// swagger:operation GET /1.0/backup-groups/{name} backup-groups backup_group_get
+//
+//	Get the backup group
+//
+//	Gets a specific backup group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Backup group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/BackupGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	group, resp := backupGroupLoadFromRequest(s, r, auth.EntitlementCanView)
+	if resp != nil {
+		return resp
+	}
+
+	return response.SyncResponse(true, group.ToAPI())
+}
+
+// swagger:operation DELETE /1.0/backup-groups/{name} backup-groups backup_group_delete
+//
+//	Delete a backup group
+//
+//	Deletes the backup group bundle.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	group, resp := backupGroupLoadFromRequest(s, r, auth.EntitlementCanManageBackups)
+	if resp != nil {
+		return resp
+	}
+
+	// The bundle is stored on the member which created the group.
+	resp = forwardedResponseToNode(s, r, group.Node)
+	if resp != nil {
+		return resp
+	}
+
+	err := os.Remove(backupGroupPath(group.Project, group.Name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteBackupGroup(ctx, group.Project, group.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/backup-groups/{name}/export backup-groups backup_group_export_get
+//
+//	Get the raw backup group bundle
+//
+//	Download the raw backup group bundle from the server.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Raw backup group bundle
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupGroupExportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	group, resp := backupGroupLoadFromRequest(s, r, auth.EntitlementCanView)
+	if resp != nil {
+		return resp
+	}
+
+	// The bundle is stored on the member which created the group.
+	resp = forwardedResponseToNode(s, r, group.Node)
+	if resp != nil {
+		return resp
+	}
+
+	ent := response.FileResponseEntry{
+		Path: backupGroupPath(group.Project, group.Name),
+	}
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+}
+
+// backupGroupLoadFromRequest loads the backup group targeted by the request and checks access to its members.
+func backupGroupLoadFromRequest(s *state.State, r *http.Request, entitlement auth.Entitlement) (*db.BackupGroup, response.Response) {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	projectName, volumeProjectName, err := backupGroupProjects(s, r)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	var group *db.BackupGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err = tx.GetBackupGroup(ctx, projectName, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	apiGroup := group.ToAPI()
+
+	err = backupGroupCheckAccess(s, r, projectName, volumeProjectName, &apiGroup, entitlement)
+	if err != nil {
+		return nil, response.SmartError(err)
+	}
+
+	return group, nil
+}
@@ -0,0 +1,536 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var backupTargetsCmd = APIEndpoint{
+	Path: "backup-targets",
+
+	Get:  APIEndpointAction{Handler: backupTargetsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: backupTargetsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var backupTargetCmd = APIEndpoint{
+	Path: "backup-targets/{name}",
+
+	Get:    APIEndpointAction{Handler: backupTargetGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: backupTargetPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: backupTargetPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: backupTargetDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/backup-targets backup-targets backup_targets_get
+//
+//	Get the backup targets
+//
+//	Returns a list of server-side backup targets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/backup-targets/offsite",
+//	              "/1.0/backup-targets/nas"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/backup-targets?recursion=1 backup-targets backup_targets_get_recursion1
+//
+//	Get the backup targets
+//
+//	Returns a list of server-side backup targets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of backup targets
+//	          items:
+//	            $ref: "#/definitions/NamedBackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var targets []db.BackupTarget
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		targets, err = tx.GetBackupTargets(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !localUtil.IsRecursionRequest(r) {
+		urls := make([]string, 0, len(targets))
+		for _, target := range targets {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "backup-targets", target.Name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]api.NamedBackupTarget, 0, len(targets))
+	for _, target := range targets {
+		result = append(result, target.ToAPI())
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/backup-targets backup-targets backup_targets_post
+//
+//	Add a backup target
+//
+//	Creates a new server-side backup target which backups can then be uploaded to by name.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: target
+//	    description: Backup target
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NamedBackupTargetsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "409":
+//	    $ref: "#/responses/Conflict"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.NamedBackupTargetsPost{}
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = backupTargetValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = backup.ValidateTargetConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := tx.CreateBackupTarget(ctx, req.Name, req.Description, req.Config)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.BackupTargetCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/backup-targets/{name} backup-targets backup_target_get
+//
+//	Get the backup target
+//
+//	Gets a specific server-side backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Backup target
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/NamedBackupTarget"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	target, err := backupTargetLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	apiTarget := target.ToAPI()
+
+	return response.SyncResponseETag(true, apiTarget, apiTarget.Writable())
+}
+
+// swagger:operation PUT /1.0/backup-targets/{name} backup-targets backup_target_put
+//
+//	Update the backup target
+//
+//	Updates the description and configuration of the backup target.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: target
+//	    description: Backup target configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NamedBackupTargetPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	target, err := backupTargetLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	apiTarget := target.ToAPI()
+	err = localUtil.EtagCheck(r, apiTarget.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.NamedBackupTargetPut{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = backup.ValidateTargetConfig(req.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpdateBackupTarget(ctx, name, req.Description, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/backup-targets/{name} backup-targets backup_target_post
+//
+//	Rename the backup target
+//
+//	Renames the backup target.
+//	Volumes referencing it through `backups.target.name` must be updated separately.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: target
+//	    description: Backup target rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/NamedBackupTargetPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "409":
+//	    $ref: "#/responses/Conflict"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.NamedBackupTargetPost{}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = backupTargetValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.RenameBackupTarget(ctx, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.BackupTargetRenamed.Event(req.Name, request.CreateRequestor(r), map[string]any{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/backup-targets/{name} backup-targets backup_target_delete
+//
+//	Delete the backup target
+//
+//	Removes the backup target.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func backupTargetDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteBackupTarget(ctx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.BackupTargetDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// backupTargetLoad returns the backup target with the given name.
+func backupTargetLoad(ctx context.Context, s *state.State, name string) (*db.BackupTarget, error) {
+	var target *db.BackupTarget
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		target, err = tx.GetBackupTarget(ctx, name)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// backupTargetValidateName checks the name of a backup target.
+func backupTargetValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.ContainsAny(name, "/ '\"") {
+		return errors.New("Backup target names may not contain slashes, spaces or quotes")
+	}
+
+	return nil
+}
+
+// backupTargetCheck checks that the backup target can be used for backups of the project.
+// Server administrators can use any named target while other clients, as well as scheduled backups, are limited to
+// the targets listing the project in their `projects` configuration. Targets described by their connection details
+// can always be used.
+func backupTargetCheck(ctx context.Context, s *state.State, r *http.Request, projectName string, target *api.BackupTarget) error {
+	if target == nil || target.Name == "" {
+		return nil
+	}
+
+	if r != nil {
+		err := s.Authorizer.CheckPermission(ctx, r, auth.ObjectServer(), auth.EntitlementCanEdit)
+		if err == nil {
+			return nil
+		} else if !api.StatusErrorCheck(err, http.StatusForbidden) {
+			return err
+		}
+	}
+
+	dbTarget, err := backupTargetLoad(ctx, s, target.Name)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(util.SplitNTrimSpace(dbTarget.Config["projects"], ",", -1, true), projectName) {
+		return api.StatusErrorf(http.StatusForbidden, "Backup target %q isn't available to project %q", target.Name, projectName)
+	}
+
+	return nil
+}
+
+// backupTargetConfig returns the config of the target a backup of the named instance or volume should be uploaded to
+// along with the path to upload it to. Targets referenced by name are looked up, others are described by the
+// connection details of the request. As named targets are shared, the path is always within a per-project and
+// per-instance or volume prefix.
+func backupTargetConfig(ctx context.Context, s *state.State, projectName string, name string, target *api.BackupTarget) (map[string]string, string, error) {
+	if target.Name == "" {
+		return backup.TargetConfig(target), target.Path, nil
+	}
+
+	dbTarget, err := backupTargetLoad(ctx, s, target.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	targetPath := path.Clean("/" + target.Path)
+	if targetPath == "/" {
+		return nil, "", errors.New("Missing backup target path")
+	}
+
+	config := maps.Clone(dbTarget.Config)
+	delete(config, "projects")
+
+	return config, path.Join(projectName, name, targetPath), nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestBackupsOutsideRetention(t *testing.T) {
+	now := time.Now()
+
+	// Backups listed out of order, the most recent being index 2.
+	dates := []time.Time{
+		now.Add(-3 * 24 * time.Hour),
+		now.Add(-10 * 24 * time.Hour),
+		now.Add(-time.Hour),
+		now.Add(-2 * 24 * time.Hour),
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected []int
+		err      bool
+	}{
+		{
+			name:     "No policy",
+			config:   map[string]string{},
+			expected: []int{},
+		},
+		{
+			name:     "Keep last",
+			config:   map[string]string{"backups.keep_last": "2"},
+			expected: []int{0, 1},
+		},
+		{
+			name:     "Keep last above count",
+			config:   map[string]string{"backups.keep_last": "10"},
+			expected: []int{},
+		},
+		{
+			name:     "Retention",
+			config:   map[string]string{"backups.retention": "5d"},
+			expected: []int{1},
+		},
+		{
+			name:     "Keep last and retention",
+			config:   map[string]string{"backups.keep_last": "3", "backups.retention": "1d"},
+			expected: []int{0, 1, 3},
+		},
+		{
+			name:   "Keep none",
+			config: map[string]string{"backups.keep_last": "0"},
+			err:    true,
+		},
+		{
+			name:   "Invalid keep last",
+			config: map[string]string{"backups.keep_last": "abc"},
+			err:    true,
+		},
+		{
+			name:   "Invalid retention",
+			config: map[string]string{"backups.retention": "abc"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pruned, err := backupsOutsideRetention(dates, tt.config)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, pruned)
+		})
+	}
+}
+
+func TestBackupSecretResourceMatch(t *testing.T) {
+	backupURL := api.NewURL().Path(version.APIVersion, "instances", "c1", "backups", "backup1")
+
+	tests := []struct {
+		name      string
+		resources []string
+		expected  bool
+	}{
+		{
+			name:      "Exact match",
+			resources: []string{"/1.0/instances/c1/backups/backup1"},
+			expected:  true,
+		},
+		{
+			name:      "Match with project",
+			resources: []string{"/1.0/instances/c1/backups/backup1?project=foo"},
+			expected:  true,
+		},
+		{
+			name:      "Match among several resources",
+			resources: []string{"/1.0/instances/c1/backups/other", "/1.0/instances/c1/backups/backup1"},
+			expected:  true,
+		},
+		{
+			name:      "Backup name prefix",
+			resources: []string{"/1.0/instances/c1/backups/backup10"},
+			expected:  false,
+		},
+		{
+			name:      "Sub-path",
+			resources: []string{"/1.0/instances/c1/backups/backup1/export"},
+			expected:  false,
+		},
+		{
+			name:      "Other instance",
+			resources: []string{"/1.0/instances/c2/backups/backup1"},
+			expected:  false,
+		},
+		{
+			name:     "No resources",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, backupSecretResourceMatch(backupURL, tt.resources))
+		})
+	}
+}
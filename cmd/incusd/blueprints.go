@@ -0,0 +1,912 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/filter"
+	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var blueprintsCmd = APIEndpoint{
+	Path: "blueprints",
+
+	Get:  APIEndpointAction{Handler: blueprintsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: blueprintsPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var blueprintCmd = APIEndpoint{
+	Path: "blueprints/{name}",
+
+	Delete: APIEndpointAction{Handler: blueprintDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: blueprintGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Patch:  APIEndpointAction{Handler: blueprintPatch, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: blueprintPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: blueprintPut, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/blueprints blueprints blueprints_get
+//
+//	Get the blueprints
+//
+//	Returns a list of blueprints (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/blueprints/web",
+//	              "/1.0/blueprints/db"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/blueprints?recursion=1 blueprints blueprints_get_recursion1
+//
+//	Get the blueprints
+//
+//	Returns a list of blueprints (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of blueprints
+//	          items:
+//	            $ref: "#/definitions/Blueprint"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	// Parse filter value.
+	filterStr := r.FormValue("filter")
+	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	mustLoadObjects := recursion || (clauses != nil && len(clauses.Clauses) > 0)
+
+	fullResults := make([]api.Blueprint, 0)
+	linkResults := make([]string, 0)
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		blueprints, err := dbCluster.GetBlueprints(ctx, tx.Tx(), dbCluster.BlueprintFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		for _, blueprint := range blueprints {
+			if !mustLoadObjects {
+				apiBlueprint := api.Blueprint{Name: blueprint.Name}
+				linkResults = append(linkResults, apiBlueprint.URL(version.APIVersion, blueprint.Project).String())
+				continue
+			}
+
+			apiBlueprint, err := blueprint.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			if clauses != nil && len(clauses.Clauses) > 0 {
+				match, err := filter.Match(*apiBlueprint, *clauses)
+				if err != nil {
+					return err
+				}
+
+				if !match {
+					continue
+				}
+			}
+
+			fullResults = append(fullResults, *apiBlueprint)
+			linkResults = append(linkResults, apiBlueprint.URL(version.APIVersion, blueprint.Project).String())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, fullResults)
+	}
+
+	return response.SyncResponse(true, linkResults)
+}
+
+// swagger:operation POST /1.0/blueprints blueprints blueprints_post
+//
+//	Add a blueprint
+//
+//	Creates a new blueprint.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: blueprint
+//	    description: Blueprint
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BlueprintsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.BlueprintsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = blueprintValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = blueprintValidate(r.Context(), s, projectName, req.BlueprintPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Update DB entry.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		devices, err := dbCluster.APIToDevices(req.Devices)
+		if err != nil {
+			return err
+		}
+
+		exists, err := dbCluster.BlueprintExists(ctx, tx.Tx(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The blueprint already exists")
+		}
+
+		blueprint := dbCluster.Blueprint{
+			Project:     projectName,
+			Name:        req.Name,
+			Description: req.Description,
+			Type:        string(req.Type),
+			Source:      req.Source,
+			Profiles:    req.Profiles,
+		}
+
+		id, err := dbCluster.CreateBlueprint(ctx, tx.Tx(), blueprint)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.CreateBlueprintConfig(ctx, tx.Tx(), id, req.Config)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.CreateBlueprintDevices(ctx, tx.Tx(), id, devices)
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Error inserting %q into database: %w", req.Name, err))
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.BlueprintCreated.Event(req.Name, projectName, requestor, nil)
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/blueprints/{name} blueprints blueprint_get
+//
+//	Get the blueprint
+//
+//	Gets a specific blueprint, optionally at one of its retained previous versions.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: version
+//	    description: Blueprint version
+//	    type: integer
+//	    example: 2
+//	responses:
+//	  "200":
+//	    description: Blueprint
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Blueprint"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var blueprintVersion int64
+	if r.FormValue("version") != "" {
+		blueprintVersion, err = strconv.ParseInt(r.FormValue("version"), 10, 64)
+		if err != nil || blueprintVersion < 1 {
+			return response.BadRequest(fmt.Errorf("Invalid blueprint version %q", r.FormValue("version")))
+		}
+	}
+
+	blueprint, err := blueprintLoad(r.Context(), s, projectName, name, blueprintVersion)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, blueprint, blueprintEtag(blueprint))
+}
+
+// swagger:operation PUT /1.0/blueprints/{name} blueprints blueprint_put
+//
+//	Update the blueprint
+//
+//	Updates the entire blueprint and increments its version.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: blueprint
+//	    description: Blueprint definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BlueprintPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	blueprint, err := blueprintLoad(r.Context(), s, projectName, name, 0)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, blueprintEtag(blueprint))
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.BlueprintPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = doBlueprintUpdate(r.Context(), s, blueprint, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.BlueprintUpdated.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation PATCH /1.0/blueprints/{name} blueprints blueprint_patch
+//
+//	Partially update the blueprint
+//
+//	Updates a subset of the blueprint and increments its version.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: blueprint
+//	    description: Blueprint definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BlueprintPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintPatch(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	blueprint, err := blueprintLoad(r.Context(), s, projectName, name, 0)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, blueprintEtag(blueprint))
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reqRaw := jmap.Map{}
+	err = json.NewDecoder(bytes.NewReader(body)).Decode(&reqRaw)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	req := api.BlueprintPut{}
+	err = json.NewDecoder(bytes.NewReader(body)).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Fill in the fields which weren't provided.
+	_, err = reqRaw.GetString("description")
+	if err != nil {
+		req.Description = blueprint.Description
+	}
+
+	_, err = reqRaw.GetString("type")
+	if err != nil {
+		req.Type = blueprint.Type
+	}
+
+	_, err = reqRaw.GetMap("source")
+	if err != nil {
+		req.Source = blueprint.Source
+	}
+
+	if req.Profiles == nil {
+		req.Profiles = blueprint.Profiles
+	}
+
+	if req.Config == nil {
+		req.Config = blueprint.Config
+	} else {
+		for k, v := range blueprint.Config {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+	}
+
+	if req.Devices == nil {
+		req.Devices = blueprint.Devices
+	} else {
+		for k, v := range blueprint.Devices {
+			_, ok := req.Devices[k]
+			if !ok {
+				req.Devices[k] = v
+			}
+		}
+	}
+
+	err = doBlueprintUpdate(r.Context(), s, blueprint, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.BlueprintUpdated.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/blueprints/{name} blueprints blueprint_post
+//
+//	Rename the blueprint
+//
+//	Renames an existing blueprint.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: blueprint
+//	    description: Blueprint rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BlueprintPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.BlueprintPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = blueprintValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the name isn't already in use.
+		exists, err := dbCluster.BlueprintExists(ctx, tx.Tx(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "Blueprint %q already exists", req.Name)
+		}
+
+		return dbCluster.RenameBlueprint(ctx, tx.Tx(), projectName, name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	lc := lifecycle.BlueprintRenamed.Event(req.Name, projectName, requestor, logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(projectName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/blueprints/{name} blueprints blueprint_delete
+//
+//	Delete the blueprint
+//
+//	Removes the blueprint.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func blueprintDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteBlueprint(ctx, tx.Tx(), projectName, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.BlueprintDeleted.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// blueprintLoad loads a blueprint from the database.
+// If version is set, the definition of that version of the blueprint is returned instead of the current one.
+func blueprintLoad(ctx context.Context, s *state.State, projectName string, name string, version int64) (*api.Blueprint, error) {
+	var blueprint *api.Blueprint
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbBlueprint, err := dbCluster.GetBlueprint(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		blueprint, err = dbBlueprint.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if version == 0 || version == blueprint.Version {
+			return nil
+		}
+
+		definition, err := dbCluster.GetBlueprintVersion(ctx, tx.Tx(), dbBlueprint.ID, version)
+		if err != nil {
+			return err
+		}
+
+		blueprint.BlueprintPut = *definition
+		blueprint.Version = version
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blueprint, nil
+}
+
+// blueprintEtag returns the fields used to compute the ETag of a blueprint.
+func blueprintEtag(blueprint *api.Blueprint) []any {
+	return []any{blueprint.Version, blueprint.Description, blueprint.Type, blueprint.Source, blueprint.Profiles, blueprint.Config, blueprint.Devices}
+}
+
+// blueprintValidateName checks that the name is suitable for a blueprint.
+func blueprintValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.Contains(name, "/") {
+		return errors.New("Blueprint names may not contain slashes")
+	}
+
+	if slices.Contains([]string{".", ".."}, name) {
+		return fmt.Errorf("Invalid blueprint name %q", name)
+	}
+
+	return nil
+}
+
+// blueprintValidate checks the definition of a blueprint.
+func blueprintValidate(ctx context.Context, s *state.State, projectName string, req api.BlueprintPut) error {
+	instType := instancetype.Any
+	if req.Type != "" {
+		var err error
+
+		instType, err = instancetype.New(string(req.Type))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !slices.Contains([]string{"", "image", "none"}, req.Source.Type) {
+		return fmt.Errorf("Invalid source type %q", req.Source.Type)
+	}
+
+	if req.Source.Type == "image" && req.Source.Alias == "" && req.Source.Fingerprint == "" && len(req.Source.Properties) == 0 {
+		return errors.New("Image sources must specify an alias, a fingerprint or properties")
+	}
+
+	err := instance.ValidConfig(s.OS, req.Config, false, instType)
+	if err != nil {
+		return err
+	}
+
+	var p *api.Project
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err = dbProject.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return instance.ValidDevices(s, *p, instType, deviceConfig.NewDevices(req.Devices), nil)
+}
+
+// doBlueprintUpdate validates and stores the new definition of a blueprint, incrementing its version.
+// The previous definition is retained as a version of the blueprint according to instances.blueprints.keep_versions.
+func doBlueprintUpdate(ctx context.Context, s *state.State, blueprint *api.Blueprint, req api.BlueprintPut) error {
+	err := blueprintValidate(ctx, s, blueprint.Project, req)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		devices, err := dbCluster.APIToDevices(req.Devices)
+		if err != nil {
+			return err
+		}
+
+		id, err := dbCluster.GetBlueprintID(ctx, tx.Tx(), blueprint.Project, blueprint.Name)
+		if err != nil {
+			return err
+		}
+
+		// Retain the previous definition and prune the versions past the retention.
+		newVersion := blueprint.Version + 1
+
+		err = dbCluster.CreateBlueprintVersion(ctx, tx.Tx(), int(id), blueprint.Version, blueprint.Writable())
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.DeleteBlueprintVersionsBefore(ctx, tx.Tx(), int(id), newVersion-s.GlobalConfig.InstancesBlueprintsKeepVersions())
+		if err != nil {
+			return err
+		}
+
+		dbBlueprint := dbCluster.Blueprint{
+			Project:     blueprint.Project,
+			Name:        blueprint.Name,
+			Description: req.Description,
+			Version:     newVersion,
+			Type:        string(req.Type),
+			Source:      req.Source,
+			Profiles:    req.Profiles,
+		}
+
+		err = dbCluster.UpdateBlueprint(ctx, tx.Tx(), blueprint.Project, blueprint.Name, dbBlueprint)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateBlueprintConfig(ctx, tx.Tx(), id, req.Config)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateBlueprintDevices(ctx, tx.Tx(), id, devices)
+	})
+}
+
+// blueprintApply fills the fields of an instance creation request from the blueprint it references and records
+// the blueprint name and version in the instance configuration.
+// Values set in the request take precedence over those of the blueprint.
+func blueprintApply(blueprint *api.Blueprint, req *api.InstancesPost) {
+	if req.Type == "" {
+		req.Type = blueprint.Type
+	}
+
+	if req.Source.Type == "" && blueprint.Source.Type != "" {
+		req.Source = api.InstanceSource{
+			Type:        blueprint.Source.Type,
+			Alias:       blueprint.Source.Alias,
+			Fingerprint: blueprint.Source.Fingerprint,
+			Properties:  blueprint.Source.Properties,
+			Server:      blueprint.Source.Server,
+			Protocol:    blueprint.Source.Protocol,
+		}
+	}
+
+	if req.Profiles == nil {
+		req.Profiles = blueprint.Profiles
+	}
+
+	config := util.CloneMap(blueprint.Config)
+	maps.Copy(config, req.Config)
+	config["volatile.blueprint.name"] = blueprint.Name
+	config["volatile.blueprint.version"] = strconv.FormatInt(blueprint.Version, 10)
+	req.Config = config
+
+	devices := make(map[string]map[string]string, len(blueprint.Devices))
+	for name, device := range blueprint.Devices {
+		devices[name] = util.CloneMap(device)
+	}
+
+	maps.Copy(devices, req.Devices)
+	req.Devices = devices
+}
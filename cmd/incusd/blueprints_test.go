@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestBlueprintApply(t *testing.T) {
+	blueprint := &api.Blueprint{
+		Name:    "web",
+		Version: 3,
+		BlueprintPut: api.BlueprintPut{
+			Type:     api.InstanceTypeContainer,
+			Source:   api.BlueprintSource{Type: "image", Alias: "debian/12", Server: "https://images.linuxcontainers.org", Protocol: "simplestreams"},
+			Profiles: []string{"default", "web"},
+			Config:   map[string]string{"limits.cpu": "2", "limits.memory": "2GiB"},
+			Devices: map[string]map[string]string{
+				"root": {"type": "disk", "pool": "default", "path": "/"},
+				"eth0": {"type": "nic", "network": "incusbr0", "name": "eth0"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		req      api.InstancesPost
+		expected api.InstancesPost
+	}{
+		{
+			name: "Empty request",
+			req:  api.InstancesPost{},
+			expected: api.InstancesPost{
+				Type:   api.InstanceTypeContainer,
+				Source: api.InstanceSource{Type: "image", Alias: "debian/12", Server: "https://images.linuxcontainers.org", Protocol: "simplestreams"},
+				InstancePut: api.InstancePut{
+					Profiles: []string{"default", "web"},
+					Config:   map[string]string{"limits.cpu": "2", "limits.memory": "2GiB", "volatile.blueprint.name": "web", "volatile.blueprint.version": "3"},
+					Devices: map[string]map[string]string{
+						"root": {"type": "disk", "pool": "default", "path": "/"},
+						"eth0": {"type": "nic", "network": "incusbr0", "name": "eth0"},
+					},
+				},
+			},
+		},
+		{
+			name: "Request overrides",
+			req: api.InstancesPost{
+				Type:   api.InstanceTypeVM,
+				Source: api.InstanceSource{Type: "none"},
+				InstancePut: api.InstancePut{
+					Profiles: []string{},
+					Config:   map[string]string{"limits.cpu": "4", "volatile.blueprint.version": "1"},
+					Devices: map[string]map[string]string{
+						"eth0": {"type": "nic", "network": "other", "name": "eth0"},
+					},
+				},
+			},
+			expected: api.InstancesPost{
+				Type:   api.InstanceTypeVM,
+				Source: api.InstanceSource{Type: "none"},
+				InstancePut: api.InstancePut{
+					Profiles: []string{},
+					Config:   map[string]string{"limits.cpu": "4", "limits.memory": "2GiB", "volatile.blueprint.name": "web", "volatile.blueprint.version": "3"},
+					Devices: map[string]map[string]string{
+						"root": {"type": "disk", "pool": "default", "path": "/"},
+						"eth0": {"type": "nic", "network": "other", "name": "eth0"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blueprintApply(blueprint, &tt.req)
+			assert.Equal(t, tt.expected, tt.req)
+		})
+	}
+
+	// The blueprint itself must be left untouched.
+	assert.Equal(t, map[string]string{"limits.cpu": "2", "limits.memory": "2GiB"}, blueprint.Config)
+	assert.Equal(t, map[string]string{"type": "nic", "network": "incusbr0", "name": "eth0"}, blueprint.Devices["eth0"])
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+var certificatesExportCmd = APIEndpoint{
+	Path: "certificates/export",
+
+	Get: APIEndpointAction{Handler: certificatesExportGet, AccessHandler: allowAuthenticated},
+}
+
+var certificatesImportCmd = APIEndpoint{
+	Path: "certificates/import",
+
+	Post: APIEndpointAction{Handler: certificatesImportPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateCertificates)},
+}
+
+// swagger:operation GET /1.0/certificates/export certificates certificates_export_get
+//
+//	Export the trust store
+//
+//	Returns a bundle of all the trusted client, metrics and CA certificates and SSH public keys along with their metadata.
+//	Server certificates of cluster members aren't included.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Certificates bundle
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/CertificatesBundle"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificatesExportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), r, auth.EntitlementCanView, auth.ObjectTypeCertificate)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	bundle := api.CertificatesBundle{Certificates: []api.CertificatePut{}}
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbCerts, err := dbCluster.GetCertificates(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, dbCert := range dbCerts {
+			if dbCert.Type == certificate.TypeServer || !userHasPermission(auth.ObjectCertificate(dbCert.Fingerprint)) {
+				continue
+			}
+
+			apiCert, err := dbCert.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			bundle.Certificates = append(bundle.Certificates, apiCert.CertificatePut)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, bundle)
+}
+
+// swagger:operation POST /1.0/certificates/import certificates certificates_import_post
+//
+//	Import a bundle of certificates
+//
+//	Adds the certificates of the bundle to the trust store.
+//	Certificates already in the trust store are skipped, replaced or make the whole import fail depending on `on_conflict`.
+//	The import is atomic, either all certificates are imported or none are.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: bundle
+//	    description: Certificates bundle
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificatesImportPost"
+//	responses:
+//	  "200":
+//	    description: Import result
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/CertificatesImportResult"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificatesImportPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Other members only need to refresh their cache.
+	if isClusterNotification(r) {
+		s.UpdateCertificateCache()
+		return response.EmptySyncResponse
+	}
+
+	req := api.CertificatesImportPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.OnConflict == "" {
+		req.OnConflict = "skip"
+	}
+
+	if !slices.Contains([]string{"skip", "replace", "fail"}, req.OnConflict) {
+		return response.BadRequest(fmt.Errorf("Invalid conflict resolution %q", req.OnConflict))
+	}
+
+	// Validate the whole bundle first.
+	dbCerts := make([]dbCluster.Certificate, 0, len(req.Certificates))
+	for i, entry := range req.Certificates {
+		dbCert, err := certificateBundleEntry(entry)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid certificate #%d: %w", i, err))
+		}
+
+		for _, other := range dbCerts {
+			if other.Fingerprint == dbCert.Fingerprint {
+				return response.BadRequest(fmt.Errorf("Certificate %q is present more than once", dbCert.Fingerprint))
+			}
+		}
+
+		dbCerts = append(dbCerts, *dbCert)
+	}
+
+	// Find the certificates already in the trust store.
+	existing := map[string]bool{}
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, dbCert := range dbCerts {
+			_, err := dbCluster.GetCertificateID(ctx, tx.Tx(), dbCert.Fingerprint)
+			if err == nil {
+				existing[dbCert.Fingerprint] = true
+			} else if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(existing) > 0 && req.OnConflict == "fail" {
+		return response.Conflict(fmt.Errorf("%d certificates are already in the trust store", len(existing)))
+	}
+
+	// Replacing a certificate requires being allowed to edit it.
+	if req.OnConflict == "replace" {
+		for fingerprint := range existing {
+			err := s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectCertificate(fingerprint), auth.EntitlementCanEdit)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+	}
+
+	result := api.CertificatesImportResult{Added: []string{}, Replaced: []string{}, Skipped: []string{}}
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for i, dbCert := range dbCerts {
+			projects := req.Certificates[i].Projects
+
+			if !existing[dbCert.Fingerprint] {
+				_, err := dbCluster.CreateCertificateWithProjects(ctx, tx.Tx(), dbCert, projects)
+				if err != nil {
+					return err
+				}
+
+				result.Added = append(result.Added, dbCert.Fingerprint)
+				continue
+			}
+
+			if req.OnConflict == "skip" {
+				result.Skipped = append(result.Skipped, dbCert.Fingerprint)
+				continue
+			}
+
+			id, err := dbCluster.GetCertificateID(ctx, tx.Tx(), dbCert.Fingerprint)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificate(ctx, tx.Tx(), dbCert.Fingerprint, dbCert)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificateProjects(ctx, tx.Tx(), int(id), projects)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificateLabels(ctx, tx.Tx(), int(id), dbCert.Labels)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificateInstances(ctx, tx.Tx(), int(id), dbCert.Instances)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificateNetworks(ctx, tx.Tx(), int(id), dbCert.AllowedNetworks)
+			if err != nil {
+				return err
+			}
+
+			result.Replaced = append(result.Replaced, dbCert.Fingerprint)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other members about the new certificates.
+	err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+		_, err := client.ImportCertificates(api.CertificatesImportPost{})
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, fingerprint := range result.Added {
+		err = s.Authorizer.AddCertificate(r.Context(), fingerprint)
+		if err != nil {
+			logger.Error("Failed to add certificate to authorizer", logger.Ctx{"fingerprint": fingerprint, "error": err})
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	requestor := request.CreateRequestor(r)
+	for _, fingerprint := range result.Added {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.CertificateCreated.Event(fingerprint, requestor, nil))
+	}
+
+	for _, fingerprint := range result.Replaced {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.CertificateUpdated.Event(fingerprint, requestor, nil))
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// certificateBundleEntry validates a certificate of a bundle and converts it to its database representation.
+func certificateBundleEntry(entry api.CertificatePut) (*dbCluster.Certificate, error) {
+	dbType, err := certificate.FromAPIType(entry.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	err = certificateInstancesValidate(entry.Instances)
+	if err != nil {
+		return nil, err
+	}
+
+	err = certificateNetworksValidate(entry.AllowedNetworks)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCert := dbCluster.Certificate{
+		Type:            dbType,
+		Name:            entry.Name,
+		Restricted:      entry.Restricted,
+		Description:     entry.Description,
+		Labels:          entry.Labels,
+		Instances:       entry.Instances,
+		AllowedNetworks: entry.AllowedNetworks,
+	}
+
+	switch dbType {
+	case certificate.TypeSSH:
+		key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(entry.Certificate))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid SSH public key: %w", err)
+		}
+
+		err = certificateSSHKeyValidate(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if dbCert.Name == "" {
+			dbCert.Name = comment
+		}
+
+		dbCert.Fingerprint = certificate.SSHFingerprint(key)
+		dbCert.Certificate = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	case certificate.TypeCA:
+		chain, err := certificateCAParse(entry.Certificate)
+		if err != nil {
+			return nil, err
+		}
+
+		if dbCert.Name == "" {
+			dbCert.Name = chain[0].Subject.CommonName
+		}
+
+		dbCert.Fingerprint = localtls.CertFingerprint(chain[0])
+		dbCert.Certificate = certificateCAEncode(chain)
+	case certificate.TypeClient, certificate.TypeMetrics:
+		var der []byte
+
+		// Try to parse as PEM.
+		block, rest := pem.Decode([]byte(entry.Certificate))
+		if block != nil {
+			der = block.Bytes
+		} else {
+			der, err = base64.StdEncoding.DecodeString(string(rest))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid certificate material: %w", err)
+		}
+
+		err = certificateValidate(cert)
+		if err != nil {
+			return nil, err
+		}
+
+		dbCert.Fingerprint = localtls.CertFingerprint(cert)
+		dbCert.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	default:
+		return nil, errors.New("Only client, metrics and CA certificates and SSH public keys can be imported")
+	}
+
+	if dbCert.Name == "" {
+		return nil, errors.New("No name provided")
+	}
+
+	return &dbCert, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// certificateCAAdd adds a trusted CA, along with its intermediates, to the trust store.
+func certificateCAAdd(s *state.State, r *http.Request, req api.CertificatesPost) response.Response {
+	chain, err := certificateCAParse(req.Certificate)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// The CA is identified by the first certificate of the chain.
+	fingerprint := localtls.CertFingerprint(chain[0])
+
+	name := req.Name
+	if name == "" {
+		name = chain[0].Subject.CommonName
+	}
+
+	if name == "" {
+		return response.BadRequest(errors.New("No name provided for the CA"))
+	}
+
+	if !isClusterNotification(r) {
+		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			// Check if we already have the CA.
+			existingCert, _ := dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), fingerprint)
+			if existingCert != nil {
+				return api.StatusErrorf(http.StatusConflict, "CA already in trust store")
+			}
+
+			dbCert := dbCluster.Certificate{
+				Fingerprint:     fingerprint,
+				Type:            certificate.TypeCA,
+				Name:            name,
+				Certificate:     certificateCAEncode(chain),
+				Restricted:      req.Restricted,
+				Description:     req.Description,
+				Labels:          req.Labels,
+				Instances:       req.Instances,
+				AllowedNetworks: req.AllowedNetworks,
+			}
+
+			_, err := dbCluster.CreateCertificateWithProjects(ctx, tx.Tx(), dbCert, req.Projects)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the new CA.
+		err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+			return client.CreateCertificate(api.CertificatesPost{
+				CertificatePut: api.CertificatePut{
+					Certificate: req.Certificate,
+					Name:        name,
+					Type:        api.CertificateTypeCA,
+				},
+			})
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Add the certificate resource to the authorizer.
+		err = s.Authorizer.AddCertificate(r.Context(), fingerprint)
+		if err != nil {
+			logger.Error("Failed to add certificate to authorizer", logger.Ctx{"fingerprint": fingerprint, "error": err})
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.CertificateCreated.Event(fingerprint, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// certificateCAParse parses and validates a PEM encoded CA certificate, optionally followed by its intermediates.
+func certificateCAParse(data string) ([]*x509.Certificate, error) {
+	chain, err := localtls.ParseCerts([]byte(data))
+	if err != nil {
+		return nil, errors.New("Invalid PEM encoded CA certificate")
+	}
+
+	for _, cert := range chain {
+		if !cert.BasicConstraintsValid || !cert.IsCA {
+			return nil, fmt.Errorf("Certificate %q isn't a CA certificate", cert.Subject.CommonName)
+		}
+
+		err = certificateValidate(cert)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return chain, nil
+}
+
+// certificateCAEncode returns the PEM encoding of a CA certificate and its intermediates.
+func certificateCAEncode(chain []*x509.Certificate) string {
+	var sb strings.Builder
+	for _, cert := range chain {
+		sb.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+
+	return sb.String()
+}
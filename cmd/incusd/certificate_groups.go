@@ -0,0 +1,605 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var certificateGroupsCmd = APIEndpoint{
+	Path: "certificates/groups",
+
+	Get:  APIEndpointAction{Handler: certificateGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: certificateGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var certificateGroupCmd = APIEndpoint{
+	Path: "certificates/groups/{name}",
+
+	Get:    APIEndpointAction{Handler: certificateGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post:   APIEndpointAction{Handler: certificateGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Put:    APIEndpointAction{Handler: certificateGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Patch:  APIEndpointAction{Handler: certificateGroupPatch, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: certificateGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// swagger:operation GET /1.0/certificates/groups certificates certificate_groups_get
+//
+//	Get the certificate groups
+//
+//	Returns a list of certificate groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/certificates/groups/operators",
+//	              "/1.0/certificates/groups/developers"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/certificates/groups?recursion=1 certificates certificate_groups_get_recursion1
+//
+//	Get the certificate groups
+//
+//	Returns a list of certificate groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of certificate groups
+//	          items:
+//	            $ref: "#/definitions/CertificateGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var groups []*api.CertificateGroup
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbGroups, err := dbCluster.GetCertificateGroups(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		groups = make([]*api.CertificateGroup, 0, len(dbGroups))
+		for _, dbGroup := range dbGroups {
+			if !recursion {
+				groups = append(groups, &api.CertificateGroup{Name: dbGroup.Name})
+				continue
+			}
+
+			group, err := dbGroup.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			groups = append(groups, group)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, groups)
+	}
+
+	urls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urls = append(urls, group.URL(version.APIVersion).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/certificates/groups certificates certificate_groups_post
+//
+//	Create a certificate group
+//
+//	Creates a new group of trusted certificates granted access to the same projects.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Certificate group to create
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Groups are restricted unless explicitly requested otherwise.
+	req := api.CertificateGroupsPost{}
+	req.Restricted = true
+
+	// Parse the request.
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		// Quick checks.
+		err = certificateGroupValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			obj := dbCluster.CertificateGroup{
+				Name:        req.Name,
+				Description: req.Description,
+				Restricted:  req.Restricted,
+			}
+
+			groupID, err := dbCluster.CreateCertificateGroup(ctx, tx.Tx(), obj)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateCertificateGroupProjects(ctx, tx.Tx(), int(groupID), req.Projects)
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.UpdateCertificateGroupCertificates(ctx, tx.Tx(), int(groupID), req.Certificates)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the new group.
+		err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+			return client.CreateCertificateGroup(req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.CertificateGroupCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/certificates/groups/{name} certificates certificate_group_get
+//
+//	Get the certificate group
+//
+//	Gets a specific certificate group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Certificate group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/CertificateGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	group, err := certificateGroupLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, group, group.Writable())
+}
+
+// swagger:operation POST /1.0/certificates/groups/{name} certificates certificate_group_post
+//
+//	Rename the certificate group
+//
+//	Renames an existing certificate group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Certificate group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.CertificateGroupPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Quick checks.
+	err = certificateGroupValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.RenameCertificateGroup(ctx, tx.Tx(), name, req.Name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.CertificateGroupRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation PUT /1.0/certificates/groups/{name} certificates certificate_group_put
+//
+//	Update the certificate group
+//
+//	Updates the entire certificate group configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Certificate group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupPut(d *Daemon, r *http.Request) response.Response {
+	return certificateGroupUpdate(d, r, false)
+}
+
+// swagger:operation PATCH /1.0/certificates/groups/{name} certificates certificate_group_patch
+//
+//	Partially update the certificate group
+//
+//	Updates a subset of the certificate group configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Certificate group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupPatch(d *Daemon, r *http.Request) response.Response {
+	return certificateGroupUpdate(d, r, true)
+}
+
+// certificateGroupUpdate handles both full and partial updates of a certificate group.
+func certificateGroupUpdate(d *Daemon, r *http.Request, patch bool) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		// Reload the cache.
+		s.UpdateCertificateCache()
+
+		return response.EmptySyncResponse
+	}
+
+	group, err := certificateGroupLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, group.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.CertificateGroupPut{Restricted: true}
+	if patch {
+		req = group.Writable()
+	}
+
+	// Parse the request.
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbGroup, err := dbCluster.GetCertificateGroup(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateCertificateGroup(ctx, tx.Tx(), name, dbCluster.CertificateGroup{Description: req.Description, Restricted: req.Restricted})
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateCertificateGroupProjects(ctx, tx.Tx(), dbGroup.ID, req.Projects)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.UpdateCertificateGroupCertificates(ctx, tx.Tx(), dbGroup.ID, req.Certificates)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Notify other nodes about the updated group.
+	err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+		return client.UpdateCertificateGroup(name, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.CertificateGroupUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/certificates/groups/{name} certificates certificate_group_delete
+//
+//	Delete the certificate group
+//
+//	Removes the certificate group. The certificates it contains remain trusted.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteCertificateGroup(ctx, tx.Tx(), name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the removed group.
+		err = notifyCertificateCacheUpdate(s, func(client incus.InstanceServer) error {
+			return client.DeleteCertificateGroup(name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.CertificateGroupDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// certificateGroupLoad returns the API representation of the certificate group with the given name.
+func certificateGroupLoad(ctx context.Context, s *state.State, name string) (*api.CertificateGroup, error) {
+	var group *api.CertificateGroup
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbGroup, err := dbCluster.GetCertificateGroup(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		group, err = dbGroup.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// notifyCertificateCacheUpdate replays the change on the other cluster members so they refresh their certificate cache.
+func notifyCertificateCacheUpdate(s *state.State, hook func(client incus.InstanceServer) error) error {
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	return notifier(hook)
+}
+
+// certificateGroupValidateName checks that the name can be used for a certificate group.
+func certificateGroupValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.ContainsAny(name, "/ '\"") {
+		return errors.New("Certificate group names may not contain slashes, spaces or quotes")
+	}
+
+	return nil
+}
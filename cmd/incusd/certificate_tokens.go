@@ -0,0 +1,283 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var certificateTokensCmd = APIEndpoint{
+	Path: "certificates/tokens",
+
+	Get: APIEndpointAction{Handler: certificateTokensGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateCertificates)},
+}
+
+var certificateTokenCmd = APIEndpoint{
+	Path: "certificates/tokens/{id}",
+
+	Get:    APIEndpointAction{Handler: certificateTokenGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateCertificates)},
+	Delete: APIEndpointAction{Handler: certificateTokenDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanCreateCertificates)},
+}
+
+// swagger:operation GET /1.0/certificates/tokens certificates certificate_tokens_get
+//
+//	Get the pending certificate add tokens
+//
+//	Returns a list of pending certificate add tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/certificates/tokens/b043d632-5c48-44b3-983c-a25660d61164"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/certificates/tokens?recursion=1 certificates certificate_tokens_get_recursion1
+//
+//	Get the pending certificate add tokens
+//
+//	Returns a list of pending certificate add tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of certificate add tokens
+//	          items:
+//	            $ref: "#/definitions/CertificateToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	tokens, _, err := certificateTokensLoad(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if localUtil.IsRecursionRequest(r) {
+		return response.SyncResponse(true, tokens)
+	}
+
+	urls := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		urls = append(urls, token.URL(version.APIVersion).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation GET /1.0/certificates/tokens/{id} certificates certificate_token_get
+//
+//	Get the certificate add token
+//
+//	Gets a specific pending certificate add token.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Certificate add token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/CertificateToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateTokenGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tokens, _, err := certificateTokensLoad(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, token := range tokens {
+		if token.ID == id {
+			return response.SyncResponse(true, token)
+		}
+	}
+
+	return response.NotFound(fmt.Errorf("Certificate add token %q not found", id))
+}
+
+// swagger:operation DELETE /1.0/certificates/tokens/{id} certificates certificate_token_delete
+//
+//	Revoke the certificate add token
+//
+//	Cancels a pending certificate add token so it can no longer be used.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	id, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	_, ops, err := certificateTokensLoad(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, op := range ops {
+		if op.ID != id {
+			continue
+		}
+
+		err = operationCancel(s, r, api.ProjectDefaultName, op)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+
+	return response.NotFound(fmt.Errorf("Certificate add token %q not found", id))
+}
+
+// certificateTokensLoad returns the pending certificate add tokens across the cluster along with their operations.
+func certificateTokensLoad(s *state.State, r *http.Request) ([]api.CertificateToken, []*api.Operation, error) {
+	ops, err := operationsGetByType(s, r, api.ProjectDefaultName, operationtype.CertificateAddToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting certificate token operations: %w", err)
+	}
+
+	tokens := make([]api.CertificateToken, 0, len(ops))
+	tokenOps := make([]*api.Operation, 0, len(ops))
+	for _, op := range ops {
+		// Cancelled tokens can't be used anymore.
+		if op.StatusCode != api.Running {
+			continue
+		}
+
+		// The metadata of operations running on other members went through JSON already.
+		data, err := json.Marshal(op.Metadata)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		meta := struct {
+			Request   api.CertificatesPost `json:"request"`
+			ExpiresAt time.Time            `json:"expiresAt"`
+		}{}
+
+		err = json.Unmarshal(data, &meta)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed parsing certificate token operation %q: %w", op.ID, err)
+		}
+
+		tokens = append(tokens, api.CertificateToken{
+			ID:          op.ID,
+			Name:        meta.Request.Name,
+			Restricted:  meta.Request.Restricted,
+			Projects:    meta.Request.Projects,
+			Description: meta.Request.Description,
+			CreatedAt:   op.CreatedAt,
+			ExpiresAt:   meta.ExpiresAt,
+		})
+
+		tokenOps = append(tokenOps, op)
+	}
+
+	return tokens, tokenOps, nil
+}
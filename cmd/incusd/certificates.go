@@ -12,9 +12,12 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"path"
+	"slices"
 	"time"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/crypto/ssh"
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/filter"
@@ -26,17 +29,20 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	localtls "github.com/lxc/incus/v6/shared/tls"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 var certificatesCmd = APIEndpoint{
@@ -55,6 +61,12 @@ var certificateCmd = APIEndpoint{
 	Put:    APIEndpointAction{Handler: certificatePut, AccessHandler: allowAuthenticated},
 }
 
+var certificateRotateCmd = APIEndpoint{
+	Path: "certificates/{fingerprint}/rotate",
+
+	Post: APIEndpointAction{Handler: certificateRotatePost, AccessHandler: allowAuthenticated},
+}
+
 // swagger:operation GET /1.0/certificates certificates certificates_get
 //
 //  Get the trusted certificates
@@ -244,8 +256,14 @@ func updateCertificateCache(d *Daemon) {
 
 	newCerts := map[certificate.Type]map[string]x509.Certificate{}
 	newProjects := map[string][]string{}
+	newTokens := map[string]certificate.Token{}
+	newSSHKeys := map[string]ssh.PublicKey{}
+	newInstances := map[string][]string{}
+	newCAs := map[string][]*x509.Certificate{}
+	newNetworks := map[string][]*net.IPNet{}
 
 	var certs []*api.Certificate
+	var groups []*api.CertificateGroup
 	var dbCerts []dbCluster.Certificate
 	var localCerts []dbCluster.Certificate
 	var err error
@@ -262,6 +280,39 @@ func updateCertificateCache(d *Daemon) {
 				return err
 			}
 		}
+
+		dbGroups, err := dbCluster.GetCertificateGroups(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		groups = make([]*api.CertificateGroup, len(dbGroups))
+		for i, g := range dbGroups {
+			groups[i], err = g.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+		}
+
+		dbTokens, err := dbCluster.GetAuthTokens(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, t := range dbTokens {
+			projects, err := dbCluster.GetAuthTokenProjects(ctx, tx.Tx(), t.ID)
+			if err != nil {
+				return err
+			}
+
+			newTokens[t.SecretHash] = certificate.Token{
+				Name:       t.Name,
+				Restricted: t.Restricted,
+				Projects:   projects,
+				ExpiresAt:  t.ExpiryDate.Time,
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -270,6 +321,56 @@ func updateCertificateCache(d *Daemon) {
 	}
 
 	for i, dbCert := range dbCerts {
+		if dbCert.Restricted && len(certs[i].Instances) > 0 {
+			newInstances[dbCert.Fingerprint] = certs[i].Instances
+		}
+
+		for _, network := range certs[i].AllowedNetworks {
+			_, subnet, err := net.ParseCIDR(network)
+			if err != nil {
+				logger.Warn("Failed parsing certificate allowed network", logger.Ctx{"name": dbCert.Name, "network": network, "err": err})
+				continue
+			}
+
+			newNetworks[dbCert.Fingerprint] = append(newNetworks[dbCert.Fingerprint], subnet)
+		}
+
+		// SSH public keys aren't X509 certificates.
+		if dbCert.Type == certificate.TypeSSH {
+			key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(dbCert.Certificate))
+			if err != nil {
+				logger.Warn("Failed parsing SSH public key", logger.Ctx{"name": dbCert.Name, "err": err})
+				continue
+			}
+
+			newSSHKeys[dbCert.Fingerprint] = key
+
+			restricted, projects := certificateGroupsAccess(certs[i], groups)
+			if restricted {
+				newProjects[dbCert.Fingerprint] = projects
+			}
+
+			continue
+		}
+
+		// Trusted CAs are kept along with their intermediates.
+		if dbCert.Type == certificate.TypeCA {
+			chain, err := localtls.ParseCerts([]byte(dbCert.Certificate))
+			if err != nil {
+				logger.Warn("Failed parsing CA certificate", logger.Ctx{"name": dbCert.Name, "err": err})
+				continue
+			}
+
+			newCAs[dbCert.Fingerprint] = chain
+
+			restricted, projects := certificateGroupsAccess(certs[i], groups)
+			if restricted {
+				newProjects[dbCert.Fingerprint] = projects
+			}
+
+			continue
+		}
+
 		_, found := newCerts[dbCert.Type]
 		if !found {
 			newCerts[dbCert.Type] = make(map[string]x509.Certificate)
@@ -289,8 +390,9 @@ func updateCertificateCache(d *Daemon) {
 
 		newCerts[dbCert.Type][localtls.CertFingerprint(cert)] = *cert
 
-		if dbCert.Restricted {
-			newProjects[localtls.CertFingerprint(cert)] = certs[i].Projects
+		restricted, projects := certificateGroupsAccess(certs[i], groups)
+		if restricted {
+			newProjects[localtls.CertFingerprint(cert)] = projects
 		}
 
 		// Add server certs to list of certificates to store in local database to allow cluster restart.
@@ -310,6 +412,35 @@ func updateCertificateCache(d *Daemon) {
 	}
 
 	d.clientCerts.SetCertificatesAndProjects(newCerts, newProjects)
+	d.clientCerts.SetTokens(newTokens)
+	d.clientCerts.SetSSHKeys(newSSHKeys)
+	d.clientCerts.SetInstances(newInstances)
+	d.clientCerts.SetCAs(newCAs)
+	d.clientCerts.SetNetworks(newNetworks)
+}
+
+// certificateGroupsAccess returns whether the certificate is restricted and the projects it has access to,
+// that is its own projects along with those of the restricted certificate groups it belongs to.
+// Unrestricted certificates keep their full access.
+func certificateGroupsAccess(cert *api.Certificate, groups []*api.CertificateGroup) (bool, []string) {
+	if !cert.Restricted {
+		return false, nil
+	}
+
+	projects := slices.Clone(cert.Projects)
+	for _, group := range groups {
+		if !group.Restricted || !slices.Contains(group.Certificates, cert.Fingerprint) {
+			continue
+		}
+
+		for _, project := range group.Projects {
+			if !slices.Contains(projects, project) {
+				projects = append(projects, project)
+			}
+		}
+	}
+
+	return true, projects
 }
 
 // updateCertificateCacheFromLocal loads trusted server certificates from local database into memory.
@@ -357,6 +488,154 @@ func updateCertificateCacheFromLocal(d *Daemon) error {
 	return nil
 }
 
+// certificateExpiryWarningPeriod is how long before a trusted certificate's expiry a warning is raised.
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// certificateExpiryWarning returns the warning message for a certificate expiring within the warning period,
+// or an empty string if the certificate doesn't expire soon.
+func certificateExpiryWarning(name string, fingerprint string, notAfter time.Time, now time.Time) string {
+	if notAfter.Sub(now) > certificateExpiryWarningPeriod {
+		return ""
+	}
+
+	if now.After(notAfter) {
+		return fmt.Sprintf("Certificate %q (%s) expired on %s", name, fingerprint, notAfter.UTC().Format(time.RFC3339))
+	}
+
+	return fmt.Sprintf("Certificate %q (%s) expires on %s", name, fingerprint, notAfter.UTC().Format(time.RFC3339))
+}
+
+// staleCertificateExpiryWarnings returns the UUIDs of the unresolved warnings which weren't refreshed since the scan started.
+// Warnings of all members are considered as the member performing the check changes along with the leader.
+func staleCertificateExpiryWarnings(warnings []dbCluster.Warning, scanStart time.Time) []string {
+	uuids := []string{}
+	for _, w := range warnings {
+		if w.Status == warningtype.StatusResolved || !w.LastSeenDate.Before(scanStart) {
+			continue
+		}
+
+		uuids = append(uuids, w.UUID)
+	}
+
+	return uuids
+}
+
+// checkCertificateExpiry raises a warning for every trusted certificate that is about to expire and
+// resolves the warnings of certificates that have since been renewed or removed.
+func checkCertificateExpiry(ctx context.Context, s *state.State) error {
+	// When clustered, only the leader performs the check to avoid duplicate warnings.
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		if s.LocalConfig.ClusterAddress() != leader {
+			return nil
+		}
+	}
+
+	scanStart := time.Now().UTC()
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbCerts, err := dbCluster.GetCertificates(ctx, tx.Tx())
+		if err != nil {
+			return fmt.Errorf("Failed loading certificates: %w", err)
+		}
+
+		for _, dbCert := range dbCerts {
+			certBlock, _ := pem.Decode([]byte(dbCert.Certificate))
+			if certBlock == nil {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(certBlock.Bytes)
+			if err != nil {
+				continue
+			}
+
+			msg := certificateExpiryWarning(dbCert.Name, dbCert.Fingerprint, cert.NotAfter, time.Now())
+			if msg == "" {
+				continue
+			}
+
+			err = tx.UpsertWarningLocalNode(ctx, "", dbCluster.TypeCertificate, dbCert.ID, warningtype.CertificateExpiring, msg)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Resolve any warning which wasn't refreshed by this scan, including those raised by a previous leader.
+		typeCode := warningtype.CertificateExpiring
+		existing, err := dbCluster.GetWarnings(ctx, tx.Tx(), dbCluster.WarningFilter{TypeCode: &typeCode})
+		if err != nil {
+			return err
+		}
+
+		for _, uuid := range staleCertificateExpiryWarnings(existing, scanStart) {
+			err = tx.UpdateWarningStatus(uuid, warningtype.StatusResolved)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func checkCertificateExpiryTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := checkCertificateExpiry(ctx, d.State())
+		if err != nil {
+			logger.Warn("Failed checking certificate expiry", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
+
+// flushCertificateUsageTask writes the last use of the trusted certificates to the database.
+func flushCertificateUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		usage := d.certUsage.Flush()
+		if len(usage) == 0 {
+			return
+		}
+
+		err := d.State().DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			for fingerprint, lastUse := range usage {
+				err := dbCluster.UpdateCertificateLastUseDate(ctx, tx.Tx(), fingerprint, lastUse)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed recording certificate usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+func refreshCertificateRevocationTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		if len(s.GlobalConfig.TrustCARevocation()) == 0 {
+			return
+		}
+
+		err := d.revocation.Refresh(ctx)
+		if err != nil {
+			logger.Warn("Failed refreshing certificate revocation data", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Hourly()
+}
+
 // clusterMemberJoinTokenValid searches for cluster join token that matches the join token provided.
 // Returns matching operation if found and cancels the operation, otherwise returns nil.
 func clusterMemberJoinTokenValid(s *state.State, r *http.Request, projectName string, joinToken *api.ClusterMemberJoinToken) (*api.Operation, error) {
@@ -552,6 +831,17 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Can't use certificate if token is requested"))
 	}
 
+	if req.TokenExpiry != "" {
+		if !req.Token {
+			return response.BadRequest(errors.New("Token expiry can only be set when a token is requested"))
+		}
+
+		_, err := internalInstance.GetExpiry(time.Time{}, req.TokenExpiry)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid token expiry: %w", err))
+		}
+	}
+
 	if req.Token {
 		if req.Type != "client" {
 			return response.BadRequest(errors.New("Tokens can only be issued for client certificates"))
@@ -604,8 +894,11 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 			}
 
 			if joinOp == nil {
+				auditRecord(s.Audit, r, api.AuditTypeTrustToken, false, "", "", "No matching cluster join operation found")
 				return response.Forbidden(errors.New("No matching cluster join operation found"))
 			}
+
+			auditRecord(s.Audit, r, api.AuditTypeTrustToken, true, "", "", "")
 		} else {
 			// Check if certificate add token supplied as token.
 			joinToken, err := localtls.CertificateTokenDecode(req.TrustToken)
@@ -617,9 +910,12 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 				}
 
 				if joinOp == nil {
+					auditRecord(s.Audit, r, api.AuditTypeTrustToken, false, "", "", "No matching certificate add operation found")
 					return response.Forbidden(errors.New("No matching certificate add operation found"))
 				}
 
+				auditRecord(s.Audit, r, api.AuditTypeTrustToken, true, "", "", "")
+
 				// Create a new request from the token data as the user isn't allowed to override anything.
 				req = api.CertificatesPost{}
 				switch tokenReq := joinOp.Metadata["request"].(type) {
@@ -628,6 +924,9 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 					req.Type = tokenReq.Type
 					req.Restricted = tokenReq.Restricted
 					req.Projects = tokenReq.Projects
+					req.Labels = tokenReq.Labels
+					req.Instances = tokenReq.Instances
+					req.AllowedNetworks = tokenReq.AllowedNetworks
 				case map[string]any:
 					req.Name = tokenReq["name"].(string)
 					req.Type = tokenReq["type"].(string)
@@ -636,10 +935,33 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 						req.Projects = append(req.Projects, project.(string))
 					}
 
+					labels, ok := tokenReq["labels"].(map[string]any)
+					if ok {
+						req.Labels = make(map[string]string, len(labels))
+						for key, value := range labels {
+							req.Labels[key] = value.(string)
+						}
+					}
+
+					instances, ok := tokenReq["instances"].([]any)
+					if ok {
+						for _, pattern := range instances {
+							req.Instances = append(req.Instances, pattern.(string))
+						}
+					}
+
+					networks, ok := tokenReq["allowed_networks"].([]any)
+					if ok {
+						for _, network := range networks {
+							req.AllowedNetworks = append(req.AllowedNetworks, network.(string))
+						}
+					}
+
 				default:
 					return response.InternalError(errors.New("Bad certificate add operation data"))
 				}
 			} else {
+				auditRecord(s.Audit, r, api.AuditTypeTrustToken, false, "", "", "Invalid trust token")
 				return response.Forbidden(nil)
 			}
 		}
@@ -650,6 +972,26 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = certificateInstancesValidate(req.Instances)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = certificateNetworksValidate(req.AllowedNetworks)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// SSH public keys aren't X509 certificates and are handled separately.
+	if dbReqType == certificate.TypeSSH {
+		return certificateSSHKeyAdd(s, r, req)
+	}
+
+	// Trusted CAs may come with their intermediates.
+	if dbReqType == certificate.TypeCA {
+		return certificateCAAdd(s, r, req)
+	}
+
 	// Extract the certificate.
 	var cert *x509.Certificate
 	if req.Certificate != "" {
@@ -708,7 +1050,11 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		// If tokens should expire, add the expiry date to the op's metadata.
+		// An expiry provided with the request takes precedence over the server-wide one.
 		expiry := s.GlobalConfig.RemoteTokenExpiry()
+		if req.TokenExpiry != "" {
+			expiry = req.TokenExpiry
+		}
 
 		if expiry != "" {
 			expiresAt, err := internalInstance.GetExpiry(time.Now(), expiry)
@@ -774,12 +1120,15 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 
 			// Store the certificate in the cluster database.
 			dbCert := dbCluster.Certificate{
-				Fingerprint: localtls.CertFingerprint(cert),
-				Type:        dbReqType,
-				Name:        name,
-				Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
-				Restricted:  req.Restricted,
-				Description: req.Description,
+				Fingerprint:     localtls.CertFingerprint(cert),
+				Type:            dbReqType,
+				Name:            name,
+				Certificate:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})),
+				Restricted:      req.Restricted,
+				Description:     req.Description,
+				Labels:          req.Labels,
+				Instances:       req.Instances,
+				AllowedNetworks: req.AllowedNetworks,
 			}
 
 			_, err := dbCluster.CreateCertificateWithProjects(ctx, tx.Tx(), dbCert, req.Projects)
@@ -1029,14 +1378,35 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 			return response.BadRequest(err)
 		}
 
+		if (reqDBType == certificate.TypeSSH) != (dbInfo.Type == api.CertificateTypeSSH) {
+			return response.BadRequest(errors.New("SSH public keys can't be converted to or from certificates"))
+		}
+
+		if (reqDBType == certificate.TypeCA) != (dbInfo.Type == api.CertificateTypeCA) {
+			return response.BadRequest(errors.New("CA certificates can't be converted to or from other certificates"))
+		}
+
 		// Convert to the database type.
 		dbCert := dbCluster.Certificate{
-			Certificate: dbInfo.Certificate,
-			Fingerprint: dbInfo.Fingerprint,
-			Restricted:  req.Restricted,
-			Name:        req.Name,
-			Type:        reqDBType,
-			Description: req.Description,
+			Certificate:     dbInfo.Certificate,
+			Fingerprint:     dbInfo.Fingerprint,
+			Restricted:      req.Restricted,
+			Name:            req.Name,
+			Type:            reqDBType,
+			Description:     req.Description,
+			Labels:          req.Labels,
+			Instances:       req.Instances,
+			AllowedNetworks: req.AllowedNetworks,
+		}
+
+		err = certificateInstancesValidate(req.Instances)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = certificateNetworksValidate(req.AllowedNetworks)
+		if err != nil {
+			return response.BadRequest(err)
 		}
 
 		var userCanEditCertificate bool
@@ -1053,11 +1423,11 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 		certProjects := req.Projects
 		if !userCanEditCertificate {
 			if r.TLS == nil {
-				response.Forbidden(errors.New("Cannot update certificate information"))
+				return response.Forbidden(errors.New("Cannot update certificate information"))
 			}
 
 			// Ensure the user in not trying to change fields other than the certificate.
-			if dbInfo.Restricted != req.Restricted || dbInfo.Name != req.Name || len(dbInfo.Projects) != len(req.Projects) {
+			if dbInfo.Restricted != req.Restricted || dbInfo.Name != req.Name || len(dbInfo.Projects) != len(req.Projects) || !slices.Equal(dbInfo.Instances, req.Instances) || !slices.Equal(dbInfo.AllowedNetworks, req.AllowedNetworks) {
 				return response.Forbidden(errors.New("Only the certificate can be changed"))
 			}
 
@@ -1069,18 +1439,29 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 
 			// Reset dbCert in order to prevent possible future security issues.
 			dbCert = dbCluster.Certificate{
-				Certificate: dbInfo.Certificate,
-				Fingerprint: dbInfo.Fingerprint,
-				Restricted:  dbInfo.Restricted,
-				Name:        dbInfo.Name,
-				Type:        reqDBType,
-				Description: req.Description,
+				Certificate:     dbInfo.Certificate,
+				Fingerprint:     dbInfo.Fingerprint,
+				Restricted:      dbInfo.Restricted,
+				Name:            dbInfo.Name,
+				Type:            reqDBType,
+				Description:     req.Description,
+				Labels:          dbInfo.Labels,
+				Instances:       dbInfo.Instances,
+				AllowedNetworks: dbInfo.AllowedNetworks,
 			}
 
 			certProjects = dbInfo.Projects
 
 			if req.Certificate != "" && dbInfo.Certificate != req.Certificate {
+				// SSH public keys and CAs aren't X509 client certificates the client could be authenticated with.
+				if dbInfo.Type == api.CertificateTypeSSH || dbInfo.Type == api.CertificateTypeCA {
+					return response.Forbidden(errors.New("Certificate cannot be changed"))
+				}
+
 				certBlock, _ := pem.Decode([]byte(dbInfo.Certificate))
+				if certBlock == nil {
+					return response.InternalError(errors.New("Failed decoding the current certificate"))
+				}
 
 				oldCert, err := x509.ParseCertificate(certBlock.Bytes)
 				if err != nil {
@@ -1108,6 +1489,14 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 		}
 
 		if req.Certificate != "" && dbInfo.Certificate != req.Certificate {
+			if reqDBType == certificate.TypeSSH {
+				return response.BadRequest(errors.New("SSH public keys can't be changed"))
+			}
+
+			if reqDBType == certificate.TypeCA {
+				return response.BadRequest(errors.New("CA certificates can't be changed"))
+			}
+
 			// Add supplied certificate.
 			block, _ := pem.Decode([]byte(req.Certificate))
 			if block == nil {
@@ -1210,10 +1599,13 @@ func certificateDelete(d *Daemon, r *http.Request) response.Response {
 		// Non-admins are able to delete only their own certificate.
 		if !userCanEditCertificate {
 			if r.TLS == nil {
-				response.Forbidden(errors.New("Cannot delete certificate"))
+				return response.Forbidden(errors.New("Cannot delete certificate"))
 			}
 
 			certBlock, _ := pem.Decode([]byte(certInfo.Certificate))
+			if certBlock == nil {
+				return response.Forbidden(errors.New("Certificate cannot be deleted"))
+			}
 
 			cert, err := x509.ParseCertificate(certBlock.Bytes)
 			if err != nil {
@@ -1275,6 +1667,210 @@ func certificateDelete(d *Daemon, r *http.Request) response.Response {
 	return response.EmptySyncResponse
 }
 
+// swagger:operation POST /1.0/certificates/{fingerprint}/rotate certificates certificate_rotate_post
+//
+//	Rotate the trusted certificate
+//
+//	Replaces the certificate with a new one while keeping its name, projects, restrictions and labels.
+//	The request must be signed with the private key of the new certificate.
+//	Non-admin clients can only rotate the certificate they're authenticated with.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: certificate
+//	    description: New certificate
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/CertificateRotatePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func certificateRotatePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	fingerprint, err := url.PathUnescape(mux.Vars(r)["fingerprint"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Parse the request.
+	req := api.CertificateRotatePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Certificate == "" {
+		return response.BadRequest(errors.New("No certificate provided"))
+	}
+
+	// Extract the new certificate.
+	var der []byte
+	block, rest := pem.Decode([]byte(req.Certificate))
+	if block != nil {
+		der = block.Bytes
+	} else {
+		data, err := base64.StdEncoding.DecodeString(string(rest))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		der = data
+	}
+
+	newCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid certificate material: %w", err))
+	}
+
+	newFingerprint := localtls.CertFingerprint(newCert)
+	oldFingerprint := fingerprint
+
+	if !isClusterNotification(r) {
+		var certInfo *dbCluster.Certificate
+		err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			var err error
+			certInfo, err = dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), fingerprint)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		oldFingerprint = certInfo.Fingerprint
+
+		if certInfo.Type != certificate.TypeClient && certInfo.Type != certificate.TypeMetrics {
+			return response.BadRequest(errors.New("Only client and metrics certificates can be rotated"))
+		}
+
+		var userCanEditCertificate bool
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectCertificate(certInfo.Fingerprint), auth.EntitlementCanEdit)
+		if err == nil {
+			userCanEditCertificate = true
+		} else if !api.StatusErrorCheck(err, http.StatusForbidden) {
+			return response.SmartError(err)
+		}
+
+		// Non-admins are only able to rotate their own certificate.
+		if !userCanEditCertificate {
+			if r.TLS == nil {
+				return response.Forbidden(errors.New("Cannot rotate certificate"))
+			}
+
+			certBlock, _ := pem.Decode([]byte(certInfo.Certificate))
+			if certBlock == nil {
+				return response.InternalError(errors.New("Failed decoding the current certificate"))
+			}
+
+			oldCert, err := x509.ParseCertificate(certBlock.Bytes)
+			if err != nil {
+				// This should not happen
+				return response.InternalError(err)
+			}
+
+			trustedCerts := map[string]x509.Certificate{
+				certInfo.Name: *oldCert,
+			}
+
+			trusted := false
+			for _, i := range r.TLS.PeerCertificates {
+				trusted, _ = localUtil.CheckTrustState(*i, trustedCerts, s.Endpoints.NetworkCert(), false)
+
+				if trusted {
+					break
+				}
+			}
+
+			if !trusted {
+				return response.Forbidden(errors.New("Certificate cannot be rotated"))
+			}
+		}
+
+		if newFingerprint == certInfo.Fingerprint {
+			return response.BadRequest(errors.New("The new certificate is identical to the current one"))
+		}
+
+		// Require proof of possession of the new certificate's private key.
+		signature, err := base64.StdEncoding.DecodeString(req.Signature)
+		if err != nil || len(signature) == 0 {
+			return response.BadRequest(errors.New("A valid signature made with the new certificate's key is required"))
+		}
+
+		err = localtls.CheckCertificateRotation(certInfo.Fingerprint, newCert, signature)
+		if err != nil {
+			return response.Forbidden(fmt.Errorf("Invalid signature for the new certificate: %w", err))
+		}
+
+		// Check validity.
+		err = certificateValidate(newCert)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		// Swap the certificate in place so that projects, groups and labels are retained.
+		dbCert := *certInfo
+		dbCert.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: newCert.Raw}))
+		dbCert.Fingerprint = newFingerprint
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			existingCert, _ := dbCluster.GetCertificateByFingerprintPrefix(ctx, tx.Tx(), newFingerprint)
+			if existingCert != nil {
+				return api.StatusErrorf(http.StatusConflict, "Certificate already in trust store")
+			}
+
+			return dbCluster.UpdateCertificate(ctx, tx.Tx(), certInfo.Fingerprint, dbCert)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Notify other nodes about the rotation.
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = notifier(func(client incus.InstanceServer) error {
+			return client.RotateCertificate(certInfo.Fingerprint, req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Replace the certificate resource in the authorizer.
+		err = s.Authorizer.DeleteCertificate(r.Context(), certInfo.Fingerprint)
+		if err != nil {
+			logger.Error("Failed to remove certificate from authorizer", logger.Ctx{"fingerprint": certInfo.Fingerprint, "error": err})
+		}
+
+		err = s.Authorizer.AddCertificate(r.Context(), newFingerprint)
+		if err != nil {
+			logger.Error("Failed to add certificate to authorizer", logger.Ctx{"fingerprint": newFingerprint, "error": err})
+		}
+	}
+
+	// Reload the cache.
+	s.UpdateCertificateCache()
+
+	lc := lifecycle.CertificateRotated.Event(newFingerprint, request.CreateRequestor(r), map[string]any{"old_fingerprint": oldFingerprint})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
 func certificateValidate(cert *x509.Certificate) error {
 	if time.Now().Before(cert.NotBefore) {
 		return errors.New("The provided certificate isn't valid yet")
@@ -1298,3 +1894,31 @@ func certificateValidate(cert *x509.Certificate) error {
 
 	return nil
 }
+
+// certificateInstancesValidate checks that the instance name patterns are valid.
+func certificateInstancesValidate(patterns []string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return errors.New("Instance name patterns can't be empty")
+		}
+
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("Invalid instance name pattern %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// certificateNetworksValidate checks that the allowed networks are valid CIDR subnets.
+func certificateNetworksValidate(networks []string) error {
+	for _, network := range networks {
+		err := validate.IsNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Invalid allowed network %q: %w", network, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestCertificateGroupsAccess(t *testing.T) {
+	group := func(name string, restricted bool, projects ...string) *api.CertificateGroup {
+		return &api.CertificateGroup{
+			Name: name,
+			CertificateGroupPut: api.CertificateGroupPut{
+				Restricted:   restricted,
+				Projects:     projects,
+				Certificates: []string{"abcd"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name               string
+		cert               api.Certificate
+		groups             []*api.CertificateGroup
+		expectedRestricted bool
+		expectedProjects   []string
+	}{
+		{
+			name:               "Unrestricted certificate without groups",
+			cert:               api.Certificate{},
+			expectedRestricted: false,
+		},
+		{
+			name:               "Unrestricted certificate in an unrestricted group",
+			cert:               api.Certificate{},
+			groups:             []*api.CertificateGroup{group("all", false)},
+			expectedRestricted: false,
+		},
+		{
+			name:               "Unrestricted certificate in a restricted group",
+			cert:               api.Certificate{},
+			groups:             []*api.CertificateGroup{group("dev", true, "foo", "bar")},
+			expectedRestricted: false,
+		},
+		{
+			name:               "Restricted certificate without projects in a restricted group",
+			cert:               api.Certificate{CertificatePut: api.CertificatePut{Restricted: true}},
+			groups:             []*api.CertificateGroup{group("dev", true, "foo", "bar")},
+			expectedRestricted: true,
+			expectedProjects:   []string{"foo", "bar"},
+		},
+		{
+			name:               "Restricted certificate in an empty restricted group",
+			cert:               api.Certificate{CertificatePut: api.CertificatePut{Restricted: true, Projects: []string{"foo"}}},
+			groups:             []*api.CertificateGroup{group("new", true)},
+			expectedRestricted: true,
+			expectedProjects:   []string{"foo"},
+		},
+		{
+			name:               "Restricted certificate in an unrestricted group",
+			cert:               api.Certificate{CertificatePut: api.CertificatePut{Restricted: true, Projects: []string{"foo"}}},
+			groups:             []*api.CertificateGroup{group("all", false, "bar")},
+			expectedRestricted: true,
+			expectedProjects:   []string{"foo"},
+		},
+		{
+			name:               "Restricted certificate in several restricted groups",
+			cert:               api.Certificate{CertificatePut: api.CertificatePut{Restricted: true, Projects: []string{"foo"}}},
+			groups:             []*api.CertificateGroup{group("dev", true, "foo", "bar"), group("ops", true, "bar", "baz")},
+			expectedRestricted: true,
+			expectedProjects:   []string{"foo", "bar", "baz"},
+		},
+		{
+			name:               "Restricted certificate outside of the group",
+			cert:               api.Certificate{Fingerprint: "efgh", CertificatePut: api.CertificatePut{Restricted: true}},
+			groups:             []*api.CertificateGroup{group("dev", true, "foo")},
+			expectedRestricted: true,
+			expectedProjects:   []string{},
+		},
+		{
+			name:               "Certificate outside of the group",
+			cert:               api.Certificate{Fingerprint: "efgh"},
+			groups:             []*api.CertificateGroup{group("dev", true, "foo")},
+			expectedRestricted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cert.Fingerprint == "" {
+				tt.cert.Fingerprint = "abcd"
+			}
+
+			restricted, projects := certificateGroupsAccess(&tt.cert, tt.groups)
+			assert.Equal(t, tt.expectedRestricted, restricted)
+			if tt.expectedRestricted {
+				assert.ElementsMatch(t, tt.expectedProjects, projects)
+			}
+		})
+	}
+}
+
+func TestCertificateExpiryWarning(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		notAfter    time.Time
+		expectedMsg string
+	}{
+		{
+			name:        "Valid for a long time",
+			notAfter:    now.Add(90 * 24 * time.Hour),
+			expectedMsg: "",
+		},
+		{
+			name:        "Expiring within the warning period",
+			notAfter:    now.Add(7 * 24 * time.Hour),
+			expectedMsg: `Certificate "foo" (abcd) expires on 2026-10-24T12:00:00Z`,
+		},
+		{
+			name:        "Expiring at the end of the warning period",
+			notAfter:    now.Add(certificateExpiryWarningPeriod),
+			expectedMsg: `Certificate "foo" (abcd) expires on 2026-11-16T12:00:00Z`,
+		},
+		{
+			name:        "Expired",
+			notAfter:    now.Add(-time.Hour),
+			expectedMsg: `Certificate "foo" (abcd) expired on 2026-10-17T11:00:00Z`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedMsg, certificateExpiryWarning("foo", "abcd", tt.notAfter, now))
+		})
+	}
+}
+
+func TestStaleCertificateExpiryWarnings(t *testing.T) {
+	scanStart := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	warnings := []dbCluster.Warning{
+		// Refreshed by the current scan.
+		{UUID: "refreshed", Node: "node1", Status: warningtype.StatusNew, LastSeenDate: scanStart.Add(time.Second)},
+
+		// Not refreshed by the current scan.
+		{UUID: "stale", Node: "node1", Status: warningtype.StatusNew, LastSeenDate: scanStart.Add(-24 * time.Hour)},
+
+		// Raised by a previous leader.
+		{UUID: "previous-leader", Node: "node2", Status: warningtype.StatusAcknowledged, LastSeenDate: scanStart.Add(-24 * time.Hour)},
+
+		// Already resolved.
+		{UUID: "resolved", Node: "node2", Status: warningtype.StatusResolved, LastSeenDate: scanStart.Add(-48 * time.Hour)},
+	}
+
+	assert.Equal(t, []string{"stale", "previous-leader"}, staleCertificateExpiryWarnings(warnings, scanStart))
+	assert.Equal(t, []string{}, staleCertificateExpiryWarnings(nil, scanStart))
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
 	"net/http"
 	"net/url"
@@ -30,7 +31,10 @@ import (
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/auth/kerberos"
+	"github.com/lxc/incus/v6/internal/server/auth/ldap"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
 	"github.com/lxc/incus/v6/internal/server/bgp"
 	"github.com/lxc/incus/v6/internal/server/certificate"
@@ -86,6 +90,9 @@ import (
 // A Daemon can respond to requests from a shared client.
 type Daemon struct {
 	clientCerts *certificate.Cache
+	revocation  *certificate.RevocationChecker
+	sshSessions *certificate.SSHSessions
+	certUsage   *certificate.Usage
 	os          *sys.OS
 	db          *db.DB
 	firewall    firewall.Firewall
@@ -119,7 +126,11 @@ type Daemon struct {
 
 	proxy func(req *http.Request) (*url.URL, error)
 
-	oidcVerifier *oidc.Verifier
+	oidcVerifier     *oidc.Verifier
+	kerberosVerifier *kerberos.Verifier
+
+	// Authentication audit log.
+	audit *audit.Log
 
 	// Stores last heartbeat node information to detect node changes.
 	lastNodeList *cluster.APIHeartbeat
@@ -205,9 +216,19 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 		shutdownCancel: shutdownCancel,
 		shutdownDoneCh: make(chan error),
 		apiExtensions:  len(version.APIExtensions),
+		sshSessions:    certificate.NewSSHSessions(),
+		certUsage:      certificate.NewUsage(),
+		audit:          audit.NewLog(auditLogSize),
 	}
 
 	d.serverCert = func() *localtls.CertInfo { return d.serverCertInt }
+	d.revocation = certificate.NewRevocationChecker(func(req *http.Request) (*url.URL, error) {
+		if d.proxy == nil {
+			return nil, nil
+		}
+
+		return d.proxy(req)
+	})
 
 	return d
 }
@@ -438,14 +459,16 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 		return certs, nil
 	}
 
-	// If in PKI mode, filter certificates that aren't trusted by the CA.
-	ca, err := localtls.ReadCert(internalUtil.VarPath("server.ca"))
+	// If in PKI mode, filter certificates that aren't trusted by the CA or its intermediates.
+	cas, err := localtls.ReadCerts(internalUtil.VarPath("server.ca"))
 	if err != nil {
 		return nil, err
 	}
 
 	certPool := x509.NewCertPool()
-	certPool.AddCert(ca)
+	for _, ca := range cas {
+		certPool.AddCert(ca)
+	}
 
 	for certType, certEntries := range certs {
 		if certType == certificate.TypeServer {
@@ -475,6 +498,30 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
 // client that has been authenticated (cluster, unix, or tls).
 func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+	trusted, username, protocol, err := d.authenticate(w, r)
+	if err != nil || !trusted {
+		return trusted, username, protocol, err
+	}
+
+	// The remaining checks only apply to entries of the trust store.
+	if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodSSH}, protocol) {
+		return trusted, username, protocol, nil
+	}
+
+	// Entries of the trust store may only be usable from some networks.
+	if !d.clientCerts.AddressAllowed(username, r.RemoteAddr) {
+		logger.Warn("Rejected trusted client outside of its allowed networks", logger.Ctx{"fingerprint": username, "ip": r.RemoteAddr})
+		return false, "", "", nil
+	}
+
+	// Keep track of when the entry was last used.
+	d.certUsage.Record(username)
+
+	return trusted, username, protocol, nil
+}
+
+// authenticate identifies the client of the request, see Authenticate.
+func (d *Daemon) authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
 		return false, "", "", err
@@ -529,6 +576,28 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		return false, "", "", errors.New("Bad/missing TLS on network query")
 	}
 
+	// Check for an API token.
+	token := authTokenFromRequest(r, d.clientCerts)
+	if token != nil {
+		return true, token.Name, api.AuthenticationMethodToken, nil
+	}
+
+	// Check for a session obtained with an SSH public key.
+	// Sessions issued by another cluster member are rejected rather than passed on to the other methods.
+	sshFingerprint, ok, err := d.sshSessions.GetSession(authBearerFromRequest(r))
+	if err != nil {
+		return false, "", "", err
+	}
+
+	if ok {
+		_, trusted := d.clientCerts.GetSSHKey(sshFingerprint)
+		if !trusted {
+			return false, "", "", &certificate.SSHAuthError{Err: errors.New("SSH public key isn't trusted anymore")}
+		}
+
+		return true, sshFingerprint, api.AuthenticationMethodSSH, nil
+	}
+
 	// Load the certificates.
 	trustCACertificates := d.globalConfig.TrustCACertificates()
 
@@ -536,7 +605,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	jwtOk, _, cert := localUtil.CheckJwtToken(r, trustedCerts[certificate.TypeClient])
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
-		if trusted {
+		if trusted && !d.isCertificateRevoked(r.Context(), cert, trustedCerts[certificate.TypeClient]) {
 			return true, username, api.AuthenticationMethodTLS, nil
 		}
 	}
@@ -551,11 +620,21 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		return true, userName, api.AuthenticationMethodOIDC, nil
 	}
 
+	// Check for a Kerberos ticket sent through SPNEGO.
+	if d.kerberosVerifier != nil && d.kerberosVerifier.IsRequest(r) {
+		userName, err := d.kerberosVerifier.Auth(r)
+		if err != nil {
+			return false, "", "", err
+		}
+
+		return true, userName, api.AuthenticationMethodKerberos, nil
+	}
+
 	// Validate metrics TLS certificates.
 	if r.URL.Path == "/1.0/metrics" {
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
-			if trusted {
+			if trusted && !d.isCertificateRevoked(r.Context(), i, trustedCerts[certificate.TypeMetrics]) {
 				return true, username, api.AuthenticationMethodTLS, nil
 			}
 		}
@@ -564,15 +643,67 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	// Validate regular TLS certificates.
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
-		if trusted {
+		if trusted && !d.isCertificateRevoked(r.Context(), i, trustedCerts[certificate.TypeClient]) {
 			return true, username, api.AuthenticationMethodTLS, nil
 		}
 	}
 
+	// Validate TLS certificates issued by a CA of the trust store, in which case the client is identified by the CA.
+	caFingerprint, issuer, trusted := d.clientCerts.VerifyCA(r.TLS.PeerCertificates)
+	if trusted && (issuer == nil || !d.checkCertificateRevocation(r.Context(), r.TLS.PeerCertificates[0], issuer)) {
+		return true, caFingerprint, api.AuthenticationMethodTLS, nil
+	}
+
 	// Reject unauthorized.
 	return false, "", "", nil
 }
 
+// isCertificateRevoked returns whether a client certificate trusted through the CA rather than
+// through the trust store was revoked according to the configured revocation checks.
+func (d *Daemon) isCertificateRevoked(ctx context.Context, cert *x509.Certificate, trustedCerts map[string]x509.Certificate) bool {
+	if !d.globalConfig.TrustCACertificates() {
+		return false
+	}
+
+	// Certificates in the trust store aren't subject to CA revocation checks.
+	fingerprint := localtls.CertFingerprint(cert)
+	_, found := trustedCerts[fingerprint]
+	if found {
+		return false
+	}
+
+	ca := d.endpoints.NetworkCert().IssuingCA(cert)
+	if ca == nil {
+		return false
+	}
+
+	return d.checkCertificateRevocation(ctx, cert, ca)
+}
+
+// checkCertificateRevocation returns whether the client certificate was revoked by its issuer according to the
+// configured revocation checks. When the status can't be determined, the certificate is only considered revoked
+// in strict mode.
+func (d *Daemon) checkCertificateRevocation(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) bool {
+	methods := d.globalConfig.TrustCARevocation()
+	if len(methods) == 0 {
+		return false
+	}
+
+	fingerprint := localtls.CertFingerprint(cert)
+
+	revoked, err := d.revocation.IsRevoked(ctx, cert, issuer, methods)
+	if err != nil {
+		logger.Warn("Failed checking certificate revocation status", logger.Ctx{"fingerprint": fingerprint, "err": err})
+		return d.globalConfig.TrustCARevocationStrict()
+	}
+
+	if revoked {
+		logger.Info("Rejected revoked client certificate", logger.Ctx{"fingerprint": fingerprint})
+	}
+
+	return revoked
+}
+
 // State creates a new State instance linked to our internal db and os.
 func (d *Daemon) State() *state.State {
 	// If the daemon is shutting down, the context will be cancelled.
@@ -592,6 +723,7 @@ func (d *Daemon) State() *state.State {
 	d.globalConfigMu.Unlock()
 
 	return &state.State{
+		Audit:                  d.audit,
 		Authorizer:             d.authorizer,
 		BGP:                    d.bgp,
 		Cluster:                d.gateway,
@@ -649,6 +781,8 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		if err != nil {
 			var authError *oidc.AuthError
 			if errors.As(err, &authError) {
+				auditRecord(d.audit, r, api.AuditTypeRequest, false, api.AuthenticationMethodOIDC, "", err.Error())
+
 				// Ensure the OIDC headers are set if needed.
 				if d.oidcVerifier != nil {
 					_ = d.oidcVerifier.WriteHeaders(w)
@@ -657,12 +791,28 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				_ = response.Unauthorized(err).Render(w)
 				return
 			}
+
+			var kerberosError *kerberos.AuthError
+			if errors.As(err, &kerberosError) {
+				auditRecord(d.audit, r, api.AuditTypeRequest, false, api.AuthenticationMethodKerberos, "", err.Error())
+				logger.Warn("Rejected Kerberos authentication", logger.Ctx{"ip": r.RemoteAddr, "err": err})
+				_ = response.Unauthorized(err).Render(w)
+				return
+			}
+
+			var sshError *certificate.SSHAuthError
+			if errors.As(err, &sshError) {
+				auditRecord(d.audit, r, api.AuditTypeRequest, false, api.AuthenticationMethodSSH, "", err.Error())
+				_ = response.Unauthorized(err).Render(w)
+				return
+			}
 		}
 
 		// Reject internal queries to remote, non-cluster, clients
 		if version == "internal" && !slices.Contains([]string{"unix", "cluster"}, protocol) {
 			// Except for the initial cluster accept request (done over trusted TLS)
 			if !trusted || c.Path != "cluster/accept" || protocol != api.AuthenticationMethodTLS {
+				auditRecord(d.audit, r, api.AuditTypeRequest, false, protocol, username, "Internal API isn't available to remote clients")
 				logger.Warn("Rejecting remote internal API request", logger.Ctx{"ip": r.RemoteAddr})
 				_ = response.Forbidden(nil).Render(w)
 				return
@@ -678,6 +828,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 
 		untrustedOk := (r.Method == "GET" && c.Get.AllowUntrusted) || (r.Method == "POST" && c.Post.AllowUntrusted)
 		if trusted {
+			auditRecord(d.audit, r, api.AuditTypeRequest, true, protocol, username, "")
 			logger.Debug("Handling API request", logCtx)
 
 			// Add authentication/authorization context data.
@@ -700,6 +851,20 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 				_ = d.oidcVerifier.WriteHeaders(w)
 			}
 
+			reason := "Client isn't trusted"
+			if err != nil {
+				reason = err.Error()
+			}
+
+			auditRecord(d.audit, r, api.AuditTypeRequest, false, protocol, username, reason)
+
+			// Ask clients without any credentials to negotiate Kerberos authentication.
+			if d.kerberosVerifier != nil && r.TLS != nil && len(r.TLS.PeerCertificates) == 0 && r.Header.Get("Authorization") == "" {
+				d.kerberosVerifier.WriteHeaders(w)
+				_ = response.Unauthorized(nil).Render(w)
+				return
+			}
+
 			logger.Warn("Rejecting request from untrusted client", logger.Ctx{"ip": r.RemoteAddr})
 			_ = response.Forbidden(nil).Render(w)
 			return
@@ -967,6 +1132,7 @@ func (d *Daemon) init() error {
 		"seccomp_proxy_send_notify_fd",
 		"idmapped_mounts_v2",
 		"core_scheduling",
+		"cgroup_advanced_isolation",
 	}
 
 	for _, extension := range lxcExtensions {
@@ -1129,14 +1295,30 @@ func (d *Daemon) init() error {
 		return err
 	}
 
+	// Get the private key of the server certificate if it isn't stored on disk.
+	var serverKeyURI string
+	err = d.db.Node.Transaction(context.TODO(), func(ctx context.Context, tx *db.NodeTx) error {
+		localConfig, err := node.ConfigLoad(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		serverKeyURI = localConfig.ServerKeyURI()
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	/* Setup network endpoint certificate */
-	networkCert, err := internalUtil.LoadCert(d.os.VarDir)
+	networkCert, err := internalUtil.LoadCert(d.os.VarDir, serverKeyURI)
 	if err != nil {
 		return err
 	}
 
 	/* Setup server certificate */
-	serverCert, err := internalUtil.LoadServerCert(d.os.VarDir)
+	serverCert, err := internalUtil.LoadServerCert(d.os.VarDir, serverKeyURI)
 	if err != nil {
 		return err
 	}
@@ -1429,6 +1611,8 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	kerberosKeytab, kerberosRealms := d.globalConfig.Kerberos()
+	auditFile, auditSyslog := d.globalConfig.AuditSinks()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
@@ -1459,6 +1643,18 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the audit log sinks.
+	err = d.audit.Configure(auditFile, auditSyslog)
+	if err != nil {
+		logger.Warn("Failed to configure the audit log", logger.Ctx{"err": err})
+	}
+
+	// Setup Kerberos authentication.
+	err = d.setupKerberos(kerberosKeytab, kerberosRealms)
+	if err != nil {
+		return err
+	}
+
 	// Setup OpenFGA authorization.
 	if openfgaAPIURL != "" && openfgaStoreID != "" && openfgaAPIToken != "" {
 		err = d.setupOpenFGA(openfgaAPIURL, openfgaAPIToken, openfgaStoreID)
@@ -1681,6 +1877,12 @@ func (d *Daemon) init() error {
 		// Prune expired custom volume snapshots and take snapshots of custom volumes (minutely check of configurable cron expression)
 		d.tasks.Add(pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d))
 
+		// Take backups of custom volumes (minutely check of configurable cron expression)
+		d.tasks.Add(autoCreateCustomVolumeBackupsTask(d))
+
+		// Remove expired trash entries (hourly)
+		d.tasks.Add(pruneExpiredTrashTask(d))
+
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d))
 
@@ -1689,6 +1891,21 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Warn about expiring trusted certificates (daily)
+		d.tasks.Add(checkCertificateExpiryTask(d))
+
+		// Refresh cached certificate revocation data (hourly)
+		d.tasks.Add(refreshCertificateRevocationTask(d))
+
+		// Record when trusted certificates were last used (every 5 minutes)
+		d.tasks.Add(flushCertificateUsageTask(d))
+
+		// Report out of memory kills in containers (every 30 seconds)
+		d.tasks.Add(instancesOOMMonitorTask(d))
+
+		// Forward instance console output to syslog (every 5 seconds)
+		d.tasks.Add(instancesConsoleSyslogTask(d))
 	}
 
 	// Start all background tasks
@@ -1903,6 +2120,8 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 		trackError(d.endpoints.Down(), "Shutdown endpoints")
 	}
 
+	d.audit.Close()
+
 	if shouldUnmount {
 		logger.Info("Unmounting temporary filesystems")
 
@@ -1935,6 +2154,30 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 	return err
 }
 
+// Setup Kerberos authentication.
+// As the keytab path is shared by all cluster members, a missing keytab only disables Kerberos on this member.
+func (d *Daemon) setupKerberos(keytab string, realms []string) error {
+	if keytab == "" {
+		d.kerberosVerifier = nil
+		return nil
+	}
+
+	verifier, err := kerberos.NewVerifier(keytab, realms)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		logger.Warn("Kerberos keytab not found, disabling Kerberos authentication on this member", logger.Ctx{"keytab": keytab})
+		d.kerberosVerifier = nil
+		return nil
+	}
+
+	d.kerberosVerifier = verifier
+
+	return nil
+}
+
 // Setup OpenFGA.
 func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) error {
 	var err error
@@ -2214,7 +2457,29 @@ func (d *Daemon) setupOpenFGA(apiURL string, apiToken string, storeID string) er
 		return &resources, nil
 	}
 
-	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, auth.WithConfig(config), auth.WithResourcesFunc(refreshResources))
+	options := []func(*auth.Opts){auth.WithConfig(config), auth.WithResourcesFunc(refreshResources)}
+
+	// Synchronize the group memberships from LDAP if configured.
+	ldapConfig := d.globalConfig.OpenFGALDAP()
+	if ldapConfig.URL != "" {
+		refreshGroups := func(ctx context.Context) (map[string][]string, error) {
+			leaderAddress, err := d.gateway.LeaderAddress()
+			if err != nil {
+				if !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+					return nil, err
+				}
+			} else if leaderAddress != d.localConfig.ClusterAddress() {
+				// If clustered and not running on a leader, skip the group update.
+				return nil, nil
+			}
+
+			return ldap.GetGroups(ctx, ldapConfig)
+		}
+
+		options = append(options, auth.WithGroupsFunc(refreshGroups, d.globalConfig.OpenFGALDAPSyncInterval()))
+	}
+
+	openfgaAuthorizer, err := auth.LoadAuthorizer(d.shutdownCtx, auth.DriverOpenFGA, logger.Log, d.clientCerts, options...)
 	if err != nil {
 		return err
 	}
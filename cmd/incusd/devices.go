@@ -193,6 +193,7 @@ func deviceNetlinkListener() (chan []string, chan device.USBEvent, chan device.U
 					busnum,
 					devnum,
 					devname,
+					filepath.Base(props["DEVPATH"]),
 					ueventParts[:len(ueventParts)-1],
 					ueventLen,
 				)
@@ -359,7 +360,8 @@ func fillFixedInstances(fixedInstances map[int64][]instance.Instance, inst insta
 
 // deviceTaskBalance is used to balance the CPU load across containers running on a host.
 // It first checks if CGroup support is available and returns if it isn't.
-// It then retrieves the effective CPU list (the CPUs that are guaranteed to be online) and isolates any isolated CPUs.
+// It then retrieves the effective CPU list (the CPUs that are guaranteed to be online) and isolates any isolated CPUs
+// as well as the CPU threads reserved by instances using CPU isolation (`limits.cpu.pin_strategy=isolated`).
 // After that, it loads all instances of containers running on the node and iterates through them.
 //
 // For each container, it checks its CPU limits and determines whether it is pinned to specific CPUs or can use the load-balancing mechanism.
@@ -375,6 +377,7 @@ func fillFixedInstances(fixedInstances map[int64][]instance.Instance, inst insta
 // For the load-balanced containers, it sorts the available CPUs based on their usage count and assigns them to containers
 // in ascending order until the required number of CPUs have been assigned.
 // Finally, the pinning map is used to set the new CPU pinning for each container, updating it to the new balanced state.
+// Virtual machines without CPU pinning then get their vCPU threads restricted to the CPUs which aren't reserved.
 //
 // Overall, this function ensures that the CPU resources of the host are utilized effectively amongst all the containers running on it.
 func deviceTaskBalance(s *state.State) {
@@ -414,6 +417,23 @@ func deviceTaskBalance(s *state.State) {
 		return
 	}
 
+	// Iterate through the instances
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		logger.Error("Problem loading instances list", logger.Ctx{"err": err})
+		return
+	}
+
+	// Get CPU topology.
+	cpusTopology, err := resources.GetCPU()
+	if err != nil {
+		logger.Errorf("Unable to load system CPUs information: %v", err)
+		return
+	}
+
+	// Get the CPU threads reserved by instances using CPU isolation.
+	reservedCpus := instance.ReservedCPUs(instances, cpusTopology)
+
 	isolatedCpusInt := resources.GetCPUIsolated()
 	effectiveCpusSlice := []string{}
 	for _, id := range effectiveCpusInt {
@@ -421,6 +441,11 @@ func deviceTaskBalance(s *state.State) {
 			continue
 		}
 
+		_, reserved := reservedCpus[id]
+		if reserved {
+			continue
+		}
+
 		effectiveCpusSlice = append(effectiveCpusSlice, fmt.Sprintf("%d", id))
 	}
 
@@ -431,30 +456,21 @@ func deviceTaskBalance(s *state.State) {
 		return
 	}
 
-	// Iterate through the instances
-	instances, err := instance.LoadNodeAll(s, instancetype.Container)
-	if err != nil {
-		logger.Error("Problem loading instances list", logger.Ctx{"err": err})
-		return
-	}
-
-	// Get CPU topology.
-	cpusTopology, err := resources.GetCPU()
-	if err != nil {
-		logger.Errorf("Unable to load system CPUs information: %v", err)
-		return
-	}
-
 	// Build a map of NUMA node to CPU threads.
 	numaNodeToCPU := make(map[int64][]int64)
 	for _, cpu := range cpusTopology.Sockets {
 		for _, core := range cpu.Cores {
 			for _, thread := range core.Threads {
-				// Skip any isolated CPU thread.
+				// Skip any isolated or reserved CPU thread.
 				if slices.Contains(isolatedCpusInt, thread.ID) {
 					continue
 				}
 
+				_, reserved := reservedCpus[thread.ID]
+				if reserved {
+					continue
+				}
+
 				numaNodeToCPU[int64(thread.NUMANode)] = append(numaNodeToCPU[int64(thread.NUMANode)], thread.ID)
 			}
 		}
@@ -462,11 +478,35 @@ func deviceTaskBalance(s *state.State) {
 
 	fixedInstances := map[int64][]instance.Instance{}
 	balancedInstances := map[instance.Instance]int{}
+	vms := []instance.Instance{}
 	for _, c := range instances {
 		var numaCpus []int64
 		var numaCpusStr []string
 
+		// Virtual machines handle their own CPU pinning.
+		if c.Type() != instancetype.Container {
+			vms = append(vms, c)
+			continue
+		}
+
 		conf := c.ExpandedConfig()
+
+		// Containers using CPU isolation are pinned to their reserved CPU threads.
+		if conf["limits.cpu.pin_strategy"] == "isolated" && conf["volatile.cpu.isolated"] != "" {
+			if c.InitPID() <= 0 {
+				continue
+			}
+
+			isolatedCpus, err := resources.ParseCpuset(conf["volatile.cpu.isolated"])
+			if err != nil {
+				logger.Error("Error parsing reserved CPU set", logger.Ctx{"cpuset": conf["volatile.cpu.isolated"], "err": err})
+				continue
+			}
+
+			fillFixedInstances(fixedInstances, c, isolatedCpus, isolatedCpus, len(isolatedCpus), false)
+			continue
+		}
+
 		cpuNodes := conf["limits.cpu.nodes"]
 		if cpuNodes != "" {
 			if cpuNodes == "balanced" {
@@ -545,6 +585,17 @@ func deviceTaskBalance(s *state.State) {
 		usage[id] = cpu
 	}
 
+	// Reserved CPU threads can only be used by the instance holding them.
+	for id := range reservedCpus {
+		cpu := deviceTaskCPU{}
+		cpu.id = id
+		cpu.strId = fmt.Sprintf("%d", id)
+		count := 0
+		cpu.count = &count
+
+		usage[id] = cpu
+	}
+
 	for cpu, ctns := range fixedInstances {
 		c, ok := usage[cpu]
 		if !ok {
@@ -565,8 +616,8 @@ func deviceTaskBalance(s *state.State) {
 	}
 
 	sortedUsage := make(deviceTaskCPUs, 0)
-	for _, value := range usage {
-		sortedUsage = append(sortedUsage, value)
+	for _, id := range cpus {
+		sortedUsage = append(sortedUsage, usage[id])
 	}
 
 	for ctn, count := range balancedInstances {
@@ -608,6 +659,97 @@ func deviceTaskBalance(s *state.State) {
 			logger.Error("balance: Unable to set cpuset", logger.Ctx{"name": ctn.Name(), "err": err, "value": strings.Join(set, ",")})
 		}
 	}
+
+	// Keep the vCPUs of virtual machines without CPU pinning off the reserved CPU threads.
+	for _, vm := range vms {
+		pid := vm.InitPID()
+		if pid <= 0 {
+			continue
+		}
+
+		conf := vm.ExpandedConfig()
+		if conf["limits.cpu.pin_strategy"] == "isolated" && conf["volatile.cpu.isolated"] != "" {
+			continue
+		}
+
+		_, err := strconv.Atoi(conf["limits.cpu"])
+		if conf["limits.cpu"] != "" && err != nil {
+			continue
+		}
+
+		allowedCpus := []int64{}
+		cpuNodes := conf["limits.cpu.nodes"]
+		if cpuNodes == "balanced" {
+			cpuNodes = conf["volatile.cpu.nodes"]
+		}
+
+		if cpuNodes != "" {
+			numaNodeSet, err := resources.ParseNumaNodeSet(cpuNodes)
+			if err != nil {
+				logger.Error("Error parsing numa node set", logger.Ctx{"numaNodes": cpuNodes, "err": err})
+				continue
+			}
+
+			for _, numaNode := range numaNodeSet {
+				allowedCpus = append(allowedCpus, numaNodeToCPU[numaNode]...)
+			}
+		} else {
+			for _, id := range effectiveCpusInt {
+				_, reserved := reservedCpus[id]
+				if !reserved {
+					allowedCpus = append(allowedCpus, id)
+				}
+			}
+		}
+
+		if len(allowedCpus) == 0 {
+			continue
+		}
+
+		err = deviceTaskVCPUAffinity(pid, allowedCpus)
+		if err != nil {
+			logger.Error("balance: Unable to set vCPU affinity", logger.Ctx{"name": vm.Name(), "err": err})
+		}
+	}
+}
+
+// deviceTaskVCPUAffinity restricts the vCPU threads of a QEMU process to the given CPU threads.
+func deviceTaskVCPUAffinity(pid int, cpus []int64) error {
+	set := unix.CPUSet{}
+	for _, id := range cpus {
+		set.Set(int(id))
+	}
+
+	tasksPath := fmt.Sprintf("/proc/%d/task", pid)
+	tasks, err := os.ReadDir(tasksPath)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		// QEMU names its vCPU threads "CPU <index>/KVM".
+		comm, err := os.ReadFile(filepath.Join(tasksPath, task.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+
+		name := strings.TrimSpace(string(comm))
+		if !strings.HasPrefix(name, "CPU ") || !strings.HasSuffix(name, "/KVM") {
+			continue
+		}
+
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+
+		err = unix.SchedSetaffinity(tid, &set)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // deviceEventListener starts the event listener for resource scheduling.
@@ -660,11 +802,17 @@ func deviceEventListener(stateFunc func() *state.State) {
 
 // devicesRegister calls the Register() function on all supported devices so they receive events.
 // This also has the effect of actively reconnecting to any running VM monitor sockets.
+// Mediated GPU devices left behind by instances which stopped while the daemon was down get cleaned up.
 func devicesRegister(instances []instance.Instance) {
 	logger.Debug("Registering running instances")
 
 	for _, inst := range instances {
 		if !inst.IsRunning() { // For VMs this will also trigger a connection to the QMP socket if running.
+			err := device.GPUMdevCleanup(inst)
+			if err != nil {
+				logger.Warn("Failed cleaning up mediated GPU devices", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			}
+
 			continue
 		}
 
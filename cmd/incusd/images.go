@@ -416,9 +416,9 @@ func imgPostInstanceInfo(ctx context.Context, s *state.State, r *http.Request, r
 	metaWriter = internalIO.NewQuotaWriter(metaWriter, budget)
 	rootfsWriter = internalIO.NewQuotaWriter(rootfsWriter, budget)
 	if imageType != "split" {
-		meta, err = c.Export(metaWriter, nil, req.Properties, req.ExpiresAt, tracker)
+		meta, err = c.Export(metaWriter, nil, req.Properties, req.ExpiresAt, req.Stateful, tracker)
 	} else {
-		meta, err = c.Export(metaWriter, rootfsWriter, req.Properties, req.ExpiresAt, tracker)
+		meta, err = c.Export(metaWriter, rootfsWriter, req.Properties, req.ExpiresAt, req.Stateful, tracker)
 	}
 
 	// Clean up file handles.
@@ -1216,6 +1216,15 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return response.InternalError(errors.New("Invalid images JSON"))
 	}
 
+	// Publishing an instance exposes its data, so make sure it can be seen.
+	if !imageUpload && slices.Contains([]string{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) && req.Source.Name != "" {
+		err = instanceSourceAccessCheck(s, r, projectName, req.Source.Name)
+		if err != nil {
+			cleanup(builddir, post)
+			return response.SmartError(err)
+		}
+	}
+
 	/* Forward requests for containers on other nodes */
 	if !imageUpload && slices.Contains([]string{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) {
 		name := req.Source.Name
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,10 +13,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kballard/go-shellquote"
 	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -37,6 +41,28 @@ import (
 
 // Helper functions
 
+// instanceSourceAccessCheck checks that the requestor can view the instance (or the parent of the snapshot)
+// used as the source of a copy or image publication, as this exposes the source's data.
+func instanceSourceAccessCheck(s *state.State, r *http.Request, projectName string, name string) error {
+	instName, _, _ := api.GetParentAndSnapshotName(name)
+
+	return s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectInstance(projectName, instName), auth.EntitlementCanView)
+}
+
+// instanceNameAccessCheck checks that the name given to a new or renamed instance matches the instance name
+// patterns the requestor's certificate or SSH key is restricted to, if any.
+func instanceNameAccessCheck(d *Daemon, r *http.Request, name string) error {
+	if auth.InstanceNameAllowed(r, d.clientCerts, name) {
+		return nil
+	}
+
+	if name == "" {
+		return api.StatusErrorf(http.StatusForbidden, "An instance name must be provided when restricted to specific instances")
+	}
+
+	return api.StatusErrorf(http.StatusForbidden, "Instance name %q isn't allowed", name)
+}
+
 // instanceCreateAsEmpty creates an empty instance.
 func instanceCreateAsEmpty(s *state.State, args db.InstanceArgs, op *operations.Operation) (instance.Instance, error) {
 	reverter := revert.New()
@@ -158,6 +184,16 @@ func instanceCreateFromImage(ctx context.Context, s *state.State, img *api.Image
 	// Set the BaseImage field (regardless of previous value).
 	args.BaseImage = img.Fingerprint
 
+	// Images published with their state resume from the included checkpoint on first start.
+	if args.Type == instancetype.Container && util.IsTrue(img.Properties["stateful"]) {
+		args.Stateful = true
+
+		_, ok := args.Config["migration.stateful"]
+		if !ok {
+			args.Config["migration.stateful"] = "true"
+		}
+	}
+
 	// Create the instance.
 	inst, instOp, cleanup, err := instance.CreateInternal(s, args, op, true, true)
 	if err != nil {
@@ -302,6 +338,15 @@ func instanceRebuildFromImage(ctx context.Context, s *state.State, r *http.Reque
 	return nil
 }
 
+func instanceRebuildFromBackup(inst instance.Instance, bInfo *backup.Info, backupFile io.ReadSeeker, op *operations.Operation) error {
+	err := inst.RebuildFromBackup(*bInfo, backupFile, op)
+	if err != nil {
+		return fmt.Errorf("Failed rebuilding instance from backup: %w", err)
+	}
+
+	return nil
+}
+
 func instanceRebuildFromEmpty(inst instance.Instance, op *operations.Operation) error {
 	err := inst.Rebuild(nil, op) // Rebuild as empty.
 	if err != nil {
@@ -320,6 +365,7 @@ type instanceCreateAsCopyOpts struct {
 	refreshExcludeOlder  bool              // During refresh, exclude source snapshots earlier than latest target snapshot
 	applyTemplateTrigger bool              // Apply deferred TemplateTriggerCopy.
 	allowInconsistent    bool              // Ignore some copy errors
+	allowRunning         bool              // Copy a running instance from a quiesced temporary snapshot.
 }
 
 // instanceCreateAsCopy create a new instance by copying from an existing instance.
@@ -329,6 +375,11 @@ func instanceCreateAsCopy(s *state.State, opts instanceCreateAsCopyOpts, op *ope
 	var err error
 	var cleanup revert.Hook
 
+	// Copy running instances from a consistent temporary snapshot rather than from their live volume.
+	if opts.allowRunning && !opts.sourceInstance.IsSnapshot() && opts.sourceInstance.IsRunning() {
+		return instanceCreateAsCopyFromRunning(s, opts, op)
+	}
+
 	reverter := revert.New()
 	defer reverter.Fail()
 
@@ -525,6 +576,115 @@ func instanceCreateAsCopy(s *state.State, opts instanceCreateAsCopyOpts, op *ope
 	return inst, nil
 }
 
+// instanceCreateAsCopyFromRunning copies a running instance by taking a temporary snapshot of it while its
+// filesystems are quiesced and copying from that snapshot, leaving the source instance running throughout.
+func instanceCreateAsCopyFromRunning(s *state.State, opts instanceCreateAsCopyOpts, op *operations.Operation) (instance.Instance, error) {
+	source := opts.sourceInstance
+
+	if opts.refresh && opts.instanceOnly {
+		return nil, errors.New("Running instances can only be refreshed together with their snapshots")
+	}
+
+	snapName := "copy-" + strings.Split(uuid.New().String(), "-")[0]
+
+	err := instanceSnapshotQuiesced(source, snapName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating temporary snapshot: %w", err)
+	}
+
+	tmpSnap, err := instance.LoadByProjectAndName(s, source.Project().Name, source.Name()+internalInstance.SnapshotDelimiter+snapName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = tmpSnap.Delete(true) }()
+
+	opts.allowRunning = false
+
+	// Without snapshots, the temporary snapshot is the only thing to copy.
+	if opts.instanceOnly {
+		opts.sourceInstance = tmpSnap
+
+		return instanceCreateAsCopy(s, opts, op)
+	}
+
+	// Otherwise copy the instance along with all its snapshots (including the temporary one) without
+	// freezing the source, then restore the copied volume to the consistent state of the temporary snapshot.
+	opts.allowInconsistent = true
+
+	inst, err := instanceCreateAsCopy(s, opts, op)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if !opts.refresh {
+		reverter.Add(func() { _ = inst.Delete(true) })
+	}
+
+	targetSnap, err := instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+snapName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	err = pool.RestoreInstanceSnapshot(inst, targetSnap, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed restoring temporary snapshot: %w", err)
+	}
+
+	err = targetSnap.Delete(true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed deleting temporary snapshot: %w", err)
+	}
+
+	err = inst.UpdateBackupFile()
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Success()
+
+	return inst, nil
+}
+
+// instanceSnapshotQuiesced takes a snapshot of a running instance while its filesystems are quiesced.
+// Containers are frozen for the duration of the snapshot while virtual machines get their filesystems
+// frozen by the agent, falling back to a crash-consistent snapshot if the agent isn't available.
+func instanceSnapshotQuiesced(inst instance.Instance, name string) error {
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "snapshot": name})
+
+	vm, isVM := inst.(instance.VM)
+	if isVM {
+		thaw, err := vm.FreezeFilesystems()
+		if err != nil {
+			l.Warn("Failed freezing guest filesystems, snapshot will only be crash-consistent", logger.Ctx{"err": err})
+		} else {
+			defer func() {
+				err := thaw()
+				if err != nil {
+					l.Error("Failed thawing guest filesystems", logger.Ctx{"err": err})
+				}
+			}()
+		}
+	} else if !inst.IsFrozen() {
+		err := inst.Freeze()
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = inst.Unfreeze() }()
+	}
+
+	return inst.Snapshot(name, time.Time{}, false)
+}
+
 // Load all instances of this nodes under the given project.
 func instanceLoadNodeProjectAll(ctx context.Context, s *state.State, projectName string) ([]instance.Instance, error) {
 	var err error
@@ -586,6 +746,39 @@ func autoCreateInstanceSnapshots(ctx context.Context, s *state.State, instances
 	return nil
 }
 
+// instanceSnapshotsOutsideRetention returns the snapshots of the instance which fall outside of its
+// snapshots.keep_* retention policy. Snapshots with an expiry date are only subject to that expiry.
+func instanceSnapshotsOutsideRetention(inst instance.Instance) ([]instance.Instance, error) {
+	allSnapshots, err := inst.Snapshots()
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading snapshots: %w", err)
+	}
+
+	snapshots := make([]instance.Instance, 0, len(allSnapshots))
+	creationDates := make([]time.Time, 0, len(allSnapshots))
+	for _, snapshot := range allSnapshots {
+		if !snapshot.ExpiryDate().IsZero() {
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+		creationDates = append(creationDates, snapshot.CreationDate())
+	}
+
+	pruned, err := snapshotsOutsideRetention(creationDates, inst.ExpandedConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]instance.Instance, 0, len(pruned))
+	for _, i := range pruned {
+		logger.Debug("Scheduling instance snapshot retention pruning", logger.Ctx{"instance": snapshots[i].Name(), "project": inst.Project().Name})
+		result = append(result, snapshots[i])
+	}
+
+	return result, nil
+}
+
 var instSnapshotsPruneRunning = sync.Map{}
 
 func pruneExpiredInstanceSnapshots(ctx context.Context, snapshots []instance.Instance) error {
@@ -617,7 +810,7 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 	// `f` creates new scheduled instance snapshots and then, prune the expired ones
 	f := func(ctx context.Context) {
 		s := d.State()
-		var instances, expiredSnapshotInstances []instance.Instance
+		var instances, expiredSnapshotInstances, retentionInstances []instance.Instance
 
 		// Get list of expired instance snapshots for this local member.
 		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -705,6 +898,11 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 					return fmt.Errorf("Failed loading instance %q (project %q) for snapshot task: %w", dbInst.Name, dbInst.Project, err)
 				}
 
+				// Check if instance has a snapshot retention policy.
+				if snapshotRetentionEnabled(inst.ExpandedConfig()) {
+					retentionInstances = append(retentionInstances, inst)
+				}
+
 				// Check if instance has snapshot schedule enabled.
 				schedule, ok := inst.ExpandedConfig()["snapshots.schedule"]
 				if !ok || schedule == "" {
@@ -732,6 +930,17 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 			return
 		}
 
+		// Add the snapshots which fall outside of the retention policy of their instance.
+		for _, inst := range retentionInstances {
+			snapshots, err := instanceSnapshotsOutsideRetention(inst)
+			if err != nil {
+				logger.Error("Failed evaluating instance snapshot retention", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+				continue
+			}
+
+			expiredSnapshotInstances = append(expiredSnapshotInstances, snapshots...)
+		}
+
 		// Handle snapshot expiry first before creating new ones to reduce the chances of running out of
 		// disk space.
 		if len(expiredSnapshotInstances) > 0 {
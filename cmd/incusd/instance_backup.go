@@ -1,12 +1,15 @@
 package main
 
 import (
+	"archive/tar"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/jmap"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
@@ -26,6 +30,8 @@ import (
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 // swagger:operation GET /1.0/instances/{name}/backups instances instance_backups_get
@@ -306,6 +312,17 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Backup names may not contain slashes"))
 	}
 
+	// Validate the rate limit.
+	err = validate.Optional(validate.IsSize)(req.Throttle)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid throttle %q: %w", req.Throttle, err))
+	}
+
+	err = backupTargetCheck(r.Context(), s, r, projectName, req.Target)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	fullName := name + internalInstance.SnapshotDelimiter + req.Name
 	instanceOnly := req.InstanceOnly
 
@@ -318,6 +335,7 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 			InstanceOnly:         instanceOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			Throttle:             req.Throttle,
 		}
 
 		// Create the backup.
@@ -328,14 +346,26 @@ func instanceBackupsPost(d *Daemon, r *http.Request) response.Response {
 
 		// Upload it if requested.
 		if req.Target != nil {
+			targetConfig, targetPath, err := backupTargetConfig(context.TODO(), s, projectName, name, req.Target)
+			if err != nil {
+				return err
+			}
+
 			// Load the backup.
 			entry, err := instance.BackupLoadByName(s, projectName, fullName)
 			if err != nil {
 				return err
 			}
 
+			throttle, err := backupThrottle(s, req.Throttle)
+			if err != nil {
+				return err
+			}
+
+			entry.SetThrottle(throttle)
+
 			// Upload it.
-			err = entry.Upload(req.Target)
+			err = entry.Upload(targetConfig, targetPath)
 			if err != nil {
 				return err
 			}
@@ -621,12 +651,110 @@ func instanceBackupDelete(d *Daemon, r *http.Request) response.Response {
 	return operations.OperationResponse(op)
 }
 
+// swagger:operation POST /1.0/instances/{name}/backups/{backup}/secret instances instance_backup_secret_post
+//
+//	Generate a backup download secret
+//
+//	Generates a secret allowing untrusted clients to download the backup file until it expires.
+//	The operation metadata contains the secret, its expiry and the export URL to use.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: secret
+//	    description: Secret request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/BackupSecretPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceBackupSecretPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.BackupSecretPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response.BadRequest(err)
+	}
+
+	// Ensure the backup exists.
+	_, err = instance.BackupLoadByName(s, projectName, name+internalInstance.SnapshotDelimiter+backupName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName)}
+
+	exportURL := api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName, "export").Project(projectName)
+
+	return backupSecretCreate(s, r, projectName, operationtype.BackupToken, resources, exportURL, req.Expiry)
+}
+
+// allowInstanceBackupExport is an AccessHandler which allows trusted clients with the can_manage_backups entitlement
+// on the instance, as well as untrusted clients holding a valid backup secret.
+func allowInstanceBackupExport(d *Daemon, r *http.Request) response.Response {
+	if d.checkTrustedClient(r) == nil {
+		return allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanManageBackups, "name")(d, r)
+	}
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return allowBackupSecret(d.State(), r, request.ProjectParam(r), operationtype.BackupToken, api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName))
+}
+
 // swagger:operation GET /1.0/instances/{name}/backups/{backup}/export instances instance_backup_export
 //
 //	Get the raw backup file(s)
 //
 //	Download the raw backup file(s) from the server.
 //
+//	Range requests are supported, allowing interrupted downloads to be resumed.
+//	The SHA256 checksum of the file is returned in the `ETag` and `X-Incus-Checksum` headers.
+//
+//	Non-optimized backups of virtual machines can also be downloaded as a `qcow2` or `vmdk`
+//	disk image of their root disk, converted when requested.
+//
 //	---
 //	produces:
 //	  - application/octet-stream
@@ -636,9 +764,25 @@ func instanceBackupDelete(d *Daemon, r *http.Request) response.Response {
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: secret
+//	    description: Secret allowing an untrusted client to download the backup
+//	    type: string
+//	  - in: query
+//	    name: format
+//	    description: Disk image format to convert the virtual machine disk to (`qcow2` or `vmdk`)
+//	    type: string
+//	    example: qcow2
+//	  - in: query
+//	    name: throttle
+//	    description: Rate limit for the download in bytes per second (overrides the server setting)
+//	    type: string
+//	    example: 50MB
 //	responses:
 //	  "200":
 //	    description: Raw image data
+//	  "206":
+//	    description: Partial raw image data (range request)
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
 //	  "500":
@@ -677,11 +821,283 @@ func instanceBackupExportGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	var backupRow db.InstanceBackup
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		backupRow, err = tx.GetInstanceBackup(ctx, projectName, fullName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Expose the checksum of the tarball, allowing clients to resume interrupted downloads through
+	// range requests (If-Range uses the ETag) and to verify the result.
+	headers := map[string]string{}
+	if backupRow.Checksum != "" {
+		headers["ETag"] = fmt.Sprintf("%q", backupRow.Checksum)
+		headers["X-Incus-Checksum"] = "sha256:" + backupRow.Checksum
+	}
+
+	tarPath := internalUtil.VarPath("backups", "instances", project.Instance(projectName, backup.Name()))
+
+	// Convert virtual machine disks to a disk image if requested.
+	format := request.QueryParam(r, "format")
+	if format != "" {
+		ent, err := backupExportDiskImage(s, tarPath, "backup/virtual-machine.img", format, fmt.Sprintf("%s.%s", name, format))
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = backupThrottleExport(s, request.QueryParam(r, "throttle"), ent)
+		if err != nil {
+			ent.Cleanup()
+			return response.SmartError(err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupRetrieved.Event(fullName, backup.Instance(), nil))
+
+		return response.FileResponse(r, []response.FileResponseEntry{*ent}, nil)
+	}
+
 	ent := response.FileResponseEntry{
-		Path: internalUtil.VarPath("backups", "instances", project.Instance(projectName, backup.Name())),
+		Path: tarPath,
+	}
+
+	err = backupThrottleExport(s, request.QueryParam(r, "throttle"), &ent)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Resumed downloads (range requests) don't emit a new event.
+	if r.Header.Get("Range") == "" {
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupRetrieved.Event(fullName, backup.Instance(), nil))
+	}
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, headers)
+}
+
+// swagger:operation GET /1.0/instances/{name}/backups/{backup}/files instances instance_backup_files_get
+//
+//	Get the backup content
+//
+//	Lists the files contained in the backup tarball without restoring it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Backup content
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of files
+//	          items:
+//	            $ref: "#/definitions/BackupFile"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceBackupFilesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	fullName := name + internalInstance.SnapshotDelimiter + backupName
+	entry, err := instance.BackupLoadByName(s, projectName, fullName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	files, err := backupListFiles(s, internalUtil.VarPath("backups", "instances", project.Instance(projectName, entry.Name())))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, files)
+}
+
+// swagger:operation POST /1.0/instances/{name}/backups/{backup}/files instances instance_backup_files_post
+//
+//	Restore files from the backup
+//
+//	Extracts a file, symlink or directory from the backup tarball into the instance,
+//	without restoring the whole backup.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: files
+//	    description: Path to restore
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupFilesPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceBackupFilesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to a container on a different node
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	req := api.BackupFilesPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Source == "" {
+		return response.BadRequest(errors.New("No source path provided"))
+	}
+
+	// Default to the original location of files from the root filesystem.
+	if req.Target == "" {
+		source := strings.Trim(req.Source, "/")
+		if source != "backup/container/rootfs" && !strings.HasPrefix(source, "backup/container/rootfs/") {
+			return response.BadRequest(errors.New("No target path provided"))
+		}
+
+		req.Target = path.Join("/", strings.TrimPrefix(source, "backup/container/rootfs"))
+	}
+
+	fullName := name + internalInstance.SnapshotDelimiter + backupName
+	entry, err := instance.BackupLoadByName(s, projectName, fullName)
+	if err != nil {
+		return response.SmartError(err)
 	}
 
-	s.Events.SendLifecycle(projectName, lifecycle.InstanceBackupRetrieved.Event(fullName, backup.Instance(), nil))
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tarPath := internalUtil.VarPath("backups", "instances", project.Instance(projectName, entry.Name()))
+
+	run := func(op *operations.Operation) error {
+		client, err := inst.FileSFTP()
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = client.Close() }()
+
+		// Apply the project's file access restrictions to every restored entry.
+		check := func(dest string, hdr *tar.Header) error {
+			if hdr.Typeflag == tar.TypeSymlink {
+				err := instanceFileCheckSymlinkCreate(inst)
+				if err != nil {
+					return err
+				}
+			}
 
-	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+			return instanceFileCheckPath(client, inst, dest, false)
+		}
+
+		err = backupRestoreFiles(s, tarPath, client, req.Source, req.Target, check)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceFilePushed.Event(inst, logger.Ctx{"path": req.Target}))
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name, "backups", backupName)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
 }
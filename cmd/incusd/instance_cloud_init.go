@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/sftp"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// cloud-init writes its progress to status.json and, once done, its outcome to result.json.
+const (
+	cloudInitStatusPath = "/run/cloud-init/status.json"
+	cloudInitResultPath = "/run/cloud-init/result.json"
+)
+
+// cloudInitStage is a boot stage entry of the cloud-init status file.
+type cloudInitStage struct {
+	Errors []string `json:"errors"`
+}
+
+// swagger:operation GET /1.0/instances/{name}/cloud-init instances instance_cloud_init_get
+//
+//	Get the cloud-init status
+//
+//	Gets whether cloud-init has finished running in the instance, its result and any reported errors.
+//	The instance must be running (and have its agent running for virtual machines).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: cloud-init status
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceCloudInit"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceCloudInitGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	// Handle requests targeted to an instance on a different node.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance must be running to get its cloud-init status"))
+	}
+
+	// Files are read through forkfile for containers and through the agent for virtual machines.
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	status, err := instanceCloudInitStatus(client)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, status)
+}
+
+// instanceCloudInitStatus works out the cloud-init status from the files it writes in the instance.
+func instanceCloudInitStatus(client *sftp.Client) (*api.InstanceCloudInit, error) {
+	status := &api.InstanceCloudInit{Errors: []string{}}
+
+	// Once done, cloud-init writes its final result.
+	var result struct {
+		V1 struct {
+			Datasource string   `json:"datasource"`
+			Errors     []string `json:"errors"`
+		} `json:"v1"`
+	}
+
+	found, err := instanceCloudInitReadJSON(client, cloudInitResultPath, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		status.Datasource = result.V1.Datasource
+		status.Status = "done"
+		if len(result.V1.Errors) > 0 {
+			status.Status = "error"
+			status.Errors = result.V1.Errors
+		}
+
+		return status, nil
+	}
+
+	// Otherwise, look at the progress of the individual stages.
+	var progress struct {
+		V1 map[string]json.RawMessage `json:"v1"`
+	}
+
+	found, err = instanceCloudInitReadJSON(client, cloudInitStatusPath, &progress)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		status.Status = "not-started"
+		return status, nil
+	}
+
+	status.Status = "running"
+
+	for _, key := range slices.Sorted(maps.Keys(progress.V1)) {
+		if key == "datasource" {
+			_ = json.Unmarshal(progress.V1[key], &status.Datasource)
+			continue
+		}
+
+		var stage cloudInitStage
+		err := json.Unmarshal(progress.V1[key], &stage)
+		if err != nil {
+			// Not a stage entry.
+			continue
+		}
+
+		status.Errors = append(status.Errors, stage.Errors...)
+	}
+
+	return status, nil
+}
+
+// instanceCloudInitReadJSON reads and decodes a JSON file from the instance, returning false if it doesn't exist.
+func instanceCloudInitReadJSON(client *sftp.Client, path string, target any) (bool, error) {
+	file, err := client.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("Failed opening %q: %w", path, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return false, fmt.Errorf("Failed reading %q: %w", path, err)
+	}
+
+	err = json.Unmarshal(content, target)
+	if err != nil {
+		return false, fmt.Errorf("Failed parsing %q: %w", path, err)
+	}
+
+	return true, nil
+}
@@ -66,7 +66,7 @@ type consoleWs struct {
 	// terminal height
 	height int
 
-	// channel type (either console or vga)
+	// channel type (either console, vga or vnc)
 	protocol string
 }
 
@@ -87,7 +87,7 @@ func (s *consoleWs) connect(_ *operations.Operation, r *http.Request, w http.Res
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
 		return s.connectConsole(r, w)
-	case instance.ConsoleTypeVGA:
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.connectVGA(r, w)
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
@@ -164,7 +164,7 @@ func (s *consoleWs) connectVGA(r *http.Request, w http.ResponseWriter) error {
 
 		logger.Debug("VGA dynamic websocket connected")
 
-		console, _, err := s.instance.Console("vga")
+		console, _, err := s.instance.Console(s.protocol)
 		if err != nil {
 			_ = conn.Close()
 			return err
@@ -202,7 +202,7 @@ func (s *consoleWs) do(op *operations.Operation) error {
 	switch s.protocol {
 	case instance.ConsoleTypeConsole:
 		return s.doConsole()
-	case instance.ConsoleTypeVGA:
+	case instance.ConsoleTypeVGA, instance.ConsoleTypeVNC:
 		return s.doVGA()
 	default:
 		return fmt.Errorf("Unknown protocol %q", s.protocol)
@@ -492,7 +492,7 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Basic parameter validation.
-	if !slices.Contains([]string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA}, post.Type) {
+	if !slices.Contains([]string{instance.ConsoleTypeConsole, instance.ConsoleTypeVGA, instance.ConsoleTypeVNC}, post.Type) {
 		return response.BadRequest(fmt.Errorf("Unknown console type %q", post.Type))
 	}
 
@@ -505,6 +505,10 @@ func instanceConsolePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("VGA console is only supported by virtual machines"))
 	}
 
+	if post.Type == instance.ConsoleTypeVNC && inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("VNC console is only supported by virtual machines"))
+	}
+
 	if !inst.IsRunning() {
 		return response.BadRequest(errors.New("Instance is not running"))
 	}
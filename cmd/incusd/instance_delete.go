@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
 )
 
 // swagger:operation DELETE /1.0/instances/{name} instances instance_delete
@@ -79,8 +81,28 @@ func instanceDelete(d *Daemon, r *http.Request) response.Response {
 	}
 
 	run := func(op *operations.Operation) error {
+		reverter := revert.New()
+		defer reverter.Fail()
+
 		inst.SetOperation(op)
-		return inst.Delete(false)
+
+		// Keep a copy of the instance in the trash so the deletion can be undone.
+		if trashEnabled(s) {
+			cleanup, err := trashInstance(s, inst, op)
+			if err != nil {
+				return fmt.Errorf("Failed moving instance to the trash: %w", err)
+			}
+
+			reverter.Add(cleanup)
+		}
+
+		err := inst.Delete(false)
+		if err != nil {
+			return err
+		}
+
+		reverter.Success()
+		return nil
 	}
 
 	resources := map[string][]api.URL{}
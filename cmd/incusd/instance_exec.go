@@ -191,6 +191,19 @@ func (s *execWs) do(op *operations.Operation) error {
 	var stdout *os.File
 	var stderr *os.File
 
+	// Capture the session's input and output if required by the project.
+	captureStreams := []string{"stdin", "stdout", "stderr"}
+	if s.req.Interactive {
+		captureStreams = []string{"stdin", "stdout"}
+	}
+
+	capture, err := newExecCapture(s.instance, op.ID(), captureStreams...)
+	if err != nil {
+		return fmt.Errorf("Failed setting up exec session capture: %w", err)
+	}
+
+	defer capture.Close()
+
 	if s.req.Interactive {
 		if s.instance.Type() == instancetype.Container {
 			// For containers, we setup a PTY on the server.
@@ -263,6 +276,8 @@ func (s *execWs) do(op *operations.Operation) error {
 	waitAttachedChildIsDead, markAttachedChildIsDead := context.WithCancel(context.Background())
 	var wgEOF sync.WaitGroup
 
+	startedAt := time.Now()
+
 	// Define a function to clean up TTYs and sockets when done.
 	finisher := func(cmdResult int, cmdErr error) error {
 		// Cancel this before closing the control connection so control handler can detect command ending.
@@ -297,6 +312,8 @@ func (s *execWs) do(op *operations.Operation) error {
 			cmdErr = nil
 		}
 
+		execAuditEvent(s.s, s.instance, s.req, startedAt, cmdResult, capture)
+
 		metadata := jmap.Map{"return": cmdResult}
 
 		err = op.ExtendMetadata(metadata)
@@ -433,10 +450,10 @@ func (s *execWs) do(op *operations.Operation) error {
 			if s.instance.Type() == instancetype.Container {
 				// For containers, we are running the command via the locally managed PTY and so
 				// need to use the same PTY handle for both read and write.
-				readDone, writeDone = ws.Mirror(conn, linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0]))
+				readDone, writeDone = ws.Mirror(conn, capture.ReadWriteCloser("stdin", "stdout", linux.NewExecWrapper(waitAttachedChildIsDead, ptys[0])))
 			} else {
-				readDone = ws.MirrorRead(conn, ptys[execWSStdout])
-				writeDone = ws.MirrorWrite(conn, ttys[execWSStdin])
+				readDone = ws.MirrorRead(conn, capture.Reader("stdout", ptys[execWSStdout]))
+				writeDone = ws.MirrorWrite(conn, capture.Writer("stdin", ttys[execWSStdin]))
 			}
 
 			readErr = <-readDone
@@ -490,10 +507,10 @@ func (s *execWs) do(op *operations.Operation) error {
 				}
 
 				if i == execWSStdin {
-					err = <-ws.MirrorWrite(conn, ttys[i])
+					err = <-ws.MirrorWrite(conn, capture.Writer("stdin", ttys[i]))
 					_ = ttys[i].Close()
 				} else {
-					err = <-ws.MirrorRead(conn, linux.NewExecWrapper(waitAttachedChildIsDead, ptys[i]))
+					err = <-ws.MirrorRead(conn, capture.Reader(captureStreams[i], linux.NewExecWrapper(waitAttachedChildIsDead, ptys[i])))
 					_ = ptys[i].Close()
 					wgEOF.Done()
 				}
@@ -749,9 +766,27 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		// Capture the output if required by the project and not already recorded.
+		var capture *execCapture
+		if !post.RecordOutput {
+			capture, err = newExecCapture(inst, op.ID(), "stdout", "stderr")
+			if err != nil {
+				return fmt.Errorf("Failed setting up exec session capture: %w", err)
+			}
+
+			defer capture.Close()
+
+			if capture != nil {
+				stdout = capture.File("stdout")
+				stderr = capture.File("stderr")
+			}
+		}
+
 		// Run the command.
+		startedAt := time.Now()
 		cmd, err := inst.Exec(post, nil, stdout, stderr)
 		if err != nil {
+			execAuditEvent(s, inst, post, startedAt, -1, capture)
 			return err
 		}
 
@@ -761,6 +796,8 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		exitStatus, cmdErr := cmd.Wait()
 		l.Debug("Instance process stopped", logger.Ctx{"err": cmdErr, "exitStatus": exitStatus})
 
+		execAuditEvent(s, inst, post, startedAt, exitStatus, capture)
+
 		metadata["return"] = exitStatus
 		err = op.ExtendMetadata(metadata)
 		if err != nil {
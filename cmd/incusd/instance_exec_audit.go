@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// execCapture records the input and output of an exec session when the project has exec.capture enabled.
+type execCapture struct {
+	inst  instance.Instance
+	files map[string]*os.File
+}
+
+// newExecCapture returns an execCapture for the given streams (stdin, stdout or stderr) of an exec session.
+// If capturing isn't enabled for the instance's project, nil is returned.
+func newExecCapture(inst instance.Instance, opID string, streams ...string) (*execCapture, error) {
+	if !util.IsTrue(inst.Project().Config["exec.capture"]) {
+		return nil, nil
+	}
+
+	// Ensure exec-output directory exists.
+	execOutputDir := inst.ExecOutputPath()
+	err := os.Mkdir(execOutputDir, 0o600)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return nil, err
+	}
+
+	c := &execCapture{
+		inst:  inst,
+		files: make(map[string]*os.File, len(streams)),
+	}
+
+	for _, stream := range streams {
+		f, err := os.OpenFile(filepath.Join(execOutputDir, fmt.Sprintf("exec_%s.%s", opID, stream)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+
+		c.files[stream] = f
+	}
+
+	return c, nil
+}
+
+// File returns the capture file of the given stream (nil if not captured).
+func (c *execCapture) File(stream string) *os.File {
+	if c == nil {
+		return nil
+	}
+
+	return c.files[stream]
+}
+
+// Reader returns a reader copying the data read from r to the capture file of the given stream.
+func (c *execCapture) Reader(stream string, r io.Reader) io.Reader {
+	f := c.File(stream)
+	if f == nil {
+		return r
+	}
+
+	return &execCaptureReader{r: r, f: f}
+}
+
+// Writer returns a writer copying the data written to w to the capture file of the given stream.
+func (c *execCapture) Writer(stream string, w io.Writer) io.Writer {
+	f := c.File(stream)
+	if f == nil {
+		return w
+	}
+
+	return &execCaptureWriter{w: w, f: f}
+}
+
+// ReadWriteCloser returns a ReadWriteCloser copying the data read from rwc to the capture file of the output stream
+// and the data written to it to the capture file of the input stream.
+func (c *execCapture) ReadWriteCloser(input string, output string, rwc io.ReadWriteCloser) io.ReadWriteCloser {
+	if c == nil {
+		return rwc
+	}
+
+	return &execCaptureReadWriteCloser{
+		Reader: c.Reader(output, rwc),
+		Writer: c.Writer(input, rwc),
+		Closer: rwc,
+	}
+}
+
+// URLs returns the API URLs of the capture files.
+func (c *execCapture) URLs() []string {
+	if c == nil {
+		return nil
+	}
+
+	urls := make([]string, 0, len(c.files))
+	for _, stream := range []string{"stdin", "stdout", "stderr"} {
+		f, ok := c.files[stream]
+		if !ok {
+			continue
+		}
+
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "instances", c.inst.Name(), "logs", "exec-output", filepath.Base(f.Name())).Project(c.inst.Project().Name).String())
+	}
+
+	return urls
+}
+
+// Close closes the capture files.
+func (c *execCapture) Close() {
+	if c == nil {
+		return
+	}
+
+	for _, f := range c.files {
+		_ = f.Close()
+	}
+}
+
+type execCaptureReadWriteCloser struct {
+	io.Reader
+	io.Writer
+	io.Closer
+}
+
+// execCaptureReader copies the data read from a stream to a capture file.
+// Failures to write to the capture file don't affect the stream.
+type execCaptureReader struct {
+	r io.Reader
+	f *os.File
+}
+
+func (c *execCaptureReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		_, _ = c.f.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// execCaptureWriter copies the data written to a stream to a capture file.
+// Failures to write to the capture file don't affect the stream.
+type execCaptureWriter struct {
+	w io.Writer
+	f *os.File
+}
+
+func (c *execCaptureWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	if n > 0 {
+		_, _ = c.f.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// execAuditEvent sends the lifecycle event recording the completion of an exec session.
+func execAuditEvent(s *state.State, inst instance.Instance, req api.InstanceExecPost, startedAt time.Time, exitStatus int, capture *execCapture) {
+	ctx := logger.Ctx{
+		"command":     req.Command,
+		"interactive": req.Interactive,
+		"user":        req.User,
+		"started_at":  startedAt,
+		"finished_at": time.Now(),
+		"return":      exitStatus,
+	}
+
+	output := capture.URLs()
+	if len(output) > 0 {
+		ctx["output"] = output
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceExecFinished.Event(inst, ctx))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"archive/tar"
 	"bytes"
 	"errors"
 	"fmt"
@@ -20,12 +21,14 @@ import (
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
@@ -67,6 +70,23 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 		path = "/" + path
 	}
 
+	// Only operate on clean paths when file access is restricted so the checked path matches the accessed one.
+	p := inst.Project()
+	if len(project.GetRestrictedFilesPaths(&p)) > 0 {
+		path = filepath.Clean(path)
+	}
+
+	if util.IsTrue(r.FormValue("recursive")) {
+		switch r.Method {
+		case "GET":
+			return instanceFileGetRecursive(s, inst, path, r)
+		case "POST":
+			return instanceFilePostRecursive(s, inst, path, r)
+		default:
+			return response.BadRequest(fmt.Errorf("Recursive mode isn't supported for method %q", r.Method))
+		}
+	}
+
 	switch r.Method {
 	case "GET":
 		return instanceFileGet(s, inst, path, r)
@@ -86,11 +106,13 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 //	Get a file
 //
 //	Gets the file content. If it's a directory, a json list of files will be returned instead.
+//	When recursive is set, a tarball of the file or directory tree is returned instead.
 //
 //	---
 //	produces:
 //	  - application/json
 //	  - application/octet-stream
+//	  - application/x-tar
 //	parameters:
 //	  - in: query
 //	    name: path
@@ -98,6 +120,11 @@ func instanceFileHandler(d *Daemon, r *http.Request) response.Response {
 //	    type: string
 //	    example: default
 //	  - in: query
+//	    name: recursive
+//	    description: Whether to retrieve the whole tree as a tarball
+//	    type: boolean
+//	    example: true
+//	  - in: query
 //	    name: project
 //	    description: Project name
 //	    type: string
@@ -161,6 +188,12 @@ func instanceFileGet(s *state.State, inst instance.Instance, path string, r *htt
 
 	reverter.Add(func() { _ = client.Close() })
 
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Get the file stats.
 	stat, err := client.Lstat(path)
 	if err != nil {
@@ -324,6 +357,12 @@ func instanceFileHead(_ *state.State, inst instance.Instance, path string, _ *ht
 
 	reverter.Add(func() { _ = client.Close() })
 
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Get the file stats.
 	stat, err := client.Lstat(path)
 	if err != nil {
@@ -371,10 +410,12 @@ func instanceFileHead(_ *state.State, inst instance.Instance, path string, _ *ht
 //	Create or replace a file
 //
 //	Creates a new file in the instance.
+//	When recursive is set, the body is a tarball which gets unpacked into the target directory.
 //
 //	---
 //	consumes:
 //	  - application/octet-stream
+//	  - application/x-tar
 //	produces:
 //	  - application/json
 //	parameters:
@@ -384,6 +425,11 @@ func instanceFileHead(_ *state.State, inst instance.Instance, path string, _ *ht
 //	    type: string
 //	    example: default
 //	  - in: query
+//	    name: recursive
+//	    description: Whether the body is a tarball to unpack into the target directory
+//	    type: boolean
+//	    example: true
+//	  - in: query
 //	    name: project
 //	    description: Project name
 //	    type: string
@@ -448,6 +494,12 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 		return response.BadRequest(fmt.Errorf("Bad file write mode: %s", write))
 	}
 
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, type_ == "file")
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check if the file already exists.
 	_, err = client.Stat(path)
 	exists := err == nil
@@ -500,6 +552,11 @@ func instanceFilePost(s *state.State, inst instance.Instance, path string, r *ht
 		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFilePushed.Event(inst, logger.Ctx{"path": path}))
 		return response.EmptySyncResponse
 	} else if type_ == "symlink" {
+		err = instanceFileCheckSymlinkCreate(inst)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		// Figure out target.
 		target, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -598,6 +655,12 @@ func instanceFileDelete(s *state.State, inst instance.Instance, path string, _ *
 
 	defer func() { _ = client.Close() }()
 
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Delete the file.
 	err = client.Remove(path)
 	if err != nil {
@@ -607,3 +670,227 @@ func instanceFileDelete(s *state.State, inst instance.Instance, path string, _ *
 	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFileDeleted.Event(inst, logger.Ctx{"path": path}))
 	return response.EmptySyncResponse
 }
+
+// instanceFileGetRecursive streams the file or directory tree at path as a tarball.
+func instanceFileGetRecursive(s *state.State, inst instance.Instance, path string, _ *http.Request) response.Response {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Get a SFTP client.
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Add(func() { _ = client.Close() })
+
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check that the path exists before starting to stream.
+	_, err = client.Lstat(path)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	cleanup := reverter.Clone()
+	reverter.Success()
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		defer cleanup.Fail()
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.WriteHeader(http.StatusOK)
+
+		tw := tar.NewWriter(w)
+		walker := client.Walk(path)
+		for walker.Step() {
+			err := walker.Err()
+			if err != nil {
+				return err
+			}
+
+			stat := walker.Stat()
+
+			// Entries are stored relative to the parent of the requested path.
+			name, err := filepath.Rel(filepath.Dir(path), walker.Path())
+			if err != nil {
+				return err
+			}
+
+			link := ""
+			if stat.Mode()&os.ModeSymlink == os.ModeSymlink {
+				link, err = client.ReadLink(walker.Path())
+				if err != nil {
+					return err
+				}
+			} else if !stat.Mode().IsDir() && !stat.Mode().IsRegular() {
+				// Skip devices, sockets and fifos.
+				continue
+			}
+
+			hdr, err := tar.FileInfoHeader(stat, link)
+			if err != nil {
+				return err
+			}
+
+			hdr.Name = name
+			if stat.Mode().IsDir() {
+				hdr.Name += "/"
+			}
+
+			fs, ok := stat.Sys().(*sftp.FileStat)
+			if ok {
+				hdr.Uid = int(fs.UID)
+				hdr.Gid = int(fs.GID)
+			}
+
+			err = tw.WriteHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			if !stat.Mode().IsRegular() {
+				continue
+			}
+
+			file, err := client.Open(walker.Path())
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(tw, file)
+			_ = file.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tw.Close()
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFileRetrieved.Event(inst, logger.Ctx{"path": path, "recursive": true}))
+		return nil
+	})
+}
+
+// instanceFilePostRecursive unpacks the tarball provided in the request body into the directory at path.
+func instanceFilePostRecursive(s *state.State, inst instance.Instance, path string, r *http.Request) response.Response {
+	// Get a SFTP client.
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	path = filepath.Clean(path)
+
+	// Check that the path may be accessed.
+	err = instanceFileCheckPath(client, inst, path, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Make sure the target directory exists.
+	err = client.MkdirAll(path)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tr := tar.NewReader(r.Body)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Failed reading tarball: %w", err))
+		}
+
+		// Prevent entries from escaping the target directory.
+		target := filepath.Join(path, filepath.Clean("/"+hdr.Name))
+		if target == path {
+			continue
+		}
+
+		// Entries may go through symlinks created by earlier entries.
+		err = instanceFileCheckPath(client, inst, target, hdr.Typeflag != tar.TypeSymlink)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		_, err = client.Lstat(target)
+		exists := err == nil
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if !exists {
+				err = client.Mkdir(target)
+				if err != nil {
+					return response.SmartError(err)
+				}
+			}
+
+		case tar.TypeReg:
+			file, err := client.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			_, err = io.Copy(file, tr)
+			_ = file.Close()
+			if err != nil {
+				return response.InternalError(err)
+			}
+
+		case tar.TypeSymlink:
+			err = instanceFileCheckSymlinkCreate(inst)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if exists {
+				err = client.Remove(target)
+				if err != nil {
+					return response.SmartError(err)
+				}
+			}
+
+			err = client.Symlink(hdr.Linkname, target)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			// Permissions, ownership and times don't apply to symlinks.
+			continue
+
+		default:
+			return response.BadRequest(fmt.Errorf("Unsupported tarball entry type for %q", hdr.Name))
+		}
+
+		err = client.Chmod(target, fs.FileMode(hdr.Mode).Perm())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = client.Chown(target, hdr.Uid, hdr.Gid)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = client.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceFilePushed.Event(inst, logger.Ctx{"path": path, "recursive": true}))
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// placementMember represents a candidate cluster member along with the data used to score it.
+type placementMember struct {
+	NodeInfo  db.NodeInfo
+	Instances int
+
+	// Live resources of the member (nil if they couldn't be retrieved).
+	Resources *api.Resources
+
+	// Live resources of the target storage pool on the member (nil if unknown).
+	Pool *api.ResourcesStoragePool
+
+	// Resources reserved through the limits of the instances already on the member.
+	ReservedCPU    int64
+	ReservedMemory int64
+
+	// Whether the member already hosts an instance of the requested anti-affinity group.
+	AntiAffinity bool
+}
+
+// placementRequest represents the resources requested by the instance being placed.
+type placementRequest struct {
+	CPU    int64
+	Memory int64
+	Disk   int64
+	Pool   string
+
+	// Project and anti-affinity group (cluster.anti_affinity) of the instance.
+	Project      string
+	AntiAffinity string
+}
+
+// placementPolicy represents a way of scoring candidate members, the member with the lowest score is selected.
+type placementPolicy struct {
+	// Whether the policy needs the live resources of the members.
+	live bool
+
+	// Whether the policy needs the resources reserved by existing instances.
+	reserved bool
+
+	score func(member *placementMember, req *placementRequest) int64
+}
+
+// placementPolicies contains the supported values of instances.placement.policy.
+var placementPolicies = map[string]placementPolicy{
+	"instance_count": {
+		score: func(member *placementMember, req *placementRequest) int64 {
+			return int64(member.Instances)
+		},
+	},
+	"load": {
+		live:  true,
+		score: placementScoreLoad,
+	},
+	"reserved": {
+		reserved: true,
+		live:     true,
+		score:    placementScoreReserved,
+	},
+	"balanced": {
+		reserved: true,
+		live:     true,
+		score: func(member *placementMember, req *placementRequest) int64 {
+			load := placementScoreLoad(member, req)
+			reserved := placementScoreReserved(member, req)
+			if load == math.MaxInt64 || reserved == math.MaxInt64 {
+				return math.MaxInt64
+			}
+
+			return (load + reserved) / 2
+		},
+	},
+}
+
+// placementPercent returns the percentage of used over total (100 if total is unknown).
+func placementPercent(used float64, total float64) int64 {
+	if total <= 0 {
+		return 100
+	}
+
+	return int64(math.Round(used * 100 / total))
+}
+
+// placementScoreLoad scores a member based on its CPU load, memory usage and storage pool usage.
+func placementScoreLoad(member *placementMember, req *placementRequest) int64 {
+	if member.Resources == nil {
+		return math.MaxInt64
+	}
+
+	res := member.Resources
+	scores := []int64{
+		placementPercent(res.Load.Average1Min, float64(res.CPU.Total)),
+		placementPercent(float64(res.Memory.Used)+float64(req.Memory), float64(res.Memory.Total)),
+	}
+
+	if member.Pool != nil {
+		scores = append(scores, placementPercent(float64(member.Pool.Space.Used)+float64(req.Disk), float64(member.Pool.Space.Total)))
+	}
+
+	// Use the highest pressure so that a member running out of any resource is avoided.
+	return slices.Max(scores)
+}
+
+// placementScoreReserved scores a member based on the CPU and memory reserved through instance limits.
+func placementScoreReserved(member *placementMember, req *placementRequest) int64 {
+	if member.Resources == nil {
+		return math.MaxInt64
+	}
+
+	res := member.Resources
+	cpu := placementPercent(float64(member.ReservedCPU+req.CPU), float64(res.CPU.Total))
+	memory := placementPercent(float64(member.ReservedMemory+req.Memory), float64(res.Memory.Total))
+
+	return max(cpu, memory)
+}
+
+// instancePlacementRequest returns the resources requested by an instance from its expanded configuration and devices.
+func instancePlacementRequest(projectName string, config map[string]string, devices map[string]map[string]string, instType api.InstanceType) (*placementRequest, error) {
+	cpu, memory, disk, err := instance.ResourceUsage(config, devices, instType)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &placementRequest{CPU: cpu, Memory: memory, Disk: disk, Project: projectName, AntiAffinity: config["cluster.anti_affinity"]}
+
+	_, rootDisk, err := internalInstance.GetRootDiskDevice(devices)
+	if err == nil {
+		req.Pool = rootDisk["pool"]
+	}
+
+	return req, nil
+}
+
+// instancePlacementSort orders the candidate members according to the instances.placement.policy configuration,
+// the most suitable member first. Members hosting an instance of the same anti-affinity group are moved last and
+// members with an equal score remain ordered by number of instances.
+func instancePlacementSort(ctx context.Context, s *state.State, candidates []db.NodeInfo, req *placementRequest) ([]db.NodeInfo, error) {
+	if len(candidates) < 2 {
+		return candidates, nil
+	}
+
+	policyName := s.GlobalConfig.InstancesPlacementPolicy()
+	policy, ok := placementPolicies[policyName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown instance placement policy %q", policyName)
+	}
+
+	members, err := instancePlacementMembers(ctx, s, candidates, req, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int64, len(members))
+	for _, member := range members {
+		scores[member.NodeInfo.Name] = policy.score(member, req)
+	}
+
+	sort.SliceStable(members, func(i int, j int) bool {
+		if members[i].AntiAffinity != members[j].AntiAffinity {
+			return !members[i].AntiAffinity
+		}
+
+		iScore := scores[members[i].NodeInfo.Name]
+		jScore := scores[members[j].NodeInfo.Name]
+		if iScore != jScore {
+			return iScore < jScore
+		}
+
+		return members[i].Instances < members[j].Instances
+	})
+
+	sorted := make([]db.NodeInfo, 0, len(members))
+	for _, member := range members {
+		sorted = append(sorted, member.NodeInfo)
+	}
+
+	logger.Debug("Computed instance placement", logger.Ctx{"policy": policyName, "scores": scores})
+
+	return sorted, nil
+}
+
+// placementCacheDuration is how long the live and reserved resources of the members are re-used for, so that placing
+// many instances in a row (evacuation, rebalancing) doesn't query all the members for each of them.
+const placementCacheDuration = 10 * time.Second
+
+// placementResourcesCacheEntry holds the live resources of a member.
+type placementResourcesCacheEntry struct {
+	resources *api.Resources
+	pools     map[string]*api.ResourcesStoragePool
+	expiry    time.Time
+}
+
+// placementReservedCacheEntry holds the resources reserved by the instances of each member.
+type placementReservedCacheEntry struct {
+	cpu    map[string]int64
+	memory map[string]int64
+	expiry time.Time
+}
+
+var (
+	placementResourcesCache map[string]placementResourcesCacheEntry
+	placementReservedCache  *placementReservedCacheEntry
+	placementCacheLock      sync.Mutex
+)
+
+// instancePlacementReserve adds the resources requested by an instance to the cached reservations of the member
+// selected for it, so that the following placements take it into account until the cache expires.
+func instancePlacementReserve(memberName string, req *placementRequest) {
+	placementCacheLock.Lock()
+	defer placementCacheLock.Unlock()
+
+	if placementReservedCache == nil || placementReservedCache.expiry.Before(time.Now()) {
+		return
+	}
+
+	placementReservedCache.cpu[memberName] += req.CPU
+	placementReservedCache.memory[memberName] += req.Memory
+}
+
+// instancePlacementReservedReset drops the cached reservations, forcing them to be computed again on next placement.
+func instancePlacementReservedReset() {
+	placementCacheLock.Lock()
+	placementReservedCache = nil
+	placementCacheLock.Unlock()
+}
+
+// instancePlacementReserved returns the CPU and memory reserved by the instances of each member, from the cache if
+// still valid. Anti-affinity is always computed from the database as it mustn't be stale.
+func instancePlacementReserved(ctx context.Context, s *state.State, req *placementRequest, reserved bool) (map[string]int64, map[string]int64, map[string]bool, error) {
+	var cpu, memory map[string]int64
+
+	if reserved {
+		placementCacheLock.Lock()
+		if placementReservedCache != nil && placementReservedCache.expiry.After(time.Now()) {
+			cpu = maps.Clone(placementReservedCache.cpu)
+			memory = maps.Clone(placementReservedCache.memory)
+		}
+
+		placementCacheLock.Unlock()
+	}
+
+	loadReserved := reserved && cpu == nil
+	if !loadReserved && req.AntiAffinity == "" {
+		return cpu, memory, nil, nil
+	}
+
+	antiAffinity := map[string]bool{}
+	if loadReserved {
+		cpu = map[string]int64{}
+		memory = map[string]int64{}
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			config := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+			if req.AntiAffinity != "" && inst.Project == req.Project && config["cluster.anti_affinity"] == req.AntiAffinity {
+				antiAffinity[inst.Node] = true
+			}
+
+			if !loadReserved {
+				return nil
+			}
+
+			devices := db.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+			instCPU, instMemory, _, err := instance.ResourceUsage(config, devices.CloneNative(), api.InstanceType(inst.Type.String()))
+			if err != nil {
+				// Don't fail placement due to a single invalid instance.
+				return nil
+			}
+
+			cpu[inst.Node] += instCPU
+			memory[inst.Node] += instMemory
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if loadReserved {
+		placementCacheLock.Lock()
+		placementReservedCache = &placementReservedCacheEntry{
+			cpu:    maps.Clone(cpu),
+			memory: maps.Clone(memory),
+			expiry: time.Now().Add(placementCacheDuration),
+		}
+
+		placementCacheLock.Unlock()
+	}
+
+	return cpu, memory, antiAffinity, nil
+}
+
+// instancePlacementResources retrieves the live resources of a member and of the storage pool (if not empty) on it,
+// from the cache if still valid.
+func instancePlacementResources(s *state.State, member db.NodeInfo, poolName string) (*api.Resources, *api.ResourcesStoragePool) {
+	placementCacheLock.Lock()
+	entry, ok := placementResourcesCache[member.Name]
+	placementCacheLock.Unlock()
+
+	if !ok || entry.expiry.Before(time.Now()) {
+		entry = placementResourcesCacheEntry{pools: map[string]*api.ResourcesStoragePool{}}
+	} else {
+		_, ok := entry.pools[poolName]
+		if poolName == "" || ok {
+			return placementCopyResources(entry.resources, entry.pools[poolName])
+		}
+
+		entry.pools = maps.Clone(entry.pools)
+	}
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		logger.Warn("Failed connecting to cluster member for instance placement", logger.Ctx{"member": member.Name, "err": err})
+		return nil, nil
+	}
+
+	if entry.resources == nil {
+		entry.resources, err = client.GetServerResources()
+		if err != nil {
+			logger.Warn("Failed getting cluster member resources for instance placement", logger.Ctx{"member": member.Name, "err": err})
+			return nil, nil
+		}
+
+		entry.expiry = time.Now().Add(placementCacheDuration)
+	}
+
+	if poolName != "" {
+		pool, err := client.UseTarget(member.Name).GetStoragePoolResources(poolName)
+		if err != nil {
+			logger.Debug("Failed getting storage pool resources for instance placement", logger.Ctx{"member": member.Name, "pool": poolName, "err": err})
+		}
+
+		// Failures are cached too to avoid retrying for each instance.
+		entry.pools[poolName] = pool
+	}
+
+	placementCacheLock.Lock()
+	if placementResourcesCache == nil {
+		placementResourcesCache = map[string]placementResourcesCacheEntry{}
+	}
+
+	placementResourcesCache[member.Name] = entry
+	placementCacheLock.Unlock()
+
+	return placementCopyResources(entry.resources, entry.pools[poolName])
+}
+
+// placementCopyResources returns deep copies of cached resources so that callers adjusting the usage (rebalance
+// planning) don't alter the cache shared with concurrent placements.
+func placementCopyResources(resources *api.Resources, pool *api.ResourcesStoragePool) (*api.Resources, *api.ResourcesStoragePool) {
+	var resourcesCopy *api.Resources
+	if resources != nil {
+		resourcesCopy = &api.Resources{}
+
+		data, err := json.Marshal(resources)
+		if err == nil {
+			err = json.Unmarshal(data, resourcesCopy)
+		}
+
+		if err != nil {
+			return nil, nil
+		}
+	}
+
+	var poolCopy *api.ResourcesStoragePool
+	if pool != nil {
+		poolValue := *pool
+		poolCopy = &poolValue
+	}
+
+	return resourcesCopy, poolCopy
+}
+
+// instancePlacementMembers gathers the data needed by the placement policy for each candidate member.
+func instancePlacementMembers(ctx context.Context, s *state.State, candidates []db.NodeInfo, req *placementRequest, policy placementPolicy) ([]*placementMember, error) {
+	members := make([]*placementMember, 0, len(candidates))
+	for _, candidate := range candidates {
+		members = append(members, &placementMember{NodeInfo: candidate})
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, member := range members {
+			var err error
+
+			member.Instances, err = tx.GetInstancesCount(ctx, "", member.NodeInfo.Name, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading placement data: %w", err)
+	}
+
+	reservedCPU, reservedMemory, antiAffinity, err := instancePlacementReserved(ctx, s, req, policy.reserved)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading placement data: %w", err)
+	}
+
+	for _, member := range members {
+		member.ReservedCPU = reservedCPU[member.NodeInfo.Name]
+		member.ReservedMemory = reservedMemory[member.NodeInfo.Name]
+		member.AntiAffinity = antiAffinity[member.NodeInfo.Name]
+	}
+
+	if !policy.live {
+		return members, nil
+	}
+
+	// Retrieve the live resources of all members in parallel.
+	wg := sync.WaitGroup{}
+	for _, member := range members {
+		wg.Add(1)
+		go func(member *placementMember) {
+			defer wg.Done()
+
+			member.Resources, member.Pool = instancePlacementResources(s, member.NodeInfo, req.Pool)
+		}(member)
+	}
+
+	wg.Wait()
+
+	return members, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// placementTestResources returns resources with 4 CPU threads and 16GiB of memory.
+func placementTestResources(load float64, memoryUsed uint64) *api.Resources {
+	res := &api.Resources{}
+	res.CPU.Total = 4
+	res.Load.Average1Min = load
+	res.Memory.Total = 16 * 1024 * 1024 * 1024
+	res.Memory.Used = memoryUsed
+
+	return res
+}
+
+func TestPlacementScoreLoad(t *testing.T) {
+	gib := int64(1024 * 1024 * 1024)
+
+	tests := []struct {
+		name     string
+		member   placementMember
+		req      placementRequest
+		expected int64
+	}{
+		{
+			name:     "Unknown resources",
+			member:   placementMember{},
+			expected: math.MaxInt64,
+		},
+		{
+			name:     "Idle member",
+			member:   placementMember{Resources: placementTestResources(0, 0)},
+			expected: 0,
+		},
+		{
+			name:     "CPU load",
+			member:   placementMember{Resources: placementTestResources(3, uint64(4*gib))},
+			expected: 75,
+		},
+		{
+			name:     "Memory usage including the request",
+			member:   placementMember{Resources: placementTestResources(1, uint64(4*gib))},
+			req:      placementRequest{Memory: 4 * gib},
+			expected: 50,
+		},
+		{
+			name: "Storage pool usage",
+			member: placementMember{
+				Resources: placementTestResources(1, uint64(4*gib)),
+				Pool:      &api.ResourcesStoragePool{Space: api.ResourcesStoragePoolSpace{Used: uint64(80 * gib), Total: uint64(100 * gib)}},
+			},
+			req:      placementRequest{Disk: 10 * gib},
+			expected: 90,
+		},
+		{
+			name: "Unknown storage pool size",
+			member: placementMember{
+				Resources: placementTestResources(0, 0),
+				Pool:      &api.ResourcesStoragePool{},
+			},
+			expected: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, placementScoreLoad(&tt.member, &tt.req))
+		})
+	}
+}
+
+func TestPlacementScoreReserved(t *testing.T) {
+	gib := int64(1024 * 1024 * 1024)
+
+	tests := []struct {
+		name     string
+		member   placementMember
+		req      placementRequest
+		expected int64
+	}{
+		{
+			name:     "Unknown resources",
+			member:   placementMember{ReservedCPU: 1},
+			expected: math.MaxInt64,
+		},
+		{
+			name:     "Nothing reserved",
+			member:   placementMember{Resources: placementTestResources(0, 0)},
+			expected: 0,
+		},
+		{
+			name:     "CPU reservations including the request",
+			member:   placementMember{Resources: placementTestResources(0, 0), ReservedCPU: 2, ReservedMemory: 4 * gib},
+			req:      placementRequest{CPU: 1},
+			expected: 75,
+		},
+		{
+			name:     "Memory reservations including the request",
+			member:   placementMember{Resources: placementTestResources(0, 0), ReservedCPU: 1, ReservedMemory: 8 * gib},
+			req:      placementRequest{Memory: 4 * gib},
+			expected: 75,
+		},
+		{
+			name:     "Overcommitted",
+			member:   placementMember{Resources: placementTestResources(0, 0), ReservedCPU: 8},
+			expected: 200,
+		},
+		{
+			name: "Live usage is ignored",
+			member: placementMember{
+				Resources:   placementTestResources(4, uint64(16*gib)),
+				ReservedCPU: 1,
+			},
+			expected: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, placementScoreReserved(&tt.member, &tt.req))
+		})
+	}
+}
+
+func TestPlacementCopyResources(t *testing.T) {
+	resources := placementTestResources(1, 1024)
+	pool := &api.ResourcesStoragePool{Space: api.ResourcesStoragePoolSpace{Used: 10, Total: 100}}
+
+	resourcesCopy, poolCopy := placementCopyResources(resources, pool)
+	assert.Equal(t, resources, resourcesCopy)
+	assert.Equal(t, pool, poolCopy)
+
+	// Adjusting the copies (as rebalance planning does) must leave the cached values untouched.
+	resourcesCopy.Load.Average1Min = 3
+	resourcesCopy.Memory.Used = 2048
+	poolCopy.Space.Used = 50
+
+	assert.Equal(t, float64(1), resources.Load.Average1Min)
+	assert.Equal(t, uint64(1024), resources.Memory.Used)
+	assert.Equal(t, uint64(10), pool.Space.Used)
+
+	resourcesCopy, poolCopy = placementCopyResources(nil, nil)
+	assert.Nil(t, resourcesCopy)
+	assert.Nil(t, poolCopy)
+}
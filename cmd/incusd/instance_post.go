@@ -183,6 +183,12 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(err)
 		}
 
+		// Check the new name is allowed for restricted certificates.
+		err = instanceNameAccessCheck(d, r, req.Name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		// Check that the new isn't already in use.
 		var id int
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
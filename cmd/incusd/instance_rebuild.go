@@ -5,19 +5,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 
 	"github.com/gorilla/mux"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -26,9 +33,10 @@ import (
 //
 //	Rebuild an instance
 //
-//	Rebuild an instance using an alternate image or as empty.
+//	Rebuild an instance using an alternate image, a backup or as empty.
 //	---
 //	consumes:
+//	  - application/json
 //	  - application/octet-stream
 //	produces:
 //	  - application/json
@@ -41,9 +49,13 @@ import (
 //	  - in: body
 //	    name: instance
 //	    description: InstanceRebuild request
-//	    required: true
+//	    required: false
 //	    schema:
 //	      $ref: "#/definitions/InstanceRebuildPost"
+//	  - in: body
+//	    name: raw_backup
+//	    description: Raw backup file
+//	    required: false
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
@@ -83,9 +95,14 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 
 	// Parse the request
 	req := api.InstanceRebuildPost{}
-	err = json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		return response.BadRequest(err)
+	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		// If we're getting binary content, it's the backup file to rebuild from.
+		req.Source.Type = "backup"
+	} else {
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
 	}
 
 	var targetProject *api.Project
@@ -108,7 +125,7 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed loading instance: %w", err)
 		}
 
-		if req.Source.Type != "none" {
+		if !slices.Contains([]string{"none", "backup"}, req.Source.Type) {
 			sourceImage, err = getSourceImageFromInstanceSource(ctx, s, tx, targetProject.Name, req.Source, &sourceImageRef, dbInst.Type.String())
 			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
 				return err
@@ -130,11 +147,51 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Instance must be stopped to be rebuilt"))
 	}
 
+	var backupFile *os.File
+	var bInfo *backup.Info
+	var backupDownloadHosts []string
+	backupDownloadURL := req.Source.Type == "backup" && r.Header.Get("Content-Type") != "application/octet-stream" && req.Source.URL != ""
+	if backupDownloadURL {
+		// The backup is downloaded as part of the operation.
+		backupDownloadHosts, err = backupDownloadCheck(s, r, req.Source.URL, req.Source.Checksum)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	} else if req.Source.Type == "backup" {
+		backupFile, bInfo, err = instanceRebuildBackupFile(s, r, inst, req.Source)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(s.ShutdownCtx)
+
 	run := func(op *operations.Operation) error {
+		defer cancel()
+
 		if req.Source.Type == "none" {
 			return instanceRebuildFromEmpty(inst, op)
 		}
 
+		if backupDownloadURL {
+			data, err := backupDownload(ctx, s, req.Source.URL, req.Source.Headers, req.Source.Checksum, backupDownloadHosts)
+			if err != nil {
+				return err
+			}
+
+			backupFile, bInfo, err = backupFileLoad(s, data)
+			_ = data.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		if req.Source.Type == "backup" {
+			defer func() { _ = backupFile.Close() }()
+
+			return instanceRebuildFromBackup(inst, bInfo, backupFile, op)
+		}
+
 		if req.Source.Server != "" {
 			sourceImage, err = ensureDownloadedImageFitWithinBudget(context.TODO(), s, r, op, *targetProject, sourceImageRef, req.Source, inst.Type().String())
 			if err != nil {
@@ -152,10 +209,50 @@ func instanceRebuildPost(d *Daemon, r *http.Request) response.Response {
 	resources := map[string][]api.URL{}
 	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
 
-	op, err := operations.OperationCreate(s, targetProject.Name, operations.OperationClassTask, operationtype.InstanceRebuild, resources, nil, run, nil, nil, r)
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, targetProject.Name, operations.OperationClassTask, operationtype.InstanceRebuild, resources, nil, run, onCancel, nil, r)
 	if err != nil {
+		cancel()
+
+		if backupFile != nil {
+			_ = backupFile.Close()
+		}
+
 		return response.InternalError(err)
 	}
 
 	return operations.OperationResponse(op)
 }
+
+// instanceRebuildBackupFile loads the backup file an instance is to be rebuilt from.
+// The backup can either be uploaded as part of the request or be an existing backup of the instance, backups
+// downloaded from a URL being loaded as part of the operation.
+func instanceRebuildBackupFile(s *state.State, r *http.Request, inst instance.Instance, source api.InstanceSource) (*os.File, *backup.Info, error) {
+	var data io.ReadCloser
+
+	switch {
+	case r.Header.Get("Content-Type") == "application/octet-stream":
+		data = r.Body
+	case source.Source != "":
+		b, err := instance.BackupLoadByName(s, inst.Project().Name, inst.Name()+internalInstance.SnapshotDelimiter+source.Source)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, err = os.Open(internalUtil.VarPath("backups", "instances", project.Instance(inst.Project().Name, b.Name())))
+		if err != nil {
+			return nil, nil, err
+		}
+
+	default:
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Missing backup source")
+	}
+
+	defer func() { _ = data.Close() }()
+
+	return backupFileLoad(s, data)
+}
@@ -11,9 +11,11 @@ import (
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
 )
 
 // swagger:operation GET /1.0/instances/{name}/sftp instances instance_sftp
@@ -73,9 +75,25 @@ func instanceSFTPHandler(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
-		conn, err = inst.FileSFTPConn()
-		if err != nil {
-			return response.SmartError(api.StatusErrorf(http.StatusInternalServerError, "Failed getting instance SFTP connection: %v", err))
+		p := inst.Project()
+		if len(project.GetRestrictedFilesPaths(&p)) > 0 {
+			// Proxy the SFTP session to only allow access to the permitted paths.
+			serverConn, clientConn := net.Pipe()
+			conn = clientConn
+
+			go func() {
+				err := serveInstanceSFTPRestricted(inst, serverConn)
+				if err != nil {
+					logger.Warn("Restricted instance SFTP server exited", logger.Ctx{"project": projectName, "instance": instName, "err": err})
+				}
+
+				_ = serverConn.Close()
+			}()
+		} else {
+			conn, err = inst.FileSFTPConn()
+			if err != nil {
+				return response.SmartError(api.StatusErrorf(http.StatusInternalServerError, "Failed getting instance SFTP connection: %v", err))
+			}
 		}
 	}
 
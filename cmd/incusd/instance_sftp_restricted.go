@@ -0,0 +1,361 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// instanceFileCheckSymlinks cleans path and checks that none of its components is a symlink using the instance's
+// SFTP client. Components which don't exist yet are accepted. If followLast is false, the last component isn't checked.
+//
+// Symlinks are refused rather than resolved as they could be changed by the instance between the check and the
+// actual access.
+func instanceFileCheckSymlinks(client *sftp.Client, path string, followLast bool) (string, error) {
+	path = filepath.Clean("/" + path)
+	if path == "/" {
+		return path, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if !followLast {
+		parts = parts[:len(parts)-1]
+	}
+
+	current := "/"
+	for _, part := range parts {
+		current = filepath.Join(current, part)
+
+		stat, err := client.Lstat(current)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+
+			return "", err
+		}
+
+		if stat.Mode()&os.ModeSymlink == os.ModeSymlink {
+			return "", api.StatusErrorf(http.StatusForbidden, "Symbolic links can't be followed in this project (%q)", current)
+		}
+	}
+
+	return path, nil
+}
+
+// instanceFileRestricted returns whether file access to the instance is restricted by its project.
+func instanceFileRestricted(inst instance.Instance) bool {
+	p := inst.Project()
+	return len(project.GetRestrictedFilesPaths(&p)) > 0
+}
+
+// instanceFileCheckPath returns an error if path is outside of the paths that the instance's project allows file
+// access to or if it goes through a symlink while file access is restricted.
+func instanceFileCheckPath(client *sftp.Client, inst instance.Instance, path string, followLast bool) error {
+	p := inst.Project()
+	allowedPaths := project.GetRestrictedFilesPaths(&p)
+	if len(allowedPaths) == 0 {
+		return nil
+	}
+
+	cleanPath, err := instanceFileCheckSymlinks(client, path, followLast)
+	if err != nil {
+		return err
+	}
+
+	if !project.CheckRestrictedFilesPath(allowedPaths, cleanPath) {
+		return api.StatusErrorf(http.StatusForbidden, "Access to %q isn't allowed in this project", path)
+	}
+
+	return nil
+}
+
+// instanceFileCheckSymlinkCreate returns an error if symlinks can't be created in the instance, which is the case
+// when its project restricts file access.
+func instanceFileCheckSymlinkCreate(inst instance.Instance) error {
+	if instanceFileRestricted(inst) {
+		return api.StatusErrorf(http.StatusForbidden, "Symbolic links can't be created in this project")
+	}
+
+	return nil
+}
+
+// instanceSFTPRestricted is a SFTP request handler proxying requests to the instance's SFTP server
+// while only allowing access to the paths allowed by the instance's project.
+type instanceSFTPRestricted struct {
+	inst   instance.Instance
+	client *sftp.Client
+}
+
+// serveInstanceSFTPRestricted serves a SFTP session on conn restricted to the paths allowed by the instance's project.
+func serveInstanceSFTPRestricted(inst instance.Instance, conn io.ReadWriteCloser) error {
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	h := &instanceSFTPRestricted{inst: inst, client: client}
+	srv := sftp.NewRequestServer(conn, sftp.Handlers{
+		FileGet:  h,
+		FilePut:  h,
+		FileCmd:  h,
+		FileList: h,
+	})
+
+	err = srv.Serve()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+func (h *instanceSFTPRestricted) check(path string, followLast bool) error {
+	return instanceFileCheckPath(h.client, h.inst, path, followLast)
+}
+
+// Fileread opens a file for reading.
+func (h *instanceSFTPRestricted) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	err := h.check(r.Filepath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.client.Open(r.Filepath)
+}
+
+// Filewrite opens a file for writing.
+func (h *instanceSFTPRestricted) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	return h.OpenFile(r)
+}
+
+// OpenFile opens a file for reading and writing.
+func (h *instanceSFTPRestricted) OpenFile(r *sftp.Request) (sftp.WriterAtReaderAt, error) {
+	err := h.check(r.Filepath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	pflags := r.Pflags()
+
+	flags := os.O_RDONLY
+	if pflags.Read && pflags.Write {
+		flags = os.O_RDWR
+	} else if pflags.Write {
+		flags = os.O_WRONLY
+	}
+
+	if pflags.Creat {
+		flags |= os.O_CREATE
+	}
+
+	if pflags.Trunc {
+		flags |= os.O_TRUNC
+	}
+
+	if pflags.Excl {
+		flags |= os.O_EXCL
+	}
+
+	return h.client.OpenFile(r.Filepath, flags)
+}
+
+// Filecmd handles the commands which don't return data.
+func (h *instanceSFTPRestricted) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		err := h.check(r.Filepath, true)
+		if err != nil {
+			return err
+		}
+
+		attrs := r.Attributes()
+		flags := r.AttrFlags()
+
+		if flags.Size {
+			err = h.client.Truncate(r.Filepath, int64(attrs.Size))
+			if err != nil {
+				return err
+			}
+		}
+
+		if flags.Permissions {
+			err = h.client.Chmod(r.Filepath, attrs.FileMode())
+			if err != nil {
+				return err
+			}
+		}
+
+		if flags.UidGid {
+			err = h.client.Chown(r.Filepath, int(attrs.UID), int(attrs.GID))
+			if err != nil {
+				return err
+			}
+		}
+
+		if flags.Acmodtime {
+			err = h.client.Chtimes(r.Filepath, attrs.AccessTime(), attrs.ModTime())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case "Rename", "Link":
+		for _, path := range []string{r.Filepath, r.Target} {
+			err := h.check(path, false)
+			if err != nil {
+				return err
+			}
+		}
+
+		if r.Method == "Link" {
+			return h.client.Link(r.Filepath, r.Target)
+		}
+
+		return h.client.Rename(r.Filepath, r.Target)
+
+	case "Symlink":
+		err := instanceFileCheckSymlinkCreate(h.inst)
+		if err != nil {
+			return err
+		}
+
+		return h.client.Symlink(r.Filepath, r.Target)
+
+	case "Rmdir", "Remove", "Mkdir":
+		err := h.check(r.Filepath, false)
+		if err != nil {
+			return err
+		}
+
+		switch r.Method {
+		case "Rmdir":
+			return h.client.RemoveDirectory(r.Filepath)
+		case "Remove":
+			return h.client.Remove(r.Filepath)
+		default:
+			return h.client.Mkdir(r.Filepath)
+		}
+	}
+
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+// Filelist handles the commands returning file information.
+func (h *instanceSFTPRestricted) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		err := h.check(r.Filepath, true)
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := h.client.ReadDir(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+
+		lister := make(instanceSFTPLister, 0, len(entries))
+		for _, entry := range entries {
+			lister = append(lister, instanceSFTPFileInfo{entry})
+		}
+
+		return lister, nil
+
+	case "Stat":
+		err := h.check(r.Filepath, true)
+		if err != nil {
+			return nil, err
+		}
+
+		stat, err := h.client.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+
+		return instanceSFTPLister{instanceSFTPFileInfo{stat}}, nil
+	}
+
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+// Lstat returns the file information without following symlinks.
+func (h *instanceSFTPRestricted) Lstat(r *sftp.Request) (sftp.ListerAt, error) {
+	err := h.check(r.Filepath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := h.client.Lstat(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	return instanceSFTPLister{instanceSFTPFileInfo{stat}}, nil
+}
+
+// Readlink returns the target of a symlink.
+func (h *instanceSFTPRestricted) Readlink(path string) (string, error) {
+	err := h.check(path, false)
+	if err != nil {
+		return "", err
+	}
+
+	return h.client.ReadLink(path)
+}
+
+// instanceSFTPLister is a sftp.ListerAt for a fixed list of files.
+type instanceSFTPLister []os.FileInfo
+
+// ListAt copies the entries starting at offset into list.
+func (l instanceSFTPLister) ListAt(list []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+
+	n := copy(list, l[offset:])
+	if n < len(list) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// instanceSFTPFileInfo exposes the ownership of files returned by the instance's SFTP server.
+type instanceSFTPFileInfo struct {
+	os.FileInfo
+}
+
+// Uid returns the file owner UID.
+func (fi instanceSFTPFileInfo) Uid() uint32 {
+	stat, ok := fi.Sys().(*sftp.FileStat)
+	if !ok {
+		return 0
+	}
+
+	return stat.UID
+}
+
+// Gid returns the file owner GID.
+func (fi instanceSFTPFileInfo) Gid() uint32 {
+	stat, ok := fi.Sys().(*sftp.FileStat)
+	if !ok {
+		return 0
+	}
+
+	return stat.GID
+}
@@ -214,6 +214,8 @@ func instanceActionToOpType(action string) (operationtype.Type, error) {
 		return operationtype.InstanceFreeze, nil
 	case internalInstance.Unfreeze:
 		return operationtype.InstanceUnfreeze, nil
+	case internalInstance.SyncTime:
+		return operationtype.InstanceTimeSync, nil
 	default:
 		return operationtype.Unknown, fmt.Errorf("Unknown action: '%s'", action)
 	}
@@ -249,6 +251,8 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 		return inst.Freeze()
 	case internalInstance.Unfreeze:
 		return inst.Unfreeze()
+	case internalInstance.SyncTime:
+		return inst.SyncTime()
 	}
 
 	return fmt.Errorf("Unknown action: '%s'", req.Action)
@@ -2,19 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/request"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
@@ -532,6 +537,67 @@ func (suite *containerTestSuite) TestContainer_findIdmap_maxed() {
 	}
 }
 
+func (suite *containerTestSuite) TestContainer_SourceAccessRestricted() {
+	suite.d.clientCerts.SetCertificatesAndProjects(map[certificate.Type]map[string]x509.Certificate{
+		certificate.TypeClient: {"ci": {}},
+	}, map[string][]string{"ci": {"default"}})
+
+	suite.d.clientCerts.SetInstances(map[string][]string{"ci": {"ci-*"}})
+
+	r := httptest.NewRequest("POST", "/1.0/instances", nil)
+	ctx := context.WithValue(r.Context(), request.CtxUsername, "ci")
+	ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodTLS)
+	r = r.WithContext(ctx)
+
+	// Copying an instance outside of the allowed patterns must fail before the source is even loaded.
+	resp := createFromCopy(r.Context(), suite.d.State(), r, "default", nil, &api.InstancesPost{
+		Name:   "ci-copy",
+		Source: api.InstanceSource{Type: "copy", Source: "prod"},
+	})
+
+	suite.Req.Equal(http.StatusForbidden, resp.Code())
+
+	// Publishing checks the instance, or the parent of a snapshot.
+	for name, allowed := range map[string]bool{
+		"ci-1":       true,
+		"ci-1/snap0": true,
+		"prod":       false,
+		"prod/snap0": false,
+	} {
+		err := instanceSourceAccessCheck(suite.d.State(), r, "default", name)
+		suite.Req.Equal(allowed, err == nil, name)
+	}
+}
+
+func (suite *containerTestSuite) TestContainer_NameAccessRestricted() {
+	suite.d.clientCerts.SetCertificatesAndProjects(map[certificate.Type]map[string]x509.Certificate{
+		certificate.TypeClient: {"ci": {}},
+	}, map[string][]string{"ci": {"default"}})
+
+	suite.d.clientCerts.SetInstances(map[string][]string{"ci": {"ci-*"}})
+
+	for url, names := range map[string]map[string]bool{
+		// Creating, copying or importing an instance.
+		"/1.0/instances": {"ci-1": true, "prod": false, "": false},
+
+		// Renaming an instance.
+		"/1.0/instances/ci-1": {"ci-2": true, "prod": false},
+	} {
+		r := httptest.NewRequest("POST", url, nil)
+		ctx := context.WithValue(r.Context(), request.CtxUsername, "ci")
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodTLS)
+		r = r.WithContext(ctx)
+
+		for name, allowed := range names {
+			err := instanceNameAccessCheck(suite.d, r, name)
+			suite.Req.Equal(allowed, err == nil, "%s to %q", url, name)
+			if err != nil {
+				suite.Req.True(api.StatusErrorCheck(err, http.StatusForbidden))
+			}
+		}
+	}
+}
+
 func TestContainerTestSuite(t *testing.T) {
 	suite.Run(t, &containerTestSuite{})
 }
@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -155,7 +156,22 @@ var instanceBackupExportCmd = APIEndpoint{
 	Name: "instanceBackupExport",
 	Path: "instances/{name}/backups/{backupName}/export",
 
-	Get: APIEndpointAction{Handler: instanceBackupExportGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanManageBackups, "name")},
+	Get: APIEndpointAction{Handler: instanceBackupExportGet, AccessHandler: allowInstanceBackupExport, AllowUntrusted: true},
+}
+
+var instanceBackupFilesCmd = APIEndpoint{
+	Name: "instanceBackupFiles",
+	Path: "instances/{name}/backups/{backupName}/files",
+
+	Get:  APIEndpointAction{Handler: instanceBackupFilesGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+	Post: APIEndpointAction{Handler: instanceBackupFilesPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanAccessFiles, "name")},
+}
+
+var instanceBackupSecretCmd = APIEndpoint{
+	Name: "instanceBackupSecret",
+	Path: "instances/{name}/backups/{backupName}/secret",
+
+	Post: APIEndpointAction{Handler: instanceBackupSecretPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanManageBackups, "name")},
 }
 
 var instanceAccessCmd = APIEndpoint{
@@ -165,6 +181,20 @@ var instanceAccessCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceAccess, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
 }
 
+var instanceCloudInitCmd = APIEndpoint{
+	Name: "instanceCloudInit",
+	Path: "instances/{name}/cloud-init",
+
+	Get: APIEndpointAction{Handler: instanceCloudInitGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+}
+
+var instanceProcessesCmd = APIEndpoint{
+	Name: "instanceProcesses",
+	Path: "instances/{name}/processes",
+
+	Get: APIEndpointAction{Handler: instanceProcessesGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+}
+
 var instanceDebugMemoryCmd = APIEndpoint{
 	Name: "instanceDebugMemory",
 	Path: "instances/{name}/debug/memory",
@@ -220,6 +250,9 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	// Sort based on instance boot priority.
 	sort.Sort(instanceAutostartList(instances))
 
+	// Then make sure that dependencies are started first.
+	instances = instancesSortDependencies(instances)
+
 	// Let's make up to 3 attempts to start instances.
 	maxAttempts := 3
 
@@ -241,6 +274,21 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 
 		instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+		// Wait for the dependencies to be running.
+		err := instanceWaitDependencies(s, inst)
+		if err != nil {
+			warnErr := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+				return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, cluster.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, err.Error())
+			})
+			if warnErr != nil {
+				instLogger.Warn("Failed to create instance autostart failure warning", logger.Ctx{"err": warnErr})
+			}
+
+			instLogger.Error("Failed to auto start instance", logger.Ctx{"err": err})
+
+			continue
+		}
+
 		// Try to start the instance.
 		attempt := 0
 		for {
@@ -298,6 +346,118 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	}
 }
 
+// instanceDependencies returns the names of the instances listed in boot.depends_on.
+func instanceDependencies(inst instance.Instance) []string {
+	value := inst.ExpandedConfig()["boot.depends_on"]
+	if value == "" {
+		return nil
+	}
+
+	return util.SplitNTrimSpace(value, ",", -1, true)
+}
+
+// instancesSortDependencies re-orders the instances so that every instance comes after the instances it depends on.
+// The existing order is otherwise preserved and circular dependencies are ignored.
+func instancesSortDependencies(instances []instance.Instance) []instance.Instance {
+	type key struct {
+		project string
+		name    string
+	}
+
+	byName := make(map[key]instance.Instance, len(instances))
+	for _, inst := range instances {
+		byName[key{inst.Project().Name, inst.Name()}] = inst
+	}
+
+	sorted := make([]instance.Instance, 0, len(instances))
+	visited := make(map[key]bool, len(instances))
+
+	var visit func(inst instance.Instance)
+	visit = func(inst instance.Instance) {
+		k := key{inst.Project().Name, inst.Name()}
+
+		_, seen := visited[k]
+		if seen {
+			if !visited[k] {
+				logger.Warn("Ignoring circular instance startup dependency", logger.Ctx{"project": k.project, "instance": k.name})
+			}
+
+			return
+		}
+
+		// Mark as being visited until all dependencies have been added.
+		visited[k] = false
+
+		for _, name := range instanceDependencies(inst) {
+			dep, ok := byName[key{k.project, name}]
+			if ok {
+				visit(dep)
+			}
+		}
+
+		visited[k] = true
+		sorted = append(sorted, inst)
+	}
+
+	for _, inst := range instances {
+		visit(inst)
+	}
+
+	return sorted
+}
+
+// instanceWaitDependencies waits for the local instances listed in boot.depends_on to be running (or ready if boot.depends_on.ready is set).
+func instanceWaitDependencies(s *state.State, inst instance.Instance) error {
+	dependencies := instanceDependencies(inst)
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	config := inst.ExpandedConfig()
+	waitReady := util.IsTrue(config["boot.depends_on.ready"])
+
+	timeout := 300
+	if config["boot.depends_on.timeout"] != "" {
+		timeout, _ = strconv.Atoi(config["boot.depends_on.timeout"])
+	}
+
+	ctx, cancel := context.WithTimeout(s.ShutdownCtx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	for _, name := range dependencies {
+		for {
+			// Reload the dependency every time to get its current volatile state.
+			dep, err := instance.LoadByProjectAndName(s, inst.Project().Name, name)
+			if err != nil {
+				return fmt.Errorf("Failed loading dependency %q: %w", name, err)
+			}
+
+			// Instances running on other cluster members can't be checked.
+			if s.ServerClustered && dep.Location() != s.ServerName {
+				break
+			}
+
+			if waitReady && dep.State() == strings.ToUpper(api.Ready.String()) {
+				break
+			} else if !waitReady && dep.IsRunning() {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				if waitReady {
+					return fmt.Errorf("Timed out waiting for dependency %q to be ready", name)
+				}
+
+				return fmt.Errorf("Timed out waiting for dependency %q to be running", name)
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	return nil
+}
+
 type instanceStopList []instance.Instance
 
 func (slice instanceStopList) Len() int {
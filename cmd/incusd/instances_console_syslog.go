@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// instanceConsoleSyslog is a syslog connection used to forward the console output of an instance.
+type instanceConsoleSyslog struct {
+	target string
+	writer *syslog.Writer
+}
+
+// instancesConsoleSyslogs holds the syslog connections of the instances forwarding their console (by instance ID).
+var instancesConsoleSyslogs = map[int]*instanceConsoleSyslog{}
+
+// instancesConsoleSyslogTask forwards the new console output of the instances with console.syslog set.
+func instancesConsoleSyslogTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		insts, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Warn("Failed loading instances for console forwarding", logger.Ctx{"err": err})
+			return
+		}
+
+		seen := make(map[int]bool, len(insts))
+		for _, inst := range insts {
+			target := inst.ExpandedConfig()["console.syslog"]
+			if target == "" || !inst.IsRunning() {
+				continue
+			}
+
+			seen[inst.ID()] = true
+
+			forwarder := instancesConsoleSyslogs[inst.ID()]
+			if forwarder != nil && forwarder.target != target {
+				_ = forwarder.writer.Close()
+				forwarder = nil
+			}
+
+			if forwarder == nil {
+				writer, err := instanceConsoleSyslogDial(target)
+				if err != nil {
+					logger.Warn("Failed connecting to console syslog target", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "target": target, "err": err})
+					continue
+				}
+
+				forwarder = &instanceConsoleSyslog{target: target, writer: writer}
+				instancesConsoleSyslogs[inst.ID()] = forwarder
+			}
+
+			output, err := inst.ConsoleBufferRead()
+			if err != nil {
+				logger.Debug("Failed reading instance console", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				continue
+			}
+
+			prefix := fmt.Sprintf("project=%s instance=%s type=%s: ", inst.Project().Name, inst.Name(), inst.Type().String())
+			for _, line := range strings.Split(output, "\n") {
+				line = strings.TrimRight(line, "\r")
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+
+				_ = forwarder.writer.Info(prefix + line)
+			}
+		}
+
+		// Close the connections of the instances which stopped or no longer forward their console.
+		for id, forwarder := range instancesConsoleSyslogs {
+			if seen[id] {
+				continue
+			}
+
+			_ = forwarder.writer.Close()
+			delete(instancesConsoleSyslogs, id)
+		}
+	}
+
+	return f, task.Every(5 * time.Second)
+}
+
+// instanceConsoleSyslogDial connects to the host syslog ("local") or to a remote one ("udp://host:port" or "tcp://host:port").
+func instanceConsoleSyslogDial(target string) (*syslog.Writer, error) {
+	network, address := "", ""
+	if target != "local" {
+		network, address, _ = strings.Cut(target, "://")
+	}
+
+	return syslog.Dial(network, address, syslog.LOG_DAEMON|syslog.LOG_INFO, "incus-console")
+}
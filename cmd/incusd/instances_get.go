@@ -25,6 +25,9 @@ import (
 	"github.com/lxc/incus/v6/shared/util"
 )
 
+// instancesStateThreads is the maximum number of instance states rendered at once by a member.
+const instancesStateThreads = 16
+
 // swagger:operation GET /1.0/instances instances instances_get
 //
 //  Get the instances
@@ -89,6 +92,9 @@ import (
 //
 //  Returns a list of instances (basic structs).
 //
+//  With `state=true`, the instances are returned along with their state
+//  (`InstanceFull` structs without snapshots and backups).
+//
 //  ---
 //  produces:
 //    - application/json
@@ -107,6 +113,10 @@ import (
 //      name: all-projects
 //      description: Retrieve instances from all projects
 //      type: boolean
+//    - in: query
+//      name: state
+//      description: Include the state of the instances
+//      type: boolean
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -205,6 +215,9 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		recursion = 0
 	}
 
+	// With recursion=1, state=true adds the instance state without the more expensive snapshots and backups.
+	withState := recursion == 1 && util.IsTrue(r.FormValue("state"))
+
 	// Parse filter value.
 	filterStr := r.FormValue("filter")
 	clauses, err := filter.Parse(filterStr, filter.QueryOperatorSet())
@@ -327,6 +340,23 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 			go func(memberAddress string, instances []db.Instance) {
 				defer wg.Done()
 
+				if withState {
+					apiInsts, err := doInstancesStateGetFromNode(filteredProjects, memberAddress, allProjects, networkCert, s.ServerCert(), r)
+					if err != nil {
+						for _, inst := range instances {
+							resultErrListAppend(inst, err)
+						}
+
+						return
+					}
+
+					for _, apiInst := range apiInsts {
+						resultFullListAppend(&apiInst)
+					}
+
+					return
+				}
+
 				if recursion == 1 {
 					apiInsts, err := doInstancesGetFromNode(filteredProjects, memberAddress, allProjects, networkCert, s.ServerCert(), r)
 					if err != nil {
@@ -370,7 +400,11 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 				}})
 			}
 		} else {
+			// Rendering the state is mostly spent waiting on the instances (agent, cgroups), allow more of it to run at once.
 			threads := min(len(instances), 4)
+			if withState {
+				threads = min(len(instances), instancesStateThreads)
+			}
 
 			hostInterfaces, _ := net.Interfaces()
 
@@ -408,10 +442,20 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 							c, _, err := inst.Render()
 							if err != nil {
 								resultErrListAppend(dbInst, err)
-							} else {
-								resultFullListAppend(&api.InstanceFull{Instance: *c.(*api.Instance)})
+								continue
+							}
+
+							instFull := &api.InstanceFull{Instance: *c.(*api.Instance)}
+
+							if withState {
+								instFull.State, err = inst.RenderState(hostInterfaces)
+								if err != nil {
+									resultErrListAppend(dbInst, err)
+									continue
+								}
 							}
 
+							resultFullListAppend(instFull)
 							continue
 						}
 
@@ -463,7 +507,7 @@ func instancesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SyncResponse(true, resultList)
 	}
 
-	if recursion == 1 {
+	if recursion == 1 && !withState {
 		resultList := make([]*api.Instance, 0, len(resultFullList))
 		for i := range resultFullList {
 			resultList = append(resultList, &resultFullList[i].Instance)
@@ -526,6 +570,57 @@ func doInstancesGetFromNode(projects []string, node string, allProjects bool, ne
 	return containers, err
 }
 
+// Fetch the instances and their state from the given remote node, using the
+// rest API and with a timeout of 30 seconds.
+func doInstancesStateGetFromNode(projects []string, node string, allProjects bool, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request) ([]api.InstanceFull, error) {
+	f := func() ([]api.InstanceFull, error) {
+		client, err := cluster.Connect(node, networkCert, serverCert, r, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to connect to member %s: %w", node, err)
+		}
+
+		var instances []api.InstanceFull
+		if allProjects {
+			instances, err = client.GetInstancesWithStateAllProjects(api.InstanceTypeAny, nil)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get instances from member %s: %w", node, err)
+			}
+		} else {
+			for _, project := range projects {
+				client = client.UseProject(project)
+
+				tmpInstances, err := client.GetInstancesWithState(api.InstanceTypeAny, nil)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get instances from member %s: %w", node, err)
+				}
+
+				instances = append(instances, tmpInstances...)
+			}
+		}
+
+		return instances, nil
+	}
+
+	timeout := time.After(30 * time.Second)
+	done := make(chan struct{})
+
+	var instances []api.InstanceFull
+	var err error
+
+	go func() {
+		instances, err = f()
+		done <- struct{}{}
+	}()
+
+	select {
+	case <-timeout:
+		err = fmt.Errorf("Timeout getting instances from member %s", node)
+	case <-done:
+	}
+
+	return instances, err
+}
+
 func doInstancesFullGetFromNode(projects []string, node string, allProjects bool, networkCert *localtls.CertInfo, serverCert *localtls.CertInfo, r *http.Request) ([]api.InstanceFull, error) {
 	f := func() ([]api.InstanceFull, error) {
 		client, err := cluster.Connect(node, networkCert, serverCert, r, true)
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// instancesOOMKills holds the out of memory kill counters last seen for each running container (by instance ID).
+var instancesOOMKills map[int]int64
+
+// instancesOOMMonitorTask emits a lifecycle event whenever the out of memory kill counter of a container goes up.
+func instancesOOMMonitorTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		insts, err := instance.LoadNodeAll(s, instancetype.Container)
+		if err != nil {
+			logger.Warn("Failed loading instances for out of memory monitoring", logger.Ctx{"err": err})
+			return
+		}
+
+		// On the first run, only record the current counters so existing kills aren't reported again.
+		firstRun := instancesOOMKills == nil
+
+		oomKills := make(map[int]int64, len(insts))
+		for _, inst := range insts {
+			if !inst.IsRunning() {
+				continue
+			}
+
+			cg, err := inst.CGroup()
+			if err != nil {
+				continue
+			}
+
+			count, err := cg.GetOOMKills()
+			if err != nil || count < 0 {
+				continue
+			}
+
+			oomKills[inst.ID()] = count
+
+			if firstRun || count <= instancesOOMKills[inst.ID()] {
+				continue
+			}
+
+			logger.Warn("Out of memory kill detected in instance", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "count": count})
+			s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceOOMKilled.Event(inst, map[string]any{"oom_kills": count - instancesOOMKills[inst.ID()]}))
+		}
+
+		instancesOOMKills = oomKills
+	}
+
+	return f, task.Every(30 * time.Second)
+}
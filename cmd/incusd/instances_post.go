@@ -498,6 +498,11 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 
 	targetProject := projectName
 
+	err := instanceSourceAccessCheck(s, r, sourceProject, req.Source.Source)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	source, err := instance.LoadByProjectAndName(s, sourceProject, req.Source.Source)
 	if err != nil {
 		return response.SmartError(err)
@@ -622,6 +627,7 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 			refreshExcludeOlder:  req.Source.RefreshExcludeOlder,
 			applyTemplateTrigger: true,
 			allowInconsistent:    req.Source.AllowInconsistent,
+			allowRunning:         req.Source.AllowRunning,
 		}, op)
 		if err != nil {
 			return err
@@ -642,80 +648,55 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 }
 
 func createFromBackup(s *state.State, r *http.Request, projectName string, data io.Reader, pool string, instanceName string) response.Response {
-	reverter := revert.New()
-	defer reverter.Fail()
-
-	// Create temporary file to store uploaded backup data.
-	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
+	backupFile, bInfo, req, err := createFromBackupLoad(r.Context(), s, projectName, data, pool, instanceName)
 	if err != nil {
-		return response.InternalError(err)
+		return response.SmartError(err)
 	}
 
-	defer func() { _ = os.Remove(backupFile.Name()) }()
-	reverter.Add(func() { _ = backupFile.Close() })
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
 
-	// Stream uploaded backup data into temporary file.
-	_, err = io.Copy(backupFile, data)
-	if err != nil {
-		return response.InternalError(err)
+		return createFromBackupFile(s, bInfo, backupFile, req, instanceName != "", op)
 	}
 
-	// Detect squashfs compression and convert to tarball.
-	_, err = backupFile.Seek(0, io.SeekStart)
-	if err != nil {
-		return response.InternalError(err)
-	}
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
 
-	_, algo, decomArgs, err := archive.DetectCompressionFile(backupFile)
+	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
 	if err != nil {
+		_ = backupFile.Close()
 		return response.InternalError(err)
 	}
 
-	if algo == ".squashfs" {
-		// Pass the temporary file as program argument to the decompression command.
-		decomArgs := append(decomArgs, backupFile.Name())
-
-		// Create temporary file to store the decompressed tarball in.
-		tarFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_decompress_", backup.WorkingDirPrefix))
-		if err != nil {
-			return response.InternalError(err)
-		}
-
-		defer func() { _ = os.Remove(tarFile.Name()) }()
-
-		// Decompress to tarFile temporary file.
-		err = archive.ExtractWithFds(decomArgs[0], decomArgs[1:], nil, nil, tarFile)
-		if err != nil {
-			return response.InternalError(err)
-		}
-
-		// We don't need the original squashfs file anymore.
-		_ = backupFile.Close()
-		_ = os.Remove(backupFile.Name())
-
-		// Replace the backup file handle with the handle to the tar file.
-		backupFile = tarFile
-	}
+	return operations.OperationResponse(op)
+}
 
-	// Parse the backup information.
-	_, err = backupFile.Seek(0, io.SeekStart)
+// createFromBackupFile creates the instance from the loaded backup file and finishes its creation.
+func createFromBackupFile(s *state.State, bInfo *backup.Info, backupFile *os.File, req *api.InstancesPost, renamed bool, op *operations.Operation) error {
+	_, err := instanceCreateFromBackupFile(s, bInfo, backupFile, renamed)
 	if err != nil {
-		return response.InternalError(err)
+		return err
 	}
 
-	bInfo, err := backup.GetInfo(backupFile, s.OS, backupFile.Name())
+	return instanceCreateFinish(s, req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+}
+
+// createFromBackupLoad stores the instance backup data into a temporary tarball, parses its information and
+// checks that the instance can be created from it. The temporary file only needs closing by the caller.
+func createFromBackupLoad(ctx context.Context, s *state.State, projectName string, data io.Reader, pool string, instanceName string) (*os.File, *backup.Info, *api.InstancesPost, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	backupFile, bInfo, err := backupFileLoad(s, data)
 	if err != nil {
-		return response.BadRequest(err)
+		return nil, nil, nil, err
 	}
 
-	// Detect broken legacy backups.
-	if bInfo.Config == nil {
-		return response.BadRequest(errors.New("Backup file is missing required information"))
-	}
+	reverter.Add(func() { _ = backupFile.Close() })
 
 	// Check project permissions.
 	var req api.InstancesPost
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		req = api.InstancesPost{
 			InstancePut: bInfo.Config.Container.InstancePut,
 			Name:        bInfo.Name,
@@ -726,7 +707,7 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return project.AllowInstanceCreation(tx, projectName, req)
 	})
 	if err != nil {
-		return response.SmartError(err)
+		return nil, nil, nil, err
 	}
 
 	bInfo.Project = projectName
@@ -751,7 +732,7 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		"snapshots": bInfo.Snapshots,
 	})
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check storage pool exists.
 		_, _, _, err = tx.GetStoragePoolInAnyState(ctx, bInfo.Pool)
 
@@ -762,100 +743,230 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		// the backup.yaml) or the pool has been specified directly from the user restoring
 		// the backup then we cannot proceed so return an error.
 		if *bInfo.OptimizedStorage || pool != "" {
-			return response.InternalError(fmt.Errorf("Storage pool not found: %w", err))
+			return nil, nil, nil, fmt.Errorf("Storage pool not found: %w", err)
 		}
 
 		var profile *api.Profile
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Otherwise try and restore to the project's default profile pool.
 			_, profile, err = tx.GetProfile(ctx, bInfo.Project, "default")
 
 			return err
 		})
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get default profile: %w", err))
+			return nil, nil, nil, fmt.Errorf("Failed to get default profile: %w", err)
 		}
 
 		_, v, err := internalInstance.GetRootDiskDevice(profile.Devices)
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get root disk device: %w", err))
+			return nil, nil, nil, fmt.Errorf("Failed to get root disk device: %w", err)
 		}
 
 		// Use the default-profile's root pool.
 		bInfo.Pool = v["pool"]
 	} else if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
-	// Copy reverter so far so we can use it inside run after this function has finished.
-	runReverter := reverter.Clone()
+	reverter.Success()
 
-	run := func(op *operations.Operation) error {
-		defer func() { _ = backupFile.Close() }()
-		defer runReverter.Fail()
+	return backupFile, bInfo, &req, nil
+}
 
-		pool, err := storagePools.LoadByName(s, bInfo.Pool)
-		if err != nil {
-			return err
-		}
+// backupFileLoad stores the backup data into a temporary tarball and parses its information.
+// The temporary file is already removed from the filesystem when returned and only needs closing by the caller.
+func backupFileLoad(s *state.State, data io.Reader) (*os.File, *backup.Info, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
 
-		// Check if the backup is optimized that the source pool driver matches the target pool driver.
-		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
-			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
-		}
+	// Create temporary file to store uploaded backup data.
+	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() { _ = os.Remove(backupFile.Name()) }()
+	reverter.Add(func() { _ = backupFile.Close() })
+
+	// Stream uploaded backup data into temporary file.
+	_, err = io.Copy(backupFile, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Detect squashfs compression and convert to tarball.
+	_, err = backupFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, algo, decomArgs, err := archive.DetectCompressionFile(backupFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if algo == ".squashfs" {
+		// Pass the temporary file as program argument to the decompression command.
+		decomArgs := append(decomArgs, backupFile.Name())
 
-		// Dump tarball to storage. Because the backup file is unpacked and restored onto the storage
-		// device before the instance is created in the database it is necessary to return two functions;
-		// a post hook that can be run once the instance has been created in the database to run any
-		// storage layer finalisations, and a revert hook that can be run if the instance database load
-		// process fails that will remove anything created thus far.
-		postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, backupFile, nil)
+		// Create temporary file to store the decompressed tarball in.
+		tarFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_decompress_", backup.WorkingDirPrefix))
 		if err != nil {
-			return fmt.Errorf("Create instance from backup: %w", err)
+			return nil, nil, err
 		}
 
-		runReverter.Add(revertHook)
+		defer func() { _ = os.Remove(tarFile.Name()) }()
+		reverter.Add(func() { _ = tarFile.Close() })
 
-		err = internalImportFromBackup(context.TODO(), s, bInfo.Project, bInfo.Name, instanceName != "")
+		// Decompress to tarFile temporary file.
+		err = archive.ExtractWithFds(decomArgs[0], decomArgs[1:], nil, nil, tarFile)
 		if err != nil {
-			return fmt.Errorf("Failed importing backup: %w", err)
+			return nil, nil, err
 		}
 
-		inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+		// We don't need the original squashfs file anymore.
+		_ = backupFile.Close()
+		_ = os.Remove(backupFile.Name())
+
+		// Replace the backup file handle with the handle to the tar file.
+		backupFile = tarFile
+	}
+
+	// Parse the backup information.
+	_, err = backupFile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bInfo, err := backup.GetInfo(backupFile, s.OS, backupFile.Name())
+	if err != nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	// Detect broken legacy backups.
+	if bInfo.Config == nil {
+		return nil, nil, api.StatusErrorf(http.StatusBadRequest, "Backup file is missing required information")
+	}
+
+	reverter.Success()
+
+	return backupFile, bInfo, nil
+}
+
+// createFromBackupURL creates an instance from a backup file downloaded from the URL of the request source.
+func createFromBackupURL(s *state.State, r *http.Request, projectName string, req *api.InstancesPost) response.Response {
+	if req.Source.URL == "" {
+		return response.BadRequest(errors.New("Missing backup URL"))
+	}
+
+	// Allow overriding the pool through the root disk device of the request.
+	var pool string
+	_, rootDev, err := internalInstance.GetRootDiskDevice(req.Devices)
+	if err == nil {
+		pool = rootDev["pool"]
+	}
+
+	allowedHosts, err := backupDownloadCheck(s, r, req.Source.URL, req.Source.Checksum)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The download happens as part of the operation, allowing for it to be cancelled.
+	ctx, cancel := context.WithCancel(s.ShutdownCtx)
+
+	run := func(op *operations.Operation) error {
+		defer cancel()
+
+		data, err := backupDownload(ctx, s, req.Source.URL, req.Source.Headers, req.Source.Checksum, allowedHosts)
 		if err != nil {
-			return fmt.Errorf("Failed loading instance: %w", err)
+			return err
 		}
 
-		// Clean up created instance if the post hook fails below.
-		runReverter.Add(func() { _ = inst.Delete(true) })
+		defer func() { _ = data.Close() }()
 
-		// Run the storage post hook to perform any final actions now that the instance has been created
-		// in the database (this normally includes unmounting volumes that were mounted).
-		if postHook != nil {
-			err = postHook(inst)
-			if err != nil {
-				return fmt.Errorf("Post hook failed: %w", err)
-			}
+		backupFile, bInfo, instReq, err := createFromBackupLoad(ctx, s, projectName, data, pool, req.Name)
+		if err != nil {
+			return err
 		}
 
-		runReverter.Success()
+		defer func() { _ = backupFile.Close() }()
 
-		return instanceCreateFinish(s, &req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
+		return createFromBackupFile(s, bInfo, backupFile, instReq, req.Name != "", op)
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
 	}
 
 	resources := map[string][]api.URL{}
-	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", bInfo.Name)}
+	if req.Name != "" {
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", req.Name)}
+	}
 
-	op, err := operations.OperationCreate(s, bInfo.Project, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, nil, nil, r)
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.BackupRestore, resources, nil, run, onCancel, nil, r)
 	if err != nil {
+		cancel()
 		return response.InternalError(err)
 	}
 
-	reverter.Success()
 	return operations.OperationResponse(op)
 }
 
+// instanceCreateFromBackupFile creates an instance from an already validated backup file.
+func instanceCreateFromBackupFile(s *state.State, bInfo *backup.Info, backupFile io.ReadSeeker, renamed bool) (instance.Instance, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	pool, err := storagePools.LoadByName(s, bInfo.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the backup is optimized that the source pool driver matches the target pool driver.
+	if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
+		return nil, fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
+	}
+
+	// Dump tarball to storage. Because the backup file is unpacked and restored onto the storage
+	// device before the instance is created in the database it is necessary to return two functions;
+	// a post hook that can be run once the instance has been created in the database to run any
+	// storage layer finalisations, and a revert hook that can be run if the instance database load
+	// process fails that will remove anything created thus far.
+	postHook, revertHook, err := pool.CreateInstanceFromBackup(*bInfo, backupFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Create instance from backup: %w", err)
+	}
+
+	reverter.Add(revertHook)
+
+	err = internalImportFromBackup(context.TODO(), s, bInfo.Project, bInfo.Name, renamed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed importing backup: %w", err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, bInfo.Project, bInfo.Name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance: %w", err)
+	}
+
+	// Clean up created instance if the post hook fails below.
+	reverter.Add(func() { _ = inst.Delete(true) })
+
+	// Run the storage post hook to perform any final actions now that the instance has been created
+	// in the database (this normally includes unmounting volumes that were mounted).
+	if postHook != nil {
+		err = postHook(inst)
+		if err != nil {
+			return nil, fmt.Errorf("Post hook failed: %w", err)
+		}
+	}
+
+	reverter.Success()
+	return inst, nil
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -911,6 +1022,11 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 
 	// If we're getting binary content, process separately
 	if r.Header.Get("Content-Type") == "application/octet-stream" {
+		err := instanceNameAccessCheck(d, r, r.Header.Get("X-Incus-name"))
+		if err != nil {
+			return response.SmartError(err)
+		}
+
 		return createFromBackup(s, r, targetProjectName, r.Body, r.Header.Get("X-Incus-pool"), r.Header.Get("X-Incus-name"))
 	}
 
@@ -921,6 +1037,31 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Fill in the request from the blueprint.
+	if req.BlueprintVersion != 0 && req.Blueprint == "" {
+		return response.BadRequest(errors.New("A blueprint version requires a blueprint"))
+	}
+
+	if req.Blueprint != "" {
+		blueprint, err := blueprintLoad(r.Context(), s, targetProjectName, req.Blueprint, req.BlueprintVersion)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		blueprintApply(blueprint, &req)
+	}
+
+	// Certificates restricted to some instances can only create instances matching those.
+	err = instanceNameAccessCheck(d, r, req.Name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Backups stored on a remote server are downloaded by the server itself.
+	if req.Source.Type == "backup" {
+		return createFromBackupURL(s, r, targetProjectName, &req)
+	}
+
 	// Set type from URL if missing
 	if req.Type == "" {
 		req.Type = api.InstanceTypeContainer // Default to container if not specified.
@@ -1197,6 +1338,20 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			candidateMembers = []db.NodeInfo{*targetMemberInfo}
 		}
 
+		// Rank the candidate members according to the placement policy.
+		var placementReq *placementRequest
+		if targetMemberInfo == nil {
+			placementReq, err = instancePlacementRequest(targetProjectName, db.ExpandInstanceConfig(req.Config, profiles), db.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles).CloneNative(), req.Type)
+			if err != nil {
+				return response.BadRequest(err)
+			}
+
+			candidateMembers, err = instancePlacementSort(r.Context(), s, candidateMembers, placementReq)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			leaderAddress, err := s.Cluster.LeaderAddress()
@@ -1220,7 +1375,7 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		// If no target member was selected yet, pick the member with the least number of instances.
+		// If no target member was selected yet, pick the best ranked member.
 		if targetMemberInfo == nil && len(candidateMembers) > 0 {
 			targetMemberInfo = &candidateMembers[0]
 		}
@@ -1228,6 +1383,10 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		if targetMemberInfo == nil {
 			return response.InternalError(errors.New("Couldn't find a cluster member for the instance"))
 		}
+
+		if placementReq != nil {
+			instancePlacementReserve(targetMemberInfo.Name, placementReq)
+		}
 	}
 
 	// Record the cluster group as a volatile config key if present.
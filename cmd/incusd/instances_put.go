@@ -139,6 +139,11 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 			if !inst.IsFrozen() {
 				continue
 			}
+
+		case internalInstance.SyncTime:
+			if inst.Type() != instancetype.VM || !inst.IsRunning() || inst.IsFrozen() {
+				continue
+			}
 		}
 
 		instances = append(instances, inst)
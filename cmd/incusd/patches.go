@@ -208,7 +208,7 @@ func patchClusteringServerCertTrust(name string, d *Daemon) error {
 	}
 
 	// Add our server cert to DB trust store.
-	serverCert, err := internalUtil.LoadServerCert(d.os.VarDir)
+	serverCert, err := internalUtil.LoadServerCert(d.os.VarDir, d.localConfig.ServerKeyURI())
 	if err != nil {
 		return err
 	}
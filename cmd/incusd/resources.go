@@ -1,15 +1,20 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 
 	"github.com/gorilla/mux"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/resources"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
 
@@ -80,9 +85,68 @@ func api10ResourcesGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Record which instances are using the mediated GPU devices.
+	if res.GPU.Total > 0 {
+		err = resourcesGPUMdevUsedBy(s, res)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.SyncResponse(true, res)
 }
 
+// resourcesGPUMdevUsedBy fills in the list of instances using each mediated GPU device profile.
+func resourcesGPUMdevUsedBy(s *state.State, res *api.Resources) error {
+	instances, err := instance.LoadNodeAll(s, instancetype.VM)
+	if err != nil {
+		return fmt.Errorf("Failed loading local instances: %w", err)
+	}
+
+	// Map the mediated device UUIDs to the instance using them.
+	mdevUsers := map[string]string{}
+	for _, inst := range instances {
+		localConfig := inst.LocalConfig()
+		for _, dev := range inst.ExpandedDevices().Sorted() {
+			if dev.Config["type"] != "gpu" || dev.Config["gputype"] != "mdev" {
+				continue
+			}
+
+			mdevUUID := localConfig[fmt.Sprintf("volatile.%s.vgpu.uuid", dev.Name)]
+			if mdevUUID == "" {
+				continue
+			}
+
+			mdevUsers[mdevUUID] = api.NewURL().Path(version.APIVersion, "instances", inst.Name()).Project(inst.Project().Name).String()
+		}
+	}
+
+	fillUsedBy := func(profiles map[string]api.ResourcesGPUCardMdev) {
+		for name, profile := range profiles {
+			profile.UsedBy = []string{}
+			for _, mdevUUID := range profile.Devices {
+				if mdevUsers[mdevUUID] != "" {
+					profile.UsedBy = append(profile.UsedBy, mdevUsers[mdevUUID])
+				}
+			}
+
+			profiles[name] = profile
+		}
+	}
+
+	for _, card := range res.GPU.Cards {
+		fillUsedBy(card.Mdev)
+
+		if card.SRIOV != nil {
+			for _, vf := range card.SRIOV.VFs {
+				fillUsedBy(vf.Mdev)
+			}
+		}
+	}
+
+	return nil
+}
+
 // swagger:operation GET /1.0/storage-pools/{name}/resources storage storage_pool_resources
 //
 //	Get storage pool resources information
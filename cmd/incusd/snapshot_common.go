@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -120,3 +121,84 @@ func cronSpecIsNow(spec string) (bool, error) {
 
 	return true, nil
 }
+
+// snapshotRetentionPeriods maps the snapshot retention configuration keys to a function returning the period
+// a snapshot creation date falls in.
+var snapshotRetentionPeriods = map[string]func(t time.Time) string{
+	"snapshots.keep_hourly": func(t time.Time) string { return t.Format("2006-01-02 15") },
+	"snapshots.keep_daily":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"snapshots.keep_weekly": func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	},
+	"snapshots.keep_monthly": func(t time.Time) string { return t.Format("2006-01") },
+	"snapshots.keep_yearly":  func(t time.Time) string { return t.Format("2006") },
+}
+
+// snapshotRetentionEnabled returns whether a snapshot retention policy is set in the config.
+func snapshotRetentionEnabled(config map[string]string) bool {
+	for key := range snapshotRetentionPeriods {
+		if config[key] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// snapshotsOutsideRetention returns the indexes of the snapshots, given by creation date, which aren't kept by
+// the retention policy set through the snapshots.keep_* configuration keys.
+// Each rule keeps the most recent snapshot of each of the last N periods (hours, days, ...) having snapshots and
+// a snapshot is kept as long as any of the rules keeps it. Nothing is pruned if no rule is set.
+func snapshotsOutsideRetention(creationDates []time.Time, config map[string]string) ([]int, error) {
+	if !snapshotRetentionEnabled(config) {
+		return nil, nil
+	}
+
+	// Sort the snapshots from the most recent to the oldest.
+	order := make([]int, len(creationDates))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return creationDates[order[i]].After(creationDates[order[j]])
+	})
+
+	kept := make(map[int]bool, len(creationDates))
+
+	for key, period := range snapshotRetentionPeriods {
+		if config[key] == "" {
+			continue
+		}
+
+		keep, err := strconv.Atoi(config[key])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s value: %w", key, err)
+		}
+
+		seen := map[string]bool{}
+		for _, i := range order {
+			if len(seen) >= keep {
+				break
+			}
+
+			p := period(creationDates[i])
+			if seen[p] {
+				continue
+			}
+
+			seen[p] = true
+			kept[i] = true
+		}
+	}
+
+	pruned := []int{}
+	for i := range creationDates {
+		if !kept[i] {
+			pruned = append(pruned, i)
+		}
+	}
+
+	return pruned, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -39,6 +40,41 @@ func (s *snapshotCommonTestSuite) TestSnapshotScheduling() {
 	op.Done(nil)
 }
 
+func (s *snapshotCommonTestSuite) TestSnapshotsOutsideRetention() {
+	base := time.Date(2025, time.March, 10, 12, 30, 0, 0, time.UTC)
+
+	// Two snapshots per hour over the last two days.
+	dates := []time.Time{}
+	for i := range 96 {
+		dates = append(dates, base.Add(-time.Duration(i)*30*time.Minute))
+	}
+
+	// No policy, nothing pruned.
+	pruned, err := snapshotsOutsideRetention(dates, map[string]string{})
+	s.Req.Nil(err)
+	s.Empty(pruned)
+
+	// Keep the most recent snapshot of the last 3 hours.
+	pruned, err = snapshotsOutsideRetention(dates, map[string]string{"snapshots.keep_hourly": "3"})
+	s.Req.Nil(err)
+	s.Len(pruned, 93)
+	s.NotContains(pruned, 0) // 12:30
+	s.Contains(pruned, 1)    // 12:00
+	s.NotContains(pruned, 2) // 11:30
+	s.NotContains(pruned, 4) // 10:30
+
+	// Rules are combined.
+	pruned, err = snapshotsOutsideRetention(dates, map[string]string{"snapshots.keep_hourly": "3", "snapshots.keep_daily": "3"})
+	s.Req.Nil(err)
+	s.Len(pruned, 91)
+	s.NotContains(pruned, 26) // 23:30 the day before
+	s.NotContains(pruned, 74) // 23:30 two days before
+
+	// Invalid values are reported.
+	_, err = snapshotsOutsideRetention(dates, map[string]string{"snapshots.keep_daily": "foo"})
+	s.Req.NotNil(err)
+}
+
 func TestSnapshotCommon(t *testing.T) {
 	suite.Run(t, &snapshotCommonTestSuite{})
 }
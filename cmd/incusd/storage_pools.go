@@ -834,6 +834,23 @@ func storagePoolPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// In clustered mode, a size change without a target grows the pool on every cluster member.
+	newSize, resize := req.Config["size"]
+	if s.ServerClustered && targetNode == "" && resize {
+		delete(req.Config, "size")
+
+		err = storagePoolGrowMembers(s, r, pool, newSize)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Reload the pool to pick up the new size.
+		pool, err = storagePools.LoadByName(s, poolName)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// In clustered mode, we differentiate between node specific and non-node specific config keys based on
 	// whether the user has specified a target to apply the config to.
 	if s.ServerClustered {
@@ -872,6 +889,56 @@ func storagePoolPut(d *Daemon, r *http.Request) response.Response {
 	return response
 }
 
+// storagePoolGrowMembers applies a new size to the storage pool on each cluster member in turn.
+func storagePoolGrowMembers(s *state.State, r *http.Request, pool storagePools.Pool, size string) error {
+	var members []db.NodeInfo
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		members, err = tx.GetNodes(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	for _, member := range members {
+		// Apply locally.
+		if member.Name == s.ServerName {
+			config := localUtil.CopyConfig(pool.Driver().Config())
+			config["size"] = size
+
+			err = pool.Update(clusterRequest.ClientTypeNormal, pool.Description(), config, nil)
+			if err != nil {
+				return fmt.Errorf("Failed resizing storage pool %q on member %q: %w", pool.Name(), member.Name, err)
+			}
+
+			continue
+		}
+
+		// Apply on the other members through a member specific update.
+		client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), r, false)
+		if err != nil {
+			return err
+		}
+
+		client = client.UseTarget(member.Name)
+
+		memberPool, etag, err := client.GetStoragePool(pool.Name())
+		if err != nil {
+			return err
+		}
+
+		memberPool.Config["size"] = size
+
+		err = client.UpdateStoragePool(pool.Name(), memberPool.Writable(), etag)
+		if err != nil {
+			return fmt.Errorf("Failed resizing storage pool %q on member %q: %w", pool.Name(), member.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // swagger:operation PATCH /1.0/storage-pools/{poolName} storage storage_pool_patch
 //
 //	Partially update the storage pool
@@ -1035,6 +1102,21 @@ func storagePoolDelete(d *Daemon, r *http.Request) response.Response {
 			return response.BadRequest(errors.New("The storage pool is currently in use"))
 		}
 
+		// The volumes kept in the trash would otherwise be lost along with the pool.
+		var trashEntries []db.TrashEntry
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			trashEntries, err = tx.GetStoragePoolTrashEntries(ctx, pool.Name())
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if len(trashEntries) > 0 {
+			return response.BadRequest(errors.New("The storage pool has entries in the trash"))
+		}
+
 		// Get the cluster notifier
 		notifier, err = cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAll)
 		if err != nil {
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var storagePoolStateCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/state",
+
+	Get: APIEndpointAction{Handler: storagePoolStateGet, AccessHandler: allowPermission(auth.ObjectTypeStoragePool, auth.EntitlementCanView, "poolName")},
+	Put: APIEndpointAction{Handler: storagePoolStatePut, AccessHandler: allowPermission(auth.ObjectTypeStoragePool, auth.EntitlementCanEdit, "poolName")},
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/state storage storage_pool_state_get
+//
+//	Get the storage pool state
+//
+//	Gets the usage and health of a specific storage pool.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Storage pool state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StoragePoolState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	res, err := pool.GetResources()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	state := api.StoragePoolState{ResourcesStoragePool: *res}
+
+	state.Health, err = pool.GetHealth()
+	if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
+		return response.InternalError(err)
+	}
+
+	return response.SyncResponse(true, state)
+}
+
+// swagger:operation PUT /1.0/storage-pools/{poolName}/state storage storage_pool_state_put
+//
+//	Change the storage pool state
+//
+//	Triggers a maintenance action (scrub) on the storage pool.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: state
+//	    description: State
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StoragePoolStatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolStatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// If a target was specified, forward the request to the relevant node.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Parse the request.
+	req := api.StoragePoolStatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	switch req.Action {
+	case "scrub":
+		err = pool.Scrub()
+		if err != nil {
+			if errors.Is(err, storageDrivers.ErrNotSupported) {
+				return response.BadRequest(fmt.Errorf("Storage pool driver %q doesn't support scrubbing", pool.Driver().Info().Name))
+			}
+
+			return response.SmartError(err)
+		}
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown state action %q", req.Action))
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.StoragePoolUpdated.Event(pool.Name(), requestor, logger.Ctx{"action": req.Action}))
+
+	return response.EmptySyncResponse
+}
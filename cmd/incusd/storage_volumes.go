@@ -654,6 +654,10 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 			return createStoragePoolVolumeFromISO(s, r, request.ProjectParam(r), projectName, r.Body, poolName, r.Header.Get("X-Incus-name"))
 		}
 
+		if r.Header.Get("X-Incus-type") == "disk" {
+			return createStoragePoolVolumeFromDiskImage(s, r, request.ProjectParam(r), projectName, r.Body, poolName, r.Header.Get("X-Incus-name"), r.Header.Get("X-Incus-format"))
+		}
+
 		return createStoragePoolVolumeFromBackup(s, r, request.ProjectParam(r), projectName, r.Body, poolName, r.Header.Get("X-Incus-name"))
 	}
 
@@ -699,6 +703,26 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Currently not allowed to create storage volumes of type %q", req.Type))
 	}
 
+	// When copying from another project, the requestor must also be allowed to access the source volume.
+	if req.Source.Type == "copy" && req.Source.Project != "" && req.Source.Project != request.ProjectParam(r) {
+		srcProjectName, err := project.StorageVolumeProject(s.DB.Cluster, req.Source.Project, db.StoragePoolVolumeTypeCustom)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		srcPoolName := req.Source.Pool
+		if srcPoolName == "" {
+			srcPoolName = poolName
+		}
+
+		srcVolumeName, _, _ := api.GetParentAndSnapshotName(req.Source.Name)
+
+		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectStorageVolume(srcProjectName, srcPoolName, db.StoragePoolVolumeTypeNameCustom, srcVolumeName, req.Source.Location), auth.EntitlementCanView)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	var poolID int64
 	var dbVolume *db.StorageVolume
 
@@ -766,6 +790,8 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return doVolumeCreateOrCopy(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	case "migration":
 		return doVolumeMigration(s, r, request.ProjectParam(r), projectName, poolName, &req)
+	case "backup":
+		return createStoragePoolVolumeFromBackupURL(s, r, request.ProjectParam(r), projectName, poolName, &req)
 	default:
 		return response.BadRequest(fmt.Errorf("Unknown source type %q", req.Source.Type))
 	}
@@ -2204,6 +2230,19 @@ func storagePoolVolumeDelete(d *Daemon, r *http.Request) response.Response {
 	op := &operations.Operation{}
 	op.SetRequestor(r)
 
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Keep a copy of custom volumes in the trash so the deletion can be undone.
+	if volumeType == db.StoragePoolVolumeTypeCustom && trashEnabled(s) {
+		cleanup, err := trashStorageVolume(s, volumeProjectName, pool, volumeName, op)
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed moving storage volume to the trash: %w", err))
+		}
+
+		reverter.Add(cleanup)
+	}
+
 	switch volumeType {
 	case db.StoragePoolVolumeTypeCustom:
 		err = pool.DeleteCustomVolume(volumeProjectName, volumeName, op)
@@ -2217,6 +2256,7 @@ func storagePoolVolumeDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	reverter.Success()
 	return response.EmptySyncResponse
 }
 
@@ -2285,14 +2325,178 @@ func createStoragePoolVolumeFromISO(s *state.State, r *http.Request, requestProj
 	return operations.OperationResponse(op)
 }
 
+// createStoragePoolVolumeFromDiskImage creates a custom block volume from an uploaded raw, qcow2 or vmdk disk image.
+func createStoragePoolVolumeFromDiskImage(s *state.State, r *http.Request, requestProjectName string, projectName string, data io.Reader, pool string, volName string, format string) response.Response {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if volName == "" {
+		return response.BadRequest(errors.New("Missing volume name"))
+	}
+
+	if format == "" {
+		format = "raw"
+	}
+
+	if !slices.Contains(storagePools.DiskImageFormats, format) {
+		return response.BadRequest(fmt.Errorf("Unsupported disk image format %q", format))
+	}
+
+	// Create temporary file to store uploaded disk image.
+	imgFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_disk_", backup.WorkingDirPrefix))
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Add(func() {
+		_ = imgFile.Close()
+		_ = os.Remove(imgFile.Name())
+	})
+
+	// Stream uploaded disk image into temporary file.
+	_, err = io.Copy(imgFile, data)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = imgFile.Close()
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// Copy reverter so far so we can use it inside run after this function has finished.
+	runReverter := reverter.Clone()
+
+	run := func(op *operations.Operation) error {
+		defer runReverter.Fail()
+
+		pool, err := storagePools.LoadByName(s, pool)
+		if err != nil {
+			return err
+		}
+
+		// Convert the disk image into the new volume.
+		err = pool.CreateCustomVolumeFromDiskImage(projectName, volName, imgFile.Name(), format, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating custom volume from disk image: %w", err)
+		}
+
+		runReverter.Success()
+		_ = os.Remove(imgFile.Name())
+
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", pool, "volumes", "custom", volName)}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.VolumeCreate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	reverter.Success()
+	return operations.OperationResponse(op)
+}
+
+// createStoragePoolVolumeFromBackupURL creates a custom volume from a backup file downloaded from the URL of the request source.
+func createStoragePoolVolumeFromBackupURL(s *state.State, r *http.Request, requestProjectName string, projectName string, pool string, req *api.StorageVolumesPost) response.Response {
+	if req.Source.URL == "" {
+		return response.BadRequest(errors.New("Missing backup URL"))
+	}
+
+	allowedHosts, err := backupDownloadCheck(s, r, req.Source.URL, req.Source.Checksum)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// The download happens as part of the operation, allowing for it to be cancelled.
+	ctx, cancel := context.WithCancel(s.ShutdownCtx)
+
+	run := func(op *operations.Operation) error {
+		defer cancel()
+
+		data, err := backupDownload(ctx, s, req.Source.URL, req.Source.Headers, req.Source.Checksum, allowedHosts)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = data.Close() }()
+
+		backupFile, bInfo, targetPool, err := storagePoolVolumeFromBackupLoad(ctx, s, projectName, data, pool, req.Name)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = backupFile.Close() }()
+
+		return storagePoolVolumeFromBackupCreate(targetPool, bInfo, backupFile)
+	}
+
+	onCancel := func(op *operations.Operation) error {
+		cancel()
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	if req.Name != "" {
+		resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", pool, "volumes", db.StoragePoolVolumeTypeNameCustom, req.Name)}
+	}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, onCancel, nil, r)
+	if err != nil {
+		cancel()
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
 func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestProjectName string, projectName string, data io.Reader, pool string, volName string) response.Response {
+	backupFile, bInfo, targetPool, err := storagePoolVolumeFromBackupLoad(r.Context(), s, projectName, data, pool, volName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(op *operations.Operation) error {
+		defer func() { _ = backupFile.Close() }()
+
+		return storagePoolVolumeFromBackupCreate(targetPool, bInfo, backupFile)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", bInfo.Pool, "volumes", string(bInfo.Type), bInfo.Name)}
+
+	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		_ = backupFile.Close()
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// storagePoolVolumeFromBackupCreate creates the custom volume from the loaded backup file.
+func storagePoolVolumeFromBackupCreate(targetPool storagePools.Pool, bInfo *backup.Info, backupFile *os.File) error {
+	// Dump tarball to storage.
+	err := targetPool.CreateCustomVolumeFromBackup(*bInfo, backupFile, nil)
+	if err != nil {
+		return fmt.Errorf("Create custom volume from backup: %w", err)
+	}
+
+	return nil
+}
+
+// storagePoolVolumeFromBackupLoad stores the custom volume backup data into a temporary tarball, parses its
+// information and loads the target storage pool. The temporary file only needs closing by the caller.
+func storagePoolVolumeFromBackupLoad(ctx context.Context, s *state.State, projectName string, data io.Reader, pool string, volName string) (*os.File, *backup.Info, storagePools.Pool, error) {
 	reverter := revert.New()
 	defer reverter.Fail()
 
 	// Create temporary file to store uploaded backup data.
 	backupFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_", backup.WorkingDirPrefix))
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
 	defer func() { _ = os.Remove(backupFile.Name()) }()
@@ -2301,18 +2505,18 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 	// Stream uploaded backup data into temporary file.
 	_, err = io.Copy(backupFile, data)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
 	// Detect squashfs compression and convert to tarball.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
 	_, algo, decomArgs, err := archive.DetectCompressionFile(backupFile)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
 	if algo == ".squashfs" {
@@ -2322,7 +2526,7 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		// Create temporary file to store the decompressed tarball in.
 		tarFile, err := os.CreateTemp(internalUtil.VarPath("backups"), fmt.Sprintf("%s_decompress_", backup.WorkingDirPrefix))
 		if err != nil {
-			return response.InternalError(err)
+			return nil, nil, nil, err
 		}
 
 		defer func() { _ = os.Remove(tarFile.Name()) }()
@@ -2330,7 +2534,7 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		// Decompress to tarFile temporary file.
 		err = archive.ExtractWithFds(decomArgs[0], decomArgs[1:], nil, nil, tarFile)
 		if err != nil {
-			return response.InternalError(err)
+			return nil, nil, nil, err
 		}
 
 		// We don't need the original squashfs file anymore.
@@ -2344,13 +2548,13 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 	// Parse the backup information.
 	_, err = backupFile.Seek(0, io.SeekStart)
 	if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
 	logger.Debug("Reading backup file info")
 	bInfo, err := backup.GetInfo(backupFile, s.OS, backupFile.Name())
 	if err != nil {
-		return response.BadRequest(err)
+		return nil, nil, nil, api.StatusErrorf(http.StatusBadRequest, "%v", err)
 	}
 
 	bInfo.Project = projectName
@@ -2375,7 +2579,7 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		"snapshots": bInfo.Snapshots,
 	})
 
-	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		// Check storage pool exists.
 		_, _, _, err = tx.GetStoragePoolInAnyState(ctx, bInfo.Pool)
 
@@ -2386,67 +2590,47 @@ func createStoragePoolVolumeFromBackup(s *state.State, r *http.Request, requestP
 		// the backup.yaml) or the pool has been specified directly from the user restoring
 		// the backup then we cannot proceed so return an error.
 		if *bInfo.OptimizedStorage || pool != "" {
-			return response.InternalError(fmt.Errorf("Storage pool not found: %w", err))
+			return nil, nil, nil, fmt.Errorf("Storage pool not found: %w", err)
 		}
 
 		var profile *api.Profile
 
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			// Otherwise try and restore to the project's default profile pool.
 			_, profile, err = tx.GetProfile(ctx, bInfo.Project, "default")
 
 			return err
 		})
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get default profile: %w", err))
+			return nil, nil, nil, fmt.Errorf("Failed to get default profile: %w", err)
 		}
 
 		_, v, err := internalInstance.GetRootDiskDevice(profile.Devices)
 		if err != nil {
-			return response.InternalError(fmt.Errorf("Failed to get root disk device: %w", err))
+			return nil, nil, nil, fmt.Errorf("Failed to get root disk device: %w", err)
 		}
 
 		// Use the default-profile's root pool.
 		bInfo.Pool = v["pool"]
 	} else if err != nil {
-		return response.InternalError(err)
+		return nil, nil, nil, err
 	}
 
-	// Copy reverter so far so we can use it inside run after this function has finished.
-	runReverter := reverter.Clone()
-
-	run := func(op *operations.Operation) error {
-		defer func() { _ = backupFile.Close() }()
-		defer runReverter.Fail()
-
-		pool, err := storagePools.LoadByName(s, bInfo.Pool)
-		if err != nil {
-			return err
-		}
-
-		// Check if the backup is optimized that the source pool driver matches the target pool driver.
-		if *bInfo.OptimizedStorage && pool.Driver().Info().Name != bInfo.Backend {
-			return fmt.Errorf("Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, pool.Driver().Info().Name)
-		}
-
-		// Dump tarball to storage.
-		err = pool.CreateCustomVolumeFromBackup(*bInfo, backupFile, nil)
-		if err != nil {
-			return fmt.Errorf("Create custom volume from backup: %w", err)
-		}
-
-		runReverter.Success()
-		return nil
+	targetPool, err := storagePools.LoadByName(s, bInfo.Pool)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	resources := map[string][]api.URL{}
-	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", bInfo.Pool, "volumes", string(bInfo.Type), bInfo.Name)}
-
-	op, err := operations.OperationCreate(s, requestProjectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
-	if err != nil {
-		return response.InternalError(err)
+	// Check if the backup is optimized that the source pool driver matches the target pool driver.
+	if *bInfo.OptimizedStorage && targetPool.Driver().Info().Name != bInfo.Backend {
+		return nil, nil, nil, api.StatusErrorf(http.StatusBadRequest, "Optimized backup storage driver %q differs from the target storage pool driver %q", bInfo.Backend, targetPool.Driver().Info().Name)
 	}
 
+	// Point the backup index at the target project, pool and volume name, as those may differ from
+	// the ones the backup was taken from.
+	bInfo.RemapVolume(bInfo.Project, bInfo.Pool, bInfo.Name)
+
 	reverter.Success()
-	return operations.OperationResponse(op)
+
+	return backupFile, bInfo, targetPool, nil
 }
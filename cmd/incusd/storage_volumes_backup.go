@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"path"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/flosch/pongo2/v6"
 	"github.com/gorilla/mux"
+	"github.com/pkg/sftp"
 
+	"github.com/lxc/incus/v6/internal/filter"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/jmap"
 	"github.com/lxc/incus/v6/internal/server/auth"
@@ -23,12 +30,17 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
 )
 
 var storagePoolVolumeTypeCustomBackupsCmd = APIEndpoint{
@@ -49,7 +61,20 @@ var storagePoolVolumeTypeCustomBackupCmd = APIEndpoint{
 var storagePoolVolumeTypeCustomBackupExportCmd = APIEndpoint{
 	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/export",
 
-	Get: APIEndpointAction{Handler: storagePoolVolumeTypeCustomBackupExportGet, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName", "location")},
+	Get: APIEndpointAction{Handler: storagePoolVolumeTypeCustomBackupExportGet, AccessHandler: allowStoragePoolVolumeBackupExport, AllowUntrusted: true},
+}
+
+var storagePoolVolumeTypeCustomBackupFilesCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/files",
+
+	Get:  APIEndpointAction{Handler: storagePoolVolumeTypeCustomBackupFilesGet, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName", "location")},
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeCustomBackupFilesPost, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit, "poolName", "type", "volumeName", "location")},
+}
+
+var storagePoolVolumeTypeCustomBackupSecretCmd = APIEndpoint{
+	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/secret",
+
+	Post: APIEndpointAction{Handler: storagePoolVolumeTypeCustomBackupSecretPost, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups, "poolName", "type", "volumeName", "location")},
 }
 
 // swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/backups storage storage_pool_volumes_type_backups_get
@@ -72,6 +97,21 @@ var storagePoolVolumeTypeCustomBackupExportCmd = APIEndpoint{
 //      description: Cluster member name
 //      type: string
 //      example: server01
+//    - in: query
+//      name: filter
+//      description: Collection filter
+//      type: string
+//      example: default
+//    - in: query
+//      name: offset
+//      description: Number of backups to skip
+//      type: integer
+//      example: 0
+//    - in: query
+//      name: limit
+//      description: Maximum number of backups to return
+//      type: integer
+//      example: 50
 //  responses:
 //    "200":
 //      description: API endpoints
@@ -126,6 +166,21 @@ var storagePoolVolumeTypeCustomBackupExportCmd = APIEndpoint{
 //	    description: Cluster member name
 //	    type: string
 //	    example: server01
+//	  - in: query
+//	    name: filter
+//	    description: Collection filter
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: offset
+//	    description: Number of backups to skip
+//	    type: integer
+//	    example: 0
+//	  - in: query
+//	    name: limit
+//	    description: Maximum number of backups to return
+//	    type: integer
+//	    example: 50
 //	responses:
 //	  "200":
 //	    description: API endpoints
@@ -212,6 +267,18 @@ func storagePoolVolumeTypeCustomBackupsGet(d *Daemon, r *http.Request) response.
 
 	recursion := localUtil.IsRecursionRequest(r)
 
+	// Parse filter value.
+	clauses, err := filter.Parse(r.FormValue("filter"), filter.QueryOperatorSet())
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid filter: %w", err))
+	}
+
+	// Parse pagination values.
+	offset, limit, err := localUtil.PaginationRequest(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	var volumeBackups []db.StoragePoolVolumeBackup
 
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -232,15 +299,30 @@ func storagePoolVolumeTypeCustomBackupsGet(d *Daemon, r *http.Request) response.
 	resultMap := []*api.StorageVolumeBackup{}
 
 	for _, backup := range backups {
+		render := backup.Render()
+
+		if clauses != nil && len(clauses.Clauses) > 0 {
+			match, err := filter.Match(*render, *clauses)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if !match {
+				continue
+			}
+		}
+
 		if !recursion {
 			url := api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", "custom", volumeName, "backups", strings.Split(backup.Name(), "/")[1]).String()
 			resultString = append(resultString, url)
 		} else {
-			render := backup.Render()
 			resultMap = append(resultMap, render)
 		}
 	}
 
+	resultString = localUtil.Paginate(resultString, offset, limit)
+	resultMap = localUtil.Paginate(resultMap, offset, limit)
+
 	if !recursion {
 		return response.SyncResponse(true, resultString)
 	}
@@ -388,40 +470,11 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 	}
 
 	if req.Name == "" {
-		var backups []string
-
 		// come up with a name.
-		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-			backups, err = tx.GetStoragePoolVolumeBackupsNames(ctx, projectName, volumeName, poolID)
-			return err
-		})
+		req.Name, err = volumeBackupDetermineNextName(r.Context(), s, projectName, volumeName, poolID, dbVolume.Config)
 		if err != nil {
 			return response.BadRequest(err)
 		}
-
-		base := volumeName + internalInstance.SnapshotDelimiter + "backup"
-		length := len(base)
-		max := 0
-
-		for _, backup := range backups {
-			// Ignore backups not containing base.
-			if !strings.HasPrefix(backup, base) {
-				continue
-			}
-
-			substr := backup[length:]
-			var num int
-			count, err := fmt.Sscanf(substr, "%d", &num)
-			if err != nil || count != 1 {
-				continue
-			}
-
-			if num >= max {
-				max = num + 1
-			}
-		}
-
-		req.Name = fmt.Sprintf("backup%d", max)
 	}
 
 	// Validate the name.
@@ -429,6 +482,17 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 		return response.BadRequest(errors.New("Backup names may not contain slashes"))
 	}
 
+	// Validate the rate limit.
+	err = validate.Optional(validate.IsSize)(req.Throttle)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid throttle %q: %w", req.Throttle, err))
+	}
+
+	err = backupTargetCheck(r.Context(), s, r, request.ProjectParam(r), req.Target)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	fullName := volumeName + internalInstance.SnapshotDelimiter + req.Name
 	volumeOnly := req.VolumeOnly
 
@@ -441,35 +505,15 @@ func storagePoolVolumeTypeCustomBackupsPost(d *Daemon, r *http.Request) response
 			VolumeOnly:           volumeOnly,
 			OptimizedStorage:     req.OptimizedStorage,
 			CompressionAlgorithm: req.CompressionAlgorithm,
+			Throttle:             req.Throttle,
 		}
 
-		// Create the backup.
-		err := volumeBackupCreate(s, args, projectName, poolName, volumeName)
+		// Create the backup, streaming it to the target if requested.
+		err := volumeBackupCreate(s, args, projectName, poolName, volumeName, req.Target)
 		if err != nil {
 			return err
 		}
 
-		// Upload it if requested.
-		if req.Target != nil {
-			// Load the backup.
-			entry, err := storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, volumeName)
-			if err != nil {
-				return err
-			}
-
-			// Upload it.
-			err = entry.Upload(req.Target)
-			if err != nil {
-				return err
-			}
-
-			// Delete the backup on successful upload.
-			err = entry.Delete()
-			if err != nil {
-				return err
-			}
-		}
-
 		s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupCreated.Event(poolName, volumeTypeName, args.Name, projectName, op.Requestor(), logger.Ctx{"type": volumeTypeName}))
 
 		return nil
@@ -842,12 +886,151 @@ func storagePoolVolumeTypeCustomBackupDelete(d *Daemon, r *http.Request) respons
 	return operations.OperationResponse(op)
 }
 
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/secret storage storage_pool_volumes_type_backup_secret_post
+//
+//	Generate a backup download secret
+//
+//	Generates a secret allowing untrusted clients to download the backup file until it expires.
+//	The operation metadata contains the secret, its expiry and the export URL to use.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: secret
+//	    description: Secret request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/BackupSecretPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeCustomBackupSecretPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the storage volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the storage pool the volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get backup name.
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if volumeTypeName != db.StoragePoolVolumeTypeNameCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", volumeTypeName))
+	}
+
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.BackupSecretPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response.BadRequest(err)
+	}
+
+	// Ensure the backup exists.
+	_, err = storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, volumeName+internalInstance.SnapshotDelimiter+backupName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName)}
+	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", backupName)}
+
+	exportURL := api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", backupName, "export").Project(request.ProjectParam(r)).Target(request.QueryParam(r, "target"))
+
+	return backupSecretCreate(s, r, projectName, operationtype.CustomVolumeBackupToken, resources, exportURL, req.Expiry)
+}
+
+// allowStoragePoolVolumeBackupExport is an AccessHandler which allows trusted clients with the can_view entitlement
+// on the storage volume, as well as untrusted clients holding a valid backup secret.
+func allowStoragePoolVolumeBackupExport(d *Daemon, r *http.Request) response.Response {
+	if d.checkTrustedClient(r) == nil {
+		return allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName", "location")(d, r)
+	}
+
+	s := d.State()
+
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return allowBackupSecret(s, r, projectName, operationtype.CustomVolumeBackupToken, api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", backupName))
+}
+
 // swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/export storage storage_pool_volumes_type_backup_export_get
 //
 //	Get the raw backup file
 //
 //	Download the raw backup file from the server.
 //
+//	Range requests are supported, allowing interrupted downloads to be resumed.
+//	The SHA256 checksum of the file is returned in the `ETag` and `X-Incus-Checksum` headers.
+//
+//	Non-optimized backups of block volumes can also be downloaded as a `qcow2` or `vmdk`
+//	disk image, converted when requested.
+//
 //	---
 //	produces:
 //	  - application/octet-stream
@@ -862,9 +1045,25 @@ func storagePoolVolumeTypeCustomBackupDelete(d *Daemon, r *http.Request) respons
 //	    description: Cluster member name
 //	    type: string
 //	    example: server01
+//	  - in: query
+//	    name: secret
+//	    description: Secret allowing an untrusted client to download the backup
+//	    type: string
+//	  - in: query
+//	    name: format
+//	    description: Disk image format to convert block volumes to (`qcow2` or `vmdk`)
+//	    type: string
+//	    example: qcow2
+//	  - in: query
+//	    name: throttle
+//	    description: Rate limit for the download in bytes per second (overrides the server setting)
+//	    type: string
+//	    example: 50MB
 //	responses:
 //	  "200":
 //	    description: Raw backup data
+//	  "206":
+//	    description: Partial raw backup data (range request)
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
 //	  "500":
@@ -930,11 +1129,628 @@ func storagePoolVolumeTypeCustomBackupExportGet(d *Daemon, r *http.Request) resp
 		return response.SmartError(err)
 	}
 
+	var backupRow db.StoragePoolVolumeBackup
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		backupRow, err = tx.GetStoragePoolVolumeBackup(ctx, projectName, poolName, fullName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Expose the checksum of the tarball, allowing clients to resume interrupted downloads through
+	// range requests (If-Range uses the ETag) and to verify the result.
+	headers := map[string]string{}
+	if backupRow.Checksum != "" {
+		headers["ETag"] = fmt.Sprintf("%q", backupRow.Checksum)
+		headers["X-Incus-Checksum"] = "sha256:" + backupRow.Checksum
+	}
+
+	tarPath := internalUtil.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName))
+
+	// Convert block volumes to a disk image if requested.
+	format := request.QueryParam(r, "format")
+	if format != "" {
+		ent, err := backupExportDiskImage(s, tarPath, "backup/volume.img", format, fmt.Sprintf("%s.%s", volumeName, format))
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = backupThrottleExport(s, request.QueryParam(r, "throttle"), ent)
+		if err != nil {
+			ent.Cleanup()
+			return response.SmartError(err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupRetrieved.Event(poolName, volumeTypeName, fullName, projectName, request.CreateRequestor(r), nil))
+
+		return response.FileResponse(r, []response.FileResponseEntry{*ent}, nil)
+	}
+
 	ent := response.FileResponseEntry{
-		Path: internalUtil.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName)),
+		Path: tarPath,
+	}
+
+	err = backupThrottleExport(s, request.QueryParam(r, "throttle"), &ent)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Resumed downloads (range requests) don't emit a new event.
+	if r.Header.Get("Range") == "" {
+		s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupRetrieved.Event(poolName, volumeTypeName, fullName, projectName, request.CreateRequestor(r), nil))
+	}
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, headers)
+}
+
+// swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/files storage storage_pool_volumes_type_backup_files_get
+//
+//	Get the backup content
+//
+//	Lists the files contained in the backup tarball without restoring it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Backup content
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of files
+//	          items:
+//	            $ref: "#/definitions/BackupFile"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeCustomBackupFilesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the storage volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the storage pool the volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get backup name.
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if volumeType != db.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", volumeTypeName))
+	}
+
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+	if resp != nil {
+		return resp
+	}
+
+	fullName := volumeName + internalInstance.SnapshotDelimiter + backupName
+
+	// Ensure the backup exists.
+	_, err = storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, fullName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	files, err := backupListFiles(s, internalUtil.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName)))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, files)
+}
+
+// swagger:operation POST /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/backups/{backupName}/files storage storage_pool_volumes_type_backup_files_post
+//
+//	Restore files from the backup
+//
+//	Extracts a file, symlink or directory from the backup tarball onto the volume,
+//	without restoring the whole backup.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: files
+//	    description: Path to restore
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/BackupFilesPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeCustomBackupFilesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the storage volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the storage pool the volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get backup name.
+	backupName, err := url.PathUnescape(mux.Vars(r)["backupName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if volumeType != db.StoragePoolVolumeTypeCustom {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", volumeTypeName))
+	}
+
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	resp = forwardedResponseIfVolumeIsRemote(s, r, poolName, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.BackupFilesPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Source == "" {
+		return response.BadRequest(errors.New("No source path provided"))
+	}
+
+	// Default to the original location of files from the volume.
+	if req.Target == "" {
+		source := strings.Trim(req.Source, "/")
+		if source != "backup/volume" && !strings.HasPrefix(source, "backup/volume/") {
+			return response.BadRequest(errors.New("No target path provided"))
+		}
+
+		req.Target = path.Join("/", strings.TrimPrefix(source, "backup/volume"))
+	}
+
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var dbVolume *db.StorageVolume
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, pool.ID(), projectName, volumeType, volumeName, true)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if dbVolume.ContentType != db.StoragePoolVolumeContentTypeNameFS {
+		return response.BadRequest(errors.New("Files can only be restored onto filesystem volumes"))
+	}
+
+	fullName := volumeName + internalInstance.SnapshotDelimiter + backupName
+
+	// Ensure the backup exists.
+	_, err = storagePoolVolumeBackupLoadByName(r.Context(), s, projectName, poolName, fullName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tarPath := internalUtil.VarPath("backups", "custom", poolName, project.StorageVolume(projectName, fullName))
+
+	// The volume is served over SFTP from its mount path, so keep the target relative to it.
+	target := strings.TrimPrefix(path.Clean("/"+req.Target), "/")
+	if target == "" {
+		target = "."
+	}
+
+	run := func(op *operations.Operation) error {
+		vol := pool.GetVolume(storageDrivers.VolumeTypeCustom, storageDrivers.ContentTypeFS, project.StorageVolume(projectName, volumeName), nil)
+
+		serverConn, clientConn := net.Pipe()
+
+		served := make(chan struct{})
+		go func() {
+			defer close(served)
+
+			err := serveVolumeSFTP(context.Background(), vol, serverConn, op)
+			if err != nil {
+				logger.Debug("Volume SFTP server exited", logger.Ctx{"pool": poolName, "volume": volumeName, "err": err})
+			}
+
+			_ = serverConn.Close()
+		}()
+
+		// Wait for the volume to be unmounted before completing the operation.
+		defer func() { <-served }()
+		defer func() { _ = clientConn.Close() }()
+
+		client, err := sftp.NewClientPipe(clientConn, clientConn)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = client.Close() }()
+
+		return backupRestoreFiles(s, tarPath, client, req.Source, target, nil)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName)}
+	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", poolName, "volumes", volumeTypeName, volumeName, "backups", backupName)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.CustomVolumeBackupRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// volumeBackupDetermineNextName renders the volume's backup name pattern ("backup%d" by default) into a backup name which isn't in use yet.
+func volumeBackupDetermineNextName(ctx context.Context, s *state.State, projectName string, volumeName string, poolID int64, volumeConfig map[string]string) (string, error) {
+	pattern := volumeConfig["backups.pattern"]
+	if pattern == "" {
+		pattern = "backup%d"
+	}
+
+	pattern, err := internalUtil.RenderTemplate(pattern, pongo2.Context{
+		"creation_date": time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if internalUtil.NamePatternCounters(pattern) > 1 {
+		return "", errors.New("Backup pattern may contain '%d' only once")
+	}
+
+	var backups []string
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		backups, err = tx.GetStoragePoolVolumeBackupsNames(ctx, projectName, volumeName, poolID)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Only keep the backup part of the names.
+	prefix := volumeName + internalInstance.SnapshotDelimiter
+	names := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		name, ok := strings.CutPrefix(backup, prefix)
+		if ok {
+			names = append(names, name)
+		}
+	}
+
+	if internalUtil.NamePatternCounters(pattern) == 0 {
+		if !slices.Contains(names, pattern) {
+			return pattern, nil
+		}
+
+		// Append '-0', '-1', etc. if the rendered name already exists.
+		pattern = pattern + "-%d"
+	}
+
+	return internalUtil.NamePatternFormat(pattern, internalUtil.NamePatternNextIndex(pattern, names)), nil
+}
+
+func autoCreateCustomVolumeBackupsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+		var volumes, remoteVolumes []db.StorageVolumeArgs
+		var memberCount int
+		var onlineMemberIDs []int64
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			allVolumes, err := tx.GetStoragePoolVolumesWithType(ctx, db.StoragePoolVolumeTypeCustom, true)
+			if err != nil {
+				return fmt.Errorf("Failed getting volumes for auto custom volume backup task: %w", err)
+			}
+
+			for _, v := range allVolumes {
+				schedule, ok := v.Config["backups.schedule"]
+				if !ok || schedule == "" {
+					continue
+				}
+
+				// Check if backup is scheduled.
+				if !snapshotIsScheduledNow(schedule, v.ID) {
+					continue
+				}
+
+				err = project.AllowBackupCreation(tx, v.ProjectName)
+				if err != nil {
+					continue
+				}
+
+				if v.NodeID < 0 {
+					// Keep a separate list of remote volumes in order to select a member to
+					// perform the backup later.
+					remoteVolumes = append(remoteVolumes, v)
+				} else {
+					logger.Debug("Scheduling local auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v) // Always include local volumes.
+				}
+			}
+
+			if len(remoteVolumes) > 0 {
+				// Get list of cluster members.
+				members, err := tx.GetNodes(ctx)
+				if err != nil {
+					return fmt.Errorf("Failed getting cluster members: %w", err)
+				}
+
+				memberCount = len(members)
+
+				// Filter to online members.
+				for _, member := range members {
+					if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+						continue
+					}
+
+					onlineMemberIDs = append(onlineMemberIDs, member.ID)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting custom volume info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(remoteVolumes) > 0 {
+			// Skip backing up remote custom volumes if there are no online members, as we can't be
+			// sure that the cluster isn't partitioned and we may end up attempting the backup on
+			// multiple members.
+			if memberCount > 1 && len(onlineMemberIDs) <= 0 {
+				logger.Error("Skipping remote volumes for auto custom volume backup task due to no online members")
+			} else {
+				localMemberID := s.DB.Cluster.GetNodeID()
+
+				for _, v := range remoteVolumes {
+					// If there are multiple cluster members, a stable random member is chosen
+					// to perform the backup from. This avoids taking the backup on every
+					// member and spreads the load across the online cluster members.
+					if memberCount > 1 {
+						selectedNodeID, err := localUtil.GetStableRandomInt64FromList(int64(v.ID), onlineMemberIDs)
+						if err != nil {
+							logger.Error("Failed scheduling remote auto custom volume backup task", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+							continue
+						}
+
+						// Don't backup, if we're not the chosen one.
+						if localMemberID != selectedNodeID {
+							continue
+						}
+					}
+
+					logger.Debug("Scheduling remote auto custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName})
+					volumes = append(volumes, v)
+				}
+			}
+		}
+
+		if len(volumes) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			return autoCreateCustomVolumeBackups(ctx, s, volumes)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.CustomVolumeBackupCreate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled volume backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Creating scheduled volume backups")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled volume backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled custom volume backups", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done creating scheduled volume backups")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
+
+func autoCreateCustomVolumeBackups(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs) error {
+	// Make the backups sequentially.
+	for _, v := range volumes {
+		err := ctx.Err()
+		if err != nil {
+			return err // Stop if context is cancelled.
+		}
+
+		// A failing volume shouldn't prevent the others from being backed up.
+		err = autoCreateCustomVolumeBackup(ctx, s, v)
+		if err != nil {
+			logger.Error("Failed creating scheduled custom volume backup", logger.Ctx{"volName": v.Name, "project": v.ProjectName, "pool": v.PoolName, "err": err})
+		}
+	}
+
+	return nil
+}
+
+// autoCreateCustomVolumeBackup creates a scheduled backup of a single custom volume.
+func autoCreateCustomVolumeBackup(ctx context.Context, s *state.State, v db.StorageVolumeArgs) error {
+	pool, err := storagePools.LoadByName(s, v.PoolName)
+	if err != nil {
+		return fmt.Errorf("Error loading pool: %w", err)
+	}
+
+	backupName, err := volumeBackupDetermineNextName(ctx, s, v.ProjectName, v.Name, pool.ID(), v.Config)
+	if err != nil {
+		return fmt.Errorf("Error retrieving next backup name: %w", err)
+	}
+
+	expiry, err := internalInstance.GetExpiry(time.Now(), v.Config["backups.expiry"])
+	if err != nil {
+		return fmt.Errorf("Error getting backup expiry: %w", err)
+	}
+
+	args := db.StoragePoolVolumeBackup{
+		Name:         v.Name + internalInstance.SnapshotDelimiter + backupName,
+		VolumeID:     v.ID,
+		CreationDate: time.Now(),
+		ExpiryDate:   expiry,
+		VolumeOnly:   util.IsTrue(v.Config["backups.volume_only"]),
+	}
+
+	// Stream it to the server-side target if one is configured.
+	var target *api.BackupTarget
+	if v.Config["backups.target.name"] != "" {
+		target = &api.BackupTarget{
+			Name: v.Config["backups.target.name"],
+			Path: path.Join(v.Config["backups.target.path"], backupName),
+		}
+
+		err = backupTargetCheck(ctx, s, nil, v.ProjectName, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = volumeBackupCreate(s, args, v.ProjectName, v.PoolName, v.Name, target)
+	if err != nil {
+		return fmt.Errorf("Error creating backup: %w", err)
 	}
 
-	s.Events.SendLifecycle(projectName, lifecycle.StorageVolumeBackupRetrieved.Event(poolName, volumeTypeName, fullName, projectName, request.CreateRequestor(r), nil))
+	s.Events.SendLifecycle(v.ProjectName, lifecycle.StorageVolumeBackupCreated.Event(v.PoolName, db.StoragePoolVolumeTypeNameCustom, args.Name, v.ProjectName, nil, logger.Ctx{"type": db.StoragePoolVolumeTypeNameCustom}))
 
-	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+	return nil
 }
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -199,8 +199,11 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 		return response.SmartError(err)
 	}
 
+	// Apply the pool's snapshot defaults.
+	parentConfig := volumeSnapshotConfig(pool.Driver().Config(), parentDBVolume.Config)
+
 	// Get the snapshot pattern.
-	pattern := parentDBVolume.Config["snapshots.pattern"]
+	pattern := parentConfig["snapshots.pattern"]
 	if pattern == "" {
 		pattern = "snap%d"
 	}
@@ -222,7 +225,7 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 			return nil
 		})
 
-		req.Name = fmt.Sprintf(pattern, i)
+		req.Name = internalUtil.NamePatternFormat(pattern, i)
 	} else {
 		// Make sure the snapshot doesn't already exist.
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -251,7 +254,7 @@ func storagePoolVolumeSnapshotsTypePost(d *Daemon, r *http.Request) response.Res
 	if req.ExpiresAt != nil {
 		expiry = *req.ExpiresAt
 	} else {
-		expiry, err = internalInstance.GetExpiry(time.Now(), parentDBVolume.Config["snapshots.expiry"])
+		expiry, err = internalInstance.GetExpiry(time.Now(), parentConfig["snapshots.expiry"])
 		if err != nil {
 			return response.BadRequest(err)
 		}
@@ -1193,6 +1196,7 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 	f := func(ctx context.Context) {
 		s := d.State()
 		var volumes, remoteVolumes, expiredSnapshots, expiredRemoteSnapshots []db.StorageVolumeArgs
+		schedules := map[int64][]string{}
 		var memberCount int
 		var onlineMemberIDs []int64
 
@@ -1239,15 +1243,13 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 					continue
 				}
 
-				schedule, ok := v.Config["snapshots.schedule"]
-				if !ok || schedule == "" {
+				// Check if any snapshot is scheduled.
+				dueSchedules := volumeSnapshotSchedulesDue(v.Config, v.ID)
+				if len(dueSchedules) == 0 {
 					continue
 				}
 
-				// Check if snapshot is scheduled.
-				if !snapshotIsScheduledNow(schedule, v.ID) {
-					continue
-				}
+				schedules[v.ID] = dueSchedules
 
 				if v.NodeID < 0 {
 					// Keep a separate list of remote volumes in order to select a member to
@@ -1379,7 +1381,7 @@ func pruneExpiredAndAutoCreateCustomVolumeSnapshotsTask(d *Daemon) (task.Func, t
 		// Handle snapshot auto creation.
 		if len(volumes) > 0 {
 			opRun := func(op *operations.Operation) error {
-				return autoCreateCustomVolumeSnapshots(ctx, s, volumes)
+				return autoCreateCustomVolumeSnapshots(ctx, s, volumes, schedules)
 			}
 
 			op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.VolumeSnapshotCreate, nil, nil, opRun, nil, nil, nil)
@@ -1447,32 +1449,109 @@ func pruneExpiredCustomVolumeSnapshots(ctx context.Context, s *state.State, expi
 	return nil
 }
 
-func autoCreateCustomVolumeSnapshots(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs) error {
-	// Make the snapshots sequentially.
-	for _, v := range volumes {
-		err := ctx.Err()
-		if err != nil {
-			return err // Stop if context is cancelled.
+// volumeSnapshotSchedulesDue returns the names of the snapshot schedules of a custom volume which are due now.
+// The unnamed "snapshots.schedule" is returned as an empty name, "snapshots.schedule.<name>" ones by their name.
+func volumeSnapshotSchedulesDue(config map[string]string, volumeID int64) []string {
+	var due []string
+
+	for k, schedule := range config {
+		var name string
+
+		if k != "snapshots.schedule" {
+			var ok bool
+
+			name, ok = strings.CutPrefix(k, "snapshots.schedule.")
+			if !ok || name == "" {
+				continue
+			}
 		}
 
-		snapshotName, err := volumeDetermineNextSnapshotName(ctx, s, v, "snap%d")
-		if err != nil {
-			return fmt.Errorf("Error retrieving next snapshot name for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		if schedule == "" || !snapshotIsScheduledNow(schedule, volumeID) {
+			continue
 		}
 
-		expiry, err := internalInstance.GetExpiry(time.Now(), v.Config["snapshots.expiry"])
-		if err != nil {
-			return fmt.Errorf("Error getting snapshot expiry for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		due = append(due, name)
+	}
+
+	// Keep the snapshot creation order stable.
+	slices.Sort(due)
+
+	return due
+}
+
+// volumeSnapshotConfig returns the volume config with the pool's "snapshots.expiry" and "snapshots.pattern"
+// applied for any of those keys the volume doesn't set itself.
+func volumeSnapshotConfig(poolConfig map[string]string, config map[string]string) map[string]string {
+	result := maps.Clone(config)
+	if result == nil {
+		result = map[string]string{}
+	}
+
+	for _, k := range []string{"snapshots.expiry", "snapshots.pattern"} {
+		_, ok := result[k]
+		if !ok && poolConfig[k] != "" {
+			result[k] = poolConfig[k]
+		}
+	}
+
+	return result
+}
+
+// volumeSnapshotScheduleConfig returns the snapshot pattern and expiry to use for the named snapshot schedule.
+// Named schedules fall back to the volume's "snapshots.expiry" and to a "<name>%d" pattern.
+func volumeSnapshotScheduleConfig(config map[string]string, name string) (string, string) {
+	if name == "" {
+		pattern, ok := config["snapshots.pattern"]
+		if !ok {
+			pattern = "snap%d"
 		}
 
+		return pattern, config["snapshots.expiry"]
+	}
+
+	pattern, ok := config["snapshots.pattern."+name]
+	if !ok {
+		pattern = name + "%d"
+	}
+
+	expiry, ok := config["snapshots.expiry."+name]
+	if !ok {
+		expiry = config["snapshots.expiry"]
+	}
+
+	return pattern, expiry
+}
+
+func autoCreateCustomVolumeSnapshots(ctx context.Context, s *state.State, volumes []db.StorageVolumeArgs, schedules map[int64][]string) error {
+	// Make the snapshots sequentially.
+	for _, v := range volumes {
 		pool, err := storagePools.LoadByName(s, v.PoolName)
 		if err != nil {
 			return fmt.Errorf("Error loading pool for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
 		}
 
-		err = pool.CreateCustomVolumeSnapshot(v.ProjectName, v.Name, snapshotName, expiry, nil)
-		if err != nil {
-			return fmt.Errorf("Error creating snapshot for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+		for _, schedule := range schedules[v.ID] {
+			err := ctx.Err()
+			if err != nil {
+				return err // Stop if context is cancelled.
+			}
+
+			pattern, expiryValue := volumeSnapshotScheduleConfig(volumeSnapshotConfig(pool.Driver().Config(), v.Config), schedule)
+
+			snapshotName, err := volumeDetermineNextSnapshotNameFromPattern(ctx, s, v, pattern)
+			if err != nil {
+				return fmt.Errorf("Error retrieving next snapshot name for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+			}
+
+			expiry, err := internalInstance.GetExpiry(time.Now(), expiryValue)
+			if err != nil {
+				return fmt.Errorf("Error getting snapshot expiry for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+			}
+
+			err = pool.CreateCustomVolumeSnapshot(v.ProjectName, v.Name, snapshotName, expiry, nil)
+			if err != nil {
+				return fmt.Errorf("Error creating snapshot for volume %q (project %q, pool %q): %w", v.Name, v.ProjectName, v.PoolName, err)
+			}
 		}
 	}
 
@@ -1480,13 +1559,18 @@ func autoCreateCustomVolumeSnapshots(ctx context.Context, s *state.State, volume
 }
 
 func volumeDetermineNextSnapshotName(ctx context.Context, s *state.State, volume db.StorageVolumeArgs, defaultPattern string) (string, error) {
-	var err error
-
 	pattern, ok := volume.Config["snapshots.pattern"]
 	if !ok {
 		pattern = defaultPattern
 	}
 
+	return volumeDetermineNextSnapshotNameFromPattern(ctx, s, volume, pattern)
+}
+
+// volumeDetermineNextSnapshotNameFromPattern renders the given snapshot pattern into a snapshot name which isn't in use yet.
+func volumeDetermineNextSnapshotNameFromPattern(ctx context.Context, s *state.State, volume db.StorageVolumeArgs, pattern string) (string, error) {
+	var err error
+
 	pattern, err = internalUtil.RenderTemplate(pattern, pongo2.Context{
 		"creation_date": time.Now(),
 	})
@@ -1494,7 +1578,7 @@ func volumeDetermineNextSnapshotName(ctx context.Context, s *state.State, volume
 		return "", err
 	}
 
-	count := strings.Count(pattern, "%d")
+	count := internalUtil.NamePatternCounters(pattern)
 	if count > 1 {
 		return "", fmt.Errorf("Snapshot pattern may contain '%%d' only once")
 	} else if count == 1 {
@@ -1506,7 +1590,7 @@ func volumeDetermineNextSnapshotName(ctx context.Context, s *state.State, volume
 			return nil
 		})
 
-		return strings.Replace(pattern, "%d", strconv.Itoa(i), 1), nil
+		return internalUtil.NamePatternFormat(pattern, i), nil
 	}
 
 	snapshotExists := false
@@ -1566,7 +1650,10 @@ func volumeDetermineNextSnapshotName(ctx context.Context, s *state.State, volume
 		}
 	}
 
+	// Append '-0', '-1', etc. if the actual pattern/snapshot name already exists
 	if snapshotExists {
+		pattern = fmt.Sprintf("%s-%%d", pattern)
+
 		var i int
 
 		_ = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -1575,7 +1662,7 @@ func volumeDetermineNextSnapshotName(ctx context.Context, s *state.State, volume
 			return nil
 		})
 
-		return strings.Replace(pattern, "%d", strconv.Itoa(i), 1), nil
+		return internalUtil.NamePatternFormat(pattern, i), nil
 	}
 
 	return pattern, nil
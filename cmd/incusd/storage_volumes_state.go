@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -24,6 +25,7 @@ var storagePoolVolumeTypeStateCmd = APIEndpoint{
 	Path: "storage-pools/{poolName}/volumes/{type}/{volumeName}/state",
 
 	Get: APIEndpointAction{Handler: storagePoolVolumeTypeStateGet, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanView, "poolName", "type", "volumeName")},
+	Put: APIEndpointAction{Handler: storagePoolVolumeTypeStatePut, AccessHandler: allowPermission(auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit, "poolName", "type", "volumeName")},
 }
 
 // swagger:operation GET /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/state storage storage_pool_volume_type_state_get
@@ -162,5 +164,133 @@ func storagePoolVolumeTypeStateGet(d *Daemon, r *http.Request) response.Response
 		}
 	}
 
+	// Fetch the mirroring state.
+	volType, err := storagePools.VolumeDBTypeToType(volumeType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	state.Mirror, err = pool.GetVolumeMirror(projectName, volumeName, volType)
+	if err != nil && !errors.Is(err, storageDrivers.ErrNotSupported) {
+		return response.SmartError(err)
+	}
+
 	return response.SyncResponse(true, state)
 }
+
+// swagger:operation PUT /1.0/storage-pools/{poolName}/volumes/{type}/{volumeName}/state storage storage_pool_volume_type_state_put
+//
+//	Change the storage volume state
+//
+//	Changes the mirroring state of the storage volume (promote or demote).
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: state
+//	    description: State
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StorageVolumeStatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func storagePoolVolumeTypeStatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Get the name of the pool the storage volume is supposed to be attached to.
+	poolName, err := url.PathUnescape(mux.Vars(r)["poolName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the volume type.
+	volumeTypeName, err := url.PathUnescape(mux.Vars(r)["type"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Get the name of the volume.
+	volumeName, err := url.PathUnescape(mux.Vars(r)["volumeName"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Convert the volume type name to our internal integer representation.
+	volumeType, err := storagePools.VolumeTypeNameToDBType(volumeTypeName)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Check that the storage volume type is valid.
+	if !slices.Contains([]int{db.StoragePoolVolumeTypeCustom, db.StoragePoolVolumeTypeContainer, db.StoragePoolVolumeTypeVM}, volumeType) {
+		return response.BadRequest(fmt.Errorf("Invalid storage volume type %q", volumeTypeName))
+	}
+
+	volType, err := storagePools.VolumeDBTypeToType(volumeType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Parse the request.
+	req := api.StorageVolumeStatePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Get the storage project name.
+	projectName, err := project.StorageVolumeProject(s.DB.Cluster, request.ProjectParam(r), volumeType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Load the storage pool.
+	pool, err := storagePools.LoadByName(s, poolName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	switch req.Action {
+	case "promote":
+		err = pool.PromoteVolume(projectName, volumeName, volType, req.Force, nil)
+	case "demote":
+		if req.Force {
+			return response.BadRequest(errors.New("The force option is only supported when promoting"))
+		}
+
+		err = pool.DemoteVolume(projectName, volumeName, volType, nil)
+	default:
+		return response.BadRequest(fmt.Errorf("Unknown state action %q", req.Action))
+	}
+
+	if err != nil {
+		if errors.Is(err, storageDrivers.ErrNotSupported) {
+			return response.BadRequest(errors.New("Storage volume mirroring isn't enabled for this volume"))
+		}
+
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
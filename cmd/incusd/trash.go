@@ -0,0 +1,699 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/backup"
+	backupConfig "github.com/lxc/incus/v6/internal/server/backup/config"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+var trashEntriesCmd = APIEndpoint{
+	Path: "trash",
+
+	Get: APIEndpointAction{Handler: trashEntriesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var trashEntryCmd = APIEndpoint{
+	Path: "trash/{uuid}",
+
+	Get:    APIEndpointAction{Handler: trashEntryGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: trashEntryPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: trashEntryDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// trashEnabled returns whether deleted instances and custom volumes should be kept in the trash.
+func trashEnabled(s *state.State) bool {
+	return s.GlobalConfig.StorageTrashExpiryDays() > 0
+}
+
+// trashAdd records a new trash entry for the instance or custom volume described by the backup config and then
+// has the move function hide its volume on the storage pool. Returns a revert hook moving the volume back in
+// place and removing the entry.
+func trashAdd(s *state.State, pool storagePools.Pool, entryType string, projectName string, name string, backupConf *backupConfig.Config, move func(entryUUID string) error) (revert.Hook, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	config, err := yaml.Marshal(backupConf)
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding backup config: %w", err)
+	}
+
+	now := time.Now().UTC()
+	entry := db.TrashEntry{
+		UUID:         uuid.New().String(),
+		Project:      projectName,
+		Type:         entryType,
+		Name:         name,
+		StoragePool:  pool.Name(),
+		Config:       string(config),
+		CreationDate: now,
+		ExpiryDate:   now.AddDate(0, 0, int(s.GlobalConfig.StorageTrashExpiryDays())),
+	}
+
+	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.CreateTrashEntry(ctx, entry)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed recording trash entry: %w", err)
+	}
+
+	reverter.Add(func() {
+		_ = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteTrashEntry(ctx, entry.UUID)
+		})
+	})
+
+	err = move(entry.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() {
+		_, err := pool.RestoreTrashVolume(projectName, backupConf, entry.UUID, nil)
+		if err != nil {
+			logger.Error("Failed moving volume back from the trash", logger.Ctx{"entry": entry.UUID, "err": err})
+		}
+	})
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
+
+// trashInstance moves the instance volume (including its snapshots) to the trash ahead of the instance deletion.
+// Returns a revert hook moving the volume back in place and removing the entry.
+func trashInstance(s *state.State, inst instance.Instance, op *operations.Operation) (revert.Hook, error) {
+	pool, err := storagePools.LoadByInstance(s, inst)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading instance storage pool: %w", err)
+	}
+
+	backupConf, err := pool.GenerateInstanceBackupConfig(inst, true, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating instance backup config: %w", err)
+	}
+
+	return trashAdd(s, pool, api.TrashEntryTypeInstance, inst.Project().Name, inst.Name(), backupConf, func(entryUUID string) error {
+		return pool.TrashInstance(inst, entryUUID, op)
+	})
+}
+
+// trashStorageVolume moves the custom volume (including its snapshots) to the trash ahead of the volume deletion.
+// Returns a revert hook moving the volume back in place and removing the entry.
+func trashStorageVolume(s *state.State, projectName string, pool storagePools.Pool, volumeName string, op *operations.Operation) (revert.Hook, error) {
+	backupConf, err := pool.GenerateCustomVolumeBackupConfig(projectName, volumeName, true, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating volume backup config: %w", err)
+	}
+
+	return trashAdd(s, pool, api.TrashEntryTypeStorageVolume, projectName, volumeName, backupConf, func(entryUUID string) error {
+		return pool.TrashCustomVolume(projectName, volumeName, entryUUID, op)
+	})
+}
+
+// trashEntryRemove deletes the volume kept in the trash for the entry and then the entry itself.
+func trashEntryRemove(s *state.State, entry *db.TrashEntry, op *operations.Operation) error {
+	poolVol, err := backup.ParseConfigYaml([]byte(entry.Config))
+	if err != nil {
+		return fmt.Errorf("Failed parsing trash entry backup config: %w", err)
+	}
+
+	pool, err := storagePools.LoadByName(s, entry.StoragePool)
+	if err != nil {
+		return err
+	}
+
+	err = pool.DeleteTrashVolume(poolVol, entry.UUID, op)
+	if err != nil {
+		return fmt.Errorf("Failed deleting trash volume: %w", err)
+	}
+
+	return s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.DeleteTrashEntry(ctx, entry.UUID)
+	})
+}
+
+// swagger:operation GET /1.0/trash trash trash_get
+//
+//	List the trash entries
+//
+//	Returns a list of trash entries (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Sync response
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/trash/5d9b3a6c-1a2f-4c1e-9d0c-6f0e2e8a3b71",
+//	              "/1.0/trash/0c6f3b8e-7d2a-4e57-a1b9-3f8d2c4e6a10"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/trash?recursion=1 trash trash_get_recursion1
+//
+//	Get the trash entries
+//
+//	Returns a list of trash entries (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of trash entries
+//	          items:
+//	            $ref: "#/definitions/TrashEntry"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntriesGet(d *Daemon, r *http.Request) response.Response {
+	var entries []db.TrashEntry
+	err := d.State().DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		entries, err = tx.GetTrashEntries(ctx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !localUtil.IsRecursionRequest(r) {
+		urls := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "trash", entry.UUID).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]api.TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.ToAPI())
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation GET /1.0/trash/{uuid} trash trash_entry_get
+//
+//	Get the trash entry
+//
+//	Gets a specific trash entry.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Trash entry
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/TrashEntry"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryGet(d *Daemon, r *http.Request) response.Response {
+	entry, err := trashEntryLoad(d.State(), r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entry.ToAPI())
+}
+
+// trashEntryLoad loads the trash entry referenced by the request.
+func trashEntryLoad(s *state.State, r *http.Request) (*db.TrashEntry, error) {
+	entryUUID, err := url.PathUnescape(mux.Vars(r)["uuid"])
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *db.TrashEntry
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		entry, err = tx.GetTrashEntry(ctx, entryUUID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// swagger:operation POST /1.0/trash/{uuid} trash trash_entry_post
+//
+//	Restore the trash entry
+//
+//	Restores the deleted instance or custom storage volume and removes the entry from the trash.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: entry
+//	    description: Restore request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/TrashEntryPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	entry, err := trashEntryLoad(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Restore on the member holding the entry.
+	resp := forwardedResponseToNode(s, r, entry.Node)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.TrashEntryPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return response.BadRequest(err)
+	}
+
+	name := entry.Name
+	if req.Name != "" {
+		name = req.Name
+	}
+
+	run := func(op *operations.Operation) error {
+		reverter := revert.New()
+		defer reverter.Fail()
+
+		poolVol, err := backup.ParseConfigYaml([]byte(entry.Config))
+		if err != nil {
+			return fmt.Errorf("Failed parsing trash entry backup config: %w", err)
+		}
+
+		pool, err := storagePools.LoadByName(s, entry.StoragePool)
+		if err != nil {
+			return err
+		}
+
+		var cleanup revert.Hook
+
+		switch entry.Type {
+		case api.TrashEntryTypeInstance:
+			cleanup, err = trashRestoreInstance(s, pool, entry, poolVol, name, op)
+		case api.TrashEntryTypeStorageVolume:
+			cleanup, err = trashRestoreStorageVolume(s, pool, entry, poolVol, name, op)
+		default:
+			err = fmt.Errorf("Unknown trash entry type %q", entry.Type)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(cleanup)
+
+		// The volume is back in place so only the entry itself is left to remove.
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.DeleteTrashEntry(ctx, entry.UUID)
+		})
+		if err != nil {
+			return err
+		}
+
+		reverter.Success()
+		return nil
+	}
+
+	resources := map[string][]api.URL{}
+	if entry.Type == api.TrashEntryTypeInstance {
+		resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name).Project(entry.Project)}
+	} else {
+		resources["storage_volumes"] = []api.URL{*api.NewURL().Path(version.APIVersion, "storage-pools", entry.StoragePool, "volumes", "custom", name).Project(entry.Project)}
+	}
+
+	op, err := operations.OperationCreate(s, entry.Project, operations.OperationClassTask, operationtype.TrashRestore, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// trashRestoreInstance moves the instance volume back out of the trash and recreates the instance records.
+// Returns a revert hook undoing the restoration.
+func trashRestoreInstance(s *state.State, pool storagePools.Pool, entry *db.TrashEntry, poolVol *backupConfig.Config, name string, op *operations.Operation) (revert.Hook, error) {
+	if poolVol.Container == nil {
+		return nil, errors.New("Trash entry is missing the instance configuration")
+	}
+
+	poolVol.Container.Name = name
+
+	// Check that the project limits allow for the instance to be restored.
+	var profiles []api.Profile
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		req := api.InstancesPost{
+			InstancePut: poolVol.Container.InstancePut,
+			Name:        name,
+			Type:        api.InstanceType(poolVol.Container.Type),
+		}
+
+		err := project.AllowInstanceCreation(tx, entry.Project, req)
+		if err != nil {
+			return err
+		}
+
+		profiles, err = tx.GetProfiles(ctx, entry.Project, poolVol.Container.Profiles)
+		if err != nil {
+			return fmt.Errorf("Failed loading profiles (%v) for instance: %w", strings.Join(poolVol.Container.Profiles, ", "), err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	cleanup, err := pool.RestoreTrashVolume(entry.Project, poolVol, entry.UUID, op)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(cleanup)
+
+	inst, cleanup, err := internalRecoverImportInstance(s, pool, entry.Project, poolVol, profiles)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating instance %q record: %w", name, err)
+	}
+
+	reverter.Add(cleanup)
+
+	for _, snap := range poolVol.Snapshots {
+		var snapProfiles []api.Profile
+
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			snapProfiles, err = tx.GetProfiles(ctx, entry.Project, snap.Profiles)
+
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading profiles (%v) for instance snapshot %q: %w", strings.Join(snap.Profiles, ", "), snap.Name, err)
+		}
+
+		cleanup, err := internalRecoverImportInstanceSnapshot(s, pool, entry.Project, poolVol, snap, snapProfiles)
+		if err != nil {
+			return nil, fmt.Errorf("Failed creating instance %q snapshot %q record: %w", name, snap.Name, err)
+		}
+
+		reverter.Add(cleanup)
+	}
+
+	// Recreate instance mount path and symlinks (must come after snapshot recovery).
+	cleanup, err = pool.ImportInstance(inst, poolVol, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed importing instance %q: %w", name, err)
+	}
+
+	reverter.Add(cleanup)
+
+	// Reinitialize the instance's root disk quota as the storage volume got a new DB ID.
+	_, rootConfig, err := internalInstance.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+	if err == nil {
+		err = pool.SetInstanceQuota(inst, rootConfig["size"], rootConfig["size.state"], op)
+		if err != nil {
+			return nil, fmt.Errorf("Failed reinitializing root disk quota %q for instance %q: %w", rootConfig["size"], name, err)
+		}
+	}
+
+	// Refresh the backup file as the instance may have been restored under a new name.
+	err = inst.UpdateBackupFile()
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup = reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
+
+// trashRestoreStorageVolume moves the custom volume back out of the trash and recreates the volume records.
+// Returns a revert hook undoing the restoration.
+func trashRestoreStorageVolume(s *state.State, pool storagePools.Pool, entry *db.TrashEntry, poolVol *backupConfig.Config, name string, op *operations.Operation) (revert.Hook, error) {
+	if poolVol.Volume == nil {
+		return nil, errors.New("Trash entry is missing the volume configuration")
+	}
+
+	poolVol.Volume.Name = name
+
+	// Check that the project limits allow for the volume to be restored.
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		req := api.StorageVolumesPost{
+			StorageVolumePut: poolVol.Volume.StorageVolumePut,
+			Name:             name,
+			Type:             db.StoragePoolVolumeTypeNameCustom,
+			ContentType:      poolVol.Volume.ContentType,
+		}
+
+		return project.AllowVolumeCreation(tx, entry.Project, pool.Name(), req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	cleanup, err := pool.RestoreTrashVolume(entry.Project, poolVol, entry.UUID, op)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(cleanup)
+
+	cleanup, err = pool.ImportCustomVolume(entry.Project, poolVol, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed importing storage volume %q: %w", name, err)
+	}
+
+	reverter.Add(cleanup)
+
+	cleanup = reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
+
+// swagger:operation DELETE /1.0/trash/{uuid} trash trash_entry_delete
+//
+//	Purge the trash entry
+//
+//	Permanently removes the entry from the trash.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func trashEntryDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	entry, err := trashEntryLoad(s, r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Purge on the member holding the entry.
+	resp := forwardedResponseToNode(s, r, entry.Node)
+	if resp != nil {
+		return resp
+	}
+
+	err = trashEntryRemove(s, entry, nil)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+func pruneExpiredTrashTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		opRun := func(op *operations.Operation) error {
+			return pruneExpiredTrash(ctx, s)
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.TrashPrune, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating prune expired trash operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Pruning expired trash entries")
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting prune expired trash operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed pruning expired trash entries", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Debug("Done pruning expired trash entries")
+	}
+
+	return f, task.Hourly()
+}
+
+// pruneExpiredTrash removes the expired trash entries stored on this member.
+func pruneExpiredTrash(ctx context.Context, s *state.State) error {
+	var entries []db.TrashEntry
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		entries, err = tx.GetLocalTrashEntries(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading trash entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		if time.Now().Before(entry.ExpiryDate) {
+			continue
+		}
+
+		err := trashEntryRemove(s, &entry, nil)
+		if err != nil {
+			return fmt.Errorf("Failed removing trash entry %q: %w", entry.UUID, err)
+		}
+	}
+
+	return nil
+}
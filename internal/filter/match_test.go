@@ -83,3 +83,33 @@ func TestMatch_Image(t *testing.T) {
 		})
 	}
 }
+
+func TestMatch_Certificate(t *testing.T) {
+	certificate := api.Certificate{
+		CertificatePut: api.CertificatePut{
+			Name: "castiana",
+			Type: api.CertificateTypeClient,
+			Labels: map[string]string{
+				"team":        "web",
+				"environment": "production",
+			},
+		},
+	}
+
+	cases := map[string]any{
+		"labels.team eq web": true,
+		"labels.team eq db":  false,
+		"labels.environment eq production and type eq client": true,
+		"labels.missing eq web":                               false,
+	}
+
+	for s := range cases {
+		t.Run(s, func(t *testing.T) {
+			f, err := filter.Parse(s, filter.QueryOperatorSet())
+			require.NoError(t, err)
+			match, err := filter.Match(certificate, *f)
+			require.NoError(t, err)
+			assert.Equal(t, cases[s], match)
+		})
+	}
+}
@@ -10,4 +10,5 @@ const (
 	Restart  InstanceAction = "restart"
 	Freeze   InstanceAction = "freeze"
 	Unfreeze InstanceAction = "unfreeze"
+	SyncTime InstanceAction = "sync-time"
 )
@@ -3,6 +3,8 @@ package instance
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +31,27 @@ var HugePageSizeSuffix = [...]string{"64KB", "1MB", "2MB", "1GB"}
 
 // InstanceConfigKeysAny is a map of config key to validator. (keys applying to containers AND virtual machines).
 var InstanceConfigKeysAny = map[string]func(value string) error{
+	// gendoc:generate(entity=instance, group=backups, key=backups.keep_last)
+	// Older backups beyond this count are deleted by the hourly backup pruning task.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of most recent backups to keep
+	"backups.keep_last": validate.Optional(validate.IsInRange(1, math.MaxUint32)),
+
+	// gendoc:generate(entity=instance, group=backups, key=backups.retention)
+	// Specify an expression like `1M 2H 3d 4w 5m 6y`.
+	// Backups older than this are deleted by the hourly backup pruning task.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: How long backups are to be kept
+	"backups.retention": func(value string) error {
+		// Validate expression
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	},
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.autorestart)
 	// If set to `true` will attempt up to 10 restarts over a 1 minute period upon unexpected instance exit.
 	// ---
@@ -37,6 +60,27 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Whether to automatically restart an instance on unexpected exit
 	"boot.autorestart": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.restart_policy)
+	// Possible values are `never`, `on-failure` and `always`.
+	// With `on-failure`, the instance is restarted if it crashed, while a clean power off from within the instance is left alone.
+	// For containers, any stop not requested through Incus is considered a failure.
+	// Restarts are delayed using an exponential backoff (starting at one second, up to five minutes).
+	// When set, this takes precedence over `boot.autorestart`.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: When to automatically restart an instance that stopped on its own
+	"boot.restart_policy": validate.Optional(validate.IsOneOf("never", "on-failure", "always")),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.restart_policy.max_retries)
+	// The counter is reset once the instance has been running for ten minutes. Set to `0` for no limit.
+	// ---
+	//  type: integer
+	//  defaultdesc: `10`
+	//  liveupdate: yes
+	//  shortdesc: Maximum number of consecutive restarts through `boot.restart_policy`
+	"boot.restart_policy.max_retries": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.autostart)
 	// If set to `false`, restore the last state.
 	// ---
@@ -63,6 +107,33 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on)
+	// Comma-separated list of instances in the same project that must be started before this instance when the daemon starts.
+	// See {ref}`instances-autostart-dependencies`.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Instances to start before this instance
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on.ready)
+	// If set to `true`, the dependencies must report being ready through `/dev/incus` rather than just be running.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  shortdesc: Whether to wait for the dependencies to be ready
+	"boot.depends_on.ready": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on.timeout)
+	// The number of seconds to wait for the dependencies before giving up on starting the instance.
+	// ---
+	//  type: integer
+	//  defaultdesc: 300
+	//  liveupdate: no
+	//  shortdesc: How long to wait for the dependencies
+	"boot.depends_on.timeout": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -149,6 +220,26 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  condition: If supported by image
 	//  shortdesc: Legacy version of `cloud-init.vendor-data`
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=console.syslog)
+	// Set to `local` to forward the console output to the host syslog (and so `journald`), or to an address like `udp://syslog01.int.example.net:514` or `tcp://syslog01.int.example.net:514` to forward it to a remote syslog server.
+	// Each console line is sent with the `incus-console` tag and prefixed with the project, name and type of the instance.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Where to forward the console output to
+	"console.syslog": validate.Optional(isConsoleSyslogTarget),
+
+	// gendoc:generate(entity=instance, group=miscellaneous, key=cluster.anti_affinity)
+	// Instances sharing the same value are spread over different cluster members whenever possible,
+	// both during automatic placement and when rebalancing the cluster.
+	//
+	// See {ref}`cluster-rebalance` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Name of the anti-affinity group of the instance
+	"cluster.anti_affinity": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=cluster.evacuate)
 	// The `cluster.evacuate` provides control over how instances are handled when a cluster member is being
 	// evacuated.
@@ -177,6 +268,81 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=cluster.healing)
+	// When {config:option}`server-cluster:cluster.healing_threshold` is set, only the instances with this option enabled
+	// are restarted on another server when their server goes offline.
+	//
+	// See {ref}`cluster-automatic-evacuation` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to restart the instance on another server when its server fails
+	"cluster.healing": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.on_failure)
+	// Possible values are `fail` and `ignore`.
+	// When set to `fail`, a failing `hooks.pre-start` or `hooks.pre-stop` hook aborts the start or stop of the instance.
+	// ---
+	//  type: string
+	//  defaultdesc: `fail`
+	//  liveupdate: yes
+	//  shortdesc: What to do when a pre-start or pre-stop hook fails
+	"hooks.on_failure": validate.Optional(validate.IsOneOf("fail", "ignore")),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.post-start)
+	// Absolute path to an executable on the host or `http://` or `https://` URL to call after the instance has started.
+	// The hook runs in the background and its failures are only logged.
+	//
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Hook to run after the instance has started
+	"hooks.post-start": validate.Optional(isLifecycleHook),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.post-stop)
+	// Absolute path to an executable on the host or `http://` or `https://` URL to call after the instance has stopped.
+	// The hook runs in the background and its failures are only logged.
+	//
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Hook to run after the instance has stopped
+	"hooks.post-stop": validate.Optional(isLifecycleHook),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.pre-start)
+	// Absolute path to an executable on the host or `http://` or `https://` URL to call before the instance is started.
+	// If the hook fails, the operation is aborted unless {config:option}`instance-hooks:hooks.on_failure` is set to `ignore`.
+	//
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Hook to run before the instance is started
+	"hooks.pre-start": validate.Optional(isLifecycleHook),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.pre-stop)
+	// Absolute path to an executable on the host or `http://` or `https://` URL to call before the instance is stopped or shut down.
+	// If the hook fails, the operation is aborted unless {config:option}`instance-hooks:hooks.on_failure` is set to `ignore`.
+	//
+	// See {ref}`instance-options-hooks` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Hook to run before the instance is stopped or shut down
+	"hooks.pre-stop": validate.Optional(isLifecycleHook),
+
+	// gendoc:generate(entity=instance, group=hooks, key=hooks.timeout)
+	// Hooks still running after this duration (for example, `30s` or `5m`) are killed or cancelled and considered failed.
+	// ---
+	//  type: string
+	//  defaultdesc: `30s`
+	//  liveupdate: yes
+	//  shortdesc: How long to wait for a hook to complete
+	"hooks.timeout": validate.Optional(validate.IsMinimumDuration(time.Second)),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -199,6 +365,18 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Which NUMA nodes to place the instance CPUs on
 	"limits.cpu.nodes": validate.Optional(validate.Or(validate.IsValidCPUSet, validate.IsOneOf("0", "balanced"))),
 
+	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu.pin_strategy)
+	// Possible values are `none` (the CPUs are shared with other instances) and `isolated`
+	// (whole CPU cores are reserved for the instance on the server when it starts).
+	//
+	// See {ref}`instance-options-limits-cpu-isolated` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: no
+	//  shortdesc: How the instance CPUs are allocated
+	"limits.cpu.pin_strategy": validate.Optional(validate.IsOneOf("none", "isolated")),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.disk.priority)
 	// Controls how much priority to give to the instance's I/O requests when under load.
 	//
@@ -287,6 +465,15 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Whether `/dev/incus` is present in the instance
 	"security.guestapi": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.nesting)
+	// For virtual machines, this requires nested virtualization to be available on the server.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to support running Incus (nested) inside the instance
+	"security.nesting": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=security, key=security.protection.delete)
 	//
 	// ---
@@ -341,6 +528,51 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.keep_hourly)
+	// Keeps the most recent snapshot of each of the given number of most recent hours that have snapshots.
+	// See {ref}`instances-snapshots-retention` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of hours to keep a snapshot for
+	"snapshots.keep_hourly": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.keep_daily)
+	// Keeps the most recent snapshot of each of the given number of most recent days that have snapshots.
+	// See {ref}`instances-snapshots-retention` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of days to keep a snapshot for
+	"snapshots.keep_daily": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.keep_weekly)
+	// Keeps the most recent snapshot of each of the given number of most recent weeks that have snapshots.
+	// See {ref}`instances-snapshots-retention` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of weeks to keep a snapshot for
+	"snapshots.keep_weekly": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.keep_monthly)
+	// Keeps the most recent snapshot of each of the given number of most recent months that have snapshots.
+	// See {ref}`instances-snapshots-retention` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of months to keep a snapshot for
+	"snapshots.keep_monthly": validate.Optional(validate.IsUint32),
+
+	// gendoc:generate(entity=instance, group=snapshots, key=snapshots.keep_yearly)
+	// Keeps the most recent snapshot of each of the given number of most recent years that have snapshots.
+	// See {ref}`instances-snapshots-retention` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: no
+	//  shortdesc: Number of years to keep a snapshot for
+	"snapshots.keep_yearly": validate.Optional(validate.IsUint32),
+
 	// Volatile keys.
 
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_template)
@@ -357,6 +589,20 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Hash of the base image
 	"volatile.base_image": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.blueprint.name)
+	// The name of the blueprint that the instance was created from (empty if the instance was not created from a blueprint).
+	// ---
+	//  type: string
+	//  shortdesc: Name of the blueprint
+	"volatile.blueprint.name": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.blueprint.version)
+	// The version of the blueprint that the instance was created from.
+	// ---
+	//  type: integer
+	//  shortdesc: Version of the blueprint
+	"volatile.blueprint.version": validate.Optional(validate.IsInt64),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.cloud_init.instance-id)
 	//
 	// ---
@@ -379,6 +625,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Instance NUMA node
 	"volatile.cpu.nodes": validate.Optional(validate.Or(validate.IsValidCPUSet, validate.IsOneOf("0", "balanced"))),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.cpu.isolated)
+	// The CPU threads that were reserved for the instance when using `limits.cpu.pin_strategy=isolated`.
+	// ---
+	//  type: string
+	//  shortdesc: Reserved CPU threads
+	"volatile.cpu.isolated": validate.Optional(validate.IsValidCPUSet),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.evacuate.origin)
 	// The cluster member that the instance lived on before evacuation.
 	// ---
@@ -614,6 +867,17 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Percentage of memory to have in sync before stopping the instance
 	"migration.incremental.memory.goal": validate.Optional(validate.IsUint32),
 
+	// gendoc:generate(entity=instance, group=migration, key=migration.incremental.memory.timeout)
+	// Once this many seconds have been spent transferring memory incrementally, the instance is stopped
+	// and the final transfer happens, even if the goal or the number of iterations haven't been reached.
+	// ---
+	//  type: integer
+	//  defaultdesc: `0` (no limit)
+	//  liveupdate: yes
+	//  condition: container
+	//  shortdesc: Maximum time (in seconds) to spend on incremental memory transfer
+	"migration.incremental.memory.timeout": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=nvidia, key=nvidia.runtime)
 	//
 	// ---
@@ -708,6 +972,19 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: Raw Seccomp configuration
 	"raw.seccomp": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=security, key=security.delegate_cgroup)
+	// When enabled, the container is given its own cgroup2 sub-tree with all available controllers delegated to it,
+	// allowing `systemd` or nested container runtimes to manage their own resource hierarchy.
+	// Resource limits keep being enforced on the parent cgroup, out of reach of the container.
+	// This requires a pure cgroup2 host.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: container
+	//  shortdesc: Whether to delegate a cgroup sub-tree to the container
+	"security.delegate_cgroup": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=security, key=security.guestapi.images)
 	//
 	// ---
@@ -746,16 +1023,6 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 	//  shortdesc: The size of the idmap to use
 	"security.idmap.size": validate.Optional(validate.IsUint32),
 
-	// gendoc:generate(entity=instance, group=security, key=security.nesting)
-	//
-	// ---
-	//  type: bool
-	//  defaultdesc: `false`
-	//  liveupdate: yes
-	//  condition: container
-	//  shortdesc: Whether to support running Incus (nested) inside the instance
-	"security.nesting": validate.Optional(validate.IsBool),
-
 	// gendoc:generate(entity=instance, group=security, key=security.privileged)
 	//
 	// ---
@@ -963,13 +1230,17 @@ var InstanceConfigKeysContainer = map[string]func(value string) error{
 var InstanceConfigKeysVM = map[string]func(value string) error{
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.memory.hugepages)
 	// If this option is set to `false`, regular system memory is used.
+	// If set to `true`, huge pages of the default size are used.
+	// A specific page size can also be selected (`64KB`, `1MB`, `2MB`, `32MB`, `512MB`, `1GB` or `16GB`, depending on the architecture).
+	//
+	// See {ref}`instance-options-limits-hugepages` for more information.
 	// ---
-	//  type: bool
+	//  type: string
 	//  defaultdesc: `false`
 	//  liveupdate: no
 	//  condition: virtual machine
-	//  shortdesc: Whether to back the instance using huge pages
-	"limits.memory.hugepages": validate.Optional(validate.IsBool),
+	//  shortdesc: Whether to back the instance using huge pages (and of which size)
+	"limits.memory.hugepages": validate.Optional(validate.Or(validate.IsBool, validate.IsOneOf("64KB", "1MB", "2MB", "32MB", "512MB", "1GB", "16GB"))),
 
 	// Caller is responsible for full validation of any raw.* value.
 
@@ -1067,6 +1338,47 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether UEFI secure boot is enforced with the default Microsoft keys
 	"security.secureboot": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=security, key=security.secureboot.db)
+	// The certificates are added to the UEFI signature database (`db`) on top of the default Microsoft keys.
+	// This allows booting operating systems and kernels signed with custom keys.
+	// Changes are applied the next time the instance is started and reset any changes made to the UEFI variables from within the instance.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: PEM encoded certificates to add to the UEFI secure boot signature database
+	"security.secureboot.db": validate.Optional(validate.IsPEMCertificates),
+
+	// gendoc:generate(entity=instance, group=security, key=security.secureboot.dbx)
+	// The content is a UEFI forbidden signature database update (as distributed by the UEFI Forum), encoded in base64.
+	// Changes are applied the next time the instance is started and reset any changes made to the UEFI variables from within the instance.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Base64 encoded forbidden signature database (`dbx`) update
+	"security.secureboot.dbx": validate.Optional(validate.IsBase64),
+
+	// gendoc:generate(entity=instance, group=security, key=security.secureboot.kek)
+	// The certificates are added to the UEFI Key Exchange Key database (`KEK`) on top of the default Microsoft keys.
+	// Changes are applied the next time the instance is started and reset any changes made to the UEFI variables from within the instance.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: PEM encoded certificates to add to the UEFI Key Exchange Key database
+	"security.secureboot.kek": validate.Optional(validate.IsPEMCertificates),
+
+	// gendoc:generate(entity=instance, group=security, key=security.secureboot.pk)
+	// The certificate replaces the default UEFI Platform Key (`PK`).
+	// Changes are applied the next time the instance is started and reset any changes made to the UEFI variables from within the instance.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: PEM encoded certificate to use as the UEFI Platform Key
+	"security.secureboot.pk": validate.Optional(validate.IsPEMCertificate),
+
 	// gendoc:generate(entity=instance, group=security, key=security.sev)
 	//
 	// ---
@@ -1107,6 +1419,37 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: The guest owner's `base64`-encoded session blob
 	"security.sev.session.data": validate.Optional(validate.IsAny),
 
+	// gendoc:generate(entity=instance, group=clock, key=agent.time_sync)
+	// See {ref}`instance-options-clock`.
+	// ---
+	//  type: bool
+	//  defaultdesc: `true`
+	//  liveupdate: yes
+	//  condition: virtual machine
+	//  shortdesc: Whether the agent synchronizes the guest clock after the VM is resumed, restored or migrated
+	"agent.time_sync": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=clock, key=clock.kvm_clock)
+	// Only applies to x86_64 virtual machines.
+	// Disabling it makes the guest rely on the TSC or HPET clock sources instead.
+	// ---
+	//  type: bool
+	//  defaultdesc: `true`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to expose the `kvm-clock` paravirtualized clock source to the guest
+	"clock.kvm_clock": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=clock, key=clock.rtc_base)
+	// Possible values are `utc` and `localtime` (host time zone).
+	// ---
+	//  type: string
+	//  defaultdesc: `localtime` for Windows guests, `utc` otherwise
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: What the real-time clock of the guest is set to
+	"clock.rtc_base": validate.Optional(validate.IsOneOf("utc", "localtime")),
+
 	// gendoc:generate(entity=instance, group=miscellaneous, key=agent.nic_config)
 	// For containers, the name and MTU of the default network interfaces is used for the instance devices.
 	// For virtual machines, set this option to `true` to set the name and MTU of the default network interfaces to be the same as the instance devices.
@@ -1118,6 +1461,18 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Whether to use the name and MTU of the default network interfaces
 	"agent.nic_config": validate.Optional(validate.IsBool),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=windows.drivers)
+	// When enabled, an ISO image with the VirtIO drivers for Windows is attached to the virtual machine as a USB CD-ROM
+	// and the virtual machine is configured as if `image.os` was set to `Windows`.
+	// The ISO image is looked up in `/usr/share/virtio-win/virtio-win.iso` unless the `INCUS_WINDOWS_DRIVERS_PATH` environment variable is set.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: no
+	//  condition: virtual machine
+	//  shortdesc: Whether to attach the Windows VirtIO drivers
+	"windows.drivers": validate.Optional(validate.IsBool),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.apply_nvram)
 	//
 	// ---
@@ -1139,6 +1494,13 @@ var InstanceConfigKeysVM = map[string]func(value string) error{
 	//  shortdesc: Real Time Clock change adjustment
 	"volatile.vm.rtc_adjustment": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.vm.hugepages)
+	// The number and size of the huge pages added to the host pool when the virtual machine started.
+	// ---
+	//  type: string
+	//  shortdesc: Huge pages allocated for the instance
+	"volatile.vm.hugepages": validate.IsAny,
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.vm.rtc_offset)
 	// Real Time Clock offset to allow virtual machines to run on a different base than the host.
 	// ---
@@ -1565,3 +1927,35 @@ func InstanceIncludeWhenCopying(configKey string, remoteCopy bool) bool {
 
 	return true // Keep all other keys.
 }
+
+// isLifecycleHook validates that a lifecycle hook is an absolute path or an HTTP(S) URL.
+func isLifecycleHook(value string) error {
+	if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return validate.IsRequestURL(value)
+	}
+
+	if !strings.HasPrefix(value, "/") {
+		return errors.New("Hooks must be an absolute path or an HTTP(S) URL")
+	}
+
+	return nil
+}
+
+// isConsoleSyslogTarget validates that a console syslog target is "local" or a TCP or UDP address.
+func isConsoleSyslogTarget(value string) error {
+	if value == "local" {
+		return nil
+	}
+
+	protocol, address, ok := strings.Cut(value, "://")
+	if !ok || (protocol != "tcp" && protocol != "udp") {
+		return errors.New("Console syslog target must be \"local\" or a tcp:// or udp:// address")
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" || port == "" {
+		return fmt.Errorf("Invalid console syslog address %q", address)
+	}
+
+	return nil
+}
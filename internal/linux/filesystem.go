@@ -114,6 +114,35 @@ func hasMountEntry(name string) int {
 	return 0
 }
 
+// MountDevices returns the device number (major:minor) backing each mount point of a process' mount namespace.
+// When a path is mounted over, the device of the topmost mount is returned.
+func MountDevices(pid int) (map[string]string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/mountinfo", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	devices := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		tokens := strings.Fields(scanner.Text())
+		if len(tokens) < 5 {
+			continue
+		}
+
+		devices[filepath.Clean(tokens[4])] = tokens[2]
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
 // IsMountPoint returns true if path is a mount point.
 func IsMountPoint(path string) bool {
 	// If we find a mount entry, it is obviously a mount point.
@@ -0,0 +1,100 @@
+package linux
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// PressureResources is the list of resources for which the kernel reports pressure stall information.
+var PressureResources = []string{"cpu", "memory", "io"}
+
+// ParsePressure parses the content of a PSI file (such as /proc/pressure/cpu or cpu.pressure in a cgroup).
+func ParsePressure(content string) (*api.InstanceStatePressureResource, error) {
+	pressure := api.InstanceStatePressureResource{}
+
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stats *api.InstanceStatePressureStats
+
+		switch fields[0] {
+		case "some":
+			stats = &pressure.Some
+		case "full":
+			stats = &pressure.Full
+		default:
+			return nil, fmt.Errorf("Unknown pressure line type %q", fields[0])
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("Invalid pressure field %q", field)
+			}
+
+			var err error
+
+			switch key {
+			case "avg10":
+				stats.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				stats.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				stats.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				stats.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("Failed parsing pressure field %q: %w", field, err)
+			}
+		}
+	}
+
+	return &pressure, nil
+}
+
+// GetPressure returns the system-wide pressure stall information for the given resource.
+func GetPressure(resource string) (*api.InstanceStatePressureResource, error) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/pressure/%s", resource))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePressure(string(content))
+}
+
+// PressureState returns the pressure stall information for all resources, using getPressure to retrieve each of them.
+// Resources which can't be retrieved are skipped and nil is returned if none are available.
+func PressureState(getPressure func(resource string) (*api.InstanceStatePressureResource, error)) *api.InstanceStatePressure {
+	var state *api.InstanceStatePressure
+
+	for _, resource := range PressureResources {
+		pressure, err := getPressure(resource)
+		if err != nil {
+			continue
+		}
+
+		if state == nil {
+			state = &api.InstanceStatePressure{}
+		}
+
+		switch resource {
+		case "cpu":
+			state.CPU = pressure
+		case "memory":
+			state.Memory = pressure
+		case "io":
+			state.IO = pressure
+		}
+	}
+
+	return state
+}
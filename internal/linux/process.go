@@ -0,0 +1,181 @@
+package linux
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// processTickDuration is the length (in nanoseconds) of a clock tick as reported in /proc/PID/stat (USER_HZ is always 100).
+const processTickDuration = 10000000
+
+// ProcessInfo returns the details of a process from /proc.
+// The PIDs of the process (from the outermost to the innermost PID namespace) are also returned.
+func ProcessInfo(pid int64) (*api.InstanceProcess, []int64, error) {
+	procPath := fmt.Sprintf("/proc/%d", pid)
+
+	// Parse the stat file, the command name is between parentheses and may contain spaces.
+	stat, err := os.ReadFile(procPath + "/stat")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := bytes.IndexByte(stat, '(')
+	end := bytes.LastIndexByte(stat, ')')
+	if start < 0 || end < start {
+		return nil, nil, fmt.Errorf("Invalid stat file for process %d", pid)
+	}
+
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return nil, nil, fmt.Errorf("Invalid stat file for process %d", pid)
+	}
+
+	process := &api.InstanceProcess{
+		PID:         pid,
+		Command:     string(stat[start+1 : end]),
+		State:       fields[0],
+		CommandLine: []string{},
+	}
+
+	values := map[int]*int64{1: &process.ParentPID, 17: &process.Threads}
+	for index, value := range values {
+		*value, err = strconv.ParseInt(fields[index], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed parsing stat file for process %d: %w", pid, err)
+		}
+	}
+
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing stat file for process %d: %w", pid, err)
+	}
+
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing stat file for process %d: %w", pid, err)
+	}
+
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing stat file for process %d: %w", pid, err)
+	}
+
+	process.CPUUsage = (utime + stime) * processTickDuration
+	process.MemoryUsage = rss * int64(os.Getpagesize())
+
+	// Get the user and the namespaced PIDs from the status file.
+	pids := []int64{pid}
+
+	f, err := os.Open(procPath + "/status")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+
+		values := strings.Fields(value)
+		if len(values) == 0 {
+			continue
+		}
+
+		switch key {
+		case "Uid":
+			process.UID, err = strconv.ParseInt(values[0], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed parsing status file for process %d: %w", pid, err)
+			}
+
+		case "NSpid":
+			pids = make([]int64, 0, len(values))
+			for _, value := range values {
+				nsPID, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("Failed parsing status file for process %d: %w", pid, err)
+				}
+
+				pids = append(pids, nsPID)
+			}
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Kernel threads have an empty command line.
+	cmdline, err := os.ReadFile(procPath + "/cmdline")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, arg := range bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0}) {
+		if len(arg) > 0 {
+			process.CommandLine = append(process.CommandLine, string(arg))
+		}
+	}
+
+	return process, pids, nil
+}
+
+// ProcessList returns the details of all processes accepted by the filter (or all processes if the filter is nil).
+// The process IDs are reported as seen from the PID namespace at the given nesting level (0 being the current one),
+// parents outside of the listed processes are reported as 0. Processes exiting while the list is built are skipped.
+func ProcessList(filter func(pid int64) bool, nsLevel int) ([]api.InstanceProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	processes := []api.InstanceProcess{}
+	nsPIDs := map[int64]int64{}
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if filter != nil && !filter(pid) {
+			continue
+		}
+
+		process, pids, err := ProcessInfo(pid)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, unix.ESRCH) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if nsLevel >= len(pids) {
+			continue
+		}
+
+		nsPIDs[pid] = pids[nsLevel]
+		processes = append(processes, *process)
+	}
+
+	for i := range processes {
+		processes[i].PID = nsPIDs[processes[i].PID]
+		processes[i].ParentPID = nsPIDs[processes[i].ParentPID]
+	}
+
+	return processes, nil
+}
@@ -1,7 +1,6 @@
 package acme
 
 import (
-	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -85,7 +84,7 @@ func UpdateCertificate(s *state.State, challengeType string, clustered bool, dom
 	}
 
 	// Load the certificate.
-	certInfo, err := internalUtil.LoadCert(s.OS.VarDir)
+	certInfo, err := internalUtil.LoadCert(s.OS.VarDir, s.LocalConfig.ServerKeyURI())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to load certificate and key file: %w", err)
 	}
@@ -148,7 +147,29 @@ func UpdateCertificate(s *state.State, challengeType string, clustered bool, dom
 
 	args = append(args, "run")
 
-	_, _, err = subprocess.RunCommandSplit(context.TODO(), env, nil, "lego", args...)
+	// Only retry on background renewals, forced renewals report the failure straight back.
+	attempts := retries
+	if force {
+		attempts = 1
+	}
+
+	for i := range attempts {
+		_, _, err = subprocess.RunCommandSplit(s.ShutdownCtx, env, nil, "lego", args...)
+		if err == nil {
+			break
+		}
+
+		l.Warn("Failed to obtain certificate", logger.Ctx{"attempt": i + 1, "err": err})
+
+		if i+1 < attempts {
+			select {
+			case <-s.ShutdownCtx.Done():
+				return nil, s.ShutdownCtx.Err()
+			case <-time.After(time.Duration(i+1) * time.Minute):
+			}
+		}
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("Failed to run lego command: %w", err)
 	}
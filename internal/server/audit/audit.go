@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Log keeps the most recent authentication decisions in memory and forwards them to the configured sinks.
+type Log struct {
+	mu sync.Mutex
+
+	// Ring buffer of the most recent entries.
+	entries []api.AuditEntry
+	next    int
+	full    bool
+
+	// Sinks.
+	filePath string
+	file     *os.File
+	syslog   *syslog.Writer
+}
+
+// NewLog returns a new audit log keeping up to size entries in memory.
+func NewLog(size int) *Log {
+	return &Log{entries: make([]api.AuditEntry, size)}
+}
+
+// Configure sets up the file and syslog sinks, closing the ones no longer in use.
+// An empty path disables the file sink.
+func (l *Log) Configure(filePath string, useSyslog bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil && l.filePath != filePath {
+		_ = l.file.Close()
+		l.file = nil
+	}
+
+	l.filePath = filePath
+	if l.filePath != "" && l.file == nil {
+		f, err := os.OpenFile(l.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("Failed to open audit log file %q: %w", l.filePath, err)
+		}
+
+		l.file = f
+	}
+
+	if !useSyslog && l.syslog != nil {
+		_ = l.syslog.Close()
+		l.syslog = nil
+	} else if useSyslog && l.syslog == nil {
+		w, err := syslog.New(syslog.LOG_AUTHPRIV|syslog.LOG_INFO, "incus")
+		if err != nil {
+			return fmt.Errorf("Failed to connect to syslog: %w", err)
+		}
+
+		l.syslog = w
+	}
+
+	return nil
+}
+
+// Record adds an entry to the log.
+func (l *Log) Record(entry api.AuditEntry) {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+
+	if l.file == nil && l.syslog == nil {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if l.file != nil {
+		_, err = l.file.Write(append(data, '\n'))
+		if err != nil {
+			logger.Warn("Failed writing to audit log file", logger.Ctx{"path": l.filePath, "err": err})
+		}
+	}
+
+	if l.syslog != nil {
+		if entry.Success {
+			err = l.syslog.Info(string(data))
+		} else {
+			err = l.syslog.Warning(string(data))
+		}
+
+		if err != nil {
+			logger.Warn("Failed writing audit log to syslog", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// Entries returns the entries kept in memory, oldest first.
+func (l *Log) Entries() []api.AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]api.AuditEntry{}, l.entries[:l.next]...)
+	}
+
+	entries := make([]api.AuditEntry, 0, len(l.entries))
+	entries = append(entries, l.entries[l.next:]...)
+	entries = append(entries, l.entries[:l.next]...)
+
+	return entries
+}
+
+// Close closes the sinks.
+func (l *Log) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+
+	if l.syslog != nil {
+		_ = l.syslog.Close()
+		l.syslog = nil
+	}
+}
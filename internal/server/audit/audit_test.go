@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestLog_Entries(t *testing.T) {
+	l := NewLog(3)
+	assert.Empty(t, l.Entries())
+
+	for _, identity := range []string{"a", "b"} {
+		l.Record(api.AuditEntry{Identity: identity})
+	}
+
+	entries := l.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].Identity)
+	assert.Equal(t, "b", entries[1].Identity)
+	assert.False(t, entries[0].Timestamp.IsZero())
+
+	// Oldest entries are dropped once full.
+	for _, identity := range []string{"c", "d"} {
+		l.Record(api.AuditEntry{Identity: identity})
+	}
+
+	entries = l.Entries()
+	require.Len(t, entries, 3)
+	assert.Equal(t, "b", entries[0].Identity)
+	assert.Equal(t, "c", entries[1].Identity)
+	assert.Equal(t, "d", entries[2].Identity)
+}
+
+func TestLog_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l := NewLog(10)
+	defer l.Close()
+
+	l.Record(api.AuditEntry{Identity: "before"})
+
+	require.NoError(t, l.Configure(path, false))
+	l.Record(api.AuditEntry{Identity: "alice", Success: true})
+	l.Record(api.AuditEntry{Identity: "bob", Reason: "Client isn't trusted"})
+
+	// Disabling the sink stops writing to the file.
+	require.NoError(t, l.Configure("", false))
+	l.Record(api.AuditEntry{Identity: "after"})
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	identities := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry := api.AuditEntry{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		identities = append(identities, entry.Identity)
+	}
+
+	assert.Equal(t, []string{"alice", "bob"}, identities)
+	assert.Len(t, l.Entries(), 4)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/shared/api"
@@ -112,6 +113,8 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+	groupsFunc      func(ctx context.Context) (map[string][]string, error)
+	groupsInterval  time.Duration
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
@@ -153,6 +156,15 @@ func WithResourcesFunc(f func() (*Resources, error)) func(*Opts) {
 	}
 }
 
+// WithGroupsFunc can be passed into LoadAuthorizer when DriverOpenFGA is used to periodically synchronize
+// the members of groups (keyed by group name) from an external directory.
+func WithGroupsFunc(f func(ctx context.Context) (map[string][]string, error), interval time.Duration) func(*Opts) {
+	return func(o *Opts) {
+		o.groupsFunc = f
+		o.groupsInterval = interval
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initializes an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *certificate.Cache, options ...func(opts *Opts)) (Authorizer, error) {
 	opts := &Opts{}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -259,6 +260,33 @@ func (f *FGA) connect(ctx context.Context, certificateCache *certificate.Cache,
 		}(opts.resourcesFunc)
 	}
 
+	if opts.groupsFunc != nil {
+		// Start group membership sync routine.
+		go func(groupsFunc func(ctx context.Context) (map[string][]string, error), interval time.Duration) {
+			for {
+				groups, err := groupsFunc(f.shutdownCtx)
+				if err == nil {
+					// groups will be nil on cluster members that shouldn't be performing updates.
+					if groups != nil {
+						err := f.syncGroups(f.shutdownCtx, groups)
+						if err != nil {
+							logger.Error("Failed background OpenFGA group sync", logger.Ctx{"err": err})
+						}
+					}
+				} else {
+					logger.Error("Failed getting group memberships", logger.Ctx{"err": err})
+				}
+
+				select {
+				case <-time.After(interval):
+					continue
+				case <-f.shutdownCtx.Done():
+					return
+				}
+			}
+		}(opts.groupsFunc, opts.groupsInterval)
+	}
+
 	return nil
 }
 
@@ -277,8 +305,8 @@ func (f *FGA) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
-	// Use the TLS driver if the user authenticated with TLS.
-	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
+	// Use the TLS driver if the user authenticated with TLS or with credentials tied to a trusted certificate.
+	if slices.Contains(tlsAuthenticationMethods, details.authenticationProtocol()) {
 		return f.tls.CheckPermission(ctx, r, object, entitlement)
 	}
 
@@ -331,8 +359,8 @@ func (f *FGA) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
-	// Use the TLS driver if the user authenticated with TLS.
-	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
+	// Use the TLS driver if the user authenticated with TLS or with credentials tied to a trusted certificate.
+	if slices.Contains(tlsAuthenticationMethods, details.authenticationProtocol()) {
 		return f.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
 	}
 
@@ -950,8 +978,32 @@ func (f *FGA) updateTuples(ctx context.Context, writes []client.ClientTupleKey,
 	return f.sendTuples(ctx, writes, deletions)
 }
 
-// sendTuples directly sends the write/deletion tuples to OpenFGA.
+// sendTuplesBatchSize is the number of tuples sent to OpenFGA within a single timeout, so that large updates
+// (such as the initial group synchronization of a large directory) don't time out.
+const sendTuplesBatchSize = 250
+
+// sendTuples directly sends the write/deletion tuples to OpenFGA, in batches of sendTuplesBatchSize tuples.
 func (f *FGA) sendTuples(ctx context.Context, writes []client.ClientTupleKey, deletions []client.ClientTupleKeyWithoutCondition) error {
+	for {
+		batchWrites := writes[:min(len(writes), sendTuplesBatchSize)]
+		batchDeletions := deletions[:min(len(deletions), sendTuplesBatchSize-len(batchWrites))]
+
+		err := f.sendTuplesBatch(ctx, batchWrites, batchDeletions)
+		if err != nil {
+			return err
+		}
+
+		writes = writes[len(batchWrites):]
+		deletions = deletions[len(batchDeletions):]
+
+		if len(writes) == 0 && len(deletions) == 0 {
+			return nil
+		}
+	}
+}
+
+// sendTuplesBatch sends a batch of write/deletion tuples to OpenFGA.
+func (f *FGA) sendTuplesBatch(ctx context.Context, writes []client.ClientTupleKey, deletions []client.ClientTupleKeyWithoutCondition) error {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
@@ -965,13 +1017,13 @@ func (f *FGA) sendTuples(ctx context.Context, writes []client.ClientTupleKey, de
 
 	body := client.ClientWriteRequest{}
 
-	if writes != nil {
+	if len(writes) > 0 {
 		body.Writes = writes
 	} else {
 		body.Writes = []client.ClientTupleKey{}
 	}
 
-	if deletions != nil {
+	if len(deletions) > 0 {
 		body.Deletes = deletions
 	} else {
 		body.Deletes = []openfga.TupleKeyWithoutCondition{}
@@ -1178,6 +1230,164 @@ func (f *FGA) syncResources(ctx context.Context, resources Resources) error {
 	return f.updateTuples(ctx, writes, deletions)
 }
 
+// syncGroups replaces the members of the given groups (keyed by group name) in OpenFGA.
+// The synchronized groups are tagged with an "ldap" relation so that the members of the groups which later
+// disappear from the list are removed too. Groups which were never listed are left untouched.
+func (f *FGA) syncGroups(ctx context.Context, groups map[string][]string) error {
+	serverObjectStr := ObjectServer().String()
+
+	syncedGroupStrs, err := f.readTupleObjects(ctx, serverObjectStr, relationLDAP, string(ObjectTypeGroup)+":")
+	if err != nil {
+		return err
+	}
+
+	localGroups := groupSyncTargets(groups, syncedGroupStrs)
+
+	var writes []client.ClientTupleKey
+	var deletions []client.ClientTupleKeyWithoutCondition
+
+	for groupObjectStr, localUserStrs := range localGroups {
+		remoteUserStrs, err := f.groupMembers(ctx, groupObjectStr)
+		if err != nil {
+			return err
+		}
+
+		groupWrites, groupDeletions := diffGroupMembers(groupObjectStr, remoteUserStrs, localUserStrs)
+		writes = append(writes, groupWrites...)
+		deletions = append(deletions, groupDeletions...)
+
+		synced := slices.Contains(syncedGroupStrs, groupObjectStr)
+		if localUserStrs != nil && !synced {
+			writes = append(writes, client.ClientTupleKey{
+				User:     serverObjectStr,
+				Relation: relationLDAP,
+				Object:   groupObjectStr,
+			})
+		} else if localUserStrs == nil && synced {
+			deletions = append(deletions, client.ClientTupleKeyWithoutCondition{
+				User:     serverObjectStr,
+				Relation: relationLDAP,
+				Object:   groupObjectStr,
+			})
+		}
+	}
+
+	return f.updateTuples(ctx, writes, deletions)
+}
+
+// groupSyncTargets returns the users expected in each group object to synchronize, keyed by group object.
+// Previously synchronized groups which aren't listed anymore are returned with no users (nil).
+func groupSyncTargets(groups map[string][]string, syncedGroupStrs []string) map[string][]string {
+	localGroups := make(map[string][]string, len(groups))
+	for groupName, members := range groups {
+		localUserStrs := make([]string, 0, len(members))
+		for _, member := range members {
+			localUserStrs = append(localUserStrs, ObjectUser(member).String())
+		}
+
+		localGroups[ObjectGroup(groupName).String()] = localUserStrs
+	}
+
+	for _, syncedGroupStr := range syncedGroupStrs {
+		_, ok := localGroups[syncedGroupStr]
+		if !ok {
+			localGroups[syncedGroupStr] = nil
+		}
+	}
+
+	return localGroups
+}
+
+// diffGroupMembers returns the tuples to write and delete for the members of the group to go from the remote
+// users to the local ones.
+func diffGroupMembers(groupObjectStr string, remoteUserStrs []string, localUserStrs []string) ([]client.ClientTupleKey, []client.ClientTupleKeyWithoutCondition) {
+	var writes []client.ClientTupleKey
+	var deletions []client.ClientTupleKeyWithoutCondition
+
+	localUserStrs = slices.Clone(localUserStrs)
+	slices.Sort(localUserStrs)
+	localUserStrs = slices.Compact(localUserStrs)
+
+	for _, localUserStr := range localUserStrs {
+		if !slices.Contains(remoteUserStrs, localUserStr) {
+			writes = append(writes, client.ClientTupleKey{
+				User:     localUserStr,
+				Relation: relationMember,
+				Object:   groupObjectStr,
+			})
+		}
+	}
+
+	for _, remoteUserStr := range remoteUserStrs {
+		if !slices.Contains(localUserStrs, remoteUserStr) {
+			deletions = append(deletions, client.ClientTupleKeyWithoutCondition{
+				User:     remoteUserStr,
+				Relation: relationMember,
+				Object:   groupObjectStr,
+			})
+		}
+	}
+
+	return writes, deletions
+}
+
+// groupMembers returns the users which are direct members of the group.
+func (f *FGA) groupMembers(ctx context.Context, groupObjectStr string) ([]string, error) {
+	relation := relationMember
+	userPrefix := string(ObjectTypeUser) + ":"
+
+	var members []string
+	err := f.readTuples(ctx, client.ClientReadRequest{Relation: &relation, Object: &groupObjectStr}, func(tuple openfga.Tuple) {
+		user := tuple.Key.GetUser()
+		if strings.HasPrefix(user, userPrefix) && user != userPrefix+"*" {
+			members = append(members, user)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read members of %q: %w", groupObjectStr, err)
+	}
+
+	return members, nil
+}
+
+// readTupleObjects returns the objects of the given type (in the "type:" form) to which the user has the relation.
+func (f *FGA) readTupleObjects(ctx context.Context, user string, relation string, objectType string) ([]string, error) {
+	var objects []string
+	err := f.readTuples(ctx, client.ClientReadRequest{User: &user, Relation: &relation, Object: &objectType}, func(tuple openfga.Tuple) {
+		objects = append(objects, tuple.Key.GetObject())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %q relations of %q: %w", relation, user, err)
+	}
+
+	return objects, nil
+}
+
+// readTuples calls the handler with all the tuples matching the request, following the continuation tokens.
+func (f *FGA) readTuples(ctx context.Context, req client.ClientReadRequest, handler func(tuple openfga.Tuple)) error {
+	var continuationToken string
+	for {
+		opts := client.ClientReadOptions{}
+		if continuationToken != "" {
+			opts.ContinuationToken = &continuationToken
+		}
+
+		resp, err := f.client.Read(ctx).Body(req).Options(opts).Execute()
+		if err != nil {
+			return err
+		}
+
+		for _, tuple := range resp.GetTuples() {
+			handler(tuple)
+		}
+
+		continuationToken = resp.GetContinuationToken()
+		if continuationToken == "" {
+			return nil
+		}
+	}
+}
+
 // GetInstanceAccess returns the list of entities who have access to the instance.
 func (f *FGA) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
 	// Get all the entries from OpenFGA.
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/openfga/go-sdk/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupSyncTargets(t *testing.T) {
+	groups := map[string][]string{
+		"admins": {"alice@example.com", "bob@example.com"},
+		"empty":  {},
+	}
+
+	synced := []string{"group:admins", "group:deleted"}
+
+	expected := map[string][]string{
+		"group:admins":  {"user:alice@example.com", "user:bob@example.com"},
+		"group:empty":   {},
+		"group:deleted": nil,
+	}
+
+	assert.Equal(t, expected, groupSyncTargets(groups, synced))
+}
+
+func TestDiffGroupMembers(t *testing.T) {
+	tests := []struct {
+		name              string
+		remoteUserStrs    []string
+		localUserStrs     []string
+		expectedWrites    []client.ClientTupleKey
+		expectedDeletions []client.ClientTupleKeyWithoutCondition
+	}{
+		{
+			name:          "New group",
+			localUserStrs: []string{"user:bob", "user:alice", "user:bob"},
+			expectedWrites: []client.ClientTupleKey{
+				{User: "user:alice", Relation: "member", Object: "group:admins"},
+				{User: "user:bob", Relation: "member", Object: "group:admins"},
+			},
+		},
+		{
+			name:           "Changed members",
+			remoteUserStrs: []string{"user:alice", "user:carol"},
+			localUserStrs:  []string{"user:alice", "user:bob"},
+			expectedWrites: []client.ClientTupleKey{
+				{User: "user:bob", Relation: "member", Object: "group:admins"},
+			},
+			expectedDeletions: []client.ClientTupleKeyWithoutCondition{
+				{User: "user:carol", Relation: "member", Object: "group:admins"},
+			},
+		},
+		{
+			name:           "Removed group",
+			remoteUserStrs: []string{"user:alice", "user:bob"},
+			expectedDeletions: []client.ClientTupleKeyWithoutCondition{
+				{User: "user:alice", Relation: "member", Object: "group:admins"},
+				{User: "user:bob", Relation: "member", Object: "group:admins"},
+			},
+		},
+		{
+			name:           "Unchanged",
+			remoteUserStrs: []string{"user:alice"},
+			localUserStrs:  []string{"user:alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes, deletions := diffGroupMembers("group:admins", tt.remoteUserStrs, tt.localUserStrs)
+			assert.Equal(t, tt.expectedWrites, writes)
+			assert.Equal(t, tt.expectedDeletions, deletions)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"path"
 	"slices"
 
 	"github.com/lxc/incus/v6/internal/server/certificate"
@@ -13,6 +14,9 @@ import (
 	"github.com/lxc/incus/v6/shared/util"
 )
 
+// tlsAuthenticationMethods are the authentication methods tied to a trusted certificate and handled by the TLS driver.
+var tlsAuthenticationMethods = []string{api.AuthenticationMethodTLS, api.AuthenticationMethodToken, api.AuthenticationMethodSSH}
+
 // TLS represents a TLS authorizer.
 type TLS struct {
 	commonAuthorizer
@@ -40,14 +44,14 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 	}
 
 	authenticationProtocol := details.authenticationProtocol()
-	if authenticationProtocol != api.AuthenticationMethodTLS {
+	if !slices.Contains(tlsAuthenticationMethods, authenticationProtocol) {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Return nil. If the server has been configured with an authentication method but no associated authorization driver,
 		// the default is to give these authenticated users admin privileges.
 		return nil
 	}
 
-	certType, isNotRestricted, projectNames, err := t.certificateDetails(details.username())
+	certType, isNotRestricted, projectNames, err := t.identityDetails(authenticationProtocol, details.username())
 	if err != nil {
 		return err
 	}
@@ -85,6 +89,10 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 	// Check project level permissions against the certificates project list.
 	projectName := object.Project()
 	if slices.Contains(projectNames, projectName) {
+		if object.Type() == ObjectTypeInstance && !t.instanceAllowed(authenticationProtocol, details.username(), object) {
+			return api.StatusErrorf(http.StatusForbidden, "Certificate is restricted to other instances")
+		}
+
 		return nil
 	}
 
@@ -114,14 +122,14 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	}
 
 	authenticationProtocol := details.authenticationProtocol()
-	if authenticationProtocol != api.AuthenticationMethodTLS {
+	if !slices.Contains(tlsAuthenticationMethods, authenticationProtocol) {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
 		// Allow all. If the server has been configured with an authentication method but no associated authorization driver,
 		// the default is to give these authenticated users admin privileges.
 		return allowFunc(true), nil
 	}
 
-	certType, isNotRestricted, projectNames, err := t.certificateDetails(details.username())
+	certType, isNotRestricted, projectNames, err := t.identityDetails(authenticationProtocol, details.username())
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +170,7 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	return func(object Object) bool {
 		// Allow if the project is in the allowed set.
 		if slices.Contains(projectNames, object.Project()) {
-			return true
+			return objectType != ObjectTypeInstance || t.instanceAllowed(authenticationProtocol, details.username(), object)
 		}
 
 		// Also allow read-only access to inherited resources.
@@ -182,6 +190,99 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 	}, nil
 }
 
+// instanceAllowed returns whether the instance object matches the instance name patterns the certificate or
+// SSH key is restricted to, if any.
+func (t *TLS) instanceAllowed(protocol string, username string, object Object) bool {
+	elements := object.Elements()
+	if len(elements) == 0 {
+		return false
+	}
+
+	return instanceNameAllowed(t.certificates, protocol, username, elements[0])
+}
+
+// InstanceNameAllowed returns whether the instance name matches the instance name patterns the certificate or
+// SSH key used for the request is restricted to, if any.
+// Creating and renaming instances is authorized at the project level, so the new name has to be checked separately.
+func InstanceNameAllowed(r *http.Request, certificates *certificate.Cache, name string) bool {
+	details, err := (&commonAuthorizer{}).requestDetails(r)
+	if err != nil {
+		return false
+	}
+
+	if details.isInternalOrUnix() {
+		return true
+	}
+
+	return instanceNameAllowed(certificates, details.authenticationProtocol(), details.username(), name)
+}
+
+// instanceNameAllowed returns whether the instance name matches the instance name patterns the certificate or
+// SSH key is restricted to, if any.
+func instanceNameAllowed(certificates *certificate.Cache, protocol string, username string, name string) bool {
+	// Only certificates and SSH keys can be restricted to instances.
+	if protocol != api.AuthenticationMethodTLS && protocol != api.AuthenticationMethodSSH {
+		return true
+	}
+
+	patterns, ok := certificates.GetInstances(username)
+	if !ok {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		match, _ := path.Match(pattern, name)
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identityDetails returns the details of the certificate, API token or SSH key used to authenticate, depending on the protocol.
+func (t *TLS) identityDetails(protocol string, username string) (certificate.Type, bool, []string, error) {
+	switch protocol {
+	case api.AuthenticationMethodToken:
+		return t.tokenDetails(username)
+	case api.AuthenticationMethodSSH:
+		return t.sshKeyDetails(username)
+	}
+
+	return t.certificateDetails(username)
+}
+
+// tokenDetails returns the same details as certificateDetails for the API token with the given name.
+// API tokens are handled as client certificates.
+func (t *TLS) tokenDetails(name string) (certificate.Type, bool, []string, error) {
+	token, ok := t.certificates.GetTokenByName(name)
+	if !ok || token.Expired() {
+		return -1, false, nil, api.StatusErrorf(http.StatusForbidden, "API token not found")
+	}
+
+	if !token.Restricted {
+		return certificate.TypeClient, true, nil, nil
+	}
+
+	return certificate.TypeClient, false, token.Projects, nil
+}
+
+// sshKeyDetails returns the same details as certificateDetails for the SSH public key with the given fingerprint.
+// SSH keys are handled as client certificates.
+func (t *TLS) sshKeyDetails(fingerprint string) (certificate.Type, bool, []string, error) {
+	_, ok := t.certificates.GetSSHKey(fingerprint)
+	if !ok {
+		return -1, false, nil, api.StatusErrorf(http.StatusForbidden, "SSH key not found")
+	}
+
+	projectNames, ok := t.certificates.GetProjects()[fingerprint]
+	if !ok {
+		return certificate.TypeClient, true, nil, nil
+	}
+
+	return certificate.TypeClient, false, projectNames, nil
+}
+
 // certificateDetails returns the certificate type, a boolean indicating if the certificate is *not* restricted, a slice of
 // project names for this certificate, or an error if the certificate could not be found.
 func (t *TLS) certificateDetails(fingerprint string) (certificate.Type, bool, []string, error) {
@@ -211,6 +312,17 @@ func (t *TLS) certificateDetails(fingerprint string) (certificate.Type, bool, []
 		return certificate.TypeMetrics, false, projectNames, nil
 	}
 
+	// Clients with a certificate issued by a CA of the trust store are identified by the CA.
+	_, ok = t.certificates.GetCA(fingerprint)
+	if ok {
+		projectNames, ok := projects[fingerprint]
+		if !ok {
+			return certificate.TypeClient, true, nil, nil
+		}
+
+		return certificate.TypeClient, false, projectNames, nil
+	}
+
 	// If we're in a CA environment, it's possible for a certificate to be trusted despite not being present in the trust store.
 	// We rely on the validation of the certificate (and its potential revocation) having been done in CheckTrustState.
 	if util.PathExists(internalUtil.VarPath("server.ca")) {
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+func TestTLS_InstanceRestrictions(t *testing.T) {
+	cache := &certificate.Cache{}
+	cache.SetCertificatesAndProjects(map[certificate.Type]map[string]x509.Certificate{
+		certificate.TypeClient: {"ci": {}, "other": {}},
+	}, map[string][]string{
+		"ci":    {"default"},
+		"other": {"default"},
+	})
+
+	cache.SetInstances(map[string][]string{"ci": {"ci-*", "build"}})
+
+	authorizer, err := LoadAuthorizer(context.Background(), DriverTLS, logger.Log, cache)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		fingerprint string
+		instance    string
+		allowed     bool
+	}{
+		{"ci", "ci-1", true},
+		{"ci", "build", true},
+		{"ci", "prod", false},
+		{"other", "prod", true},
+	} {
+		r := httptest.NewRequest("GET", "/1.0/instances/"+tt.instance, nil)
+		ctx := context.WithValue(r.Context(), request.CtxUsername, tt.fingerprint)
+		ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodTLS)
+		r = r.WithContext(ctx)
+
+		err := authorizer.CheckPermission(context.Background(), r, ObjectInstance("default", tt.instance), EntitlementCanEdit)
+		assert.Equal(t, tt.allowed, err == nil, "%s on %s", tt.fingerprint, tt.instance)
+
+		// Other object types aren't affected by the instance restrictions.
+		err = authorizer.CheckPermission(context.Background(), r, ObjectProfile("default", "default"), EntitlementCanEdit)
+		assert.NoError(t, err)
+
+		checker, err := authorizer.GetPermissionChecker(context.Background(), r, EntitlementCanView, ObjectTypeInstance)
+		require.NoError(t, err)
+		assert.Equal(t, tt.allowed, checker(ObjectInstance("default", tt.instance)), "%s on %s", tt.fingerprint, tt.instance)
+	}
+}
+
+func TestInstanceNameAllowed(t *testing.T) {
+	cache := &certificate.Cache{}
+	cache.SetInstances(map[string][]string{"ci": {"ci-*", "build"}})
+
+	for _, protocol := range []string{api.AuthenticationMethodTLS, api.AuthenticationMethodSSH} {
+		for _, tt := range []struct {
+			method   string
+			url      string
+			username string
+			name     string
+			allowed  bool
+		}{
+			// Creating an instance.
+			{"POST", "/1.0/instances", "ci", "ci-1", true},
+			{"POST", "/1.0/instances", "ci", "build", true},
+			{"POST", "/1.0/instances", "ci", "prod", false},
+			{"POST", "/1.0/instances", "ci", "", false},
+			{"POST", "/1.0/instances", "other", "prod", true},
+			{"POST", "/1.0/instances", "other", "", true},
+
+			// Renaming an instance.
+			{"POST", "/1.0/instances/ci-1", "ci", "ci-2", true},
+			{"POST", "/1.0/instances/ci-1", "ci", "prod", false},
+			{"POST", "/1.0/instances/prod", "other", "prod-2", true},
+		} {
+			r := httptest.NewRequest(tt.method, tt.url, nil)
+			ctx := context.WithValue(r.Context(), request.CtxUsername, tt.username)
+			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+			r = r.WithContext(ctx)
+
+			assert.Equal(t, tt.allowed, InstanceNameAllowed(r, cache, tt.name), "%s %s as %s (%s) to %q", tt.method, tt.url, tt.username, protocol, tt.name)
+		}
+	}
+
+	// Other authentication methods aren't restricted to instances.
+	r := httptest.NewRequest("POST", "/1.0/instances", nil)
+	ctx := context.WithValue(r.Context(), request.CtxUsername, "ci")
+	ctx = context.WithValue(ctx, request.CtxProtocol, api.AuthenticationMethodOIDC)
+	r = r.WithContext(ctx)
+
+	assert.True(t, InstanceNameAllowed(r, cache, "prod"))
+}
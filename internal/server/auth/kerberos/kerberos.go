@@ -0,0 +1,112 @@
+package kerberos
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jcmturner/gokrb5/v8/iana/msgtype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+)
+
+// Verifier holds all information needed to verify SPNEGO (Kerberos) authentication requests.
+type Verifier struct {
+	keytab *keytab.Keytab
+	realms []string
+}
+
+// AuthError represents an authentication error.
+type AuthError struct {
+	Err error
+}
+
+func (e AuthError) Error() string {
+	return fmt.Sprintf("Failed to authenticate: %s", e.Err.Error())
+}
+
+func (e AuthError) Unwrap() error {
+	return e.Err
+}
+
+// Auth validates the SPNEGO token of the request and returns the authenticated principal as "user@REALM".
+func (v *Verifier) Auth(r *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Negotiate ")
+	if !ok {
+		return "", &AuthError{errors.New("Bad authorization token, expected a Negotiate token")}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", &AuthError{fmt.Errorf("Invalid Negotiate token: %w", err)}
+	}
+
+	// Extract the Kerberos AP-REQ, either wrapped in SPNEGO or sent as a raw Kerberos token.
+	var krb5Token spnego.KRB5Token
+
+	var spnegoToken spnego.SPNEGOToken
+	err = spnegoToken.Unmarshal(data)
+	if err == nil {
+		if !spnegoToken.Init {
+			return "", &AuthError{errors.New("Unexpected SPNEGO token, expected an initial token")}
+		}
+
+		data = spnegoToken.NegTokenInit.MechTokenBytes
+	}
+
+	err = krb5Token.Unmarshal(data)
+	if err != nil {
+		return "", &AuthError{fmt.Errorf("Invalid Kerberos token: %w", err)}
+	}
+
+	if krb5Token.APReq.MsgType != msgtype.KRB_AP_REQ {
+		return "", &AuthError{errors.New("Kerberos token doesn't contain an authentication request")}
+	}
+
+	settings := []func(*service.Settings){}
+	clientAddress, err := types.GetHostAddress(r.RemoteAddr)
+	if err == nil {
+		settings = append(settings, service.ClientAddress(clientAddress))
+	}
+
+	ok, creds, err := service.VerifyAPREQ(&krb5Token.APReq, service.NewSettings(v.keytab, settings...))
+	if err != nil {
+		return "", &AuthError{err}
+	}
+
+	if !ok || creds == nil {
+		return "", &AuthError{errors.New("Kerberos authentication request isn't valid")}
+	}
+
+	if len(v.realms) > 0 && !slices.Contains(v.realms, creds.Domain()) {
+		return "", &AuthError{fmt.Errorf("Kerberos realm %q isn't allowed", creds.Domain())}
+	}
+
+	return fmt.Sprintf("%s@%s", creds.UserName(), creds.Domain()), nil
+}
+
+// IsRequest checks if the request is using Kerberos authentication.
+func (v *Verifier) IsRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Negotiate ")
+}
+
+// WriteHeaders writes the headers asking the client to authenticate through SPNEGO.
+func (v *Verifier) WriteHeaders(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", "Negotiate")
+}
+
+// NewVerifier returns a Verifier using the service keys from the keytab.
+// If realms isn't empty, only principals of those realms are accepted.
+func NewVerifier(keytabPath string, realms []string) (*Verifier, error) {
+	kt, err := keytab.Load(keytabPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load Kerberos keytab %q: %w", keytabPath, err)
+	}
+
+	return &Verifier{keytab: kt, realms: realms}, nil
+}
@@ -0,0 +1,190 @@
+package kerberos
+
+import (
+	"encoding/base64"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/nametype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/jcmturner/gokrb5/v8/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKeytab writes a keytab holding the key of the HTTP/incus.example.com service principal.
+func writeKeytab(t *testing.T) (string, *keytab.Keytab) {
+	kt := keytab.New()
+	err := kt.AddEntry("HTTP/incus.example.com", "EXAMPLE.COM", "secret", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96)
+	require.NoError(t, err)
+
+	data, err := kt.Marshal()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "incus.keytab")
+	err = os.WriteFile(path, data, 0o600)
+	require.NoError(t, err)
+
+	return path, kt
+}
+
+// newRequest returns a request carrying a SPNEGO token for the user of the realm, as issued by the KDC of the realm.
+func newRequest(t *testing.T, kt *keytab.Keytab, user string, realm string) *http.Request {
+	now := time.Now().UTC()
+	cname := types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, user)
+	sname := types.NewPrincipalName(nametype.KRB_NT_SRV_HST, "HTTP/incus.example.com")
+
+	tkt, sessionKey, err := messages.NewTicket(cname, realm, sname, "EXAMPLE.COM", types.NewKrbFlags(), kt, etypeID.AES256_CTS_HMAC_SHA1_96, 1, now, now, now.Add(time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+
+	cl := client.NewWithPassword(user, realm, "password", config.New())
+	negTokenInit, err := spnego.NewNegTokenInitKRB5(cl, tkt, sessionKey)
+	require.NoError(t, err)
+
+	token := spnego.SPNEGOToken{Init: true, NegTokenInit: negTokenInit}
+	data, err := token.Marshal()
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "https://incus.example.com:8443/1.0", nil)
+	require.NoError(t, err)
+
+	r.RemoteAddr = "192.0.2.10:51234"
+	r.Header.Set("Authorization", "Negotiate "+base64.StdEncoding.EncodeToString(data))
+
+	return r
+}
+
+func TestNewVerifier(t *testing.T) {
+	path, _ := writeKeytab(t)
+
+	_, err := NewVerifier(path, nil)
+	assert.NoError(t, err)
+
+	// A missing keytab is reported as such.
+	_, err = NewVerifier(filepath.Join(t.TempDir(), "missing.keytab"), nil)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	// An invalid keytab is reported as any other error.
+	invalidPath := filepath.Join(t.TempDir(), "invalid.keytab")
+	err = os.WriteFile(invalidPath, []byte("invalid"), 0o600)
+	require.NoError(t, err)
+
+	_, err = NewVerifier(invalidPath, nil)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestVerifierAuth(t *testing.T) {
+	path, kt := writeKeytab(t)
+
+	tests := []struct {
+		name         string
+		realms       []string
+		user         string
+		realm        string
+		expectedUser string
+		expectedErr  bool
+	}{
+		{
+			name:         "Any realm",
+			user:         "alice",
+			realm:        "EXAMPLE.COM",
+			expectedUser: "alice@EXAMPLE.COM",
+		},
+		{
+			name:         "Allowed realm",
+			realms:       []string{"OTHER.COM", "EXAMPLE.COM"},
+			user:         "bob",
+			realm:        "EXAMPLE.COM",
+			expectedUser: "bob@EXAMPLE.COM",
+		},
+		{
+			name:        "Disallowed realm",
+			realms:      []string{"EXAMPLE.COM"},
+			user:        "carol",
+			realm:       "OTHER.COM",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewVerifier(path, tt.realms)
+			require.NoError(t, err)
+
+			r := newRequest(t, kt, tt.user, tt.realm)
+			assert.True(t, verifier.IsRequest(r))
+
+			user, err := verifier.Auth(r)
+			if tt.expectedErr {
+				var authErr *AuthError
+				assert.True(t, errors.As(err, &authErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUser, user)
+		})
+	}
+}
+
+func TestVerifierAuthInvalid(t *testing.T) {
+	path, _ := writeKeytab(t)
+
+	verifier, err := NewVerifier(path, nil)
+	require.NoError(t, err)
+
+	// A ticket issued for a service key the keytab doesn't hold.
+	otherKeytab := keytab.New()
+	err = otherKeytab.AddEntry("HTTP/incus.example.com", "EXAMPLE.COM", "other", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96)
+	require.NoError(t, err)
+
+	forged := newRequest(t, otherKeytab, "mallory", "EXAMPLE.COM")
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{
+			name:          "Not a Negotiate token",
+			authorization: "Bearer foo",
+		},
+		{
+			name:          "Invalid encoding",
+			authorization: "Negotiate !!!",
+		},
+		{
+			name:          "Invalid token",
+			authorization: "Negotiate " + base64.StdEncoding.EncodeToString([]byte("invalid")),
+		},
+		{
+			name:          "Wrong service key",
+			authorization: forged.Header.Get("Authorization"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://incus.example.com:8443/1.0", nil)
+			require.NoError(t, err)
+
+			r.RemoteAddr = "192.0.2.10:51234"
+			r.Header.Set("Authorization", tt.authorization)
+
+			_, err = verifier.Auth(r)
+
+			var authErr *AuthError
+			assert.True(t, errors.As(err, &authErr))
+		})
+	}
+}
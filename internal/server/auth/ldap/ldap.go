@@ -0,0 +1,242 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ldapTimeout is the maximum time spent on a single LDAP request.
+const ldapTimeout = 30 * time.Second
+
+// ldapPageSize is the number of entries requested per page when listing groups and users.
+const ldapPageSize = 500
+
+// Config holds all information needed to read group memberships from an LDAP server.
+type Config struct {
+	// URL of the LDAP server (ldap:// or ldaps://).
+	URL string
+
+	// DN and password used to bind to the server (anonymous if empty).
+	BindDN       string
+	BindPassword string
+
+	// DN under which to look for groups and users.
+	BaseDN string
+
+	// Filter selecting the groups to synchronize.
+	GroupFilter string
+
+	// Attribute holding the name of a group.
+	GroupAttribute string
+
+	// Attribute of the user entries holding the Incus username.
+	UserAttribute string
+}
+
+// group is an LDAP group along with its direct members.
+type group struct {
+	// Name of the group, from the group attribute.
+	name string
+
+	// Normalized DN of the group.
+	dn string
+
+	// Normalized DNs of the members (from the member and uniqueMember attributes).
+	memberDNs []string
+
+	// User IDs of the members (from the memberUid attribute of POSIX groups).
+	memberUIDs []string
+}
+
+// GetGroups returns the members of the groups matching the filter, keyed by group name.
+// Members are identified by the value of the user attribute of their entry.
+//
+// Members are read from the member and uniqueMember attributes (DNs) as well as from the memberUid attribute
+// (user IDs of POSIX groups). The users are listed with a single search under the base DN, members outside of
+// it are looked up individually. Members of nested groups are included if the nested group also matches the filter.
+func GetGroups(ctx context.Context, config Config) (map[string][]string, error) {
+	conn, err := ldap.DialURL(config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to LDAP server: %w", err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	conn.SetTimeout(ldapTimeout)
+
+	if config.BindDN != "" {
+		err = conn.Bind(config.BindDN, config.BindPassword)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to bind to LDAP server: %w", err)
+		}
+	}
+
+	// List the groups.
+	req := ldap.NewSearchRequest(config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, config.GroupFilter, []string{config.GroupAttribute, "member", "uniqueMember", "memberUid"}, nil)
+	res, err := conn.SearchWithPaging(req, ldapPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search LDAP groups: %w", err)
+	}
+
+	groups := make([]group, 0, len(res.Entries))
+	for _, entry := range res.Entries {
+		name := entry.GetAttributeValue(config.GroupAttribute)
+		if name == "" {
+			continue
+		}
+
+		g := group{
+			name:       name,
+			dn:         normalizeDN(entry.DN),
+			memberUIDs: entry.GetAttributeValues("memberUid"),
+		}
+
+		for _, memberDN := range append(entry.GetAttributeValues("member"), entry.GetAttributeValues("uniqueMember")...) {
+			g.memberDNs = append(g.memberDNs, normalizeDN(memberDN))
+		}
+
+		groups = append(groups, g)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// List all the users at once rather than looking up each member.
+	filter := fmt.Sprintf("(%s=*)", ldap.EscapeFilter(config.UserAttribute))
+	req = ldap.NewSearchRequest(config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, []string{config.UserAttribute, "uid"}, nil)
+	res, err = conn.SearchWithPaging(req, ldapPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search LDAP users: %w", err)
+	}
+
+	usernames := map[string]string{}
+	uids := map[string]string{}
+	for _, entry := range res.Entries {
+		username := entry.GetAttributeValue(config.UserAttribute)
+		usernames[normalizeDN(entry.DN)] = username
+
+		uid := entry.GetAttributeValue("uid")
+		if uid != "" {
+			uids[uid] = username
+		}
+	}
+
+	// Look up the members which weren't found under the base DN.
+	groupDNs := map[string]bool{}
+	for _, g := range groups {
+		groupDNs[g.dn] = true
+	}
+
+	for _, g := range groups {
+		for _, memberDN := range g.memberDNs {
+			_, ok := usernames[memberDN]
+			if ok || groupDNs[memberDN] {
+				continue
+			}
+
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			usernames[memberDN], err = getUsername(conn, memberDN, config.UserAttribute)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resolveGroups(groups, usernames, uids), nil
+}
+
+// resolveGroups returns the usernames of the members of each group, including those of nested groups.
+// Members whose username is unknown are skipped.
+func resolveGroups(groups []group, usernames map[string]string, uids map[string]string) map[string][]string {
+	groupsByDN := make(map[string]*group, len(groups))
+	for i := range groups {
+		groupsByDN[groups[i].dn] = &groups[i]
+	}
+
+	var addMembers func(g *group, members map[string]bool, visited map[string]bool)
+	addMembers = func(g *group, members map[string]bool, visited map[string]bool) {
+		// Guard against membership loops.
+		if visited[g.dn] {
+			return
+		}
+
+		visited[g.dn] = true
+
+		for _, memberDN := range g.memberDNs {
+			nested, ok := groupsByDN[memberDN]
+			if ok {
+				addMembers(nested, members, visited)
+				continue
+			}
+
+			username := usernames[memberDN]
+			if username != "" {
+				members[username] = true
+			}
+		}
+
+		for _, uid := range g.memberUIDs {
+			username := uids[uid]
+			if username != "" {
+				members[username] = true
+			}
+		}
+	}
+
+	result := make(map[string][]string, len(groups))
+	for i := range groups {
+		members := map[string]bool{}
+		addMembers(&groups[i], members, map[string]bool{})
+
+		_, ok := result[groups[i].name]
+		if !ok {
+			result[groups[i].name] = []string{}
+		}
+
+		result[groups[i].name] = append(result[groups[i].name], slices.Sorted(maps.Keys(members))...)
+	}
+
+	return result
+}
+
+// normalizeDN returns the DN in a form suitable for comparisons.
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+
+	return strings.ToLower(parsed.String())
+}
+
+// getUsername returns the value of the user attribute of the entry, or an empty string if the entry or attribute doesn't exist.
+func getUsername(conn *ldap.Conn, dn string, attribute string) (string, error) {
+	req := ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false, "(objectClass=*)", []string{attribute}, nil)
+	res, err := conn.Search(req)
+	if err != nil {
+		var ldapErr *ldap.Error
+		if errors.As(err, &ldapErr) && ldapErr.ResultCode == ldap.LDAPResultNoSuchObject {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("Failed to look up LDAP entry %q: %w", dn, err)
+	}
+
+	if len(res.Entries) == 0 {
+		return "", nil
+	}
+
+	return res.Entries[0].GetAttributeValue(attribute), nil
+}
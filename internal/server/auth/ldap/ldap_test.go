@@ -0,0 +1,69 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveGroups(t *testing.T) {
+	usernames := map[string]string{
+		"uid=alice,ou=people,dc=example,dc=com": "alice@example.com",
+		"uid=bob,ou=people,dc=example,dc=com":   "bob@example.com",
+		"uid=carol,ou=people,dc=example,dc=com": "carol@example.com",
+		"cn=service,ou=apps,dc=example,dc=com":  "",
+	}
+
+	uids := map[string]string{
+		"alice": "alice@example.com",
+		"dave":  "dave@example.com",
+	}
+
+	groups := []group{
+		{
+			name:      "admins",
+			dn:        "cn=admins,ou=groups,dc=example,dc=com",
+			memberDNs: []string{"uid=alice,ou=people,dc=example,dc=com", "cn=service,ou=apps,dc=example,dc=com"},
+		},
+		{
+			name:      "developers",
+			dn:        "cn=developers,ou=groups,dc=example,dc=com",
+			memberDNs: []string{"uid=bob,ou=people,dc=example,dc=com", "cn=admins,ou=groups,dc=example,dc=com", "uid=unknown,ou=people,dc=example,dc=com"},
+		},
+		{
+			name:       "posix",
+			dn:         "cn=posix,ou=groups,dc=example,dc=com",
+			memberUIDs: []string{"alice", "dave", "unknown"},
+		},
+		{
+			name:      "loop-a",
+			dn:        "cn=loop-a,ou=groups,dc=example,dc=com",
+			memberDNs: []string{"cn=loop-b,ou=groups,dc=example,dc=com", "uid=carol,ou=people,dc=example,dc=com"},
+		},
+		{
+			name:      "loop-b",
+			dn:        "cn=loop-b,ou=groups,dc=example,dc=com",
+			memberDNs: []string{"cn=loop-a,ou=groups,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+		},
+		{
+			name: "empty",
+			dn:   "cn=empty,ou=groups,dc=example,dc=com",
+		},
+	}
+
+	expected := map[string][]string{
+		"admins":     {"alice@example.com"},
+		"developers": {"alice@example.com", "bob@example.com"},
+		"posix":      {"alice@example.com", "dave@example.com"},
+		"loop-a":     {"bob@example.com", "carol@example.com"},
+		"loop-b":     {"bob@example.com", "carol@example.com"},
+		"empty":      {},
+	}
+
+	assert.Equal(t, expected, resolveGroups(groups, usernames, uids))
+}
+
+func TestNormalizeDN(t *testing.T) {
+	assert.Equal(t, "uid=alice,ou=people,dc=example,dc=com", normalizeDN("UID=Alice, OU=People,DC=example,DC=com"))
+	assert.Equal(t, "not a dn", normalizeDN("Not a DN"))
+}
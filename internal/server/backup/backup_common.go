@@ -1,19 +1,12 @@
 package backup
 
 import (
-	"context"
-	"crypto/tls"
-	"fmt"
-	"net/http"
-	"net/url"
+	"errors"
+	"io"
 	"os"
 	"time"
 
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
-
 	"github.com/lxc/incus/v6/internal/server/state"
-	"github.com/lxc/incus/v6/shared/api"
 )
 
 // WorkingDirPrefix is used when temporary working directories are needed.
@@ -28,6 +21,7 @@ type CommonBackup struct {
 	expiryDate           time.Time
 	optimizedStorage     bool
 	compressionAlgorithm string
+	throttle             int64
 }
 
 // Name returns the name of the backup.
@@ -45,6 +39,16 @@ func (b *CommonBackup) SetCompressionAlgorithm(compression string) {
 	b.compressionAlgorithm = compression
 }
 
+// Throttle returns the rate limit (in bytes per second) applied to the backup, 0 meaning unlimited.
+func (b *CommonBackup) Throttle() int64 {
+	return b.throttle
+}
+
+// SetThrottle sets the rate limit (in bytes per second) applied to the backup.
+func (b *CommonBackup) SetThrottle(limit int64) {
+	b.throttle = limit
+}
+
 // OptimizedStorage returns whether the backup is to be performed using
 // optimization supported by the storage driver.
 func (b *CommonBackup) OptimizedStorage() bool {
@@ -52,51 +56,111 @@ func (b *CommonBackup) OptimizedStorage() bool {
 }
 
 // upload handles backup uploads.
-func (b *CommonBackup) upload(filePath string, req *api.BackupTarget) error {
-	if req.Protocol != "s3" {
-		return fmt.Errorf("Unsupported backup target protocol %q", req.Protocol)
+func (b *CommonBackup) upload(filePath string, config map[string]string, targetPath string) error {
+	// Upload the object.
+	tr, err := os.Open(filePath)
+	if err != nil {
+		return err
 	}
 
-	// Set up an S3 client.
-	uri, err := url.Parse(req.URL)
+	defer tr.Close()
+
+	upload, err := newTargetUpload(config, targetPath)
 	if err != nil {
 		return err
 	}
 
-	creds := credentials.NewStaticV4(req.AccessKey, req.SecretKey, "")
+	return upload(NewThrottledReader(tr, b.throttle))
+}
 
-	ts := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    30 * time.Second,
-		DisableCompression: true,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-			MinVersion:         tls.VersionTLS12,
-		},
-	}
+// targetPartSize is the size of the parts used when streaming a backup to its target.
+// It bounds the amount of memory used by the upload, allowing for backups of up to 640GiB.
+const targetPartSize = 64 * 1024 * 1024
+
+// TargetWriter streams the data written to it to a backup target.
+//
+// The data goes through an in-memory pipe to an upload running in the background, so nothing is stored on
+// disk. Writes block until the upload consumed the data, which makes the target's speed the limiting factor.
+// A TargetWriter must always be finalized with either Close or Abort so that the background upload ends.
+type TargetWriter struct {
+	// pipeWriter is the write end of the pipe read by the background upload.
+	pipeWriter *io.PipeWriter
+
+	// done receives the result of the background upload, it's nil once the upload was finalized.
+	done chan error
+}
 
-	client, err := minio.New(uri.Host, &minio.Options{
-		BucketLookup: minio.BucketLookupPath,
-		Creds:        creds,
-		Secure:       uri.Scheme == "https",
-		Transport:    ts,
-	})
+// NewTargetWriter starts uploading to targetPath on the backup target described by config and returns a
+// writer for the backup content.
+//
+// The config is the one of a server-side backup target (or the equivalent generated from a backup request)
+// and an error is returned if it doesn't describe a supported target. The upload is only complete once
+// Close returns without error.
+func NewTargetWriter(config map[string]string, targetPath string) (*TargetWriter, error) {
+	upload, err := newTargetUpload(config, targetPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Upload the object.
-	tr, err := os.Open(filePath)
-	if err != nil {
-		return err
+	pipeReader, pipeWriter := io.Pipe()
+
+	w := &TargetWriter{
+		pipeWriter: pipeWriter,
+		done:       make(chan error, 1),
 	}
 
-	defer tr.Close()
+	go func() {
+		err := upload(pipeReader)
 
-	_, err = client.PutObject(context.Background(), req.BucketName, req.Path, tr, -1, minio.PutObjectOptions{})
-	if err != nil {
-		return err
+		// Unblock the writer if the upload failed early.
+		_ = pipeReader.CloseWithError(err)
+
+		w.done <- err
+	}()
+
+	return w, nil
+}
+
+// Write sends data to the backup target.
+//
+// It blocks until the data was consumed by the upload and returns the upload error if the upload failed,
+// in which case the caller should stop writing and call Abort.
+func (w *TargetWriter) Write(p []byte) (int, error) {
+	return w.pipeWriter.Write(p)
+}
+
+// Close signals the end of the backup content and waits for the upload to complete.
+//
+// It returns the upload error, if any, in which case the object may not exist on the target.
+// Calling Close or Abort again once the writer was finalized does nothing.
+func (w *TargetWriter) Close() error {
+	if w.done == nil {
+		return nil
+	}
+
+	_ = w.pipeWriter.Close()
+	err := <-w.done
+	w.done = nil
+
+	return err
+}
+
+// Abort cancels the upload with the given error and waits for the upload to stop.
+//
+// S3 multipart uploads are aborted before any object is created and partial SSH uploads are removed,
+// WebDAV servers may however keep a partial file. Calling Close or Abort again once the writer was
+// finalized does nothing.
+func (w *TargetWriter) Abort(err error) {
+	if w.done == nil {
+		return
+	}
+
+	// A nil error would complete the upload rather than cancel it.
+	if err == nil {
+		err = errors.New("Backup upload aborted")
 	}
 
-	return nil
+	_ = w.pipeWriter.CloseWithError(err)
+	<-w.done
+	w.done = nil
 }
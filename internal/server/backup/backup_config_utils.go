@@ -89,8 +89,13 @@ func ParseConfigYamlFile(path string) (*config.Config, error) {
 		return nil, err
 	}
 
+	return ParseConfigYaml(data)
+}
+
+// ParseConfigYaml decodes the supplied YAML data into a Config.
+func ParseConfigYaml(data []byte) (*config.Config, error) {
 	backupConf := config.Config{}
-	err = yaml.Unmarshal(data, &backupConf)
+	err := yaml.Unmarshal(data, &backupConf)
 	if err != nil {
 		return nil, err
 	}
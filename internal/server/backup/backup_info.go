@@ -144,3 +144,21 @@ func GetInfo(r io.ReadSeeker, sysOS *sys.OS, outputPath string) (*Info, error) {
 
 	return &result, nil
 }
+
+// RemapVolume points a custom volume backup, including the configuration embedded in its index, to the
+// project, pool and volume name it is being restored as.
+func (i *Info) RemapVolume(projectName string, poolName string, volumeName string) {
+	i.Project = projectName
+	i.Pool = poolName
+	i.Name = volumeName
+
+	if i.Config == nil || i.Config.Volume == nil {
+		return
+	}
+
+	i.Config.Volume.Project = projectName
+	i.Config.Volume.Name = volumeName
+
+	// The location is set again by the target server when the volume is created.
+	i.Config.Volume.Location = ""
+}
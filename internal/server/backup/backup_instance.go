@@ -155,7 +155,7 @@ func (b *InstanceBackup) Render() *api.InstanceBackup {
 }
 
 // Upload pushes the backup to external storage.
-func (b *InstanceBackup) Upload(req *api.BackupTarget) error {
+func (b *InstanceBackup) Upload(config map[string]string, targetPath string) error {
 	backupPath := internalUtil.VarPath("backups", "instances", project.Instance(b.instance.Project().Name, b.name))
-	return b.upload(backupPath, req)
+	return b.upload(backupPath, config, targetPath)
 }
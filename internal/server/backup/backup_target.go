@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// TargetConfig returns the backup target config matching the connection details of an upload request.
+func TargetConfig(req *api.BackupTarget) map[string]string {
+	return map[string]string{
+		"protocol":      req.Protocol,
+		"url":           req.URL,
+		"s3.bucket":     req.BucketName,
+		"s3.access_key": req.AccessKey,
+		"s3.secret_key": req.SecretKey,
+	}
+}
+
+// ValidateTargetConfig validates the config of a server-side backup target.
+func ValidateTargetConfig(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		// gendoc:generate(entity=backup_target, group=common, key=protocol)
+		//
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: Upload protocol (`s3`, `webdav` or `ssh`)
+		"protocol": validate.IsOneOf("s3", "webdav", "ssh"),
+
+		// gendoc:generate(entity=backup_target, group=common, key=url)
+		// For `ssh`, use the `ssh://<host>[:<port>]/<path>` form.
+		// ---
+		//  type: string
+		//  required: yes
+		//  shortdesc: URL of the target server
+		"url": validate.IsRequestURL,
+
+		// gendoc:generate(entity=backup_target, group=common, key=projects)
+		// Server administrators can use the target from any project.
+		// ---
+		//  type: string
+		//  shortdesc: Comma-separated list of projects which can use the target
+		"projects": validate.Optional(validate.IsListOf(validate.IsAny)),
+	}
+
+	switch config["protocol"] {
+	case "s3":
+		// gendoc:generate(entity=backup_target, group=s3, key=s3.bucket)
+		//
+		// ---
+		//  type: string
+		//  condition: `s3` protocol
+		//  required: yes
+		//  shortdesc: Name of the bucket to upload to
+		rules["s3.bucket"] = validate.IsNotEmpty
+
+		// gendoc:generate(entity=backup_target, group=s3, key=s3.access_key)
+		//
+		// ---
+		//  type: string
+		//  condition: `s3` protocol
+		//  shortdesc: S3 access key
+		rules["s3.access_key"] = validate.IsAny
+
+		// gendoc:generate(entity=backup_target, group=s3, key=s3.secret_key)
+		//
+		// ---
+		//  type: string
+		//  condition: `s3` protocol
+		//  shortdesc: S3 secret key
+		rules["s3.secret_key"] = validate.IsAny
+	case "webdav":
+		// gendoc:generate(entity=backup_target, group=webdav, key=webdav.username)
+		//
+		// ---
+		//  type: string
+		//  condition: `webdav` protocol
+		//  shortdesc: User name for HTTP basic authentication
+		rules["webdav.username"] = validate.IsAny
+
+		// gendoc:generate(entity=backup_target, group=webdav, key=webdav.password)
+		//
+		// ---
+		//  type: string
+		//  condition: `webdav` protocol
+		//  shortdesc: Password for HTTP basic authentication
+		rules["webdav.password"] = validate.IsAny
+	case "ssh":
+		// gendoc:generate(entity=backup_target, group=ssh, key=ssh.user)
+		//
+		// ---
+		//  type: string
+		//  condition: `ssh` protocol
+		//  defaultdesc: `root`
+		//  shortdesc: User to log in as
+		rules["ssh.user"] = validate.IsAny
+
+		// gendoc:generate(entity=backup_target, group=ssh, key=ssh.private_key)
+		//
+		// ---
+		//  type: string
+		//  condition: `ssh` protocol
+		//  required: yes
+		//  shortdesc: PEM encoded private key used to log in
+		rules["ssh.private_key"] = func(value string) error {
+			_, err := ssh.ParsePrivateKey([]byte(value))
+			return err
+		}
+
+		// gendoc:generate(entity=backup_target, group=ssh, key=ssh.host_key)
+		// The key is in the `authorized_keys` format, for example as found in the server's `/etc/ssh/ssh_host_ed25519_key.pub`.
+		// ---
+		//  type: string
+		//  condition: `ssh` protocol
+		//  required: yes
+		//  shortdesc: Public host key of the target server
+		rules["ssh.host_key"] = func(value string) error {
+			_, _, _, _, err := ssh.ParseAuthorizedKey([]byte(value))
+			return err
+		}
+	}
+
+	for k, validator := range rules {
+		err := validator(config[k])
+		if err != nil {
+			return fmt.Errorf("Invalid value for backup target option %q: %w", k, err)
+		}
+	}
+
+	for k := range config {
+		_, ok := rules[k]
+		if !ok {
+			return fmt.Errorf("Invalid backup target option %q", k)
+		}
+	}
+
+	return nil
+}
+
+// newTargetUpload returns a function uploading the content of a reader to targetPath on the backup target.
+func newTargetUpload(config map[string]string, targetPath string) (func(r io.Reader) error, error) {
+	switch config["protocol"] {
+	case "s3":
+		return newS3TargetUpload(config, targetPath)
+	case "webdav":
+		return newWebDAVTargetUpload(config, targetPath)
+	case "ssh":
+		return newSSHTargetUpload(config, targetPath)
+	}
+
+	return nil, fmt.Errorf("Unsupported backup target protocol %q", config["protocol"])
+}
+
+// newS3TargetUpload returns an upload function for an S3 backup target.
+func newS3TargetUpload(config map[string]string, targetPath string) (func(r io.Reader) error, error) {
+	// Set up an S3 client.
+	uri, err := url.Parse(config["url"])
+	if err != nil {
+		return nil, err
+	}
+
+	creds := credentials.NewStaticV4(config["s3.access_key"], config["s3.secret_key"], "")
+
+	ts := &http.Transport{
+		MaxIdleConns:       10,
+		IdleConnTimeout:    30 * time.Second,
+		DisableCompression: true,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			MinVersion:         tls.VersionTLS12,
+		},
+	}
+
+	client, err := minio.New(uri.Host, &minio.Options{
+		BucketLookup: minio.BucketLookupPath,
+		Creds:        creds,
+		Secure:       uri.Scheme == "https",
+		Transport:    ts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r io.Reader) error {
+		_, err := client.PutObject(context.Background(), config["s3.bucket"], targetPath, r, -1, minio.PutObjectOptions{PartSize: targetPartSize})
+		return err
+	}, nil
+}
+
+// newWebDAVTargetUpload returns an upload function for a WebDAV backup target.
+func newWebDAVTargetUpload(config map[string]string, targetPath string) (func(r io.Reader) error, error) {
+	targetURL, err := url.JoinPath(config["url"], targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(r io.Reader) error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, targetURL, r)
+		if err != nil {
+			return err
+		}
+
+		if config["webdav.username"] != "" {
+			req.SetBasicAuth(config["webdav.username"], config["webdav.password"])
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("Failed uploading to WebDAV server: %s", resp.Status)
+		}
+
+		return nil
+	}, nil
+}
+
+// newSSHTargetUpload returns an upload function for an SSH (SFTP) backup target.
+// The connection is established right away so that connection errors are reported before any data is sent.
+func newSSHTargetUpload(config map[string]string, targetPath string) (func(r io.Reader) error, error) {
+	uri, err := url.Parse(config["url"])
+	if err != nil {
+		return nil, err
+	}
+
+	if uri.Scheme != "ssh" {
+		return nil, fmt.Errorf("Unsupported URL scheme %q for SSH backup target", uri.Scheme)
+	}
+
+	signer, err := ssh.ParsePrivateKey([]byte(config["ssh.private_key"]))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing SSH private key: %w", err)
+	}
+
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config["ssh.host_key"]))
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing SSH host key: %w", err)
+	}
+
+	user := config["ssh.user"]
+	if user == "" {
+		user = "root"
+	}
+
+	address := uri.Host
+	if uri.Port() == "" {
+		address = net.JoinHostPort(uri.Hostname(), "22")
+	}
+
+	conn, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+		Timeout:         30 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to SSH server %q: %w", address, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("Failed starting SFTP session: %w", err)
+	}
+
+	fullPath := path.Join(uri.Path, targetPath)
+
+	return func(r io.Reader) error {
+		defer func() { _ = conn.Close() }()
+		defer func() { _ = client.Close() }()
+
+		err := client.MkdirAll(path.Dir(fullPath))
+		if err != nil {
+			return fmt.Errorf("Failed creating %q: %w", path.Dir(fullPath), err)
+		}
+
+		f, err := client.Create(fullPath)
+		if err != nil {
+			return fmt.Errorf("Failed creating %q: %w", fullPath, err)
+		}
+
+		_, err = f.ReadFrom(r)
+		if err != nil {
+			_ = f.Close()
+			_ = client.Remove(fullPath)
+			return err
+		}
+
+		err = f.Close()
+		if err != nil {
+			_ = client.Remove(fullPath)
+			return err
+		}
+
+		return nil
+	}, nil
+}
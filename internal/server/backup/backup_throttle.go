@@ -0,0 +1,85 @@
+package backup
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// ParseThrottle parses a backup throttle setting (bytes per second, units supported) into a limit.
+// An empty value means no limit and returns 0.
+func ParseThrottle(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return units.ParseByteSizeString(value)
+}
+
+// throttledReader limits the rate at which data can be read from the underlying reader.
+type throttledReader struct {
+	io.Reader
+
+	limiter *rate.Limiter
+}
+
+// Read reads from the underlying reader, waiting as needed to honor the rate limit.
+func (r *throttledReader) Read(p []byte) (int, error) {
+	// Never read more than a single burst at once.
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		waitErr := r.limiter.WaitN(context.Background(), n)
+		if waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+
+	return n, err
+}
+
+// throttledReadSeeker is a throttledReader which also supports seeking.
+type throttledReadSeeker struct {
+	throttledReader
+
+	seeker io.Seeker
+}
+
+// Seek calls Seek on the underlying reader.
+func (r *throttledReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.seeker.Seek(offset, whence)
+}
+
+// newLimiter returns a rate limiter allowing for limit bytes per second.
+func newLimiter(limit int64) *rate.Limiter {
+	// Allow for bursts of up to a tenth of a second worth of data, with a floor to keep reads efficient.
+	burst := max(int(limit/10), 4096)
+
+	return rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+// NewThrottledReader returns a reader limited to reading limit bytes per second from r.
+// A limit of 0 or less returns r unchanged.
+func NewThrottledReader(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+
+	return &throttledReader{Reader: r, limiter: newLimiter(limit)}
+}
+
+// NewThrottledReadSeeker returns a read seeker limited to reading limit bytes per second from r.
+// A limit of 0 or less returns r unchanged.
+func NewThrottledReadSeeker(r io.ReadSeeker, limit int64) io.ReadSeeker {
+	if limit <= 0 {
+		return r
+	}
+
+	return &throttledReadSeeker{throttledReader: throttledReader{Reader: r, limiter: newLimiter(limit)}, seeker: r}
+}
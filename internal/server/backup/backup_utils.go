@@ -4,9 +4,12 @@ import (
 	"archive/tar"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/sys"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/archive"
 )
 
@@ -33,3 +36,50 @@ func TarReader(r io.ReadSeeker, sysOS *sys.OS, outputPath string) (*tar.Reader,
 
 	return tr, cancelFunc, nil
 }
+
+// ListFiles returns the entries of the backup tarball read from r.
+func ListFiles(r io.ReadSeeker, sysOS *sys.OS, outputPath string) ([]api.BackupFile, error) {
+	tr, cancelFunc, err := TarReader(r, sysOS, outputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cancelFunc()
+
+	files := []api.BackupFile{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break // End of archive.
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Error reading backup file list: %w", err)
+		}
+
+		file := api.BackupFile{
+			Path:       strings.TrimSuffix(hdr.Name, "/"),
+			ModifiedAt: hdr.ModTime,
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			file.Type = "file"
+			file.Size = hdr.Size
+		case tar.TypeDir:
+			file.Type = "directory"
+		case tar.TypeSymlink:
+			file.Type = "symlink"
+			file.LinkTarget = hdr.Linkname
+		case tar.TypeLink:
+			file.Type = "hardlink"
+			file.LinkTarget = hdr.Linkname
+		default:
+			file.Type = "other"
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
@@ -150,7 +150,7 @@ func (b *VolumeBackup) Render() *api.StorageVolumeBackup {
 }
 
 // Upload pushes the backup to external storage.
-func (b *VolumeBackup) Upload(req *api.BackupTarget) error {
+func (b *VolumeBackup) Upload(config map[string]string, targetPath string) error {
 	backupPath := internalUtil.VarPath("backups", "custom", b.poolName, project.StorageVolume(b.projectName, b.name))
-	return b.upload(backupPath, req)
+	return b.upload(backupPath, config, targetPath)
 }
@@ -3,9 +3,27 @@ package certificate
 import (
 	"crypto/x509"
 	"maps"
+	"net"
+	"slices"
 	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
 )
 
+// Token represents an API token in the Cache.
+type Token struct {
+	Name       string
+	Restricted bool
+	Projects   []string
+	ExpiresAt  time.Time
+}
+
+// Expired returns whether the token can no longer be used.
+func (t *Token) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
 // Cache represents an thread-safe in-memory cache of the certificates in the database.
 type Cache struct {
 	// certificates is a map of certificate Type to map of certificate fingerprint to x509.Certificate.
@@ -15,7 +33,25 @@ type Cache struct {
 	// If a certificate fingerprint is present in certificates, but not present in projects, it means the certificate is
 	// not restricted.
 	projects map[string][]string
-	mu       sync.RWMutex
+
+	// instances is a map of certificate fingerprint to slice of instance name patterns the certificate is restricted to.
+	// If a certificate fingerprint isn't present, the certificate can access all instances of its projects.
+	instances map[string][]string
+
+	// tokens is a map of API token secret hash to token.
+	tokens map[string]Token
+
+	// sshKeys is a map of SSH public key fingerprint to trusted SSH public key.
+	sshKeys map[string]ssh.PublicKey
+
+	// cas is a map of trusted CA fingerprint to the CA certificate and its intermediates.
+	cas map[string][]*x509.Certificate
+
+	// networks is a map of certificate fingerprint to the networks the certificate can be used from.
+	// If a certificate fingerprint isn't present, the certificate can be used from anywhere.
+	networks map[string][]*net.IPNet
+
+	mu sync.RWMutex
 }
 
 // SetCertificatesAndProjects sets both certificates and projects on the Cache.
@@ -92,3 +128,173 @@ func (c *Cache) GetProjects() map[string][]string {
 
 	return projects
 }
+
+// SetInstances sets the instance name patterns on the Cache.
+func (c *Cache) SetInstances(instances map[string][]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.instances = instances
+}
+
+// GetInstances returns a copy of the instance name patterns the certificate is restricted to.
+func (c *Cache) GetInstances(fingerprint string) ([]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	patterns, ok := c.instances[fingerprint]
+
+	return slices.Clone(patterns), ok
+}
+
+// SetTokens sets the API tokens on the Cache.
+func (c *Cache) SetTokens(tokens map[string]Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens = tokens
+}
+
+// GetToken returns a copy of the API token matching the secret hash.
+func (c *Cache) GetToken(secretHash string) (*Token, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	token, ok := c.tokens[secretHash]
+	if !ok {
+		return nil, false
+	}
+
+	token.Projects = slices.Clone(token.Projects)
+
+	return &token, true
+}
+
+// GetTokenByName returns a copy of the API token with the given name.
+func (c *Cache) GetTokenByName(name string) (*Token, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, token := range c.tokens {
+		if token.Name == name {
+			token.Projects = slices.Clone(token.Projects)
+			return &token, true
+		}
+	}
+
+	return nil, false
+}
+
+// SetSSHKeys sets the trusted SSH public keys on the Cache.
+func (c *Cache) SetSSHKeys(keys map[string]ssh.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sshKeys = keys
+}
+
+// GetSSHKey returns the trusted SSH public key with the given fingerprint.
+func (c *Cache) GetSSHKey(fingerprint string) (ssh.PublicKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	key, ok := c.sshKeys[fingerprint]
+
+	return key, ok
+}
+
+// SetCAs sets the trusted CAs on the Cache.
+func (c *Cache) SetCAs(cas map[string][]*x509.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cas = cas
+}
+
+// GetCA returns the certificates of the trusted CA with the given fingerprint.
+func (c *Cache) GetCA(fingerprint string) ([]*x509.Certificate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	certs, ok := c.cas[fingerprint]
+
+	return slices.Clone(certs), ok
+}
+
+// VerifyCA returns the fingerprint of the trusted CA which issued the client certificate, along with the
+// certificate which directly issued it in the verified chain (nil if the client certificate is itself trusted).
+// The first certificate of the chain is the client certificate, the others are the intermediates sent by the client.
+func (c *Cache) VerifyCA(chain []*x509.Certificate) (string, *x509.Certificate, bool) {
+	if len(chain) == 0 {
+		return "", nil, false
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, fingerprint := range slices.Sorted(maps.Keys(c.cas)) {
+		// All the certificates of the CA are trusted, including its intermediates.
+		roots := x509.NewCertPool()
+		for _, cert := range c.cas[fingerprint] {
+			roots.AddCert(cert)
+		}
+
+		verifiedChains, err := chain[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		if err == nil {
+			var issuer *x509.Certificate
+			if len(verifiedChains[0]) > 1 {
+				issuer = verifiedChains[0][1]
+			}
+
+			return fingerprint, issuer, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// SetNetworks sets the allowed networks on the Cache.
+func (c *Cache) SetNetworks(networks map[string][]*net.IPNet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.networks = networks
+}
+
+// AddressAllowed returns whether the certificate with the given fingerprint can be used from the remote address.
+func (c *Cache) AddressAllowed(fingerprint string, remoteAddr string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	networks, ok := c.networks[fingerprint]
+	if !ok {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
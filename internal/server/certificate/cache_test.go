@@ -0,0 +1,126 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChainCert(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestCache_VerifyCA(t *testing.T) {
+	root, rootKey := newTestChainCert(t, "root", true, nil, nil)
+	intermediate, intermediateKey := newTestChainCert(t, "intermediate", true, root, rootKey)
+	client, _ := newTestChainCert(t, "client", false, intermediate, intermediateKey)
+	direct, _ := newTestChainCert(t, "direct", false, root, rootKey)
+
+	other, otherKey := newTestChainCert(t, "other", true, nil, nil)
+	stranger, _ := newTestChainCert(t, "stranger", false, other, otherKey)
+
+	cache := &Cache{}
+	cache.SetCAs(map[string][]*x509.Certificate{
+		"root":  {root},
+		"chain": {intermediate, root},
+	})
+
+	for _, tt := range []struct {
+		name        string
+		chain       []*x509.Certificate
+		fingerprint string
+		issuer      *x509.Certificate
+		trusted     bool
+	}{
+		{"Client sending its intermediate", []*x509.Certificate{client, intermediate}, "chain", intermediate, true},
+		{"Client without intermediate", []*x509.Certificate{client}, "chain", intermediate, true},
+		{"Client issued by the root", []*x509.Certificate{direct}, "chain", root, true},
+		{"Client of another CA", []*x509.Certificate{stranger}, "", nil, false},
+		{"Intermediate of another CA", []*x509.Certificate{stranger, intermediate}, "", nil, false},
+		{"No certificate", nil, "", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fingerprint, issuer, trusted := cache.VerifyCA(tt.chain)
+			assert.Equal(t, tt.trusted, trusted)
+			assert.Equal(t, tt.fingerprint, fingerprint)
+			assert.Equal(t, tt.issuer, issuer)
+		})
+	}
+
+	// Without the intermediate in the trust store, the client must send it.
+	cache.SetCAs(map[string][]*x509.Certificate{"root": {root}})
+
+	_, _, trusted := cache.VerifyCA([]*x509.Certificate{client})
+	assert.False(t, trusted)
+
+	fingerprint, issuer, trusted := cache.VerifyCA([]*x509.Certificate{client, intermediate})
+	assert.True(t, trusted)
+	assert.Equal(t, "root", fingerprint)
+	assert.Equal(t, intermediate, issuer)
+}
+
+func TestCache_AddressAllowed(t *testing.T) {
+	_, lan, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	_, vpn, err := net.ParseCIDR("fd00::/64")
+	require.NoError(t, err)
+
+	cache := &Cache{}
+	cache.SetNetworks(map[string][]*net.IPNet{"bound": {lan, vpn}})
+
+	for _, tt := range []struct {
+		fingerprint string
+		remoteAddr  string
+		allowed     bool
+	}{
+		{"bound", "10.1.2.3:51234", true},
+		{"bound", "[fd00::1]:51234", true},
+		{"bound", "192.0.2.1:51234", false},
+		{"bound", "@", false},
+		{"unbound", "192.0.2.1:51234", true},
+	} {
+		assert.Equal(t, tt.allowed, cache.AddressAllowed(tt.fingerprint, tt.remoteAddr), "%s from %s", tt.fingerprint, tt.remoteAddr)
+	}
+}
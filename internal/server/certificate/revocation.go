@@ -0,0 +1,401 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// RevocationCRL indicates that CRL distribution points should be checked.
+const RevocationCRL = "crl"
+
+// RevocationOCSP indicates that OCSP responders should be checked.
+const RevocationOCSP = "ocsp"
+
+// revocationFetchTimeout is the maximum time spent fetching a CRL or an OCSP response.
+const revocationFetchTimeout = 10 * time.Second
+
+// revocationCacheDuration is how long responses without an explicit next update time are cached for.
+const revocationCacheDuration = time.Hour
+
+// revocationMaxSize is the maximum size of a CRL or OCSP response.
+const revocationMaxSize = 32 * 1024 * 1024
+
+// revocationRetryInterval is how long a failure to retrieve revocation data is cached for.
+const revocationRetryInterval = 5 * time.Minute
+
+// revocationMaxStale is how long expired revocation data keeps being used while it can't be refreshed.
+const revocationMaxStale = 24 * time.Hour
+
+// revocationFailure records a failed attempt at retrieving revocation data.
+type revocationFailure struct {
+	err  error
+	time time.Time
+}
+
+// revocationCRL is a cached revocation list along with the CA which issued it.
+type revocationCRL struct {
+	list   *x509.RevocationList
+	issuer *x509.Certificate
+}
+
+// RevocationChecker checks certificates against the CRL distribution points and OCSP responders
+// listed in them, keeping a cache of the retrieved revocation data.
+type RevocationChecker struct {
+	client *http.Client
+
+	// crls is a map of CRL distribution point URL to the last retrieved revocation list.
+	crls map[string]*revocationCRL
+
+	// ocsp is a map of issuer and certificate serial number (see ocspKey) to the last retrieved OCSP response.
+	ocsp map[string]*ocsp.Response
+
+	// failures is a map of CRL URL or OCSP issuer and serial number to the last failed retrieval.
+	failures map[string]revocationFailure
+
+	// refreshing tracks the background refreshes currently in progress.
+	refreshing map[string]bool
+
+	mu sync.Mutex
+}
+
+// NewRevocationChecker returns a new RevocationChecker using the provided proxy function for outgoing requests.
+func NewRevocationChecker(proxy func(req *http.Request) (*url.URL, error)) *RevocationChecker {
+	return &RevocationChecker{
+		client: &http.Client{
+			Timeout:   revocationFetchTimeout,
+			Transport: &http.Transport{Proxy: proxy},
+		},
+		crls:       map[string]*revocationCRL{},
+		ocsp:       map[string]*ocsp.Response{},
+		failures:   map[string]revocationFailure{},
+		refreshing: map[string]bool{},
+	}
+}
+
+// IsRevoked returns whether the certificate was revoked by its issuer using the requested methods.
+// An error is returned if the revocation status couldn't be determined.
+//
+// Revocation data is only fetched synchronously the first time it's needed. Expired data keeps being
+// used while it's refreshed in the background and failed retrievals are cached for revocationRetryInterval.
+func (c *RevocationChecker) IsRevoked(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate, methods []string) (bool, error) {
+	for _, method := range methods {
+		var revoked bool
+		var err error
+
+		switch method {
+		case RevocationCRL:
+			revoked, err = c.checkCRL(ctx, cert, issuer)
+		case RevocationOCSP:
+			revoked, err = c.checkOCSP(ctx, cert, issuer)
+		default:
+			return false, fmt.Errorf("Unknown revocation check method %q", method)
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		if revoked {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Refresh re-fetches all cached revocation lists, validating each against the CA which issued it,
+// and drops stale OCSP responses and expired failures.
+func (c *RevocationChecker) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	crls := make(map[string]*x509.Certificate, len(c.crls))
+	for u, crl := range c.crls {
+		crls[u] = crl.issuer
+	}
+
+	for id, resp := range c.ocsp {
+		if responseStale(resp.NextUpdate, resp.ThisUpdate) {
+			delete(c.ocsp, id)
+		}
+	}
+
+	for key, failure := range c.failures {
+		if time.Since(failure.time) > revocationRetryInterval {
+			delete(c.failures, key)
+		}
+	}
+
+	c.mu.Unlock()
+
+	var errs []error
+	for u, issuer := range crls {
+		_, err := c.fetchCRL(ctx, u, issuer)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkCRL checks the certificate against all of its HTTP(S) CRL distribution points.
+func (c *RevocationChecker) checkCRL(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) (bool, error) {
+	for _, u := range cert.CRLDistributionPoints {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			continue
+		}
+
+		crl, err := c.cachedCRL(ctx, u, issuer)
+		if err != nil {
+			return false, err
+		}
+
+		for _, revoked := range crl.RevokedCertificateEntries {
+			if cert.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// cachedCRL returns the revocation list at the given URL, only fetching it synchronously if nothing usable is cached.
+func (c *RevocationChecker) cachedCRL(ctx context.Context, u string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	key := RevocationCRL + ":" + u
+
+	c.mu.Lock()
+	crl := c.crls[u]
+	failure, failed := c.failures[key]
+	c.mu.Unlock()
+
+	// A revocation list cached for another CA (same distribution point) must be fetched again.
+	if crl == nil || !crl.issuer.Equal(issuer) || responseStale(crl.list.NextUpdate, crl.list.ThisUpdate) {
+		if failed && time.Since(failure.time) < revocationRetryInterval {
+			return nil, failure.err
+		}
+
+		return c.fetchCRL(ctx, u, issuer)
+	}
+
+	if responseExpired(crl.list.NextUpdate, crl.list.ThisUpdate) {
+		c.refresh(key, func(ctx context.Context) error {
+			_, err := c.fetchCRL(ctx, u, issuer)
+			return err
+		})
+	}
+
+	return crl.list, nil
+}
+
+// fetchCRL retrieves, validates and caches the revocation list at the given URL.
+func (c *RevocationChecker) fetchCRL(ctx context.Context, u string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	crl, err := c.fetchCRLData(ctx, u, issuer)
+	if err != nil {
+		c.recordFailure(RevocationCRL+":"+u, err)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.crls[u] = &revocationCRL{list: crl, issuer: issuer}
+	delete(c.failures, RevocationCRL+":"+u)
+	c.mu.Unlock()
+
+	return crl, nil
+}
+
+// fetchCRLData retrieves and validates the revocation list at the given URL.
+func (c *RevocationChecker) fetchCRLData(ctx context.Context, u string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	data, err := c.fetch(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching CRL from %q: %w", u, err)
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing CRL from %q: %w", u, err)
+	}
+
+	err = crl.CheckSignatureFrom(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("CRL from %q isn't signed by the CA: %w", u, err)
+	}
+
+	return crl, nil
+}
+
+// checkOCSP checks the certificate against the first reachable OCSP responder.
+func (c *RevocationChecker) checkOCSP(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) (bool, error) {
+	if len(cert.OCSPServer) == 0 {
+		return false, nil
+	}
+
+	id := ocspKey(cert, issuer)
+	key := RevocationOCSP + ":" + id
+
+	c.mu.Lock()
+	resp := c.ocsp[id]
+	failure, failed := c.failures[key]
+	c.mu.Unlock()
+
+	if resp == nil || responseStale(resp.NextUpdate, resp.ThisUpdate) {
+		if failed && time.Since(failure.time) < revocationRetryInterval {
+			return false, failure.err
+		}
+
+		var err error
+
+		resp, err = c.fetchOCSP(ctx, cert, issuer)
+		if err != nil {
+			return false, err
+		}
+	} else if responseExpired(resp.NextUpdate, resp.ThisUpdate) {
+		c.refresh(key, func(ctx context.Context) error {
+			_, err := c.fetchOCSP(ctx, cert, issuer)
+			return err
+		})
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return false, nil
+	case ocsp.Revoked:
+		return true, nil
+	}
+
+	return false, errors.New("OCSP responder doesn't know about the certificate")
+}
+
+// ocspKey returns the key of the cached OCSP response for the certificate.
+// Serial numbers are only unique for a given CA, so the key also identifies the issuer through its public key.
+func ocspKey(cert *x509.Certificate, issuer *x509.Certificate) string {
+	issuerKey := sha256.Sum256(issuer.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(issuerKey[:]) + ":" + cert.SerialNumber.String()
+}
+
+// fetchOCSP queries the OCSP responders of the certificate until one of them returns a valid response and caches it.
+func (c *RevocationChecker) fetchOCSP(ctx context.Context, cert *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	id := ocspKey(cert, issuer)
+	key := RevocationOCSP + ":" + id
+
+	req, err := ocsp.CreateRequest(cert, issuer, &ocsp.RequestOptions{Hash: crypto.SHA256})
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating OCSP request: %w", err)
+	}
+
+	var errs []error
+	for _, server := range cert.OCSPServer {
+		data, err := c.fetch(ctx, http.MethodPost, server, req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed querying OCSP responder %q: %w", server, err))
+			continue
+		}
+
+		resp, err := ocsp.ParseResponseForCert(data, cert, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Invalid response from OCSP responder %q: %w", server, err))
+			continue
+		}
+
+		c.mu.Lock()
+		c.ocsp[id] = resp
+		delete(c.failures, key)
+		c.mu.Unlock()
+
+		return resp, nil
+	}
+
+	err = errors.Join(errs...)
+	c.recordFailure(key, err)
+
+	return nil, err
+}
+
+// recordFailure caches a failed retrieval so that it isn't retried before revocationRetryInterval.
+func (c *RevocationChecker) recordFailure(key string, err error) {
+	c.mu.Lock()
+	c.failures[key] = revocationFailure{err: err, time: time.Now()}
+	c.mu.Unlock()
+}
+
+// refresh runs the fetch function in the background unless a refresh of the same data is already running
+// or recently failed.
+func (c *RevocationChecker) refresh(key string, fetch func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	failure, failed := c.failures[key]
+	if c.refreshing[key] || (failed && time.Since(failure.time) < revocationRetryInterval) {
+		return
+	}
+
+	c.refreshing[key] = true
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), revocationFetchTimeout)
+		defer cancel()
+
+		_ = fetch(ctx)
+
+		c.mu.Lock()
+		delete(c.refreshing, key)
+		c.mu.Unlock()
+	}()
+}
+
+// fetch performs an HTTP request and returns the response body.
+func (c *RevocationChecker) fetch(ctx context.Context, method string, u string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/ocsp-request")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected HTTP status %q", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, revocationMaxSize))
+}
+
+// responseExpired returns whether revocation data should be refreshed.
+func responseExpired(nextUpdate time.Time, thisUpdate time.Time) bool {
+	if nextUpdate.IsZero() {
+		return time.Now().After(thisUpdate.Add(revocationCacheDuration))
+	}
+
+	return time.Now().After(nextUpdate)
+}
+
+// responseStale returns whether expired revocation data is too old to be used while it's being refreshed.
+func responseStale(nextUpdate time.Time, thisUpdate time.Time) bool {
+	if nextUpdate.IsZero() {
+		return time.Now().After(thisUpdate.Add(revocationCacheDuration + revocationMaxStale))
+	}
+
+	return time.Now().After(nextUpdate.Add(revocationMaxStale))
+}
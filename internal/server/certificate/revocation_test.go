@@ -0,0 +1,255 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func newTestCert(t *testing.T, serial int64, crlURL string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent = template
+		parentKey = key
+	} else {
+		template.CRLDistributionPoints = []string{crlURL}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
+
+func TestRevocationCheckerCRL(t *testing.T) {
+	ca, caKey := newTestCert(t, 1, "", nil, nil)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: big.NewInt(2), RevocationTime: time.Now()},
+		},
+	}, ca, caKey)
+	require.NoError(t, err)
+
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_, _ = w.Write(crl)
+	}))
+	defer server.Close()
+
+	revokedCert, _ := newTestCert(t, 2, server.URL, ca, caKey)
+	validCert, _ := newTestCert(t, 3, server.URL, ca, caKey)
+	unreachableCert, _ := newTestCert(t, 4, "http://127.0.0.1:1/ca.crl", ca, caKey)
+
+	checker := NewRevocationChecker(nil)
+
+	revoked, err := checker.IsRevoked(context.Background(), revokedCert, ca, []string{RevocationCRL})
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = checker.IsRevoked(context.Background(), validCert, ca, []string{RevocationCRL})
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// The CRL is cached until its next update.
+	require.Equal(t, 1, fetches)
+
+	_, err = checker.IsRevoked(context.Background(), unreachableCert, ca, []string{RevocationCRL})
+	require.Error(t, err)
+
+	// Certificates without OCSP responders can't be checked against one.
+	revoked, err = checker.IsRevoked(context.Background(), validCert, ca, []string{RevocationOCSP})
+	require.NoError(t, err)
+	require.False(t, revoked)
+}
+
+func TestRevocationCheckerOCSP(t *testing.T) {
+	checker := NewRevocationChecker(nil)
+
+	// Two CAs issuing a certificate with the same serial number, only one of which is revoked.
+	for _, status := range []int{ocsp.Revoked, ocsp.Good} {
+		ca, caKey := newTestCert(t, 1, "", nil, nil)
+
+		var cert *x509.Certificate
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+				Status:       status,
+				SerialNumber: cert.SerialNumber,
+				ThisUpdate:   time.Now(),
+				NextUpdate:   time.Now().Add(time.Hour),
+				RevokedAt:    time.Now(),
+			}, caKey)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			_, _ = w.Write(resp)
+		}))
+		defer server.Close()
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      pkix.Name{CommonName: "test"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			OCSPServer:   []string{server.URL},
+		}, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		cert, err = x509.ParseCertificate(der)
+		require.NoError(t, err)
+
+		revoked, err := checker.IsRevoked(context.Background(), cert, ca, []string{RevocationOCSP})
+		require.NoError(t, err)
+		require.Equal(t, status == ocsp.Revoked, revoked)
+	}
+
+	require.Len(t, checker.ocsp, 2)
+}
+
+func TestRevocationCheckerRefresh(t *testing.T) {
+	checker := NewRevocationChecker(nil)
+
+	// Each cached revocation list is refreshed against the CA which issued it.
+	fetches := map[string]int{}
+	for _, name := range []string{"ca1", "ca2"} {
+		ca, caKey := newTestCert(t, 1, "", nil, nil)
+
+		crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: time.Now(),
+			NextUpdate: time.Now().Add(time.Hour),
+		}, ca, caKey)
+		require.NoError(t, err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches[name]++
+			_, _ = w.Write(crl)
+		}))
+		defer server.Close()
+
+		cert, _ := newTestCert(t, 2, server.URL, ca, caKey)
+
+		revoked, err := checker.IsRevoked(context.Background(), cert, ca, []string{RevocationCRL})
+		require.NoError(t, err)
+		require.False(t, revoked)
+	}
+
+	err := checker.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"ca1": 2, "ca2": 2}, fetches)
+
+	// A revocation list cached for another CA isn't trusted.
+	otherCA, otherKey := newTestCert(t, 1, "", nil, nil)
+	for u := range checker.crls {
+		cert, _ := newTestCert(t, 3, u, otherCA, otherKey)
+
+		_, err := checker.IsRevoked(context.Background(), cert, otherCA, []string{RevocationCRL})
+		require.Error(t, err)
+	}
+}
+
+func TestRevocationCheckerFailures(t *testing.T) {
+	ca, caKey := newTestCert(t, 1, "", nil, nil)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-2 * time.Hour),
+		NextUpdate: time.Now().Add(-time.Hour),
+	}, ca, caKey)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	fetches := 0
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fetches++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write(crl)
+	}))
+	defer server.Close()
+
+	cert, _ := newTestCert(t, 2, server.URL, ca, caKey)
+	checker := NewRevocationChecker(nil)
+
+	// Failures are cached rather than retried on every check.
+	for range 3 {
+		_, err = checker.IsRevoked(context.Background(), cert, ca, []string{RevocationCRL})
+		require.Error(t, err)
+	}
+
+	mu.Lock()
+	require.Equal(t, 1, fetches)
+	failing = false
+	mu.Unlock()
+
+	// Once the failure expires, the CRL is fetched again.
+	checker.mu.Lock()
+	checker.failures[RevocationCRL+":"+server.URL] = revocationFailure{err: errors.New("Expired"), time: time.Now().Add(-revocationRetryInterval)}
+	checker.mu.Unlock()
+
+	revoked, err := checker.IsRevoked(context.Background(), cert, ca, []string{RevocationCRL})
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	// The CRL is now expired, it keeps being used while a refresh happens in the background.
+	revoked, err = checker.IsRevoked(context.Background(), cert, ca, []string{RevocationCRL})
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	require.Eventually(t, func() bool {
+		checker.mu.Lock()
+		defer checker.mu.Unlock()
+
+		return len(checker.refreshing) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, 3, fetches)
+	mu.Unlock()
+}
@@ -0,0 +1,259 @@
+package certificate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// sshChallengeExpiry is how long an SSH challenge can be answered for.
+const sshChallengeExpiry = time.Minute
+
+// sshSessionExpiry is how long a session obtained with an SSH key remains valid.
+const sshSessionExpiry = time.Hour
+
+// sshMaxChallenges is the maximum number of pending SSH challenges.
+const sshMaxChallenges = 1024
+
+// sshMaxChallengesPerKey is the maximum number of pending SSH challenges for a single key.
+const sshMaxChallengesPerKey = 8
+
+// sshTokenPrefix is the prefix of the challenges and session tokens, followed by the ID of the issuing server.
+const sshTokenPrefix = "incus-ssh-"
+
+// sshSignatureFormats are the accepted signature formats, SHA-1 based "ssh-rsa" signatures being rejected.
+var sshSignatureFormats = []string{
+	ssh.KeyAlgoRSASHA256,
+	ssh.KeyAlgoRSASHA512,
+	ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521,
+}
+
+// SSHAuthError represents an SSH authentication error.
+type SSHAuthError struct {
+	Err error
+}
+
+func (e SSHAuthError) Error() string {
+	return fmt.Sprintf("Failed to authenticate: %s", e.Err.Error())
+}
+
+func (e SSHAuthError) Unwrap() error {
+	return e.Err
+}
+
+// SSHFingerprint returns the fingerprint of an SSH public key as used in the trust store.
+func SSHFingerprint(key ssh.PublicKey) string {
+	hash := sha256.Sum256(key.Marshal())
+	return hex.EncodeToString(hash[:])
+}
+
+// sshEntry is a challenge or a session issued for an SSH public key.
+type sshEntry struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// SSHSessions keeps track of the pending SSH challenges and of the sessions obtained by answering them.
+//
+// Challenges and sessions are only kept in memory and so are only valid on the server which issued them.
+// They carry the ID of that server so that those issued by another cluster member are explicitly rejected.
+type SSHSessions struct {
+	// id identifies the server issuing the challenges and sessions.
+	id string
+
+	// challenges is a map of challenge to the challenge details.
+	challenges map[string]sshEntry
+
+	// sessions is a map of session token hash to the session details.
+	sessions map[string]sshEntry
+
+	mu sync.Mutex
+}
+
+// NewSSHSessions returns a new empty SSHSessions.
+func NewSSHSessions() *SSHSessions {
+	id := uuid.New()
+
+	return &SSHSessions{
+		id:         hex.EncodeToString(id[:8]),
+		challenges: map[string]sshEntry{},
+		sessions:   map[string]sshEntry{},
+	}
+}
+
+// newToken returns a new random challenge or session token issued by this server.
+func (s *SSHSessions) newToken() (string, error) {
+	token, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return "", err
+	}
+
+	return sshTokenPrefix + s.id + "-" + token, nil
+}
+
+// checkIssuer checks that the challenge or session token was issued by this server.
+// It returns false if the value isn't an SSH challenge or session token at all.
+func (s *SSHSessions) checkIssuer(token string) (bool, error) {
+	issuer, ok := strings.CutPrefix(token, sshTokenPrefix)
+	if !ok {
+		return false, nil
+	}
+
+	if !strings.HasPrefix(issuer, s.id+"-") {
+		return true, errors.New("Issued by another server, SSH sessions are only valid on the server which issued them")
+	}
+
+	return true, nil
+}
+
+// NewChallenge issues a new challenge to be signed by the SSH public key.
+// The number of pending challenges, both overall and per key, is limited.
+func (s *SSHSessions) NewChallenge(key ssh.PublicKey) (string, time.Time, error) {
+	challenge, err := s.newToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	fingerprint := SSHFingerprint(key)
+	expiresAt := time.Now().Add(sshChallengeExpiry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	if len(s.challenges) >= sshMaxChallenges {
+		return "", time.Time{}, api.StatusErrorf(http.StatusTooManyRequests, "Too many pending SSH challenges")
+	}
+
+	pending := 0
+	for _, entry := range s.challenges {
+		if entry.fingerprint == fingerprint {
+			pending++
+		}
+	}
+
+	if pending >= sshMaxChallengesPerKey {
+		return "", time.Time{}, api.StatusErrorf(http.StatusTooManyRequests, "Too many pending SSH challenges for this key")
+	}
+
+	s.challenges[challenge] = sshEntry{fingerprint: fingerprint, expiresAt: expiresAt}
+
+	return challenge, expiresAt, nil
+}
+
+// NewDecoyChallenge returns a challenge which isn't recorded and so can't be answered.
+// It's handed out for untrusted keys so that the response doesn't reveal whether a key is trusted.
+func (s *SSHSessions) NewDecoyChallenge() (string, time.Time, error) {
+	challenge, err := s.newToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return challenge, time.Now().Add(sshChallengeExpiry), nil
+}
+
+// NewSession checks the signature of the challenge by the SSH public key and returns a new session token on success.
+// Challenges can only be answered once.
+func (s *SSHSessions) NewSession(key ssh.PublicKey, challenge string, signature *ssh.Signature) (string, time.Time, error) {
+	_, err := s.checkIssuer(challenge)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Invalid challenge: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	entry, ok := s.challenges[challenge]
+	if !ok {
+		return "", time.Time{}, errors.New("Unknown or expired challenge")
+	}
+
+	delete(s.challenges, challenge)
+
+	if entry.fingerprint != SSHFingerprint(key) {
+		return "", time.Time{}, errors.New("Challenge wasn't issued for this key")
+	}
+
+	if !slices.Contains(sshSignatureFormats, signature.Format) {
+		return "", time.Time{}, fmt.Errorf("Unsupported signature format %q", signature.Format)
+	}
+
+	err = key.Verify([]byte(challenge), signature)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("Invalid signature: %w", err)
+	}
+
+	token, err := s.newToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(sshSessionExpiry)
+	s.sessions[sshTokenHash(token)] = sshEntry{fingerprint: entry.fingerprint, expiresAt: expiresAt}
+
+	return token, expiresAt, nil
+}
+
+// GetSession returns the fingerprint of the SSH public key the session token was issued for.
+// It returns false if the token isn't an SSH session token and an SSHAuthError if the session isn't valid on this server.
+func (s *SSHSessions) GetSession(token string) (string, bool, error) {
+	ok, err := s.checkIssuer(token)
+	if !ok {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", true, &SSHAuthError{fmt.Errorf("Invalid SSH session: %w", err)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.sessions[sshTokenHash(token)]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", true, &SSHAuthError{errors.New("Unknown or expired SSH session")}
+	}
+
+	return entry.fingerprint, true, nil
+}
+
+// prune removes the expired challenges and sessions.
+func (s *SSHSessions) prune() {
+	now := time.Now()
+
+	for challenge, entry := range s.challenges {
+		if now.After(entry.expiresAt) {
+			delete(s.challenges, challenge)
+		}
+	}
+
+	for hash, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, hash)
+		}
+	}
+}
+
+// sshTokenHash returns the hash under which a session token is stored.
+func sshTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
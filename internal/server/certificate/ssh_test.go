@@ -0,0 +1,191 @@
+package certificate
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSSHSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	return signer
+}
+
+func TestSSHSessions(t *testing.T) {
+	sessions := NewSSHSessions()
+	signer := newTestSSHSigner(t)
+	otherSigner := newTestSSHSigner(t)
+
+	challenge, _, err := sessions.NewChallenge(signer.PublicKey())
+	require.NoError(t, err)
+
+	// Signature by another key.
+	signature, err := otherSigner.Sign(rand.Reader, []byte(challenge))
+	require.NoError(t, err)
+
+	_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.Error(t, err)
+
+	// Challenges can't be answered after a failed attempt.
+	signature, err = signer.Sign(rand.Reader, []byte(challenge))
+	require.NoError(t, err)
+
+	_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.Error(t, err)
+
+	// Valid signature.
+	challenge, _, err = sessions.NewChallenge(signer.PublicKey())
+	require.NoError(t, err)
+
+	signature, err = signer.Sign(rand.Reader, []byte(challenge))
+	require.NoError(t, err)
+
+	token, _, err := sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.NoError(t, err)
+
+	fingerprint, ok, err := sessions.GetSession(token)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, SSHFingerprint(signer.PublicKey()), fingerprint)
+
+	// Challenges can only be answered once.
+	_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.Error(t, err)
+
+	// Other bearer tokens aren't SSH sessions.
+	_, ok, err = sessions.GetSession("invalid")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Unknown SSH sessions are rejected.
+	_, ok, err = sessions.GetSession(sshTokenPrefix + sessions.id + "-invalid")
+	require.True(t, ok)
+
+	var authErr *SSHAuthError
+	require.ErrorAs(t, err, &authErr)
+}
+
+func TestSSHSessionsSignatureFormat(t *testing.T) {
+	sessions := NewSSHSessions()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	algoSigner, ok := signer.(ssh.AlgorithmSigner)
+	require.True(t, ok)
+
+	// SHA-1 signatures are rejected.
+	challenge, _, err := sessions.NewChallenge(signer.PublicKey())
+	require.NoError(t, err)
+
+	signature, err := algoSigner.SignWithAlgorithm(rand.Reader, []byte(challenge), ssh.KeyAlgoRSA)
+	require.NoError(t, err)
+
+	_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.Error(t, err)
+
+	// SHA-2 signatures are accepted.
+	for _, algo := range []string{ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSASHA512} {
+		challenge, _, err := sessions.NewChallenge(signer.PublicKey())
+		require.NoError(t, err)
+
+		signature, err := algoSigner.SignWithAlgorithm(rand.Reader, []byte(challenge), algo)
+		require.NoError(t, err)
+
+		_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+		require.NoError(t, err)
+	}
+}
+
+func TestSSHSessionsDecoyChallenge(t *testing.T) {
+	sessions := NewSSHSessions()
+	signer := newTestSSHSigner(t)
+
+	// Decoy challenges can't be answered, even with a valid signature.
+	challenge, _, err := sessions.NewDecoyChallenge()
+	require.NoError(t, err)
+
+	signature, err := signer.Sign(rand.Reader, []byte(challenge))
+	require.NoError(t, err)
+
+	_, _, err = sessions.NewSession(signer.PublicKey(), challenge, signature)
+	require.Error(t, err)
+}
+
+func TestSSHSessionsCrossMember(t *testing.T) {
+	member1 := NewSSHSessions()
+	member2 := NewSSHSessions()
+	signer := newTestSSHSigner(t)
+
+	// A challenge issued by a member can't be answered on another one.
+	challenge, _, err := member1.NewChallenge(signer.PublicKey())
+	require.NoError(t, err)
+
+	signature, err := signer.Sign(rand.Reader, []byte(challenge))
+	require.NoError(t, err)
+
+	_, _, err = member2.NewSession(signer.PublicKey(), challenge, signature)
+	require.ErrorContains(t, err, "Issued by another server")
+
+	// The challenge remains valid on the member which issued it.
+	token, _, err := member1.NewSession(signer.PublicKey(), challenge, signature)
+	require.NoError(t, err)
+
+	// A session is explicitly rejected by the other members.
+	_, ok, err := member2.GetSession(token)
+	require.True(t, ok)
+
+	var authErr *SSHAuthError
+	require.ErrorAs(t, err, &authErr)
+	require.ErrorContains(t, err, "Issued by another server")
+
+	fingerprint, ok, err := member1.GetSession(token)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, SSHFingerprint(signer.PublicKey()), fingerprint)
+}
+
+func TestSSHSessionsChallengeLimits(t *testing.T) {
+	sessions := NewSSHSessions()
+	signer := newTestSSHSigner(t)
+
+	for range sshMaxChallengesPerKey {
+		_, _, err := sessions.NewChallenge(signer.PublicKey())
+		require.NoError(t, err)
+	}
+
+	// Pending challenges are limited per key.
+	_, _, err := sessions.NewChallenge(signer.PublicKey())
+	require.Error(t, err)
+
+	_, _, err = sessions.NewChallenge(newTestSSHSigner(t).PublicKey())
+	require.NoError(t, err)
+
+	// Expired challenges don't count towards the limit.
+	sessions.mu.Lock()
+	for challenge, entry := range sessions.challenges {
+		entry.expiresAt = time.Now().Add(-time.Second)
+		sessions.challenges[challenge] = entry
+	}
+
+	sessions.mu.Unlock()
+
+	_, _, err = sessions.NewChallenge(signer.PublicKey())
+	require.NoError(t, err)
+	require.Len(t, sessions.challenges, 1)
+}
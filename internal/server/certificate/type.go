@@ -18,6 +18,12 @@ const TypeServer = Type(2)
 // TypeMetrics indicates a metrics certificate type.
 const TypeMetrics = Type(3)
 
+// TypeSSH indicates an SSH public key.
+const TypeSSH = Type(4)
+
+// TypeCA indicates a trusted certificate authority, along with its intermediates.
+const TypeCA = Type(5)
+
 // FromAPIType converts an API type to the equivalent Type.
 func FromAPIType(apiType string) (Type, error) {
 	switch apiType {
@@ -27,6 +33,10 @@ func FromAPIType(apiType string) (Type, error) {
 		return TypeServer, nil
 	case api.CertificateTypeMetrics:
 		return TypeMetrics, nil
+	case api.CertificateTypeSSH:
+		return TypeSSH, nil
+	case api.CertificateTypeCA:
+		return TypeCA, nil
 	}
 
 	return -1, errors.New("Invalid certificate type")
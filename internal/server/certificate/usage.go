@@ -0,0 +1,39 @@
+package certificate
+
+import (
+	"sync"
+	"time"
+)
+
+// Usage keeps track of when the entries of the trust store were last used.
+// Uses are kept in memory and periodically flushed to the database to avoid writing on every request.
+type Usage struct {
+	// pending is a map of fingerprint to the last use not yet written to the database.
+	pending map[string]time.Time
+
+	mu sync.Mutex
+}
+
+// NewUsage returns a new empty Usage.
+func NewUsage() *Usage {
+	return &Usage{pending: map[string]time.Time{}}
+}
+
+// Record marks the entry with the given fingerprint as used now.
+func (u *Usage) Record(fingerprint string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.pending[fingerprint] = time.Now().UTC()
+}
+
+// Flush returns the uses recorded since the last flush and forgets them.
+func (u *Usage) Flush() map[string]time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	pending := u.pending
+	u.pending = map[string]time.Time{}
+
+	return pending
+}
@@ -0,0 +1,24 @@
+package certificate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage_Flush(t *testing.T) {
+	u := NewUsage()
+	assert.Empty(t, u.Flush())
+
+	u.Record("a")
+	first := u.Flush()["a"]
+	u.Record("a")
+	u.Record("b")
+
+	usage := u.Flush()
+	assert.Len(t, usage, 2)
+	assert.False(t, usage["a"].Before(first))
+
+	// Uses are only returned once.
+	assert.Empty(t, u.Flush())
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/shared/api"
 )
 
 // CGroup represents the main cgroup abstraction.
@@ -1005,8 +1006,34 @@ func (cg *CGroup) GetOOMKills() (int64, error) {
 	return -1, errors.New("Failed getting oom_kill")
 }
 
+// GetPressure returns the pressure stall information for the given resource (cpu, memory or io).
+func (cg *CGroup) GetPressure(resource string) (*api.InstanceStatePressureResource, error) {
+	controller := resource
+	if resource == "io" {
+		controller = "blkio"
+	}
+
+	// Pressure stall information is only available on cgroup2.
+	version := cgControllers[controller]
+	if version != V2 {
+		return nil, ErrControllerMissing
+	}
+
+	val, err := cg.rw.Get(version, resource, fmt.Sprintf("%s.pressure", resource))
+	if err != nil {
+		return nil, err
+	}
+
+	return linux.ParsePressure(val)
+}
+
 // GetIOStats returns disk stats.
 func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
+	devMap, err := cg.GetIOStatsByDevice()
+	if err != nil {
+		return nil, err
+	}
+
 	partitions, err := os.ReadFile("/proc/partitions")
 	if err != nil {
 		return nil, fmt.Errorf("Failed to read /proc/partitions: %w", err)
@@ -1032,7 +1059,18 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 		partMap[fmt.Sprintf("%s:%s", fields[0], fields[1])] = fields[3]
 	}
 
-	// ioMap contains io stats for each device
+	// ioMap contains io stats for each device (by name)
+	ioMap := make(map[string]*IOStats, len(devMap))
+	for devID, ioStats := range devMap {
+		ioMap[partMap[devID]] = ioStats
+	}
+
+	return ioMap, nil
+}
+
+// GetIOStatsByDevice returns disk stats indexed by device number (major:minor).
+func (cg *CGroup) GetIOStatsByDevice() (map[string]*IOStats, error) {
+	// ioMap contains io stats for each device (by major:minor)
 	ioMap := make(map[string]*IOStats)
 
 	version := cgControllers["blkio"]
@@ -1059,15 +1097,15 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 				continue
 			}
 
-			if ioMap[partMap[fields[0]]] == nil {
-				ioMap[partMap[fields[0]]] = &IOStats{}
+			if ioMap[fields[0]] == nil {
+				ioMap[fields[0]] = &IOStats{}
 			}
 
 			switch fields[1] {
 			case "Read":
-				ioMap[partMap[fields[0]]].ReadBytes, err = strconv.ParseUint(fields[2], 10, 64)
+				ioMap[fields[0]].ReadBytes, err = strconv.ParseUint(fields[2], 10, 64)
 			case "Write":
-				ioMap[partMap[fields[0]]].WrittenBytes, err = strconv.ParseUint(fields[2], 10, 64)
+				ioMap[fields[0]].WrittenBytes, err = strconv.ParseUint(fields[2], 10, 64)
 			}
 
 			if err != nil {
@@ -1094,15 +1132,15 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 				continue
 			}
 
-			if ioMap[partMap[fields[0]]] == nil {
-				ioMap[partMap[fields[0]]] = &IOStats{}
+			if ioMap[fields[0]] == nil {
+				ioMap[fields[0]] = &IOStats{}
 			}
 
 			switch fields[1] {
 			case "Read":
-				ioMap[partMap[fields[0]]].ReadsCompleted, err = strconv.ParseUint(fields[2], 10, 64)
+				ioMap[fields[0]].ReadsCompleted, err = strconv.ParseUint(fields[2], 10, 64)
 			case "Write":
-				ioMap[partMap[fields[0]]].WritesCompleted, err = strconv.ParseUint(fields[2], 10, 64)
+				ioMap[fields[0]].WritesCompleted, err = strconv.ParseUint(fields[2], 10, 64)
 			}
 
 			if err != nil {
@@ -1169,7 +1207,7 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 				}
 			}
 
-			ioMap[partMap[devID]] = ioStats
+			ioMap[devID] = ioStats
 		}
 
 		return ioMap, nil
@@ -1177,3 +1215,40 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 
 	return nil, ErrUnknownVersion
 }
+
+// GetControllers returns the controllers available to the cgroup.
+func (cg *CGroup) GetControllers() ([]string, error) {
+	if cgLayout != CgroupsUnified {
+		return nil, ErrControllerMissing
+	}
+
+	val, err := cg.rw.Get(V2, "unified", "cgroup.controllers")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(val), nil
+}
+
+// GetSubtreeControllers returns the controllers enabled for the children of the cgroup.
+func (cg *CGroup) GetSubtreeControllers() ([]string, error) {
+	if cgLayout != CgroupsUnified {
+		return nil, ErrControllerMissing
+	}
+
+	val, err := cg.rw.Get(V2, "unified", "cgroup.subtree_control")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(val), nil
+}
+
+// EnableSubtreeController enables a controller for the children of the cgroup.
+func (cg *CGroup) EnableSubtreeController(controller string) error {
+	if cgLayout != CgroupsUnified {
+		return ErrControllerMissing
+	}
+
+	return cg.rw.Set(V2, "unified", "cgroup.subtree_control", "+"+controller)
+}
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -18,6 +19,7 @@ import (
 var (
 	cgControllers = map[string]Backend{}
 	cgNamespace   bool
+	cgNsDelegate  bool
 )
 
 // Layout determines the cgroup layout on this system.
@@ -43,12 +45,16 @@ type Info struct {
 
 	// Namespacing indicates support for the cgroup namespace
 	Namespacing bool
+
+	// NsDelegate indicates that the cgroup2 hierarchy is mounted with nsdelegate
+	NsDelegate bool
 }
 
 // GetInfo returns basic system cgroup information.
 func GetInfo() Info {
 	info := Info{}
 	info.Namespacing = cgNamespace
+	info.NsDelegate = cgNsDelegate
 	info.Layout = cgLayout
 
 	return info
@@ -486,5 +492,46 @@ func Init() {
 
 		// With Cgroup2 freezer is built-in.
 		cgControllers["freezer"] = V2
+
+		// Check whether cgroup namespaces are delegation boundaries.
+		cgNsDelegate = hasNsDelegate()
+	}
+}
+
+// hasNsDelegate checks whether the cgroup2 hierarchy is mounted with the nsdelegate option.
+func hasNsDelegate() bool {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return false
 	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		mountFields, superFields, found := strings.Cut(scanner.Text(), " - ")
+		if !found {
+			continue
+		}
+
+		mount := strings.Fields(mountFields)
+		super := strings.Fields(superFields)
+		if len(mount) < 5 || len(super) < 3 || mount[4] != cgPath || super[0] != "cgroup2" {
+			continue
+		}
+
+		return slices.Contains(strings.Split(super[2], ","), "nsdelegate")
+	}
+
+	return false
+}
+
+// UnifiedControllers returns the controllers available on the cgroup2 hierarchy.
+func UnifiedControllers() ([]string, error) {
+	content, err := os.ReadFile(filepath.Join(cgPath, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(content)), nil
 }
@@ -12,9 +12,11 @@ import (
 	"github.com/sirupsen/logrus"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth/ldap"
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -46,6 +48,11 @@ func (c *Config) BackupsCompressionAlgorithm() string {
 	return c.m.GetString("backups.compression_algorithm")
 }
 
+// BackupsDownloadHosts returns the hosts from which non-admin clients can have backups downloaded.
+func (c *Config) BackupsDownloadHosts() []string {
+	return util.SplitNTrimSpace(c.m.GetString("backups.download_hosts"), ",", -1, true)
+}
+
 // MetricsAuthentication checks whether metrics API requires authentication.
 func (c *Config) MetricsAuthentication() bool {
 	return c.m.GetBool("core.metrics_authentication")
@@ -82,6 +89,17 @@ func (c *Config) TrustCACertificates() bool {
 	return c.m.GetBool("core.trust_ca_certificates")
 }
 
+// TrustCARevocation returns the revocation checks to perform on CA-issued client certificates.
+func (c *Config) TrustCARevocation() []string {
+	return util.SplitNTrimSpace(c.m.GetString("core.trust_ca_revocation"), ",", -1, true)
+}
+
+// TrustCARevocationStrict returns whether client certificates should be rejected when their
+// revocation status can't be determined.
+func (c *Config) TrustCARevocationStrict() bool {
+	return c.m.GetBool("core.trust_ca_revocation_strict")
+}
+
 // ProxyHTTPS returns the configured HTTPS proxy, if any.
 func (c *Config) ProxyHTTPS() string {
 	return c.m.GetString("core.proxy_https")
@@ -173,6 +191,11 @@ func (c *Config) LinstorSSL() (string, string, string) {
 	return c.m.GetString("storage.linstor.ca_cert"), c.m.GetString("storage.linstor.client_cert"), c.m.GetString("storage.linstor.client_key")
 }
 
+// StorageTrashExpiryDays returns the number of days deleted instances and custom volumes are kept in the trash.
+func (c *Config) StorageTrashExpiryDays() int64 {
+	return c.m.GetInt64("storage.trash_expiry")
+}
+
 // ShutdownTimeout returns the number of minutes to wait for running operation to complete
 // before the server shuts down.
 func (c *Config) ShutdownTimeout() time.Duration {
@@ -210,6 +233,21 @@ func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
 }
 
+// InstancesBlueprintsKeepVersions returns the number of previous versions of each blueprint to retain.
+func (c *Config) InstancesBlueprintsKeepVersions() int64 {
+	return c.m.GetInt64("instances.blueprints.keep_versions")
+}
+
+// InstancesHugepagesAutoAllocate returns whether the host huge pages pool should be managed automatically.
+func (c *Config) InstancesHugepagesAutoAllocate() bool {
+	return c.m.GetBool("instances.hugepages.auto_allocate")
+}
+
+// InstancesPlacementPolicy returns the policy used to rank cluster members for automatic instance placement.
+func (c *Config) InstancesPlacementPolicy() string {
+	return c.m.GetString("instances.placement.policy")
+}
+
 // InstancesPlacementScriptlet returns the instances placement scriptlet source code.
 func (c *Config) InstancesPlacementScriptlet() string {
 	return c.m.GetString("instances.placement.scriptlet")
@@ -294,6 +332,16 @@ func (c *Config) RemoteTokenExpiry() string {
 	return c.m.GetString("core.remote_token_expiry")
 }
 
+// AuditSinks returns the path of the audit log file and whether to send the audit log to syslog.
+func (c *Config) AuditSinks() (string, bool) {
+	return c.m.GetString("core.audit_file"), c.m.GetBool("core.audit_syslog")
+}
+
+// Kerberos returns the Kerberos keytab path and the list of allowed realms.
+func (c *Config) Kerberos() (string, []string) {
+	return c.m.GetString("kerberos.keytab"), util.SplitNTrimSpace(c.m.GetString("kerberos.realms"), ",", -1, true)
+}
+
 // OIDCServer returns all the OpenID Connect settings needed to connect to a server.
 func (c *Config) OIDCServer() (string, string, string, string, string) {
 	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
@@ -324,6 +372,25 @@ func (c *Config) OpenFGA() (apiURL string, apiToken string, storeID string) {
 	return c.m.GetString("openfga.api.url"), c.m.GetString("openfga.api.token"), c.m.GetString("openfga.store.id")
 }
 
+// OpenFGALDAP returns all settings needed to synchronize OpenFGA groups from an LDAP server.
+func (c *Config) OpenFGALDAP() ldap.Config {
+	return ldap.Config{
+		URL:            c.m.GetString("openfga.ldap.url"),
+		BindDN:         c.m.GetString("openfga.ldap.bind_dn"),
+		BindPassword:   c.m.GetString("openfga.ldap.bind_password"),
+		BaseDN:         c.m.GetString("openfga.ldap.base_dn"),
+		GroupFilter:    c.m.GetString("openfga.ldap.group_filter"),
+		GroupAttribute: c.m.GetString("openfga.ldap.group_attribute"),
+		UserAttribute:  c.m.GetString("openfga.ldap.user_attribute"),
+	}
+}
+
+// OpenFGALDAPSyncInterval returns how often OpenFGA groups are synchronized from the LDAP server.
+func (c *Config) OpenFGALDAPSyncInterval() time.Duration {
+	n := c.m.GetInt64("openfga.ldap.sync_interval")
+	return time.Duration(n) * time.Minute
+}
+
 // Loggers returns a map where the key is the logger name and the value is its type.
 func (c *Config) Loggers() (map[string]string, error) {
 	result := make(map[string]string)
@@ -537,6 +604,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Compression algorithm to use for backups
 	"backups.compression_algorithm": {Default: "gzip", Validator: validate.IsCompressionAlgorithm},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.download_hosts)
+	// Comma-separated list of host names from which clients that aren't server administrators can import a backup by URL.
+	// Server administrators can download from any host. Loopback and link-local destinations are always refused.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Hosts allowed for backup imports by URL
+	"backups.download_hosts": {Validator: validate.Optional(validate.IsListOf(validate.IsAny))},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.offline_threshold)
 	// Specify the number of seconds after which an unresponsive member is considered offline.
 	// ---
@@ -640,6 +716,23 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to enforce authentication on the metrics endpoint
 	"core.metrics_authentication": {Type: config.Bool, Default: "true"},
 
+	// gendoc:generate(entity=server, group=core, key=core.audit_file)
+	// Authentication decisions are appended to this file as JSON, one per line.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Path of the authentication audit log file
+	"core.audit_file": {Validator: validate.Optional(validate.IsAbsFilePath)},
+
+	// gendoc:generate(entity=server, group=core, key=core.audit_syslog)
+	// Authentication decisions are sent to the local syslog with the `authpriv` facility.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to send the authentication audit log to syslog
+	"core.audit_syslog": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=core, key=core.bgp_asn)
 	//
 	// ---
@@ -742,6 +835,26 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to automatically trust clients signed by the CA
 	"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+	// gendoc:generate(entity=server, group=core, key=core.trust_ca_revocation)
+	// Comma-separated list of revocation checks (`crl` and/or `ocsp`) to perform on client certificates
+	// trusted through {config:option}`server-core:core.trust_ca_certificates` or issued by a CA of the trust store.
+	// CRL distribution points and OCSP responders are taken from the client certificate.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Revocation checks for CA-issued client certificates
+	"core.trust_ca_revocation": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("crl", "ocsp")))},
+
+	// gendoc:generate(entity=server, group=core, key=core.trust_ca_revocation_strict)
+	// When enabled, client certificates are rejected if their revocation status can't be determined,
+	// for example because the CRL distribution point or OCSP responder can't be reached.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to reject clients when revocation checks fail
+	"core.trust_ca_revocation_strict": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=images, key=images.auto_update_cached)
 	//
 	// ---
@@ -786,6 +899,26 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: When an unused cached remote image is flushed
 	"images.remote_cache_expiry": {Type: config.Int64, Default: "10"},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.blueprints.keep_versions)
+	// Number of previous versions of each blueprint to retain.
+	// Retained versions can be selected when creating an instance from a blueprint.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `10`
+	//  shortdesc: How many previous blueprint versions to keep
+	"instances.blueprints.keep_versions": {Type: config.Int64, Default: "10", Validator: validate.Optional(validate.IsUint32)},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.hugepages.auto_allocate)
+	// When enabled, the host huge pages pool is grown as needed when starting virtual machines using `limits.memory.hugepages`
+	// and shrunk back once they stop.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to manage the host huge pages pool automatically
+	"instances.hugepages.auto_allocate": {Type: config.Bool, Validator: validate.Optional(validate.IsBool)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=instances.lxcfs.per_instance)
 	// LXCFS is used to provide overlays for common `/proc` and `/sys`
 	// files which reflect the resource limits applied to the container.
@@ -815,6 +948,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How to set the host name for a NIC
 	"instances.nic.host_name": {Validator: validate.Optional(validate.IsOneOf("random", "mac"))},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.placement.policy)
+	// Possible values are `instance_count`, `balanced`, `load` and `reserved`.
+	// See {ref}`clustering-instance-placement-policy` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `instance_count`
+	//  shortdesc: Policy used to rank cluster members for automatic instance placement
+	"instances.placement.policy": {Default: "instance_count", Validator: validate.Optional(validate.IsOneOf("balanced", "load", "reserved", "instance_count"))},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=instances.placement.scriptlet)
 	// When using custom automatic instance placement logic, this option stores the scriptlet.
 	// See {ref}`clustering-instance-placement-scriptlet` for more information.
@@ -908,6 +1051,76 @@ var ConfigSchema = config.Schema{
 	// shortdesc: URL of the OpenFGA server
 	"openfga.api.url": {},
 
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.base_dn)
+	// Specify the DN under which to look for the groups to synchronize and their members.
+	// Members outside of it are looked up individually, which is slower for large groups.
+	// ---
+	// type: string
+	// scope: global
+	// shortdesc: Base DN of the LDAP groups
+	"openfga.ldap.base_dn": {},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.bind_dn)
+	// Leave empty to bind anonymously.
+	// ---
+	// type: string
+	// scope: global
+	// shortdesc: DN used to bind to the LDAP server
+	"openfga.ldap.bind_dn": {},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.bind_password)
+	//
+	// ---
+	// type: string
+	// scope: global
+	// shortdesc: Password used to bind to the LDAP server
+	"openfga.ldap.bind_password": {},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.group_attribute)
+	// The value of this attribute is used as the name of the OpenFGA group.
+	// ---
+	// type: string
+	// scope: global
+	// defaultdesc: `cn`
+	// shortdesc: Attribute holding the name of LDAP groups
+	"openfga.ldap.group_attribute": {Default: "cn"},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.group_filter)
+	// Members are read from the `member`, `uniqueMember` and `memberUid` attributes of the groups.
+	// Nested groups are expanded if they match the filter too.
+	// ---
+	// type: string
+	// scope: global
+	// defaultdesc: `(|(objectClass=groupOfNames)(objectClass=group))`
+	// shortdesc: Filter selecting the LDAP groups to synchronize
+	"openfga.ldap.group_filter": {Default: "(|(objectClass=groupOfNames)(objectClass=group))"},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.sync_interval)
+	// Specify the number of minutes between two synchronizations of the group memberships.
+	// ---
+	// type: integer
+	// scope: global
+	// defaultdesc: `60`
+	// shortdesc: Interval between LDAP group synchronizations
+	"openfga.ldap.sync_interval": {Type: config.Int64, Default: "60", Validator: validate.Optional(validate.IsInRange(1, 10080))},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.url)
+	// Set this to synchronize the members of the LDAP or Active Directory groups into OpenFGA groups.
+	// ---
+	// type: string
+	// scope: global
+	// shortdesc: URL of the LDAP server
+	"openfga.ldap.url": {Validator: validate.Optional(validate.IsListOf(validate.IsRequestURL))},
+
+	// gendoc:generate(entity=server, group=openfga, key=openfga.ldap.user_attribute)
+	// The value of this attribute must match the username used by Incus, for example the OpenID Connect claim.
+	// ---
+	// type: string
+	// scope: global
+	// defaultdesc: `mail`
+	// shortdesc: Attribute of LDAP users holding their Incus username
+	"openfga.ldap.user_attribute": {Default: "mail"},
+
 	// gendoc:generate(entity=server, group=openfga, key=openfga.store.id)
 	//
 	// ---
@@ -956,6 +1169,24 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=kerberos, key=kerberos.keytab)
+	// Set this to enable Kerberos (SPNEGO) authentication.
+	// The keytab must contain the key of the `HTTP/<hostname>` service principal and should be present at that path on all cluster members.
+	// Kerberos authentication is disabled on members where the keytab is missing.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Path to the Kerberos keytab of the server
+	"kerberos.keytab": {Validator: validate.Optional(validate.IsAbsFilePath)},
+
+	// gendoc:generate(entity=server, group=kerberos, key=kerberos.realms)
+	// If empty, principals of any realm the keytab can validate tickets for are accepted.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Comma separated list of allowed Kerberos realms
+	"kerberos.realms": {Validator: validate.Optional(validate.IsListOf(validate.IsAny))},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
@@ -1034,6 +1265,16 @@ var ConfigSchema = config.Schema{
 	//  scope: global
 	//  shortdesc: LINSTOR SSL client key
 	"storage.linstor.client_key": {Default: ""},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=storage.trash_expiry)
+	// When set, deleted instances and custom storage volumes are kept in the trash for the given
+	// number of days, during which they can be restored. Set to `0` to delete them immediately.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Number of days deleted instances and custom volumes are kept in the trash
+	"storage.trash_expiry": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsUint32)},
 }
 
 func expiryValidator(value string) error {
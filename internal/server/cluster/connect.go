@@ -16,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/proxy"
@@ -46,12 +47,12 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 
 	args := &incus.ConnectionArgs{
 		TLSServerCert: string(networkCert.PublicKey()),
-		TLSClientCert: string(serverCert.PublicKey()),
-		TLSClientKey:  string(serverCert.PrivateKey()),
 		SkipGetServer: true,
 		UserAgent:     version.UserAgent,
 	}
 
+	internalUtil.SetClientCertificate(args, serverCert)
+
 	if notify {
 		args.UserAgent = clusterRequest.UserAgentNotifier
 	}
@@ -247,12 +248,12 @@ func SetupTrust(serverCert *localtls.CertInfo, serverName string, targetAddress
 func UpdateTrust(serverCert *localtls.CertInfo, serverName string, targetAddress string, targetCert string) error {
 	// Connect to the target cluster node.
 	args := &incus.ConnectionArgs{
-		TLSClientCert: string(serverCert.PublicKey()),
-		TLSClientKey:  string(serverCert.PrivateKey()),
 		TLSServerCert: targetCert,
 		UserAgent:     version.UserAgent,
 	}
 
+	internalUtil.SetClientCertificate(args, serverCert)
+
 	target, err := incus.ConnectIncus(fmt.Sprintf("https://%s", targetAddress), args)
 	if err != nil {
 		return fmt.Errorf("Failed to connect to target cluster node %q: %w", targetAddress, err)
@@ -28,8 +28,7 @@ func tlsClientConfig(networkCert *localtls.CertInfo, serverCert *localtls.CertIn
 	config := localtls.InitTLSConfig()
 	config.Certificates = []tls.Certificate{keypair}
 	config.RootCAs = x509.NewCertPool()
-	ca := serverCert.CA()
-	if ca != nil {
+	for _, ca := range serverCert.CAs() {
 		config.RootCAs.AddCert(ca)
 	}
 
@@ -0,0 +1,165 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// BackupGroup is a value object holding all db-related details about a backup group.
+type BackupGroup struct {
+	ID           int64
+	Project      string
+	Node         string
+	Name         string
+	Instance     string
+	Volumes      []api.BackupGroupVolume
+	CreationDate time.Time
+}
+
+// ToAPI converts the backup group to its API representation.
+func (g BackupGroup) ToAPI() api.BackupGroup {
+	volumes := g.Volumes
+	if volumes == nil {
+		volumes = []api.BackupGroupVolume{}
+	}
+
+	return api.BackupGroup{
+		Name:      g.Name,
+		CreatedAt: g.CreationDate,
+		Snapshot:  g.Name,
+		Instance:  g.Instance,
+		Volumes:   volumes,
+		Location:  g.Node,
+	}
+}
+
+const backupGroupsQuery = `
+SELECT backup_groups.id, projects.name, nodes.name, backup_groups.name, backup_groups.instance, backup_groups.creation_date
+    FROM backup_groups
+    JOIN projects ON projects.id=backup_groups.project_id
+    JOIN nodes ON nodes.id=backup_groups.node_id
+`
+
+const backupGroupsVolumesQuery = `
+SELECT backup_groups_volumes.backup_group_id, backup_groups_volumes.storage_pool, backup_groups_volumes.name
+    FROM backup_groups_volumes
+    JOIN backup_groups ON backup_groups.id=backup_groups_volumes.backup_group_id
+    JOIN projects ON projects.id=backup_groups.project_id
+`
+
+// getBackupGroups runs the backup groups query with the given extra clause, loading the volumes of all the
+// matching groups at once.
+func (c *ClusterTx) getBackupGroups(ctx context.Context, where string, args ...any) ([]BackupGroup, error) {
+	var groups []BackupGroup
+
+	err := query.Scan(ctx, c.tx, backupGroupsQuery+where+" ORDER BY backup_groups.id", func(scan func(dest ...any) error) error {
+		var g BackupGroup
+
+		err := scan(&g.ID, &g.Project, &g.Node, &g.Name, &g.Instance, &g.CreationDate)
+		if err != nil {
+			return err
+		}
+
+		groups = append(groups, g)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := map[int64][]api.BackupGroupVolume{}
+
+	err = query.Scan(ctx, c.tx, backupGroupsVolumesQuery+where+" ORDER BY backup_groups_volumes.id", func(scan func(dest ...any) error) error {
+		var groupID int64
+		var vol api.BackupGroupVolume
+
+		err := scan(&groupID, &vol.Pool, &vol.Name)
+		if err != nil {
+			return err
+		}
+
+		volumes[groupID] = append(volumes[groupID], vol)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		groups[i].Volumes = volumes[groups[i].ID]
+	}
+
+	return groups, nil
+}
+
+// GetBackupGroups returns the backup groups of the given project.
+func (c *ClusterTx) GetBackupGroups(ctx context.Context, projectName string) ([]BackupGroup, error) {
+	return c.getBackupGroups(ctx, "WHERE projects.name=?", projectName)
+}
+
+// GetBackupGroup returns the backup group with the given name in the given project.
+func (c *ClusterTx) GetBackupGroup(ctx context.Context, projectName string, name string) (*BackupGroup, error) {
+	groups, err := c.getBackupGroups(ctx, "WHERE projects.name=? AND backup_groups.name=?", projectName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Backup group not found")
+	}
+
+	return &groups[0], nil
+}
+
+// CreateBackupGroup records a new backup group, stored on this member.
+func (c *ClusterTx) CreateBackupGroup(ctx context.Context, group BackupGroup) error {
+	q := `
+INSERT INTO backup_groups (project_id, node_id, name, instance, creation_date)
+    VALUES ((SELECT id FROM projects WHERE name=?), ?, ?, ?, ?)
+`
+	result, err := c.tx.ExecContext(ctx, q, group.Project, c.nodeID, group.Name, group.Instance, group.CreationDate)
+	if err != nil {
+		return err
+	}
+
+	groupID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, vol := range group.Volumes {
+		_, err := c.tx.ExecContext(ctx, "INSERT INTO backup_groups_volumes (backup_group_id, storage_pool, name) VALUES (?, ?, ?)", groupID, vol.Pool, vol.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteBackupGroup removes the backup group with the given name in the given project.
+func (c *ClusterTx) DeleteBackupGroup(ctx context.Context, projectName string, name string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM backup_groups WHERE project_id=(SELECT id FROM projects WHERE name=?) AND name=?", projectName, name)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Backup group not found")
+	}
+
+	return nil
+}
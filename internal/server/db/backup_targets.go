@@ -0,0 +1,217 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// BackupTarget is a value object holding all db-related details about a server-side backup target.
+type BackupTarget struct {
+	ID          int64
+	Name        string
+	Description string
+	Config      map[string]string
+}
+
+// ToAPI converts the backup target to its API representation.
+func (t BackupTarget) ToAPI() api.NamedBackupTarget {
+	return api.NamedBackupTarget{
+		NamedBackupTargetPost: api.NamedBackupTargetPost{
+			Name: t.Name,
+		},
+		NamedBackupTargetPut: api.NamedBackupTargetPut{
+			Description: t.Description,
+			Config:      t.Config,
+		},
+	}
+}
+
+// GetBackupTargetNames returns the names of all the backup targets.
+func (c *ClusterTx) GetBackupTargetNames(ctx context.Context) ([]string, error) {
+	return query.SelectStrings(ctx, c.tx, "SELECT name FROM backup_targets ORDER BY name")
+}
+
+// GetBackupTargets returns all the backup targets.
+func (c *ClusterTx) GetBackupTargets(ctx context.Context) ([]BackupTarget, error) {
+	var targets []BackupTarget
+
+	err := query.Scan(ctx, c.tx, "SELECT id, name, description FROM backup_targets ORDER BY name", func(scan func(dest ...any) error) error {
+		var t BackupTarget
+
+		err := scan(&t.ID, &t.Name, &t.Description)
+		if err != nil {
+			return err
+		}
+
+		targets = append(targets, t)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range targets {
+		targets[i].Config, err = c.getBackupTargetConfig(ctx, targets[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return targets, nil
+}
+
+// GetBackupTarget returns the backup target with the given name.
+func (c *ClusterTx) GetBackupTarget(ctx context.Context, name string) (*BackupTarget, error) {
+	t := BackupTarget{Name: name}
+
+	err := c.tx.QueryRowContext(ctx, "SELECT id, description FROM backup_targets WHERE name=?", name).Scan(&t.ID, &t.Description)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Backup target not found")
+		}
+
+		return nil, err
+	}
+
+	t.Config, err = c.getBackupTargetConfig(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// getBackupTargetConfig returns the config of the backup target with the given ID.
+func (c *ClusterTx) getBackupTargetConfig(ctx context.Context, id int64) (map[string]string, error) {
+	config := map[string]string{}
+
+	err := query.Scan(ctx, c.tx, "SELECT key, value FROM backup_targets_config WHERE backup_target_id=?", func(scan func(dest ...any) error) error {
+		var key, value string
+
+		err := scan(&key, &value)
+		if err != nil {
+			return err
+		}
+
+		config[key] = value
+
+		return nil
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// setBackupTargetConfig replaces the config of the backup target with the given ID.
+func (c *ClusterTx) setBackupTargetConfig(ctx context.Context, id int64, config map[string]string) error {
+	_, err := c.tx.ExecContext(ctx, "DELETE FROM backup_targets_config WHERE backup_target_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range config {
+		if value == "" {
+			continue
+		}
+
+		_, err = c.tx.ExecContext(ctx, "INSERT INTO backup_targets_config (backup_target_id, key, value) VALUES (?, ?, ?)", id, key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateBackupTarget adds a new backup target.
+func (c *ClusterTx) CreateBackupTarget(ctx context.Context, name string, description string, config map[string]string) (int64, error) {
+	_, err := c.GetBackupTarget(ctx, name)
+	if err == nil {
+		return -1, api.StatusErrorf(http.StatusConflict, "A backup target for that name already exists")
+	}
+
+	result, err := c.tx.ExecContext(ctx, "INSERT INTO backup_targets (name, description) VALUES (?, ?)", name, description)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, err
+	}
+
+	err = c.setBackupTargetConfig(ctx, id, config)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// UpdateBackupTarget updates the description and config of a backup target.
+func (c *ClusterTx) UpdateBackupTarget(ctx context.Context, name string, description string, config map[string]string) error {
+	target, err := c.GetBackupTarget(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE backup_targets SET description=? WHERE id=?", description, target.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.setBackupTargetConfig(ctx, target.ID, config)
+}
+
+// RenameBackupTarget renames a backup target.
+func (c *ClusterTx) RenameBackupTarget(ctx context.Context, name string, newName string) error {
+	_, err := c.GetBackupTarget(ctx, newName)
+	if err == nil {
+		return api.StatusErrorf(http.StatusConflict, "A backup target for that name already exists")
+	}
+
+	result, err := c.tx.ExecContext(ctx, "UPDATE backup_targets SET name=? WHERE name=?", newName, name)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n != 1 {
+		return api.StatusErrorf(http.StatusNotFound, "Backup target not found")
+	}
+
+	return nil
+}
+
+// DeleteBackupTarget removes a backup target.
+func (c *ClusterTx) DeleteBackupTarget(ctx context.Context, name string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM backup_targets WHERE name=?", name)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n != 1 {
+		return api.StatusErrorf(http.StatusNotFound, "Backup target not found")
+	}
+
+	return nil
+}
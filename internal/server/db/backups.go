@@ -25,6 +25,8 @@ type InstanceBackup struct {
 	InstanceOnly         bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Throttle             string
+	Checksum             string
 }
 
 // StoragePoolVolumeBackup is a value object holding all db-related details about a storage volume backup.
@@ -37,6 +39,8 @@ type StoragePoolVolumeBackup struct {
 	VolumeOnly           bool
 	OptimizedStorage     bool
 	CompressionAlgorithm string
+	Checksum             string
+	Throttle             string
 }
 
 // StoragePoolBucketBackup is a value object holding all db-related details about a storage bucket backup.
@@ -74,7 +78,8 @@ func (c *ClusterTx) GetInstanceBackup(ctx context.Context, projectName string, n
 	q := `
 SELECT instances_backups.id, instances_backups.instance_id,
        instances_backups.creation_date, instances_backups.expiry_date,
-       instances_backups.container_only, instances_backups.optimized_storage
+       instances_backups.container_only, instances_backups.optimized_storage,
+       instances_backups.checksum
     FROM instances_backups
     JOIN instances ON instances.id=instances_backups.instance_id
     JOIN projects ON projects.id=instances.project_id
@@ -84,6 +89,7 @@ SELECT instances_backups.id, instances_backups.instance_id,
 	arg2 := []any{
 		&args.ID, &args.InstanceID, &args.CreationDate,
 		&args.ExpiryDate, &instanceOnlyInt, &optimizedStorageInt,
+		&args.Checksum,
 	}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, arg2)
@@ -106,6 +112,21 @@ SELECT instances_backups.id, instances_backups.instance_id,
 	return args, nil
 }
 
+// UpdateInstanceBackupChecksum sets the checksum of the instance backup with the given name.
+func (c *ClusterTx) UpdateInstanceBackupChecksum(ctx context.Context, name string, checksum string) error {
+	id, err := c.getInstanceBackupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE instances_backups SET checksum=? WHERE id=?", checksum, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetInstanceBackupWithID returns the backup with the given ID.
 func (c *ClusterTx) GetInstanceBackupWithID(ctx context.Context, backupID int) (InstanceBackup, error) {
 	args := InstanceBackup{}
@@ -439,6 +460,21 @@ func (c *ClusterTx) CreateStoragePoolVolumeBackup(ctx context.Context, args Stor
 	return nil
 }
 
+// UpdateStoragePoolVolumeBackupChecksum sets the checksum of the storage volume backup with the given name.
+func (c *ClusterTx) UpdateStoragePoolVolumeBackupChecksum(ctx context.Context, name string, checksum string) error {
+	id, err := c.getStoragePoolVolumeBackupID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.ExecContext(ctx, "UPDATE storage_volumes_backups SET checksum=? WHERE id=?", checksum, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Returns the ID of the storage volume backup with the given name.
 func (c *ClusterTx) getStoragePoolVolumeBackupID(ctx context.Context, name string) (int, error) {
 	q := "SELECT id FROM storage_volumes_backups WHERE name=?"
@@ -480,14 +516,15 @@ SELECT
 	backups.creation_date,
 	backups.expiry_date,
 	backups.volume_only,
-	backups.optimized_storage
+	backups.optimized_storage,
+	backups.checksum
 FROM storage_volumes_backups AS backups
 JOIN storage_volumes ON storage_volumes.id=backups.storage_volume_id
 JOIN projects ON projects.id=storage_volumes.project_id
 WHERE projects.name=? AND backups.name=?
 `
 	arg1 := []any{projectName, backupName}
-	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage}
+	outfmt := []any{&args.ID, &args.VolumeID, &args.Name, &args.CreationDate, &args.ExpiryDate, &args.VolumeOnly, &args.OptimizedStorage, &args.Checksum}
 
 	err := dbQueryRowScan(ctx, c, q, arg1, outfmt)
 	if err != nil {
@@ -23,7 +23,22 @@ func (db *DB) UpdateCertificate(ctx context.Context, fingerprint string, cert cl
 			return err
 		}
 
-		return cluster.UpdateCertificateProjects(ctx, tx.Tx(), int(id), projectNames)
+		err = cluster.UpdateCertificateProjects(ctx, tx.Tx(), int(id), projectNames)
+		if err != nil {
+			return err
+		}
+
+		err = cluster.UpdateCertificateLabels(ctx, tx.Tx(), int(id), cert.Labels)
+		if err != nil {
+			return err
+		}
+
+		err = cluster.UpdateCertificateInstances(ctx, tx.Tx(), int(id), cert.Instances)
+		if err != nil {
+			return err
+		}
+
+		return cluster.UpdateCertificateNetworks(ctx, tx.Tx(), int(id), cert.AllowedNetworks)
 	})
 
 	return err
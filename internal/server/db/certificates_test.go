@@ -25,3 +25,19 @@ func TestGetCertificate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, cert.Fingerprint, "foobar")
 }
+
+func TestGetURIFromEntityCertificate(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	id, err := cluster.CreateCertificate(ctx, tx.Tx(), cluster.Certificate{Fingerprint: "foobar", Name: "foo"})
+	require.NoError(t, err)
+
+	uri, err := tx.GetURIFromEntity(ctx, cluster.TypeCertificate, int(id))
+	require.NoError(t, err)
+	assert.Equal(t, "/1.0/certificates/foobar", uri)
+
+	_, err = tx.GetURIFromEntity(ctx, cluster.TypeCertificate, int(id)+1)
+	assert.ErrorIs(t, err, db.ErrUnknownEntityID)
+}
@@ -0,0 +1,191 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AuthToken is an API token usable as a bearer token in place of a client certificate.
+// Only a hash of the token secret is stored.
+type AuthToken struct {
+	ID           int
+	Name         string
+	Description  string
+	SecretHash   string
+	Restricted   bool
+	CreationDate time.Time
+	ExpiryDate   sql.NullTime
+}
+
+// ToAPI converts the database AuthToken struct to an api.AuthToken entry filling the projects from the database.
+func (t *AuthToken) ToAPI(ctx context.Context, tx *sql.Tx) (*api.AuthToken, error) {
+	resp := api.AuthToken{}
+	resp.Name = t.Name
+	resp.Description = t.Description
+	resp.Restricted = t.Restricted
+	resp.CreatedAt = t.CreationDate
+
+	if t.ExpiryDate.Valid {
+		resp.ExpiresAt = t.ExpiryDate.Time
+	}
+
+	var err error
+
+	resp.Projects, err = GetAuthTokenProjects(ctx, tx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// getAuthTokens returns the API tokens matching the given where clause.
+func getAuthTokens(ctx context.Context, tx *sql.Tx, where string, args ...any) ([]AuthToken, error) {
+	tokens := []AuthToken{}
+
+	q := `SELECT id, name, description, secret_hash, restricted, creation_date, expiry_date FROM auth_tokens ` + where + ` ORDER BY name`
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		t := AuthToken{}
+
+		err := scan(&t.ID, &t.Name, &t.Description, &t.SecretHash, &t.Restricted, &t.CreationDate, &t.ExpiryDate)
+		if err != nil {
+			return err
+		}
+
+		tokens = append(tokens, t)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"auth_tokens\" table: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetAuthTokens returns all API tokens.
+func GetAuthTokens(ctx context.Context, tx *sql.Tx) ([]AuthToken, error) {
+	return getAuthTokens(ctx, tx, "")
+}
+
+// GetAuthToken returns the API token with the given name.
+func GetAuthToken(ctx context.Context, tx *sql.Tx, name string) (*AuthToken, error) {
+	tokens, err := getAuthTokens(ctx, tx, "WHERE name = ?", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, mapErr(ErrNotFound, "API token")
+	}
+
+	return &tokens[0], nil
+}
+
+// CreateAuthToken adds a new API token to the database.
+func CreateAuthToken(ctx context.Context, tx *sql.Tx, token AuthToken) (int64, error) {
+	result, err := tx.ExecContext(ctx, `INSERT INTO auth_tokens (name, description, secret_hash, restricted, creation_date, expiry_date) VALUES (?, ?, ?, ?, ?, ?)`, token.Name, token.Description, token.SecretHash, token.Restricted, token.CreationDate, token.ExpiryDate)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		return -1, mapErr(ErrConflict, "API token")
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("Failed to create \"auth_tokens\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"auth_tokens\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateAuthToken updates the description, restriction and expiry of the API token with the given name.
+func UpdateAuthToken(ctx context.Context, tx *sql.Tx, name string, token AuthToken) error {
+	result, err := tx.ExecContext(ctx, `UPDATE auth_tokens SET description = ?, restricted = ?, expiry_date = ? WHERE name = ?`, token.Description, token.Restricted, token.ExpiryDate, name)
+	if err != nil {
+		return fmt.Errorf("Failed to update \"auth_tokens\" entry: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return mapErr(ErrNotFound, "API token")
+	}
+
+	return nil
+}
+
+// DeleteAuthToken deletes the API token with the given name.
+func DeleteAuthToken(ctx context.Context, tx *sql.Tx, name string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM auth_tokens WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete \"auth_tokens\" entry: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return mapErr(ErrNotFound, "API token")
+	}
+
+	return nil
+}
+
+// GetAuthTokenProjects returns the names of the projects the API token is restricted to.
+func GetAuthTokenProjects(ctx context.Context, tx *sql.Tx, tokenID int) ([]string, error) {
+	q := `
+SELECT projects.name
+FROM auth_tokens_projects
+JOIN projects ON projects.id = auth_tokens_projects.project_id
+WHERE auth_tokens_projects.auth_token_id = ?
+ORDER BY projects.name
+`
+
+	projects, err := query.SelectStrings(ctx, tx, q, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch API token projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UpdateAuthTokenProjects replaces the projects the API token is restricted to.
+func UpdateAuthTokenProjects(ctx context.Context, tx *sql.Tx, tokenID int, projectNames []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM auth_tokens_projects WHERE auth_token_id = ?`, tokenID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete API token projects: %w", err)
+	}
+
+	for _, projectName := range projectNames {
+		projectID, err := GetProjectID(ctx, tx, projectName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO auth_tokens_projects (auth_token_id, project_id) VALUES (?, ?)`, tokenID, projectID)
+		if err != nil {
+			return fmt.Errorf("Failed to add project %q to API token: %w", projectName, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target blueprints.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e blueprint objects
+//generate-database:mapper stmt -e blueprint objects-by-ID
+//generate-database:mapper stmt -e blueprint objects-by-Name
+//generate-database:mapper stmt -e blueprint objects-by-Project
+//generate-database:mapper stmt -e blueprint objects-by-Project-and-Name
+//generate-database:mapper stmt -e blueprint id
+//generate-database:mapper stmt -e blueprint create
+//generate-database:mapper stmt -e blueprint rename
+//generate-database:mapper stmt -e blueprint update
+//generate-database:mapper stmt -e blueprint delete-by-Project-and-Name
+//
+//generate-database:mapper method -i -e blueprint ID
+//generate-database:mapper method -i -e blueprint Exists
+//generate-database:mapper method -i -e blueprint GetMany references=Config,Device
+//generate-database:mapper method -i -e blueprint GetOne
+//generate-database:mapper method -i -e blueprint Create references=Config,Device
+//generate-database:mapper method -i -e blueprint Rename
+//generate-database:mapper method -i -e blueprint Update references=Config,Device
+//generate-database:mapper method -i -e blueprint DeleteOne-by-Project-and-Name
+
+// Blueprint is a value object holding db-related details about a blueprint.
+type Blueprint struct {
+	ID          int
+	ProjectID   int                 `db:"omit=create,update"`
+	Project     string              `db:"primary=yes&join=projects.name"`
+	Name        string              `db:"primary=yes"`
+	Description string              `db:"coalesce=''"`
+	Version     int64               `db:"omit=create"`
+	Type        string              `db:"coalesce=''"`
+	Source      api.BlueprintSource `db:"marshal=json"`
+	Profiles    []string            `db:"marshal=json"`
+}
+
+// BlueprintFilter specifies potential query parameter fields.
+type BlueprintFilter struct {
+	ID      *int
+	Project *string
+	Name    *string
+}
+
+// ToAPI returns a cluster Blueprint as an API struct.
+func (b *Blueprint) ToAPI(ctx context.Context, tx *sql.Tx) (*api.Blueprint, error) {
+	config, err := GetBlueprintConfig(ctx, tx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := GetBlueprintDevices(ctx, tx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := GetBlueprintVersions(ctx, tx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := b.Profiles
+	if profiles == nil {
+		profiles = []string{}
+	}
+
+	blueprint := &api.Blueprint{
+		BlueprintPut: api.BlueprintPut{
+			Description: b.Description,
+			Type:        api.InstanceType(b.Type),
+			Source:      b.Source,
+			Profiles:    profiles,
+			Config:      config,
+			Devices:     DevicesToAPI(devices),
+		},
+		Name:     b.Name,
+		Version:  b.Version,
+		Versions: append(versions, b.Version),
+		Project:  b.Project,
+	}
+
+	return blueprint, nil
+}
+
+// GetBlueprintVersions returns the previous versions of the blueprint with the given ID which are retained, oldest first.
+func GetBlueprintVersions(ctx context.Context, tx *sql.Tx, blueprintID int) ([]int64, error) {
+	versions, err := query.SelectIntegers(ctx, tx, `SELECT version FROM blueprints_versions WHERE blueprint_id = ? ORDER BY version`, blueprintID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints_versions\" table: %w", err)
+	}
+
+	result := make([]int64, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, int64(version))
+	}
+
+	return result, nil
+}
+
+// GetBlueprintVersion returns the definition of a previous version of the blueprint with the given ID.
+func GetBlueprintVersion(ctx context.Context, tx *sql.Tx, blueprintID int, version int64) (*api.BlueprintPut, error) {
+	var definition string
+
+	err := tx.QueryRowContext(ctx, `SELECT definition FROM blueprints_versions WHERE blueprint_id = ? AND version = ?`, blueprintID, version).Scan(&definition)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Blueprint version not found")
+		}
+
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints_versions\" table: %w", err)
+	}
+
+	blueprint := api.BlueprintPut{}
+	err = json.Unmarshal([]byte(definition), &blueprint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse blueprint version %d: %w", version, err)
+	}
+
+	return &blueprint, nil
+}
+
+// CreateBlueprintVersion stores the definition of a version of the blueprint with the given ID.
+func CreateBlueprintVersion(ctx context.Context, tx *sql.Tx, blueprintID int, version int64, blueprint api.BlueprintPut) error {
+	definition, err := json.Marshal(blueprint)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO blueprints_versions (blueprint_id, version, definition) VALUES (?, ?, ?)`, blueprintID, version, string(definition))
+	if err != nil {
+		return fmt.Errorf("Failed to create \"blueprints_versions\" entry: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlueprintVersionsBefore deletes the versions of the blueprint with the given ID older than the given version.
+func DeleteBlueprintVersionsBefore(ctx context.Context, tx *sql.Tx, blueprintID int, version int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM blueprints_versions WHERE blueprint_id = ? AND version < ?`, blueprintID, version)
+	if err != nil {
+		return fmt.Errorf("Failed to delete \"blueprints_versions\" entries: %w", err)
+	}
+
+	return nil
+}
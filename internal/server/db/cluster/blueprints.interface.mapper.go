@@ -0,0 +1,64 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// BlueprintGenerated is an interface of generated methods for Blueprint.
+type BlueprintGenerated interface {
+	// GetBlueprintID return the ID of the blueprint with the given key.
+	// generator: blueprint ID
+	GetBlueprintID(ctx context.Context, db tx, project string, name string) (int64, error)
+
+	// BlueprintExists checks if a blueprint with the given key exists.
+	// generator: blueprint Exists
+	BlueprintExists(ctx context.Context, db dbtx, project string, name string) (bool, error)
+
+	// GetBlueprintConfig returns all available Blueprint Config
+	// generator: blueprint GetMany
+	GetBlueprintConfig(ctx context.Context, db tx, blueprintID int, filters ...ConfigFilter) (map[string]string, error)
+
+	// GetBlueprintDevices returns all available Blueprint Devices
+	// generator: blueprint GetMany
+	GetBlueprintDevices(ctx context.Context, db tx, blueprintID int, filters ...DeviceFilter) (map[string]Device, error)
+
+	// GetBlueprints returns all available blueprints.
+	// generator: blueprint GetMany
+	GetBlueprints(ctx context.Context, db dbtx, filters ...BlueprintFilter) ([]Blueprint, error)
+
+	// GetBlueprint returns the blueprint with the given key.
+	// generator: blueprint GetOne
+	GetBlueprint(ctx context.Context, db dbtx, project string, name string) (*Blueprint, error)
+
+	// CreateBlueprintConfig adds new blueprint Config to the database.
+	// generator: blueprint Create
+	CreateBlueprintConfig(ctx context.Context, db dbtx, blueprintID int64, config map[string]string) error
+
+	// CreateBlueprintDevices adds new blueprint Devices to the database.
+	// generator: blueprint Create
+	CreateBlueprintDevices(ctx context.Context, db tx, blueprintID int64, devices map[string]Device) error
+
+	// CreateBlueprint adds a new blueprint to the database.
+	// generator: blueprint Create
+	CreateBlueprint(ctx context.Context, db dbtx, object Blueprint) (int64, error)
+
+	// RenameBlueprint renames the blueprint matching the given key parameters.
+	// generator: blueprint Rename
+	RenameBlueprint(ctx context.Context, db dbtx, project string, name string, to string) error
+
+	// UpdateBlueprintConfig updates the blueprint Config matching the given key parameters.
+	// generator: blueprint Update
+	UpdateBlueprintConfig(ctx context.Context, db tx, blueprintID int64, config map[string]string) error
+
+	// UpdateBlueprintDevices updates the blueprint Device matching the given key parameters.
+	// generator: blueprint Update
+	UpdateBlueprintDevices(ctx context.Context, db tx, blueprintID int64, devices map[string]Device) error
+
+	// UpdateBlueprint updates the blueprint matching the given key parameters.
+	// generator: blueprint Update
+	UpdateBlueprint(ctx context.Context, db tx, project string, name string, object Blueprint) error
+
+	// DeleteBlueprint deletes the blueprint matching the given key parameters.
+	// generator: blueprint DeleteOne-by-Project-and-Name
+	DeleteBlueprint(ctx context.Context, db dbtx, project string, name string) error
+}
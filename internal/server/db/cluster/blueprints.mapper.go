@@ -0,0 +1,656 @@
+//go:build linux && cgo && !agent
+
+// Code generated by generate-database from the incus project - DO NOT EDIT.
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var blueprintObjects = RegisterStmt(`
+SELECT blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles
+  FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  ORDER BY projects.id, blueprints.name
+`)
+
+var blueprintObjectsByID = RegisterStmt(`
+SELECT blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles
+  FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  WHERE ( blueprints.id = ? )
+  ORDER BY projects.id, blueprints.name
+`)
+
+var blueprintObjectsByName = RegisterStmt(`
+SELECT blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles
+  FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  WHERE ( blueprints.name = ? )
+  ORDER BY projects.id, blueprints.name
+`)
+
+var blueprintObjectsByProject = RegisterStmt(`
+SELECT blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles
+  FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  WHERE ( project = ? )
+  ORDER BY projects.id, blueprints.name
+`)
+
+var blueprintObjectsByProjectAndName = RegisterStmt(`
+SELECT blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles
+  FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  WHERE ( project = ? AND blueprints.name = ? )
+  ORDER BY projects.id, blueprints.name
+`)
+
+var blueprintID = RegisterStmt(`
+SELECT blueprints.id FROM blueprints
+  JOIN projects ON blueprints.project_id = projects.id
+  WHERE projects.name = ? AND blueprints.name = ?
+`)
+
+var blueprintCreate = RegisterStmt(`
+INSERT INTO blueprints (project_id, name, description, type, source, profiles)
+  VALUES ((SELECT projects.id FROM projects WHERE projects.name = ?), ?, ?, ?, ?, ?)
+`)
+
+var blueprintRename = RegisterStmt(`
+UPDATE blueprints SET name = ? WHERE project_id = (SELECT projects.id FROM projects WHERE projects.name = ?) AND name = ?
+`)
+
+var blueprintUpdate = RegisterStmt(`
+UPDATE blueprints
+  SET project_id = (SELECT projects.id FROM projects WHERE projects.name = ?), name = ?, description = ?, version = ?, type = ?, source = ?, profiles = ?
+ WHERE id = ?
+`)
+
+var blueprintDeleteByProjectAndName = RegisterStmt(`
+DELETE FROM blueprints WHERE project_id = (SELECT projects.id FROM projects WHERE projects.name = ?) AND name = ?
+`)
+
+// GetBlueprintID return the ID of the blueprint with the given key.
+// generator: blueprint ID
+func GetBlueprintID(ctx context.Context, db tx, project string, name string) (_ int64, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	stmt, err := Stmt(db, blueprintID)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"blueprintID\" prepared statement: %w", err)
+	}
+
+	row := stmt.QueryRowContext(ctx, project, name)
+	var id int64
+	err = row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, ErrNotFound
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"blueprints\" ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// BlueprintExists checks if a blueprint with the given key exists.
+// generator: blueprint Exists
+func BlueprintExists(ctx context.Context, db dbtx, project string, name string) (_ bool, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	stmt, err := Stmt(db, blueprintID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to get \"blueprintID\" prepared statement: %w", err)
+	}
+
+	row := stmt.QueryRowContext(ctx, project, name)
+	var id int64
+	err = row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("Failed to get \"blueprints\" ID: %w", err)
+	}
+
+	return true, nil
+}
+
+// blueprintColumns returns a string of column names to be used with a SELECT statement for the entity.
+// Use this function when building statements to retrieve database entries matching the Blueprint entity.
+func blueprintColumns() string {
+	return "blueprints.id, blueprints.project_id, projects.name AS project, blueprints.name, coalesce(blueprints.description, ''), blueprints.version, coalesce(blueprints.type, ''), blueprints.source, blueprints.profiles"
+}
+
+// getBlueprints can be used to run handwritten sql.Stmts to return a slice of objects.
+func getBlueprints(ctx context.Context, stmt *sql.Stmt, args ...any) ([]Blueprint, error) {
+	objects := make([]Blueprint, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		b := Blueprint{}
+		var sourceStr string
+		var profilesStr string
+		err := scan(&b.ID, &b.ProjectID, &b.Project, &b.Name, &b.Description, &b.Version, &b.Type, &sourceStr, &profilesStr)
+		if err != nil {
+			return err
+		}
+
+		err = unmarshalJSON(sourceStr, &b.Source)
+		if err != nil {
+			return err
+		}
+
+		err = unmarshalJSON(profilesStr, &b.Profiles)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, b)
+
+		return nil
+	}
+
+	err := selectObjects(ctx, stmt, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// getBlueprintsRaw can be used to run handwritten query strings to return a slice of objects.
+func getBlueprintsRaw(ctx context.Context, db dbtx, sql string, args ...any) ([]Blueprint, error) {
+	objects := make([]Blueprint, 0)
+
+	dest := func(scan func(dest ...any) error) error {
+		b := Blueprint{}
+		var sourceStr string
+		var profilesStr string
+		err := scan(&b.ID, &b.ProjectID, &b.Project, &b.Name, &b.Description, &b.Version, &b.Type, &sourceStr, &profilesStr)
+		if err != nil {
+			return err
+		}
+
+		err = unmarshalJSON(sourceStr, &b.Source)
+		if err != nil {
+			return err
+		}
+
+		err = unmarshalJSON(profilesStr, &b.Profiles)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, b)
+
+		return nil
+	}
+
+	err := scan(ctx, db, sql, dest, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetBlueprints returns all available blueprints.
+// generator: blueprint GetMany
+func GetBlueprints(ctx context.Context, db dbtx, filters ...BlueprintFilter) (_ []Blueprint, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	var err error
+
+	// Result slice.
+	objects := make([]Blueprint, 0)
+
+	// Pick the prepared statement and arguments to use based on active criteria.
+	var sqlStmt *sql.Stmt
+	args := []any{}
+	queryParts := [2]string{}
+
+	if len(filters) == 0 {
+		sqlStmt, err = Stmt(db, blueprintObjects)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get \"blueprintObjects\" prepared statement: %w", err)
+		}
+	}
+
+	for i, filter := range filters {
+		if filter.Project != nil && filter.Name != nil && filter.ID == nil {
+			args = append(args, []any{filter.Project, filter.Name}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(db, blueprintObjectsByProjectAndName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"blueprintObjectsByProjectAndName\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(blueprintObjectsByProjectAndName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"blueprintObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.Project != nil && filter.ID == nil && filter.Name == nil {
+			args = append(args, []any{filter.Project}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(db, blueprintObjectsByProject)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"blueprintObjectsByProject\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(blueprintObjectsByProject)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"blueprintObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.Name != nil && filter.ID == nil && filter.Project == nil {
+			args = append(args, []any{filter.Name}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(db, blueprintObjectsByName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"blueprintObjectsByName\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(blueprintObjectsByName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"blueprintObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID != nil && filter.Project == nil && filter.Name == nil {
+			args = append(args, []any{filter.ID}...)
+			if len(filters) == 1 {
+				sqlStmt, err = Stmt(db, blueprintObjectsByID)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to get \"blueprintObjectsByID\" prepared statement: %w", err)
+				}
+
+				break
+			}
+
+			query, err := StmtString(blueprintObjectsByID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get \"blueprintObjects\" prepared statement: %w", err)
+			}
+
+			parts := strings.SplitN(query, "ORDER BY", 2)
+			if i == 0 {
+				copy(queryParts[:], parts)
+				continue
+			}
+
+			_, where, _ := strings.Cut(parts[0], "WHERE")
+			queryParts[0] += "OR" + where
+		} else if filter.ID == nil && filter.Project == nil && filter.Name == nil {
+			return nil, fmt.Errorf("Cannot filter on empty BlueprintFilter")
+		} else {
+			return nil, errors.New("No statement exists for the given Filter")
+		}
+	}
+
+	// Select.
+	if sqlStmt != nil {
+		objects, err = getBlueprints(ctx, sqlStmt, args...)
+	} else {
+		queryStr := strings.Join(queryParts[:], "ORDER BY")
+		objects, err = getBlueprintsRaw(ctx, db, queryStr, args...)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints\" table: %w", err)
+	}
+
+	return objects, nil
+}
+
+// GetBlueprintDevices returns all available Blueprint Devices
+// generator: blueprint GetMany
+func GetBlueprintDevices(ctx context.Context, db tx, blueprintID int, filters ...DeviceFilter) (_ map[string]Device, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	blueprintDevices, err := GetDevices(ctx, db, "blueprints", "blueprint", filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := map[string]Device{}
+	for _, ref := range blueprintDevices[blueprintID] {
+		_, ok := devices[ref.Name]
+		if !ok {
+			devices[ref.Name] = ref
+		} else {
+			return nil, fmt.Errorf("Found duplicate Device with name %q", ref.Name)
+		}
+	}
+
+	return devices, nil
+}
+
+// GetBlueprintConfig returns all available Blueprint Config
+// generator: blueprint GetMany
+func GetBlueprintConfig(ctx context.Context, db tx, blueprintID int, filters ...ConfigFilter) (_ map[string]string, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	blueprintConfig, err := GetConfig(ctx, db, "blueprints", "blueprint", filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	config, ok := blueprintConfig[blueprintID]
+	if !ok {
+		config = map[string]string{}
+	}
+
+	return config, nil
+}
+
+// GetBlueprint returns the blueprint with the given key.
+// generator: blueprint GetOne
+func GetBlueprint(ctx context.Context, db dbtx, project string, name string) (_ *Blueprint, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	filter := BlueprintFilter{}
+	filter.Project = &project
+	filter.Name = &name
+
+	objects, err := GetBlueprints(ctx, db, filter)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"blueprints\" table: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return &objects[0], nil
+	default:
+		return nil, fmt.Errorf("More than one \"blueprints\" entry matches")
+	}
+}
+
+// CreateBlueprint adds a new blueprint to the database.
+// generator: blueprint Create
+func CreateBlueprint(ctx context.Context, db dbtx, object Blueprint) (_ int64, _err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	args := make([]any, 6)
+
+	// Populate the statement arguments.
+	args[0] = object.Project
+	args[1] = object.Name
+	args[2] = object.Description
+	args[3] = object.Type
+	marshaledSource, err := marshalJSON(object.Source)
+	if err != nil {
+		return -1, err
+	}
+
+	args[4] = marshaledSource
+	marshaledProfiles, err := marshalJSON(object.Profiles)
+	if err != nil {
+		return -1, err
+	}
+
+	args[5] = marshaledProfiles
+
+	// Prepared statement to use.
+	stmt, err := Stmt(db, blueprintCreate)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get \"blueprintCreate\" prepared statement: %w", err)
+	}
+
+	// Execute the statement.
+	result, err := stmt.Exec(args...)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.Code == sqlite3.ErrConstraint {
+			return -1, ErrConflict
+		}
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("Failed to create \"blueprints\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"blueprints\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// CreateBlueprintDevices adds new blueprint Devices to the database.
+// generator: blueprint Create
+func CreateBlueprintDevices(ctx context.Context, db tx, blueprintID int64, devices map[string]Device) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	for key, device := range devices {
+		device.ReferenceID = int(blueprintID)
+		devices[key] = device
+	}
+
+	err := CreateDevices(ctx, db, "blueprints", "blueprint", devices)
+	if err != nil {
+		return fmt.Errorf("Insert Device failed for Blueprint: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBlueprintConfig adds new blueprint Config to the database.
+// generator: blueprint Create
+func CreateBlueprintConfig(ctx context.Context, db dbtx, blueprintID int64, config map[string]string) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	referenceID := int(blueprintID)
+	for key, value := range config {
+		insert := Config{
+			ReferenceID: referenceID,
+			Key:         key,
+			Value:       value,
+		}
+
+		err := CreateConfig(ctx, db, "blueprints", "blueprint", insert)
+		if err != nil {
+			return fmt.Errorf("Insert Config failed for Blueprint: %w", err)
+		}
+
+	}
+
+	return nil
+}
+
+// RenameBlueprint renames the blueprint matching the given key parameters.
+// generator: blueprint Rename
+func RenameBlueprint(ctx context.Context, db dbtx, project string, name string, to string) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	stmt, err := Stmt(db, blueprintRename)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"blueprintRename\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.Exec(to, project, name)
+	if err != nil {
+		return fmt.Errorf("Rename Blueprint failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows failed: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query affected %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// UpdateBlueprint updates the blueprint matching the given key parameters.
+// generator: blueprint Update
+func UpdateBlueprint(ctx context.Context, db tx, project string, name string, object Blueprint) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	id, err := GetBlueprintID(ctx, db, project, name)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := Stmt(db, blueprintUpdate)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"blueprintUpdate\" prepared statement: %w", err)
+	}
+
+	marshaledSource, err := marshalJSON(object.Source)
+	if err != nil {
+		return err
+	}
+
+	marshaledProfiles, err := marshalJSON(object.Profiles)
+	if err != nil {
+		return err
+	}
+
+	result, err := stmt.Exec(object.Project, object.Name, object.Description, object.Version, object.Type, marshaledSource, marshaledProfiles, id)
+	if err != nil {
+		return fmt.Errorf("Update \"blueprints\" entry failed: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n != 1 {
+		return fmt.Errorf("Query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// UpdateBlueprintDevices updates the blueprint Device matching the given key parameters.
+// generator: blueprint Update
+func UpdateBlueprintDevices(ctx context.Context, db tx, blueprintID int64, devices map[string]Device) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	err := UpdateDevices(ctx, db, "blueprints", "blueprint", int(blueprintID), devices)
+	if err != nil {
+		return fmt.Errorf("Replace Device for Blueprint failed: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateBlueprintConfig updates the blueprint Config matching the given key parameters.
+// generator: blueprint Update
+func UpdateBlueprintConfig(ctx context.Context, db tx, blueprintID int64, config map[string]string) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	err := UpdateConfig(ctx, db, "blueprints", "blueprint", int(blueprintID), config)
+	if err != nil {
+		return fmt.Errorf("Replace Config for Blueprint failed: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlueprint deletes the blueprint matching the given key parameters.
+// generator: blueprint DeleteOne-by-Project-and-Name
+func DeleteBlueprint(ctx context.Context, db dbtx, project string, name string) (_err error) {
+	defer func() {
+		_err = mapErr(_err, "Blueprint")
+	}()
+
+	stmt, err := Stmt(db, blueprintDeleteByProjectAndName)
+	if err != nil {
+		return fmt.Errorf("Failed to get \"blueprintDeleteByProjectAndName\" prepared statement: %w", err)
+	}
+
+	result, err := stmt.Exec(project, name)
+	if err != nil {
+		return fmt.Errorf("Delete \"blueprints\": %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return ErrNotFound
+	} else if n > 1 {
+		return fmt.Errorf("Query deleted %d Blueprint rows instead of 1", n)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CertificateGroup is a named set of trusted certificates sharing the same project restrictions.
+type CertificateGroup struct {
+	ID          int
+	Name        string
+	Description string
+	Restricted  bool
+}
+
+// ToAPI converts the database CertificateGroup struct to an api.CertificateGroup
+// entry filling the projects and member certificates from the database.
+func (g *CertificateGroup) ToAPI(ctx context.Context, tx *sql.Tx) (*api.CertificateGroup, error) {
+	resp := api.CertificateGroup{}
+	resp.Name = g.Name
+	resp.Description = g.Description
+	resp.Restricted = g.Restricted
+
+	var err error
+
+	resp.Projects, err = GetCertificateGroupProjects(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Certificates, err = GetCertificateGroupCertificates(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// getCertificateGroups returns the certificate groups matching the given where clause.
+func getCertificateGroups(ctx context.Context, tx *sql.Tx, where string, args ...any) ([]CertificateGroup, error) {
+	groups := []CertificateGroup{}
+
+	q := `SELECT id, name, description, restricted FROM certificates_groups ` + where + ` ORDER BY name`
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		g := CertificateGroup{}
+
+		err := scan(&g.ID, &g.Name, &g.Description, &g.Restricted)
+		if err != nil {
+			return err
+		}
+
+		groups = append(groups, g)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"certificates_groups\" table: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetCertificateGroups returns all certificate groups.
+func GetCertificateGroups(ctx context.Context, tx *sql.Tx) ([]CertificateGroup, error) {
+	return getCertificateGroups(ctx, tx, "")
+}
+
+// GetCertificateGroup returns the certificate group with the given name.
+func GetCertificateGroup(ctx context.Context, tx *sql.Tx, name string) (*CertificateGroup, error) {
+	groups, err := getCertificateGroups(ctx, tx, "WHERE name = ?", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 {
+		return nil, mapErr(ErrNotFound, "Certificate group")
+	}
+
+	return &groups[0], nil
+}
+
+// CreateCertificateGroup adds a new certificate group to the database.
+func CreateCertificateGroup(ctx context.Context, tx *sql.Tx, group CertificateGroup) (int64, error) {
+	result, err := tx.ExecContext(ctx, `INSERT INTO certificates_groups (name, description, restricted) VALUES (?, ?, ?)`, group.Name, group.Description, group.Restricted)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		return -1, mapErr(ErrConflict, "Certificate group")
+	}
+
+	if err != nil {
+		return -1, fmt.Errorf("Failed to create \"certificates_groups\" entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to fetch \"certificates_groups\" entry ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdateCertificateGroup updates the description and restriction of the certificate group with the given name.
+func UpdateCertificateGroup(ctx context.Context, tx *sql.Tx, name string, group CertificateGroup) error {
+	result, err := tx.ExecContext(ctx, `UPDATE certificates_groups SET description = ?, restricted = ? WHERE name = ?`, group.Description, group.Restricted, name)
+	if err != nil {
+		return fmt.Errorf("Failed to update \"certificates_groups\" entry: %w", err)
+	}
+
+	return checkCertificateGroupAffected(result)
+}
+
+// RenameCertificateGroup renames the certificate group with the given name.
+func RenameCertificateGroup(ctx context.Context, tx *sql.Tx, name string, to string) error {
+	result, err := tx.ExecContext(ctx, `UPDATE certificates_groups SET name = ? WHERE name = ?`, to, name)
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		return mapErr(ErrConflict, "Certificate group")
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to rename \"certificates_groups\" entry: %w", err)
+	}
+
+	return checkCertificateGroupAffected(result)
+}
+
+// DeleteCertificateGroup deletes the certificate group with the given name.
+func DeleteCertificateGroup(ctx context.Context, tx *sql.Tx, name string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM certificates_groups WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete \"certificates_groups\" entry: %w", err)
+	}
+
+	return checkCertificateGroupAffected(result)
+}
+
+// checkCertificateGroupAffected returns a not found error if no certificate group was affected by a query.
+func checkCertificateGroupAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to fetch affected rows: %w", err)
+	}
+
+	if n == 0 {
+		return mapErr(ErrNotFound, "Certificate group")
+	}
+
+	return nil
+}
+
+// GetCertificateGroupProjects returns the names of the projects the certificate group is restricted to.
+func GetCertificateGroupProjects(ctx context.Context, tx *sql.Tx, groupID int) ([]string, error) {
+	q := `
+SELECT projects.name
+FROM certificates_groups_projects
+JOIN projects ON projects.id = certificates_groups_projects.project_id
+WHERE certificates_groups_projects.certificate_group_id = ?
+ORDER BY projects.name
+`
+
+	projects, err := query.SelectStrings(ctx, tx, q, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch certificate group projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UpdateCertificateGroupProjects replaces the projects the certificate group is restricted to.
+func UpdateCertificateGroupProjects(ctx context.Context, tx *sql.Tx, groupID int, projectNames []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM certificates_groups_projects WHERE certificate_group_id = ?`, groupID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete certificate group projects: %w", err)
+	}
+
+	for _, projectName := range projectNames {
+		projectID, err := GetProjectID(ctx, tx, projectName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO certificates_groups_projects (certificate_group_id, project_id) VALUES (?, ?)`, groupID, projectID)
+		if err != nil {
+			return fmt.Errorf("Failed to add project %q to certificate group: %w", projectName, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCertificateGroupCertificates returns the fingerprints of the certificates in the certificate group.
+func GetCertificateGroupCertificates(ctx context.Context, tx *sql.Tx, groupID int) ([]string, error) {
+	q := `
+SELECT certificates.fingerprint
+FROM certificates_groups_certificates
+JOIN certificates ON certificates.id = certificates_groups_certificates.certificate_id
+WHERE certificates_groups_certificates.certificate_group_id = ?
+ORDER BY certificates.fingerprint
+`
+
+	fingerprints, err := query.SelectStrings(ctx, tx, q, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch certificate group members: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
+// UpdateCertificateGroupCertificates replaces the certificates in the certificate group.
+// Fingerprints may be given in their short form as long as they are unambiguous.
+func UpdateCertificateGroupCertificates(ctx context.Context, tx *sql.Tx, groupID int, fingerprints []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM certificates_groups_certificates WHERE certificate_group_id = ?`, groupID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete certificate group members: %w", err)
+	}
+
+	for _, fingerprint := range fingerprints {
+		cert, err := GetCertificateByFingerprintPrefix(ctx, tx, fingerprint)
+		if err != nil {
+			return fmt.Errorf("Failed to load certificate %q: %w", fingerprint, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO certificates_groups_certificates (certificate_group_id, certificate_id) VALUES (?, ?)`, groupID, cert.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to add certificate %q to certificate group: %w", fingerprint, err)
+		}
+	}
+
+	return nil
+}
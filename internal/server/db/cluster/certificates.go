@@ -4,10 +4,13 @@ package cluster
 
 import (
 	"context"
+	"crypto/x509"
 	"database/sql"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/internal/server/db/query"
@@ -39,13 +42,17 @@ import (
 
 // Certificate is here to pass the certificates content from the database around.
 type Certificate struct {
-	ID          int
-	Fingerprint string `db:"primary=yes"`
-	Type        certificate.Type
-	Name        string
-	Certificate string
-	Restricted  bool
-	Description string
+	ID              int
+	Fingerprint     string `db:"primary=yes"`
+	Type            certificate.Type
+	Name            string
+	Certificate     string
+	Restricted      bool
+	Description     string
+	LastUseDate     sql.NullTime      `db:"omit=create,update"`
+	Labels          map[string]string `db:"ignore"`
+	Instances       []string          `db:"ignore"`
+	AllowedNetworks []string          `db:"ignore"`
 }
 
 // CertificateFilter specifies potential query parameter fields.
@@ -65,6 +72,10 @@ func (cert *Certificate) ToAPIType() string {
 		return api.CertificateTypeServer
 	case certificate.TypeMetrics:
 		return api.CertificateTypeMetrics
+	case certificate.TypeSSH:
+		return api.CertificateTypeSSH
+	case certificate.TypeCA:
+		return api.CertificateTypeCA
 	}
 
 	return api.CertificateTypeUnknown
@@ -80,6 +91,15 @@ func (cert *Certificate) ToAPI(ctx context.Context, tx *sql.Tx) (*api.Certificat
 	resp.Restricted = cert.Restricted
 	resp.Type = cert.ToAPIType()
 	resp.Description = cert.Description
+	resp.LastUsedAt = cert.LastUseDate.Time
+
+	certBlock, _ := pem.Decode([]byte(cert.Certificate))
+	if certBlock != nil {
+		x509Cert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err == nil {
+			resp.ExpiresAt = x509Cert.NotAfter
+		}
+	}
 
 	projects, err := GetCertificateProjects(ctx, tx, cert.ID)
 	if err != nil {
@@ -91,6 +111,21 @@ func (cert *Certificate) ToAPI(ctx context.Context, tx *sql.Tx) (*api.Certificat
 		resp.Projects[i] = p.Name
 	}
 
+	resp.Labels, err = GetCertificateLabels(ctx, tx, cert.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Instances, err = GetCertificateInstances(ctx, tx, cert.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.AllowedNetworks, err = GetCertificateNetworks(ctx, tx, cert.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &resp, nil
 }
 
@@ -145,5 +180,128 @@ func CreateCertificateWithProjects(ctx context.Context, tx *sql.Tx, cert Certifi
 		return -1, err
 	}
 
+	err = UpdateCertificateLabels(ctx, tx, int(id), cert.Labels)
+	if err != nil {
+		return -1, err
+	}
+
+	err = UpdateCertificateInstances(ctx, tx, int(id), cert.Instances)
+	if err != nil {
+		return -1, err
+	}
+
+	err = UpdateCertificateNetworks(ctx, tx, int(id), cert.AllowedNetworks)
+	if err != nil {
+		return -1, err
+	}
+
 	return id, err
 }
+
+// GetCertificateLabels returns the labels of the certificate with the given ID.
+func GetCertificateLabels(ctx context.Context, tx *sql.Tx, certificateID int) (map[string]string, error) {
+	labels := map[string]string{}
+
+	q := `SELECT key, value FROM certificates_labels WHERE certificate_id = ?`
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		var key, value string
+
+		err := scan(&key, &value)
+		if err != nil {
+			return err
+		}
+
+		labels[key] = value
+
+		return nil
+	}, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"certificates_labels\" table: %w", err)
+	}
+
+	return labels, nil
+}
+
+// UpdateCertificateLabels replaces the labels of the certificate with the given ID.
+func UpdateCertificateLabels(ctx context.Context, tx *sql.Tx, certificateID int, labels map[string]string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM certificates_labels WHERE certificate_id = ?`, certificateID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete certificate labels: %w", err)
+	}
+
+	for key, value := range labels {
+		_, err = tx.ExecContext(ctx, `INSERT INTO certificates_labels (certificate_id, key, value) VALUES (?, ?, ?)`, certificateID, key, value)
+		if err != nil {
+			return fmt.Errorf("Failed to add label %q to certificate: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCertificateInstances returns the instance name patterns the certificate with the given ID is restricted to.
+func GetCertificateInstances(ctx context.Context, tx *sql.Tx, certificateID int) ([]string, error) {
+	q := `SELECT pattern FROM certificates_instances WHERE certificate_id = ? ORDER BY id`
+	patterns, err := query.SelectStrings(ctx, tx, q, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"certificates_instances\" table: %w", err)
+	}
+
+	return patterns, nil
+}
+
+// UpdateCertificateInstances replaces the instance name patterns of the certificate with the given ID.
+func UpdateCertificateInstances(ctx context.Context, tx *sql.Tx, certificateID int, patterns []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM certificates_instances WHERE certificate_id = ?`, certificateID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete certificate instance patterns: %w", err)
+	}
+
+	for _, pattern := range patterns {
+		_, err = tx.ExecContext(ctx, `INSERT INTO certificates_instances (certificate_id, pattern) VALUES (?, ?)`, certificateID, pattern)
+		if err != nil {
+			return fmt.Errorf("Failed to add instance pattern %q to certificate: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCertificateNetworks returns the networks the certificate with the given ID can be used from.
+func GetCertificateNetworks(ctx context.Context, tx *sql.Tx, certificateID int) ([]string, error) {
+	q := `SELECT network FROM certificates_networks WHERE certificate_id = ? ORDER BY id`
+	networks, err := query.SelectStrings(ctx, tx, q, certificateID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch from \"certificates_networks\" table: %w", err)
+	}
+
+	return networks, nil
+}
+
+// UpdateCertificateNetworks replaces the networks the certificate with the given ID can be used from.
+func UpdateCertificateNetworks(ctx context.Context, tx *sql.Tx, certificateID int, networks []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM certificates_networks WHERE certificate_id = ?`, certificateID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete certificate networks: %w", err)
+	}
+
+	for _, network := range networks {
+		_, err = tx.ExecContext(ctx, `INSERT INTO certificates_networks (certificate_id, network) VALUES (?, ?)`, certificateID, network)
+		if err != nil {
+			return fmt.Errorf("Failed to add network %q to certificate: %w", network, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateCertificateLastUseDate records the last use of the certificate with the given fingerprint.
+// Older dates are ignored, so that members of a cluster can record uses in any order.
+func UpdateCertificateLastUseDate(ctx context.Context, tx *sql.Tx, fingerprint string, date time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE certificates SET last_use_date = ? WHERE fingerprint = ? AND (last_use_date IS NULL OR last_use_date < ?)`, date, fingerprint, date)
+	if err != nil {
+		return fmt.Errorf("Failed to update certificate last use date: %w", err)
+	}
+
+	return nil
+}
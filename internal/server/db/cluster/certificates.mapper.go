@@ -16,20 +16,20 @@ import (
 )
 
 var certificateObjects = RegisterStmt(`
-SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description
+SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description, certificates.last_use_date
   FROM certificates
   ORDER BY certificates.fingerprint
 `)
 
 var certificateObjectsByID = RegisterStmt(`
-SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description
+SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description, certificates.last_use_date
   FROM certificates
   WHERE ( certificates.id = ? )
   ORDER BY certificates.fingerprint
 `)
 
 var certificateObjectsByFingerprint = RegisterStmt(`
-SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description
+SELECT certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description, certificates.last_use_date
   FROM certificates
   WHERE ( certificates.fingerprint = ? )
   ORDER BY certificates.fingerprint
@@ -62,7 +62,7 @@ UPDATE certificates
 // certificateColumns returns a string of column names to be used with a SELECT statement for the entity.
 // Use this function when building statements to retrieve database entries matching the Certificate entity.
 func certificateColumns() string {
-	return "certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description"
+	return "certificates.id, certificates.fingerprint, certificates.type, certificates.name, certificates.certificate, certificates.restricted, certificates.description, certificates.last_use_date"
 }
 
 // getCertificates can be used to run handwritten sql.Stmts to return a slice of objects.
@@ -71,7 +71,7 @@ func getCertificates(ctx context.Context, stmt *sql.Stmt, args ...any) ([]Certif
 
 	dest := func(scan func(dest ...any) error) error {
 		c := Certificate{}
-		err := scan(&c.ID, &c.Fingerprint, &c.Type, &c.Name, &c.Certificate, &c.Restricted, &c.Description)
+		err := scan(&c.ID, &c.Fingerprint, &c.Type, &c.Name, &c.Certificate, &c.Restricted, &c.Description, &c.LastUseDate)
 		if err != nil {
 			return err
 		}
@@ -95,7 +95,7 @@ func getCertificatesRaw(ctx context.Context, db dbtx, sql string, args ...any) (
 
 	dest := func(scan func(dest ...any) error) error {
 		c := Certificate{}
-		err := scan(&c.ID, &c.Fingerprint, &c.Type, &c.Name, &c.Certificate, &c.Restricted, &c.Description)
+		err := scan(&c.ID, &c.Fingerprint, &c.Type, &c.Name, &c.Certificate, &c.Restricted, &c.Description, &c.LastUseDate)
 		if err != nil {
 			return err
 		}
@@ -6,6 +6,101 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE "auth_tokens" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    secret_hash TEXT NOT NULL,
+    restricted INTEGER NOT NULL DEFAULT 0,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME,
+    UNIQUE (name),
+    UNIQUE (secret_hash)
+);
+CREATE TABLE "auth_tokens_projects" (
+    auth_token_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_token_id) REFERENCES "auth_tokens" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (auth_token_id, project_id)
+);
+CREATE TABLE "backup_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    instance TEXT NOT NULL DEFAULT "",
+    creation_date DATETIME NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE TABLE "backup_groups_volumes" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    backup_group_id INTEGER NOT NULL,
+    storage_pool TEXT NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (backup_group_id, storage_pool, name),
+    FOREIGN KEY (backup_group_id) REFERENCES "backup_groups" (id) ON DELETE CASCADE
+);
+CREATE TABLE "backup_targets" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+CREATE TABLE "backup_targets_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    backup_target_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (backup_target_id, key),
+    FOREIGN KEY (backup_target_id) REFERENCES "backup_targets" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    version INTEGER NOT NULL DEFAULT 1,
+    type TEXT NOT NULL DEFAULT "",
+    source TEXT NOT NULL,
+    profiles TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (blueprint_id, key),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_devices" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    type INTEGER NOT NULL default 0,
+    UNIQUE (blueprint_id, name),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_devices_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_device_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (blueprint_device_id, key),
+    FOREIGN KEY (blueprint_device_id) REFERENCES "blueprints_devices" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_versions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (blueprint_id, version),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
 CREATE TABLE certificates (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     fingerprint TEXT NOT NULL,
@@ -14,8 +109,52 @@ CREATE TABLE certificates (
     certificate TEXT NOT NULL,
     restricted INTEGER NOT NULL DEFAULT 0,
     description TEXT NOT NULL DEFAULT "",
+    last_use_date DATETIME,
     UNIQUE (fingerprint)
 );
+CREATE TABLE "certificates_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    restricted INTEGER NOT NULL DEFAULT 1,
+    UNIQUE (name)
+);
+CREATE TABLE "certificates_groups_certificates" (
+    certificate_group_id INTEGER NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    FOREIGN KEY (certificate_group_id) REFERENCES "certificates_groups" (id) ON DELETE CASCADE,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_group_id, certificate_id)
+);
+CREATE TABLE "certificates_groups_projects" (
+    certificate_group_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (certificate_group_id) REFERENCES "certificates_groups" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (certificate_group_id, project_id)
+);
+CREATE TABLE "certificates_instances" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, pattern)
+);
+CREATE TABLE "certificates_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, key)
+);
+CREATE TABLE "certificates_networks" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    network TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, network)
+);
 CREATE TABLE "certificates_projects" (
     certificate_id INTEGER NOT NULL,
     project_id INTEGER NOT NULL,
@@ -130,6 +269,7 @@ CREATE TABLE "instances_backups" (
     expiry_date DATETIME,
     container_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    checksum TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (instance_id) REFERENCES "instances" (id) ON DELETE CASCADE,
     UNIQUE (instance_id, name)
 );
@@ -609,6 +749,7 @@ CREATE TABLE "storage_volumes_backups" (
     expiry_date DATETIME,
     volume_only INTEGER NOT NULL default 0,
     optimized_storage INTEGER NOT NULL default 0,
+    checksum TEXT NOT NULL DEFAULT "",
     FOREIGN KEY (storage_volume_id) REFERENCES "storage_volumes" (id) ON DELETE CASCADE,
     UNIQUE (storage_volume_id, name)
 );
@@ -654,6 +795,21 @@ CREATE TABLE "storage_volumes_snapshots_config" (
     UNIQUE (storage_volume_snapshot_id, key)
 );
 CREATE UNIQUE INDEX storage_volumes_unique_storage_pool_id_node_id_project_id_name_type ON "storage_volumes" (storage_pool_id, IFNULL(node_id, -1), project_id, name, type);
+CREATE TABLE "trash" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    storage_pool TEXT NOT NULL,
+    config TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
 CREATE TABLE "warnings" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     node_id INTEGER,
@@ -674,5 +830,5 @@ CREATE TABLE "warnings" (
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
 
-INSERT INTO schema (version, updated_at) VALUES (76, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (87, strftime("%s"))
 `
@@ -113,6 +113,306 @@ var updates = map[int]schema.Update{
 	74: updateFromV73,
 	75: updateFromV74,
 	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+	84: updateFromV83,
+	85: updateFromV84,
+	86: updateFromV85,
+	87: updateFromV86,
+}
+
+// updateFromV86 adds the backup groups tables.
+func updateFromV86(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "backup_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    instance TEXT NOT NULL DEFAULT "",
+    creation_date DATETIME NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+CREATE TABLE "backup_groups_volumes" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    backup_group_id INTEGER NOT NULL,
+    storage_pool TEXT NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (backup_group_id, storage_pool, name),
+    FOREIGN KEY (backup_group_id) REFERENCES "backup_groups" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating backup groups tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV85 adds the blueprints tables.
+func updateFromV85(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "blueprints" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    version INTEGER NOT NULL DEFAULT 1,
+    type TEXT NOT NULL DEFAULT "",
+    source TEXT NOT NULL,
+    profiles TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (blueprint_id, key),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_devices" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    type INTEGER NOT NULL default 0,
+    UNIQUE (blueprint_id, name),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_devices_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_device_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (blueprint_device_id, key),
+    FOREIGN KEY (blueprint_device_id) REFERENCES "blueprints_devices" (id) ON DELETE CASCADE
+);
+CREATE TABLE "blueprints_versions" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    blueprint_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    definition TEXT NOT NULL,
+    UNIQUE (blueprint_id, version),
+    FOREIGN KEY (blueprint_id) REFERENCES "blueprints" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating blueprints tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV84 adds the backup targets tables.
+func updateFromV84(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "backup_targets" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    UNIQUE (name)
+);
+CREATE TABLE "backup_targets_config" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    backup_target_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (backup_target_id, key),
+    FOREIGN KEY (backup_target_id) REFERENCES "backup_targets" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating backup targets tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV83 adds the trash table.
+func updateFromV83(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "trash" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    uuid TEXT NOT NULL,
+    project_id INTEGER NOT NULL,
+    node_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    storage_pool TEXT NOT NULL,
+    config TEXT NOT NULL,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (uuid),
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    FOREIGN KEY (node_id) REFERENCES "nodes" (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating trash table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV82 adds the checksum of instance and custom volume backups.
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE storage_volumes_backups ADD COLUMN checksum TEXT NOT NULL DEFAULT "";`)
+	if err != nil {
+		return fmt.Errorf("Failed adding checksum column to storage_volumes_backups table: %w", err)
+	}
+
+	_, err = tx.Exec(`ALTER TABLE instances_backups ADD COLUMN checksum TEXT NOT NULL DEFAULT "";`)
+	if err != nil {
+		return fmt.Errorf("Failed adding checksum column to instances_backups table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV81 adds the last use date of certificates.
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE certificates ADD COLUMN last_use_date DATETIME;`)
+	if err != nil {
+		return fmt.Errorf("Failed adding last_use_date column to certificates table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV80 adds the certificate allowed networks table.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "certificates_networks" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    network TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, network)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating certificate networks table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds the certificate instance patterns table.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "certificates_instances" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, pattern)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating certificate instances table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV78 adds the certificate labels table.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "certificates_labels" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_id, key)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating certificate labels table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds the API tokens tables.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "auth_tokens" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    secret_hash TEXT NOT NULL,
+    restricted INTEGER NOT NULL DEFAULT 0,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME,
+    UNIQUE (name),
+    UNIQUE (secret_hash)
+);
+
+CREATE TABLE "auth_tokens_projects" (
+    auth_token_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_token_id) REFERENCES "auth_tokens" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (auth_token_id, project_id)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating API tokens tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds the certificate groups tables.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE "certificates_groups" (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT "",
+    restricted INTEGER NOT NULL DEFAULT 1,
+    UNIQUE (name)
+);
+
+CREATE TABLE "certificates_groups_certificates" (
+    certificate_group_id INTEGER NOT NULL,
+    certificate_id INTEGER NOT NULL,
+    FOREIGN KEY (certificate_group_id) REFERENCES "certificates_groups" (id) ON DELETE CASCADE,
+    FOREIGN KEY (certificate_id) REFERENCES certificates (id) ON DELETE CASCADE,
+    UNIQUE (certificate_group_id, certificate_id)
+);
+
+CREATE TABLE "certificates_groups_projects" (
+    certificate_group_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (certificate_group_id) REFERENCES "certificates_groups" (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE,
+    UNIQUE (certificate_group_id, project_id)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed creating certificate groups tables: %w", err)
+	}
+
+	return nil
 }
 
 func updateFromV75(ctx context.Context, tx *sql.Tx) error {
@@ -89,7 +89,7 @@ func (c *ClusterTx) GetURIFromEntity(ctx context.Context, entityType int, entity
 				continue
 			}
 
-			uri = fmt.Sprintf(cluster.EntityURIs[entityType], cert.Name)
+			uri = fmt.Sprintf(cluster.EntityURIs[entityType], cert.Fingerprint)
 			break
 		}
 
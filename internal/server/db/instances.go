@@ -18,6 +18,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db/query"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 )
 
@@ -1014,24 +1015,12 @@ ORDER BY instances_snapshots.creation_date, instances_snapshots.id
 		return 0
 	}
 
-	max := 0
-
+	names := make([]string, 0, len(results))
 	for _, r := range results {
-		snapOnlyName := r[0].(string)
-		fields := strings.SplitN(pattern, "%d", 2)
-
-		var num int
-		count, err := fmt.Sscanf(snapOnlyName, fmt.Sprintf("%s%%d%s", fields[0], fields[1]), &num)
-		if err != nil || count != 1 {
-			continue
-		}
-
-		if num >= max {
-			max = num + 1
-		}
+		names = append(names, r[0].(string))
 	}
 
-	return max
+	return internalUtil.NamePatternNextIndex(pattern, names)
 }
 
 // DeleteReadyStateFromLocalInstances deletes the volatile.last_state.ready config key
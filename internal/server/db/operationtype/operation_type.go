@@ -79,6 +79,14 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	BackupGroupCreate
+	BackupGroupRestore
+	BackupToken
+	CustomVolumeBackupToken
+	TrashRestore
+	TrashPrune
+	InstanceTimeSync
+	ClusterRebalance
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +218,22 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case BackupGroupCreate:
+		return "Creating backup group"
+	case BackupGroupRestore:
+		return "Restoring backup group"
+	case BackupToken:
+		return "Instance backup download token"
+	case CustomVolumeBackupToken:
+		return "Custom volume backup download token"
+	case TrashRestore:
+		return "Restoring from trash"
+	case TrashPrune:
+		return "Pruning expired trash entries"
+	case InstanceTimeSync:
+		return "Synchronizing instance clock"
+	case ClusterRebalance:
+		return "Rebalancing cluster"
 	default:
 		return "Executing operation"
 	}
@@ -300,6 +324,12 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
 	case BucketBackupRestore:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
+	case BackupToken:
+		return auth.ObjectTypeInstance, auth.EntitlementCanManageBackups
+	case CustomVolumeBackupToken:
+		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
+	case InstanceTimeSync:
+		return auth.ObjectTypeInstance, auth.EntitlementCanUpdateState
 
 	default:
 		return "", ""
@@ -43,6 +43,14 @@ storage_pools_config JOIN storage_pools ON storage_pools.id=storage_pools_config
 	return pools, nil
 }
 
+// GetStoragePoolMemberConfig returns the member-specific configuration of the given storage pool on the cluster
+// member with the given name.
+func (c *ClusterTx) GetStoragePoolMemberConfig(ctx context.Context, poolID int64, memberName string) (map[string]string, error) {
+	table := "storage_pools_config JOIN nodes ON nodes.id=storage_pools_config.node_id"
+
+	return query.SelectConfig(ctx, c.tx, table, "storage_pools_config.storage_pool_id=? AND nodes.name=?", poolID, memberName)
+}
+
 // GetStoragePoolID returns the ID of the pool with the given name.
 func (c *ClusterTx) GetStoragePoolID(ctx context.Context, name string) (int64, error) {
 	stmt := "SELECT id FROM storage_pools WHERE name=?"
@@ -885,6 +893,7 @@ var NodeSpecificStorageConfig = []string{
 	"lvm.thinpool_name",
 	"lvm.vg_name",
 	"lvm.vg.force_reuse",
+	"ceph.client_addresses",
 }
 
 // IsRemoteStorage return whether a given pool is backed by remote storage.
@@ -14,6 +14,7 @@ import (
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/db/query"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -721,24 +722,12 @@ SELECT storage_volumes_snapshots.name FROM storage_volumes_snapshots
 		return 0
 	}
 
-	max := 0
-
+	names := make([]string, 0, len(results))
 	for _, r := range results {
-		substr := r[0].(string)
-		fields := strings.SplitN(pattern, "%d", 2)
-
-		var num int
-		count, err := fmt.Sscanf(substr, fmt.Sprintf("%s%%d%s", fields[0], fields[1]), &num)
-		if err != nil || count != 1 {
-			continue
-		}
-
-		if num >= max {
-			max = num + 1
-		}
+		names = append(names, r[0].(string))
 	}
 
-	return max
+	return internalUtil.NamePatternNextIndex(pattern, names)
 }
 
 // Updates the description of a storage volume.
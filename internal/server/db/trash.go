@@ -0,0 +1,140 @@
+//go:build linux && cgo && !agent
+
+package db
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// TrashEntry is a value object holding all db-related details about a deleted instance or custom
+// storage volume kept in the trash.
+type TrashEntry struct {
+	ID           int64
+	UUID         string
+	Project      string
+	Node         string
+	Type         string
+	Name         string
+	StoragePool  string
+	Config       string
+	CreationDate time.Time
+	ExpiryDate   time.Time
+}
+
+// ToAPI converts the trash entry to its API representation.
+func (e TrashEntry) ToAPI() api.TrashEntry {
+	return api.TrashEntry{
+		UUID:      e.UUID,
+		Type:      e.Type,
+		Name:      e.Name,
+		Project:   e.Project,
+		Pool:      e.StoragePool,
+		Location:  e.Node,
+		DeletedAt: e.CreationDate,
+		ExpiresAt: e.ExpiryDate,
+	}
+}
+
+const trashEntriesQuery = `
+SELECT trash.id, trash.uuid, projects.name, nodes.name, trash.type, trash.name, trash.storage_pool, trash.config, trash.creation_date, trash.expiry_date
+    FROM trash
+    JOIN projects ON projects.id=trash.project_id
+    JOIN nodes ON nodes.id=trash.node_id
+`
+
+// getTrashEntries runs the trash entries query with the given extra clause.
+func (c *ClusterTx) getTrashEntries(ctx context.Context, where string, args ...any) ([]TrashEntry, error) {
+	var entries []TrashEntry
+
+	q := trashEntriesQuery + where + " ORDER BY trash.id"
+
+	err := query.Scan(ctx, c.tx, q, func(scan func(dest ...any) error) error {
+		var e TrashEntry
+
+		err := scan(&e.ID, &e.UUID, &e.Project, &e.Node, &e.Type, &e.Name, &e.StoragePool, &e.Config, &e.CreationDate, &e.ExpiryDate)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, e)
+
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetTrashEntries returns all the entries in the trash.
+func (c *ClusterTx) GetTrashEntries(ctx context.Context) ([]TrashEntry, error) {
+	return c.getTrashEntries(ctx, "")
+}
+
+// GetLocalTrashEntries returns the entries in the trash which are stored on this member.
+func (c *ClusterTx) GetLocalTrashEntries(ctx context.Context) ([]TrashEntry, error) {
+	return c.getTrashEntries(ctx, "WHERE trash.node_id=?", c.nodeID)
+}
+
+// GetProjectTrashEntries returns the entries in the trash which belong to the given project.
+func (c *ClusterTx) GetProjectTrashEntries(ctx context.Context, projectName string) ([]TrashEntry, error) {
+	return c.getTrashEntries(ctx, "WHERE projects.name=?", projectName)
+}
+
+// GetStoragePoolTrashEntries returns the entries in the trash which are kept on the given storage pool.
+func (c *ClusterTx) GetStoragePoolTrashEntries(ctx context.Context, poolName string) ([]TrashEntry, error) {
+	return c.getTrashEntries(ctx, "WHERE trash.storage_pool=?", poolName)
+}
+
+// GetTrashEntry returns the trash entry with the given UUID.
+func (c *ClusterTx) GetTrashEntry(ctx context.Context, uuid string) (*TrashEntry, error) {
+	entries, err := c.getTrashEntries(ctx, "WHERE trash.uuid=?", uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Trash entry not found")
+	}
+
+	return &entries[0], nil
+}
+
+// CreateTrashEntry records a new entry in the trash, stored on this member.
+func (c *ClusterTx) CreateTrashEntry(ctx context.Context, entry TrashEntry) error {
+	q := `
+INSERT INTO trash (uuid, project_id, node_id, type, name, storage_pool, config, creation_date, expiry_date)
+    VALUES (?, (SELECT id FROM projects WHERE name=?), ?, ?, ?, ?, ?, ?, ?)
+`
+	_, err := c.tx.ExecContext(ctx, q, entry.UUID, entry.Project, c.nodeID, entry.Type, entry.Name, entry.StoragePool, entry.Config, entry.CreationDate, entry.ExpiryDate)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteTrashEntry removes the trash entry with the given UUID.
+func (c *ClusterTx) DeleteTrashEntry(ctx context.Context, uuid string) error {
+	result, err := c.tx.ExecContext(ctx, "DELETE FROM trash WHERE uuid=?", uuid)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Trash entry not found")
+	}
+
+	return nil
+}
@@ -56,6 +56,8 @@ const (
 	StoragePoolUnvailable
 	// UnableToUpdateClusterCertificate represents the unable to update cluster certificate warning.
 	UnableToUpdateClusterCertificate
+	// CertificateExpiring represents a trusted certificate which is about to expire.
+	CertificateExpiring
 )
 
 // TypeNames associates a warning code to its name.
@@ -84,6 +86,7 @@ var TypeNames = map[Type]string{
 	InstanceTypeNotOperational:        "Instance type not operational",
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
+	CertificateExpiring:               "Trusted certificate is about to expire",
 }
 
 // Severity returns the severity of the warning type.
@@ -137,6 +140,8 @@ func (t Type) Severity() Severity {
 		return SeverityHigh
 	case UnableToUpdateClusterCertificate:
 		return SeverityLow
+	case CertificateExpiring:
+		return SeverityModerate
 	}
 
 	return SeverityLow
@@ -29,6 +29,9 @@ type USBEvent struct {
 
 	BusNum int
 	DevNum int
+
+	// Port is the physical location of the device on the USB topology (e.g. 1-2.3).
+	Port string
 }
 
 // usbHandlers stores the event handler callbacks for USB events.
@@ -98,7 +101,7 @@ func USBRunHandlers(state *state.State, event *USBEvent) {
 }
 
 // USBNewEvent instantiates a new USBEvent struct.
-func USBNewEvent(action string, vendor string, product string, serial string, major string, minor string, busnum string, devnum string, devname string, ueventParts []string, ueventLen int) (USBEvent, error) {
+func USBNewEvent(action string, vendor string, product string, serial string, major string, minor string, busnum string, devnum string, devname string, port string, ueventParts []string, ueventLen int) (USBEvent, error) {
 	majorInt, err := strconv.ParseUint(major, 10, 32)
 	if err != nil {
 		return USBEvent{}, err
@@ -140,5 +143,6 @@ func USBNewEvent(action string, vendor string, product string, serial string, ma
 		ueventLen,
 		busnumInt,
 		devnumInt,
+		port,
 	}, nil
 }
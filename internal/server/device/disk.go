@@ -515,6 +515,11 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 					return errors.New("Cannot add un-shared custom storage block volume to more than one instance")
 				}
 			}
+
+			// Check that volumes shared in read-only mode are only attached read-only.
+			if dbVolume.Config["security.shared"] == "readonly" && util.IsFalseOrEmpty(d.config["readonly"]) {
+				return fmt.Errorf("Custom storage volume %q is shared read-only and must be attached with readonly=true", volName)
+			}
 		}
 
 		// Only perform expensive instance pool volume checks when not validating a profile and after
@@ -644,6 +649,11 @@ func (d *disk) validateConfig(instConf instance.ConfigReader) error {
 		}
 	}
 
+	// Only block devices can be booted from.
+	if instConf.Type() == instancetype.VM && d.config["boot.priority"] != "" && d.config["path"] != "" && d.config["path"] != "/" {
+		return errors.New("The boot.priority property can't be set on shared filesystem disks")
+	}
+
 	// Restrict disks allowed when live-migratable.
 	if instConf.Type() == instancetype.VM && util.IsTrue(instConf.ExpandedConfig()["migration.stateful"]) {
 		if d.config["path"] != "" && d.config["path"] != "/" {
@@ -1043,15 +1053,16 @@ func (d *disk) startVM() (*deviceConfig.RunConfig, error) {
 		opts = append(opts, fmt.Sprintf("cache=%s", d.config["io.cache"]))
 	}
 
-	// Add I/O limits if set.
+	// Add I/O limits if set (either on the device or on the attached custom volume).
 	var diskLimits *deviceConfig.DiskLimits
-	if d.config["limits.read"] != "" || d.config["limits.write"] != "" || d.config["limits.max"] != "" {
-		// Parse the limits into usable values.
-		readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
-		if err != nil {
-			return nil, err
-		}
 
+	// Parse the limits into usable values.
+	readBps, readIops, writeBps, writeIops, err := d.parseLimit(d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if readBps > 0 || readIops > 0 || writeBps > 0 || writeIops > 0 {
 		diskLimits = &deviceConfig.DiskLimits{
 			ReadBytes:  readBps,
 			ReadIOps:   readIops,
@@ -1610,7 +1621,12 @@ func (d *disk) generateLimits(runConf *deviceConfig.RunConfig) error {
 			continue
 		}
 
-		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" {
+		dev, err := d.volumeLimits(dev)
+		if err != nil {
+			return err
+		}
+
+		if dev["limits.read"] != "" || dev["limits.write"] != "" || dev["limits.max"] != "" || dev["limits.max.iops"] != "" {
 			hasDiskLimits = true
 		}
 	}
@@ -2397,8 +2413,53 @@ func (d *disk) getDiskLimits() (map[string]diskBlockLimit, error) {
 	return result, nil
 }
 
+// volumeLimits returns the device configuration with the I/O limits of the attached custom volume applied
+// to the limits which aren't set on the device itself.
+func (d *disk) volumeLimits(dev deviceConfig.Device) (deviceConfig.Device, error) {
+	// Only custom volumes can carry their own limits and the device wide limit takes precedence over them.
+	if dev["pool"] == "" || dev["source"] == "" || internalInstance.IsRootDiskDevice(dev) || dev["limits.max"] != "" {
+		return dev, nil
+	}
+
+	storageProjectName, err := project.StorageVolumeProject(d.state.DB.Cluster, d.inst.Project().Name, db.StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	volName := strings.SplitN(dev["source"], "/", 2)[0]
+
+	var dbVolume *db.StorageVolume
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, dev["pool"])
+		if err != nil {
+			return err
+		}
+
+		dbVolume, err = tx.GetStoragePoolVolume(ctx, poolID, storageProjectName, db.StoragePoolVolumeTypeCustom, volName, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading custom storage volume %q on storage pool %q: %w", volName, dev["pool"], err)
+	}
+
+	newDev := dev.Clone()
+	for _, key := range []string{"limits.read", "limits.write", "limits.max.iops"} {
+		if newDev[key] == "" && dbVolume.Config[key] != "" {
+			newDev[key] = dbVolume.Config[key]
+		}
+	}
+
+	return newDev, nil
+}
+
 // parseLimit parses the disk configuration for its I/O limits and returns the I/O bytes/iops limits.
 func (d *disk) parseLimit(dev deviceConfig.Device) (int64, int64, int64, int64, error) {
+	// Apply the limits of the attached custom volume.
+	dev, err := d.volumeLimits(dev)
+	if err != nil {
+		return -1, -1, -1, -1, err
+	}
+
 	readSpeed := dev["limits.read"]
 	writeSpeed := dev["limits.write"]
 
@@ -2446,6 +2507,22 @@ func (d *disk) parseLimit(dev deviceConfig.Device) (int64, int64, int64, int64,
 		return -1, -1, -1, -1, err
 	}
 
+	// Cap the IOPS to the custom volume limit.
+	if dev["limits.max.iops"] != "" {
+		maxIops, err := strconv.ParseInt(dev["limits.max.iops"], 10, 64)
+		if err != nil {
+			return -1, -1, -1, -1, err
+		}
+
+		if readIops == 0 || readIops > maxIops {
+			readIops = maxIops
+		}
+
+		if writeIops == 0 || writeIops > maxIops {
+			writeIops = maxIops
+		}
+	}
+
 	return readBps, readIops, writeBps, writeIops, nil
 }
 
@@ -138,13 +138,9 @@ func (d *gpuMdev) startVM() (*deviceConfig.RunConfig, error) {
 			}
 
 			reverter.Add(func() {
-				path := fmt.Sprintf("/sys/bus/mdev/devices/%s", mdevUUID)
-
-				if util.PathExists(path) {
-					err := os.WriteFile(filepath.Join(path, "remove"), []byte("1\n"), 0o200)
-					if err != nil {
-						d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": mdevUUID, "err": err})
-					}
+				err := gpuMdevRemove(mdevUUID)
+				if err != nil {
+					d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": mdevUUID, "err": err})
 				}
 			})
 		}
@@ -197,19 +193,65 @@ func (d *gpuMdev) postStop() error {
 	v := d.volatileGet()
 
 	if v["vgpu.uuid"] != "" {
-		path := fmt.Sprintf("/sys/bus/mdev/devices/%s", v["vgpu.uuid"])
-
-		if util.PathExists(path) {
-			err := os.WriteFile(filepath.Join(path, "remove"), []byte("1\n"), 0o200)
-			if err != nil {
-				d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": v["vgpu.uuid"], "err": err})
-			}
+		err := gpuMdevRemove(v["vgpu.uuid"])
+		if err != nil {
+			d.logger.Error("Failed to remove vgpu", logger.Ctx{"device": v["vgpu.uuid"], "err": err})
 		}
 	}
 
 	return nil
 }
 
+// gpuMdevRemove removes the mediated device with the given UUID if it still exists.
+func gpuMdevRemove(mdevUUID string) error {
+	path := fmt.Sprintf("/sys/bus/mdev/devices/%s", mdevUUID)
+	if !util.PathExists(path) {
+		return nil
+	}
+
+	return os.WriteFile(filepath.Join(path, "remove"), []byte("1\n"), 0o200)
+}
+
+// GPUMdevCleanup removes any mediated device left behind by a stopped instance.
+// This covers the case where the instance stopped while the daemon wasn't running and so the
+// device's post-stop hook never ran.
+func GPUMdevCleanup(inst instance.Instance) error {
+	if inst.Type() != instancetype.VM || inst.IsRunning() {
+		return nil
+	}
+
+	gpuMdevMu.Lock()
+	defer gpuMdevMu.Unlock()
+
+	localConfig := inst.LocalConfig()
+	changes := map[string]string{}
+	for _, dev := range inst.ExpandedDevices().Sorted() {
+		if dev.Config["type"] != "gpu" || dev.Config["gputype"] != "mdev" {
+			continue
+		}
+
+		key := fmt.Sprintf("volatile.%s.vgpu.uuid", dev.Name)
+		if localConfig[key] == "" {
+			continue
+		}
+
+		err := gpuMdevRemove(localConfig[key])
+		if err != nil {
+			return fmt.Errorf("Failed to remove vgpu %q: %w", localConfig[key], err)
+		}
+
+		changes[key] = ""
+		changes[fmt.Sprintf("volatile.%s.last_state.pci.slot.name", dev.Name)] = ""
+		changes[fmt.Sprintf("volatile.%s.last_state.pci.driver", dev.Name)] = ""
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return inst.VolatileSet(changes)
+}
+
 // validateConfig checks the supplied config for correctness.
 func (d *gpuMdev) validateConfig(instConf instance.ConfigReader) error {
 	if !instanceSupported(instConf.Type(), instancetype.VM) {
@@ -77,7 +77,7 @@ func (d *nicOVN) UpdatableFields(oldDevice Type) []string {
 		return []string{}
 	}
 
-	return []string{"security.acls"}
+	return []string{"security.acls", "limits.ingress", "limits.egress", "limits.max"}
 }
 
 // validateConfig checks the supplied config for correctness.
@@ -197,6 +197,30 @@ func (d *nicOVN) validateConfig(instConf instance.ConfigReader) error {
 		//  shortdesc: Comma-delimited list of IPv6 static routes to route to the NIC and publish on uplink network
 		"ipv6.routes.external",
 
+		// gendoc:generate(entity=devices, group=nic_ovn, key=limits.ingress)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: I/O limit in bit/s for incoming traffic (various suffixes supported, see {ref}instances-limit-units)
+		"limits.ingress",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=limits.egress)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: I/O limit in bit/s for outgoing traffic (various suffixes supported, see {ref}instances-limit-units)
+		"limits.egress",
+
+		// gendoc:generate(entity=devices, group=nic_ovn, key=limits.max)
+		//
+		// ---
+		//  type: string
+		//  managed: no
+		//  shortdesc: I/O limit in bit/s for both incoming and outgoing traffic (same as setting both limits.ingress and limits.egress)
+		"limits.max",
+
 		// gendoc:generate(entity=devices, group=nic_ovn, key=boot.priority)
 		//
 		// ---
@@ -988,12 +1012,23 @@ func (d *nicOVN) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 		}
 	}
 
+	// Check whether the logical switch port needs reconfiguring.
+	var removedACLs []string
+	portChanged := false
+	for _, key := range []string{"limits.ingress", "limits.egress", "limits.max"} {
+		if d.config[key] != oldConfig[key] {
+			portChanged = true
+			break
+		}
+	}
+
 	// Apply any changes needed when assigned ACLs change.
 	if d.config["security.acls"] != oldConfig["security.acls"] {
+		portChanged = true
+
 		// Work out which ACLs have been removed and remove logical port from those groups.
 		oldACLs := util.SplitNTrimSpace(oldConfig["security.acls"], ",", -1, true)
 		newACLs := util.SplitNTrimSpace(d.config["security.acls"], ",", -1, true)
-		removedACLs := []string{}
 		for _, oldACL := range oldACLs {
 			if !slices.Contains(newACLs, oldACL) {
 				removedACLs = append(removedACLs, oldACL)
@@ -1005,52 +1040,55 @@ func (d *nicOVN) Update(oldDevices deviceConfig.Devices, isRunning bool) error {
 		if err != nil {
 			return fmt.Errorf("Failed removing unused OVN address sets: %w", err)
 		}
+	}
 
-		// Setup the logical port with new ACLs if running.
-		if isRunning {
-			// Load uplink network config.
-			uplinkNetworkName := d.network.Config()["network"]
-			var uplink *api.Network
-			var uplinkConfig map[string]string
-
-			if uplinkNetworkName != "none" {
-				err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-					var err error
-
-					_, uplink, _, err = tx.GetNetworkInAnyState(ctx, api.ProjectDefaultName, uplinkNetworkName)
+	// Setup the logical port with the new ACLs and limits if running.
+	if portChanged && isRunning {
+		// Load uplink network config.
+		uplinkNetworkName := d.network.Config()["network"]
+		var uplink *api.Network
+		var uplinkConfig map[string]string
 
-					return err
-				})
-				if err != nil {
-					return fmt.Errorf("Failed to load uplink network %q: %w", uplinkNetworkName, err)
-				}
+		if uplinkNetworkName != "none" {
+			err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				var err error
 
-				uplinkConfig = uplink.Config
-			}
+				_, uplink, _, err = tx.GetNetworkInAnyState(ctx, api.ProjectDefaultName, uplinkNetworkName)
 
-			// Update OVN logical switch port for instance.
-			_, _, err := d.network.InstanceDevicePortStart(&network.OVNInstanceNICSetupOpts{
-				InstanceUUID: d.inst.LocalConfig()["volatile.uuid"],
-				DNSName:      d.inst.Name(),
-				DeviceName:   d.name,
-				DeviceConfig: d.config,
-				UplinkConfig: uplinkConfig,
-			}, removedACLs)
+				return err
+			})
 			if err != nil {
-				return fmt.Errorf("Failed updating OVN port: %w", err)
+				return fmt.Errorf("Failed to load uplink network %q: %w", uplinkNetworkName, err)
 			}
+
+			uplinkConfig = uplink.Config
 		}
 
-		if len(removedACLs) > 0 {
-			err := addressset.OVNDeleteAddressSetsViaACLs(d.state, d.logger, d.ovnnb, d.network.Project(), removedACLs)
-			if err != nil {
-				return fmt.Errorf("Failed removing unused OVN address sets: %w", err)
-			}
+		// Update OVN logical switch port for instance.
+		_, _, err := d.network.InstanceDevicePortStart(&network.OVNInstanceNICSetupOpts{
+			InstanceUUID: d.inst.LocalConfig()["volatile.uuid"],
+			DNSName:      d.inst.Name(),
+			DeviceName:   d.name,
+			DeviceConfig: d.config,
+			UplinkConfig: uplinkConfig,
+		}, removedACLs)
+		if err != nil {
+			return fmt.Errorf("Failed updating OVN port: %w", err)
+		}
+	}
 
-			err = acl.OVNPortGroupDeleteIfUnused(d.state, d.logger, d.ovnnb, d.network.Project(), d.inst, d.name, newACLs...)
-			if err != nil {
-				return fmt.Errorf("Failed removing unused OVN port groups: %w", err)
-			}
+	// Clean up after any removed ACLs.
+	if len(removedACLs) > 0 {
+		newACLs := util.SplitNTrimSpace(d.config["security.acls"], ",", -1, true)
+
+		err := addressset.OVNDeleteAddressSetsViaACLs(d.state, d.logger, d.ovnnb, d.network.Project(), removedACLs)
+		if err != nil {
+			return fmt.Errorf("Failed removing unused OVN address sets: %w", err)
+		}
+
+		err = acl.OVNPortGroupDeleteIfUnused(d.state, d.logger, d.ovnnb, d.network.Project(), d.inst, d.name, newACLs...)
+		if err != nil {
+			return fmt.Errorf("Failed removing unused OVN port groups: %w", err)
 		}
 	}
 
@@ -2,11 +2,13 @@ package device
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -221,7 +223,16 @@ func (d *tpm) startVM() (*deviceConfig.RunConfig, error) {
 	// Delete any leftover socket.
 	_ = os.Remove(socketPath)
 
-	proc, err := subprocess.NewProcess("swtpm", []string{"socket", "--tpm2", "--tpmstate", fmt.Sprintf("dir=%s", tpmDevPath), "--ctrl", fmt.Sprintf("type=unixio,path=swtpm-%s.sock", d.name)}, "", "")
+	args := []string{"socket", "--tpm2", "--tpmstate", fmt.Sprintf("dir=%s", tpmDevPath), "--ctrl", fmt.Sprintf("type=unixio,path=swtpm-%s.sock", d.name)}
+
+	// The TPM state is transferred by QEMU as part of the live migration stream.
+	// Release the lock on the state directory once it has been handed over so the emulator on the
+	// target can take ownership of it when the instance's config volume is on shared storage.
+	if util.IsTrue(d.inst.ExpandedConfig()["migration.stateful"]) && tpmHasCapability("cmdarg-migration") {
+		args = append(args, "--migration", "release-lock-outgoing")
+	}
+
+	proc, err := subprocess.NewProcess("swtpm", args, "", "")
 	if err != nil {
 		return nil, err
 	}
@@ -304,3 +315,22 @@ func (d *tpm) Remove() error {
 
 	return os.RemoveAll(tpmDevPath)
 }
+
+// tpmHasCapability returns whether the TPM emulator advertises the given capability.
+func tpmHasCapability(capability string) bool {
+	out, err := subprocess.RunCommand("swtpm", "socket", "--print-capabilities")
+	if err != nil {
+		return false
+	}
+
+	var caps struct {
+		Features []string `json:"features"`
+	}
+
+	err = json.Unmarshal([]byte(out), &caps)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(caps.Features, capability)
+}
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"regexp"
 	"strings"
 
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
@@ -19,6 +20,18 @@ import (
 // usbDevPath is the path where USB devices can be enumerated.
 const usbDevPath = "/sys/bus/usb/devices"
 
+// usbPortRegex matches the physical port of a USB device (bus number followed by the port chain).
+var usbPortRegex = regexp.MustCompile(`^[0-9]+-[0-9]+(\.[0-9]+)*$`)
+
+// usbValidatePort checks that the value is a valid USB port.
+func usbValidatePort(value string) error {
+	if !usbPortRegex.MatchString(value) {
+		return fmt.Errorf("Invalid USB port %q", value)
+	}
+
+	return nil
+}
+
 // usbIsOurDevice indicates whether the USB device event qualifies as part of our device.
 // This function is not defined against the usb struct type so that it can be used in event
 // callbacks without needing to keep a reference to the usb device struct.
@@ -28,7 +41,8 @@ func usbIsOurDevice(config deviceConfig.Device, usb *USBEvent) bool {
 		(config["productid"] != "" && config["productid"] != usb.Product) ||
 		(config["serial"] != "" && config["serial"] != usb.Serial) ||
 		(config["busnum"] != "" && config["busnum"] != fmt.Sprintf("%d", usb.BusNum)) ||
-		(config["devnum"] != "" && config["devnum"] != fmt.Sprintf("%d", usb.DevNum)) {
+		(config["devnum"] != "" && config["devnum"] != fmt.Sprintf("%d", usb.DevNum)) ||
+		(config["port"] != "" && config["port"] != usb.Port) {
 		return false
 	}
 
@@ -122,6 +136,13 @@ func (d *usb) validateConfig(instConf instance.ConfigReader) error {
 		//  type: int
 		//  shortdesc: The device number of the USB device
 		"devnum": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=devices, group=usb, key=port)
+		// Unlike the device number, the port remains the same when the device is unplugged and plugged back in.
+		// ---
+		//  type: string
+		//  shortdesc: The physical port the USB device is plugged into (for example, `1-2.3`)
+		"port": validate.Optional(usbValidatePort),
 	}
 
 	err := d.config.Validate(rules)
@@ -273,10 +294,10 @@ func (d *usb) Stop() (*deviceConfig.RunConfig, error) {
 		}
 	}
 
-	if d.inst.Type() == instancetype.Container {
-		// Unregister any USB event handlers for this device.
-		usbUnregisterHandler(d.inst, d.name)
+	// Unregister any USB event handlers for this device.
+	usbUnregisterHandler(d.inst, d.name)
 
+	if d.inst.Type() == instancetype.Container {
 		err := unixDeviceRemove(d.inst.DevicesPath(), "unix", d.name, "", &runConf)
 		if err != nil {
 			return nil, err
@@ -337,6 +358,7 @@ func (d *usb) loadUsb() ([]USBEvent, error) {
 			values["busnum"],
 			values["devnum"],
 			values["devname"],
+			ent.Name(),
 			[]string{},
 			0,
 		)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"os"
@@ -27,6 +28,7 @@ import (
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/device/nictype"
 	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/drivers/qemudefault"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
@@ -41,6 +43,7 @@ import (
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -50,6 +53,23 @@ var (
 	muInstancesLastRestart sync.Mutex
 )
 
+// Track restart attempts made through boot.restart_policy.
+var (
+	instancesRestartAttempts   = map[int]instanceRestartAttempts{}
+	muInstancesRestartAttempts sync.Mutex
+)
+
+type instanceRestartAttempts struct {
+	count int
+	last  time.Time
+}
+
+// restartPolicyMaxDelay is the longest delay applied between two restarts through boot.restart_policy.
+const restartPolicyMaxDelay = 5 * time.Minute
+
+// restartPolicyResetInterval is how long an instance needs to run after a restart for its attempts to be reset.
+const restartPolicyResetInterval = 10 * time.Minute
+
 // ErrExecCommandNotFound indicates the command is not found.
 var ErrExecCommandNotFound = api.StatusErrorf(http.StatusBadRequest, "Command not found")
 
@@ -62,6 +82,9 @@ var ErrInstanceIsStopped error = api.StatusErrorf(http.StatusBadRequest, "The in
 // muNUMA is used to serialize NUMA node selection.
 var muNUMA sync.Mutex
 
+// muCPUIsolation is used to serialize the reservation of isolated CPU cores.
+var muCPUIsolation sync.Mutex
+
 // deviceManager is an interface that allows managing device lifecycle.
 type deviceManager interface {
 	deviceAdd(dev device.Device, instanceRunning bool) error
@@ -199,6 +222,83 @@ func (d *common) shouldAutoRestart() bool {
 	return false
 }
 
+// shouldRestart returns whether an instance which stopped on its own should be started again and how long to
+// wait before doing so. It applies boot.restart_policy when set and falls back to boot.autorestart otherwise.
+func (d *common) shouldRestart(failure bool) (bool, time.Duration) {
+	policy := d.expandedConfig["boot.restart_policy"]
+	if policy == "" {
+		return d.shouldAutoRestart(), 0
+	}
+
+	if policy == "never" || (policy == "on-failure" && !failure) {
+		return false, 0
+	}
+
+	maxRetries := 10
+	if d.expandedConfig["boot.restart_policy.max_retries"] != "" {
+		maxRetries, _ = strconv.Atoi(d.expandedConfig["boot.restart_policy.max_retries"])
+	}
+
+	muInstancesRestartAttempts.Lock()
+	defer muInstancesRestartAttempts.Unlock()
+
+	// Consider the instance healthy again if it kept running long enough since its last restart.
+	attempts := instancesRestartAttempts[d.id]
+	if !attempts.last.IsZero() && time.Since(attempts.last) > restartPolicyResetInterval {
+		attempts.count = 0
+	}
+
+	if maxRetries > 0 && attempts.count >= maxRetries {
+		d.logger.Warn("Not restarting instance as the maximum number of restart attempts was reached", logger.Ctx{"attempts": attempts.count})
+		return false, 0
+	}
+
+	// Exponential backoff starting at one second.
+	delay := min(time.Second<<min(attempts.count, 10), restartPolicyMaxDelay)
+
+	attempts.count++
+	attempts.last = time.Now().Add(delay)
+	instancesRestartAttempts[d.id] = attempts
+
+	return true, delay
+}
+
+// scheduleRestart starts the instance again once the delay has passed, unless it was started in the meantime.
+func (d *common) scheduleRestart(delay time.Duration) {
+	s := d.state
+	projectName := d.project.Name
+	instanceName := d.name
+	l := d.logger
+
+	l.Info("Scheduling instance restart", logger.Ctx{"delay": delay})
+
+	go func() {
+		select {
+		case <-time.After(delay):
+		case <-s.ShutdownCtx.Done():
+			return
+		}
+
+		inst, err := instance.LoadByProjectAndName(s, projectName, instanceName)
+		if err != nil {
+			l.Warn("Failed loading instance for scheduled restart", logger.Ctx{"err": err})
+			return
+		}
+
+		if inst.IsRunning() {
+			return
+		}
+
+		err = inst.Start(false)
+		if err != nil {
+			l.Error("Failed restarting instance", logger.Ctx{"err": err})
+			return
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceRestarted.Event(inst, nil))
+	}()
+}
+
 // ID gets instances's ID.
 func (d *common) ID() int {
 	return d.id
@@ -771,6 +871,102 @@ func (d *common) rebuildCommon(inst instance.Instance, img *api.Image, op *opera
 	return nil
 }
 
+// rebuildFromBackupCommon handles the common part of instance rebuilds from a backup.
+// Only the instance volume is restored, the instance keeps its own configuration and devices.
+func (d *common) rebuildFromBackupCommon(inst instance.Instance, srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	if srcBackup.Config == nil || srcBackup.Config.Container == nil {
+		return errors.New("Backup file is missing required information")
+	}
+
+	if srcBackup.Type != "" && string(srcBackup.Type) != inst.Type().String() {
+		return fmt.Errorf("Backup type %q doesn't match instance type %q", srcBackup.Type, inst.Type())
+	}
+
+	pool, err := d.getStoragePool()
+	if err != nil {
+		return err
+	}
+
+	if srcBackup.OptimizedStorage != nil && *srcBackup.OptimizedStorage {
+		if pool.Driver().Info().Name != srcBackup.Backend {
+			return fmt.Errorf("Optimized backup storage driver %q differs from the instance storage pool driver %q", srcBackup.Backend, pool.Driver().Info().Name)
+		}
+
+		// Optimized backups store the instance volume relative to its snapshots.
+		if len(srcBackup.Snapshots) > 0 {
+			return errors.New("Optimized backups including snapshots can't be used to rebuild an instance")
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	instLocalConfig := d.localConfig
+
+	// Replace the image keys and the idmap with those the backed up volume was created with.
+	for k := range instLocalConfig {
+		if strings.HasPrefix(k, "image.") {
+			delete(instLocalConfig, k)
+		}
+	}
+
+	delete(instLocalConfig, "volatile.base_image")
+	delete(instLocalConfig, "volatile.idmap.next")
+	delete(instLocalConfig, "volatile.last_state.idmap")
+
+	for k, v := range srcBackup.Config.Container.Config {
+		if strings.HasPrefix(k, "image.") || slices.Contains([]string{"volatile.base_image", "volatile.last_state.idmap"}, k) {
+			instLocalConfig[k] = v
+		}
+	}
+
+	instLocalConfig["volatile.uuid.generation"] = uuid.New().String()
+
+	// Only restore the instance volume, leaving out any snapshots included in the backup.
+	backupConfig := *srcBackup.Config
+	backupConfig.Snapshots = nil
+	backupConfig.VolumeSnapshots = nil
+
+	srcBackup.Project = inst.Project().Name
+	srcBackup.Name = inst.Name()
+	srcBackup.Pool = pool.Name()
+	srcBackup.Snapshots = nil
+	srcBackup.Config = &backupConfig
+
+	err = pool.DeleteInstance(inst, op)
+	if err != nil {
+		return err
+	}
+
+	postHook, revertHook, err := pool.CreateInstanceFromBackup(srcBackup, srcData, op)
+	if err != nil {
+		return fmt.Errorf("Failed restoring backup: %w", err)
+	}
+
+	reverter.Add(revertHook)
+
+	err = d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateInstanceConfig(ctx, tx.Tx(), int64(inst.ID()), instLocalConfig)
+	})
+	if err != nil {
+		return err
+	}
+
+	d.localConfig = instLocalConfig
+
+	// Run the storage post hook now that the instance volume is in place.
+	if postHook != nil {
+		err = postHook(inst)
+		if err != nil {
+			return fmt.Errorf("Post hook failed: %w", err)
+		}
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
 // runHooks executes the callback functions returned from a function.
 func (d *common) runHooks(hooks []func() error) error {
 	// Run any post start hooks.
@@ -861,6 +1057,40 @@ func (d *common) updateProgress(progress string) {
 	}
 }
 
+// updateMigrationProgress updates the operation metadata with the progress of the state transfer of a live migration.
+func (d *common) updateMigrationProgress(progress api.MigrationStateProgress) {
+	if d.op == nil {
+		return
+	}
+
+	meta := d.op.Metadata()
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+
+	progress.ETA = -1
+	if progress.Speed > 0 {
+		progress.ETA = progress.RemainingBytes / progress.Speed
+	}
+
+	text := fmt.Sprintf("State transfer: %s (%s/s)", units.GetByteSizeString(progress.TransferredBytes, 2), units.GetByteSizeString(progress.Speed, 2))
+	if progress.Iteration > 0 {
+		text += fmt.Sprintf(", iteration %d", progress.Iteration)
+	}
+
+	if progress.ETA >= 0 {
+		text += fmt.Sprintf(", %s remaining", time.Duration(progress.ETA)*time.Second)
+	}
+
+	// The storage transfer is over by the time the state gets transferred.
+	delete(meta, "fs_progress")
+	delete(meta, "block_progress")
+
+	meta["state_transfer"] = progress
+	meta["state_progress"] = text
+	_ = d.op.UpdateMetadata(meta)
+}
+
 // insertConfigkey function attempts to insert the instance config key into the database. If the insert fails
 // then the database is queried to check whether another query inserted the same key. If the key is still
 // unpopulated then the insert querty is retried until it succeeds or a retry limit is reached.
@@ -954,6 +1184,12 @@ func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error
 		return errors.New("Requested architecture isn't supported by this host")
 	}
 
+	// Validate CPU requirements.
+	err = d.validateCPURequirements()
+	if err != nil {
+		return err
+	}
+
 	// Must happen before creating operation Start lock to avoid the status check returning Stopped due to the
 	// existence of a Start operation lock.
 	err = d.isStartableStatusCode(statusCode)
@@ -964,6 +1200,95 @@ func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error
 	return nil
 }
 
+// validateCPURequirements checks that the CPU pinning and nesting requirements of the instance can be met by this server.
+func (d *common) validateCPURequirements() error {
+	limitCPU := d.expandedConfig["limits.cpu"]
+	limitNodes := d.expandedConfig["limits.cpu.nodes"]
+	nesting := d.Type() == instancetype.VM && util.IsTrue(d.expandedConfig["security.nesting"])
+
+	_, err := strconv.Atoi(limitCPU)
+	pinnedCPU := limitCPU != "" && err != nil
+	pinnedNodes := limitNodes != "" && limitNodes != "balanced"
+
+	// Shortcut when there's nothing to check.
+	if !pinnedCPU && !pinnedNodes && !nesting {
+		return nil
+	}
+
+	cpu, err := resources.GetCPU()
+	if err != nil {
+		return fmt.Errorf("Failed getting CPU information: %w", err)
+	}
+
+	threads := map[int64]bool{}
+	nodes := map[int64]bool{}
+	for _, cpuSocket := range cpu.Sockets {
+		for _, cpuCore := range cpuSocket.Cores {
+			for _, cpuThread := range cpuCore.Threads {
+				threads[cpuThread.ID] = true
+				nodes[int64(cpuThread.NUMANode)] = true
+			}
+		}
+	}
+
+	if pinnedCPU {
+		pins, err := resources.ParseCpuset(limitCPU)
+		if err != nil {
+			return fmt.Errorf("Invalid limits.cpu value %q: %w", limitCPU, err)
+		}
+
+		for _, pin := range pins {
+			if !threads[pin] {
+				return fmt.Errorf("limits.cpu references CPU %d which doesn't exist on this server", pin)
+			}
+		}
+	}
+
+	if pinnedNodes {
+		numaNodes, err := resources.ParseNumaNodeSet(limitNodes)
+		if err != nil {
+			return fmt.Errorf("Invalid limits.cpu.nodes value %q: %w", limitNodes, err)
+		}
+
+		for _, numaNode := range numaNodes {
+			if !nodes[numaNode] {
+				return fmt.Errorf("limits.cpu.nodes references NUMA node %d which doesn't exist on this server", numaNode)
+			}
+		}
+	}
+
+	if nesting && (cpu.Virtualization == nil || !cpu.Virtualization.Nested) {
+		return errors.New("security.nesting requires nested virtualization which isn't available on this server")
+	}
+
+	// Ensure the pinned CPUs aren't reserved by an instance using CPU isolation (checked on reservation otherwise).
+	if pinnedCPU && d.expandedConfig["limits.cpu.pin_strategy"] != "isolated" {
+		insts, err := instance.LoadNodeAll(d.state, instancetype.Any)
+		if err != nil {
+			return err
+		}
+
+		others := make([]instance.Instance, 0, len(insts))
+		for _, inst := range insts {
+			if inst.ID() != d.id {
+				others = append(others, inst)
+			}
+		}
+
+		reserved := instance.ReservedCPUs(others, cpu)
+
+		pins, _ := resources.ParseCpuset(limitCPU)
+		for _, pin := range pins {
+			inst, ok := reserved[pin]
+			if ok {
+				return fmt.Errorf("limits.cpu references CPU %d which is reserved by instance %q in project %q", pin, inst.Name(), inst.Project().Name)
+			}
+		}
+	}
+
+	return nil
+}
+
 // onStopOperationSetup creates or picks up the relevant operation. This is used in the stopns and stop hooks to
 // ensure that a lock on their activities is held before the instance process is stopped. This prevents a start
 // request run at the same time from overlapping with the stop process.
@@ -1636,6 +1961,147 @@ func (d *common) balanceNUMANodes() error {
 	return d.VolatileSet(map[string]string{"volatile.cpu.nodes": fmt.Sprintf("%d", nodes[0])})
 }
 
+// cpuLimit returns the CPU limit to apply to the instance, this is the list of reserved CPU threads when using CPU isolation.
+func (d *common) cpuLimit() string {
+	if d.expandedConfig["limits.cpu.pin_strategy"] == "isolated" && d.expandedConfig["volatile.cpu.isolated"] != "" {
+		return d.expandedConfig["volatile.cpu.isolated"]
+	}
+
+	return d.expandedConfig["limits.cpu"]
+}
+
+// allocateIsolatedCPUs reserves whole CPU cores for the instance, skipping any core used by another instance.
+func (d *common) allocateIsolatedCPUs() error {
+	muCPUIsolation.Lock()
+	defer muCPUIsolation.Unlock()
+
+	// Get the CPU information.
+	cpu, err := resources.GetCPU()
+	if err != nil {
+		return fmt.Errorf("Failed getting CPU information: %w", err)
+	}
+
+	// Get all local instances.
+	insts, err := instance.LoadNodeAll(d.state, instancetype.Any)
+	if err != nil {
+		return err
+	}
+
+	// Record the CPU threads used by other instances.
+	others := make([]instance.Instance, 0, len(insts))
+	pinned := map[int64]bool{}
+	for _, inst := range insts {
+		// Ignore ourselves.
+		if inst.ID() == d.id {
+			continue
+		}
+
+		others = append(others, inst)
+
+		// Ignore stopped instances and instances without CPU pinning.
+		limit := inst.ExpandedConfig()["limits.cpu"]
+		_, err := strconv.Atoi(limit)
+		if !inst.IsRunning() || limit == "" || err == nil {
+			continue
+		}
+
+		pins, err := resources.ParseCpuset(limit)
+		if err != nil {
+			continue
+		}
+
+		for _, pin := range pins {
+			pinned[pin] = true
+		}
+	}
+
+	reserved := instance.ReservedCPUs(others, cpu)
+
+	limit := d.expandedConfig["limits.cpu"]
+	if limit == "" {
+		if d.Type() != instancetype.VM {
+			return errors.New("limits.cpu must be set when using limits.cpu.pin_strategy=isolated")
+		}
+
+		limit = strconv.Itoa(qemudefault.CPUCores)
+	}
+
+	count, err := strconv.Atoi(limit)
+	if err != nil {
+		// Specific CPU threads were requested, check that they're available.
+		pins, err := resources.ParseCpuset(limit)
+		if err != nil {
+			return fmt.Errorf("Invalid limits.cpu value %q: %w", limit, err)
+		}
+
+		for _, pin := range pins {
+			inst, ok := reserved[pin]
+			if ok {
+				return fmt.Errorf("CPU %d is already reserved by instance %q in project %q", pin, inst.Name(), inst.Project().Name)
+			}
+
+			if pinned[pin] {
+				return fmt.Errorf("CPU %d is already used by another instance", pin)
+			}
+		}
+
+		return d.VolatileSet(map[string]string{"volatile.cpu.isolated": limit})
+	}
+
+	// Restrict the selection to the instance NUMA node(s).
+	var numaNodes []int64
+	limitNodes := d.expandedConfig["limits.cpu.nodes"]
+	if limitNodes == "balanced" {
+		limitNodes = d.expandedConfig["volatile.cpu.nodes"]
+	}
+
+	if limitNodes != "" {
+		numaNodes, err = resources.ParseNumaNodeSet(limitNodes)
+		if err != nil {
+			return fmt.Errorf("Invalid limits.cpu.nodes value %q: %w", limitNodes, err)
+		}
+	}
+
+	// Pick the first cores which are entirely available.
+	isolatedCPUs := resources.GetCPUIsolated()
+	selected := []string{}
+	for _, cpuSocket := range cpu.Sockets {
+		for _, cpuCore := range cpuSocket.Cores {
+			available := len(selected) < count
+			for _, cpuThread := range cpuCore.Threads {
+				_, isReserved := reserved[cpuThread.ID]
+				if !cpuThread.Online || isReserved || pinned[cpuThread.ID] || slices.Contains(isolatedCPUs, cpuThread.ID) {
+					available = false
+					break
+				}
+
+				if numaNodes != nil && !slices.Contains(numaNodes, int64(cpuThread.NUMANode)) {
+					available = false
+					break
+				}
+			}
+
+			if !available {
+				continue
+			}
+
+			for _, cpuThread := range cpuCore.Threads {
+				if len(selected) == count {
+					break
+				}
+
+				selected = append(selected, strconv.FormatInt(cpuThread.ID, 10))
+			}
+		}
+	}
+
+	if len(selected) < count {
+		return fmt.Errorf("Not enough available CPU cores to reserve %d CPU threads", count)
+	}
+
+	return d.VolatileSet(map[string]string{"volatile.cpu.isolated": strings.Join(selected, ",")})
+}
+
 // Gets the process starting time.
 func (d *common) processStartedAt(pid int) (time.Time, error) {
 	if pid < 1 {
@@ -0,0 +1,123 @@
+package drivers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// lifecycleHookTimeout is how long a lifecycle hook may run when hooks.timeout isn't set.
+const lifecycleHookTimeout = 30 * time.Second
+
+// lifecycleHookPayload is the body sent to webhooks and the environment passed to scripts.
+type lifecycleHookPayload struct {
+	Hook     string `json:"hook"`
+	Project  string `json:"project"`
+	Instance string `json:"instance"`
+	Type     string `json:"type"`
+}
+
+// runLifecycleHook runs the user-defined hook for a lifecycle transition (pre-start, post-start, pre-stop or post-stop).
+// Post hooks run in the background. Failing pre hooks return an error unless hooks.on_failure is set to ignore.
+func (d *common) runLifecycleHook(hook string) error {
+	target := d.expandedConfig["hooks."+hook]
+	if target == "" {
+		return nil
+	}
+
+	timeout := lifecycleHookTimeout
+	if d.expandedConfig["hooks.timeout"] != "" {
+		value, err := time.ParseDuration(d.expandedConfig["hooks.timeout"])
+		if err != nil {
+			return fmt.Errorf("Invalid hooks.timeout: %w", err)
+		}
+
+		timeout = value
+	}
+
+	payload := lifecycleHookPayload{
+		Hook:     hook,
+		Project:  d.project.Name,
+		Instance: d.name,
+		Type:     d.dbType.String(),
+	}
+
+	run := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			return d.callLifecycleWebhook(ctx, target, payload)
+		}
+
+		env := append(os.Environ(),
+			"INCUS_HOOK="+payload.Hook,
+			"INCUS_PROJECT="+payload.Project,
+			"INCUS_INSTANCE="+payload.Instance,
+			"INCUS_INSTANCE_TYPE="+payload.Type,
+		)
+
+		_, _, err := subprocess.RunCommandSplit(ctx, env, nil, target, hook)
+		return err
+	}
+
+	if strings.HasPrefix(hook, "post-") {
+		go func() {
+			err := run()
+			if err != nil {
+				d.logger.Warn("Failed running lifecycle hook", logger.Ctx{"hook": hook, "err": err})
+			}
+		}()
+
+		return nil
+	}
+
+	err := run()
+	if err != nil {
+		if d.expandedConfig["hooks.on_failure"] == "ignore" {
+			d.logger.Warn("Failed running lifecycle hook", logger.Ctx{"hook": hook, "err": err})
+			return nil
+		}
+
+		return fmt.Errorf("Failed running %q hook: %w", hook, err)
+	}
+
+	return nil
+}
+
+// callLifecycleWebhook sends the lifecycle hook payload to a webhook, expecting a successful status code.
+func (d *common) callLifecycleWebhook(ctx context.Context, target string, payload lifecycleHookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Transport: &http.Transport{Proxy: d.state.Proxy}}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook returned %q", resp.Status)
+	}
+
+	return nil
+}
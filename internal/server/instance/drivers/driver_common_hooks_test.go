@@ -0,0 +1,106 @@
+package drivers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// newLifecycleHookInstance returns an instance with the given hooks configuration.
+func newLifecycleHookInstance(config map[string]string) *common {
+	return &common{
+		state:          &state.State{},
+		dbType:         instancetype.Container,
+		expandedConfig: config,
+		logger:         logger.Log,
+		name:           "c1",
+		project:        api.Project{Name: "default"},
+	}
+}
+
+// newLifecycleHookScript writes an executable hook script with the given body.
+func newLifecycleHookScript(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "hook")
+
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700)
+	require.NoError(t, err)
+
+	return path
+}
+
+// Test that a pre hook running past hooks.timeout is killed and fails.
+func TestRunLifecycleHook_Timeout(t *testing.T) {
+	d := newLifecycleHookInstance(map[string]string{
+		"hooks.pre-start": newLifecycleHookScript(t, "exec sleep 10"),
+		"hooks.timeout":   "200ms",
+	})
+
+	start := time.Now()
+	err := d.runLifecycleHook("pre-start")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	d.expandedConfig["hooks.timeout"] = "invalid"
+	err = d.runLifecycleHook("pre-start")
+	assert.ErrorContains(t, err, "Invalid hooks.timeout")
+}
+
+// Test that hooks.on_failure=ignore lets failing pre hooks through.
+func TestRunLifecycleHook_OnFailureIgnore(t *testing.T) {
+	d := newLifecycleHookInstance(map[string]string{
+		"hooks.pre-stop": newLifecycleHookScript(t, "exit 1"),
+	})
+
+	err := d.runLifecycleHook("pre-stop")
+	assert.ErrorContains(t, err, "pre-stop")
+
+	d.expandedConfig["hooks.on_failure"] = "ignore"
+	err = d.runLifecycleHook("pre-stop")
+	assert.NoError(t, err)
+}
+
+// Test that webhooks get the hook payload and fail on non-2xx status codes.
+func TestRunLifecycleHook_Webhook(t *testing.T) {
+	status := http.StatusOK
+	payloads := []lifecycleHookPayload{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := lifecycleHookPayload{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+
+		w.WriteHeader(status)
+	}))
+
+	defer srv.Close()
+
+	d := newLifecycleHookInstance(map[string]string{
+		"hooks.pre-start": srv.URL,
+	})
+
+	err := d.runLifecycleHook("pre-start")
+	assert.NoError(t, err)
+
+	status = http.StatusInternalServerError
+	err = d.runLifecycleHook("pre-start")
+	assert.ErrorContains(t, err, "500")
+
+	d.expandedConfig["hooks.on_failure"] = "ignore"
+	err = d.runLifecycleHook("pre-start")
+	assert.NoError(t, err)
+
+	require.Len(t, payloads, 3)
+	assert.Equal(t, lifecycleHookPayload{Hook: "pre-start", Project: "default", Instance: "c1", Type: "container"}, payloads[0])
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/lxc/incus/v6/internal/netutils"
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cgroup"
 	"github.com/lxc/incus/v6/internal/server/daemon"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -236,6 +237,11 @@ func lxcCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operatio
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = d.validateCPURequirements()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	_, rootDiskDevice, err := d.getRootDiskDevice()
@@ -440,6 +446,9 @@ type lxc struct {
 
 var idmapLock sync.Mutex
 
+// lxcConsoleBuffers holds the console ring buffer content last returned by ConsoleBufferRead for each container (by instance ID).
+var lxcConsoleBuffers sync.Map
+
 func (d *lxc) findIdmap() (*idmap.Set, int64, error) {
 	if d.state.OS.IdmapSet == nil {
 		return nil, 0, errors.New("System doesn't have a functional idmap setup")
@@ -791,6 +800,26 @@ func (d *lxc) initLXC(config bool) (*liblxc.Container, error) {
 		return nil, err
 	}
 
+	// Delegate a cgroup sub-tree to the container, limits are applied to the outer cgroup.
+	if util.IsTrue(d.expandedConfig["security.delegate_cgroup"]) {
+		cgName := project.Instance(d.Project().Name, d.Name())
+
+		err = lxcSetConfigItem(cc, "lxc.cgroup.dir.monitor", fmt.Sprintf("lxc.monitor.%s", cgName))
+		if err != nil {
+			return nil, err
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.cgroup.dir.container", fmt.Sprintf("lxc.payload.%s", cgName))
+		if err != nil {
+			return nil, err
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.cgroup.dir.container.inner", "ns")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = lxcSetConfigItem(cc, "lxc.autodev", "1")
 	if err != nil {
 		return nil, err
@@ -1942,6 +1971,16 @@ func (d *lxc) startCommon() (string, []func() error, error) {
 		}
 	}
 
+	// Reserve CPU cores if needed.
+	if d.expandedConfig["limits.cpu.pin_strategy"] == "isolated" {
+		err := d.allocateIsolatedCPUs()
+		if err != nil {
+			return "", nil, err
+		}
+
+		reverter.Add(func() { _ = d.VolatileSet(map[string]string{"volatile.cpu.isolated": ""}) })
+	}
+
 	// Check if idmap needs changing.
 	if !d.IsPrivileged() {
 		nextMap, err := d.NextIdmap()
@@ -2753,6 +2792,15 @@ func (d *lxc) Start(stateful bool) error {
 		d.logger.Info("Starting instance", ctxMap)
 	}
 
+	// Run the user-defined pre-start hook, unless the instance is only being moved.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-start")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// If stateful, restore now.
 	if stateful && d.stateful {
 		d.logger.Info("Restoring stateful checkpoint")
@@ -2784,6 +2832,10 @@ func (d *lxc) Start(stateful bool) error {
 			return fmt.Errorf("Failed clearing instance stateful flag: %w", err)
 		}
 
+		if op.Action() != operationlock.ActionMigrate {
+			_ = d.runLifecycleHook("post-start")
+		}
+
 		if op.Action() == "start" {
 			d.logger.Info("Started instance", ctxMap)
 			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
@@ -2885,11 +2937,19 @@ func (d *lxc) Start(stateful bool) error {
 
 		d.logger.Error("Failed starting instance", ctxMap)
 
+		// Release any reserved CPU cores.
+		_ = d.VolatileSet(map[string]string{"volatile.cpu.isolated": ""})
+
 		// Return the actual error
 		op.Done(err)
 		return err
 	}
 
+	// Make all controllers available to the delegated cgroup sub-tree.
+	if util.IsTrue(d.expandedConfig["security.delegate_cgroup"]) {
+		d.delegateCgroup()
+	}
+
 	// Run any post start hooks.
 	err = d.runHooks(postStartHooks)
 	if err != nil {
@@ -2901,6 +2961,10 @@ func (d *lxc) Start(stateful bool) error {
 		return err
 	}
 
+	if op.Action() != operationlock.ActionMigrate {
+		_ = d.runLifecycleHook("post-start")
+	}
+
 	if op.Action() == "start" {
 		d.logger.Info("Started instance", ctxMap)
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
@@ -2909,6 +2973,49 @@ func (d *lxc) Start(stateful bool) error {
 	return nil
 }
 
+// delegateCgroup enables all controllers available to the container's outer cgroup on its delegated sub-tree.
+// Failures are only logged as the container is still functional, just with fewer controllers delegated.
+func (d *lxc) delegateCgroup() {
+	if !d.state.OS.CGInfo.NsDelegate {
+		d.logger.Warn("The cgroup2 hierarchy isn't mounted with nsdelegate, the delegated cgroup isn't a delegation boundary")
+	}
+
+	cc, err := d.initLXC(false)
+	if err != nil {
+		d.logger.Warn("Failed loading container for cgroup delegation", logger.Ctx{"err": err})
+		return
+	}
+
+	cg, err := d.cgroup(cc, true)
+	if err != nil {
+		d.logger.Warn("Failed loading cgroup for cgroup delegation", logger.Ctx{"err": err})
+		return
+	}
+
+	controllers, err := cg.GetControllers()
+	if err != nil {
+		d.logger.Warn("Failed getting available cgroup controllers", logger.Ctx{"err": err})
+		return
+	}
+
+	enabled, err := cg.GetSubtreeControllers()
+	if err != nil {
+		d.logger.Warn("Failed getting delegated cgroup controllers", logger.Ctx{"err": err})
+		return
+	}
+
+	for _, controller := range controllers {
+		if slices.Contains(enabled, controller) {
+			continue
+		}
+
+		err = cg.EnableSubtreeController(controller)
+		if err != nil {
+			d.logger.Warn("Failed delegating cgroup controller", logger.Ctx{"controller": controller, "err": err})
+		}
+	}
+}
+
 // OnHook is the top-level hook handler.
 func (d *lxc) OnHook(hookName string, args map[string]string) error {
 	switch hookName {
@@ -2990,6 +3097,22 @@ func (d *lxc) validateStartup(stateful bool, statusCode api.StatusCode) error {
 		return errors.New("The image used by this instance requires nesting. Please set security.nesting=true on the instance")
 	}
 
+	// Ensure cgroup delegation is supported by the host.
+	if util.IsTrue(d.expandedConfig["security.delegate_cgroup"]) {
+		cgInfo := cgroup.GetInfo()
+		if cgInfo.Layout != cgroup.CgroupsUnified {
+			return errors.New("Cgroup delegation requires a pure cgroup2 host")
+		}
+
+		if !cgInfo.Namespacing {
+			return errors.New("Cgroup delegation requires cgroup namespace support")
+		}
+
+		if !d.state.OS.LXCFeatures["cgroup_advanced_isolation"] {
+			return errors.New("Cgroup delegation requires a version of LXC supporting cgroup_advanced_isolation")
+		}
+	}
+
 	return nil
 }
 
@@ -3031,6 +3154,15 @@ func (d *lxc) Stop(stateful bool) error {
 		d.logger.Info("Stopping instance", ctxMap)
 	}
 
+	// Run the user-defined pre-stop hook.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-stop")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Forcefully stop any forkfile process if running.
 	d.stopForkfile(true)
 
@@ -3216,6 +3348,15 @@ func (d *lxc) Shutdown(timeout time.Duration) error {
 		d.logger.Info("Shutting down instance", ctxMap)
 	}
 
+	// Run the user-defined pre-stop hook.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-stop")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Release liblxc container once done.
 	defer func() {
 		d.release()
@@ -3291,6 +3432,11 @@ func (d *lxc) Rebuild(img *api.Image, op *operations.Operation) error {
 	return d.rebuildCommon(d, img, op)
 }
 
+// RebuildFromBackup rebuilds the instance using the supplied backup file as source.
+func (d *lxc) RebuildFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return d.rebuildFromBackupCommon(d, srcBackup, srcData, op)
+}
+
 // onStopNS is triggered by LXC's stop hook once a container is shutdown but before the container's
 // namespaces have been closed. The netns path of the stopped container is provided.
 func (d *lxc) onStopNS(args map[string]string) error {
@@ -3335,10 +3481,11 @@ func (d *lxc) onStop(args map[string]string) error {
 	// Make sure we can't call go-lxc functions by mistake
 	d.fromHook = true
 
-	// Record power state.
+	// Record power state and release any reserved CPU cores.
 	err = d.VolatileSet(map[string]string{
 		"volatile.last_state.power": instance.PowerStateStopped,
 		"volatile.last_state.ready": "false",
+		"volatile.cpu.isolated":     "",
 	})
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
@@ -3438,11 +3585,30 @@ func (d *lxc) onStop(args map[string]string) error {
 			_ = unix.Unmount(filepath.Join(d.DevicesPath(), "lxcfs"), unix.MNT_DETACH)
 		}
 
+		// Run the user-defined post-stop hook.
+		if op.Action() != operationlock.ActionMigrate {
+			_ = d.runLifecycleHook("post-stop")
+		}
+
+		// Forget about the console content, the ring buffer is gone.
+		lxcConsoleBuffers.Delete(d.id)
+
 		// Determine if instance should be auto-restarted.
+		// As there is no way to tell a clean power off from a crash of the container's init, any stop
+		// which wasn't requested through Incus is considered a failure.
 		var autoRestart bool
-		if target != "reboot" && op.GetInstanceInitiated() && d.shouldAutoRestart() {
-			autoRestart = true
+		var restartScheduled bool
+		if target != "reboot" && op.GetInstanceInitiated() {
+			var restartDelay time.Duration
+			autoRestart, restartDelay = d.shouldRestart(true)
+			if autoRestart && restartDelay > 0 {
+				d.scheduleRestart(restartDelay)
+				autoRestart = false
+				restartScheduled = true
+			}
+		}
 
+		if autoRestart {
 			// Mark current shutdown as complete.
 			op.Done(nil)
 
@@ -3487,7 +3653,7 @@ func (d *lxc) onStop(args map[string]string) error {
 		defer cgroup.TaskSchedulerTrigger("container", d.name, "stopped")
 
 		// Destroy ephemeral containers
-		if d.ephemeral {
+		if d.ephemeral && !restartScheduled {
 			err = d.delete(true)
 			if err != nil {
 				op.Done(fmt.Errorf("Failed deleting ephemeral instance: %w", err))
@@ -3836,6 +4002,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 		status.Network = d.networkState(hostInterfaces)
 		status.Pid = int64(pid)
 		status.Processes = processesState
+		status.Pressure = d.pressureState()
 
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
@@ -3845,6 +4012,10 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 
 	status.Disk = d.diskState()
 
+	if d.isRunningStatusCode(statusCode) {
+		d.diskIOState(status.Disk, pid)
+	}
+
 	d.release()
 
 	return &status, nil
@@ -3855,6 +4026,52 @@ func (d *lxc) RenderState(hostInterfaces []net.Interface) (*api.InstanceState, e
 	return d.renderState(d.statusCode(), hostInterfaces)
 }
 
+// SyncTime isn't supported for containers as they use the host clock.
+func (d *lxc) SyncTime() error {
+	return api.StatusErrorf(http.StatusBadRequest, "Containers use the host clock")
+}
+
+// Processes returns the list of processes running in the container, as seen from inside of it.
+func (d *lxc) Processes() ([]api.InstanceProcess, error) {
+	pid := d.InitPID()
+	if pid <= 0 {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Instance isn't running")
+	}
+
+	// Only list the processes sharing the PID namespace of the container's init.
+	pidNS, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/pid", pid))
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting the container's PID namespace: %w", err)
+	}
+
+	_, initPIDs, err := linux.ProcessInfo(int64(pid))
+	if err != nil {
+		return nil, err
+	}
+
+	processes, err := linux.ProcessList(func(pid int64) bool {
+		ns, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/pid", pid))
+		return err == nil && ns == pidNS
+	}, len(initPIDs)-1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report the users as seen from inside the container.
+	idmapSet, err := d.CurrentIdmap()
+	if err != nil {
+		return nil, err
+	}
+
+	if idmapSet != nil {
+		for i := range processes {
+			processes[i].UID, _ = idmapSet.ShiftIntoNS(processes[i].UID, 0)
+		}
+	}
+
+	return processes, nil
+}
+
 // snapshot creates a snapshot of the instance.
 func (d *lxc) snapshot(name string, expiry time.Time, stateful bool) error {
 	// Check that migration.stateful is set for stateful actions.
@@ -3886,29 +4103,6 @@ func (d *lxc) snapshot(name string, expiry time.Time, stateful bool) error {
 
 		defer func() { _ = os.RemoveAll(stateDir) }()
 
-		// Release liblxc container once done.
-		defer func() {
-			d.release()
-		}()
-
-		// Load the go-lxc struct
-		if d.expandedConfig["raw.lxc"] != "" {
-			cc, err := d.initLXC(true)
-			if err != nil {
-				return err
-			}
-
-			err = d.loadRawLXCConfig(cc)
-			if err != nil {
-				return err
-			}
-		} else {
-			_, err = d.initLXC(false)
-			if err != nil {
-				return err
-			}
-		}
-
 		/* TODO: ideally we would freeze here and unfreeze below after
 		 * we've copied the filesystem, to make sure there are no
 		 * changes by the container while snapshotting. Unfortunately
@@ -3918,20 +4112,9 @@ func (d *lxc) snapshot(name string, expiry time.Time, stateful bool) error {
 		 * was frozen. Until that's fixed, all calls to Unfreeze()
 		 * after snapshotting will fail.
 		 */
-		criuMigrationArgs := instance.CriuMigrationArgs{
-			Cmd:          liblxc.MIGRATE_DUMP,
-			StateDir:     stateDir,
-			Function:     "snapshot",
-			Stop:         false,
-			ActionScript: false,
-			DumpDir:      "",
-			PreDumpDir:   "",
-		}
-
-		// Dump the state.
-		err = d.migrate(&criuMigrationArgs)
+		err = d.checkpoint(stateDir, "snapshot")
 		if err != nil {
-			return fmt.Errorf("Failed taking stateful checkpoint: %w", err)
+			return err
 		}
 	}
 
@@ -3941,6 +4124,50 @@ func (d *lxc) snapshot(name string, expiry time.Time, stateful bool) error {
 	return d.snapshotCommon(d, name, expiry, stateful)
 }
 
+// checkpoint dumps the state of the running container into stateDir, leaving it running.
+func (d *lxc) checkpoint(stateDir string, function string) error {
+	// Release liblxc container once done.
+	defer func() {
+		d.release()
+	}()
+
+	// Load the go-lxc struct
+	if d.expandedConfig["raw.lxc"] != "" {
+		cc, err := d.initLXC(true)
+		if err != nil {
+			return err
+		}
+
+		err = d.loadRawLXCConfig(cc)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err := d.initLXC(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	criuMigrationArgs := instance.CriuMigrationArgs{
+		Cmd:          liblxc.MIGRATE_DUMP,
+		StateDir:     stateDir,
+		Function:     function,
+		Stop:         false,
+		ActionScript: false,
+		DumpDir:      "",
+		PreDumpDir:   "",
+	}
+
+	// Dump the state.
+	err := d.migrate(&criuMigrationArgs)
+	if err != nil {
+		return fmt.Errorf("Failed taking stateful checkpoint: %w", err)
+	}
+
+	return nil
+}
+
 // Snapshot takes a new snapshot.
 func (d *lxc) Snapshot(name string, expiry time.Time, stateful bool) error {
 	return d.snapshot(name, expiry, stateful)
@@ -5098,6 +5325,11 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 					}
 				}
 			} else if key == "limits.cpu" || key == "limits.cpu.nodes" {
+				// The reserved CPU cores can only be changed on startup.
+				if d.expandedConfig["limits.cpu.pin_strategy"] == "isolated" {
+					return fmt.Errorf("Cannot update key %q when using CPU isolation and the container is running", key)
+				}
+
 				// Clear the "volatile.cpu.nodes" if needed.
 				d.ClearLimitsCPUNodes(changedConfig)
 
@@ -5316,19 +5548,59 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 }
 
 // Export backs up the instance.
-func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
+func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, stateful bool, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
 	ctxMap := logger.Ctx{
 		"created":   d.creationDate,
 		"ephemeral": d.ephemeral,
 		"used":      d.lastUsedDate,
+		"stateful":  stateful,
 	}
 
-	if d.IsRunning() {
+	if stateful {
+		// Quick checks.
+		if d.IsSnapshot() || !d.IsRunning() {
+			return nil, errors.New("Stateful export requires a running instance")
+		}
+
+		if util.IsFalseOrEmpty(d.expandedConfig["migration.stateful"]) {
+			return nil, errors.New("Stateful export requires that the instance has migration.stateful be set to true")
+		}
+
+		_, err := exec.LookPath("criu")
+		if err != nil {
+			return nil, errors.New("Unable to export the instance state. CRIU isn't installed")
+		}
+	} else if d.IsRunning() {
 		return nil, errors.New("Cannot export a running instance as an image")
 	}
 
 	d.logger.Info("Exporting instance", ctxMap)
 
+	if stateful {
+		// Checkpoint the container next to its rootfs so it ends up in the metadata tarball.
+		stateDir := d.StatePath()
+		_ = os.RemoveAll(stateDir)
+
+		err := os.MkdirAll(stateDir, 0o700)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = os.RemoveAll(stateDir) }()
+
+		err = d.checkpoint(stateDir, "export")
+		if err != nil {
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+
+		// Freeze the container while its filesystem is exported so it matches the checkpoint more closely.
+		err = d.Freeze()
+		if err == nil {
+			defer func() { _ = d.Unfreeze() }()
+		}
+	}
+
 	// Start the storage.
 	_, err := d.mount()
 	if err != nil {
@@ -5459,6 +5731,13 @@ func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties ma
 
 	maps.Copy(meta.Properties, properties)
 
+	if stateful {
+		// Instances created from the image will be restored from the checkpoint.
+		meta.Properties["stateful"] = "true"
+	} else {
+		delete(meta.Properties, "stateful")
+	}
+
 	if !expiration.IsZero() {
 		meta.ExpiryDate = expiration.UTC().Unix()
 	}
@@ -5551,6 +5830,15 @@ func (d *lxc) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties ma
 		}
 	}
 
+	// Include the checkpoint.
+	if stateful {
+		err = filepath.Walk(d.StatePath(), writeToMetaTar)
+		if err != nil {
+			d.logger.Error("Failed exporting instance", ctxMap)
+			return nil, err
+		}
+	}
+
 	err = metaTarWriter.Close()
 	if err != nil {
 		d.logger.Error("Failed exporting instance", ctxMap)
@@ -5596,7 +5884,7 @@ func getCRIULogErrors(imagesDir string, method string) (string, error) {
 }
 
 // Check if CRIU supports pre-dumping and number of pre-dump iterations.
-func (d *lxc) migrationSendCheckForPreDumpSupport() (bool, int) {
+func (d *lxc) migrationSendCheckForPreDumpSupport(args instance.MigrateSendArgs) (bool, int) {
 	// Check if this architecture/kernel/criu combination supports pre-copy dirty memory tracking feature.
 	_, err := subprocess.RunCommand("criu", "check", "--feature", "mem_dirty_track")
 	if err != nil {
@@ -5622,7 +5910,9 @@ func (d *lxc) migrationSendCheckForPreDumpSupport() (bool, int) {
 	// container will be definitely migrated, even if the remaining number
 	// of memory pages is below the defined threshold.
 	tmp = d.ExpandedConfig()["migration.incremental.memory.iterations"]
-	if tmp != "" {
+	if args.PreDumpIterations > 0 {
+		maxIterations = args.PreDumpIterations
+	} else if tmp != "" {
 		maxIterations, _ = strconv.Atoi(tmp)
 	} else {
 		// default to 10
@@ -5642,6 +5932,34 @@ func (d *lxc) migrationSendCheckForPreDumpSupport() (bool, int) {
 	return usePreDumps, maxIterations
 }
 
+// migrationSendPreDumpLimits returns the pre-copy goal (percentage of memory pages to have in sync)
+// and the maximum time to spend pre-dumping before doing the final dump (0 means no limit).
+func (d *lxc) migrationSendPreDumpLimits(args instance.MigrateSendArgs) (int, time.Duration) {
+	// threshold is the percentage of memory pages that needs
+	// to be pre-copied for the pre-copy migration to stop.
+	threshold := args.PreDumpGoal
+	if threshold <= 0 {
+		tmp := d.ExpandedConfig()["migration.incremental.memory.goal"]
+		if tmp != "" {
+			threshold, _ = strconv.Atoi(tmp)
+		} else {
+			// defaults to 70%
+			threshold = 70
+		}
+	}
+
+	timeout := args.PreDumpTimeout
+	if timeout <= 0 {
+		tmp := d.ExpandedConfig()["migration.incremental.memory.timeout"]
+		if tmp != "" {
+			seconds, _ := strconv.Atoi(tmp)
+			timeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return threshold, timeout
+}
+
 func (d *lxc) migrationSendWriteActionScript(directory string, operation string, secret string, execPath string) error {
 	script := fmt.Sprintf(`#!/bin/sh -e
 if [ "$CRTOOLS_SCRIPT_ACTION" = "post-dump" ]; then
@@ -5729,7 +6047,7 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 	maxDumpIterations := 0
 	if args.Live {
 		var offerUsePreDumps bool
-		offerUsePreDumps, maxDumpIterations = d.migrationSendCheckForPreDumpSupport()
+		offerUsePreDumps, maxDumpIterations = d.migrationSendCheckForPreDumpSupport(args)
 		offerHeader.Predump = proto.Bool(offerUsePreDumps)
 		offerHeader.Criu = migration.CRIUType_CRIU_RSYNC.Enum()
 	} else {
@@ -5931,6 +6249,8 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 				return err
 			}
 
+			progress := api.MigrationStateProgress{}
+
 			if liblxc.RuntimeLiblxcVersionAtLeast(liblxc.Version(), 2, 0, 4) {
 				// What happens below is slightly convoluted. Due to various complications
 				// with networking, there's no easy way for criu to exit and leave the
@@ -6005,6 +6325,9 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 				// rsync protocol.
 				if respHeader.GetPredump() {
 					d.logger.Debug("The other side does support pre-copy")
+					preDumpGoal, preDumpTimeout := d.migrationSendPreDumpLimits(args)
+					preDumpStart := time.Now()
+					var previousDump time.Time
 					final := false
 					for !final {
 						preDumpCounter++
@@ -6014,6 +6337,11 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 							final = true
 						}
 
+						if preDumpTimeout > 0 && time.Since(preDumpStart) >= preDumpTimeout {
+							d.logger.Debug("Pre-copy timeout reached; next dump is the final dump", logger.Ctx{"timeout": preDumpTimeout})
+							final = true
+						}
+
 						dumpDir := fmt.Sprintf("%03d", preDumpCounter)
 						loopArgs := preDumpLoopArgs{
 							stateConn:     stateConn,
@@ -6022,9 +6350,13 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 							preDumpDir:    preDumpDir,
 							dumpDir:       dumpDir,
 							final:         final,
+							goal:          preDumpGoal,
 							rsyncFeatures: rsyncFeatures,
+							progress:      &progress,
+							previousDump:  previousDump,
 						}
 
+						previousDump = time.Now()
 						final, err = d.migrateSendPreDumpLoop(&loopArgs)
 						if err != nil {
 							_ = os.RemoveAll(checkpointDir)
@@ -6095,8 +6427,16 @@ func (d *lxc) MigrateSend(args instance.MigrateSendArgs) error {
 			// However assuming we're network bound, there's really no reason to do these in.
 			// parallel. In the future when we're using p.haul's protocol, it will make sense
 			// to do these in parallel.
+			var finalSize int64
+			dumpStats, err := crit.GetDumpStats(internalUtil.AddSlash(checkpointDir) + "final/")
+			if err == nil {
+				finalSize = int64(dumpStats.GetPagesWritten()) * int64(os.Getpagesize())
+			}
+
+			progress.Iteration++
+
 			ctName, _, _ := api.GetParentAndSnapshotName(d.Name())
-			err = rsync.Send(ctName, internalUtil.AddSlash(checkpointDir), stateConn, nil, rsyncFeatures, rsyncBwlimit, d.state.OS.ExecPath)
+			err = rsync.Send(ctName, internalUtil.AddSlash(checkpointDir), stateConn, d.migrationStateTracker(&progress, finalSize), rsyncFeatures, rsyncBwlimit, d.state.OS.ExecPath)
 			if err != nil {
 				return err
 			}
@@ -6160,7 +6500,24 @@ type preDumpLoopArgs struct {
 	preDumpDir    string
 	dumpDir       string
 	final         bool
+	goal          int
 	rsyncFeatures []string
+	progress      *api.MigrationStateProgress
+	previousDump  time.Time
+}
+
+// migrationStateTracker returns a tracker reporting the progress of the transfer of a CRIU dump of the given size.
+func (d *lxc) migrationStateTracker(progress *api.MigrationStateProgress, size int64) *ioprogress.ProgressTracker {
+	transferred := progress.TransferredBytes
+
+	return &ioprogress.ProgressTracker{
+		Handler: func(sent int64, speed int64) {
+			progress.TransferredBytes = transferred + sent
+			progress.RemainingBytes = max(size-sent, 0)
+			progress.Speed = speed
+			d.updateMigrationProgress(*progress)
+		},
+	}
 }
 
 // migrateSendPreDumpLoop is the main logic behind the pre-copy migration.
@@ -6191,13 +6548,6 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 		return final, fmt.Errorf("Failed sending instance: %w", err)
 	}
 
-	// Send the pre-dump.
-	ctName, _, _ := api.GetParentAndSnapshotName(d.Name())
-	err = rsync.Send(ctName, internalUtil.AddSlash(args.checkpointDir), args.stateConn, nil, args.rsyncFeatures, args.bwlimit, d.state.OS.ExecPath)
-	if err != nil {
-		return final, err
-	}
-
 	// The function readCriuStatsDump() reads the CRIU 'stats-dump' file
 	// in path and returns the pages_written, pages_skipped_parent, error.
 	readCriuStatsDump := func(path string) (uint64, uint64, error) {
@@ -6226,17 +6576,25 @@ func (d *lxc) migrateSendPreDumpLoop(args *preDumpLoopArgs) (bool, error) {
 
 	d.logger.Debug("CRIU pages", logger.Ctx{"pages": written, "skipped": skippedParent, "skippedPerc": percentageSkipped})
 
-	// threshold is the percentage of memory pages that needs
-	// to be pre-copied for the pre-copy migration to stop.
-	var threshold int
-	tmp := d.ExpandedConfig()["migration.incremental.memory.goal"]
-	if tmp != "" {
-		threshold, _ = strconv.Atoi(tmp)
-	} else {
-		// defaults to 70%
-		threshold = 70
+	// The pages written by a pre-dump are those dirtied since the previous one.
+	pageSize := int64(os.Getpagesize())
+	args.progress.Iteration++
+	args.progress.TotalBytes = int64(totalPages) * pageSize
+	if !args.previousDump.IsZero() {
+		elapsed := time.Since(args.previousDump).Seconds()
+		if elapsed > 0 {
+			args.progress.DirtyRate = int64(float64(int64(written)*pageSize) / elapsed)
+		}
+	}
+
+	// Send the pre-dump.
+	ctName, _, _ := api.GetParentAndSnapshotName(d.Name())
+	err = rsync.Send(ctName, internalUtil.AddSlash(args.checkpointDir), args.stateConn, d.migrationStateTracker(args.progress, int64(written)*pageSize), args.rsyncFeatures, args.bwlimit, d.state.OS.ExecPath)
+	if err != nil {
+		return final, err
 	}
 
+	threshold := args.goal
 	if percentageSkipped > threshold {
 		d.logger.Debug("Memory pages skipped due to pre-copy is larger than threshold", logger.Ctx{"skippedPerc": percentageSkipped, "thresholdPerc": threshold})
 		d.logger.Debug("This was the last pre-dump; next dump is the final dump")
@@ -7606,6 +7964,30 @@ func (d *lxc) ConsoleLog(opts liblxc.ConsoleLogOptions) (string, error) {
 	return string(msg), nil
 }
 
+// ConsoleBufferRead returns the console output produced since the last read, leaving the ring buffer untouched.
+func (d *lxc) ConsoleBufferRead() (string, error) {
+	if !d.IsRunning() {
+		lxcConsoleBuffers.Delete(d.id)
+		return "", nil
+	}
+
+	cc, err := d.initLXC(false)
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := cc.ConsoleLog(liblxc.ConsoleLogOptions{ReadLog: true})
+	if err != nil {
+		return "", err
+	}
+
+	current := string(msg)
+	previous, _ := lxcConsoleBuffers.Swap(d.id, current)
+	previousStr, _ := previous.(string)
+
+	return consoleBufferDiff(previousStr, current), nil
+}
+
 // Exec executes a command inside the instance.
 func (d *lxc) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	// Generate the LXC config if missing.
@@ -7774,6 +8156,20 @@ func (d *lxc) cpuState() api.InstanceStateCPU {
 	return cpu
 }
 
+func (d *lxc) pressureState() *api.InstanceStatePressure {
+	cc, err := d.initLXC(false)
+	if err != nil {
+		return nil
+	}
+
+	cg, err := d.cgroup(cc, true)
+	if err != nil {
+		return nil
+	}
+
+	return linux.PressureState(cg.GetPressure)
+}
+
 func (d *lxc) diskState() map[string]api.InstanceStateDisk {
 	disk := map[string]api.InstanceStateDisk{}
 
@@ -7830,6 +8226,56 @@ func (d *lxc) diskState() map[string]api.InstanceStateDisk {
 	return disk
 }
 
+// diskIOState adds the I/O statistics of the block devices backing the disk devices to the disk info.
+// Disks backed by a filesystem without a block device (e.g. btrfs or zfs) don't get any statistics.
+func (d *lxc) diskIOState(disk map[string]api.InstanceStateDisk, pid int) {
+	cc, err := d.initLXC(false)
+	if err != nil {
+		return
+	}
+
+	cg, err := d.cgroup(cc, true)
+	if err != nil {
+		return
+	}
+
+	if !d.state.OS.CGInfo.Supports(cgroup.Blkio, cg) {
+		return
+	}
+
+	ioStats, err := cg.GetIOStatsByDevice()
+	if err != nil {
+		d.logger.Warn("Failed to get disk stats", logger.Ctx{"err": err})
+		return
+	}
+
+	mounts, err := linux.MountDevices(pid)
+	if err != nil {
+		d.logger.Warn("Failed to get the container mounts", logger.Ctx{"err": err})
+		return
+	}
+
+	for _, dev := range d.expandedDevices.Sorted() {
+		if dev.Config["type"] != "disk" || dev.Config["path"] == "" {
+			continue
+		}
+
+		devID, ok := mounts[filepath.Join("/", dev.Config["path"])]
+		if !ok || ioStats[devID] == nil {
+			continue
+		}
+
+		stats := ioStats[devID]
+
+		state := disk[dev.Name]
+		state.ReadBytes = int64(stats.ReadBytes)
+		state.ReadOps = int64(stats.ReadsCompleted)
+		state.WriteBytes = int64(stats.WrittenBytes)
+		state.WriteOps = int64(stats.WritesCompleted)
+		disk[dev.Name] = state
+	}
+}
+
 func (d *lxc) memoryState() api.InstanceStateMemory {
 	memory := api.InstanceStateMemory{}
 
@@ -7885,6 +8331,12 @@ func (d *lxc) memoryState() api.InstanceStateMemory {
 		}
 	}
 
+	// Out of memory kills
+	oomKills, err := cg.GetOOMKills()
+	if err == nil {
+		memory.OOMKills = oomKills
+	}
+
 	return memory
 }
 
@@ -8933,6 +9385,19 @@ func (d *lxc) Metrics(hostInterfaces []net.Interface) (*metrics.MetricSet, error
 		out.AddSamples(metrics.CPUs, metrics.Sample{Value: float64(CPUs)})
 	}
 
+	// Get pressure stats
+	for _, resource := range linux.PressureResources {
+		pressure, err := cg.GetPressure(resource)
+		if err != nil {
+			continue
+		}
+
+		labels := map[string]string{"resource": resource}
+
+		out.AddSamples(metrics.PressureSomeSecondsTotal, metrics.Sample{Value: float64(pressure.Some.Total) / 1000000, Labels: labels})
+		out.AddSamples(metrics.PressureFullSecondsTotal, metrics.Sample{Value: float64(pressure.Full.Total) / 1000000, Labels: labels})
+	}
+
 	// Get disk stats
 	diskStats, err := cg.GetIOStats()
 	if err != nil {
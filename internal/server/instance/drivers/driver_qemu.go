@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"sort"
 	"strconv"
@@ -49,6 +50,7 @@ import (
 	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/ports"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cgroup"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -115,6 +117,15 @@ const qemuBlockDevIDPrefix = "incus_"
 // qemuMigrationNBDExportName is the name of the disk device export by the migration NBD server.
 const qemuMigrationNBDExportName = "incus_root"
 
+// qemuTimeSyncAttempts is the number of attempts (one per second) at reaching the agent to synchronize the guest clock.
+const qemuTimeSyncAttempts = 30
+
+// qemuDiskDetachTimeout is how long the guest is given to release a disk being hot-removed.
+const qemuDiskDetachTimeout = 30 * time.Second
+
+// qemuUSBDeviceSuffix matches the bus and device numbers suffix of passed through USB devices.
+var qemuUSBDeviceSuffix = regexp.MustCompile(`^[0-9]{3}-[0-9]{3}$`)
+
 // qemuSparseUSBPorts is the amount of sparse USB ports for VMs.
 // 4 are reserved, and the other 4 can be used for any USB device.
 const qemuSparseUSBPorts = 8
@@ -267,6 +278,11 @@ func qemuCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operati
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = d.validateCPURequirements()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Retrieve the instance's storage pool.
@@ -455,7 +471,7 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 	state := d.state
 
 	return func(event string, data map[string]any) {
-		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted, qmp.EventRTCChange}, event) {
+		if !slices.Contains([]string{qmp.EventVMShutdown, qmp.EventAgentStarted, qmp.EventRTCChange, qmp.EventGuestPanicked}, event) {
 			return // Don't bother loading the instance from DB if we aren't going to handle the event.
 		}
 
@@ -503,7 +519,8 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 				d.logger.Debug("Instance stopped", logger.Ctx{"target": target, "reason": data["reason"]})
 			}
 
-			err = d.onStop(target)
+			// Anything but a clean power off from within the guest is treated as a failure.
+			err = d.onStop(target, entry != "guest-shutdown")
 			if err != nil {
 				d.logger.Error("Failed to cleanly stop instance", logger.Ctx{"err": err})
 				return
@@ -520,6 +537,11 @@ func (d *qemu) getMonitorEventHandler() func(event string, data map[string]any)
 			if err != nil {
 				d.logger.Error("Failed to apply rtc change", logger.Ctx{"offset": val, "err": err})
 			}
+
+		case qmp.EventGuestPanicked:
+			// The guest is left paused for investigation (see the panic action set on start).
+			d.logger.Warn("Instance kernel panicked", logger.Ctx{"info": data["info"]})
+			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstancePanicked.Event(d, nil))
 		}
 	}
 }
@@ -657,7 +679,7 @@ func (d *qemu) pidWait(timeout time.Duration) bool {
 }
 
 // onStop is run when the instance stops.
-func (d *qemu) onStop(target string) error {
+func (d *qemu) onStop(target string, failure bool) error {
 	d.logger.Debug("onStop hook started", logger.Ctx{"target": target})
 	defer d.logger.Debug("onStop hook finished", logger.Ctx{"target": target})
 
@@ -686,21 +708,30 @@ func (d *qemu) onStop(target string) error {
 		d.logger.Error("VM process failed to stop", logger.Ctx{"timeout": waitTimeout})
 	}
 
-	// Record power state.
+	// Record power state and release any reserved CPU cores.
 	err = d.VolatileSet(map[string]string{
 		"volatile.last_state.power": instance.PowerStateStopped,
 		"volatile.last_state.ready": "false",
+		"volatile.cpu.isolated":     "",
 	})
 	if err != nil {
 		// Don't return an error here as we still want to cleanup the instance even if DB not available.
 		d.logger.Error("Failed recording last power state", logger.Ctx{"err": err})
 	}
 
+	cgroup.TaskSchedulerTrigger("virtual-machine", d.name, "stopped")
+
 	// Cleanup.
 	d.cleanupDevices() // Must be called before unmount.
+	err = d.hugepagesRelease()
+	if err != nil {
+		d.logger.Warn("Failed releasing huge pages", logger.Ctx{"err": err})
+	}
+
 	_ = os.Remove(d.pidFilePath())
 	_ = os.Remove(d.monitorPath())
 	_ = os.Remove(d.spicePath())
+	_ = os.Remove(d.vncPath())
 
 	// Stop the storage for the instance.
 	err = d.unmount()
@@ -717,11 +748,25 @@ func (d *qemu) onStop(target string) error {
 		return err
 	}
 
+	// Run the user-defined post-stop hook.
+	if op.Action() != operationlock.ActionMigrate {
+		_ = d.runLifecycleHook("post-stop")
+	}
+
 	// Determine if instance should be auto-restarted.
 	var autoRestart bool
-	if target != "reboot" && op.GetInstanceInitiated() && d.shouldAutoRestart() {
-		autoRestart = true
+	var restartScheduled bool
+	if target != "reboot" && op.GetInstanceInitiated() {
+		var restartDelay time.Duration
+		autoRestart, restartDelay = d.shouldRestart(failure)
+		if autoRestart && restartDelay > 0 {
+			d.scheduleRestart(restartDelay)
+			autoRestart = false
+			restartScheduled = true
+		}
+	}
 
+	if autoRestart {
 		// Mark current shutdown as complete.
 		op.Done(nil)
 
@@ -750,7 +795,7 @@ func (d *qemu) onStop(target string) error {
 		}
 
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceRestarted.Event(d, nil))
-	} else if d.ephemeral {
+	} else if d.ephemeral && !restartScheduled {
 		// Destroy ephemeral virtual machines.
 		err = d.delete(true)
 		if err != nil {
@@ -806,6 +851,15 @@ func (d *qemu) Shutdown(timeout time.Duration) error {
 		}
 	}
 
+	// Run the user-defined pre-stop hook.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-stop")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Connect to the monitor.
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
@@ -873,6 +927,11 @@ func (d *qemu) Rebuild(img *api.Image, op *operations.Operation) error {
 	return d.rebuildCommon(d, img, op)
 }
 
+// RebuildFromBackup rebuilds the instance using the supplied backup file as source.
+func (d *qemu) RebuildFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
+	return d.rebuildFromBackupCommon(d, srcBackup, srcData, op)
+}
+
 // killQemuProcess kills specified process. Optimistically attempts to wait for the process to fully exit, but does
 // not return an error if the Wait call fails. This is because this function is used in scenarios where the daemon has
 // been restarted after the VM has been started and is no longer the parent of the QEMU process.
@@ -1093,7 +1152,7 @@ func (d *qemu) saveState(monitor *qmp.Monitor) error {
 		return fmt.Errorf("Failed initializing state save to %q: %w", stateFile.Name(), err)
 	}
 
-	err = monitor.MigrateWait("completed")
+	err = monitor.MigrateWait("completed", nil)
 	if err != nil {
 		return fmt.Errorf("Failed saving state to %q: %w", stateFile.Name(), err)
 	}
@@ -1129,6 +1188,24 @@ func (d *qemu) validateStartup(stateful bool, statusCode api.StatusCode) error {
 		return errors.New("Secure boot can't be enabled while CSM is turned on. Please set security.secureboot=false on the instance")
 	}
 
+	// Ensure secureboot is enabled when custom keys are provided.
+	if d.secureBootKeysConfigured() && (util.IsFalse(d.expandedConfig["security.secureboot"]) || util.IsTrue(d.expandedConfig["security.csm"])) {
+		return errors.New("Custom secure boot keys require security.secureboot to be enabled")
+	}
+
+	// Ensure the Windows drivers are available when requested.
+	if util.IsTrue(d.expandedConfig["windows.drivers"]) {
+		_, found := d.expandedDevices[qemuWindowsDriversDevName]
+		if found {
+			return fmt.Errorf("Device name %q is reserved when windows.drivers is enabled", qemuWindowsDriversDevName)
+		}
+
+		_, err := d.windowsDriversPath()
+		if err != nil {
+			return err
+		}
+	}
+
 	// gendoc:generate(entity=image, group=requirements, key=requirements.cdrom_agent)
 	//
 	// ---
@@ -1284,6 +1361,15 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 
 	defer op.Done(err)
 
+	// Run the user-defined pre-start hook, unless the instance is only being moved.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-start")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Assign NUMA node(s) if needed.
 	if d.expandedConfig["limits.cpu.nodes"] == "balanced" {
 		err := d.balanceNUMANodes()
@@ -1303,6 +1389,21 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	// Reserve CPU cores if needed.
+	if d.expandedConfig["limits.cpu.pin_strategy"] == "isolated" {
+		err := d.allocateIsolatedCPUs()
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+
+		reverter.Add(func() { _ = d.VolatileSet(map[string]string{"volatile.cpu.isolated": ""}) })
+
+		if !d.state.OS.CoreScheduling {
+			d.logger.Warn("Core scheduling isn't supported, the reserved CPU cores may be shared with host processes")
+		}
+	}
+
 	// Rotate the log files.
 	for _, logfile := range []string{d.LogFilePath(), d.ConsoleBufferLogPath(), d.QMPLogFilePath()} {
 		if util.PathExists(logfile) {
@@ -1325,7 +1426,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	}
 
 	// Cleanup old sockets.
-	for _, socketPath := range []string{d.consolePath(), d.spicePath(), d.monitorPath()} {
+	for _, socketPath := range []string{d.consolePath(), d.spicePath(), d.vncPath(), d.monitorPath()} {
 		_ = os.Remove(socketPath)
 	}
 
@@ -1508,6 +1609,20 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		devConfs = append(devConfs, runConf)
 	}
 
+	// Attach the Windows drivers if requested.
+	if util.IsTrue(d.expandedConfig["windows.drivers"]) {
+		runConf, err := d.windowsDriversRunConfig()
+		if err != nil {
+			err = fmt.Errorf("Failed attaching the Windows drivers: %w", err)
+			op.Done(err)
+			return err
+		}
+
+		reverter.Add(runConf.Revert)
+		postStartHooks = append(postStartHooks, runConf.PostHooks...)
+		devConfs = append(devConfs, runConf)
+	}
+
 	// Setup the config drive readonly bind mount. Important that this come after the root disk device start.
 	// in order to allow unmounts triggered by deferred resizes of the root volume.
 	configMntPath := d.configDriveMountPath()
@@ -1562,7 +1677,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	}
 
 	// Get CPU information.
-	cpuInfo, err := d.cpuTopology(d.expandedConfig["limits.cpu"])
+	cpuInfo, err := d.cpuTopology(d.cpuLimit())
 	if err != nil {
 		return err
 	}
@@ -1582,6 +1697,11 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		if cpuInfo.threads > 1 {
 			cpuExtensions = append(cpuExtensions, "topoext")
 		}
+
+		// Hide the kvm-clock clock source if requested.
+		if util.IsFalse(d.expandedConfig["clock.kvm_clock"]) {
+			cpuExtensions = append(cpuExtensions, "-kvmclock")
+		}
 	}
 
 	cpuType := "host"
@@ -1693,6 +1813,7 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		"-sandbox", "on,obsolete=deny,elevateprivileges=allow,spawn=allow,resourcecontrol=deny",
 		"-readconfig", confFile,
 		"-spice", d.spiceCmdlineConfig(),
+		"-vnc", d.vncCmdlineConfig(),
 		"-pidfile", d.pidFilePath(),
 		"-D", d.LogFilePath(),
 	}
@@ -1726,12 +1847,16 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 	// APply the RTC configuration.
 	adjustment := d.getStartupRTCAdjustment()
 
+	// Default to localtime on Windows and to UTC otherwise.
+	rtcBase := d.expandedConfig["clock.rtc_base"]
+	if rtcBase == "" && d.isWindows() {
+		rtcBase = "localtime"
+	}
+
 	base := time.Now().Add(adjustment)
-	if d.isWindows() {
-		// Set base to localtime on windows.
+	if rtcBase == "localtime" {
 		base = base.Local()
 	} else {
-		// set base to UTC on !windows.
 		base = base.UTC()
 	}
 
@@ -1814,9 +1939,36 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		}
 	}
 
+	// Grow the host huge pages pool if needed.
+	if d.hugepagesEnabled() && d.state.GlobalConfig.InstancesHugepagesAutoAllocate() {
+		memSize := d.expandedConfig["limits.memory"]
+		if memSize == "" {
+			memSize = qemudefault.MemSize
+		}
+
+		memSizeBytes, err := ParseMemoryStr(memSize)
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+
+		reverter.Add(func() {
+			err := d.hugepagesRelease()
+			if err != nil {
+				d.logger.Warn("Failed releasing huge pages", logger.Ctx{"err": err})
+			}
+		})
+
+		err = d.hugepagesAllocate(memSizeBytes)
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Handle hugepages on architectures where we don't set NUMA nodes.
-	if d.architecture != osarch.ARCH_64BIT_INTEL_X86 && util.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
-		hugetlb, err := localUtil.HugepagesPath()
+	if d.architecture != osarch.ARCH_64BIT_INTEL_X86 && d.hugepagesEnabled() {
+		hugetlb, err := d.hugepagesPath()
 		if err != nil {
 			op.Done(err)
 			return err
@@ -1979,6 +2131,21 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 				op.Done(err)
 				return err
 			}
+		} else {
+			// Get the list of PIDs from the VM.
+			pids, err := monitor.GetCPUs()
+			if err != nil {
+				op.Done(err)
+				return err
+			}
+
+			// Create a core scheduling group.
+			err = d.setCoreSched(pids)
+			if err != nil {
+				err = fmt.Errorf("Failed to allocate new core scheduling domain for vCPU threads: %w", err)
+				op.Done(err)
+				return err
+			}
 		}
 	} else {
 		// Get the list of PIDs from the VM.
@@ -2079,6 +2246,9 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 
 	// Finish handling stateful start.
 	if stateful {
+		// The guest clock is behind by however long the state was saved for.
+		d.syncTimeBackground()
+
 		// Cleanup state.
 		_ = os.Remove(d.StatePath())
 		d.stateful = false
@@ -2101,6 +2271,9 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 
 	reverter.Success()
 
+	// Keep the other instances off the reserved CPU cores.
+	cgroup.TaskSchedulerTrigger("virtual-machine", d.name, "started")
+
 	// Post-start startup hook
 	err = d.startupHook(monitor, "post-start")
 	if err != nil {
@@ -2121,6 +2294,10 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		return err
 	}
 
+	if op.Action() != operationlock.ActionMigrate {
+		_ = d.runLifecycleHook("post-start")
+	}
+
 	if op.Action() == "start" {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
 	}
@@ -2354,6 +2531,14 @@ func (d *qemu) setupNvram() error {
 		return err
 	}
 
+	// Enroll the custom secure boot keys.
+	if util.IsFalseOrEmpty(d.expandedConfig["security.csm"]) && util.IsTrueOrEmpty(d.expandedConfig["security.secureboot"]) && d.secureBootKeysConfigured() {
+		err = d.enrollSecureBootKeys(filepath.Join(d.Path(), efiVarsName))
+		if err != nil {
+			return err
+		}
+	}
+
 	nvramPath := d.nvramPath()
 
 	// Handle the case where the firmware vars filename matches our internal one.
@@ -2678,32 +2863,56 @@ func (d *qemu) deviceDetachBlockDevice(deviceName string, rawConfig deviceConfig
 	deviceID := fmt.Sprintf("%s%s", qemuDeviceIDPrefix, escapedDeviceName)
 	blockDevName := d.blockNodeName(escapedDeviceName)
 
-	err = monitor.RemoveFDFromFDSet(blockDevName)
+	// Request the removal of the device from the guest.
+	err = monitor.RemoveDevice(deviceID)
 	if err != nil {
 		return err
 	}
 
-	err = monitor.RemoveDevice(deviceID)
-	if err != nil {
-		return err
+	// Wait for the guest to release the device (PCI based disks need the guest to acknowledge the unplug).
+	// If it doesn't, keep the block node around so the guest is never left with a disk lacking its backend.
+	waitUntil := time.Now().Add(qemuDiskDetachTimeout)
+	for {
+		devIDs, err := monitor.QueryPeripherals()
+		if err != nil {
+			return fmt.Errorf("Failed getting devices to check for disk detach: %w", err)
+		}
+
+		if !slices.Contains(devIDs, deviceID) {
+			break
+		}
+
+		if time.Now().After(waitUntil) {
+			return fmt.Errorf("Guest didn't release disk %q after %v, the device remains attached until the guest acknowledges the removal", deviceName, qemuDiskDetachTimeout)
+		}
+
+		d.logger.Debug("Waiting for the guest to release disk device", logger.Ctx{"device": deviceName})
+		time.Sleep(time.Second)
 	}
 
-	waitDuration := time.Duration(time.Second * time.Duration(10))
-	waitUntil := time.Now().Add(waitDuration)
+	// Remove the block node now that no device uses it anymore.
+	waitUntil = time.Now().Add(qemuDiskDetachTimeout)
 	for {
 		err = monitor.RemoveBlockDevice(blockDevName)
 		if err == nil {
 			break
 		}
 
-		if api.StatusErrorCheck(err, http.StatusLocked) {
-			time.Sleep(time.Second * time.Duration(2))
-			continue
+		if time.Now().After(waitUntil) {
+			return fmt.Errorf("Failed to detach block device after %v: %w", qemuDiskDetachTimeout, err)
 		}
 
-		if time.Now().After(waitUntil) {
-			return fmt.Errorf("Failed to detach block device after %v", waitDuration)
+		if !api.StatusErrorCheck(err, http.StatusLocked) {
+			return err
 		}
+
+		time.Sleep(time.Second)
+	}
+
+	// Finally release the file descriptor passed to QEMU for the disk.
+	err = monitor.RemoveFDFromFDSet(blockDevName)
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -2903,6 +3112,12 @@ func (d *qemu) deviceStop(dev device.Device, instanceRunning bool, _ string) err
 					return err
 				}
 			}
+
+			// Detach any remaining USB device which is no longer present on the host.
+			err = d.deviceDetachUSBLeftovers(dev.Name())
+			if err != nil {
+				return err
+			}
 		}
 
 		// Detach disk from running instance.
@@ -3062,6 +3277,14 @@ func (d *qemu) spiceCmdlineConfig() string {
 	return fmt.Sprintf("unix=on,disable-ticketing=on,addr=%s", d.spicePath())
 }
 
+func (d *qemu) vncPath() string {
+	return filepath.Join(d.RunPath(), "qemu.vnc")
+}
+
+func (d *qemu) vncCmdlineConfig() string {
+	return fmt.Sprintf("unix:%s", d.vncPath())
+}
+
 // generateConfigShare generates the config share directory that will be exported to the VM via
 // a 9P share. Due to the unknown size of templates inside the images this directory is created
 // inside the VM's config volume so that it can be restricted by quota.
@@ -3493,9 +3716,32 @@ func (d *qemu) deviceBootPriorities(base int) (map[string]int, error) {
 	return sortedDevs, nil
 }
 
+// bootOrder returns the names of the bootable devices in the order the firmware tries them.
+func (d *qemu) bootOrder() ([]string, error) {
+	bootIndexes, err := d.deviceBootPriorities(0)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]string, 0, len(bootIndexes))
+	for name := range bootIndexes {
+		// Shared filesystems and the agent drive can't be booted from.
+		dev := d.expandedDevices[name]
+		if dev["type"] == "disk" && ((dev["path"] != "" && dev["path"] != "/") || dev["source"] == "agent:config") {
+			continue
+		}
+
+		devices = append(devices, name)
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return bootIndexes[devices[i]] < bootIndexes[devices[j]] })
+
+	return devices, nil
+}
+
 // isWindows returns whether the VM is Windows.
 func (d *qemu) isWindows() bool {
-	return strings.Contains(strings.ToLower(d.expandedConfig["image.os"]), "windows")
+	return strings.Contains(strings.ToLower(d.expandedConfig["image.os"]), "windows") || util.IsTrue(d.expandedConfig["windows.drivers"])
 }
 
 func (d *qemu) getStartupRTCAdjustment() time.Duration {
@@ -3633,6 +3879,11 @@ func (d *qemu) generateQemuConfig(machineDefinition string, cpuInfo *cpuTopology
 	// VM core info (memory dump).
 	conf = append(conf, qemuCoreInfo()...)
 
+	// Guest panic notification.
+	if d.architecture == osarch.ARCH_64BIT_INTEL_X86 {
+		conf = append(conf, qemuPVPanic()...)
+	}
+
 	// Setup the bus allocator.
 	bus := qemuNewBus(busName, &conf)
 
@@ -4123,8 +4374,8 @@ func (d *qemu) getCPUOpts(cpuInfo *cpuTopology, memSizeBytes int64) (*qemuCPUOpt
 	}
 
 	cpuOpts.hugepages = ""
-	if util.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
-		hugetlb, err := localUtil.HugepagesPath()
+	if d.hugepagesEnabled() {
+		hugetlb, err := d.hugepagesPath()
 		if err != nil {
 			return nil, err
 		}
@@ -4166,7 +4417,8 @@ func (d *qemu) addCPUMemoryConfig(conf *[]cfg.Section, cpuInfo *cpuTopology) err
 		return err
 	}
 
-	if maxMemoryBytes < memSizeBytes {
+	// Without memory hotplug support, don't reserve any space for additional memory.
+	if maxMemoryBytes < memSizeBytes || !d.MemoryHotplugSupported() {
 		maxMemoryBytes = memSizeBytes
 	}
 
@@ -5282,6 +5534,15 @@ func (d *qemu) Stop(stateful bool) error {
 		return err
 	}
 
+	// Run the user-defined pre-stop hook.
+	if op.Action() != operationlock.ActionMigrate {
+		err = d.runLifecycleHook("pre-stop")
+		if err != nil {
+			op.Done(err)
+			return err
+		}
+	}
+
 	// Connect to the monitor.
 	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
 	if err != nil {
@@ -5296,7 +5557,7 @@ func (d *qemu) Stop(stateful bool) error {
 		}
 
 		// Wait for QEMU process to exit and perform device cleanup.
-		err = d.onStop("stop")
+		err = d.onStop("stop", false)
 		if err != nil {
 			op.Done(err)
 			return err
@@ -5402,10 +5663,62 @@ func (d *qemu) Unfreeze() error {
 		return err
 	}
 
+	d.syncTimeBackground()
+
 	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceResumed.Event(d, nil))
 	return nil
 }
 
+// SyncTime has the agent set the guest clock to the host time.
+func (d *qemu) SyncTime() error {
+	if !d.IsRunning() || d.IsFrozen() {
+		return api.StatusErrorf(http.StatusBadRequest, "Instance isn't running")
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(nil, client)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	_, _, err = agent.RawQuery("PUT", "/1.0/time", agentAPI.TimePut{Time: time.Now()}, "")
+	if err != nil {
+		return fmt.Errorf("Failed synchronizing guest clock: %w", err)
+	}
+
+	return nil
+}
+
+// syncTimeBackground synchronizes the guest clock (unless disabled) once the agent is reachable.
+func (d *qemu) syncTimeBackground() {
+	if util.IsFalse(d.expandedConfig["agent.time_sync"]) {
+		return
+	}
+
+	go func() {
+		var err error
+
+		// The agent may take a little while to reconnect after the guest resumes.
+		for range qemuTimeSyncAttempts {
+			err = d.SyncTime()
+			if err == nil {
+				d.logger.Debug("Guest clock synchronized")
+				return
+			}
+
+			time.Sleep(time.Second)
+		}
+
+		d.logger.Warn("Failed synchronizing guest clock", logger.Ctx{"err": err})
+	}()
+}
+
 // IsPrivileged does not apply to virtual machines. Always returns false.
 func (d *qemu) IsPrivileged() bool {
 	return false
@@ -5416,6 +5729,9 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 	var err error
 	var monitor *qmp.Monitor
 
+	reverter := revert.New()
+	defer reverter.Fail()
+
 	// Deal with state.
 	if stateful {
 		// Confirm the instance has stateful migration enabled.
@@ -5440,6 +5756,16 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 			return err
 		}
 
+		// Make sure the guest doesn't remain paused and no stale state is left behind on failure.
+		reverter.Add(func() {
+			_ = os.Remove(d.StatePath())
+
+			err := monitor.Start()
+			if err != nil {
+				d.logger.Error("Failed resuming instance after failed stateful snapshot", logger.Ctx{"err": err})
+			}
+		})
+
 		// Dump the state.
 		err = d.saveState(monitor)
 		if err != nil {
@@ -5467,6 +5793,8 @@ func (d *qemu) snapshot(name string, expiry time.Time, stateful bool) error {
 		}
 	}
 
+	reverter.Success()
+
 	return nil
 }
 
@@ -6050,6 +6378,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	if isRunning {
 		// Only certain keys can be changed on a running VM.
 		liveUpdateKeys := []string{
+			"agent.time_sync",
 			"cluster.evacuate",
 			"limits.memory",
 			"security.agent.metrics",
@@ -6064,6 +6393,7 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			"cloud-init.",
 			"environment.",
 			"image.",
+			"security.secureboot.",
 			"snapshots.",
 			"user.",
 			"volatile.",
@@ -6105,6 +6435,10 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			if key == "limits.cpu" {
 				oldValue := oldExpandedConfig["limits.cpu"]
 
+				if d.expandedConfig["limits.cpu.pin_strategy"] == "isolated" {
+					return fmt.Errorf("Cannot update key %q when using CPU isolation and the VM is running", key)
+				}
+
 				if oldValue != "" {
 					_, err := strconv.Atoi(oldValue)
 					if err != nil {
@@ -6130,14 +6464,12 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			} else if key == "limits.memory" {
 				err = d.updateMemoryLimit(value)
 				if err != nil {
-					if err != nil {
-						return fmt.Errorf("Failed updating memory limit: %w", err)
-					}
+					return fmt.Errorf("Failed updating memory limit: %w", err)
 				}
 			} else if key == "security.csm" {
 				// Defer rebuilding nvram until next start.
 				d.localConfig["volatile.apply_nvram"] = "true"
-			} else if key == "security.secureboot" {
+			} else if key == "security.secureboot" || strings.HasPrefix(key, "security.secureboot.") {
 				// Defer rebuilding nvram until next start.
 				d.localConfig["volatile.apply_nvram"] = "true"
 			} else if key == "security.guestapi" {
@@ -6308,16 +6640,12 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 	return nil
 }
 
-// updateMemoryLimit live updates the VM's memory limit by reszing the balloon device.
+// updateMemoryLimit live updates the VM's memory limit by hotplugging memory or resizing the balloon device.
 func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if newLimit == "" {
 		return nil
 	}
 
-	if util.IsTrue(d.expandedConfig["limits.memory.hugepages"]) {
-		return errors.New("Cannot live update memory limit when using huge pages")
-	}
-
 	// Check new size string is valid and convert to bytes.
 	newSizeBytes, err := ParseMemoryStr(newLimit)
 	if err != nil {
@@ -6349,9 +6677,22 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 	if curSizeMB == newSizeMB {
 		return nil
 	} else if baseSizeMB < newSizeMB {
+		if !d.MemoryHotplugSupported() {
+			if d.hugepagesEnabled() {
+				return errors.New("Memory hotplug isn't supported when using huge pages, restart the VM to apply the new memory limit")
+			}
+
+			return errors.New("Memory hotplug isn't supported by QEMU on this system, restart the VM to apply the new memory limit")
+		}
+
 		return d.hotplugMemory(monitor, newSizeBytes-curSizeBytes)
 	}
 
+	// The balloon device can't reclaim memory backed by huge pages.
+	if d.hugepagesEnabled() {
+		return errors.New("Cannot live update memory limit when using huge pages")
+	}
+
 	// Set effective memory size.
 	err = monitor.SetMemoryBalloonSizeBytes(newSizeBytes)
 	if err != nil {
@@ -6389,7 +6730,7 @@ func (d *qemu) updateMemoryLimit(newLimit string) error {
 // respecting NUMA node placement and hugepages.
 func (d *qemu) hotplugMemory(monitor *qmp.Monitor, sizeBytes int64) error {
 	// Get CPU information.
-	cpuInfo, err := d.cpuTopology(d.expandedConfig["limits.cpu"])
+	cpuInfo, err := d.cpuTopology(d.cpuLimit())
 	if err != nil {
 		return err
 	}
@@ -6721,13 +7062,17 @@ func (d *qemu) delete(force bool) error {
 }
 
 // Export publishes the instance.
-func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
+func (d *qemu) Export(metaWriter io.Writer, rootfsWriter io.Writer, properties map[string]string, expiration time.Time, stateful bool, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error) {
 	ctxMap := logger.Ctx{
 		"created":   d.creationDate,
 		"ephemeral": d.ephemeral,
 		"used":      d.lastUsedDate,
 	}
 
+	if stateful {
+		return nil, errors.New("Stateful export isn't supported for virtual machines")
+	}
+
 	if d.IsRunning() {
 		return nil, errors.New("Cannot export a running instance as an image")
 	}
@@ -7515,7 +7860,7 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	// Non-shared storage snapshot transfer finalization.
 	if !sameSharedStorage {
 		// Wait until state transfer has reached pre-switchover state (the guest OS will remain paused).
-		err = monitor.MigrateWait("pre-switchover")
+		err = monitor.MigrateWait("pre-switchover", d.migrationProgress)
 		if err != nil {
 			return fmt.Errorf("Failed waiting for state transfer to reach pre-switchover stage: %w", err)
 		}
@@ -7541,7 +7886,7 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	}
 
 	// Wait until the migration state transfer has completed (the guest OS will remain paused).
-	err = monitor.MigrateWait("completed")
+	err = monitor.MigrateWait("completed", d.migrationProgress)
 	if err != nil {
 		return fmt.Errorf("Failed waiting for state transfer to reach completed stage: %w", err)
 	}
@@ -7587,6 +7932,18 @@ func (d *qemu) migrateSendLive(pool storagePools.Pool, clusterMoveSourceName str
 	return nil
 }
 
+// migrationProgress reports the progress of the state transfer of a live migration.
+func (d *qemu) migrationProgress(info *qmp.MigrationInfo) {
+	d.updateMigrationProgress(api.MigrationStateProgress{
+		Iteration:        info.RAM.DirtySyncCount,
+		TransferredBytes: info.RAM.Transferred,
+		RemainingBytes:   info.RAM.Remaining,
+		TotalBytes:       info.RAM.Total,
+		DirtyRate:        info.RAM.DirtyPagesRate * info.RAM.PageSize,
+		Speed:            int64(info.RAM.Mbps * 1000 * 1000 / 8),
+	})
+}
+
 func (d *qemu) MigrateReceive(args instance.MigrateReceiveArgs) error {
 	d.logger.Debug("Migration receive starting")
 	defer d.logger.Debug("Migration receive stopped")
@@ -8158,6 +8515,8 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 		path = d.consolePath()
 	case instance.ConsoleTypeVGA:
 		path = d.spicePath()
+	case instance.ConsoleTypeVNC:
+		path = d.vncPath()
 	default:
 		return nil, nil, fmt.Errorf("Unknown protocol %q", protocol)
 	}
@@ -8516,6 +8875,14 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 			}
 		}
 
+		// Report the huge pages backing the VM memory.
+		if d.hugepagesEnabled() && pid > 0 {
+			status.Memory.HugepagesUsage, err = d.hugepagesUsage(pid)
+			if err != nil {
+				d.logger.Warn("Error getting huge pages usage", logger.Ctx{"err": err})
+			}
+		}
+
 		status.Pid = int64(pid)
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
@@ -8530,6 +8897,22 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 		d.logger.Warn("Error getting disk usage", logger.Ctx{"err": err})
 	}
 
+	if d.isRunningStatusCode(statusCode) {
+		if status.Disk == nil {
+			status.Disk = map[string]api.InstanceStateDisk{}
+		}
+
+		err = d.diskIOState(status.Disk)
+		if err != nil {
+			d.logger.Warn("Error getting disk I/O statistics", logger.Ctx{"err": err})
+		}
+	}
+
+	status.BootOrder, err = d.bootOrder()
+	if err != nil {
+		d.logger.Warn("Error getting boot order", logger.Ctx{"err": err})
+	}
+
 	return status, nil
 }
 
@@ -8565,6 +8948,46 @@ func (d *qemu) diskState() (map[string]api.InstanceStateDisk, error) {
 	return disk, nil
 }
 
+// diskIOState adds the I/O statistics of the disk devices attached as block devices to the disk info.
+func (d *qemu) diskIOState(disk map[string]api.InstanceStateDisk) error {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	stats, err := monitor.GetBlockStats()
+	if err != nil {
+		return err
+	}
+
+	for _, dev := range d.expandedDevices.Sorted() {
+		if dev.Config["type"] != "disk" {
+			continue
+		}
+
+		// The qdev is either the device ID or the QOM path of the device (e.g. for virtio-blk).
+		deviceID := fmt.Sprintf("%s%s", qemuDeviceIDPrefix, linux.PathNameEncode(dev.Name))
+		for qdev, stat := range stats {
+			if qdev != deviceID && !strings.HasPrefix(qdev, fmt.Sprintf("/machine/peripheral/%s/", deviceID)) {
+				continue
+			}
+
+			state := disk[dev.Name]
+			state.ReadBytes = int64(stat.BytesRead)
+			state.ReadOps = int64(stat.ReadsCompleted)
+			state.ReadTime = int64(stat.ReadTime)
+			state.WriteBytes = int64(stat.BytesWritten)
+			state.WriteOps = int64(stat.WritesCompleted)
+			state.WriteTime = int64(stat.WriteTime)
+			disk[dev.Name] = state
+
+			break
+		}
+	}
+
+	return nil
+}
+
 // agentGetState connects to the agent inside of the VM and does
 // an API call to get the current state.
 func (d *qemu) agentGetState() (*api.InstanceState, error) {
@@ -8588,6 +9011,65 @@ func (d *qemu) agentGetState() (*api.InstanceState, error) {
 	return status, nil
 }
 
+// Processes returns the list of processes running in the VM, as reported by the agent.
+func (d *qemu) Processes() ([]api.InstanceProcess, error) {
+	if !d.IsRunning() {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Instance isn't running")
+	}
+
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(nil, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to agent: %w", err)
+	}
+
+	defer agent.Disconnect()
+
+	return agent.GetInstanceProcesses("")
+}
+
+// FreezeFilesystems asks the agent inside of the VM to freeze the guest filesystems
+// and returns a function to thaw them again.
+func (d *qemu) FreezeFilesystems() (func() error, error) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := incus.ConnectIncusHTTP(nil, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to agent: %w", err)
+	}
+
+	// The agent thaws the filesystems by itself should we fail to do so within the timeout.
+	_, _, err = agent.RawQuery("PUT", "/1.0/state", api.InstanceStatePut{Action: "freeze", Timeout: 60}, "")
+	if err != nil {
+		agent.Disconnect()
+		return nil, fmt.Errorf("Failed freezing guest filesystems: %w", err)
+	}
+
+	d.logger.Debug("Guest filesystems frozen")
+
+	thaw := func() error {
+		defer agent.Disconnect()
+
+		_, _, err := agent.RawQuery("PUT", "/1.0/state", api.InstanceStatePut{Action: "unfreeze"}, "")
+		if err != nil {
+			return fmt.Errorf("Failed thawing guest filesystems: %w", err)
+		}
+
+		d.logger.Debug("Guest filesystems thawed")
+
+		return nil
+	}
+
+	return thaw, nil
+}
+
 // IsRunning returns whether or not the instance is running.
 func (d *qemu) IsRunning() bool {
 	return d.isRunningStatusCode(d.statusCode())
@@ -9365,6 +9847,14 @@ func (d *qemu) checkFeatures(hostArch int, qemuPath string) (map[string]any, err
 		features["cpu_hotplug"] = struct{}{}
 	}
 
+	// Check memory hotplug feature.
+	_, err = monitor.QueryDeviceProperties("pc-dimm")
+	if err != nil {
+		logger.Debug("Failed querying pc-dimm device during VM feature check", logger.Ctx{"err": err})
+	} else {
+		features["memory_hotplug"] = struct{}{}
+	}
+
 	// Check AMD SEV features (only for x86 architecture)
 	if hostArch == osarch.ARCH_64BIT_INTEL_X86 {
 		cmdline, err := os.ReadFile("/proc/cmdline")
@@ -9600,6 +10090,36 @@ func (d *qemu) deviceDetachUSB(usbDev deviceConfig.USBDeviceItem) error {
 	return nil
 }
 
+// deviceDetachUSBLeftovers removes the USB devices passed through for the given device which are still attached
+// to the running instance, such as those which were unplugged from the host without the instance being notified.
+func (d *qemu) deviceDetachUSBLeftovers(deviceName string) error {
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	ids, err := monitor.QueryPeripherals()
+	if err != nil {
+		return err
+	}
+
+	// USB device IDs are made of the device name followed by the bus and device numbers.
+	prefix := fmt.Sprintf("%s%s-", qemuDeviceIDPrefix, deviceName)
+	for _, id := range ids {
+		suffix, found := strings.CutPrefix(id, prefix)
+		if !found || !qemuUSBDeviceSuffix.MatchString(suffix) {
+			continue
+		}
+
+		err = d.deviceDetachUSB(deviceConfig.USBDeviceItem{DeviceName: fmt.Sprintf("%s-%s", deviceName, suffix)})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Block node names may only be up to 31 characters long, so use a hash if longer.
 func (d *qemu) blockNodeName(name string) string {
 	if len(name) > 25 {
@@ -9698,7 +10218,9 @@ func (d *qemu) setCPUs(monitor *qmp.Monitor, count int) error {
 
 			reverter.Add(func() {
 				err := monitor.RemoveDevice(devID)
-				d.logger.Warn("Failed to remove CPU device", logger.Ctx{"err": err})
+				if err != nil {
+					d.logger.Warn("Failed to remove CPU device", logger.Ctx{"err": err})
+				}
 			})
 		}
 	} else {
@@ -9720,17 +10242,31 @@ func (d *qemu) setCPUs(monitor *qmp.Monitor, count int) error {
 			}
 
 			reverter.Add(func() {
-				err := monitor.AddDevice(map[string]any{
-					"id":        devID,
-					"driver":    cpu.Type,
-					"socket-id": cpu.Props.SocketID,
-					"core-id":   cpu.Props.CoreID,
-					"thread-id": cpu.Props.ThreadID,
-				})
-				d.logger.Warn("Failed to add CPU device", logger.Ctx{"err": err})
+				qemuDev := map[string]any{
+					"id":      devID,
+					"driver":  cpu.Type,
+					"core-id": cpu.Props.CoreID,
+				}
+
+				// No such thing as sockets and threads on s390x.
+				if d.architecture != osarch.ARCH_64BIT_S390_BIG_ENDIAN {
+					qemuDev["socket-id"] = cpu.Props.SocketID
+					qemuDev["thread-id"] = cpu.Props.ThreadID
+				}
+
+				err := monitor.AddDevice(qemuDev)
+				if err != nil {
+					d.logger.Warn("Failed to add CPU device", logger.Ctx{"err": err})
+				}
 			})
 		}
 
+		// Removal requires the guest to release the vCPUs, wait for it to do so.
+		err = d.waitCPUUnplug(monitor, count-1)
+		if err != nil {
+			return err
+		}
+
 		// QEMU doesn't immediately remove the thread from the vCPU list.
 		// Wait a second to allow the thread to fully exit and disappear from the vCPU list.
 		time.Sleep(time.Second)
@@ -9747,6 +10283,31 @@ func (d *qemu) setCPUs(monitor *qmp.Monitor, count int) error {
 	return nil
 }
 
+// waitCPUUnplug waits for the guest to release vCPUs until only the expected number of hotplugged vCPUs remain.
+func (d *qemu) waitCPUUnplug(monitor *qmp.Monitor, expected int) error {
+	for range 20 {
+		cpus, err := monitor.QueryHotpluggableCPUs()
+		if err != nil {
+			return fmt.Errorf("Failed to query hotpluggable CPUs: %w", err)
+		}
+
+		hotplugged := 0
+		for _, cpu := range cpus {
+			if strings.HasPrefix(cpu.QOMPath, "/machine/peripheral") {
+				hotplugged++
+			}
+		}
+
+		if hotplugged <= expected {
+			return nil
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return errors.New("The guest didn't release the removed vCPUs, it may not support CPU hot-unplug")
+}
+
 func (d *qemu) architectureSupportsCPUHotplug() bool {
 	// Check supported features.
 	info := DriverStatuses()[instancetype.VM].Info
@@ -9754,6 +10315,18 @@ func (d *qemu) architectureSupportsCPUHotplug() bool {
 	return found
 }
 
+func (d *qemu) architectureSupportsMemoryHotplug() bool {
+	// Check supported features.
+	info := DriverStatuses()[instancetype.VM].Info
+	_, found := info.Features["memory_hotplug"]
+	return found
+}
+
+// MemoryHotplugSupported returns whether memory can be added to the running VM.
+func (d *qemu) MemoryHotplugSupported() bool {
+	return d.architectureSupportsMemoryHotplug() && !d.hugepagesEnabled()
+}
+
 func (d *qemu) postCPUHotplug(monitor *qmp.Monitor) error {
 	// Get the vCPU PID list.
 	pids, err := monitor.GetCPUs()
@@ -9844,11 +10417,47 @@ func (d *qemu) ConsoleLog() (string, error) {
 		return "", err
 	}
 
+	_, err = d.consoleBufferFlush(monitor)
+	if err != nil {
+		return "", err
+	}
+
+	// Read and return the complete log for this instance.
+	fullLog, err := os.ReadFile(d.ConsoleBufferLogPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// If there's no log file yet, such as right at VM creation, return an empty string.
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return string(fullLog), nil
+}
+
+// ConsoleBufferRead returns the console output produced since the last read, moving it to the console log file.
+func (d *qemu) ConsoleBufferRead() (string, error) {
+	if !d.IsRunning() {
+		return "", nil
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler(), d.QMPLogFilePath())
+	if err != nil {
+		return "", err
+	}
+
+	return d.consoleBufferFlush(monitor)
+}
+
+// consoleBufferFlush empties the console ring buffer into the console log file, returning what it contained.
+func (d *qemu) consoleBufferFlush(monitor *qmp.Monitor) (string, error) {
 	logString, err := monitor.RingbufRead("console")
 	if err != nil {
 		// If a VM was started by an older version of Incus which was then upgraded, its
 		// console device won't be a ring buffer. We don't want to cause an error in this
 		// case, so just return an empty string.
+		// The same happens while a console is attached.
 		if errors.Is(err, qmp.ErrNotARingbuf) {
 			return "", nil
 		}
@@ -9871,18 +10480,7 @@ func (d *qemu) ConsoleLog() (string, error) {
 		}
 	}
 
-	// Read and return the complete log for this instance.
-	fullLog, err := os.ReadFile(d.ConsoleBufferLogPath())
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			// If there's no log file yet, such as right at VM creation, return an empty string.
-			return "", nil
-		}
-
-		return "", err
-	}
-
-	return string(fullLog), nil
+	return logString, nil
 }
 
 // consoleSwapRBWithSocket swaps the qemu backend for the instance's console to a unix socket.
@@ -0,0 +1,248 @@
+package drivers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/resources"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// muHugepages is used to serialize changes to the host hugepages pools.
+var muHugepages sync.Mutex
+
+// hugepagesEnabled returns whether the VM memory is backed by huge pages.
+func (d *qemu) hugepagesEnabled() bool {
+	value := d.expandedConfig["limits.memory.hugepages"]
+
+	return value != "" && !util.IsFalse(value)
+}
+
+// hugepagesSize returns the huge page size requested for the VM or 0 for the system default.
+func (d *qemu) hugepagesSize() (int64, error) {
+	value := d.expandedConfig["limits.memory.hugepages"]
+	if !d.hugepagesEnabled() || util.IsTrue(value) {
+		return 0, nil
+	}
+
+	// Page sizes are always binary units (2MB is 2MiB).
+	return units.ParseByteSizeString(strings.TrimSuffix(value, "B") + "iB")
+}
+
+// hugepagesPath returns the hugetlbfs mount to use for the VM memory, mounting one if needed.
+func (d *qemu) hugepagesPath() (string, error) {
+	pageSize, err := d.hugepagesSize()
+	if err != nil {
+		return "", err
+	}
+
+	if pageSize == 0 {
+		return localUtil.HugepagesPath()
+	}
+
+	hugetlb, err := localUtil.HugepagesPathForSize(pageSize)
+	if err == nil {
+		return hugetlb, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	// No existing mount for that page size, setup our own.
+	muHugepages.Lock()
+	defer muHugepages.Unlock()
+
+	// Another VM may have set it up in the meantime.
+	hugetlb, err = localUtil.HugepagesPathForSize(pageSize)
+	if err == nil {
+		return hugetlb, nil
+	}
+
+	hugetlb = internalUtil.VarPath("hugepages", strconv.FormatInt(pageSize/1024, 10)+"kB")
+	err = os.MkdirAll(hugetlb, 0o700)
+	if err != nil {
+		return "", err
+	}
+
+	err = unix.Mount("hugetlbfs", hugetlb, "hugetlbfs", 0, fmt.Sprintf("pagesize=%d", pageSize))
+	if err != nil {
+		return "", fmt.Errorf("Failed mounting hugetlbfs with %d bytes pages: %w", pageSize, err)
+	}
+
+	return hugetlb, nil
+}
+
+// hugepagesPoolPath returns the sysfs path of the host pool for the VM huge pages and the page size.
+func (d *qemu) hugepagesPoolPath() (string, int64, error) {
+	pageSize, err := d.hugepagesSize()
+	if err != nil {
+		return "", -1, err
+	}
+
+	if pageSize == 0 {
+		memory, err := resources.GetMemory()
+		if err != nil {
+			return "", -1, err
+		}
+
+		pageSize = int64(memory.HugepagesSize)
+	}
+
+	if pageSize <= 0 {
+		return "", -1, errors.New("Huge pages aren't supported on this system")
+	}
+
+	poolPath := fmt.Sprintf("/sys/kernel/mm/hugepages/hugepages-%dkB", pageSize/1024)
+	if !util.PathExists(poolPath) {
+		return "", -1, fmt.Errorf("Huge pages of %d bytes aren't supported on this system", pageSize)
+	}
+
+	return poolPath, pageSize, nil
+}
+
+// hugepagesAllocate grows the host pool so it can back the VM memory, recording how many pages were added.
+func (d *qemu) hugepagesAllocate(memSizeBytes int64) error {
+	poolPath, pageSize, err := d.hugepagesPoolPath()
+	if err != nil {
+		return err
+	}
+
+	muHugepages.Lock()
+	defer muHugepages.Unlock()
+
+	readPool := func(name string) (int64, error) {
+		content, err := os.ReadFile(filepath.Join(poolPath, name))
+		if err != nil {
+			return -1, err
+		}
+
+		return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	}
+
+	total, err := readPool("nr_hugepages")
+	if err != nil {
+		return err
+	}
+
+	free, err := readPool("free_hugepages")
+	if err != nil {
+		return err
+	}
+
+	reserved, err := readPool("resv_hugepages")
+	if err != nil {
+		return err
+	}
+
+	needed := (memSizeBytes + pageSize - 1) / pageSize
+	missing := needed - (free - reserved)
+	if missing <= 0 {
+		return nil
+	}
+
+	err = os.WriteFile(filepath.Join(poolPath, "nr_hugepages"), []byte(strconv.FormatInt(total+missing, 10)), 0o644)
+	if err != nil {
+		return fmt.Errorf("Failed growing the huge pages pool: %w", err)
+	}
+
+	// The kernel may not be able to allocate all the pages.
+	newTotal, err := readPool("nr_hugepages")
+	if err != nil {
+		return err
+	}
+
+	allocated := newTotal - total
+	if allocated > 0 {
+		err = d.VolatileSet(map[string]string{"volatile.vm.hugepages": fmt.Sprintf("%d:%d", allocated, pageSize)})
+		if err != nil {
+			return err
+		}
+	}
+
+	if allocated < missing {
+		return fmt.Errorf("Only %d of the %d missing huge pages could be allocated", allocated, missing)
+	}
+
+	return nil
+}
+
+// hugepagesRelease shrinks the host pool by the pages that were added when the VM started.
+func (d *qemu) hugepagesRelease() error {
+	value := d.localConfig["volatile.vm.hugepages"]
+	if value == "" {
+		return nil
+	}
+
+	countStr, sizeStr, _ := strings.Cut(value, ":")
+	count, err := strconv.ParseInt(countStr, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	pageSize, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	muHugepages.Lock()
+	defer muHugepages.Unlock()
+
+	poolPath := filepath.Join(fmt.Sprintf("/sys/kernel/mm/hugepages/hugepages-%dkB", pageSize/1024), "nr_hugepages")
+	content, err := os.ReadFile(poolPath)
+	if err != nil {
+		return err
+	}
+
+	total, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(poolPath, []byte(strconv.FormatInt(max(total-count, 0), 10)), 0o644)
+	if err != nil {
+		return fmt.Errorf("Failed shrinking the huge pages pool: %w", err)
+	}
+
+	return d.VolatileSet(map[string]string{"volatile.vm.hugepages": ""})
+}
+
+// hugepagesUsage returns the amount of huge pages memory used by the VM process.
+func (d *qemu) hugepagesUsage(pid int) (int64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/smaps_rollup", pid))
+	if err != nil {
+		return -1, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var usage int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || (fields[0] != "Shared_Hugetlb:" && fields[0] != "Private_Hugetlb:") {
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return -1, err
+		}
+
+		usage += value * 1024
+	}
+
+	return usage, scanner.Err()
+}
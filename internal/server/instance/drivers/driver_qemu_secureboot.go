@@ -0,0 +1,107 @@
+package drivers
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// qemuSecureBootOwnerGUID is the owner GUID used for the secure boot keys enrolled by Incus.
+const qemuSecureBootOwnerGUID = "bfc780d8-6472-4185-9782-f8c31cfc5473"
+
+// secureBootKeysConfigured returns whether custom secure boot keys are set for the instance.
+func (d *qemu) secureBootKeysConfigured() bool {
+	for _, key := range []string{"security.secureboot.pk", "security.secureboot.kek", "security.secureboot.db", "security.secureboot.dbx"} {
+		if d.expandedConfig[key] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enrollSecureBootKeys enrolls the custom secure boot keys of the instance into the EDK2 vars file.
+func (d *qemu) enrollSecureBootKeys(varsPath string) error {
+	_, err := exec.LookPath("virt-fw-vars")
+	if err != nil {
+		return fmt.Errorf("Required tool '%s' is missing", "virt-fw-vars")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "incus_secureboot_")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	outputPath := filepath.Join(tmpDir, "vars.fd")
+	args := []string{"--input", varsPath, "--output", outputPath}
+
+	// Write each certificate to its own file as expected by virt-fw-vars.
+	certIndex := 0
+	writeCerts := func(value string, flag string) error {
+		rest := []byte(value)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return nil
+			}
+
+			certPath := filepath.Join(tmpDir, fmt.Sprintf("cert%d.pem", certIndex))
+			certIndex++
+
+			err := os.WriteFile(certPath, pem.EncodeToMemory(block), 0o600)
+			if err != nil {
+				return err
+			}
+
+			args = append(args, flag, qemuSecureBootOwnerGUID, certPath)
+		}
+	}
+
+	for _, entry := range []struct {
+		key  string
+		flag string
+	}{
+		{key: "security.secureboot.pk", flag: "--set-pk"},
+		{key: "security.secureboot.kek", flag: "--add-kek"},
+		{key: "security.secureboot.db", flag: "--add-db"},
+	} {
+		err = writeCerts(d.expandedConfig[entry.key], entry.flag)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.expandedConfig["security.secureboot.dbx"] != "" {
+		dbx, err := base64.StdEncoding.DecodeString(d.expandedConfig["security.secureboot.dbx"])
+		if err != nil {
+			return fmt.Errorf("Failed decoding %q: %w", "security.secureboot.dbx", err)
+		}
+
+		dbxPath := filepath.Join(tmpDir, "dbx.bin")
+		err = os.WriteFile(dbxPath, dbx, 0o600)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, "--set-dbx", dbxPath)
+	}
+
+	// Make sure secure boot is enforced with the new keys.
+	args = append(args, "--secure-boot")
+
+	_, err = subprocess.RunCommand("virt-fw-vars", args...)
+	if err != nil {
+		return fmt.Errorf("Failed enrolling secure boot keys: %w", err)
+	}
+
+	return internalUtil.FileCopy(outputPath, varsPath)
+}
@@ -364,6 +364,14 @@ func qemuCoreInfo() []cfg.Section {
 	}}
 }
 
+func qemuPVPanic() []cfg.Section {
+	return []cfg.Section{{
+		Name:    `device "qemu_pvpanic"`,
+		Comment: "Guest panic notification",
+		Entries: map[string]string{"driver": "pvpanic"},
+	}}
+}
+
 func qemuIOMMU(opts *qemuDevOpts, isWindows bool) []cfg.Section {
 	if isWindows {
 		return []cfg.Section{{
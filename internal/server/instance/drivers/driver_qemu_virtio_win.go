@@ -0,0 +1,63 @@
+package drivers
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/device"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// qemuWindowsDriversPath is where distributions ship the VirtIO drivers ISO for Windows (virtio-win package).
+const qemuWindowsDriversPath = "/usr/share/virtio-win/virtio-win.iso"
+
+// qemuWindowsDriversDevName is the device name used for the Windows drivers CD-ROM.
+const qemuWindowsDriversDevName = "windows-drivers"
+
+// windowsDriversPath returns the path to the Windows drivers ISO.
+func (d *qemu) windowsDriversPath() (string, error) {
+	isoPath := os.Getenv("INCUS_WINDOWS_DRIVERS_PATH")
+	if isoPath == "" {
+		isoPath = qemuWindowsDriversPath
+	}
+
+	if !util.PathExists(isoPath) {
+		return "", fmt.Errorf("The Windows drivers ISO %q couldn't be found, install it or point INCUS_WINDOWS_DRIVERS_PATH to it", isoPath)
+	}
+
+	return isoPath, nil
+}
+
+// windowsDriversRunConfig returns the run config attaching the Windows drivers ISO as a CD-ROM.
+func (d *qemu) windowsDriversRunConfig() (*deviceConfig.RunConfig, error) {
+	isoPath, err := d.windowsDriversPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// Open file handle to isoPath source.
+	f, err := os.OpenFile(isoPath, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed opening source path %q: %w", isoPath, err)
+	}
+
+	runConf := deviceConfig.RunConfig{
+		PostHooks: []func() error{f.Close},
+		Revert:    func() { _ = f.Close() }, // Close file on VM start failure.
+	}
+
+	// Windows setup doesn't include the VirtIO drivers, so use a USB CD-ROM it can read out of the box.
+	runConf.Mounts = []deviceConfig.MountEntryItem{
+		{
+			DevPath: fmt.Sprintf("%s:%d:%s", device.DiskFileDescriptorMountPrefix, f.Fd(), isoPath),
+			DevName: qemuWindowsDriversDevName,
+			FSType:  "iso9660",
+			Opts:    []string{"ro", "bus=usb"},
+		},
+	}
+
+	return &runConf, nil
+}
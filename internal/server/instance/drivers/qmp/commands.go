@@ -134,6 +134,32 @@ func (m *Monitor) QueryHotpluggableCPUs() ([]HotpluggableCPU, error) {
 	return resp.Return, nil
 }
 
+// QueryDeviceProperties returns the list of properties of the specified device type.
+func (m *Monitor) QueryDeviceProperties(typeName string) ([]string, error) {
+	// Prepare the response.
+	var resp struct {
+		Return []struct {
+			Name string `json:"name"`
+		} `json:"return"`
+	}
+
+	args := map[string]any{
+		"typename": typeName,
+	}
+
+	err := m.Run("device-list-properties", args, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query properties of device %q: %w", typeName, err)
+	}
+
+	properties := make([]string, 0, len(resp.Return))
+	for _, property := range resp.Return {
+		properties = append(properties, property.Name)
+	}
+
+	return properties, nil
+}
+
 // QueryCPUModel returns a CPUModel for the specified model name.
 func (m *Monitor) QueryCPUModel(model string) (*CPUModel, error) {
 	// Prepare the response.
@@ -396,32 +422,61 @@ func (m *Monitor) Migrate(name string) error {
 	return nil
 }
 
+// MigrationRAMInfo represents the progress of the memory transfer of a migration job.
+type MigrationRAMInfo struct {
+	Transferred    int64   `json:"transferred"`
+	Remaining      int64   `json:"remaining"`
+	Total          int64   `json:"total"`
+	DirtyPagesRate int64   `json:"dirty-pages-rate"`
+	PageSize       int64   `json:"page-size"`
+	DirtySyncCount int64   `json:"dirty-sync-count"`
+	Mbps           float64 `json:"mbps"`
+}
+
+// MigrationInfo represents the status of a migration job.
+type MigrationInfo struct {
+	Status string           `json:"status"`
+	RAM    MigrationRAMInfo `json:"ram"`
+}
+
+// QueryMigrate returns the status of the current migration job.
+func (m *Monitor) QueryMigrate() (*MigrationInfo, error) {
+	// Prepare the response.
+	var resp struct {
+		Return MigrationInfo `json:"return"`
+	}
+
+	err := m.Run("query-migrate", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp.Return, nil
+}
+
 // MigrateWait waits until migration job reaches the specified status.
 // Returns nil if the migraton job reaches the specified status or an error if the migration job is in the failed
-// status.
-func (m *Monitor) MigrateWait(state string) error {
+// status. The progress function (if any) is called with the job status while the job is active.
+func (m *Monitor) MigrateWait(state string, progress func(info *MigrationInfo)) error {
 	// Wait until it completes or fails.
 	for {
-		// Prepare the response.
-		var resp struct {
-			Return struct {
-				Status string `json:"status"`
-			} `json:"return"`
-		}
-
-		err := m.Run("query-migrate", nil, &resp)
+		info, err := m.QueryMigrate()
 		if err != nil {
 			return err
 		}
 
-		if resp.Return.Status == "failed" {
+		if info.Status == "failed" {
 			return errors.New("Migrate call failed")
 		}
 
-		if resp.Return.Status == state {
+		if info.Status == state {
 			return nil
 		}
 
+		if progress != nil && info.Status == "active" {
+			progress(info)
+		}
+
 		time.Sleep(1 * time.Second)
 	}
 }
@@ -892,6 +947,8 @@ type BlockStats struct {
 	WritesCompleted int `json:"wr_operations"`
 	BytesRead       int `json:"rd_bytes"`
 	ReadsCompleted  int `json:"rd_operations"`
+	WriteTime       int `json:"wr_total_time_ns"`
+	ReadTime        int `json:"rd_total_time_ns"`
 }
 
 // GetBlockStats return block device stats.
@@ -1268,6 +1325,36 @@ func (m *Monitor) CheckPCIDevice(deviceID string) (bool, error) {
 	return false, nil
 }
 
+// QueryPeripherals returns the IDs of the devices which were added with an ID.
+func (m *Monitor) QueryPeripherals() ([]string, error) {
+	var resp struct {
+		Return []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"return"`
+	}
+
+	args := map[string]any{
+		"path": "/machine/peripheral",
+	}
+
+	err := m.Run("qom-list", args, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing devices: %w", err)
+	}
+
+	ids := make([]string, 0, len(resp.Return))
+	for _, entry := range resp.Return {
+		if !strings.HasPrefix(entry.Type, "child<") {
+			continue
+		}
+
+		ids = append(ids, entry.Name)
+	}
+
+	return ids, nil
+}
+
 // RingbufRead returns the complete contents of the specified ring buffer.
 func (m *Monitor) RingbufRead(device string) (string, error) {
 	// Begin by ensuring the device specified is actually a ring buffer.
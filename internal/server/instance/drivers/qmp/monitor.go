@@ -40,6 +40,9 @@ var EventDiskEjected = "DEVICE_TRAY_MOVED"
 // EventRTCChange is used to get RTC adjustment.
 var EventRTCChange = "RTC_CHANGE"
 
+// EventGuestPanicked is the event sent when the guest reports a kernel panic.
+var EventGuestPanicked = "GUEST_PANICKED"
+
 // ExcludedCommands is used to filter verbose commands from the QMP logs.
 var ExcludedCommands = []string{"ringbuf-read"}
 
@@ -285,3 +285,19 @@ func getNodeResources(s *state.State, name string, address string) (*api.Resourc
 
 	return res, nil
 }
+
+// consoleBufferDiff returns what was written to a console ring buffer between two reads of it.
+func consoleBufferDiff(previous string, current string) string {
+	if strings.HasPrefix(current, previous) {
+		return current[len(previous):]
+	}
+
+	// The ring buffer wrapped around, look for the end of the previous content.
+	tail := previous[max(len(previous)-256, 0):]
+	idx := strings.LastIndex(current, tail)
+	if idx < 0 {
+		return current
+	}
+
+	return current[idx+len(tail):]
+}
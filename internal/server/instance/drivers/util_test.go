@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -64,3 +65,17 @@ func TestExtractTrailingNumber(t *testing.T) {
 		t.Errorf("unexpected error message: got %q, want %q", err.Error(), expectedErr)
 	}
 }
+
+// Test consoleBufferDiff.
+func TestConsoleBufferDiff(t *testing.T) {
+	assert.Equal(t, "boot\n", consoleBufferDiff("", "boot\n"))
+	assert.Equal(t, "login: ", consoleBufferDiff("boot\n", "boot\nlogin: "))
+	assert.Equal(t, "", consoleBufferDiff("boot\n", "boot\n"))
+
+	// Wrapped buffer.
+	previous := strings.Repeat("boot\n", 100)
+	assert.Equal(t, "ready\n", consoleBufferDiff(previous, previous[100:]+"ready\n"))
+
+	// Cleared buffer.
+	assert.Equal(t, "new\n", consoleBufferDiff("old\n", "new\n"))
+}
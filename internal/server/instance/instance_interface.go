@@ -38,6 +38,7 @@ const HookStop = "onstop"
 const (
 	ConsoleTypeConsole = "console"
 	ConsoleTypeVGA     = "vga"
+	ConsoleTypeVNC     = "vnc"
 )
 
 // TemplateTrigger trigger name.
@@ -83,7 +84,9 @@ type Instance interface {
 	Stop(stateful bool) error
 	Restart(timeout time.Duration) error
 	Rebuild(img *api.Image, op *operations.Operation) error
+	RebuildFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error
 	Unfreeze() error
+	SyncTime() error
 
 	ReloadDevice(devName string) error
 	RegisterDevices()
@@ -103,7 +106,7 @@ type Instance interface {
 	Update(newConfig db.InstanceArgs, userRequested bool) error
 
 	Delete(force bool) error
-	Export(meta io.Writer, roofs io.Writer, properties map[string]string, expiration time.Time, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error)
+	Export(meta io.Writer, roofs io.Writer, properties map[string]string, expiration time.Time, stateful bool, tracker *ioprogress.ProgressTracker) (*api.ImageMetadata, error)
 
 	// Live configuration.
 	CGroup() (*cgroup.CGroup, error)
@@ -115,6 +118,7 @@ type Instance interface {
 
 	// Console - Allocate and run a console tty or a spice Unix socket.
 	Console(protocol string) (*os.File, chan error, error)
+	ConsoleBufferRead() (string, error)
 	Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (Cmd, error)
 
 	// Status
@@ -122,6 +126,7 @@ type Instance interface {
 	RenderWithUsage() (any, any, error)
 	RenderFull(hostInterfaces []net.Interface) (*api.InstanceFull, any, error)
 	RenderState(hostInterfaces []net.Interface) (*api.InstanceState, error)
+	Processes() ([]api.InstanceProcess, error)
 	IsRunning() bool
 	IsFrozen() bool
 	IsEphemeral() bool
@@ -198,6 +203,8 @@ type VM interface {
 	ConsoleLog() (string, error)
 	ConsoleScreenshot(screenshotFile *os.File) error
 	DumpGuestMemory(w *os.File, format string) error
+	MemoryHotplugSupported() bool
+	FreezeFilesystems() (func() error, error)
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
@@ -240,6 +247,12 @@ type MigrateSendArgs struct {
 	MigrateArgs
 
 	AllowInconsistent bool
+
+	// Pre-copy tuning for live container migration.
+	// When left unset, the instance configuration (or built-in default) is used.
+	PreDumpIterations int
+	PreDumpGoal       int
+	PreDumpTimeout    time.Duration
 }
 
 // MigrateReceiveArgs represent arguments for instance migration receive.
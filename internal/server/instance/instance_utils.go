@@ -1057,7 +1057,7 @@ func NextSnapshotName(s *state.State, inst Instance, defaultPattern string) (str
 		return "", err
 	}
 
-	count := strings.Count(pattern, "%d")
+	count := internalUtil.NamePatternCounters(pattern)
 	if count > 1 {
 		return "", fmt.Errorf("Snapshot pattern may contain '%%d' only once")
 	} else if count == 1 {
@@ -1069,7 +1069,7 @@ func NextSnapshotName(s *state.State, inst Instance, defaultPattern string) (str
 			return nil
 		})
 
-		return strings.Replace(pattern, "%d", strconv.Itoa(i), 1), nil
+		return internalUtil.NamePatternFormat(pattern, i), nil
 	}
 
 	snapshotExists := false
@@ -1098,7 +1098,7 @@ func NextSnapshotName(s *state.State, inst Instance, defaultPattern string) (str
 
 			return nil
 		})
-		return strings.Replace(pattern, "%d", strconv.Itoa(i), 1), nil
+		return internalUtil.NamePatternFormat(pattern, i), nil
 	}
 
 	return pattern, nil
@@ -1348,3 +1348,43 @@ func ResourceUsage(instConfig map[string]string, instDevices map[string]map[stri
 
 	return cpuUsage, memoryUsage, diskUsage, nil
 }
+
+// ReservedCPUs returns the CPU threads reserved by the instances using CPU isolation, along with the instance holding them.
+// The reservation covers whole CPU cores, so the SMT siblings of the threads used by an instance are reserved too.
+func ReservedCPUs(insts []Instance, cpu *api.ResourcesCPU) map[int64]Instance {
+	// Build a map of CPU thread to the threads of its core.
+	siblings := map[int64][]int64{}
+	for _, cpuSocket := range cpu.Sockets {
+		for _, cpuCore := range cpuSocket.Cores {
+			threads := make([]int64, 0, len(cpuCore.Threads))
+			for _, cpuThread := range cpuCore.Threads {
+				threads = append(threads, cpuThread.ID)
+			}
+
+			for _, id := range threads {
+				siblings[id] = threads
+			}
+		}
+	}
+
+	reserved := map[int64]Instance{}
+	for _, inst := range insts {
+		conf := inst.ExpandedConfig()
+		if conf["limits.cpu.pin_strategy"] != "isolated" || conf["volatile.cpu.isolated"] == "" {
+			continue
+		}
+
+		cpus, err := resources.ParseCpuset(conf["volatile.cpu.isolated"])
+		if err != nil {
+			continue
+		}
+
+		for _, id := range cpus {
+			for _, sibling := range siblings[id] {
+				reserved[sibling] = inst
+			}
+		}
+	}
+
+	return reserved
+}
@@ -0,0 +1,64 @@
+package ip
+
+import (
+	"bytes"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// FDB represents arguments for bridge forwarding database manipulation.
+type FDB struct {
+	DevName   string
+	MAC       net.HardwareAddr
+	Permanent bool
+}
+
+// Show lists the forwarding database entries of the bridge DevName, optionally filtered by MAC address.
+// The DevName of the returned entries is the bridge port the MAC address was seen on.
+func (f *FDB) Show() ([]FDB, error) {
+	out, err := subprocess.RunCommand("bridge", "fdb", "show", "br", f.DevName)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFDB(out, f.MAC), nil
+}
+
+// parseFDB parses the output of "bridge fdb show", only keeping the entries of bridge ports.
+func parseFDB(out string, filterMAC net.HardwareAddr) []FDB {
+	entries := []FDB{}
+
+	for _, line := range util.SplitNTrimSpace(out, "\n", -1, true) {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[1] != "dev" {
+			continue
+		}
+
+		// Only keep the entries of the bridge itself, not those of the ports' own hardware.
+		if !slices.Contains(fields, "master") {
+			continue
+		}
+
+		mac, err := net.ParseMAC(fields[0])
+		if err != nil {
+			continue
+		}
+
+		// Check entry matches desired MAC address if specified.
+		if filterMAC != nil && !bytes.Equal(filterMAC, mac) {
+			continue
+		}
+
+		entries = append(entries, FDB{
+			DevName:   fields[2],
+			MAC:       mac,
+			Permanent: slices.Contains(fields, "permanent"),
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,28 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// AuthTokenAction represents a lifecycle event action for API tokens.
+type AuthTokenAction string
+
+// All supported lifecycle events for API tokens.
+const (
+	AuthTokenCreated = AuthTokenAction(api.EventLifecycleAuthTokenCreated)
+	AuthTokenDeleted = AuthTokenAction(api.EventLifecycleAuthTokenDeleted)
+	AuthTokenUpdated = AuthTokenAction(api.EventLifecycleAuthTokenUpdated)
+)
+
+// Event creates the lifecycle event for an action on an API token.
+func (a AuthTokenAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "auth", "tokens", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// BackupTargetAction represents a lifecycle event action for server-side backup targets.
+type BackupTargetAction string
+
+// All supported lifecycle events for server-side backup targets.
+const (
+	BackupTargetCreated = BackupTargetAction(api.EventLifecycleBackupTargetCreated)
+	BackupTargetDeleted = BackupTargetAction(api.EventLifecycleBackupTargetDeleted)
+	BackupTargetRenamed = BackupTargetAction(api.EventLifecycleBackupTargetRenamed)
+	BackupTargetUpdated = BackupTargetAction(api.EventLifecycleBackupTargetUpdated)
+)
+
+// Event creates the lifecycle event for an action on a server-side backup target.
+func (a BackupTargetAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "backup-targets", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// BlueprintAction represents a lifecycle event action for blueprints.
+type BlueprintAction string
+
+// All supported lifecycle events for blueprints.
+const (
+	BlueprintCreated = BlueprintAction(api.EventLifecycleBlueprintCreated)
+	BlueprintDeleted = BlueprintAction(api.EventLifecycleBlueprintDeleted)
+	BlueprintUpdated = BlueprintAction(api.EventLifecycleBlueprintUpdated)
+	BlueprintRenamed = BlueprintAction(api.EventLifecycleBlueprintRenamed)
+)
+
+// Event creates the lifecycle event for an action on a blueprint.
+func (a BlueprintAction) Event(name string, projectName string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "blueprints", name).Project(projectName)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
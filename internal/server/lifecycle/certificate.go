@@ -12,6 +12,7 @@ type CertificateAction string
 const (
 	CertificateCreated = CertificateAction(api.EventLifecycleCertificateCreated)
 	CertificateDeleted = CertificateAction(api.EventLifecycleCertificateDeleted)
+	CertificateRotated = CertificateAction(api.EventLifecycleCertificateRotated)
 	CertificateUpdated = CertificateAction(api.EventLifecycleCertificateUpdated)
 )
 
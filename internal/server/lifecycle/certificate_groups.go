@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// CertificateGroupAction represents a lifecycle event action for certificate groups.
+type CertificateGroupAction string
+
+// All supported lifecycle events for certificate groups.
+const (
+	CertificateGroupCreated = CertificateGroupAction(api.EventLifecycleCertificateGroupCreated)
+	CertificateGroupDeleted = CertificateGroupAction(api.EventLifecycleCertificateGroupDeleted)
+	CertificateGroupUpdated = CertificateGroupAction(api.EventLifecycleCertificateGroupUpdated)
+	CertificateGroupRenamed = CertificateGroupAction(api.EventLifecycleCertificateGroupRenamed)
+)
+
+// Event creates the lifecycle event for an action on a certificate group.
+func (a CertificateGroupAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "certificates", "groups", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
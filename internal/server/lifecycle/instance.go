@@ -24,10 +24,13 @@ const (
 	InstanceCreated          = InstanceAction(api.EventLifecycleInstanceCreated)
 	InstanceDeleted          = InstanceAction(api.EventLifecycleInstanceDeleted)
 	InstanceExec             = InstanceAction(api.EventLifecycleInstanceExec)
+	InstanceExecFinished     = InstanceAction(api.EventLifecycleInstanceExecFinished)
 	InstanceFileDeleted      = InstanceAction(api.EventLifecycleInstanceFileDeleted)
 	InstanceFilePushed       = InstanceAction(api.EventLifecycleInstanceFilePushed)
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
 	InstanceMigrated         = InstanceAction(api.EventLifecycleInstanceMigrated)
+	InstanceOOMKilled        = InstanceAction(api.EventLifecycleInstanceOOMKilled)
+	InstancePanicked         = InstanceAction(api.EventLifecycleInstancePanicked)
 	InstancePaused           = InstanceAction(api.EventLifecycleInstancePaused)
 	InstanceReady            = InstanceAction(api.EventLifecycleInstanceReady)
 	InstanceRenamed          = InstanceAction(api.EventLifecycleInstanceRenamed)
@@ -8,6 +8,7 @@ type Metrics struct {
 	Filesystem     []FilesystemMetrics `json:"filesystem" yaml:"filesystem"`
 	Memory         MemoryMetrics       `json:"memory" yaml:"memory"`
 	Network        []NetworkMetrics    `json:"network" yaml:"network"`
+	Pressure       []PressureMetrics   `json:"pressure" yaml:"pressure"`
 	ProcessesTotal uint64              `json:"procs_total" yaml:"procs_total"`
 }
 
@@ -79,3 +80,10 @@ type NetworkMetrics struct {
 	TransmitErrors  uint64 `json:"network_transmit_errs" yaml:"network_transmit_errs"`
 	TransmitPackets uint64 `json:"network_transmit_packets" yaml:"network_transmit_packets"`
 }
+
+// PressureMetrics represents pressure stall information metrics for an instance.
+type PressureMetrics struct {
+	Resource    string  `json:"resource" yaml:"resource"`
+	SomeSeconds float64 `json:"pressure_some_seconds" yaml:"pressure_some_seconds"`
+	FullSeconds float64 `json:"pressure_full_seconds" yaml:"pressure_full_seconds"`
+}
@@ -22,8 +22,9 @@ func NewMetricSet(labels map[string]string) *MetricSet {
 	return &out
 }
 
-// FilterSamples filters the existing MetricSet using the given permission checker. Samples not containing "project" and
-// "name" labels are skipped.
+// FilterSamples filters the existing MetricSet using the given permission checker. Samples containing "project", "pool"
+// and "volume" labels are checked against the custom storage volume, samples containing "project" and "name" labels
+// against the instance and all other samples against the server.
 func (m *MetricSet) FilterSamples(permissionChecker func(object auth.Object) bool) {
 	for metricType, samples := range m.set {
 		allowedSamples := make([]Sample, 0, len(samples))
@@ -31,6 +32,14 @@ func (m *MetricSet) FilterSamples(permissionChecker func(object auth.Object) boo
 			projectName := s.Labels["project"]
 			instanceName := s.Labels["name"]
 
+			if projectName != "" && s.Labels["pool"] != "" && s.Labels["volume"] != "" {
+				if permissionChecker(auth.ObjectStorageVolume(projectName, s.Labels["pool"], "custom", s.Labels["volume"], s.Labels["location"])) {
+					allowedSamples = append(allowedSamples, s)
+				}
+
+				continue
+			}
+
 			if projectName == "" || instanceName == "" {
 				if permissionChecker(auth.ObjectServer()) {
 					allowedSamples = append(allowedSamples, s)
@@ -295,6 +304,14 @@ func MetricSetFromAPI(metrics *Metrics, labels map[string]string) (*MetricSet, e
 		set.AddSamples(NetworkTransmitPacketsTotal, Sample{Value: float64(stats.TransmitPackets), Labels: labels})
 	}
 
+	// Pressure stats
+	for _, stats := range metrics.Pressure {
+		labels := map[string]string{"resource": stats.Resource}
+
+		set.AddSamples(PressureSomeSecondsTotal, Sample{Value: stats.SomeSeconds, Labels: labels})
+		set.AddSamples(PressureFullSecondsTotal, Sample{Value: stats.FullSeconds, Labels: labels})
+	}
+
 	// Procs stats
 	set.AddSamples(ProcsTotal, Sample{Value: float64(metrics.ProcessesTotal)})
 
@@ -38,6 +38,27 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 	// Should no longer contain the sample.
 	require.Equal(t, []Sample{}, m.set[CPUSecondsTotal])
 
+	volumeLabels := map[string]string{"project": "default", "pool": "local", "volume": "data"}
+	m = NewMetricSet(nil)
+	m.AddSamples(StorageVolumeUsedBytes, Sample{Value: 10, Labels: volumeLabels})
+	permissionChecker = func(object auth.Object) bool {
+		return object == auth.ObjectStorageVolume("default", "local", "custom", "data", "")
+	}
+
+	m.FilterSamples(permissionChecker)
+
+	// Should still contain the sample.
+	require.Equal(t, []Sample{{Value: 10, Labels: volumeLabels}}, m.set[StorageVolumeUsedBytes])
+
+	permissionChecker = func(object auth.Object) bool {
+		return object == auth.ObjectInstance("default", "data")
+	}
+
+	m.FilterSamples(permissionChecker)
+
+	// Should no longer contain the sample.
+	require.Equal(t, []Sample{}, m.set[StorageVolumeUsedBytes])
+
 	m = NewMetricSet(map[string]string{"project": "default"})
 	m.AddSamples(CPUSecondsTotal, Sample{Value: 10})
 
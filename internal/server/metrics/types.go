@@ -91,6 +91,10 @@ const (
 	NetworkTransmitErrsTotal
 	// NetworkTransmitPacketsTotal represents the amount of transmitted packets on a given interface.
 	NetworkTransmitPacketsTotal
+	// PressureSomeSecondsTotal represents the total time during which some tasks were stalled on a resource.
+	PressureSomeSecondsTotal
+	// PressureFullSecondsTotal represents the total time during which all non-idle tasks were stalled on a resource.
+	PressureFullSecondsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
 	// OperationsTotal represents the number of running operations.
@@ -145,140 +149,168 @@ const (
 	GoOtherSysBytes
 	// GoNextGCBytes represents the number of heap bytes when next garbage collection will take place.
 	GoNextGCBytes
+	// StorageVolumeUsedBytes represents the used space in bytes of a custom storage volume.
+	StorageVolumeUsedBytes
+	// StorageVolumeQuotaBytes represents the size limit in bytes of a custom storage volume.
+	StorageVolumeQuotaBytes
+	// StorageVolumeReadBytesTotal represents the read bytes for a custom storage volume.
+	StorageVolumeReadBytesTotal
+	// StorageVolumeReadsCompletedTotal represents the completed reads for a custom storage volume.
+	StorageVolumeReadsCompletedTotal
+	// StorageVolumeWrittenBytesTotal represents the written bytes for a custom storage volume.
+	StorageVolumeWrittenBytesTotal
+	// StorageVolumeWritesCompletedTotal represents the completed writes for a custom storage volume.
+	StorageVolumeWritesCompletedTotal
 )
 
 // MetricNames associates a metric type to its name.
 var MetricNames = map[MetricType]string{
-	CPUSecondsTotal:             "incus_cpu_seconds_total",
-	CPUs:                        "incus_cpu_effective_total",
-	DiskReadBytesTotal:          "incus_disk_read_bytes_total",
-	DiskReadsCompletedTotal:     "incus_disk_reads_completed_total",
-	DiskWrittenBytesTotal:       "incus_disk_written_bytes_total",
-	DiskWritesCompletedTotal:    "incus_disk_writes_completed_total",
-	FilesystemAvailBytes:        "incus_filesystem_avail_bytes",
-	FilesystemFreeBytes:         "incus_filesystem_free_bytes",
-	FilesystemSizeBytes:         "incus_filesystem_size_bytes",
-	GoAllocBytes:                "incus_go_alloc_bytes",
-	GoAllocBytesTotal:           "incus_go_alloc_bytes_total",
-	GoBuckHashSysBytes:          "incus_go_buck_hash_sys_bytes",
-	GoFreesTotal:                "incus_go_frees_total",
-	GoGCSysBytes:                "incus_go_gc_sys_bytes",
-	GoGoroutines:                "incus_go_goroutines",
-	GoHeapAllocBytes:            "incus_go_heap_alloc_bytes",
-	GoHeapIdleBytes:             "incus_go_heap_idle_bytes",
-	GoHeapInuseBytes:            "incus_go_heap_inuse_bytes",
-	GoHeapObjects:               "incus_go_heap_objects",
-	GoHeapReleasedBytes:         "incus_go_heap_released_bytes",
-	GoHeapSysBytes:              "incus_go_heap_sys_bytes",
-	GoLookupsTotal:              "incus_go_lookups_total",
-	GoMallocsTotal:              "incus_go_mallocs_total",
-	GoMCacheInuseBytes:          "incus_go_mcache_inuse_bytes",
-	GoMCacheSysBytes:            "incus_go_mcache_sys_bytes",
-	GoMSpanInuseBytes:           "incus_go_mspan_inuse_bytes",
-	GoMSpanSysBytes:             "incus_go_mspan_sys_bytes",
-	GoNextGCBytes:               "incus_go_next_gc_bytes",
-	GoOtherSysBytes:             "incus_go_other_sys_bytes",
-	GoStackInuseBytes:           "incus_go_stack_inuse_bytes",
-	GoStackSysBytes:             "incus_go_stack_sys_bytes",
-	GoSysBytes:                  "incus_go_sys_bytes",
-	MemoryActiveAnonBytes:       "incus_memory_Active_anon_bytes",
-	MemoryActiveFileBytes:       "incus_memory_Active_file_bytes",
-	MemoryActiveBytes:           "incus_memory_Active_bytes",
-	MemoryCachedBytes:           "incus_memory_Cached_bytes",
-	MemoryDirtyBytes:            "incus_memory_Dirty_bytes",
-	MemoryHugePagesFreeBytes:    "incus_memory_HugepagesFree_bytes",
-	MemoryHugePagesTotalBytes:   "incus_memory_HugepagesTotal_bytes",
-	MemoryInactiveAnonBytes:     "incus_memory_Inactive_anon_bytes",
-	MemoryInactiveFileBytes:     "incus_memory_Inactive_file_bytes",
-	MemoryInactiveBytes:         "incus_memory_Inactive_bytes",
-	MemoryMappedBytes:           "incus_memory_Mapped_bytes",
-	MemoryMemAvailableBytes:     "incus_memory_MemAvailable_bytes",
-	MemoryMemFreeBytes:          "incus_memory_MemFree_bytes",
-	MemoryMemTotalBytes:         "incus_memory_MemTotal_bytes",
-	MemoryRSSBytes:              "incus_memory_RSS_bytes",
-	MemoryShmemBytes:            "incus_memory_Shmem_bytes",
-	MemorySwapBytes:             "incus_memory_Swap_bytes",
-	MemoryUnevictableBytes:      "incus_memory_Unevictable_bytes",
-	MemoryWritebackBytes:        "incus_memory_Writeback_bytes",
-	MemoryOOMKillsTotal:         "incus_memory_OOM_kills_total",
-	NetworkReceiveBytesTotal:    "incus_network_receive_bytes_total",
-	NetworkReceiveDropTotal:     "incus_network_receive_drop_total",
-	NetworkReceiveErrsTotal:     "incus_network_receive_errs_total",
-	NetworkReceivePacketsTotal:  "incus_network_receive_packets_total",
-	NetworkTransmitBytesTotal:   "incus_network_transmit_bytes_total",
-	NetworkTransmitDropTotal:    "incus_network_transmit_drop_total",
-	NetworkTransmitErrsTotal:    "incus_network_transmit_errs_total",
-	NetworkTransmitPacketsTotal: "incus_network_transmit_packets_total",
-	OperationsTotal:             "incus_operations_total",
-	ProcsTotal:                  "incus_procs_total",
-	UptimeSeconds:               "incus_uptime_seconds",
-	WarningsTotal:               "incus_warnings_total",
+	CPUSecondsTotal:                   "incus_cpu_seconds_total",
+	CPUs:                              "incus_cpu_effective_total",
+	DiskReadBytesTotal:                "incus_disk_read_bytes_total",
+	DiskReadsCompletedTotal:           "incus_disk_reads_completed_total",
+	DiskWrittenBytesTotal:             "incus_disk_written_bytes_total",
+	DiskWritesCompletedTotal:          "incus_disk_writes_completed_total",
+	FilesystemAvailBytes:              "incus_filesystem_avail_bytes",
+	FilesystemFreeBytes:               "incus_filesystem_free_bytes",
+	FilesystemSizeBytes:               "incus_filesystem_size_bytes",
+	GoAllocBytes:                      "incus_go_alloc_bytes",
+	GoAllocBytesTotal:                 "incus_go_alloc_bytes_total",
+	GoBuckHashSysBytes:                "incus_go_buck_hash_sys_bytes",
+	GoFreesTotal:                      "incus_go_frees_total",
+	GoGCSysBytes:                      "incus_go_gc_sys_bytes",
+	GoGoroutines:                      "incus_go_goroutines",
+	GoHeapAllocBytes:                  "incus_go_heap_alloc_bytes",
+	GoHeapIdleBytes:                   "incus_go_heap_idle_bytes",
+	GoHeapInuseBytes:                  "incus_go_heap_inuse_bytes",
+	GoHeapObjects:                     "incus_go_heap_objects",
+	GoHeapReleasedBytes:               "incus_go_heap_released_bytes",
+	GoHeapSysBytes:                    "incus_go_heap_sys_bytes",
+	GoLookupsTotal:                    "incus_go_lookups_total",
+	GoMallocsTotal:                    "incus_go_mallocs_total",
+	GoMCacheInuseBytes:                "incus_go_mcache_inuse_bytes",
+	GoMCacheSysBytes:                  "incus_go_mcache_sys_bytes",
+	GoMSpanInuseBytes:                 "incus_go_mspan_inuse_bytes",
+	GoMSpanSysBytes:                   "incus_go_mspan_sys_bytes",
+	GoNextGCBytes:                     "incus_go_next_gc_bytes",
+	GoOtherSysBytes:                   "incus_go_other_sys_bytes",
+	GoStackInuseBytes:                 "incus_go_stack_inuse_bytes",
+	GoStackSysBytes:                   "incus_go_stack_sys_bytes",
+	GoSysBytes:                        "incus_go_sys_bytes",
+	MemoryActiveAnonBytes:             "incus_memory_Active_anon_bytes",
+	MemoryActiveFileBytes:             "incus_memory_Active_file_bytes",
+	MemoryActiveBytes:                 "incus_memory_Active_bytes",
+	MemoryCachedBytes:                 "incus_memory_Cached_bytes",
+	MemoryDirtyBytes:                  "incus_memory_Dirty_bytes",
+	MemoryHugePagesFreeBytes:          "incus_memory_HugepagesFree_bytes",
+	MemoryHugePagesTotalBytes:         "incus_memory_HugepagesTotal_bytes",
+	MemoryInactiveAnonBytes:           "incus_memory_Inactive_anon_bytes",
+	MemoryInactiveFileBytes:           "incus_memory_Inactive_file_bytes",
+	MemoryInactiveBytes:               "incus_memory_Inactive_bytes",
+	MemoryMappedBytes:                 "incus_memory_Mapped_bytes",
+	MemoryMemAvailableBytes:           "incus_memory_MemAvailable_bytes",
+	MemoryMemFreeBytes:                "incus_memory_MemFree_bytes",
+	MemoryMemTotalBytes:               "incus_memory_MemTotal_bytes",
+	MemoryRSSBytes:                    "incus_memory_RSS_bytes",
+	MemoryShmemBytes:                  "incus_memory_Shmem_bytes",
+	MemorySwapBytes:                   "incus_memory_Swap_bytes",
+	MemoryUnevictableBytes:            "incus_memory_Unevictable_bytes",
+	MemoryWritebackBytes:              "incus_memory_Writeback_bytes",
+	MemoryOOMKillsTotal:               "incus_memory_OOM_kills_total",
+	NetworkReceiveBytesTotal:          "incus_network_receive_bytes_total",
+	NetworkReceiveDropTotal:           "incus_network_receive_drop_total",
+	NetworkReceiveErrsTotal:           "incus_network_receive_errs_total",
+	NetworkReceivePacketsTotal:        "incus_network_receive_packets_total",
+	NetworkTransmitBytesTotal:         "incus_network_transmit_bytes_total",
+	NetworkTransmitDropTotal:          "incus_network_transmit_drop_total",
+	NetworkTransmitErrsTotal:          "incus_network_transmit_errs_total",
+	NetworkTransmitPacketsTotal:       "incus_network_transmit_packets_total",
+	OperationsTotal:                   "incus_operations_total",
+	PressureFullSecondsTotal:          "incus_pressure_full_seconds_total",
+	PressureSomeSecondsTotal:          "incus_pressure_some_seconds_total",
+	ProcsTotal:                        "incus_procs_total",
+	StorageVolumeQuotaBytes:           "incus_storage_volume_quota_bytes",
+	StorageVolumeReadBytesTotal:       "incus_storage_volume_read_bytes_total",
+	StorageVolumeReadsCompletedTotal:  "incus_storage_volume_reads_completed_total",
+	StorageVolumeUsedBytes:            "incus_storage_volume_used_bytes",
+	StorageVolumeWritesCompletedTotal: "incus_storage_volume_writes_completed_total",
+	StorageVolumeWrittenBytesTotal:    "incus_storage_volume_written_bytes_total",
+	UptimeSeconds:                     "incus_uptime_seconds",
+	WarningsTotal:                     "incus_warnings_total",
 }
 
 // MetricHeaders represents the metric headers which contain help messages as specified by OpenMetrics.
 var MetricHeaders = map[MetricType]string{
-	CPUSecondsTotal:             "# HELP incus_cpu_seconds_total The total number of CPU time used in seconds.",
-	CPUs:                        "# HELP incus_cpu_effective_total The total number of effective CPUs.",
-	DiskReadBytesTotal:          "# HELP incus_disk_read_bytes_total The total number of bytes read.",
-	DiskReadsCompletedTotal:     "# HELP incus_disk_reads_completed_total The total number of completed reads.",
-	DiskWrittenBytesTotal:       "# HELP incus_disk_written_bytes_total The total number of bytes written.",
-	DiskWritesCompletedTotal:    "# HELP incus_disk_writes_completed_total The total number of completed writes.",
-	FilesystemAvailBytes:        "# HELP incus_filesystem_avail_bytes The number of available space in bytes.",
-	FilesystemFreeBytes:         "# HELP incus_filesystem_free_bytes The number of free space in bytes.",
-	FilesystemSizeBytes:         "# HELP incus_filesystem_size_bytes The size of the filesystem in bytes.",
-	GoAllocBytes:                "# HELP incus_go_alloc_bytes Number of bytes allocated and still in use.",
-	GoAllocBytesTotal:           "# HELP incus_go_alloc_bytes_total Total number of bytes allocated, even if freed.",
-	GoBuckHashSysBytes:          "# HELP incus_go_buck_hash_sys_bytes Number of bytes used by the profiling bucket hash table.",
-	GoFreesTotal:                "# HELP incus_go_frees_total Total number of frees.",
-	GoGCSysBytes:                "# HELP incus_go_gc_sys_bytes Number of bytes used for garbage collection system metadata.",
-	GoGoroutines:                "# HELP incus_go_goroutines Number of goroutines that currently exist.",
-	GoHeapAllocBytes:            "# HELP incus_go_heap_alloc_bytes Number of heap bytes allocated and still in use.",
-	GoHeapIdleBytes:             "# HELP incus_go_heap_idle_bytes Number of heap bytes waiting to be used.",
-	GoHeapInuseBytes:            "# HELP incus_go_heap_inuse_bytes Number of heap bytes that are in use.",
-	GoHeapObjects:               "# HELP incus_go_heap_objects Number of allocated objects.",
-	GoHeapReleasedBytes:         "# HELP incus_go_heap_released_bytes Number of heap bytes released to OS.",
-	GoHeapSysBytes:              "# HELP incus_go_heap_sys_bytes Number of heap bytes obtained from system.",
-	GoLookupsTotal:              "# HELP incus_go_lookups_total Total number of pointer lookups.",
-	GoMallocsTotal:              "# HELP incus_go_mallocs_total Total number of mallocs.",
-	GoMCacheInuseBytes:          "# HELP incus_go_mcache_inuse_bytes Number of bytes in use by mcache structures.",
-	GoMCacheSysBytes:            "# HELP incus_go_mcache_sys_bytes Number of bytes used for mcache structures obtained from system.",
-	GoMSpanInuseBytes:           "# HELP incus_go_mspan_inuse_bytes Number of bytes in use by mspan structures.",
-	GoMSpanSysBytes:             "# HELP incus_go_mspan_sys_bytes Number of bytes used for mspan structures obtained from system.",
-	GoNextGCBytes:               "# HELP incus_go_next_gc_bytes Number of heap bytes when next garbage collection will take place.",
-	GoOtherSysBytes:             "# HELP incus_go_other_sys_bytes Number of bytes used for other system allocations.",
-	GoStackInuseBytes:           "# HELP incus_go_stack_inuse_bytes Number of bytes in use by the stack allocator.",
-	GoStackSysBytes:             "# HELP incus_go_stack_sys_bytes Number of bytes obtained from system for stack allocator.",
-	GoSysBytes:                  "# HELP incus_go_sys_bytes Number of bytes obtained from system.",
-	MemoryActiveAnonBytes:       "# HELP incus_memory_Active_anon_bytes The amount of anonymous memory on active LRU list.",
-	MemoryActiveFileBytes:       "# HELP incus_memory_Active_file_bytes The amount of file-backed memory on active LRU list.",
-	MemoryActiveBytes:           "# HELP incus_memory_Active_bytes The amount of memory on active LRU list.",
-	MemoryCachedBytes:           "# HELP incus_memory_Cached_bytes The amount of cached memory.",
-	MemoryDirtyBytes:            "# HELP incus_memory_Dirty_bytes The amount of memory waiting to get written back to the disk.",
-	MemoryHugePagesFreeBytes:    "# HELP incus_memory_HugepagesFree_bytes The amount of free memory for hugetlb.",
-	MemoryHugePagesTotalBytes:   "# HELP incus_memory_HugepagesTotal_bytes The amount of used memory for hugetlb.",
-	MemoryInactiveAnonBytes:     "# HELP incus_memory_Inactive_anon_bytes The amount of anonymous memory on inactive LRU list.",
-	MemoryInactiveFileBytes:     "# HELP incus_memory_Inactive_file_bytes The amount of file-backed memory on inactive LRU list.",
-	MemoryInactiveBytes:         "# HELP incus_memory_Inactive_bytes The amount of memory on inactive LRU list.",
-	MemoryMappedBytes:           "# HELP incus_memory_Mapped_bytes The amount of mapped memory.",
-	MemoryMemAvailableBytes:     "# HELP incus_memory_MemAvailable_bytes The amount of available memory.",
-	MemoryMemFreeBytes:          "# HELP incus_memory_MemFree_bytes The amount of free memory.",
-	MemoryMemTotalBytes:         "# HELP incus_memory_MemTotal_bytes The amount of used memory.",
-	MemoryRSSBytes:              "# HELP incus_memory_RSS_bytes The amount of anonymous and swap cache memory.",
-	MemoryShmemBytes:            "# HELP incus_memory_Shmem_bytes The amount of cached filesystem data that is swap-backed.",
-	MemorySwapBytes:             "# HELP incus_memory_Swap_bytes The amount of used swap memory.",
-	MemoryUnevictableBytes:      "# HELP incus_memory_Unevictable_bytes The amount of unevictable memory.",
-	MemoryWritebackBytes:        "# HELP incus_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
-	MemoryOOMKillsTotal:         "# HELP incus_memory_OOM_kills_total The number of out of memory kills.",
-	NetworkReceiveBytesTotal:    "# HELP incus_network_receive_bytes_total The amount of received bytes on a given interface.",
-	NetworkReceiveDropTotal:     "# HELP incus_network_receive_drop_total The amount of received dropped bytes on a given interface.",
-	NetworkReceiveErrsTotal:     "# HELP incus_network_receive_errs_total The amount of received errors on a given interface.",
-	NetworkReceivePacketsTotal:  "# HELP incus_network_receive_packets_total The amount of received packets on a given interface.",
-	NetworkTransmitBytesTotal:   "# HELP incus_network_transmit_bytes_total The amount of transmitted bytes on a given interface.",
-	NetworkTransmitDropTotal:    "# HELP incus_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
-	NetworkTransmitErrsTotal:    "# HELP incus_network_transmit_errs_total The amount of transmitted errors on a given interface.",
-	NetworkTransmitPacketsTotal: "# HELP incus_network_transmit_packets_total The amount of transmitted packets on a given interface.",
-	OperationsTotal:             "# HELP incus_operations_total The number of running operations",
-	ProcsTotal:                  "# HELP incus_procs_total The number of running processes.",
-	UptimeSeconds:               "# HELP incus_uptime_seconds The daemon uptime in seconds.",
-	WarningsTotal:               "# HELP incus_warnings_total The number of active warnings.",
+	CPUSecondsTotal:                   "# HELP incus_cpu_seconds_total The total number of CPU time used in seconds.",
+	CPUs:                              "# HELP incus_cpu_effective_total The total number of effective CPUs.",
+	DiskReadBytesTotal:                "# HELP incus_disk_read_bytes_total The total number of bytes read.",
+	DiskReadsCompletedTotal:           "# HELP incus_disk_reads_completed_total The total number of completed reads.",
+	DiskWrittenBytesTotal:             "# HELP incus_disk_written_bytes_total The total number of bytes written.",
+	DiskWritesCompletedTotal:          "# HELP incus_disk_writes_completed_total The total number of completed writes.",
+	FilesystemAvailBytes:              "# HELP incus_filesystem_avail_bytes The number of available space in bytes.",
+	FilesystemFreeBytes:               "# HELP incus_filesystem_free_bytes The number of free space in bytes.",
+	FilesystemSizeBytes:               "# HELP incus_filesystem_size_bytes The size of the filesystem in bytes.",
+	GoAllocBytes:                      "# HELP incus_go_alloc_bytes Number of bytes allocated and still in use.",
+	GoAllocBytesTotal:                 "# HELP incus_go_alloc_bytes_total Total number of bytes allocated, even if freed.",
+	GoBuckHashSysBytes:                "# HELP incus_go_buck_hash_sys_bytes Number of bytes used by the profiling bucket hash table.",
+	GoFreesTotal:                      "# HELP incus_go_frees_total Total number of frees.",
+	GoGCSysBytes:                      "# HELP incus_go_gc_sys_bytes Number of bytes used for garbage collection system metadata.",
+	GoGoroutines:                      "# HELP incus_go_goroutines Number of goroutines that currently exist.",
+	GoHeapAllocBytes:                  "# HELP incus_go_heap_alloc_bytes Number of heap bytes allocated and still in use.",
+	GoHeapIdleBytes:                   "# HELP incus_go_heap_idle_bytes Number of heap bytes waiting to be used.",
+	GoHeapInuseBytes:                  "# HELP incus_go_heap_inuse_bytes Number of heap bytes that are in use.",
+	GoHeapObjects:                     "# HELP incus_go_heap_objects Number of allocated objects.",
+	GoHeapReleasedBytes:               "# HELP incus_go_heap_released_bytes Number of heap bytes released to OS.",
+	GoHeapSysBytes:                    "# HELP incus_go_heap_sys_bytes Number of heap bytes obtained from system.",
+	GoLookupsTotal:                    "# HELP incus_go_lookups_total Total number of pointer lookups.",
+	GoMallocsTotal:                    "# HELP incus_go_mallocs_total Total number of mallocs.",
+	GoMCacheInuseBytes:                "# HELP incus_go_mcache_inuse_bytes Number of bytes in use by mcache structures.",
+	GoMCacheSysBytes:                  "# HELP incus_go_mcache_sys_bytes Number of bytes used for mcache structures obtained from system.",
+	GoMSpanInuseBytes:                 "# HELP incus_go_mspan_inuse_bytes Number of bytes in use by mspan structures.",
+	GoMSpanSysBytes:                   "# HELP incus_go_mspan_sys_bytes Number of bytes used for mspan structures obtained from system.",
+	GoNextGCBytes:                     "# HELP incus_go_next_gc_bytes Number of heap bytes when next garbage collection will take place.",
+	GoOtherSysBytes:                   "# HELP incus_go_other_sys_bytes Number of bytes used for other system allocations.",
+	GoStackInuseBytes:                 "# HELP incus_go_stack_inuse_bytes Number of bytes in use by the stack allocator.",
+	GoStackSysBytes:                   "# HELP incus_go_stack_sys_bytes Number of bytes obtained from system for stack allocator.",
+	GoSysBytes:                        "# HELP incus_go_sys_bytes Number of bytes obtained from system.",
+	MemoryActiveAnonBytes:             "# HELP incus_memory_Active_anon_bytes The amount of anonymous memory on active LRU list.",
+	MemoryActiveFileBytes:             "# HELP incus_memory_Active_file_bytes The amount of file-backed memory on active LRU list.",
+	MemoryActiveBytes:                 "# HELP incus_memory_Active_bytes The amount of memory on active LRU list.",
+	MemoryCachedBytes:                 "# HELP incus_memory_Cached_bytes The amount of cached memory.",
+	MemoryDirtyBytes:                  "# HELP incus_memory_Dirty_bytes The amount of memory waiting to get written back to the disk.",
+	MemoryHugePagesFreeBytes:          "# HELP incus_memory_HugepagesFree_bytes The amount of free memory for hugetlb.",
+	MemoryHugePagesTotalBytes:         "# HELP incus_memory_HugepagesTotal_bytes The amount of used memory for hugetlb.",
+	MemoryInactiveAnonBytes:           "# HELP incus_memory_Inactive_anon_bytes The amount of anonymous memory on inactive LRU list.",
+	MemoryInactiveFileBytes:           "# HELP incus_memory_Inactive_file_bytes The amount of file-backed memory on inactive LRU list.",
+	MemoryInactiveBytes:               "# HELP incus_memory_Inactive_bytes The amount of memory on inactive LRU list.",
+	MemoryMappedBytes:                 "# HELP incus_memory_Mapped_bytes The amount of mapped memory.",
+	MemoryMemAvailableBytes:           "# HELP incus_memory_MemAvailable_bytes The amount of available memory.",
+	MemoryMemFreeBytes:                "# HELP incus_memory_MemFree_bytes The amount of free memory.",
+	MemoryMemTotalBytes:               "# HELP incus_memory_MemTotal_bytes The amount of used memory.",
+	MemoryRSSBytes:                    "# HELP incus_memory_RSS_bytes The amount of anonymous and swap cache memory.",
+	MemoryShmemBytes:                  "# HELP incus_memory_Shmem_bytes The amount of cached filesystem data that is swap-backed.",
+	MemorySwapBytes:                   "# HELP incus_memory_Swap_bytes The amount of used swap memory.",
+	MemoryUnevictableBytes:            "# HELP incus_memory_Unevictable_bytes The amount of unevictable memory.",
+	MemoryWritebackBytes:              "# HELP incus_memory_Writeback_bytes The amount of memory queued for syncing to disk.",
+	MemoryOOMKillsTotal:               "# HELP incus_memory_OOM_kills_total The number of out of memory kills.",
+	NetworkReceiveBytesTotal:          "# HELP incus_network_receive_bytes_total The amount of received bytes on a given interface.",
+	NetworkReceiveDropTotal:           "# HELP incus_network_receive_drop_total The amount of received dropped bytes on a given interface.",
+	NetworkReceiveErrsTotal:           "# HELP incus_network_receive_errs_total The amount of received errors on a given interface.",
+	NetworkReceivePacketsTotal:        "# HELP incus_network_receive_packets_total The amount of received packets on a given interface.",
+	NetworkTransmitBytesTotal:         "# HELP incus_network_transmit_bytes_total The amount of transmitted bytes on a given interface.",
+	NetworkTransmitDropTotal:          "# HELP incus_network_transmit_drop_total The amount of transmitted dropped bytes on a given interface.",
+	NetworkTransmitErrsTotal:          "# HELP incus_network_transmit_errs_total The amount of transmitted errors on a given interface.",
+	NetworkTransmitPacketsTotal:       "# HELP incus_network_transmit_packets_total The amount of transmitted packets on a given interface.",
+	OperationsTotal:                   "# HELP incus_operations_total The number of running operations",
+	PressureFullSecondsTotal:          "# HELP incus_pressure_full_seconds_total The total time in seconds during which all non-idle tasks were stalled on a resource.",
+	PressureSomeSecondsTotal:          "# HELP incus_pressure_some_seconds_total The total time in seconds during which at least some tasks were stalled on a resource.",
+	ProcsTotal:                        "# HELP incus_procs_total The number of running processes.",
+	StorageVolumeQuotaBytes:           "# HELP incus_storage_volume_quota_bytes The size limit of the storage volume in bytes.",
+	StorageVolumeReadBytesTotal:       "# HELP incus_storage_volume_read_bytes_total The total number of bytes read from the storage volume.",
+	StorageVolumeReadsCompletedTotal:  "# HELP incus_storage_volume_reads_completed_total The total number of completed reads from the storage volume.",
+	StorageVolumeUsedBytes:            "# HELP incus_storage_volume_used_bytes The used space of the storage volume in bytes.",
+	StorageVolumeWritesCompletedTotal: "# HELP incus_storage_volume_writes_completed_total The total number of completed writes to the storage volume.",
+	StorageVolumeWrittenBytesTotal:    "# HELP incus_storage_volume_written_bytes_total The total number of bytes written to the storage volume.",
+	UptimeSeconds:                     "# HELP incus_uptime_seconds The daemon uptime in seconds.",
+	WarningsTotal:                     "# HELP incus_warnings_total The number of active warnings.",
 }
@@ -312,6 +312,15 @@ func (n *bridge) Validate(config map[string]string) error {
 		//  shortdesc: Static routes to provide via DHCP option 121, as a comma-separated list of alternating subnets (CIDR) and gateway addresses (same syntax as dnsmasq)
 		"ipv4.dhcp.routes": validate.Optional(validate.IsDHCPRouteList),
 
+		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.metadata)
+		//
+		// ---
+		//  type: bool
+		//  condition: IPv4 address
+		//  default: `false`
+		//  shortdesc: Whether to serve EC2 and NoCloud style metadata on `169.254.169.254` (see {ref}`network-bridge-metadata`)
+		"ipv4.metadata": validate.Optional(validate.IsBool),
+
 		// gendoc:generate(entity=network_bridge, group=common, key=ipv4.routes)
 		//
 		// ---
@@ -747,6 +756,11 @@ func (n *bridge) Validate(config map[string]string) error {
 		}
 	}
 
+	// The cloud metadata service relies on the kernel bridge forwarding database to identify instances.
+	if util.IsTrue(config["ipv4.metadata"]) && config["bridge.driver"] == "openvswitch" {
+		return errors.New(`"ipv4.metadata" can't be used with the "openvswitch" bridge driver`)
+	}
+
 	// Check using same MAC address on every cluster node is safe.
 	if config["bridge.hwaddr"] != "" {
 		err = n.checkClusterWideMACSafe(config)
@@ -1008,7 +1022,7 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		}
 
 		// Load server certificate. This is needs to be the same certificate for all nodes in a cluster.
-		cert, err := internalUtil.LoadCert(n.state.OS.VarDir)
+		cert, err := internalUtil.LoadCert(n.state.OS.VarDir, n.state.LocalConfig.ServerKeyURI())
 		if err != nil {
 			return err
 		}
@@ -1464,6 +1478,18 @@ func (n *bridge) setup(oldConfig map[string]string) error {
 		n.applyBootRoutesV4(ctRoutes)
 	}
 
+	// Configure the cloud metadata service.
+	if util.IsTrue(n.config["ipv4.metadata"]) && !util.IsNoneOrEmpty(n.config["ipv4.address"]) {
+		err = n.metadataSetup()
+		if err != nil {
+			return err
+		}
+
+		reverter.Add(func() { metadataStop(n.name) })
+	} else {
+		metadataStop(n.name)
+	}
+
 	// Snapshot container specific IPv6 routes (added with boot proto) before removing IPv6 addresses.
 	// This is because the kernel removes any static routes on an interface when all addresses removed.
 	ctRoutes, err = n.bootRoutesV6()
@@ -1975,6 +2001,9 @@ func (n *bridge) Stop() error {
 		return nil
 	}
 
+	// Stop the cloud metadata service.
+	metadataStop(n.name)
+
 	// Clear BGP.
 	err := n.bgpClear(n.config)
 	if err != nil {
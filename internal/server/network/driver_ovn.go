@@ -45,6 +45,7 @@ import (
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 	"github.com/lxc/incus/v6/shared/validate"
 )
@@ -1080,7 +1081,7 @@ func (n *ovn) getRouterMAC() (net.HardwareAddr, error) {
 	hwAddr := n.config["bridge.hwaddr"]
 	if hwAddr == "" {
 		// Load server certificate. This is needs to be the same certificate for all nodes in a cluster.
-		cert, err := internalUtil.LoadCert(n.state.OS.VarDir)
+		cert, err := internalUtil.LoadCert(n.state.OS.VarDir, n.state.LocalConfig.ServerKeyURI())
 		if err != nil {
 			return nil, err
 		}
@@ -4879,6 +4880,34 @@ func (n *ovn) InstanceDevicePortStart(opts *OVNInstanceNICSetupOpts, securityACL
 		n.logger.Debug("Cleared NIC default rule", logger.Ctx{"port": instancePortName})
 	}
 
+	// Apply bandwidth limits.
+	limitIngress := opts.DeviceConfig["limits.ingress"]
+	limitEgress := opts.DeviceConfig["limits.egress"]
+	if opts.DeviceConfig["limits.max"] != "" {
+		limitIngress = opts.DeviceConfig["limits.max"]
+		limitEgress = opts.DeviceConfig["limits.max"]
+	}
+
+	var ingress, egress int64
+	if limitIngress != "" {
+		ingress, err = units.ParseBitSizeString(limitIngress)
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid ingress limit %q: %w", limitIngress, err)
+		}
+	}
+
+	if limitEgress != "" {
+		egress, err = units.ParseBitSizeString(limitEgress)
+		if err != nil {
+			return "", nil, fmt.Errorf("Invalid egress limit %q: %w", limitEgress, err)
+		}
+	}
+
+	err = n.ovnnb.UpdateLogicalSwitchPortQoS(context.TODO(), n.getIntSwitchName(), instancePortName, ingress, egress)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed applying OVN QoS rules for instance NIC: %w", err)
+	}
+
 	reverter.Success()
 	return instancePortName, dnsIPs, nil
 }
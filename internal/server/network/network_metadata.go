@@ -0,0 +1,326 @@
+package network
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	"gopkg.in/yaml.v2"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/ip"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// metadataAddress is the link-local address the cloud metadata service listens on.
+const metadataAddress = "169.254.169.254"
+
+// metadataServers holds the running cloud metadata services (by network name).
+var metadataServers = map[string]*http.Server{}
+
+var metadataServersMu sync.Mutex
+
+// metadataSetup adds the link-local address to the bridge and starts the cloud metadata service on it.
+func (n *bridge) metadataSetup() error {
+	metadataStop(n.name)
+
+	addr := &ip.Addr{
+		DevName: n.name,
+		Address: metadataAddress + "/32",
+		Family:  ip.FamilyV4,
+	}
+
+	err := addr.Add()
+	if err != nil {
+		return fmt.Errorf("Failed adding metadata address: %w", err)
+	}
+
+	// Bind to the bridge so that each network gets its own listener on the shared address.
+	listenConfig := net.ListenConfig{
+		Control: func(network string, address string, c syscall.RawConn) error {
+			var sockErr error
+
+			err := c.Control(func(fd uintptr) {
+				sockErr = unix.SetsockoptString(int(fd), unix.SOL_SOCKET, unix.SO_BINDTODEVICE, n.name)
+			})
+			if err != nil {
+				return err
+			}
+
+			return sockErr
+		},
+	}
+
+	listener, err := listenConfig.Listen(context.Background(), "tcp4", net.JoinHostPort(metadataAddress, "80"))
+	if err != nil {
+		return fmt.Errorf("Failed starting metadata listener: %w", err)
+	}
+
+	server := &http.Server{Handler: http.HandlerFunc(n.metadataHandler)}
+
+	metadataServersMu.Lock()
+	metadataServers[n.name] = server
+	metadataServersMu.Unlock()
+
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			n.logger.Warn("Cloud metadata service stopped", logger.Ctx{"err": err})
+		}
+	}()
+
+	return nil
+}
+
+// metadataStop stops the cloud metadata service of a network if running.
+func metadataStop(networkName string) {
+	metadataServersMu.Lock()
+	server := metadataServers[networkName]
+	delete(metadataServers, networkName)
+	metadataServersMu.Unlock()
+
+	if server != nil {
+		_ = server.Close()
+	}
+}
+
+// metadataInstance finds the local instance the request comes from.
+// The neighbour entry of the requesting address gives its MAC address and the bridge forwarding database the port
+// that MAC address was last seen on. Both have to match the same NIC, identified by its host side interface, so that
+// another instance on the bridge can't get answered by sending frames with the MAC and IP addresses of its target.
+func (n *bridge) metadataInstance(remoteAddr string) (instance.Instance, error) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteIP := net.ParseIP(host)
+	if remoteIP == nil {
+		return nil, fmt.Errorf("Invalid remote address %q", host)
+	}
+
+	neigh := &ip.Neigh{DevName: n.name}
+	neighbours, err := neigh.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	var hwAddr net.HardwareAddr
+	for _, neighbour := range neighbours {
+		if neighbour.Addr.Equal(remoteIP) {
+			hwAddr = neighbour.MAC
+			break
+		}
+	}
+
+	if hwAddr == nil {
+		return nil, fmt.Errorf("No neighbour entry for %q", remoteIP.String())
+	}
+
+	fdb := &ip.FDB{DevName: n.name, MAC: hwAddr}
+	entries, err := fdb.Show()
+	if err != nil {
+		return nil, err
+	}
+
+	var port string
+	for _, entry := range entries {
+		if !entry.Permanent {
+			port = entry.DevName
+			break
+		}
+	}
+
+	if port == "" {
+		return nil, fmt.Errorf("No forwarding database entry for %q", hwAddr.String())
+	}
+
+	var instProject, instName string
+	var matchErr error
+	filter := dbCluster.InstanceFilter{Node: &n.state.ServerName}
+	err = UsedByInstanceDevices(n.state, n.project, n.name, n.Type(), func(inst db.InstanceArgs, nicName string, nicConfig map[string]string) error {
+		ok, err := metadataNICMatches(inst.Config, nicName, nicConfig, hwAddr, port)
+		if err != nil {
+			matchErr = fmt.Errorf("Instance %q: %w", inst.Name, err)
+			return nil
+		}
+
+		if ok {
+			instProject = inst.Project
+			instName = inst.Name
+		}
+
+		return nil
+	}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if instName == "" {
+		if matchErr != nil {
+			return nil, matchErr
+		}
+
+		return nil, fmt.Errorf("No instance found for %q on %q", hwAddr.String(), port)
+	}
+
+	return instance.LoadByProjectAndName(n.state, instProject, instName)
+}
+
+// metadataNICMatches checks whether a NIC is the source of a request from the given MAC address received on the given
+// bridge port. Matching NICs must have MAC and IPv4 filtering enabled.
+func metadataNICMatches(instConfig map[string]string, nicName string, nicConfig map[string]string, hwAddr net.HardwareAddr, port string) (bool, error) {
+	if instConfig[fmt.Sprintf("volatile.%s.host_name", nicName)] != port {
+		return false, nil
+	}
+
+	nicHwAddr := nicConfig["hwaddr"]
+	if nicHwAddr == "" {
+		nicHwAddr = instConfig[fmt.Sprintf("volatile.%s.hwaddr", nicName)]
+	}
+
+	mac, _ := net.ParseMAC(nicHwAddr)
+	if mac == nil || mac.String() != hwAddr.String() {
+		return false, fmt.Errorf("NIC %q received a request from unexpected MAC address %q", nicName, hwAddr.String())
+	}
+
+	if util.IsFalseOrEmpty(nicConfig["security.mac_filtering"]) || util.IsFalseOrEmpty(nicConfig["security.ipv4_filtering"]) {
+		return false, fmt.Errorf("NIC %q doesn't have security.mac_filtering and security.ipv4_filtering enabled", nicName)
+	}
+
+	return true, nil
+}
+
+// metadataHandler serves the EC2 (/latest/...) and NoCloud (/meta-data, /user-data, ...) metadata of the requesting instance.
+func (n *bridge) metadataHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := n.metadataInstance(r.RemoteAddr)
+	if err != nil {
+		n.logger.Debug("Refusing cloud metadata request", logger.Ctx{"remote": r.RemoteAddr, "err": err})
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// EC2 clients first request a session token (IMDSv2), any token is accepted afterwards.
+	if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/api/token") {
+		token := make([]byte, 16)
+		_, _ = rand.Read(token)
+		_, _ = fmt.Fprint(w, hex.EncodeToString(token))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	localIPv4, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+	content, ok := metadataContent(inst.Name(), inst.CloudInitID(), inst.ExpandedConfig(), localIPv4, r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = fmt.Fprint(w, content)
+}
+
+// metadataContent returns the metadata document at the given path for an instance from its name, cloud-init
+// instance ID, expanded configuration and IPv4 address.
+func metadataContent(name string, instanceID string, config map[string]string, localIPv4 string, path string) (string, bool) {
+	cloudInitConfig := func(key string, fallback string) string {
+		value, ok := config["cloud-init."+key]
+		if !ok {
+			value = config["user."+key]
+		}
+
+		if value == "" {
+			return fallback
+		}
+
+		return value
+	}
+
+	userData := cloudInitConfig("user-data", "#cloud-config\n{}")
+	sshKeys := metadataSSHKeys(userData)
+
+	// NoCloud layout, also used by the EC2 layout for user-data.
+	nocloud := map[string]string{
+		"meta-data":   fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n%s", instanceID, name, config["user.meta-data"]),
+		"user-data":   userData,
+		"vendor-data": cloudInitConfig("vendor-data", "#cloud-config\n{}"),
+	}
+
+	networkConfig := cloudInitConfig("network-config", "")
+	if networkConfig != "" {
+		nocloud["network-config"] = networkConfig
+	}
+
+	path = strings.Trim(path, "/")
+	value, ok := nocloud[path]
+	if ok {
+		return value, true
+	}
+
+	// The network configuration is optional.
+	if path == "network-config" {
+		return "", false
+	}
+
+	// EC2 layout, accepting any API version ("latest", "2009-04-04", ...).
+	_, path, found := strings.Cut(path, "/")
+	if !found {
+		return "meta-data/\nuser-data", true
+	}
+
+	ec2 := map[string]string{
+		"user-data":                           userData,
+		"meta-data":                           "hostname\ninstance-id\nlocal-hostname\nlocal-ipv4\npublic-keys/",
+		"meta-data/hostname":                  name,
+		"meta-data/instance-id":               instanceID,
+		"meta-data/local-hostname":            name,
+		"meta-data/local-ipv4":                localIPv4,
+		"meta-data/public-keys":               "",
+		"meta-data/public-keys/0":             "openssh-key",
+		"meta-data/public-keys/0/openssh-key": strings.Join(sshKeys, "\n"),
+	}
+
+	if len(sshKeys) > 0 {
+		ec2["meta-data/public-keys"] = "0=incus"
+	} else {
+		delete(ec2, "meta-data/public-keys/0")
+		delete(ec2, "meta-data/public-keys/0/openssh-key")
+	}
+
+	value, ok = ec2[path]
+	return value, ok
+}
+
+// metadataSSHKeys returns the top-level ssh_authorized_keys of a cloud-config user-data.
+func metadataSSHKeys(userData string) []string {
+	if !strings.HasPrefix(userData, "#cloud-config") {
+		return nil
+	}
+
+	var cloudConfig struct {
+		SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys"`
+	}
+
+	err := yaml.Unmarshal([]byte(userData), &cloudConfig)
+	if err != nil {
+		return nil
+	}
+
+	return cloudConfig.SSHAuthorizedKeys
+}
@@ -0,0 +1,186 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataContent(t *testing.T) {
+	userData := "#cloud-config\nssh_authorized_keys:\n- ssh-ed25519 AAAA user@host\n"
+
+	config := map[string]string{
+		"cloud-init.user-data": userData,
+		"user.vendor-data":     "#cloud-config\npackages: [vim]",
+		"user.meta-data":       "zone: a\n",
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		path     string
+		expected string
+		found    bool
+	}{
+		{
+			name:     "NoCloud meta-data",
+			config:   config,
+			path:     "/meta-data",
+			expected: "instance-id: 1234\nlocal-hostname: c1\nzone: a\n",
+			found:    true,
+		},
+		{
+			name:     "NoCloud user-data",
+			config:   config,
+			path:     "/user-data",
+			expected: userData,
+			found:    true,
+		},
+		{
+			name:     "NoCloud vendor-data from user key",
+			config:   config,
+			path:     "/vendor-data",
+			expected: "#cloud-config\npackages: [vim]",
+			found:    true,
+		},
+		{
+			name:     "NoCloud default user-data",
+			config:   map[string]string{},
+			path:     "/user-data",
+			expected: "#cloud-config\n{}",
+			found:    true,
+		},
+		{
+			name:   "NoCloud network-config unset",
+			config: config,
+			path:   "/network-config",
+			found:  false,
+		},
+		{
+			name:     "EC2 index",
+			config:   config,
+			path:     "/latest",
+			expected: "meta-data/\nuser-data",
+			found:    true,
+		},
+		{
+			name:     "EC2 local IPv4",
+			config:   config,
+			path:     "/latest/meta-data/local-ipv4",
+			expected: "10.0.0.2",
+			found:    true,
+		},
+		{
+			name:     "EC2 instance ID with API version",
+			config:   config,
+			path:     "/2009-04-04/meta-data/instance-id",
+			expected: "1234",
+			found:    true,
+		},
+		{
+			name:     "EC2 public keys",
+			config:   config,
+			path:     "/latest/meta-data/public-keys/0/openssh-key",
+			expected: "ssh-ed25519 AAAA user@host",
+			found:    true,
+		},
+		{
+			name:   "EC2 public keys without keys",
+			config: map[string]string{},
+			path:   "/latest/meta-data/public-keys/0/openssh-key",
+			found:  false,
+		},
+		{
+			name:   "Unknown path",
+			config: config,
+			path:   "/latest/meta-data/unknown",
+			found:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, found := metadataContent("c1", "1234", tt.config, "10.0.0.2", tt.path)
+			assert.Equal(t, tt.found, found)
+			if tt.found {
+				assert.Equal(t, tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestMetadataNICMatches(t *testing.T) {
+	victimMAC, _ := net.ParseMAC("00:16:3e:00:00:01")
+
+	victimConfig := map[string]string{
+		"volatile.eth0.host_name": "veth-victim",
+		"volatile.eth0.hwaddr":    "00:16:3e:00:00:01",
+	}
+
+	victimNIC := map[string]string{
+		"security.mac_filtering":  "true",
+		"security.ipv4_filtering": "true",
+	}
+
+	attackerConfig := map[string]string{
+		"volatile.eth0.host_name": "veth-attacker",
+		"volatile.eth0.hwaddr":    "00:16:3e:00:00:02",
+	}
+
+	tests := []struct {
+		name       string
+		instConfig map[string]string
+		nicConfig  map[string]string
+		hwAddr     net.HardwareAddr
+		port       string
+		match      bool
+		err        bool
+	}{
+		{
+			name:       "Request from the instance's own port",
+			instConfig: victimConfig,
+			nicConfig:  victimNIC,
+			hwAddr:     victimMAC,
+			port:       "veth-victim",
+			match:      true,
+		},
+		{
+			name:       "Spoofed MAC address doesn't match the target NIC",
+			instConfig: victimConfig,
+			nicConfig:  victimNIC,
+			hwAddr:     victimMAC,
+			port:       "veth-attacker",
+			match:      false,
+		},
+		{
+			name:       "Spoofed MAC address doesn't match the sending NIC",
+			instConfig: attackerConfig,
+			nicConfig:  map[string]string{},
+			hwAddr:     victimMAC,
+			port:       "veth-attacker",
+			err:        true,
+		},
+		{
+			name:       "NIC without filtering",
+			instConfig: victimConfig,
+			nicConfig:  map[string]string{},
+			hwAddr:     victimMAC,
+			port:       "veth-victim",
+			err:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := metadataNICMatches(tt.instConfig, "eth0", tt.nicConfig, tt.hwAddr, tt.port)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.match, match)
+		})
+	}
+}
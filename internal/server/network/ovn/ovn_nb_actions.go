@@ -84,6 +84,9 @@ const (
 	ovnExtIDIncusLocation   = "incus_location"
 )
 
+// ovnQoSPriority is the priority used for the QoS rules applied to instance ports.
+const ovnQoSPriority = 100
+
 // OVNIPv6RAOpts IPv6 router advertisements options that can be applied to a router.
 type OVNIPv6RAOpts struct {
 	SendPeriodic       bool
@@ -1667,6 +1670,138 @@ func (o *NB) logicalSwitchPortACLRules(ctx context.Context, portName OVNSwitchPo
 	return ruleUUIDs, nil
 }
 
+// logicalSwitchPortQoSRules returns the QoS rules belonging to a logical switch port.
+func (o *NB) logicalSwitchPortQoSRules(ctx context.Context, portName OVNSwitchPort) ([]ovnNB.QoS, error) {
+	qosRules := []ovnNB.QoS{}
+
+	err := o.client.WhereCache(func(qos *ovnNB.QoS) bool {
+		return qos.ExternalIDs != nil && qos.ExternalIDs[ovnExtIDIncusSwitchPort] == string(portName)
+	}).List(ctx, &qosRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return qosRules, nil
+}
+
+// logicalSwitchPortQoSDeleteOperations returns the operations that delete the QoS rules of a logical switch port.
+func (o *NB) logicalSwitchPortQoSDeleteOperations(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort) ([]ovsdb.Operation, error) {
+	operations := []ovsdb.Operation{}
+
+	qosRules, err := o.logicalSwitchPortQoSRules(ctx, portName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, qos := range qosRules {
+		logicalSwitch := ovnNB.LogicalSwitch{
+			Name: string(switchName),
+		}
+
+		updateOps, err := o.client.Where(&logicalSwitch).Mutate(&logicalSwitch, ovsModel.Mutation{
+			Field:   &logicalSwitch.QOSRules,
+			Mutator: ovsdb.MutateOperationDelete,
+			Value:   []string{qos.UUID},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, updateOps...)
+
+		deleteOps, err := o.client.Where(&qos).Delete()
+		if err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, deleteOps...)
+	}
+
+	return operations, nil
+}
+
+// UpdateLogicalSwitchPortQoS applies bandwidth limits (in bit/s) to a logical switch port, replacing any existing ones.
+// The ingress limit applies to traffic sent to the port and the egress limit to traffic sent from it.
+// A zero limit means no limit in that direction.
+func (o *NB) UpdateLogicalSwitchPortQoS(ctx context.Context, switchName OVNSwitch, portName OVNSwitchPort, ingress int64, egress int64) error {
+	// Remove any existing rules.
+	operations, err := o.logicalSwitchPortQoSDeleteOperations(ctx, switchName, portName)
+	if err != nil {
+		return err
+	}
+
+	// Add the new rules.
+	for i, rule := range []struct {
+		direction string
+		match     string
+		limit     int64
+	}{
+		{direction: ovnNB.QoSDirectionToLport, match: fmt.Sprintf(`outport == "%s"`, portName), limit: ingress},
+		{direction: ovnNB.QoSDirectionFromLport, match: fmt.Sprintf(`inport == "%s"`, portName), limit: egress},
+	} {
+		if rule.limit <= 0 {
+			continue
+		}
+
+		// OVN expects the rate and burst in kbit/s and kbit.
+		rate := max(int(rule.limit/1000), 1)
+
+		qos := ovnNB.QoS{
+			UUID:      fmt.Sprintf("qos%d", i),
+			Direction: rule.direction,
+			Priority:  ovnQoSPriority,
+			Match:     rule.match,
+			Bandwidth: map[string]int{
+				ovnNB.QoSBandwidthRate:  rate,
+				ovnNB.QoSBandwidthBurst: rate,
+			},
+			ExternalIDs: map[string]string{
+				ovnExtIDIncusSwitch:     string(switchName),
+				ovnExtIDIncusSwitchPort: string(portName),
+			},
+		}
+
+		createOps, err := o.client.Create(&qos)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, createOps...)
+
+		logicalSwitch := ovnNB.LogicalSwitch{
+			Name: string(switchName),
+		}
+
+		updateOps, err := o.client.Where(&logicalSwitch).Mutate(&logicalSwitch, ovsModel.Mutation{
+			Field:   &logicalSwitch.QOSRules,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{qos.UUID},
+		})
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, updateOps...)
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	// Apply the changes.
+	resp, err := o.client.Transact(ctx, operations...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ovsdb.CheckOperationResults(resp, operations)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetLogicalSwitchPorts returns a map of logical switch ports (name and UUID) for a switch.
 // Includes non-instance ports, such as the router port.
 func (o *NB) GetLogicalSwitchPorts(ctx context.Context, switchName OVNSwitch) (map[OVNSwitchPort]OVNSwitchPortUUID, error) {
@@ -2312,6 +2447,14 @@ func (o *NB) logicalSwitchPortDeleteOperations(ctx context.Context, switchName O
 
 	operations = append(operations, updateOps...)
 
+	// Remove any QoS rules of the port.
+	qosOps, err := o.logicalSwitchPortQoSDeleteOperations(ctx, switchName, portName)
+	if err != nil {
+		return nil, err
+	}
+
+	operations = append(operations, qosOps...)
+
 	// Delete the port itself.
 	deleteOps, err := o.client.Where(&logicalSwitchPort).Delete()
 	if err != nil {
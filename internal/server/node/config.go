@@ -46,6 +46,11 @@ func (c *Config) HTTPSAddress() string {
 	return networkAddress
 }
 
+// ServerKeyURI returns the URI of the private key of the server certificate, if held in a PKCS#11 token or TPM.
+func (c *Config) ServerKeyURI() string {
+	return c.m.GetString("core.server_key_uri")
+}
+
 // BGPAddress returns the address and port to setup the BGP listener on.
 func (c *Config) BGPAddress() string {
 	return c.m.GetString("core.bgp_address")
@@ -106,6 +111,11 @@ func (c *Config) StorageBucketsAddress() string {
 	return objectAddress
 }
 
+// BackupsThrottle returns the rate limit to apply to backup creation, upload and export.
+func (c *Config) BackupsThrottle() string {
+	return c.m.GetString("backups.throttle")
+}
+
 // StorageBackupsVolume returns the name of the pool/volume to use for storing backup tarballs.
 func (c *Config) StorageBackupsVolume() string {
 	return c.m.GetString("storage.backups_volume")
@@ -171,6 +181,18 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Address to bind for the remote API (HTTPS)
 	"core.https_address": {Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+	// Private key of the server certificate
+
+	// gendoc:generate(entity=server, group=core, key=core.server_key_uri)
+	// Set this to a PKCS#11 URI or a TPM key handle to use a private key that isn't stored on disk.
+	// See {ref}`authentication-server-key`. Changes take effect after restarting the daemon.
+	// Can't be used together with ACME on standalone servers.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: URI of the private key of the server certificate
+	"core.server_key_uri": {Validator: validate.Optional(internalUtil.ValidateKeyURI)},
+
 	// Network address for cluster communication
 
 	// gendoc:generate(entity=server, group=cluster, key=cluster.https_address)
@@ -261,6 +283,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OVS socket path
 	"network.ovs.connection": {Default: "unix:/run/openvswitch/db.sock"},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=backups.throttle)
+	// Limits the rate at which backup tarballs are created, uploaded to backup targets and exported, in bytes per second (units such as `50MB` are supported).
+	// It can be overridden for a single backup through the `throttle` field of the backup request.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Rate limit for backups (unlimited if not set)
+	"backups.throttle": {Validator: validate.Optional(validate.IsSize)},
+
 	// Storage volumes to store backups/images on
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=storage.backups_volume)
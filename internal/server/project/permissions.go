@@ -826,6 +826,35 @@ func CheckRestrictedDevicesDiskPaths(projectConfig map[string]string, sourcePath
 	return false, ""
 }
 
+// GetRestrictedFilesPaths returns the list of paths to which file access within the project's instances is restricted.
+// An empty list means that file access isn't restricted.
+func GetRestrictedFilesPaths(p *api.Project) []string {
+	if util.IsFalseOrEmpty(p.Config["restricted"]) {
+		return nil
+	}
+
+	return util.SplitNTrimSpace(p.Config["restricted.files.paths"], ",", -1, true)
+}
+
+// CheckRestrictedFilesPath checks whether the path is within one of the allowed paths.
+// If no allowed paths are specified, then all paths are allowed.
+func CheckRestrictedFilesPath(allowedPaths []string, path string) bool {
+	if len(allowedPaths) == 0 {
+		return true
+	}
+
+	// Clean, then add trailing slash, to ensure we are prefix matching on whole path.
+	path = fmt.Sprintf("%s/", filepath.Clean(path))
+	for _, allowedPath := range allowedPaths {
+		allowedPathTrailing := fmt.Sprintf("%s/", filepath.Clean(allowedPath))
+		if allowedPathTrailing == "//" || strings.HasPrefix(path, allowedPathTrailing) {
+			return true
+		}
+	}
+
+	return false
+}
+
 var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
@@ -854,6 +883,7 @@ var allRestrictions = map[string]string{
 	"restricted.devices.nic":               "managed",
 	"restricted.devices.disk":              "managed",
 	"restricted.devices.disk.paths":        "",
+	"restricted.files.paths":               "",
 	"restricted.idmap.uid":                 "",
 	"restricted.idmap.gid":                 "",
 	"restricted.networks.access":           "",
@@ -877,8 +907,14 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 		return true
 	}
 
+	// Lifecycle hooks run on the host.
+	if strings.HasPrefix(key, "hooks.") {
+		return true
+	}
+
 	if slices.Contains([]string{
 		"boot.host_shutdown_action",
+		"console.syslog",
 		"boot.host_shutdown_timeout",
 		"linux.kernel_modules",
 		"limits.memory.swap",
@@ -899,9 +935,15 @@ func isContainerLowLevelOptionForbidden(key string) bool {
 
 // Return true if a low-level VM option is forbidden.
 func isVMLowLevelOptionForbidden(key string) bool {
+	// Lifecycle hooks run on the host.
+	if strings.HasPrefix(key, "hooks.") {
+		return true
+	}
+
 	return slices.Contains([]string{
 		"boot.host_shutdown_action",
 		"boot.host_shutdown_timeout",
+		"console.syslog",
 		"limits.memory.hugepages",
 		"raw.apparmor",
 		"raw.idmap",
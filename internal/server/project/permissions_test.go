@@ -249,3 +249,23 @@ func TestCheckClusterTargetRestriction_RestrictedFalse(t *testing.T) {
 	err = project.CheckClusterTargetRestriction(authorizer, req, p, "n1")
 	assert.NoError(t, err)
 }
+
+func TestCheckRestrictedFilesPath(t *testing.T) {
+	tests := []struct {
+		allowedPaths []string
+		path         string
+		allowed      bool
+	}{
+		{nil, "/etc/passwd", true},
+		{[]string{"/srv"}, "/srv", true},
+		{[]string{"/srv"}, "/srv/www/index.html", true},
+		{[]string{"/srv"}, "/srv2/file", false},
+		{[]string{"/srv"}, "/etc/passwd", false},
+		{[]string{"/srv", "/var/www/"}, "/var/www/index.html", true},
+		{[]string{"/"}, "/etc/passwd", true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, project.CheckRestrictedFilesPath(tt.allowedPaths, tt.path), "path %q with allowed paths %v", tt.path, tt.allowedPaths)
+	}
+}
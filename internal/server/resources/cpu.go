@@ -534,5 +534,38 @@ func GetCPU() (*api.ResourcesCPU, error) {
 
 	cpu.Architecture = strings.TrimRight(string(uname.Machine[:]), "\x00")
 
+	// Get the virtualization support
+	cpu.Virtualization = getCPUVirtualization(cpu.Sockets)
+
 	return &cpu, nil
 }
+
+// getCPUVirtualization returns the hardware virtualization support based on the CPU flags and KVM parameters.
+func getCPUVirtualization(sockets []api.ResourcesCPUSocket) *api.ResourcesCPUVirtualization {
+	for _, socket := range sockets {
+		for _, core := range socket.Cores {
+			for _, extension := range []string{"vmx", "svm"} {
+				if !slices.Contains(core.Flags, extension) {
+					continue
+				}
+
+				module := "kvm_intel"
+				if extension == "svm" {
+					module = "kvm_amd"
+				}
+
+				virt := &api.ResourcesCPUVirtualization{Extension: extension}
+
+				// The nested parameter is either a boolean (Y/N) or an integer depending on the module.
+				nested, err := os.ReadFile(filepath.Join("/sys/module", module, "parameters", "nested"))
+				if err == nil {
+					virt.Nested = slices.Contains([]string{"Y", "1"}, strings.TrimSpace(string(nested)))
+				}
+
+				return virt
+			}
+		}
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"go.starlark.net/starlark"
 
+	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -16,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
 	"github.com/lxc/incus/v6/internal/server/scriptlet/marshal"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/shared/api"
 	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
@@ -55,6 +57,39 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		return starlark.None, nil
 	}
 
+	// connectMember connects to a remote candidate member.
+	connectMember := func(memberName string) (incus.InstanceServer, error) {
+		var targetMember *db.NodeInfo
+		for i := range candidateMembers {
+			if candidateMembers[i].Name == memberName {
+				targetMember = &candidateMembers[i]
+				break
+			}
+		}
+
+		if targetMember == nil {
+			return nil, fmt.Errorf("Invalid member name: %s", memberName)
+		}
+
+		return cluster.Connect(targetMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	}
+
+	// getMemberResources returns the resources of a candidate member.
+	getMemberResources := func(memberName string) (*api.Resources, error) {
+		// Get the local resource usage.
+		if memberName == s.ServerName {
+			return resources.GetResources()
+		}
+
+		// Get remote member resource usage.
+		client, err := connectMember(memberName)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.GetServerResources()
+	}
+
 	getClusterMemberResourcesFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		var memberName string
 
@@ -63,34 +98,106 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 			return nil, err
 		}
 
-		var res *api.Resources
+		res, err := getMemberResources(memberName)
+		if err != nil {
+			return nil, err
+		}
 
-		// Get the local resource usage.
-		if memberName == s.ServerName {
-			res, err = resources.GetResources()
+		rv, err := marshal.StarlarkMarshal(res)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling cluster member resources for %q failed: %w", memberName, err)
+		}
+
+		return rv, nil
+	}
+
+	getClusterMemberMetricsFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var memberName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "member_name", &memberName)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := getMemberResources(memberName)
+		if err != nil {
+			return nil, err
+		}
+
+		metrics := apiScriptlet.ClusterMemberMetrics{
+			CPUThreads:       res.CPU.Total,
+			LoadAverage1Min:  res.Load.Average1Min,
+			LoadAverage5Min:  res.Load.Average5Min,
+			LoadAverage10Min: res.Load.Average10Min,
+			MemoryUsed:       res.Memory.Used,
+			MemoryTotal:      res.Memory.Total,
+			Processes:        res.Load.Processes,
+		}
+
+		// Add the resources reserved by the instances on the member.
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			metrics.Instances, err = tx.GetInstancesCount(ctx, "", memberName, true)
 			if err != nil {
-				return nil, err
+				return err
 			}
-		} else {
-			// Get remote member resource usage.
-			var targetMember *db.NodeInfo
-			for i := range candidateMembers {
-				if candidateMembers[i].Name == memberName {
-					targetMember = &candidateMembers[i]
-					break
+
+			return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+				config := db.ExpandInstanceConfig(inst.Config, inst.Profiles)
+				devices := db.ExpandInstanceDevices(inst.Devices, inst.Profiles)
+
+				usageCPU, usageMemory, _, err := internalInstance.ResourceUsage(config, devices.CloneNative(), api.InstanceType(inst.Type.String()))
+				if err != nil {
+					return nil
 				}
-			}
 
-			if targetMember == nil {
-				return nil, fmt.Errorf("Invalid member name: %s", memberName)
+				metrics.ReservedCPUCores += uint64(usageCPU)
+				metrics.ReservedMemorySize += uint64(usageMemory)
+
+				return nil
+			}, dbCluster.InstanceFilter{Node: &memberName})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rv, err := marshal.StarlarkMarshal(metrics)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling cluster member metrics for %q failed: %w", memberName, err)
+		}
+
+		return rv, nil
+	}
+
+	getStoragePoolResourcesFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var memberName string
+		var poolName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "member_name", &memberName, "pool_name", &poolName)
+		if err != nil {
+			return nil, err
+		}
+
+		var res *api.ResourcesStoragePool
+
+		if memberName == s.ServerName {
+			// Get the local storage pool usage.
+			pool, err := storagePools.LoadByName(s, poolName)
+			if err != nil {
+				return nil, err
 			}
 
-			client, err := cluster.Connect(targetMember.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+			res, err = pool.GetResources()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Get remote storage pool usage.
+			client, err := connectMember(memberName)
 			if err != nil {
 				return nil, err
 			}
 
-			res, err = client.GetServerResources()
+			res, err = client.UseTarget(memberName).GetStoragePoolResources(poolName)
 			if err != nil {
 				return nil, err
 			}
@@ -98,7 +205,7 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 
 		rv, err := marshal.StarlarkMarshal(res)
 		if err != nil {
-			return nil, fmt.Errorf("Marshalling cluster member resources for %q failed: %w", memberName, err)
+			return nil, fmt.Errorf("Marshalling storage pool resources for %q on %q failed: %w", poolName, memberName, err)
 		}
 
 		return rv, nil
@@ -270,6 +377,44 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 		return rv, nil
 	}
 
+	getInstancesCountByProjectFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var locationName string
+		var includePending bool
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "location??", &locationName, "pending??", &includePending)
+		if err != nil {
+			return nil, err
+		}
+
+		counts := map[string]int{}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			projectNames, err := dbCluster.GetProjectNames(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			for _, projectName := range projectNames {
+				counts[projectName], err = tx.GetInstancesCount(ctx, projectName, locationName, includePending)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rv, err := marshal.StarlarkMarshal(counts)
+		if err != nil {
+			return nil, fmt.Errorf("Marshalling instance counts failed: %w", err)
+		}
+
+		return rv, nil
+	}
+
 	getClusterMembersFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 		var group string
 		var allMembers []db.NodeInfo
@@ -451,17 +596,20 @@ func InstancePlacementRun(ctx context.Context, l logger.Logger, s *state.State,
 	// Remember to match the entries in scriptletLoad.InstancePlacementCompile() with this list so Starlark can
 	// perform compile time validation of functions used.
 	env := starlark.StringDict{
-		"log_info":                     starlark.NewBuiltin("log_info", logFunc),
-		"log_warn":                     starlark.NewBuiltin("log_warn", logFunc),
-		"log_error":                    starlark.NewBuiltin("log_error", logFunc),
-		"set_target":                   starlark.NewBuiltin("set_target", setTargetFunc),
-		"get_cluster_member_resources": starlark.NewBuiltin("get_cluster_member_resources", getClusterMemberResourcesFunc),
-		"get_cluster_member_state":     starlark.NewBuiltin("get_cluster_member_state", getClusterMemberStateFunc),
-		"get_instance_resources":       starlark.NewBuiltin("get_instance_resources", getInstanceResourcesFunc),
-		"get_instances":                starlark.NewBuiltin("get_instances", getInstancesFunc),
-		"get_instances_count":          starlark.NewBuiltin("get_instances_count", getInstancesCountFunc),
-		"get_cluster_members":          starlark.NewBuiltin("get_cluster_members", getClusterMembersFunc),
-		"get_project":                  starlark.NewBuiltin("get_project", getProjectFunc),
+		"log_info":                       starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":                       starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":                      starlark.NewBuiltin("log_error", logFunc),
+		"set_target":                     starlark.NewBuiltin("set_target", setTargetFunc),
+		"get_cluster_member_resources":   starlark.NewBuiltin("get_cluster_member_resources", getClusterMemberResourcesFunc),
+		"get_cluster_member_metrics":     starlark.NewBuiltin("get_cluster_member_metrics", getClusterMemberMetricsFunc),
+		"get_cluster_member_state":       starlark.NewBuiltin("get_cluster_member_state", getClusterMemberStateFunc),
+		"get_instance_resources":         starlark.NewBuiltin("get_instance_resources", getInstanceResourcesFunc),
+		"get_instances":                  starlark.NewBuiltin("get_instances", getInstancesFunc),
+		"get_instances_count":            starlark.NewBuiltin("get_instances_count", getInstancesCountFunc),
+		"get_instances_count_by_project": starlark.NewBuiltin("get_instances_count_by_project", getInstancesCountByProjectFunc),
+		"get_storage_pool_resources":     starlark.NewBuiltin("get_storage_pool_resources", getStoragePoolResourcesFunc),
+		"get_cluster_members":            starlark.NewBuiltin("get_cluster_members", getClusterMembersFunc),
+		"get_project":                    starlark.NewBuiltin("get_project", getProjectFunc),
 	}
 
 	prog, thread, err := scriptletLoad.InstancePlacementProgram()
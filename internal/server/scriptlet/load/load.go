@@ -28,10 +28,13 @@ func InstancePlacementCompile(name string, src string) (*starlark.Program, error
 		"log_error",
 		"set_target",
 		"get_cluster_member_resources",
+		"get_cluster_member_metrics",
 		"get_cluster_member_state",
 		"get_instance_resources",
 		"get_instances",
 		"get_instances_count",
+		"get_instances_count_by_project",
+		"get_storage_pool_resources",
 		"get_cluster_members",
 		"get_project",
 	})
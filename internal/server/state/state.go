@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/bgp"
 	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
@@ -91,6 +92,9 @@ type State struct {
 	// Authorizer.
 	Authorizer auth.Authorizer
 
+	// Authentication audit log.
+	Audit *audit.Log
+
 	// OVN.
 	OVN func() (*ovn.NB, *ovn.SB, error)
 
@@ -76,6 +76,9 @@ var instanceDiskVolumeEffectiveFields = []string{
 	"size.state",
 }
 
+// trashVolumePrefix is the prefix of the storage names of the volumes kept in the trash.
+const trashVolumePrefix = "_trash_"
+
 type backend struct {
 	driver drivers.Driver
 	id     int64
@@ -272,6 +275,28 @@ func (b *backend) GetResources() (*api.ResourcesStoragePool, error) {
 	return b.driver.GetResources()
 }
 
+// GetHealth returns the health of the pool.
+func (b *backend) GetHealth() (*api.StoragePoolStateHealth, error) {
+	if b.Status() == api.StoragePoolStatusPending {
+		return nil, errors.New("The pool is in pending state")
+	}
+
+	return b.driver.GetHealth()
+}
+
+// Scrub starts a scrub of the pool.
+func (b *backend) Scrub() error {
+	l := b.logger.AddContext(nil)
+	l.Debug("Scrub started")
+	defer l.Debug("Scrub finished")
+
+	if b.Status() == api.StoragePoolStatusPending {
+		return errors.New("The pool is in pending state")
+	}
+
+	return b.driver.Scrub()
+}
+
 // IsUsed returns whether the storage pool is used by any volumes or profiles (excluding image volumes).
 func (b *backend) IsUsed() (bool, error) {
 	usedBy, err := UsedBy(context.TODO(), b.state, b, true, true, db.StoragePoolVolumeTypeNameImage)
@@ -1398,8 +1423,11 @@ func (b *backend) RefreshCustomVolume(projectName string, srcProjectName string,
 		l.Debug("RefreshCustomVolume cross-pool mode detected")
 
 		// Negotiate the migration type to use.
+		// Flag the offer as a refresh so that optimized types are only picked when they support incremental streams.
 		offeredTypes := srcPool.MigrationTypes(contentType, true, snapshots, false, true)
 		offerHeader := localMigration.TypesToHeader(offeredTypes...)
+		refresh := true
+		offerHeader.Refresh = &refresh
 		migrationTypes, err := localMigration.MatchTypes(offerHeader, FallbackMigrationType(contentType), b.MigrationTypes(contentType, true, snapshots, false, true))
 		if err != nil {
 			return fmt.Errorf("Failed to negotiate copy migration type: %w", err)
@@ -1454,8 +1482,10 @@ func (b *backend) RefreshCustomVolume(projectName string, srcProjectName string,
 				Snapshots:          snapshotNames,
 				MigrationType:      migrationTypes[0],
 				TrackProgress:      true, // Do use a progress tracker on sender.
+				Refresh:            true, // Indicate to sender to use incremental streams.
 				ContentType:        string(contentType),
 				Info:               &localMigration.Info{Config: srcConfig},
+				VolumeOnly:         !snapshots,
 				StorageMove:        true,
 			}, op)
 			if err != nil {
@@ -2795,6 +2825,42 @@ func (b *backend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
 	return &val, nil
 }
 
+// FenceInstance cuts off the given cluster member from the instance's root volume.
+// This is used before recovering the instance from a cluster member which went offline.
+func (b *backend) FenceInstance(inst instance.Instance, memberName string) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "member": memberName})
+	l.Debug("FenceInstance started")
+	defer l.Debug("FenceInstance finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	contentType := InstanceContentType(inst)
+
+	// There's no need to pass config as it's not needed when fencing the volume.
+	volStorageName := project.Instance(inst.Project().Name, inst.Name())
+	vol := b.GetVolume(volType, contentType, volStorageName, nil)
+
+	// Get the pool configuration specific to the member being fenced.
+	var memberConfig map[string]string
+	err = b.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		memberConfig, err = tx.GetStoragePoolMemberConfig(ctx, b.ID(), memberName)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading storage pool configuration of member %q: %w", memberName, err)
+	}
+
+	return b.driver.FenceVolume(vol, memberConfig)
+}
+
 // SetInstanceQuota sets the quota on the instance's root volume.
 // Returns ErrInUse if the instance is running and the storage driver doesn't support online resizing.
 func (b *backend) SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error {
@@ -3931,6 +3997,12 @@ func (b *backend) CreateBucket(projectName string, bucket api.StorageBucketsPost
 		}
 
 		reverter.Add(func() { _ = s3Client.RemoveBucket(ctx, bucket.Name) })
+
+		// Apply initial versioning and lifecycle settings.
+		err = s3.SetBucketConfig(ctx, s3Client, bucket.Name, bucket.Config, bucket.Config)
+		if err != nil {
+			return err
+		}
 	} else {
 		// Handle per-driver implementation for remote storage drivers.
 		err = b.driver.CreateBucket(bucketVol, op)
@@ -4026,6 +4098,27 @@ func (b *backend) UpdateBucket(projectName string, bucketName string, bucket api
 			if err != nil {
 				return err
 			}
+
+			// Apply versioning and lifecycle changes through a restarted MinIO process.
+			if s3.BucketConfigChanged(changedConfig) {
+				minioProc, err = b.ActivateBucket(projectName, bucketName, op)
+				if err != nil {
+					return err
+				}
+
+				s3Client, err := minioProc.S3Client()
+				if err != nil {
+					return err
+				}
+
+				ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+				defer ctxCancel()
+
+				err = s3.SetBucketConfig(ctx, s3Client, curBucket.Name, bucket.Config, changedConfig)
+				if err != nil {
+					return err
+				}
+			}
 		} else {
 			// Handle per-driver implementation for remote storage drivers.
 			err = b.driver.UpdateBucket(curBucketVol, changedConfig)
@@ -5476,7 +5569,7 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 		}
 
 		sharedVolume, ok := changedConfig["security.shared"]
-		if ok && util.IsFalseOrEmpty(sharedVolume) {
+		if ok && util.IsFalseOrEmpty(sharedVolume) && contentType == drivers.ContentTypeBlock {
 			var usedByProfileDevices []api.Profile
 
 			err = VolumeUsedByProfileDevices(b.state, b.name, projectName, &curVol.StorageVolume, func(profileID int64, profile api.Profile, project api.Project, usedByDevices []string) error {
@@ -5508,6 +5601,35 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 			}
 		}
 
+		// Switching to the read-only shared mode requires all existing attachments to be read-only.
+		if ok && sharedVolume == "readonly" {
+			err = VolumeUsedByProfileDevices(b.state, b.name, projectName, &curVol.StorageVolume, func(profileID int64, profile api.Profile, project api.Project, usedByDevices []string) error {
+				for _, devName := range usedByDevices {
+					if util.IsFalseOrEmpty(profile.Devices[devName]["readonly"]) {
+						return fmt.Errorf("Cannot share custom storage volume read-only while attached read-write to profile %q", profile.Name)
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, false, func(inst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+				for _, devName := range usedByDevices {
+					if util.IsFalseOrEmpty(inst.Devices[devName]["readonly"]) {
+						return fmt.Errorf("Cannot share custom storage volume read-only while attached read-write to instance %q", inst.Name)
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
 		curVol := b.GetVolume(drivers.VolumeTypeCustom, contentType, volStorageName, curVol.Config)
 		if !userOnly {
 			err = b.driver.UpdateVolume(curVol, changedConfig)
@@ -5607,6 +5729,61 @@ func (b *backend) UpdateCustomVolume(projectName string, volName string, newDesc
 		}
 	}
 
+	// Apply I/O limit changes to the running instances using the volume.
+	_, changedReadLimit := changedConfig["limits.read"]
+	_, changedWriteLimit := changedConfig["limits.write"]
+	_, changedIOPSLimit := changedConfig["limits.max.iops"]
+	if changedReadLimit || changedWriteLimit || changedIOPSLimit {
+		type instDevice struct {
+			args    db.InstanceArgs
+			devices []string
+		}
+
+		instDevices := []instDevice{}
+		err = VolumeUsedByInstanceDevices(b.state, b.name, projectName, &curVol.StorageVolume, true, func(dbInst db.InstanceArgs, project api.Project, usedByDevices []string) error {
+			instDevices = append(instDevices, instDevice{args: dbInst, devices: usedByDevices})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range instDevices {
+			c, err := ConnectIfInstanceIsRemote(b.state, entry.args.Project, entry.args.Name, nil)
+			if err != nil {
+				return err
+			}
+
+			if c != nil {
+				// Have the member running the instance reload its devices.
+				uri := fmt.Sprintf("/internal/instances/%d/onreload?devices=%s", entry.args.ID, url.QueryEscape(strings.Join(entry.devices, ",")))
+				_, _, err := c.RawQuery("GET", uri, nil, "")
+				if err != nil {
+					return fmt.Errorf("Failed applying I/O limits to instance %q: %w", entry.args.Name, err)
+				}
+
+				continue
+			}
+
+			// Update the local instance.
+			inst, err := instance.LoadByProjectAndName(b.state, entry.args.Project, entry.args.Name)
+			if err != nil {
+				return err
+			}
+
+			if !inst.IsRunning() {
+				continue
+			}
+
+			for _, devName := range entry.devices {
+				err = inst.ReloadDevice(devName)
+				if err != nil {
+					return fmt.Errorf("Failed applying I/O limits to device %q of instance %q: %w", devName, inst.Name(), err)
+				}
+			}
+		}
+	}
+
 	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeUpdated.Event(newVol, string(newVol.Type()), projectName, op, nil))
 
 	return nil
@@ -5815,6 +5992,96 @@ func (b *backend) GetCustomVolumeUsage(projectName, volName string) (*VolumeUsag
 	return &val, nil
 }
 
+// GetCustomVolumeIOStats returns the I/O counters of the custom volume's block device.
+// Returns nil if the volume isn't currently active on this server.
+func (b *backend) GetCustomVolumeIOStats(projectName string, volName string) (*drivers.VolumeIOStats, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), volStorageName, volume.Config)
+
+	return b.driver.GetVolumeIOStats(vol)
+}
+
+// mirrorVolume returns the instance or custom volume to apply mirroring actions to.
+func (b *backend) mirrorVolume(projectName string, volName string, volType drivers.VolumeType) (drivers.Volume, error) {
+	volume, err := VolumeDBGet(b, projectName, volName, volType)
+	if err != nil {
+		return drivers.Volume{}, err
+	}
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+	if volType != drivers.VolumeTypeCustom {
+		volStorageName = project.Instance(projectName, volName)
+	}
+
+	return b.GetVolume(volType, drivers.ContentType(volume.ContentType), volStorageName, volume.Config), nil
+}
+
+// GetVolumeMirror returns the mirroring state of an instance or custom volume.
+func (b *backend) GetVolumeMirror(projectName string, volName string, volType drivers.VolumeType) (*api.StorageVolumeStateMirror, error) {
+	err := b.isStatusReady()
+	if err != nil {
+		return nil, err
+	}
+
+	vol, err := b.mirrorVolume(projectName, volName, volType)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.driver.GetVolumeMirror(vol)
+}
+
+// PromoteVolume promotes a mirrored instance or custom volume to primary.
+func (b *backend) PromoteVolume(projectName string, volName string, volType drivers.VolumeType, force bool, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "volType": volType, "force": force})
+	l.Debug("PromoteVolume started")
+	defer l.Debug("PromoteVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	vol, err := b.mirrorVolume(projectName, volName, volType)
+	if err != nil {
+		return err
+	}
+
+	return b.driver.PromoteVolume(vol, force)
+}
+
+// DemoteVolume demotes a mirrored instance or custom volume to non-primary.
+func (b *backend) DemoteVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "volType": volType})
+	l.Debug("DemoteVolume started")
+	defer l.Debug("DemoteVolume finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	vol, err := b.mirrorVolume(projectName, volName, volType)
+	if err != nil {
+		return err
+	}
+
+	return b.driver.DemoteVolume(vol)
+}
+
 // MountCustomVolume mounts a custom volume.
 func (b *backend) MountCustomVolume(projectName, volName string, op *operations.Operation) (*MountInfo, error) {
 	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName})
@@ -6579,6 +6846,11 @@ func (b *backend) ListUnknownVolumes(op *operations.Operation) (map[string][]*ba
 	projectVols := make(map[string][]*backupConfig.Config)
 
 	for _, poolVol := range poolVols {
+		// Skip the volumes kept in the trash as they belong to deleted instances and volumes.
+		if strings.HasPrefix(poolVol.Name(), trashVolumePrefix) {
+			continue
+		}
+
 		volType := poolVol.Type()
 
 		// If the storage driver has returned a filesystem volume for a VM, this is a break of protocol.
@@ -7227,6 +7499,116 @@ func (b *backend) CreateCustomVolumeFromISO(projectName string, volName string,
 	return nil
 }
 
+// CreateCustomVolumeFromDiskImage creates a custom block volume from a raw, qcow2 or vmdk disk image, converting it
+// to a raw disk in the process.
+func (b *backend) CreateCustomVolumeFromDiskImage(projectName string, volName string, imgPath string, format string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volume": volName, "format": format})
+	l.Debug("CreateCustomVolumeFromDiskImage started")
+	defer l.Debug("CreateCustomVolumeFromDiskImage finished")
+
+	err := b.isStatusReady()
+	if err != nil {
+		return err
+	}
+
+	imgSize, err := DiskImageSize(b.state.OS, imgPath, format)
+	if err != nil {
+		return err
+	}
+
+	// Check whether we are allowed to create volumes.
+	req := api.StorageVolumesPost{
+		Name: volName,
+		StorageVolumePut: api.StorageVolumePut{
+			Config: map[string]string{
+				"size": fmt.Sprintf("%d", imgSize),
+			},
+		},
+		ContentType: string(drivers.ContentTypeBlock),
+	}
+
+	err = b.state.DB.Cluster.Transaction(b.state.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowVolumeCreation(tx, projectName, b.name, req)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed checking volume creation allowed: %w", err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Get the volume name on storage.
+	volStorageName := project.StorageVolume(projectName, volName)
+
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentTypeBlock, volStorageName, req.Config)
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if volExists {
+		return errors.New("Cannot create volume, already exists on target storage")
+	}
+
+	// Validate config and create database entry for new storage volume.
+	err = VolumeDBCreate(b, projectName, volName, "", vol.Type(), false, vol.Config(), time.Now(), time.Time{}, vol.ContentType(), true, true)
+	if err != nil {
+		return fmt.Errorf("Failed creating database entry for custom volume: %w", err)
+	}
+
+	reverter.Add(func() { _ = VolumeDBDelete(b, projectName, volName, vol.Type()) })
+
+	volFiller := drivers.VolumeFiller{
+		Fill: func(vol drivers.Volume, rootBlockPath string, allowUnsafeResize bool) (int64, error) {
+			var tracker *ioprogress.ProgressTracker
+			if op != nil {
+				metadata := make(map[string]any)
+				tracker = &ioprogress.ProgressTracker{
+					Handler: func(percent, speed int64) {
+						operations.SetProgressMetadata(metadata, "create_volume_from_disk_image_convert", "Converting disk image", percent, 0, speed)
+						_ = op.UpdateMetadata(metadata)
+					},
+				}
+			}
+
+			err := DiskImageConvert(b.state.OS, imgPath, format, rootBlockPath, tracker)
+			if err != nil {
+				return -1, err
+			}
+
+			return imgSize, nil
+		},
+	}
+
+	// Convert the disk image into the new storage volume.
+	err = b.driver.CreateVolume(vol, &volFiller, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating volume: %w", err)
+	}
+
+	eventCtx := logger.Ctx{"type": vol.Type()}
+	if !b.Driver().Info().Remote {
+		eventCtx["location"] = b.state.ServerName
+	}
+
+	var location string
+	if b.state.ServerClustered && !b.Driver().Info().Remote {
+		location = b.state.ServerName
+	}
+
+	// Record new volume with authorizer.
+	err = b.state.Authorizer.AddStoragePoolVolume(b.state.ShutdownCtx, projectName, b.Name(), vol.Type().Singular(), volName, location)
+	if err != nil {
+		logger.Error("Failed to add storage volume to authorizer", logger.Ctx{"name": volName, "type": vol.Type(), "pool": b.Name(), "project": projectName, "error": err})
+	}
+
+	b.state.Events.SendLifecycle(projectName, lifecycle.StorageVolumeCreated.Event(vol, string(vol.Type()), projectName, op, eventCtx))
+
+	reverter.Success()
+	return nil
+}
+
 func (b *backend) CreateCustomVolumeFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
 	l := b.logger.AddContext(logger.Ctx{"project": srcBackup.Project, "volume": srcBackup.Name, "snapshots": srcBackup.Snapshots, "optimizedStorage": *srcBackup.OptimizedStorage})
 	l.Debug("CreateCustomVolumeFromBackup started")
@@ -7515,3 +7897,167 @@ func (b *backend) getFirstAdminStorageBucketPoolKey(projectName string, bucketNa
 
 	return bucketKey, nil
 }
+
+// trashVolumeName returns the storage name of the volume kept in the trash for the given entry.
+// As project names can't contain underscores, this can't clash with the name of any other volume.
+func trashVolumeName(entryUUID string) string {
+	return trashVolumePrefix + entryUUID
+}
+
+// trashVolume returns the volume kept in the trash for the given backup config along with the storage name
+// it gets restored as in the given project.
+func (b *backend) trashVolume(projectName string, poolVol *backupConfig.Config, entryUUID string) (drivers.Volume, string, error) {
+	if poolVol.Container != nil {
+		instType, err := instancetype.New(poolVol.Container.Type)
+		if err != nil {
+			return drivers.Volume{}, "", err
+		}
+
+		volType, err := InstanceTypeToVolumeType(instType)
+		if err != nil {
+			return drivers.Volume{}, "", err
+		}
+
+		contentType := drivers.ContentTypeFS
+		if instType == instancetype.VM {
+			contentType = drivers.ContentTypeBlock
+		}
+
+		// There's no need to pass config as it's not needed when renaming or deleting a volume.
+		vol := b.GetVolume(volType, contentType, trashVolumeName(entryUUID), nil)
+
+		return vol, project.Instance(projectName, poolVol.Container.Name), nil
+	}
+
+	if poolVol.Volume != nil {
+		vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(poolVol.Volume.ContentType), trashVolumeName(entryUUID), nil)
+
+		return vol, project.StorageVolume(projectName, poolVol.Volume.Name), nil
+	}
+
+	return drivers.Volume{}, "", errors.New("Invalid pool volume config supplied")
+}
+
+// TrashInstance renames the instance volume and its snapshots to a hidden name on the storage pool.
+// This keeps them around when the instance gets deleted so they can later be restored with RestoreTrashVolume.
+func (b *backend) TrashInstance(inst instance.Instance, entryUUID string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "entry": entryUUID})
+	l.Debug("TrashInstance started")
+	defer l.Debug("TrashInstance finished")
+
+	if inst.IsSnapshot() {
+		return errors.New("Instance cannot be a snapshot")
+	}
+
+	volType, err := InstanceTypeToVolumeType(inst.Type())
+	if err != nil {
+		return err
+	}
+
+	// There's no need to pass config as it's not needed when renaming a volume.
+	vol := b.GetVolume(volType, InstanceContentType(inst), project.Instance(inst.Project().Name, inst.Name()), nil)
+
+	return b.driver.RenameVolume(vol, trashVolumeName(entryUUID), op)
+}
+
+// TrashCustomVolume renames the custom volume and its snapshots to a hidden name on the storage pool.
+// This keeps them around when the volume gets deleted so they can later be restored with RestoreTrashVolume.
+func (b *backend) TrashCustomVolume(projectName string, volName string, entryUUID string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "volName": volName, "entry": entryUUID})
+	l.Debug("TrashCustomVolume started")
+	defer l.Debug("TrashCustomVolume finished")
+
+	if internalInstance.IsSnapshot(volName) {
+		return errors.New("Volume name cannot be a snapshot")
+	}
+
+	volume, err := VolumeDBGet(b, projectName, volName, drivers.VolumeTypeCustom)
+	if err != nil {
+		return err
+	}
+
+	vol := b.GetVolume(drivers.VolumeTypeCustom, drivers.ContentType(volume.ContentType), project.StorageVolume(projectName, volName), volume.Config)
+
+	return b.driver.RenameVolume(vol, trashVolumeName(entryUUID), op)
+}
+
+// RestoreTrashVolume renames the volume kept in the trash back to the name of the instance or custom volume
+// described by the backup config in the given project. The database records must then be recreated with
+// ImportInstance or ImportCustomVolume. Returns a revert hook moving the volume back to the trash.
+func (b *backend) RestoreTrashVolume(projectName string, poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) (revert.Hook, error) {
+	l := b.logger.AddContext(logger.Ctx{"project": projectName, "entry": entryUUID})
+	l.Debug("RestoreTrashVolume started")
+	defer l.Debug("RestoreTrashVolume finished")
+
+	vol, volStorageName, err := b.trashVolume(projectName, poolVol, entryUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the name isn't in use by a volume created since the deletion.
+	existingVol := b.GetVolume(vol.Type(), vol.ContentType(), volStorageName, nil)
+
+	volExists, err := b.driver.HasVolume(existingVol)
+	if err != nil {
+		return nil, err
+	}
+
+	if volExists {
+		return nil, api.StatusErrorf(http.StatusConflict, "A volume by that name already exists on the storage pool")
+	}
+
+	err = b.driver.RenameVolume(vol, volStorageName, op)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := func() { _ = b.driver.RenameVolume(existingVol, vol.Name(), op) }
+
+	return cleanup, nil
+}
+
+// DeleteTrashVolume deletes the volume kept in the trash for the backup config along with its snapshots.
+func (b *backend) DeleteTrashVolume(poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) error {
+	l := b.logger.AddContext(logger.Ctx{"entry": entryUUID})
+	l.Debug("DeleteTrashVolume started")
+	defer l.Debug("DeleteTrashVolume finished")
+
+	vol, _, err := b.trashVolume(api.ProjectDefaultName, poolVol, entryUUID)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := b.driver.HasVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if !volExists {
+		return nil
+	}
+
+	// Delete the snapshots first as the volume can't be deleted while they exist.
+	snapshots, err := b.driver.VolumeSnapshots(vol, op)
+	if err != nil {
+		return err
+	}
+
+	for _, snapName := range snapshots {
+		snapVol, err := vol.NewSnapshot(snapName)
+		if err != nil {
+			return err
+		}
+
+		err = b.driver.DeleteVolumeSnapshot(snapVol, op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting snapshot %q: %w", snapName, err)
+		}
+	}
+
+	err = b.driver.DeleteVolume(vol, op)
+	if err != nil {
+		return fmt.Errorf("Error deleting storage volume: %w", err)
+	}
+
+	return nil
+}
@@ -77,6 +77,14 @@ func (b *mockBackend) GetResources() (*api.ResourcesStoragePool, error) {
 	return nil, nil
 }
 
+func (b *mockBackend) GetHealth() (*api.StoragePoolStateHealth, error) {
+	return nil, nil
+}
+
+func (b *mockBackend) Scrub() error {
+	return nil
+}
+
 func (b *mockBackend) IsUsed() (bool, error) {
 	return false, nil
 }
@@ -190,6 +198,10 @@ func (b *mockBackend) GetInstanceUsage(inst instance.Instance) (*VolumeUsage, er
 	return nil, nil
 }
 
+func (b *mockBackend) FenceInstance(inst instance.Instance, memberName string) error {
+	return nil
+}
+
 func (b *mockBackend) SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error {
 	return nil
 }
@@ -319,6 +331,25 @@ func (b *mockBackend) GetCustomVolumeUsage(projectName string, volName string) (
 	return nil, nil
 }
 
+func (b *mockBackend) GetCustomVolumeIOStats(projectName string, volName string) (*drivers.VolumeIOStats, error) {
+	return nil, nil
+}
+
+// GetVolumeMirror ...
+func (b *mockBackend) GetVolumeMirror(projectName string, volName string, volType drivers.VolumeType) (*api.StorageVolumeStateMirror, error) {
+	return nil, nil
+}
+
+// PromoteVolume ...
+func (b *mockBackend) PromoteVolume(projectName string, volName string, volType drivers.VolumeType, force bool, op *operations.Operation) error {
+	return nil
+}
+
+// DemoteVolume ...
+func (b *mockBackend) DemoteVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error {
+	return nil
+}
+
 func (b *mockBackend) MountCustomVolume(projectName string, volName string, op *operations.Operation) (*MountInfo, error) {
 	return nil, nil
 }
@@ -363,6 +394,10 @@ func (b *mockBackend) CreateCustomVolumeFromISO(projectName string, volName stri
 	return nil
 }
 
+func (b *mockBackend) CreateCustomVolumeFromDiskImage(projectName string, volName string, imgPath string, format string, op *operations.Operation) error {
+	return nil
+}
+
 // GenerateBucketBackupConfig returns the backup config entry for this bucket.
 func (b *mockBackend) GenerateBucketBackupConfig(projectName string, bucketName string, op *operations.Operation) (*backupConfig.Config, error) {
 	return nil, nil
@@ -377,3 +412,23 @@ func (b *mockBackend) BackupBucket(projectName string, bucketName string, tarWri
 func (b *mockBackend) CreateBucketFromBackup(srcBackup backup.Info, srcData io.ReadSeeker, op *operations.Operation) error {
 	return nil
 }
+
+// TrashInstance moves the instance volume to the trash.
+func (b *mockBackend) TrashInstance(inst instance.Instance, entryUUID string, op *operations.Operation) error {
+	return nil
+}
+
+// TrashCustomVolume moves the custom volume to the trash.
+func (b *mockBackend) TrashCustomVolume(projectName string, volName string, entryUUID string, op *operations.Operation) error {
+	return nil
+}
+
+// RestoreTrashVolume restores the volume kept in the trash.
+func (b *mockBackend) RestoreTrashVolume(projectName string, poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) (revert.Hook, error) {
+	return nil, nil
+}
+
+// DeleteTrashVolume deletes the volume kept in the trash.
+func (b *mockBackend) DeleteTrashVolume(poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) error {
+	return nil
+}
@@ -363,16 +363,7 @@ func (d *btrfs) Update(changedConfig map[string]string) error {
 		}
 
 		// Resize loop file
-		f, err := os.OpenFile(loopPath, os.O_RDWR, 0o600)
-		if err != nil {
-			return err
-		}
-
-		defer func() { _ = f.Close() }()
-
-		sizeBytes, _ := units.ParseByteSizeString(size)
-
-		err = f.Truncate(sizeBytes)
+		err := growLoopFile(loopPath, size)
 		if err != nil {
 			return err
 		}
@@ -487,6 +478,44 @@ func (d *btrfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
 }
 
+// GetHealth returns the health of the btrfs filesystem from its device statistics.
+func (d *btrfs) GetHealth() (*api.StoragePoolStateHealth, error) {
+	out, err := subprocess.RunCommand("btrfs", "device", "stats", GetPoolMountPath(d.name))
+	if err != nil {
+		return nil, err
+	}
+
+	errCount, counters := btrfsParseDeviceStats(out)
+
+	health := &api.StoragePoolStateHealth{
+		Status:      "healthy",
+		Description: strings.Join(counters, ", "),
+		Errors:      errCount,
+		ScrubStatus: "none",
+	}
+
+	if errCount > 0 {
+		health.Status = "degraded"
+	}
+
+	out, err = subprocess.RunCommand("btrfs", "scrub", "status", GetPoolMountPath(d.name))
+	if err == nil {
+		health.ScrubStatus = btrfsParseScrubStatus(out)
+	}
+
+	return health, nil
+}
+
+// Scrub starts a scrub of the btrfs filesystem.
+func (d *btrfs) Scrub() error {
+	_, err := subprocess.RunCommand("btrfs", "scrub", "start", GetPoolMountPath(d.name))
+	if err != nil {
+		return fmt.Errorf("Failed to scrub btrfs filesystem: %w", err)
+	}
+
+	return nil
+}
+
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *btrfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
@@ -240,7 +240,7 @@ func (d *btrfs) deleteSubvolume(rootPath string, recursion bool) error {
 	// Single subvolume deletion.
 	destroy := func(path string) error {
 		// Attempt (but don't fail on) to delete any qgroup on the subvolume.
-		qgroup, _, err := d.getQGroup(path)
+		qgroup, _, _, err := d.getQGroup(path)
 		if err == nil {
 			_, _ = subprocess.RunCommand("btrfs", "qgroup", "destroy", qgroup, path)
 		}
@@ -306,16 +306,25 @@ func (d *btrfs) deleteSubvolume(rootPath string, recursion bool) error {
 	return nil
 }
 
-func (d *btrfs) getQGroup(path string) (string, int64, error) {
+// getQGroup returns the quota group of the subvolume along with its referenced and exclusive usage.
+func (d *btrfs) getQGroup(path string) (string, int64, int64, error) {
 	// Try to get the qgroup details.
 	output, err := subprocess.RunCommand("btrfs", "qgroup", "show", "-e", "-f", "--raw", path)
 	if err != nil {
-		return "", -1, errBtrfsNoQuota
+		return "", -1, -1, errBtrfsNoQuota
 	}
 
-	// Parse to extract the qgroup identifier.
-	var qgroup string
-	usage := int64(-1)
+	qgroup, referenced, exclusive := btrfsParseQGroup(output)
+	if qgroup == "" {
+		return "", -1, -1, errBtrfsNoQGroup
+	}
+
+	return qgroup, referenced, exclusive, nil
+}
+
+// btrfsParseQGroup extracts the quota group identifier along with its referenced and exclusive usage
+// from the raw output of "btrfs qgroup show". The usage is -1 when it can't be parsed.
+func btrfsParseQGroup(output string) (string, int64, int64) {
 	for _, line := range strings.Split(output, "\n") {
 		// Use case-insensitive field title match because BTRFS tooling changed casing between versions.
 		if line == "" || strings.HasPrefix(strings.ToLower(line), "qgroupid") || strings.HasPrefix(line, "-") {
@@ -329,20 +338,31 @@ func (d *btrfs) getQGroup(path string) (string, int64, error) {
 			continue
 		}
 
-		qgroup = fields[0]
-		val, err := strconv.ParseInt(fields[2], 10, 64)
-		if err == nil {
-			usage = val
+		referenced, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			referenced = -1
+		}
+
+		exclusive, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			exclusive = -1
 		}
 
-		break
+		return fields[0], referenced, exclusive
 	}
 
-	if qgroup == "" {
-		return "", -1, errBtrfsNoQGroup
+	return "", -1, -1
+}
+
+// qgroupRescanRunning returns whether a quota rescan is in progress on the filesystem, in which case
+// the quota group usage isn't accurate yet.
+func (d *btrfs) qgroupRescanRunning(path string) bool {
+	output, err := subprocess.RunCommand("btrfs", "quota", "rescan", "-s", path)
+	if err != nil {
+		return false
 	}
 
-	return qgroup, usage, nil
+	return strings.Contains(output, "rescan operation running")
 }
 
 func (d *btrfs) sendSubvolume(path string, parent string, conn io.ReadWriteCloser, tracker *ioprogress.ProgressTracker) error {
@@ -659,3 +679,61 @@ func (d *btrfs) receiveSubVolume(r io.Reader, receivePath string, tracker *iopro
 
 	return subVolPath, nil
 }
+
+// btrfsParseDeviceStats extracts the total number of errors along with the non-zero counters
+// from the raw output of "btrfs device stats".
+func btrfsParseDeviceStats(output string) (uint64, []string) {
+	var errCount uint64
+	counters := []string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		count, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil || count == 0 {
+			continue
+		}
+
+		errCount += count
+		counters = append(counters, fmt.Sprintf("%s %d", fields[0], count))
+	}
+
+	return errCount, counters
+}
+
+// btrfsParseScrubStatus extracts the scrub status from the raw output of "btrfs scrub status".
+func btrfsParseScrubStatus(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		// Current tooling reports the status on its own line.
+		status, ok := strings.CutPrefix(line, "Status:")
+		if ok {
+			switch strings.TrimSpace(status) {
+			case "running":
+				return "running"
+			case "finished":
+				return "finished"
+			case "aborted", "interrupted":
+				return "canceled"
+			}
+		}
+
+		// Older tooling reports it as part of the summary line.
+		if strings.HasPrefix(line, "scrub started at") {
+			switch {
+			case strings.Contains(line, "running for"):
+				return "running"
+			case strings.Contains(line, "and finished after"):
+				return "finished"
+			case strings.Contains(line, "and was aborted after"):
+				return "canceled"
+			}
+		}
+	}
+
+	return "none"
+}
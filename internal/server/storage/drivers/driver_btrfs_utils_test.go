@@ -0,0 +1,104 @@
+package drivers
+
+import (
+	"testing"
+)
+
+func Test_btrfsParseQGroup(t *testing.T) {
+	tests := []struct {
+		name           string
+		output         string
+		wantQGroup     string
+		wantReferenced int64
+		wantExclusive  int64
+	}{
+		{
+			"Current tooling",
+			"Qgroupid    Referenced    Exclusive  Max exclusive   Path \n--------    ----------    ---------  -------------   ---- \n0/257        1073758208        16384           none   containers/c1\n",
+			"0/257",
+			1073758208,
+			16384,
+		},
+		{
+			"Older tooling",
+			"qgroupid         rfer         excl     max_excl \n--------         ----         ----     -------- \n0/258           49152        49152         none \n",
+			"0/258",
+			49152,
+			49152,
+		},
+		{
+			"Unparsable usage",
+			"qgroupid         rfer         excl\n0/259               -            -\n",
+			"0/259",
+			-1,
+			-1,
+		},
+		{
+			"No quota group",
+			"qgroupid         rfer         excl     max_excl \n--------         ----         ----     -------- \n",
+			"",
+			-1,
+			-1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qgroup, referenced, exclusive := btrfsParseQGroup(tt.output)
+			if qgroup != tt.wantQGroup || referenced != tt.wantReferenced || exclusive != tt.wantExclusive {
+				t.Errorf("btrfsParseQGroup() = %q, %d, %d, want %q, %d, %d", qgroup, referenced, exclusive, tt.wantQGroup, tt.wantReferenced, tt.wantExclusive)
+			}
+		})
+	}
+}
+
+func Test_btrfsParseDeviceStats(t *testing.T) {
+	output := "[/dev/loop0].write_io_errs    0\n[/dev/loop0].read_io_errs     2\n[/dev/loop0].flush_io_errs    0\n[/dev/loop0].corruption_errs  1\n[/dev/loop0].generation_errs  0\n"
+
+	errCount, counters := btrfsParseDeviceStats(output)
+	if errCount != 3 {
+		t.Errorf("btrfsParseDeviceStats() errors = %d, want 3", errCount)
+	}
+
+	if len(counters) != 2 || counters[0] != "[/dev/loop0].read_io_errs 2" || counters[1] != "[/dev/loop0].corruption_errs 1" {
+		t.Errorf("btrfsParseDeviceStats() counters = %v", counters)
+	}
+}
+
+func Test_btrfsParseScrubStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			"Current tooling finished",
+			"UUID:             0f3ac2f6-8a4b-4a4e-9a1b-4c1f2a3b4c5d\nScrub started:    Sun Oct  8 00:24:02 2023\nStatus:           finished\nDuration:         0:00:01\nTotal to scrub:   1.00GiB\nRate:             1.00GiB/s\nError summary:    no errors found\n",
+			"finished",
+		},
+		{
+			"Current tooling running",
+			"UUID:             0f3ac2f6-8a4b-4a4e-9a1b-4c1f2a3b4c5d\nScrub started:    Sun Oct  8 00:24:02 2023\nStatus:           running\n",
+			"running",
+		},
+		{
+			"Older tooling aborted",
+			"scrub status for 0f3ac2f6-8a4b-4a4e-9a1b-4c1f2a3b4c5d\n\tscrub started at Sun Oct  8 00:24:02 2023 and was aborted after 00:00:05\n",
+			"canceled",
+		},
+		{
+			"Never scrubbed",
+			"UUID:             0f3ac2f6-8a4b-4a4e-9a1b-4c1f2a3b4c5d\n\tno stats available\n",
+			"none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := btrfsParseScrubStatus(tt.output)
+			if got != tt.want {
+				t.Errorf("btrfsParseScrubStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1017,16 +1017,29 @@ func (d *btrfs) UpdateVolume(vol Volume, changedConfig map[string]string) error
 // GetVolumeUsage returns the disk space used by the volume.
 func (d *btrfs) GetVolumeUsage(vol Volume) (int64, error) {
 	// Attempt to get the qgroup information.
-	_, usage, err := d.getQGroup(vol.MountPath())
+	_, referenced, exclusive, err := d.getQGroup(vol.MountPath())
 	if err != nil {
-		if errors.Is(err, errBtrfsNoQuota) {
+		// Without quota group, the usage can't be determined.
+		if errors.Is(err, errBtrfsNoQuota) || errors.Is(err, errBtrfsNoQGroup) {
 			return -1, ErrNotSupported
 		}
 
 		return -1, err
 	}
 
-	return usage, nil
+	// The usage reported while the quota groups are being rescanned is incomplete.
+	if d.qgroupRescanRunning(GetPoolMountPath(d.name)) {
+		return -1, ErrNotSupported
+	}
+
+	// Snapshots are accounted for the data only they reference (the space that would be freed by deleting them).
+	if vol.IsSnapshot() {
+		return exclusive, nil
+	}
+
+	// Volumes are accounted for all the data they reference, including what's shared with their snapshots.
+	// This matches what the size limit of the volume applies to.
+	return referenced, nil
 }
 
 // SetVolumeQuota applies a size limit on volume.
@@ -1077,7 +1090,7 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 	volPath := vol.MountPath()
 
 	// Try to locate an existing quota group.
-	qgroup, _, err := d.getQGroup(volPath)
+	qgroup, _, _, err := d.getQGroup(volPath)
 	if err != nil && !d.state.OS.RunningInUserNS {
 		// If quotas are disabled, attempt to enable them.
 		if errors.Is(err, errBtrfsNoQuota) {
@@ -1094,7 +1107,7 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 			}
 
 			// Try again.
-			qgroup, _, err = d.getQGroup(volPath)
+			qgroup, _, _, err = d.getQGroup(volPath)
 		}
 
 		// If there's no qgroup, attempt to create one.
@@ -1125,8 +1138,14 @@ func (d *btrfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 				return err
 			}
 
+			// Rescan the quota groups in the background so the usage of the existing data gets accounted.
+			_, err = subprocess.RunCommand("btrfs", "quota", "rescan", GetPoolMountPath(d.name))
+			if err != nil {
+				d.logger.Warn("Failed starting quota rescan", logger.Ctx{"err": err})
+			}
+
 			// Try to get the qgroup again.
-			qgroup, _, err = d.getQGroup(volPath)
+			qgroup, _, _, err = d.getQGroup(volPath)
 		}
 
 		if err != nil {
@@ -250,6 +250,14 @@ func (d *ceph) Create() error {
 		d.config["ceph.osd.pg_num"] = msg
 	}
 
+	// Enable mirroring on the pool so that individual volumes can be mirrored.
+	if util.IsTrue(d.config["ceph.rbd.mirroring"]) {
+		err := d.osdSetPoolMirroring(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 
 	return nil
@@ -296,6 +304,7 @@ func (d *ceph) Delete(op *operations.Operation) error {
 func (d *ceph) Validate(config map[string]string) error {
 	rules := map[string]func(value string) error{
 		"ceph.cluster_name":       validate.IsAny,
+		"ceph.client_addresses":   validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
 		"ceph.osd.force_reuse":    validate.Optional(validate.IsBool), // Deprecated, should not be used.
 		"ceph.osd.pg_num":         validate.IsAny,
 		"ceph.osd.pool_name":      validate.IsAny,
@@ -303,6 +312,7 @@ func (d *ceph) Validate(config map[string]string) error {
 		"ceph.rbd.clone_copy":     validate.Optional(validate.IsBool),
 		"ceph.rbd.du":             validate.Optional(validate.IsBool),
 		"ceph.rbd.features":       validate.IsAny,
+		"ceph.rbd.mirroring":      validate.Optional(validate.IsBool),
 		"ceph.user.name":          validate.IsAny,
 		"volatile.pool.pristine":  validate.IsAny,
 	}
@@ -312,6 +322,14 @@ func (d *ceph) Validate(config map[string]string) error {
 
 // Update applies any driver changes required from a configuration change.
 func (d *ceph) Update(changedConfig map[string]string) error {
+	mirroring, ok := changedConfig["ceph.rbd.mirroring"]
+	if ok {
+		err := d.osdSetPoolMirroring(util.IsTrue(mirroring))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -336,6 +354,27 @@ func (d *ceph) Unmount() (bool, error) {
 	return true, nil
 }
 
+// GetHealth returns the health of the Ceph cluster.
+func (d *ceph) GetHealth() (*api.StoragePoolStateHealth, error) {
+	return cephHealth(d.config["ceph.cluster_name"], d.config["ceph.user.name"])
+}
+
+// Scrub starts a scrub of the OSD pool.
+func (d *ceph) Scrub() error {
+	_, err := subprocess.RunCommand("ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"pool",
+		"scrub",
+		d.config["ceph.osd.pool_name"])
+	if err != nil {
+		return fmt.Errorf("Failed to scrub OSD pool %q: %w", d.config["ceph.osd.pool_name"], err)
+	}
+
+	return nil
+}
+
 // GetResources returns the pool resource usage information.
 func (d *ceph) GetResources() (*api.ResourcesStoragePool, error) {
 	var stdout bytes.Buffer
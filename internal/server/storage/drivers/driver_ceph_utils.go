@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -1335,3 +1336,298 @@ func (d *ceph) resizeVolume(vol Volume, sizeBytes int64, allowShrink bool) error
 
 	return err
 }
+
+// rbdMirrorInfo represents the mirroring details of an RBD image as reported by "rbd info".
+type rbdMirrorInfo struct {
+	Features  []string `json:"features"`
+	Mirroring *struct {
+		Mode    string `json:"mode"`
+		State   string `json:"state"`
+		Primary bool   `json:"primary"`
+	} `json:"mirroring"`
+}
+
+// rbdGetVolumeMirrorInfo returns the features and mirroring details of an RBD storage volume.
+func (d *ceph) rbdGetVolumeMirrorInfo(vol Volume) (*rbdMirrorInfo, error) {
+	out, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"--format", "json",
+		"info",
+		d.getRBDVolumeName(vol, "", false))
+	if err != nil {
+		return nil, err
+	}
+
+	info := rbdMirrorInfo{}
+	err = json.Unmarshal([]byte(out), &info)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing RBD volume information: %w", err)
+	}
+
+	return &info, nil
+}
+
+// rbdEnableVolumeMirroring enables mirroring of an RBD storage volume in the given mode.
+func (d *ceph) rbdEnableVolumeMirroring(vol Volume, mode string) error {
+	info, err := d.rbdGetVolumeMirrorInfo(vol)
+	if err != nil {
+		return err
+	}
+
+	if info.Mirroring != nil && info.Mirroring.State == "enabled" {
+		if info.Mirroring.Mode == mode {
+			return nil
+		}
+
+		return fmt.Errorf("Mirroring is already enabled in %q mode", info.Mirroring.Mode)
+	}
+
+	// Journal based mirroring requires the exclusive-lock and journaling features.
+	if mode == "journal" {
+		for _, feature := range []string{"exclusive-lock", "journaling"} {
+			if slices.Contains(info.Features, feature) {
+				continue
+			}
+
+			_, err = subprocess.RunCommand(
+				"rbd",
+				"--id", d.config["ceph.user.name"],
+				"--cluster", d.config["ceph.cluster_name"],
+				"--pool", d.config["ceph.osd.pool_name"],
+				"feature",
+				"enable",
+				d.getRBDVolumeName(vol, "", false),
+				feature)
+			if err != nil {
+				return fmt.Errorf("Failed enabling the %q feature: %w", feature, err)
+			}
+		}
+	}
+
+	_, err = subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"mirror",
+		"image",
+		"enable",
+		d.getRBDVolumeName(vol, "", false),
+		mode)
+	if err != nil {
+		return fmt.Errorf("Failed enabling mirroring: %w", err)
+	}
+
+	return nil
+}
+
+// rbdDisableVolumeMirroring disables mirroring of an RBD storage volume.
+func (d *ceph) rbdDisableVolumeMirroring(vol Volume) error {
+	_, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"mirror",
+		"image",
+		"disable",
+		d.getRBDVolumeName(vol, "", false))
+	if err != nil {
+		return fmt.Errorf("Failed disabling mirroring: %w", err)
+	}
+
+	return nil
+}
+
+// rbdGetVolumeMirrorStatus returns the replication state and its description for a mirrored RBD storage volume.
+func (d *ceph) rbdGetVolumeMirrorStatus(vol Volume) (string, string, error) {
+	out, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"--format", "json",
+		"mirror",
+		"image",
+		"status",
+		d.getRBDVolumeName(vol, "", false))
+	if err != nil {
+		return "", "", err
+	}
+
+	status := struct {
+		State       string `json:"state"`
+		Description string `json:"description"`
+	}{}
+
+	err = json.Unmarshal([]byte(out), &status)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed parsing RBD mirroring status: %w", err)
+	}
+
+	return status.State, status.Description, nil
+}
+
+// rbdGetVolumeWatchers returns the addresses of the clients watching an RBD storage volume.
+func (d *ceph) rbdGetVolumeWatchers(vol Volume) ([]string, error) {
+	out, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"--format", "json",
+		"status",
+		d.getRBDVolumeName(vol, "", false))
+	if err != nil {
+		return nil, err
+	}
+
+	status := struct {
+		Watchers []struct {
+			Address string `json:"address"`
+		} `json:"watchers"`
+	}{}
+
+	err = json.Unmarshal([]byte(out), &status)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing RBD status: %w", err)
+	}
+
+	watchers := make([]string, 0, len(status.Watchers))
+	for _, watcher := range status.Watchers {
+		watchers = append(watchers, watcher.Address)
+	}
+
+	return watchers, nil
+}
+
+// cephFenceAddresses returns the IP addresses to blocklist in order to cut off a cluster member from a volume.
+// The addresses come from the member's configured Ceph client addresses or, if not configured, from the
+// watchers of the volume (in the "IP:PORT/NONCE" form), which must then all come from the same address.
+// Fails if no address can be found or if a watcher doesn't match the configured addresses.
+func cephFenceAddresses(watchers []string, clientAddresses string) ([]string, error) {
+	addresses := []string{}
+	for _, address := range util.SplitNTrimSpace(clientAddresses, ",", -1, true) {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return nil, fmt.Errorf("Invalid Ceph client address %q", address)
+		}
+
+		addresses = append(addresses, ip.String())
+	}
+
+	configured := len(addresses) > 0
+
+	for _, watcher := range watchers {
+		host, _, err := net.SplitHostPort(strings.Split(watcher, "/")[0])
+		ip := net.ParseIP(host)
+		if err != nil || ip == nil {
+			return nil, fmt.Errorf("Invalid Ceph watcher address %q", watcher)
+		}
+
+		if slices.Contains(addresses, ip.String()) {
+			continue
+		}
+
+		if configured {
+			return nil, fmt.Errorf("Volume is still in use by Ceph client %q", watcher)
+		}
+
+		if len(addresses) > 0 {
+			return nil, errors.New("Volume is watched from multiple addresses, set \"ceph.client_addresses\" to tell them apart")
+		}
+
+		addresses = append(addresses, ip.String())
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("No Ceph client address to blocklist, set \"ceph.client_addresses\" on the cluster member")
+	}
+
+	return addresses, nil
+}
+
+// cephBlocklistClient adds a client to the OSD blocklist, preventing any further access to the cluster.
+func (d *ceph) cephBlocklistClient(address string) error {
+	_, err := subprocess.RunCommand("ceph",
+		"--name", fmt.Sprintf("client.%s", d.config["ceph.user.name"]),
+		"--cluster", d.config["ceph.cluster_name"],
+		"osd",
+		"blocklist",
+		"add",
+		address)
+	if err != nil {
+		return fmt.Errorf("Failed blocklisting Ceph client %q: %w", address, err)
+	}
+
+	return nil
+}
+
+// rbdPromoteVolume promotes a mirrored RBD storage volume to primary.
+func (d *ceph) rbdPromoteVolume(vol Volume, force bool) error {
+	args := []string{
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"mirror",
+		"image",
+		"promote",
+	}
+
+	if force {
+		args = append(args, "--force")
+	}
+
+	args = append(args, d.getRBDVolumeName(vol, "", false))
+
+	_, err := subprocess.RunCommand("rbd", args...)
+	if err != nil {
+		return fmt.Errorf("Failed promoting RBD volume: %w", err)
+	}
+
+	return nil
+}
+
+// rbdDemoteVolume demotes a mirrored RBD storage volume to non-primary.
+func (d *ceph) rbdDemoteVolume(vol Volume) error {
+	_, err := subprocess.RunCommand(
+		"rbd",
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"--pool", d.config["ceph.osd.pool_name"],
+		"mirror",
+		"image",
+		"demote",
+		d.getRBDVolumeName(vol, "", false))
+	if err != nil {
+		return fmt.Errorf("Failed demoting RBD volume: %w", err)
+	}
+
+	return nil
+}
+
+// osdSetPoolMirroring enables (in image mode) or disables mirroring on the OSD pool.
+func (d *ceph) osdSetPoolMirroring(enable bool) error {
+	args := []string{
+		"--id", d.config["ceph.user.name"],
+		"--cluster", d.config["ceph.cluster_name"],
+		"mirror",
+		"pool",
+	}
+
+	if enable {
+		args = append(args, "enable", d.config["ceph.osd.pool_name"], "image")
+	} else {
+		args = append(args, "disable", d.config["ceph.osd.pool_name"])
+	}
+
+	_, err := subprocess.RunCommand("rbd", args...)
+	if err != nil {
+		return fmt.Errorf("Failed configuring mirroring on the OSD pool: %w", err)
+	}
+
+	return nil
+}
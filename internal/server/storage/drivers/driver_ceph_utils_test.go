@@ -3,6 +3,8 @@ package drivers
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_ceph_getRBDVolumeName(t *testing.T) {
@@ -117,6 +119,66 @@ func Test_ceph_getRBDVolumeName(t *testing.T) {
 	}
 }
 
+func Test_cephFenceAddresses(t *testing.T) {
+	tests := []struct {
+		name            string
+		watchers        []string
+		clientAddresses string
+		want            []string
+		wantErr         bool
+	}{
+		{
+			name:            "Configured addresses without watchers",
+			clientAddresses: "10.0.0.1, fd00::1",
+			want:            []string{"10.0.0.1", "fd00::1"},
+		},
+		{
+			name:            "Configured addresses with matching watchers",
+			watchers:        []string{"10.0.0.1:0/1234", "[fd00::1]:6800/5678"},
+			clientAddresses: "10.0.0.1,fd00::1",
+			want:            []string{"10.0.0.1", "fd00::1"},
+		},
+		{
+			name:            "Configured addresses with watcher from another address",
+			watchers:        []string{"10.0.0.2:0/1234"},
+			clientAddresses: "10.0.0.1",
+			wantErr:         true,
+		},
+		{
+			name:     "Addresses from watchers",
+			watchers: []string{"10.0.0.1:0/1234", "10.0.0.1:0/5678"},
+			want:     []string{"10.0.0.1"},
+		},
+		{
+			name:     "Watchers from multiple addresses",
+			watchers: []string{"10.0.0.1:0/1234", "10.0.0.2:0/5678"},
+			wantErr:  true,
+		},
+		{
+			name:    "No configured address nor watcher",
+			wantErr: true,
+		},
+		{
+			name:     "Invalid watcher address",
+			watchers: []string{"client.admin"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cephFenceAddresses(tt.watchers, tt.clientAddresses)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func Example_ceph_parseParent() {
 	d := &ceph{}
 
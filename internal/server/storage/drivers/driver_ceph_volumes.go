@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"os"
 	"os/exec"
 	"strings"
@@ -286,6 +287,14 @@ func (d *ceph) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Ope
 		}
 	}
 
+	// Enable mirroring now that the volume is filled.
+	if vol.config["ceph.rbd.mirror"] != "" {
+		err = d.rbdEnableVolumeMirroring(vol, vol.config["ceph.rbd.mirror"])
+		if err != nil {
+			return err
+		}
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -364,6 +373,13 @@ func (d *ceph) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots boo
 			return err
 		}
 
+		if v.config["ceph.rbd.mirror"] != "" {
+			err = d.rbdEnableVolumeMirroring(v, v.config["ceph.rbd.mirror"])
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}
 
@@ -643,6 +659,13 @@ func (d *ceph) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser, vo
 		return err
 	}
 
+	if vol.config["ceph.rbd.mirror"] != "" {
+		err = d.rbdEnableVolumeMirroring(vol, vol.config["ceph.rbd.mirror"])
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -839,6 +862,7 @@ func (d *ceph) commonVolumeRules() map[string]func(value string) error {
 	return map[string]func(value string) error{
 		"block.filesystem":    validate.Optional(validate.IsOneOf(blockBackedAllowedFilesystems...)),
 		"block.mount_options": validate.IsAny,
+		"ceph.rbd.mirror":     validate.Optional(validate.IsOneOf("journal", "snapshot")),
 	}
 }
 
@@ -855,6 +879,10 @@ func (d *ceph) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
+	if vol.config["ceph.rbd.mirror"] != "" && util.IsFalseOrEmpty(d.config["ceph.rbd.mirroring"]) {
+		return errors.New("Mirroring must be enabled on the storage pool (ceph.rbd.mirroring) to mirror volumes")
+	}
+
 	return d.validateVolume(vol, commonRules, removeUnknownKeys)
 }
 
@@ -868,6 +896,119 @@ func (d *ceph) UpdateVolume(vol Volume, changedConfig map[string]string) error {
 		}
 	}
 
+	newMirror, mirrorChanged := changedConfig["ceph.rbd.mirror"]
+	if mirrorChanged {
+		vols := []Volume{vol}
+		if vol.IsVMBlock() {
+			vols = append(vols, vol.NewVMBlockFilesystemVolume())
+		}
+
+		for _, v := range vols {
+			// Switching mode requires disabling mirroring first.
+			if v.config["ceph.rbd.mirror"] != "" {
+				err := d.rbdDisableVolumeMirroring(v)
+				if err != nil {
+					return err
+				}
+			}
+
+			if newMirror != "" {
+				err := d.rbdEnableVolumeMirroring(v, newMirror)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetVolumeMirror returns the mirroring state of the volume.
+func (d *ceph) GetVolumeMirror(vol Volume) (*api.StorageVolumeStateMirror, error) {
+	info, err := d.rbdGetVolumeMirrorInfo(vol)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mirroring == nil || info.Mirroring.State != "enabled" {
+		return nil, ErrNotSupported
+	}
+
+	state, description, err := d.rbdGetVolumeMirrorStatus(vol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.StorageVolumeStateMirror{
+		Mode:        info.Mirroring.Mode,
+		Primary:     info.Mirroring.Primary,
+		State:       state,
+		Description: description,
+	}, nil
+}
+
+// PromoteVolume promotes the mirrored volume to primary.
+func (d *ceph) PromoteVolume(vol Volume, force bool) error {
+	err := d.rbdPromoteVolume(vol, force)
+	if err != nil {
+		return err
+	}
+
+	if vol.IsVMBlock() {
+		return d.rbdPromoteVolume(vol.NewVMBlockFilesystemVolume(), force)
+	}
+
+	return nil
+}
+
+// DemoteVolume demotes the mirrored volume to non-primary.
+func (d *ceph) DemoteVolume(vol Volume) error {
+	err := d.rbdDemoteVolume(vol)
+	if err != nil {
+		return err
+	}
+
+	if vol.IsVMBlock() {
+		return d.rbdDemoteVolume(vol.NewVMBlockFilesystemVolume())
+	}
+
+	return nil
+}
+
+// FenceVolume blocklists all the Ceph clients of the cluster member which used the volume, preventing them from
+// writing to it. The member's client addresses are taken from its "ceph.client_addresses" pool configuration or,
+// when unset, from the clients still watching the volume. They are blocklisted as a whole as the member's clients
+// may not be watching the volume anymore while still being able to write to it.
+func (d *ceph) FenceVolume(vol Volume, memberConfig map[string]string) error {
+	vols := []Volume{vol}
+	if vol.IsVMBlock() {
+		vols = append(vols, vol.NewVMBlockFilesystemVolume())
+	}
+
+	watchers := []string{}
+	for _, vol := range vols {
+		volWatchers, err := d.rbdGetVolumeWatchers(vol)
+		if err != nil {
+			return err
+		}
+
+		watchers = append(watchers, volWatchers...)
+	}
+
+	addresses, err := cephFenceAddresses(watchers, memberConfig["ceph.client_addresses"])
+	if err != nil {
+		return fmt.Errorf("Failed fencing volume %q: %w", vol.Name(), err)
+	}
+
+	// A zero port and nonce blocklists all the clients from the address.
+	for _, address := range addresses {
+		err := d.cephBlocklistClient(net.JoinHostPort(address, "0") + "/0")
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1003,12 +1144,9 @@ func (d *ceph) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, o
 		fsType := vol.ConfigBlockFilesystem()
 
 		if sizeBytes < oldSizeBytes {
-			if !filesystemTypeCanBeShrunk(fsType) {
-				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
-			}
-
-			if inUse {
-				return ErrInUse // We don't allow online shrinking of filesystem volumes.
+			err = checkFileSystemShrink(fsType, vol, sizeBytes, inUse)
+			if err != nil {
+				return err
 			}
 
 			// Shrink filesystem first. Pass allowUnsafeResize to allow disabling of filesystem
@@ -1078,6 +1216,21 @@ func (d *ceph) GetVolumeDiskPath(vol Volume) (string, error) {
 	return "", ErrNotSupported
 }
 
+// GetVolumeIOStats returns the I/O counters of a volume's block device.
+// No counters are returned when the RBD volume isn't currently mapped on this server.
+func (d *ceph) GetVolumeIOStats(vol Volume) (*VolumeIOStats, error) {
+	if !vol.IsVMBlock() && !(vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		return nil, ErrNotSupported
+	}
+
+	_, devPath, err := d.getRBDMappedDevPath(vol, false)
+	if err != nil {
+		return nil, nil
+	}
+
+	return blockDeviceIOStats(devPath)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *ceph) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -109,6 +109,10 @@ func (d *cephfs) FillConfig() error {
 		d.config["cephfs.user.name"] = CephDefaultUser
 	}
 
+	if d.config["cephfs.subvolume_group"] == "" {
+		d.config["cephfs.subvolume_group"] = d.name
+	}
+
 	return nil
 }
 
@@ -310,6 +314,28 @@ func (d *cephfs) Create() error {
 		return errors.New("Only empty CephFS paths can be used as a storage pool")
 	}
 
+	// Create the subvolume group if missing, otherwise check that it's empty.
+	group := d.config["cephfs.subvolume_group"]
+
+	_, err = d.cephfsCommand("subvolumegroup", "getpath", fsName, group)
+	if err != nil {
+		_, err = d.cephfsCommand("subvolumegroup", "create", fsName, group)
+		if err != nil {
+			return fmt.Errorf("Failed to create subvolume group %q: %w", group, err)
+		}
+
+		reverter.Add(func() { _, _ = d.cephfsCommand("subvolumegroup", "rm", fsName, group) })
+	} else {
+		subvolumes, err := d.cephfsCommand("subvolume", "ls", fsName, "--group_name", group, "--format", "json")
+		if err != nil {
+			return fmt.Errorf("Failed to list the subvolumes of group %q: %w", group, err)
+		}
+
+		if subvolumes != "[]" {
+			return fmt.Errorf("Only empty CephFS subvolume groups can be used as a storage pool, %q isn't empty", group)
+		}
+	}
+
 	reverter.Success()
 
 	return nil
@@ -321,6 +347,19 @@ func (d *cephfs) Delete(op *operations.Operation) error {
 	fsName, fsPath, _ := strings.Cut(d.config["cephfs.path"], "/")
 	fsPath = "/" + fsPath
 
+	// Remove the subvolume group.
+	if d.useSubvolumes() {
+		_, err := forceUnmount(d.subvolumesPath())
+		if err != nil {
+			return err
+		}
+
+		_, err = d.cephfsCommand("subvolumegroup", "rm", fsName, d.config["cephfs.subvolume_group"], "--force")
+		if err != nil {
+			return fmt.Errorf("Failed to delete subvolume group %q: %w", d.config["cephfs.subvolume_group"], err)
+		}
+	}
+
 	// Create a temporary mountpoint.
 	mountPath, err := os.MkdirTemp("", "incus_cephfs_")
 	if err != nil {
@@ -407,6 +446,7 @@ func (d *cephfs) Validate(config map[string]string) error {
 		"cephfs.cluster_name":    validate.IsAny,
 		"cephfs.fscache":         validate.Optional(validate.IsBool),
 		"cephfs.path":            validate.IsAny,
+		"cephfs.subvolume_group": validate.IsAny,
 		"cephfs.user.name":       validate.IsAny,
 		"cephfs.create_missing":  validate.Optional(validate.IsBool),
 		"cephfs.osd_pg_num":      validate.Optional(validate.IsInt64),
@@ -420,6 +460,11 @@ func (d *cephfs) Validate(config map[string]string) error {
 
 // Update applies any driver changes required from a configuration change.
 func (d *cephfs) Update(changedConfig map[string]string) error {
+	_, ok := changedConfig["cephfs.subvolume_group"]
+	if ok {
+		return errors.New("cephfs.subvolume_group cannot be changed")
+	}
+
 	return nil
 }
 
@@ -431,41 +476,22 @@ func (d *cephfs) Mount() (bool, error) {
 	}
 
 	// Parse the namespace / path.
-	fsName, fsPath, _ := strings.Cut(d.config["cephfs.path"], "/")
+	_, fsPath, _ := strings.Cut(d.config["cephfs.path"], "/")
 	fsPath = "/" + fsPath
 
-	// Collect Ceph information.
-	clusterName := d.config["cephfs.cluster_name"]
-	userName := d.config["cephfs.user.name"]
-
-	fsid, err := CephFsid(clusterName, userName)
-	if err != nil {
-		return false, err
-	}
-
-	monitors, err := CephMonitors(clusterName, userName)
-	if err != nil {
-		return false, err
-	}
-
-	key, err := CephKeyring(clusterName, userName)
+	// Mount the pool.
+	err := d.mountFS(fsPath, GetPoolMountPath(d.name))
 	if err != nil {
 		return false, err
 	}
 
-	srcPath, options := CephBuildMount(
-		userName,
-		key,
-		fsid,
-		monitors,
-		fsName,
-		fsPath,
-	)
-
-	// Mount the pool.
-	err = TryMount(srcPath, GetPoolMountPath(d.name), "ceph", 0, strings.Join(options, ","))
-	if err != nil {
-		return false, err
+	// Mount the subvolume group.
+	if d.useSubvolumes() {
+		err = d.mountSubvolumes()
+		if err != nil {
+			_, _ = forceUnmount(GetPoolMountPath(d.name))
+			return false, err
+		}
 	}
 
 	return true, nil
@@ -473,9 +499,21 @@ func (d *cephfs) Mount() (bool, error) {
 
 // Unmount clears any of the runtime state of the driver.
 func (d *cephfs) Unmount() (bool, error) {
+	if d.useSubvolumes() {
+		_, err := forceUnmount(d.subvolumesPath())
+		if err != nil {
+			return false, err
+		}
+	}
+
 	return forceUnmount(GetPoolMountPath(d.name))
 }
 
+// GetHealth returns the health of the Ceph cluster.
+func (d *cephfs) GetHealth() (*api.StoragePoolStateHealth, error) {
+	return cephHealth(d.config["cephfs.cluster_name"], d.config["cephfs.user.name"])
+}
+
 // GetResources returns the pool resource usage information.
 func (d *cephfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return genericVFSGetResources(d)
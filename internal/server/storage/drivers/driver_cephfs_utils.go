@@ -1,12 +1,25 @@
 package drivers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
 
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/units"
 )
 
+// cephfsSubvolumesDir is the directory of the storage pool on which the subvolume group is mounted.
+const cephfsSubvolumesDir = ".subvolumes"
+
 // fsExists checks that the Ceph FS instance indeed exists.
 func (d *cephfs) fsExists(clusterName string, userName string, fsName string) (bool, error) {
 	_, err := subprocess.RunCommand("ceph", "--name", fmt.Sprintf("client.%s", userName), "--cluster", clusterName, "fs", "get", fsName)
@@ -44,3 +57,242 @@ func (d *cephfs) osdPoolExists(clusterName string, userName string, osdPoolName
 
 	return true, nil
 }
+
+// cephfsCommand runs a "ceph fs" command against the cluster of the storage pool.
+func (d *cephfs) cephfsCommand(args ...string) (string, error) {
+	args = append([]string{"--name", fmt.Sprintf("client.%s", d.config["cephfs.user.name"]), "--cluster", d.config["cephfs.cluster_name"], "fs"}, args...)
+
+	out, err := subprocess.RunCommand("ceph", args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// fsName returns the name of the CephFS filesystem used by the storage pool.
+func (d *cephfs) fsName() string {
+	fsName, _, _ := strings.Cut(d.config["cephfs.path"], "/")
+	return fsName
+}
+
+// mountFS mounts the given path of the CephFS filesystem on the target path.
+func (d *cephfs) mountFS(fsPath string, target string) error {
+	// Collect Ceph information.
+	clusterName := d.config["cephfs.cluster_name"]
+	userName := d.config["cephfs.user.name"]
+
+	fsid, err := CephFsid(clusterName, userName)
+	if err != nil {
+		return err
+	}
+
+	monitors, err := CephMonitors(clusterName, userName)
+	if err != nil {
+		return err
+	}
+
+	key, err := CephKeyring(clusterName, userName)
+	if err != nil {
+		return err
+	}
+
+	srcPath, options := CephBuildMount(
+		userName,
+		key,
+		fsid,
+		monitors,
+		d.fsName(),
+		fsPath,
+	)
+
+	return TryMount(srcPath, target, "ceph", 0, strings.Join(options, ","))
+}
+
+// useSubvolumes returns whether the volumes of the storage pool are backed by CephFS subvolumes.
+func (d *cephfs) useSubvolumes() bool {
+	return d.config["cephfs.subvolume_group"] != ""
+}
+
+// subvolumesPath returns the path on which the subvolume group of the storage pool is mounted.
+func (d *cephfs) subvolumesPath() string {
+	return filepath.Join(GetPoolMountPath(d.name), cephfsSubvolumesDir)
+}
+
+// mountSubvolumes mounts the subvolume group of the storage pool.
+func (d *cephfs) mountSubvolumes() error {
+	path := d.subvolumesPath()
+	if linux.IsMountPoint(path) {
+		return nil
+	}
+
+	groupPath, err := d.cephfsCommand("subvolumegroup", "getpath", d.fsName(), d.config["cephfs.subvolume_group"])
+	if err != nil {
+		return fmt.Errorf("Failed to get the path of subvolume group %q: %w", d.config["cephfs.subvolume_group"], err)
+	}
+
+	err = os.MkdirAll(path, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory '%s': %w", path, err)
+	}
+
+	return d.mountFS(groupPath, path)
+}
+
+// subvolumePath returns the data path of the subvolume backing the volume.
+// The volume's mount path is a symlink to the data directory of its subvolume, in the form
+// <subvolumes>/<subvolume>/<uuid>, which keeps the volume name independent of the subvolume name.
+func (d *cephfs) subvolumePath(volType VolumeType, volName string) (string, error) {
+	volPath := GetVolumeMountPath(d.name, volType, volName)
+
+	target, err := os.Readlink(volPath)
+	if err != nil {
+		return "", fmt.Errorf("Failed to resolve the subvolume of %q: %w", volName, err)
+	}
+
+	if filepath.Dir(filepath.Dir(target)) != d.subvolumesPath() {
+		return "", fmt.Errorf("Volume %q isn't backed by a subvolume", volName)
+	}
+
+	return target, nil
+}
+
+// subvolumeName returns the name of the subvolume backing the volume.
+func (d *cephfs) subvolumeName(volType VolumeType, volName string) (string, error) {
+	path, err := d.subvolumePath(volType, volName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Base(filepath.Dir(path)), nil
+}
+
+// subvolumeSnapshotPath returns the data path of a snapshot of the subvolume backing the volume.
+func (d *cephfs) subvolumeSnapshotPath(volType VolumeType, volName string, snapName string) (string, error) {
+	path, err := d.subvolumePath(volType, volName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(path), ".snap", snapName, filepath.Base(path)), nil
+}
+
+// createSubvolume creates the subvolume backing the volume and links it on the volume's mount path.
+func (d *cephfs) createSubvolume(vol Volume) error {
+	group := d.config["cephfs.subvolume_group"]
+
+	// Subvolumes can't be renamed so use a unique name rather than the volume name.
+	subvolName := uuid.New().String()
+
+	_, err := d.cephfsCommand("subvolume", "create", d.fsName(), subvolName, "--group_name", group)
+	if err != nil {
+		return fmt.Errorf("Failed to create subvolume for %q: %w", vol.name, err)
+	}
+
+	path, err := d.cephfsCommand("subvolume", "getpath", d.fsName(), subvolName, "--group_name", group)
+	if err == nil {
+		target := filepath.Join(d.subvolumesPath(), subvolName, filepath.Base(path))
+		err = os.Symlink(target, vol.MountPath())
+	}
+
+	if err != nil {
+		_, _ = d.cephfsCommand("subvolume", "rm", d.fsName(), subvolName, "--group_name", group)
+		return fmt.Errorf("Failed to link subvolume for %q: %w", vol.name, err)
+	}
+
+	return nil
+}
+
+// deleteSubvolume removes the subvolume backing the volume and its link.
+func (d *cephfs) deleteSubvolume(vol Volume) error {
+	subvolName, err := d.subvolumeName(vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.cephfsCommand("subvolume", "rm", d.fsName(), subvolName, "--group_name", d.config["cephfs.subvolume_group"])
+	if err != nil {
+		return fmt.Errorf("Failed to delete subvolume for %q: %w", vol.name, err)
+	}
+
+	err = os.Remove(vol.MountPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to remove '%s': %w", vol.MountPath(), err)
+	}
+
+	return nil
+}
+
+// resizeSubvolume sets the quota of the subvolume backing the volume (0 removes the quota).
+func (d *cephfs) resizeSubvolume(vol Volume, sizeBytes int64) error {
+	subvolName, err := d.subvolumeName(vol.volType, vol.name)
+	if err != nil {
+		return err
+	}
+
+	size := "infinite"
+	if sizeBytes > 0 {
+		size = strconv.FormatInt(sizeBytes, 10)
+	}
+
+	_, err = d.cephfsCommand("subvolume", "resize", d.fsName(), subvolName, size, "--group_name", d.config["cephfs.subvolume_group"])
+	if err != nil {
+		return fmt.Errorf("Failed to resize subvolume for %q to %s: %w", vol.name, units.GetByteSizeStringIEC(sizeBytes, 2), err)
+	}
+
+	return nil
+}
+
+// subvolumeUsage returns the space used by the subvolume backing the volume.
+func (d *cephfs) subvolumeUsage(vol Volume) (int64, error) {
+	subvolName, err := d.subvolumeName(vol.volType, vol.name)
+	if err != nil {
+		return -1, err
+	}
+
+	out, err := d.cephfsCommand("subvolume", "info", d.fsName(), subvolName, "--group_name", d.config["cephfs.subvolume_group"], "--format", "json")
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get subvolume info for %q: %w", vol.name, err)
+	}
+
+	info := struct {
+		BytesUsed int64 `json:"bytes_used"`
+	}{}
+
+	err = json.Unmarshal([]byte(out), &info)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to parse subvolume info for %q: %w", vol.name, err)
+	}
+
+	return info.BytesUsed, nil
+}
+
+// createSubvolumeSnapshot snapshots the subvolume backing the volume and returns the data path of the snapshot.
+func (d *cephfs) createSubvolumeSnapshot(volType VolumeType, volName string, snapName string) (string, error) {
+	subvolName, err := d.subvolumeName(volType, volName)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = d.cephfsCommand("subvolume", "snapshot", "create", d.fsName(), subvolName, snapName, "--group_name", d.config["cephfs.subvolume_group"])
+	if err != nil {
+		return "", fmt.Errorf("Failed to create subvolume snapshot %q for %q: %w", snapName, volName, err)
+	}
+
+	return d.subvolumeSnapshotPath(volType, volName, snapName)
+}
+
+// deleteSubvolumeSnapshot deletes a snapshot of the subvolume backing the volume.
+func (d *cephfs) deleteSubvolumeSnapshot(volType VolumeType, volName string, snapName string) error {
+	subvolName, err := d.subvolumeName(volType, volName)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.cephfsCommand("subvolume", "snapshot", "rm", d.fsName(), subvolName, snapName, "--group_name", d.config["cephfs.subvolume_group"], "--force")
+	if err != nil {
+		return fmt.Errorf("Failed to delete subvolume snapshot %q for %q: %w", snapName, volName, err)
+	}
+
+	return nil
+}
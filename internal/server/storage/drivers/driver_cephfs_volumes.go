@@ -36,8 +36,7 @@ func (d *cephfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.O
 	}
 
 	// Create the main volume path.
-	volPath := vol.MountPath()
-	err := vol.EnsureMountPath()
+	err := d.createVolumePath(vol)
 	if err != nil {
 		return err
 	}
@@ -46,7 +45,7 @@ func (d *cephfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.O
 	revertPath := true
 	defer func() {
 		if revertPath {
-			_ = os.RemoveAll(volPath)
+			_ = d.deleteVolumePath(vol)
 		}
 	}()
 
@@ -76,8 +75,7 @@ func (d *cephfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots b
 	bwlimit := d.config["rsync.bwlimit"]
 
 	// Create the main volume path.
-	volPath := vol.MountPath()
-	err := vol.EnsureMountPath()
+	err := d.createVolumePath(vol)
 	if err != nil {
 		return err
 	}
@@ -97,7 +95,7 @@ func (d *cephfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots b
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		_ = os.RemoveAll(volPath)
+		_ = d.deleteVolumePath(vol)
 	}()
 
 	// Ensure the volume is mounted.
@@ -165,8 +163,7 @@ func (d *cephfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser,
 	}
 
 	// Create the main volume path.
-	volPath := vol.MountPath()
-	err := vol.EnsureMountPath()
+	err := d.createVolumePath(vol)
 	if err != nil {
 		return err
 	}
@@ -186,7 +183,7 @@ func (d *cephfs) CreateVolumeFromMigration(vol Volume, conn io.ReadWriteCloser,
 			_ = d.DeleteVolumeSnapshot(snapVol, op)
 		}
 
-		_ = os.RemoveAll(volPath)
+		_ = d.deleteVolumePath(vol)
 	}()
 
 	// Ensure the volume is mounted.
@@ -262,9 +259,9 @@ func (d *cephfs) DeleteVolume(vol Volume, op *operations.Operation) error {
 	}
 
 	// Remove the volume from the storage device.
-	err = os.RemoveAll(volPath)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("Failed to delete '%s': %w", volPath, err)
+	err = d.deleteVolumePath(vol)
+	if err != nil {
+		return err
 	}
 
 	// Although the volume snapshot directory should already be removed, lets remove it here
@@ -279,6 +276,42 @@ func (d *cephfs) DeleteVolume(vol Volume, op *operations.Operation) error {
 	return nil
 }
 
+// createVolumePath creates the mount path of the volume, backed by a new subvolume when enabled.
+func (d *cephfs) createVolumePath(vol Volume) error {
+	if d.useSubvolumes() {
+		err := d.createSubvolume(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := vol.EnsureMountPath()
+	if err != nil {
+		if d.useSubvolumes() {
+			_ = d.deleteSubvolume(vol)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// deleteVolumePath removes the mount path of the volume along with its subvolume when enabled.
+func (d *cephfs) deleteVolumePath(vol Volume) error {
+	if d.useSubvolumes() {
+		return d.deleteSubvolume(vol)
+	}
+
+	volPath := vol.MountPath()
+	err := os.RemoveAll(volPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to delete '%s': %w", volPath, err)
+	}
+
+	return nil
+}
+
 // HasVolume indicates whether a specific volume exists on the storage pool.
 func (d *cephfs) HasVolume(vol Volume) (bool, error) {
 	return genericVFSHasVolume(vol)
@@ -309,7 +342,11 @@ func (d *cephfs) GetVolumeUsage(vol Volume) (int64, error) {
 		return -1, ErrNotSupported
 	}
 
-	out, err := subprocess.RunCommand("getfattr", "-n", "ceph.quota.max_bytes", "--only-values", GetVolumeMountPath(d.name, vol.volType, vol.name))
+	if d.useSubvolumes() {
+		return d.subvolumeUsage(vol)
+	}
+
+	out, err := subprocess.RunCommand("getfattr", "-n", "ceph.dir.rbytes", "--only-values", GetVolumeMountPath(d.name, vol.volType, vol.name))
 	if err != nil {
 		return -1, err
 	}
@@ -334,6 +371,10 @@ func (d *cephfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool,
 		return err
 	}
 
+	if d.useSubvolumes() {
+		return d.resizeSubvolume(vol, sizeBytes)
+	}
+
 	_, err = subprocess.RunCommand("setfattr", "-n", "ceph.quota.max_bytes", "-v", fmt.Sprintf("%d", sizeBytes), GetVolumeMountPath(d.name, vol.volType, vol.name))
 	return err
 }
@@ -430,34 +471,37 @@ func (d *cephfs) RenameVolume(vol Volume, newVolName string, op *operations.Oper
 		})
 	}
 
-	// Rename any snapshots of the volume too.
-	snapshots, err := vol.Snapshots(op)
-	if err != nil {
-		return err
-	}
+	// The snapshots of subvolumes don't depend on the volume name.
+	if !d.useSubvolumes() {
+		// Rename any snapshots of the volume too.
+		snapshots, err := vol.Snapshots(op)
+		if err != nil {
+			return err
+		}
 
-	sourcePath := GetVolumeMountPath(d.name, vol.volType, newVolName)
-	targetPath := GetVolumeMountPath(d.name, vol.volType, newVolName)
+		sourcePath := GetVolumeMountPath(d.name, vol.volType, newVolName)
+		targetPath := GetVolumeMountPath(d.name, vol.volType, newVolName)
 
-	for _, snapshot := range snapshots {
-		// Figure out the snapshot paths.
-		_, snapName, _ := api.GetParentAndSnapshotName(snapshot.name)
-		oldCephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
-		newCephSnapPath := filepath.Join(targetPath, ".snap", snapName)
-		oldPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(vol.name, snapName))
-		newPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(newVolName, snapName))
+		for _, snapshot := range snapshots {
+			// Figure out the snapshot paths.
+			_, snapName, _ := api.GetParentAndSnapshotName(snapshot.name)
+			oldCephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
+			newCephSnapPath := filepath.Join(targetPath, ".snap", snapName)
+			oldPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(vol.name, snapName))
+			newPath := GetVolumeMountPath(d.name, vol.volType, GetSnapshotVolumeName(newVolName, snapName))
 
-		// Update the symlink.
-		err = os.Symlink(newCephSnapPath, newPath)
-		if err != nil {
-			return fmt.Errorf("Failed to symlink '%s' to '%s': %w", newCephSnapPath, newPath, err)
-		}
+			// Update the symlink.
+			err = os.Symlink(newCephSnapPath, newPath)
+			if err != nil {
+				return fmt.Errorf("Failed to symlink '%s' to '%s': %w", newCephSnapPath, newPath, err)
+			}
 
-		revertPaths = append(revertPaths, volRevert{
-			oldPath:   oldPath,
-			newPath:   oldCephSnapPath,
-			isSymlink: true,
-		})
+			revertPaths = append(revertPaths, volRevert{
+				oldPath:   oldPath,
+				newPath:   oldCephSnapPath,
+				isSymlink: true,
+			})
+		}
 	}
 
 	oldPath := GetVolumeMountPath(d.name, vol.volType, vol.name)
@@ -491,16 +535,25 @@ func (d *cephfs) CreateVolumeSnapshot(snapVol Volume, op *operations.Operation)
 	parentName, snapName, _ := api.GetParentAndSnapshotName(snapVol.name)
 
 	// Create the snapshot.
-	sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
-	cephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
+	var cephSnapPath string
+	if d.useSubvolumes() {
+		var err error
+		cephSnapPath, err = d.createSubvolumeSnapshot(snapVol.volType, parentName, snapName)
+		if err != nil {
+			return err
+		}
+	} else {
+		sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+		cephSnapPath = filepath.Join(sourcePath, ".snap", snapName)
 
-	err := os.Mkdir(cephSnapPath, 0o711)
-	if err != nil {
-		return fmt.Errorf("Failed to create directory '%s': %w", cephSnapPath, err)
+		err := os.Mkdir(cephSnapPath, 0o711)
+		if err != nil {
+			return fmt.Errorf("Failed to create directory '%s': %w", cephSnapPath, err)
+		}
 	}
 
 	// Create the parent directory.
-	err = createParentSnapshotDirIfMissing(d.name, snapVol.volType, parentName)
+	err := createParentSnapshotDirIfMissing(d.name, snapVol.volType, parentName)
 	if err != nil {
 		return err
 	}
@@ -520,17 +573,24 @@ func (d *cephfs) DeleteVolumeSnapshot(snapVol Volume, op *operations.Operation)
 	parentName, snapName, _ := api.GetParentAndSnapshotName(snapVol.name)
 
 	// Delete the snapshot itself.
-	sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
-	cephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
+	if d.useSubvolumes() {
+		err := d.deleteSubvolumeSnapshot(snapVol.volType, parentName, snapName)
+		if err != nil {
+			return err
+		}
+	} else {
+		sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
+		cephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
 
-	err := os.Remove(cephSnapPath)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("Failed to remove '%s': %w", cephSnapPath, err)
+		err := os.Remove(cephSnapPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed to remove '%s': %w", cephSnapPath, err)
+		}
 	}
 
 	// Remove the symlink.
 	snapPath := snapVol.MountPath()
-	err = os.Remove(snapPath)
+	err := os.Remove(snapPath)
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("Failed to remove '%s': %w", snapPath, err)
 	}
@@ -579,6 +639,14 @@ func (d *cephfs) RestoreVolume(vol Volume, snapshotName string, op *operations.O
 	sourcePath := GetVolumeMountPath(d.name, vol.volType, vol.name)
 	cephSnapPath := filepath.Join(sourcePath, ".snap", snapshotName)
 
+	if d.useSubvolumes() {
+		var err error
+		cephSnapPath, err = d.subvolumeSnapshotPath(vol.volType, vol.name, snapshotName)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Restore using rsync.
 	bwlimit := d.config["rsync.bwlimit"]
 	output, err := rsync.LocalCopy(cephSnapPath, vol.MountPath(), bwlimit, false)
@@ -595,6 +663,19 @@ func (d *cephfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op
 	sourcePath := GetVolumeMountPath(d.name, snapVol.volType, parentName)
 	oldCephSnapPath := filepath.Join(sourcePath, ".snap", snapName)
 	newCephSnapPath := filepath.Join(sourcePath, ".snap", newSnapshotName)
+	linkTarget := newCephSnapPath
+
+	// Subvolume snapshots are taken at the subvolume root with the data in a sub-directory.
+	if d.useSubvolumes() {
+		var err error
+		linkTarget, err = d.subvolumeSnapshotPath(snapVol.volType, parentName, newSnapshotName)
+		if err != nil {
+			return err
+		}
+
+		oldCephSnapPath = filepath.Join(filepath.Dir(filepath.Dir(linkTarget)), snapName)
+		newCephSnapPath = filepath.Dir(linkTarget)
+	}
 
 	err := os.Rename(oldCephSnapPath, newCephSnapPath)
 	if err != nil {
@@ -609,9 +690,9 @@ func (d *cephfs) RenameVolumeSnapshot(snapVol Volume, newSnapshotName string, op
 	}
 
 	newPath := GetVolumeMountPath(d.name, snapVol.volType, GetSnapshotVolumeName(parentName, newSnapshotName))
-	err = os.Symlink(newCephSnapPath, newPath)
+	err = os.Symlink(linkTarget, newPath)
 	if err != nil {
-		return fmt.Errorf("Failed to symlink '%s' to '%s': %w", newCephSnapPath, newPath, err)
+		return fmt.Errorf("Failed to symlink '%s' to '%s': %w", linkTarget, newPath, err)
 	}
 
 	return nil
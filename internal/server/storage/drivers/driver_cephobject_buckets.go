@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/storage/s3"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/units"
@@ -142,6 +144,12 @@ func (d *cephobject) CreateBucket(bucket Volume, op *operations.Operation) error
 		}
 	}
 
+	// Apply initial versioning and lifecycle settings.
+	err = s3.SetBucketConfig(ctx, minioClient, storageBucketName, bucket.config, bucket.config)
+	if err != nil {
+		return err
+	}
+
 	reverter.Success()
 	return nil
 }
@@ -192,6 +200,31 @@ func (d *cephobject) UpdateBucket(bucket Volume, changedConfig map[string]string
 		}
 	}
 
+	if s3.BucketConfigChanged(changedConfig) {
+		adminUserInfo, _, err := d.radosgwadminGetUser(context.TODO(), cephobjectRadosgwAdminUser)
+		if err != nil {
+			return fmt.Errorf("Failed getting admin user %q: %w", cephobjectRadosgwAdminUser, err)
+		}
+
+		minioClient, err := d.s3Client(*adminUserInfo)
+		if err != nil {
+			return err
+		}
+
+		// The full config is needed to rebuild the lifecycle rule.
+		config := maps.Clone(bucket.config)
+		maps.Copy(config, changedConfig)
+
+		ctx, ctxCancel := context.WithTimeout(context.TODO(), time.Duration(time.Second*30))
+		defer ctxCancel()
+
+		_, bucketName := project.StorageVolumeParts(bucket.name)
+		err = s3.SetBucketConfig(ctx, minioClient, d.radosgwBucketName(bucketName), config, changedConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
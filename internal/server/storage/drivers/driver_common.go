@@ -19,6 +19,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
@@ -310,6 +311,16 @@ func (d *common) moveGPTAltHeader(devPath string) error {
 	return err
 }
 
+// GetHealth returns the health of the storage pool.
+func (d *common) GetHealth() (*api.StoragePoolStateHealth, error) {
+	return nil, ErrNotSupported
+}
+
+// Scrub starts a scrub of the storage pool.
+func (d *common) Scrub() error {
+	return ErrNotSupported
+}
+
 // runFiller runs the supplied filler, and setting the returned volume size back into filler.
 func (d *common) runFiller(vol Volume, devPath string, filler *VolumeFiller, allowUnsafeResize bool) error {
 	if filler == nil || filler.Fill == nil {
@@ -377,6 +388,26 @@ func (d *common) GetVolumeUsage(vol Volume) (int64, error) {
 	return -1, ErrNotSupported
 }
 
+// GetVolumeMirror returns the mirroring state of a volume.
+func (d *common) GetVolumeMirror(vol Volume) (*api.StorageVolumeStateMirror, error) {
+	return nil, ErrNotSupported
+}
+
+// PromoteVolume promotes a mirrored volume to primary.
+func (d *common) PromoteVolume(vol Volume, force bool) error {
+	return ErrNotSupported
+}
+
+// DemoteVolume demotes a mirrored volume to non-primary.
+func (d *common) DemoteVolume(vol Volume) error {
+	return ErrNotSupported
+}
+
+// FenceVolume cuts off the cluster member with the given pool configuration from a volume.
+func (d *common) FenceVolume(vol Volume, memberConfig map[string]string) error {
+	return ErrNotSupported
+}
+
 // SetVolumeQuota applies a size limit on volume.
 func (d *common) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
 	return ErrNotSupported
@@ -387,6 +418,11 @@ func (d *common) GetVolumeDiskPath(vol Volume) (string, error) {
 	return "", ErrNotSupported
 }
 
+// GetVolumeIOStats returns the I/O counters of a volume's block device.
+func (d *common) GetVolumeIOStats(vol Volume) (*VolumeIOStats, error) {
+	return nil, ErrNotSupported
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *common) ListVolumes() ([]Volume, error) {
 	return nil, ErrNotSupported
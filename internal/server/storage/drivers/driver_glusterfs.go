@@ -0,0 +1,14 @@
+package drivers
+
+// glusterfsType is the GlusterFS network filesystem.
+var glusterfsType = remoteFSType{
+	name:           "glusterfs",
+	label:          "GlusterFS",
+	sourceLabel:    "volume",
+	validateSource: glusterfsIsVolume,
+}
+
+// glusterfs stores its volumes as directories on an existing GlusterFS volume, mounted through its FUSE client.
+type glusterfs struct {
+	remoteFS
+}
@@ -0,0 +1,20 @@
+package drivers
+
+import (
+	"errors"
+	"strings"
+)
+
+// glusterfsIsVolume validates a GlusterFS volume in the `<host>:/<volume>[/<subdir>]` form.
+func glusterfsIsVolume(value string) error {
+	host, path, ok := strings.Cut(value, ":")
+	if !ok || host == "" {
+		return errors.New("GlusterFS volume must be in the <host>:/<volume> form")
+	}
+
+	if !strings.HasPrefix(path, "/") || strings.Trim(path, "/") == "" {
+		return errors.New("GlusterFS volume name is missing")
+	}
+
+	return nil
+}
@@ -70,6 +70,9 @@ const LinstorAuxType = "Aux/Incus/type"
 // LinstorAuxContentType represents the AuxProp storing the Incus volume content type.
 const LinstorAuxContentType = "Aux/Incus/content-type"
 
+// DrbdQuorumProperty represents the resource definition property holding the DRBD quorum policy.
+const DrbdQuorumProperty = "DrbdOptions/Resource/quorum"
+
 // errResourceDefinitionNotFound indicates that a resource definition could not be found in Linstor.
 var errResourceDefinitionNotFound = errors.New("Resource definition not found")
 
@@ -1144,3 +1147,29 @@ func (d *linstor) parseContentType(s string) (*ContentType, bool) {
 func (d *linstor) generateUUIDWithPrefix() string {
 	return d.config[LinstorVolumePrefixConfigKey] + strings.ReplaceAll(uuid.NewString(), "-", "")
 }
+
+// linstorFenceCheck checks that DRBD quorum keeps the unreachable nodes from writing to a resource.
+// Quorum must be enabled on the resource and a majority of its replicas must be on online nodes, in which case
+// the remaining replicas can't reach quorum on their own. No replica may be in use anymore.
+func linstorFenceCheck(quorum string, resources []linstorClient.ResourceWithVolumes, onlineNodes []string) error {
+	if quorum == "" || quorum == "off" {
+		return errors.New("DRBD quorum isn't enabled on the volume")
+	}
+
+	online := 0
+	for _, r := range resources {
+		if r.State != nil && r.State.InUse != nil && *r.State.InUse {
+			return fmt.Errorf("Volume is still in use on %q", r.NodeName)
+		}
+
+		if slices.Contains(onlineNodes, r.NodeName) {
+			online++
+		}
+	}
+
+	if online*2 <= len(resources) {
+		return fmt.Errorf("Only %d out of %d volume replicas are on online nodes, not enough for DRBD quorum", online, len(resources))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package drivers
+
+import (
+	"testing"
+
+	linstorClient "github.com/LINBIT/golinstor/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_linstorFenceCheck(t *testing.T) {
+	inUse := true
+	notInUse := false
+
+	resource := func(node string, used *bool) linstorClient.ResourceWithVolumes {
+		r := linstorClient.ResourceWithVolumes{}
+		r.NodeName = node
+		r.State = &linstorClient.ResourceState{InUse: used}
+
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		quorum      string
+		resources   []linstorClient.ResourceWithVolumes
+		onlineNodes []string
+		wantErr     bool
+	}{
+		{
+			name:        "Majority of replicas online",
+			quorum:      "majority",
+			resources:   []linstorClient.ResourceWithVolumes{resource("node1", &notInUse), resource("node2", &notInUse), resource("node3", nil)},
+			onlineNodes: []string{"node2", "node3"},
+		},
+		{
+			name:        "Quorum disabled",
+			quorum:      "off",
+			resources:   []linstorClient.ResourceWithVolumes{resource("node1", &notInUse), resource("node2", &notInUse), resource("node3", nil)},
+			onlineNodes: []string{"node2", "node3"},
+			wantErr:     true,
+		},
+		{
+			name:        "Quorum unset",
+			resources:   []linstorClient.ResourceWithVolumes{resource("node1", &notInUse), resource("node2", &notInUse), resource("node3", nil)},
+			onlineNodes: []string{"node2", "node3"},
+			wantErr:     true,
+		},
+		{
+			name:        "Half of the replicas online",
+			quorum:      "majority",
+			resources:   []linstorClient.ResourceWithVolumes{resource("node1", &notInUse), resource("node2", &notInUse)},
+			onlineNodes: []string{"node2"},
+			wantErr:     true,
+		},
+		{
+			name:        "Still in use",
+			quorum:      "majority",
+			resources:   []linstorClient.ResourceWithVolumes{resource("node1", &inUse), resource("node2", &notInUse), resource("node3", nil)},
+			onlineNodes: []string{"node2", "node3"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := linstorFenceCheck(tt.quorum, tt.resources, tt.onlineNodes)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
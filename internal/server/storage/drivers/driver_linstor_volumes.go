@@ -1010,6 +1010,47 @@ func (d *linstor) GetVolumeUsage(vol Volume) (int64, error) {
 	return usageInBytes, nil
 }
 
+// FenceVolume makes sure that the cluster member which used the volume can't write to it anymore.
+// LINSTOR can't cut off an unreachable node, so this relies on DRBD quorum: the volume must have quorum enabled
+// and a majority of its replicas must be on online nodes, leaving the unreachable node without quorum.
+func (d *linstor) FenceVolume(vol Volume, memberConfig map[string]string) error {
+	linstor, err := d.state.Linstor()
+	if err != nil {
+		return err
+	}
+
+	resourceDefinition, err := d.getResourceDefinition(vol, false)
+	if err != nil {
+		return err
+	}
+
+	resources, err := linstor.Client.Resources.GetResourceView(context.TODO(), &linstorClient.ListOpts{
+		Resource: []string{resourceDefinition.Name},
+	})
+	if err != nil {
+		return fmt.Errorf("Unable to get the resources for the resource definition: %w", err)
+	}
+
+	nodes, err := linstor.Client.Nodes.GetAll(context.TODO())
+	if err != nil {
+		return fmt.Errorf("Unable to get the LINSTOR nodes: %w", err)
+	}
+
+	onlineNodes := []string{}
+	for _, node := range nodes {
+		if node.ConnectionStatus == "ONLINE" {
+			onlineNodes = append(onlineNodes, node.Name)
+		}
+	}
+
+	err = linstorFenceCheck(resourceDefinition.Props[DrbdQuorumProperty], resources, onlineNodes)
+	if err != nil {
+		return fmt.Errorf("Failed fencing volume %q: %w", vol.Name(), err)
+	}
+
+	return nil
+}
+
 // SetVolumeQuota applies a size limit on volume.
 // Does nothing if supplied with an empty/zero size.
 func (d *linstor) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error {
@@ -1052,12 +1093,9 @@ func (d *linstor) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool
 		fsType := vol.ConfigBlockFilesystem()
 
 		if sizeBytes < oldSizeBytes {
-			if !filesystemTypeCanBeShrunk(fsType) {
-				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
-			}
-
-			if inUse {
-				return ErrInUse // We don't allow online shrinking of filesystem volumes.
+			err = checkFileSystemShrink(fsType, vol, sizeBytes, inUse)
+			if err != nil {
+				return err
 			}
 
 			// Shrink filesystem first. Pass allowUnsafeResize to allow disabling of filesystem
@@ -451,12 +451,9 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 		fsType := vol.ConfigBlockFilesystem()
 
 		if sizeBytes < oldSizeBytes {
-			if !filesystemTypeCanBeShrunk(fsType) {
-				return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
-			}
-
-			if inUse {
-				return ErrInUse // We don't allow online shrinking of filesystem volumes.
+			err = checkFileSystemShrink(fsType, vol, sizeBytes, inUse)
+			if err != nil {
+				return err
 			}
 
 			// Activate volume if needed.
@@ -479,14 +476,27 @@ func (d *lvm) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			// -f onto resize2fs as well.
 			err = shrinkFileSystem(fsType, volDevPath, vol, sizeBytes, allowUnsafeResize)
 			if err != nil {
-				_, _ = d.deactivateVolume(vol)
+				if !inUse {
+					_, _ = d.deactivateVolume(vol)
+				}
+
 				return err
 			}
 
-			// Deactivate the volume for resizing.
-			_, err = d.deactivateVolume(vol)
-			if err != nil {
-				return err
+			if inUse {
+				// Mounted volumes can't be deactivated, so get exclusive mode instead.
+				release, err := d.acquireExclusive(vol)
+				if err != nil {
+					return err
+				}
+
+				defer release()
+			} else {
+				// Deactivate the volume for resizing.
+				_, err = d.deactivateVolume(vol)
+				if err != nil {
+					return err
+				}
 			}
 
 			l.Debug("Logical volume filesystem shrunk")
@@ -611,6 +621,21 @@ func (d *lvm) GetVolumeDiskPath(vol Volume) (string, error) {
 	return "", ErrNotSupported
 }
 
+// GetVolumeIOStats returns the I/O counters of a volume's block device.
+// No counters are returned when the logical volume isn't currently active.
+func (d *lvm) GetVolumeIOStats(vol Volume) (*VolumeIOStats, error) {
+	if !vol.IsVMBlock() && !(vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		return nil, ErrNotSupported
+	}
+
+	volDevPath := d.lvmDevPath(d.config["lvm.vg_name"], vol.volType, vol.contentType, vol.name)
+	if !linux.IsBlockdevPath(volDevPath) {
+		return nil, nil
+	}
+
+	return blockDeviceIOStats(volDevPath)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *lvm) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -0,0 +1,14 @@
+package drivers
+
+// nfsType is the NFS network filesystem.
+var nfsType = remoteFSType{
+	name:           "nfs",
+	label:          "NFS",
+	sourceLabel:    "export",
+	validateSource: nfsIsExport,
+}
+
+// nfs stores its volumes as directories on an existing NFS export.
+type nfs struct {
+	remoteFS
+}
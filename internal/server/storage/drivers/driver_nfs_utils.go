@@ -0,0 +1,20 @@
+package drivers
+
+import (
+	"errors"
+	"strings"
+)
+
+// nfsIsExport validates an NFS export in the `<host>:<path>` form.
+func nfsIsExport(value string) error {
+	host, path, ok := strings.Cut(value, ":")
+	if !ok || host == "" {
+		return errors.New("NFS export must be in the <host>:<path> form")
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return errors.New("NFS export path must be absolute")
+	}
+
+	return nil
+}
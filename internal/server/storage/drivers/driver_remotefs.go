@@ -0,0 +1,275 @@
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/internal/migration"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	localMigration "github.com/lxc/incus/v6/internal/server/migration"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// remoteFSType describes the network filesystem used by a remoteFS based driver.
+type remoteFSType struct {
+	// Name of the driver, also used as the prefix of its configuration keys and to find its mount helper.
+	name string
+
+	// Human readable name of the filesystem and of what is mounted from it (for error messages).
+	label       string
+	sourceLabel string
+
+	// Validator for the source of the pool.
+	validateSource func(value string) error
+}
+
+// remoteFS stores its volumes as directories on a network filesystem mounted on all servers, re-using the dir driver
+// for volume handling. As with the dir driver, snapshots are emulated by copying the volume content.
+// Quotas aren't supported as the network filesystems don't support project quotas.
+type remoteFS struct {
+	dir
+
+	fs remoteFSType
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *remoteFS) load() error {
+	// Register the patches.
+	err := d.dir.load()
+	if err != nil {
+		return err
+	}
+
+	// Validate the required binaries.
+	_, err = exec.LookPath(d.mountTool())
+	if err != nil {
+		return fmt.Errorf("Required tool '%s' is missing", d.mountTool())
+	}
+
+	return nil
+}
+
+// isRemote returns true indicating this driver uses remote storage.
+func (d *remoteFS) isRemote() bool {
+	return true
+}
+
+// Info returns info about the driver and its environment.
+func (d *remoteFS) Info() Info {
+	return Info{
+		Name:                         d.fs.name,
+		Version:                      "1",
+		DefaultVMBlockFilesystemSize: deviceConfig.DefaultVMBlockFilesystemSize,
+		OptimizedImages:              false,
+		PreservesInodes:              false,
+		Remote:                       d.isRemote(),
+		VolumeTypes:                  []VolumeType{VolumeTypeCustom, VolumeTypeImage, VolumeTypeContainer},
+		VolumeMultiNode:              d.isRemote(),
+		BlockBacking:                 false,
+		RunningCopyFreeze:            true,
+		DirectIO:                     false,
+		IOUring:                      false,
+		MountedRoot:                  true,
+		Buckets:                      false,
+	}
+}
+
+// FillConfig populates the storage pool's configuration file with the default values.
+func (d *remoteFS) FillConfig() error {
+	return nil
+}
+
+// Create is called during pool creation and is effectively using an empty driver struct.
+// WARNING: The Create() function cannot rely on any of the struct attributes being set.
+func (d *remoteFS) Create() error {
+	pathKey := d.fs.name + ".path"
+
+	// Config validation.
+	if d.config["source"] == "" {
+		return fmt.Errorf("Missing required source %s", d.fs.sourceLabel)
+	}
+
+	if d.config[pathKey] != "" && d.config[pathKey] != d.config["source"] {
+		return fmt.Errorf("%s must match the source", pathKey)
+	}
+
+	d.config[pathKey] = d.config["source"]
+
+	// Create a temporary mountpoint.
+	mountPath, err := os.MkdirTemp("", fmt.Sprintf("incus_%s_", d.fs.name))
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory under: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(mountPath) }()
+
+	err = os.Chmod(mountPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to chmod '%s': %w", mountPath, err)
+	}
+
+	mountPoint := filepath.Join(mountPath, "mount")
+
+	err = os.Mkdir(mountPoint, 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory '%s': %w", mountPoint, err)
+	}
+
+	// Mount the source.
+	err = d.mount(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _, _ = forceUnmount(mountPoint) }()
+
+	// Check that the source is empty.
+	ok, _ := internalUtil.PathIsEmpty(mountPoint)
+	if !ok {
+		return fmt.Errorf("Only empty %s %ss can be used as a storage pool", d.fs.label, d.fs.sourceLabel)
+	}
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *remoteFS) Delete(op *operations.Operation) error {
+	// Mount the source if needed.
+	_, err := d.Mount()
+	if err != nil {
+		return err
+	}
+
+	// On delete, wipe everything in the source.
+	err = wipeDirectory(GetPoolMountPath(d.name))
+	if err != nil {
+		return err
+	}
+
+	// Unmount the path.
+	_, err = d.Unmount()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *remoteFS) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		d.fs.name + ".path":          validate.Optional(d.fs.validateSource),
+		d.fs.name + ".mount_options": validate.IsAny,
+	}
+
+	return d.validatePool(config, rules, nil)
+}
+
+// Update applies any driver changes required from a configuration change.
+func (d *remoteFS) Update(changedConfig map[string]string) error {
+	_, ok := changedConfig[d.fs.name+".path"]
+	if ok {
+		return fmt.Errorf("%s.path cannot be changed", d.fs.name)
+	}
+
+	return nil
+}
+
+// Mount mounts the storage pool.
+func (d *remoteFS) Mount() (bool, error) {
+	path := GetPoolMountPath(d.name)
+
+	// Check if already mounted.
+	if linux.IsMountPoint(path) {
+		return false, nil
+	}
+
+	err := d.mount(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Unmount unmounts the storage pool.
+func (d *remoteFS) Unmount() (bool, error) {
+	return forceUnmount(GetPoolMountPath(d.name))
+}
+
+// ValidateVolume validates the supplied volume config. Optionally removes invalid keys from the volume's config.
+func (d *remoteFS) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
+	if vol.volType == VolumeTypeCustom && vol.contentType != ContentTypeFS {
+		return fmt.Errorf("Only filesystem custom volumes are supported on %s storage pools", d.fs.label)
+	}
+
+	// The dir driver relies on project quotas which network filesystems don't support.
+	if vol.config["size"] != "" {
+		return fmt.Errorf("Size limits aren't supported on %s storage pools", d.fs.label)
+	}
+
+	return d.dir.ValidateVolume(vol, removeUnknownKeys)
+}
+
+// MigrationTypes returns the supported migration types and options supported by the driver.
+func (d *remoteFS) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
+	var rsyncFeatures []string
+
+	// Do not pass compression argument to rsync if the associated
+	// config key, that is rsync.compression, is set to false.
+	if util.IsFalse(d.Config()["rsync.compression"]) {
+		rsyncFeatures = []string{"delete", "bidirectional"}
+	} else {
+		rsyncFeatures = []string{"delete", "compress", "bidirectional"}
+	}
+
+	if contentType != ContentTypeFS {
+		return nil
+	}
+
+	// Do not support xattr transfer on network filesystems.
+	return []localMigration.Type{
+		{
+			FSType:   migration.MigrationFSType_RSYNC,
+			Features: rsyncFeatures,
+		},
+	}
+}
+
+// mountTool returns the mount helper of the filesystem.
+func (d *remoteFS) mountTool() string {
+	return "mount." + d.fs.name
+}
+
+// mount mounts the source of the pool on the target path with the additional mount options.
+// The mount is done through the filesystem's mount helper so that host name resolution and protocol negotiation
+// are handled for us.
+func (d *remoteFS) mount(target string) error {
+	source := d.config[d.fs.name+".path"]
+
+	err := d.fs.validateSource(source)
+	if err != nil {
+		return err
+	}
+
+	args := []string{source, target}
+
+	options := d.config[d.fs.name+".mount_options"]
+	if options != "" {
+		args = append(args, "-o", options)
+	}
+
+	_, err = subprocess.RunCommand(d.mountTool(), args...)
+	if err != nil {
+		return fmt.Errorf("Failed mounting %s %s %q on %q: %w", d.fs.label, d.fs.sourceLabel, source, target, err)
+	}
+
+	return nil
+}
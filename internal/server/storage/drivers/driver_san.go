@@ -0,0 +1,166 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+// san uses a shared LVM volume group on top of a LUN (or NVMe namespace) exported by an external SAN.
+// The driver takes care of connecting to the target (iSCSI or NVMe over TCP) on every server and of
+// picking the multipath device when available, the volumes themselves are handled by the lvm driver.
+// The whole pool uses a single pre-provisioned LUN, volumes aren't mapped to their own LUN as that would require
+// managing the SAN itself through its vendor-specific API.
+type san struct {
+	lvm
+}
+
+// load is used to run one-time action per-driver rather than per-pool.
+func (d *san) load() error {
+	err := d.lvm.load()
+	if err != nil {
+		return err
+	}
+
+	// The protocol specific tools are checked on connection as they depend on the pool config.
+	return nil
+}
+
+// Info returns info about the driver and its environment.
+func (d *san) Info() Info {
+	info := d.lvm.Info()
+	info.Name = "san"
+
+	return info
+}
+
+// Create creates the storage pool on the storage device.
+func (d *san) Create() error {
+	if d.config["source"] != "" {
+		return errors.New("The source of a SAN storage pool cannot be set, it is determined from the target")
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	devPath, err := d.sanConnect()
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = d.sanDisconnect() })
+
+	// Create the shared volume group on the target device.
+	d.config["source"] = devPath
+
+	err = d.lvm.Create()
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// Delete removes the storage pool from the storage device.
+func (d *san) Delete(op *operations.Operation) error {
+	err := d.lvm.Delete(op)
+	if err != nil {
+		return err
+	}
+
+	return d.sanDisconnect()
+}
+
+// Validate checks that all provide keys are supported and that no conflicting or missing configuration is present.
+func (d *san) Validate(config map[string]string) error {
+	rules := map[string]func(value string) error{
+		"san.protocol":  validate.Optional(validate.IsOneOf("iscsi", "nvme")),
+		"san.target":    validate.IsAny,
+		"san.addresses": validate.Optional(validate.IsListOf(validate.IsListenAddress(false, false, false))),
+		"san.lun":       validate.Optional(validate.IsUint32),
+	}
+
+	// Validate the SAN keys and leave the rest to the lvm driver.
+	lvmConfig := maps.Clone(config)
+	for k, validator := range rules {
+		err := validator(config[k])
+		if err != nil {
+			return fmt.Errorf("Invalid value for option %q: %w", k, err)
+		}
+
+		delete(lvmConfig, k)
+	}
+
+	if config["san.target"] == "" {
+		return errors.New(`The "san.target" option is required`)
+	}
+
+	if config["san.addresses"] == "" {
+		return errors.New(`The "san.addresses" option is required`)
+	}
+
+	return d.lvm.Validate(lvmConfig)
+}
+
+// Update updates the storage pool settings.
+func (d *san) Update(changedConfig map[string]string) error {
+	for k := range changedConfig {
+		if strings.HasPrefix(k, "san.") {
+			return fmt.Errorf("%s cannot be changed", k)
+		}
+	}
+
+	return d.lvm.Update(changedConfig)
+}
+
+// Mount connects to the SAN target and mounts the storage pool.
+func (d *san) Mount() (bool, error) {
+	_, err := d.sanConnect()
+	if err != nil {
+		return false, err
+	}
+
+	return d.lvm.Mount()
+}
+
+// sanProtocolTools returns the tools required for the configured protocol.
+func (d *san) sanProtocolTools() []string {
+	if d.config["san.protocol"] == "nvme" {
+		return []string{"nvme"}
+	}
+
+	return []string{"iscsiadm"}
+}
+
+// sanConnect connects to the SAN target over all configured addresses and returns the path of the block device.
+func (d *san) sanConnect() (string, error) {
+	for _, tool := range d.sanProtocolTools() {
+		_, err := exec.LookPath(tool)
+		if err != nil {
+			return "", fmt.Errorf("Required tool %q is missing", tool)
+		}
+	}
+
+	if d.config["san.protocol"] == "nvme" {
+		return d.nvmeConnect()
+	}
+
+	return d.iscsiConnect()
+}
+
+// sanDisconnect disconnects from the SAN target.
+func (d *san) sanDisconnect() error {
+	if d.config["san.protocol"] == "nvme" {
+		return d.nvmeDisconnect()
+	}
+
+	return d.iscsiDisconnect()
+}
@@ -0,0 +1,240 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/linux"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+const (
+	// iscsiDefaultPort is the default iSCSI portal port.
+	iscsiDefaultPort = "3260"
+
+	// nvmeDefaultPort is the default NVMe over TCP port.
+	nvmeDefaultPort = "4420"
+
+	// iscsiErrSessionExists is the iscsiadm exit status when already logged into the target.
+	iscsiErrSessionExists = 15
+
+	// sanDeviceTimeout is how long to wait for the block device to show up after connecting.
+	sanDeviceTimeout = 10 * time.Second
+)
+
+// sanAddress is a target address.
+type sanAddress struct {
+	host string
+	port string
+}
+
+// String returns the address in the host:port form.
+func (a sanAddress) String() string {
+	return net.JoinHostPort(a.host, a.port)
+}
+
+// sanAddressList is a list of target addresses.
+type sanAddressList []sanAddress
+
+// parse adds the comma-separated target addresses to the list, applying the default port.
+func (l *sanAddressList) parse(addresses string, defaultPort string) {
+	for _, address := range util.SplitNTrimSpace(addresses, ",", -1, true) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			host = strings.Trim(address, "[]")
+			port = defaultPort
+		}
+
+		*l = append(*l, sanAddress{host: host, port: port})
+	}
+}
+
+// sanLUN returns the configured LUN (or NVMe namespace ID).
+func (d *san) sanLUN() string {
+	if d.config["san.lun"] != "" {
+		return d.config["san.lun"]
+	}
+
+	if d.config["san.protocol"] == "nvme" {
+		return "1"
+	}
+
+	return "0"
+}
+
+// sanWaitDevice waits for any of the candidate device paths to show up and returns the first one found.
+func sanWaitDevice(candidates func() []string) (string, error) {
+	waitUntil := time.Now().Add(sanDeviceTimeout)
+	for {
+		for _, devPath := range candidates() {
+			if linux.IsBlockdevPath(devPath) {
+				return devPath, nil
+			}
+		}
+
+		if time.Now().After(waitUntil) {
+			return "", errors.New("Timed out waiting for the SAN block device to appear")
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// sanMultipathDevice returns the multipath device holding the given block device (if any).
+func sanMultipathDevice(devPath string) string {
+	target, err := filepath.EvalSymlinks(devPath)
+	if err != nil {
+		return ""
+	}
+
+	holders, err := os.ReadDir(filepath.Join("/sys/class/block", filepath.Base(target), "holders"))
+	if err != nil {
+		return ""
+	}
+
+	for _, holder := range holders {
+		uuid, err := os.ReadFile(filepath.Join("/sys/class/block", holder.Name(), "dm", "uuid"))
+		if err != nil || !strings.HasPrefix(string(uuid), "mpath-") {
+			continue
+		}
+
+		name, err := os.ReadFile(filepath.Join("/sys/class/block", holder.Name(), "dm", "name"))
+		if err != nil {
+			continue
+		}
+
+		return filepath.Join("/dev/mapper", strings.TrimSpace(string(name)))
+	}
+
+	return ""
+}
+
+// iscsiConnect logs into the iSCSI target on all the portals and returns the path of the LUN's block device.
+func (d *san) iscsiConnect() (string, error) {
+	var addresses sanAddressList
+	addresses.parse(d.config["san.addresses"], iscsiDefaultPort)
+
+	target := d.config["san.target"]
+	var paths []string
+
+	for _, address := range addresses {
+		// Discover the target on the portal so that a node record exists for it.
+		_, err := subprocess.RunCommand("iscsiadm", "--mode", "discovery", "--type", "sendtargets", "--portal", address.String())
+		if err != nil {
+			return "", fmt.Errorf("Failed discovering iSCSI targets on %q: %w", address, err)
+		}
+
+		_, err = subprocess.RunCommand("iscsiadm", "--mode", "node", "--targetname", target, "--portal", address.String(), "--login")
+		if err != nil {
+			status, _ := linux.ExitStatus(err)
+			if status != iscsiErrSessionExists {
+				return "", fmt.Errorf("Failed logging into iSCSI target %q on %q: %w", target, address, err)
+			}
+		}
+
+		paths = append(paths, fmt.Sprintf("/dev/disk/by-path/ip-%s-iscsi-%s-lun-%s", address, target, d.sanLUN()))
+	}
+
+	devPath, err := sanWaitDevice(func() []string { return paths })
+	if err != nil {
+		return "", err
+	}
+
+	// Use the multipath device when the LUN is reachable through it.
+	mpathPath := sanMultipathDevice(devPath)
+	if mpathPath != "" {
+		return mpathPath, nil
+	}
+
+	if len(paths) > 1 {
+		d.logger.Warn("No multipath device found for the iSCSI LUN, using a single path", logger.Ctx{"target": target, "dev": devPath})
+	}
+
+	return devPath, nil
+}
+
+// iscsiDisconnect logs out of the iSCSI target on all the portals.
+func (d *san) iscsiDisconnect() error {
+	var addresses sanAddressList
+	addresses.parse(d.config["san.addresses"], iscsiDefaultPort)
+
+	for _, address := range addresses {
+		_, err := subprocess.RunCommand("iscsiadm", "--mode", "node", "--targetname", d.config["san.target"], "--portal", address.String(), "--logout")
+		if err != nil {
+			return fmt.Errorf("Failed logging out of iSCSI target %q on %q: %w", d.config["san.target"], address, err)
+		}
+	}
+
+	return nil
+}
+
+// nvmeSubsystem returns the sysfs path of the NVMe subsystem of the target (if connected).
+func (d *san) nvmeSubsystem() string {
+	subsystems, err := filepath.Glob("/sys/class/nvme-subsystem/*/subsysnqn")
+	if err != nil {
+		return ""
+	}
+
+	for _, subsystem := range subsystems {
+		nqn, err := os.ReadFile(subsystem)
+		if err != nil {
+			continue
+		}
+
+		if strings.TrimSpace(string(nqn)) == d.config["san.target"] {
+			return filepath.Dir(subsystem)
+		}
+	}
+
+	return ""
+}
+
+// nvmeConnect connects to the NVMe over TCP target on all the addresses and returns the path of the namespace's block device.
+// Multipath is handled by the kernel's native NVMe multipathing which exposes a single device per namespace.
+func (d *san) nvmeConnect() (string, error) {
+	var addresses sanAddressList
+	addresses.parse(d.config["san.addresses"], nvmeDefaultPort)
+
+	target := d.config["san.target"]
+
+	for _, address := range addresses {
+		_, err := subprocess.RunCommand("nvme", "connect", "--transport", "tcp", "--traddr", address.host, "--trsvcid", address.port, "--nqn", target)
+		if err != nil && !strings.Contains(err.Error(), "already connected") {
+			return "", fmt.Errorf("Failed connecting to NVMe target %q on %q: %w", target, address, err)
+		}
+	}
+
+	return sanWaitDevice(func() []string {
+		subsystem := d.nvmeSubsystem()
+		if subsystem == "" {
+			return nil
+		}
+
+		// Namespace block devices are named nvme<subsystem>n<namespace>.
+		namespaces, _ := filepath.Glob(filepath.Join(subsystem, fmt.Sprintf("nvme*n%s", d.sanLUN())))
+
+		paths := make([]string, 0, len(namespaces))
+		for _, namespace := range namespaces {
+			paths = append(paths, filepath.Join("/dev", filepath.Base(namespace)))
+		}
+
+		return paths
+	})
+}
+
+// nvmeDisconnect disconnects from the NVMe over TCP target.
+func (d *san) nvmeDisconnect() error {
+	_, err := subprocess.RunCommand("nvme", "disconnect", "--nqn", d.config["san.target"])
+	if err != nil {
+		return fmt.Errorf("Failed disconnecting from NVMe target %q: %w", d.config["san.target"], err)
+	}
+
+	return nil
+}
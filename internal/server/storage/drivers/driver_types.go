@@ -29,3 +29,11 @@ type VolumeFiller struct {
 
 	Fingerprint string // If the Filler will unpack an image, it should be this fingerprint.
 }
+
+// VolumeIOStats represents the I/O counters of a volume's block device.
+type VolumeIOStats struct {
+	ReadBytes       uint64
+	ReadsCompleted  uint64
+	WrittenBytes    uint64
+	WritesCompleted uint64
+}
@@ -542,7 +542,17 @@ func (d *zfs) Validate(config map[string]string) error {
 
 			return validate.IsBool(value)
 		}),
-		"zfs.export": validate.Optional(validate.IsBool),
+		"zfs.export":             validate.Optional(validate.IsBool),
+		"zfs.send_raw":           validate.Optional(validate.IsBool),
+		"zfs.encryption.keyfile": validate.Optional(validate.IsAbsFilePath),
+	}
+
+	if util.IsTrue(config["zfs.send_raw"]) && !zfsRaw {
+		return errors.New("Raw send requires ZFS 0.8.0 or higher")
+	}
+
+	if util.IsTrue(config["volume.zfs.encryption"]) && !zfsRaw {
+		return errors.New("Encryption requires ZFS 0.8.0 or higher")
 	}
 
 	return d.validatePool(config, rules, d.commonVolumeRules())
@@ -566,16 +576,7 @@ func (d *zfs) Update(changedConfig map[string]string) error {
 		}
 
 		// Resize loop file
-		f, err := os.OpenFile(loopPath, os.O_RDWR, 0o600)
-		if err != nil {
-			return err
-		}
-
-		defer func() { _ = f.Close() }()
-
-		sizeBytes, _ := units.ParseByteSizeString(size)
-
-		err = f.Truncate(sizeBytes)
+		err := growLoopFile(loopPath, size)
 		if err != nil {
 			return err
 		}
@@ -739,6 +740,47 @@ func (d *zfs) GetResources() (*api.ResourcesStoragePool, error) {
 	return &res, nil
 }
 
+// GetHealth returns the health of the zpool.
+func (d *zfs) GetHealth() (*api.StoragePoolStateHealth, error) {
+	poolName := strings.Split(d.config["zfs.pool_name"], "/")[0]
+
+	out, err := subprocess.RunCommand("zpool", "status", "-p", poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, errCount, scrub := zfsParsePoolStatus(out)
+
+	health := &api.StoragePoolStateHealth{
+		Description: state,
+		Errors:      errCount,
+		ScrubStatus: scrub,
+	}
+
+	switch {
+	case state == "ONLINE" && errCount == 0:
+		health.Status = "healthy"
+	case state == "ONLINE" || state == "DEGRADED":
+		health.Status = "degraded"
+	default:
+		health.Status = "failed"
+	}
+
+	return health, nil
+}
+
+// Scrub starts a scrub of the zpool.
+func (d *zfs) Scrub() error {
+	poolName := strings.Split(d.config["zfs.pool_name"], "/")[0]
+
+	_, err := subprocess.RunCommand("zpool", "scrub", poolName)
+	if err != nil {
+		return fmt.Errorf("Failed to scrub zpool %q: %w", poolName, err)
+	}
+
+	return nil
+}
+
 // MigrationType returns the type of transfer methods to be used when doing migrations between pools in preference order.
 func (d *zfs) MigrationTypes(contentType ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []localMigration.Type {
 	var rsyncFeatures []string
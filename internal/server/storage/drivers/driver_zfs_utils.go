@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -16,6 +18,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/migration"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/subprocess"
 	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
@@ -28,6 +31,12 @@ const (
 	// zfsISOVolSuffix suffix used for iso content type volumes.
 	zfsISOVolSuffix = ".iso"
 
+	// zfsEncryptionKeyFile is the name of the encryption key file managed by Incus in the pool directory.
+	zfsEncryptionKeyFile = ".zfs-encryption.key"
+
+	// zfsEncryptionKeySize is the size of the raw encryption keys.
+	zfsEncryptionKeySize = 32
+
 	// zfsMinBlocksize is a minimum value for recordsize and volblocksize properties.
 	zfsMinBlocksize = 512
 
@@ -237,6 +246,47 @@ func (d *zfs) setBlocksize(vol Volume, size int64) error {
 	return nil
 }
 
+// datasetPropertiesFromConfig returns the dataset properties to set based on the volume's config.
+func (d *zfs) datasetPropertiesFromConfig(vol Volume) []string {
+	var props []string
+
+	compression := vol.ExpandedConfig("zfs.compression")
+	if compression != "" {
+		props = append(props, fmt.Sprintf("compression=%s", compression))
+	}
+
+	dedup := vol.ExpandedConfig("zfs.dedup")
+	if dedup != "" {
+		props = append(props, fmt.Sprintf("dedup=%s", dedup))
+	}
+
+	return props
+}
+
+// setDatasetPropertiesFromConfig applies the compression and dedup properties from the volume's config.
+func (d *zfs) setDatasetPropertiesFromConfig(vol Volume) error {
+	props := d.datasetPropertiesFromConfig(vol)
+	if len(props) == 0 {
+		return nil
+	}
+
+	return d.setDatasetProperties(d.dataset(vol, false), props...)
+}
+
+// setDatasetProperty sets the property on the dataset, or makes it inherit its parent's value when empty.
+func (d *zfs) setDatasetProperty(dataset string, key string, value string) error {
+	if value == "" {
+		_, err := subprocess.RunCommand("zfs", "inherit", key, dataset)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	return d.setDatasetProperties(dataset, fmt.Sprintf("%s=%s", key, value))
+}
+
 func (d *zfs) getDatasetProperty(dataset string, key string) (string, error) {
 	output, err := subprocess.RunCommand("zfs", "get", "-H", "-p", "-o", "value", key, dataset)
 	if err != nil {
@@ -308,6 +358,117 @@ func (d *zfs) initialDatasets() []string {
 	return entries
 }
 
+// needsEncryption returns whether the volume should be created as an encrypted dataset.
+// Image volumes are shared between instances and are never encrypted.
+func (d *zfs) needsEncryption(vol Volume) bool {
+	return vol.volType != VolumeTypeImage && util.IsTrue(vol.config["zfs.encryption"])
+}
+
+// isEncrypted returns whether the dataset uses ZFS native encryption.
+func (d *zfs) isEncrypted(dataset string) bool {
+	encryption, err := d.getDatasetProperty(dataset, "encryption")
+	if err != nil {
+		return false
+	}
+
+	return encryption != "" && encryption != "-" && encryption != "off"
+}
+
+// encryptionKeyPath returns the path to the key file used for the encrypted datasets of the pool.
+func (d *zfs) encryptionKeyPath() string {
+	if d.config["zfs.encryption.keyfile"] != "" {
+		return d.config["zfs.encryption.keyfile"]
+	}
+
+	return filepath.Join(GetPoolMountPath(d.name), zfsEncryptionKeyFile)
+}
+
+// ensureEncryptionKey makes sure the key file of the pool exists, generating one if managed by Incus.
+func (d *zfs) ensureEncryptionKey() (string, error) {
+	keyPath := d.encryptionKeyPath()
+	if util.PathExists(keyPath) {
+		return keyPath, nil
+	}
+
+	if d.config["zfs.encryption.keyfile"] != "" {
+		return "", fmt.Errorf("Encryption key file %q doesn't exist", keyPath)
+	}
+
+	key := make([]byte, zfsEncryptionKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed generating encryption key: %w", err)
+	}
+
+	err = os.WriteFile(keyPath, key, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("Failed writing encryption key file %q: %w", keyPath, err)
+	}
+
+	return keyPath, nil
+}
+
+// encryptionOptions returns the dataset properties needed to create an encrypted dataset for the volume.
+func (d *zfs) encryptionOptions(vol Volume) ([]string, error) {
+	if !d.needsEncryption(vol) {
+		return nil, nil
+	}
+
+	keyPath, err := d.ensureEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{"encryption=on", "keyformat=raw", fmt.Sprintf("keylocation=file://%s", keyPath)}, nil
+}
+
+// loadEncryptionKey loads the encryption key of the dataset if it's encrypted and the key isn't loaded yet.
+func (d *zfs) loadEncryptionKey(dataset string) error {
+	props, err := d.getDatasetProperties(dataset, "encryptionroot", "keystatus")
+	if err != nil {
+		return err
+	}
+
+	root := props["encryptionroot"]
+	if root == "" || root == "-" || props["keystatus"] == "available" {
+		return nil
+	}
+
+	// Datasets received from raw streams expect their key to be prompted for.
+	// Point them to the pool's key file instead when there is one.
+	keyLocation, err := d.getDatasetProperty(root, "keylocation")
+	if err != nil {
+		return err
+	}
+
+	if keyLocation == "prompt" {
+		keyPath := d.encryptionKeyPath()
+		if !util.PathExists(keyPath) {
+			return fmt.Errorf("Encryption key of %q isn't loaded, use \"zfs load-key\" to load it", root)
+		}
+
+		err = d.setDatasetProperties(root, fmt.Sprintf("keylocation=file://%s", keyPath))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = subprocess.RunCommand("zfs", "load-key", root)
+	if err != nil {
+		return fmt.Errorf("Failed loading encryption key of %q: %w", root, err)
+	}
+
+	d.logger.Debug("Loaded ZFS encryption key", logger.Ctx{"dataset": root})
+
+	return nil
+}
+
+// useRawSend returns whether raw send streams should be used for optimized backups and migrations.
+// Raw streams keep encrypted and compressed blocks as they are on disk so they aren't decrypted or decompressed on transfer.
+func (d *zfs) useRawSend() bool {
+	return zfsRaw && util.IsTrue(d.config["zfs.send_raw"])
+}
+
 func (d *zfs) needsRecursion(dataset string) bool {
 	// Ignore snapshots for the test.
 	dataset = strings.Split(dataset, "@")[0]
@@ -338,6 +499,9 @@ func (d *zfs) sendDataset(dataset string, parent string, volSrcArgs *migration.V
 		if zfsRaw {
 			args = append(args, "-w")
 		}
+	} else if d.useRawSend() || d.isEncrypted(dataset) {
+		// Send the data as stored on disk (keeping encrypted datasets encrypted) along with the dataset properties.
+		args = append(args, "-w", "-p")
 	}
 
 	if slices.Contains(volSrcArgs.MigrationType.Features, "compress") {
@@ -425,6 +589,46 @@ func ValidateZfsBlocksize(value string) error {
 	return nil
 }
 
+// ValidateZfsCompression validates the compression property value of a volume.
+func ValidateZfsCompression(value string) error {
+	if slices.Contains([]string{"on", "off", "lz4", "lzjb", "zle", "gzip", "zstd", "zstd-fast"}, value) {
+		return nil
+	}
+
+	// Algorithms with an explicit level.
+	for prefix, levels := range map[string][2]int{"gzip-": {1, 9}, "zstd-fast-": {1, 1000}, "zstd-": {1, 19}} {
+		levelStr, ok := strings.CutPrefix(value, prefix)
+		if !ok {
+			continue
+		}
+
+		level, err := strconv.Atoi(levelStr)
+		if err == nil && level >= levels[0] && level <= levels[1] {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Invalid ZFS compression algorithm %q", value)
+}
+
+// ValidateZfsDedup validates the dedup property value of a volume.
+func ValidateZfsDedup(value string) error {
+	if slices.Contains([]string{"on", "off", "verify"}, value) {
+		return nil
+	}
+
+	checksum, verify, found := strings.Cut(value, ",")
+	if found && verify != "verify" {
+		return fmt.Errorf("Invalid ZFS dedup value %q", value)
+	}
+
+	if !slices.Contains([]string{"sha256", "sha512", "skein", "edonr", "blake3"}, checksum) {
+		return fmt.Errorf("Invalid ZFS dedup checksum %q", checksum)
+	}
+
+	return nil
+}
+
 // ZFSDataset is the structure used to store information about a dataset.
 type ZFSDataset struct {
 	Name string `json:"name" yaml:"name"`
@@ -473,3 +677,61 @@ func (d *zfs) delegateDataset(vol Volume, pid int) error {
 func ZFSSupportsDelegation() bool {
 	return zfsDelegate
 }
+
+// zfsParsePoolStatus extracts the state, the total number of I/O and checksum errors of the devices
+// and the scrub status from the raw output of "zpool status -p".
+func zfsParsePoolStatus(output string) (string, uint64, string) {
+	state := ""
+	scrub := "none"
+	var errCount uint64
+
+	inConfig := false
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "state:":
+			if len(fields) > 1 {
+				state = fields[1]
+			}
+
+			continue
+		case "scan:":
+			switch {
+			case strings.Contains(line, "scrub in progress"):
+				scrub = "running"
+			case strings.Contains(line, "scrub repaired"):
+				scrub = "finished"
+			case strings.Contains(line, "scrub canceled"):
+				scrub = "canceled"
+			}
+
+			continue
+		case "NAME":
+			inConfig = true
+			continue
+		case "errors:":
+			inConfig = false
+			continue
+		}
+
+		// Sum the READ, WRITE and CKSUM columns of all the devices.
+		if !inConfig || len(fields) < 5 {
+			continue
+		}
+
+		for _, field := range fields[2:5] {
+			count, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				break
+			}
+
+			errCount += count
+		}
+	}
+
+	return state, errCount, scrub
+}
@@ -0,0 +1,86 @@
+package drivers
+
+import (
+	"testing"
+)
+
+func Test_zfsParsePoolStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantState  string
+		wantErrors uint64
+		wantScrub  string
+	}{
+		{
+			"Healthy after scrub",
+			"  pool: default\n state: ONLINE\n  scan: scrub repaired 0B in 00:00:01 with 0 errors on Sun Oct  8 00:24:02 2023\nconfig:\n\n\tNAME                              STATE     READ WRITE CKSUM\n\tdefault                           ONLINE       0     0     0\n\t  /var/lib/incus/disks/default.img  ONLINE       0     0     0\n\nerrors: No known data errors\n",
+			"ONLINE",
+			0,
+			"finished",
+		},
+		{
+			"Degraded mirror with scrub running",
+			"  pool: tank\n state: DEGRADED\nstatus: One or more devices could not be used.\n  scan: scrub in progress since Sun Oct  8 00:24:02 2023\nconfig:\n\n\tNAME        STATE     READ WRITE CKSUM\n\ttank        DEGRADED     0     0     0\n\t  mirror-0  DEGRADED     0     0     0\n\t    sda     ONLINE       0     0     3\n\t    sdb     UNAVAIL      4     1     0  cannot open\n\nerrors: No known data errors\n",
+			"DEGRADED",
+			8,
+			"running",
+		},
+		{
+			"Never scrubbed",
+			"  pool: tank\n state: ONLINE\nconfig:\n\n\tNAME        STATE     READ WRITE CKSUM\n\ttank        ONLINE       0     0     0\n\t  sda       ONLINE       0     0     0\n\nerrors: No known data errors\n",
+			"ONLINE",
+			0,
+			"none",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, errCount, scrub := zfsParsePoolStatus(tt.output)
+			if state != tt.wantState {
+				t.Errorf("zfsParsePoolStatus() state = %q, want %q", state, tt.wantState)
+			}
+
+			if errCount != tt.wantErrors {
+				t.Errorf("zfsParsePoolStatus() errors = %d, want %d", errCount, tt.wantErrors)
+			}
+
+			if scrub != tt.wantScrub {
+				t.Errorf("zfsParsePoolStatus() scrub = %q, want %q", scrub, tt.wantScrub)
+			}
+		})
+	}
+}
+
+func TestValidateZfsCompression(t *testing.T) {
+	for _, value := range []string{"on", "off", "lz4", "zstd", "zstd-19", "zstd-fast", "zstd-fast-10", "gzip-9"} {
+		err := ValidateZfsCompression(value)
+		if err != nil {
+			t.Errorf("ValidateZfsCompression(%q) unexpected error: %v", value, err)
+		}
+	}
+
+	for _, value := range []string{"", "lz5", "gzip-10", "zstd-0", "zstd-fast-x", "zstd-"} {
+		err := ValidateZfsCompression(value)
+		if err == nil {
+			t.Errorf("ValidateZfsCompression(%q) expected an error", value)
+		}
+	}
+}
+
+func TestValidateZfsDedup(t *testing.T) {
+	for _, value := range []string{"on", "off", "verify", "sha256", "sha512,verify", "blake3"} {
+		err := ValidateZfsDedup(value)
+		if err != nil {
+			t.Errorf("ValidateZfsDedup(%q) unexpected error: %v", value, err)
+		}
+	}
+
+	for _, value := range []string{"", "md5", "sha256,", "sha256,noverify"} {
+		err := ValidateZfsDedup(value)
+		if err == nil {
+			t.Errorf("ValidateZfsDedup(%q) expected an error", value)
+		}
+	}
+}
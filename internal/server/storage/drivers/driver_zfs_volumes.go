@@ -151,9 +151,18 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		}
 	}
 
+	encryptionOpts, err := d.encryptionOptions(vol)
+	if err != nil {
+		return err
+	}
+
 	if vol.contentType == ContentTypeFS && !d.isBlockBacked(vol) {
+		opts := []string{"mountpoint=legacy", "canmount=noauto"}
+		opts = append(opts, d.datasetPropertiesFromConfig(vol)...)
+		opts = append(opts, encryptionOpts...)
+
 		// Create the filesystem dataset.
-		err := d.createDataset(d.dataset(vol, false), "mountpoint=legacy", "canmount=noauto")
+		err := d.createDataset(d.dataset(vol, false), opts...)
 		if err != nil {
 			return err
 		}
@@ -226,6 +235,9 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 			return err
 		}
 
+		opts = append(opts, d.datasetPropertiesFromConfig(vol)...)
+		opts = append(opts, encryptionOpts...)
+
 		// Create the volume dataset.
 		err = d.createVolume(d.dataset(vol, false), sizeBytes, opts...)
 		if err != nil {
@@ -270,7 +282,7 @@ func (d *zfs) CreateVolume(vol Volume, filler *VolumeFiller, op *operations.Oper
 		reverter.Add(func() { _ = d.DeleteVolume(fsVol, op) })
 	}
 
-	err := vol.MountTask(func(mountPath string, op *operations.Operation) error {
+	err = vol.MountTask(func(mountPath string, op *operations.Operation) error {
 		// Run the volume filler function if supplied.
 		if filler != nil && filler.Fill != nil {
 			var err error
@@ -545,6 +557,12 @@ func (d *zfs) CreateVolumeFromBackup(vol Volume, srcBackup backup.Info, srcData
 			if err != nil {
 				return nil, nil, err
 			}
+
+			// Apply the compression and dedup properties.
+			err = d.setDatasetPropertiesFromConfig(v)
+			if err != nil {
+				return nil, nil, err
+			}
 		}
 
 		// Only mount instance filesystem volumes for backup.yaml access.
@@ -669,25 +687,39 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 	// Delete the volume created on failure.
 	reverter.Add(func() { _ = d.DeleteVolume(vol, op) })
 
-	// If zfs.clone_copy is disabled or source volume has snapshots, then use full copy mode.
-	if util.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 {
+	// Clones share the encryption of their origin, so encrypting an unencrypted source requires a full copy.
+	var encryptionOpts []string
+	if !d.isEncrypted(srcSnapshot) {
+		encryptionOpts, err = d.encryptionOptions(vol)
+		if err != nil {
+			return err
+		}
+	}
+
+	// If zfs.clone_copy is disabled, source volume has snapshots or the copy must be encrypted, then use full copy mode.
+	if util.IsFalse(d.config["zfs.clone_copy"]) || len(snapshots) > 0 || len(encryptionOpts) > 0 {
 		snapName := strings.SplitN(srcSnapshot, "@", 2)[1]
 
 		// Send/receive the snapshot.
 		var sender *exec.Cmd
-		var receiver *exec.Cmd
-		if vol.ContentType() == ContentTypeBlock || d.isBlockBacked(vol) {
-			receiver = exec.Command("zfs", "receive", d.dataset(vol, false))
-		} else {
-			receiver = exec.Command("zfs", "receive", "-x", "mountpoint", d.dataset(vol, false))
+		receiverArgs := []string{"receive"}
+		if vol.ContentType() != ContentTypeBlock && !d.isBlockBacked(vol) {
+			receiverArgs = append(receiverArgs, "-x", "mountpoint")
+		}
+
+		for _, opt := range encryptionOpts {
+			receiverArgs = append(receiverArgs, "-o", opt)
 		}
 
+		receiver := exec.Command("zfs", append(receiverArgs, d.dataset(vol, false))...)
+
 		// Handle transferring snapshots.
 		if len(snapshots) > 0 {
 			args := []string{"send", "-R"}
 
 			// Use raw flag is supported, this is required to send/receive encrypted volumes (and enables compression).
-			if zfsRaw {
+			// Raw streams can't be encrypted on receive though.
+			if zfsRaw && len(encryptionOpts) == 0 {
 				args = append(args, "-w")
 			}
 
@@ -701,12 +733,13 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 			if d.needsRecursion(d.dataset(srcVol, false)) {
 				args = append(args, "-R")
 
-				if zfsRaw {
+				if zfsRaw && len(encryptionOpts) == 0 {
 					args = append(args, "-w")
 				}
 			}
 
-			if d.config["zfs.clone_copy"] == "rebase" {
+			// Incremental streams can't be received as a new encrypted dataset.
+			if d.config["zfs.clone_copy"] == "rebase" && len(encryptionOpts) == 0 {
 				var err error
 				origin := d.dataset(srcVol, false)
 				for {
@@ -855,6 +888,12 @@ func (d *zfs) CreateVolumeFromCopy(vol Volume, srcVol Volume, copySnapshots bool
 			if err != nil {
 				return err
 			}
+
+			// Apply the compression and dedup properties.
+			err = d.setDatasetPropertiesFromConfig(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		if d.isBlockBacked(srcVol) && renegerateFilesystemUUIDNeeded(vol.ConfigBlockFilesystem()) {
@@ -1220,6 +1259,12 @@ func (d *zfs) createVolumeFromMigrationOptimized(vol Volume, conn io.ReadWriteCl
 			if err != nil {
 				return err
 			}
+
+			// Apply the compression and dedup properties.
+			err = d.setDatasetPropertiesFromConfig(vol)
+			if err != nil {
+				return err
+			}
 		}
 
 		if d.isBlockBacked(vol) && renegerateFilesystemUUIDNeeded(vol.ConfigBlockFilesystem()) {
@@ -1579,10 +1624,13 @@ func (d *zfs) commonVolumeRules() map[string]func(value string) error {
 		"block.mount_options":  validate.IsAny,
 		"zfs.block_mode":       validate.Optional(validate.IsBool),
 		"zfs.blocksize":        validate.Optional(ValidateZfsBlocksize),
+		"zfs.compression":      validate.Optional(ValidateZfsCompression),
+		"zfs.dedup":            validate.Optional(ValidateZfsDedup),
 		"zfs.remove_snapshots": validate.Optional(validate.IsBool),
 		"zfs.reserve_space":    validate.Optional(validate.IsBool),
 		"zfs.use_refquota":     validate.Optional(validate.IsBool),
 		"zfs.delegate":         validate.Optional(validate.IsBool),
+		"zfs.encryption":       validate.Optional(validate.IsBool),
 	}
 }
 
@@ -1603,6 +1651,10 @@ func (d *zfs) ValidateVolume(vol Volume, removeUnknownKeys bool) error {
 		delete(commonRules, "block.mount_options")
 	}
 
+	if util.IsTrue(vol.config["zfs.encryption"]) && !zfsRaw {
+		return errors.New("Encryption requires ZFS 0.8.0 or higher")
+	}
+
 	return d.validateVolume(vol, commonRules, removeUnknownKeys)
 }
 
@@ -1611,11 +1663,27 @@ func (d *zfs) UpdateVolume(vol Volume, changedConfig map[string]string) error {
 	// Mangle the current volume to its old values.
 	old := make(map[string]string)
 	for k, v := range changedConfig {
+		if k == "zfs.encryption" {
+			return errors.New("zfs.encryption cannot be changed")
+		}
+
 		if k == "size" || k == "zfs.use_refquota" || k == "zfs.reserve_space" {
 			old[k] = vol.config[k]
 			vol.config[k] = v
 		}
 
+		if k == "zfs.compression" || k == "zfs.dedup" {
+			// Fall back to the pool default when the key is unset.
+			if v == "" {
+				v = d.config["volume."+k]
+			}
+
+			err := d.setDatasetProperty(d.dataset(vol, false), strings.TrimPrefix(k, "zfs."), v)
+			if err != nil {
+				return err
+			}
+		}
+
 		if k == "zfs.blocksize" {
 			// Convert to bytes.
 			sizeBytes, err := units.ParseByteSizeString(v)
@@ -1815,12 +1883,9 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 			l := d.logger.AddContext(logger.Ctx{"dev": volDevPath, "size": fmt.Sprintf("%db", sizeBytes)})
 
 			if sizeBytes < oldVolSizeBytes {
-				if !filesystemTypeCanBeShrunk(fsType) {
-					return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
-				}
-
-				if inUse {
-					return ErrInUse // We don't allow online shrinking of filesystem block volumes.
+				err = checkFileSystemShrink(fsType, vol, sizeBytes, inUse)
+				if err != nil {
+					return err
 				}
 
 				// Shrink filesystem first.
@@ -1893,6 +1958,28 @@ func (d *zfs) SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op
 		return nil
 	}
 
+	// Quotas below the current usage are refused by ZFS, check before clearing the existing one.
+	if sizeBytes > 0 {
+		usageKey := "used"
+		if util.IsTrue(vol.ExpandedConfig("zfs.use_refquota")) {
+			usageKey = "referenced"
+		}
+
+		usedBytesStr, err := d.getDatasetProperty(d.dataset(vol, false), usageKey)
+		if err != nil {
+			return err
+		}
+
+		usedBytes, err := strconv.ParseInt(usedBytesStr, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		if sizeBytes < usedBytes {
+			return fmt.Errorf("Volume cannot be shrunk to %s as %s is currently used: %w", units.GetByteSizeStringIEC(sizeBytes, 2), units.GetByteSizeStringIEC(usedBytes, 2), ErrCannotBeShrunk)
+		}
+	}
+
 	// Clear the existing quota.
 	for _, property := range []string{"quota", "refquota", "reservation", "refreservation"} {
 		err = d.setDatasetProperties(d.dataset(vol, false), fmt.Sprintf("%s=none", property))
@@ -2005,6 +2092,21 @@ func (d *zfs) GetVolumeDiskPath(vol Volume) (string, error) {
 	return d.tryGetVolumeDiskPathFromDataset(ctx, d.dataset(vol, false))
 }
 
+// GetVolumeIOStats returns the I/O counters of a volume's block device.
+// No counters are returned when the zvol isn't currently exposed as a device.
+func (d *zfs) GetVolumeIOStats(vol Volume) (*VolumeIOStats, error) {
+	if !vol.IsVMBlock() && !(vol.volType == VolumeTypeCustom && IsContentBlock(vol.contentType)) {
+		return nil, ErrNotSupported
+	}
+
+	devPath, err := d.getVolumeDiskPathFromDataset(d.dataset(vol, false))
+	if err != nil {
+		return nil, nil
+	}
+
+	return blockDeviceIOStats(devPath)
+}
+
 // ListVolumes returns a list of volumes in storage pool.
 func (d *zfs) ListVolumes() ([]Volume, error) {
 	vols := make(map[string]Volume)
@@ -2135,6 +2237,14 @@ func (d *zfs) activateVolume(vol Volume) (bool, error) {
 
 	dataset := d.dataset(vol, false)
 
+	// The device of encrypted volumes only shows up once their key is loaded.
+	if util.IsTrue(vol.config["zfs.encryption"]) {
+		err := d.loadEncryptionKey(dataset)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	// Check if already active.
 	current, err := d.getDatasetProperty(dataset, "volmode")
 	if err != nil {
@@ -2246,6 +2356,13 @@ func (d *zfs) MountVolume(vol Volume, op *operations.Operation) error {
 	// Check if filesystem volume already mounted.
 	if vol.contentType == ContentTypeFS && !d.isBlockBacked(vol) {
 		if !linux.IsMountPoint(mountPath) {
+			if util.IsTrue(vol.config["zfs.encryption"]) {
+				err := d.loadEncryptionKey(dataset)
+				if err != nil {
+					return err
+				}
+			}
+
 			err := d.setDatasetProperties(dataset, "mountpoint=legacy", "canmount=noauto")
 			if err != nil {
 				return err
@@ -2834,6 +2951,9 @@ func (d *zfs) BackupVolume(vol Volume, tarWriter *instancewriter.InstanceTarWrit
 			if zfsRaw {
 				args = append(args, "-w")
 			}
+		} else if d.useRawSend() || d.isEncrypted(path) {
+			// Send the data as stored on disk (keeping encrypted datasets encrypted) along with the dataset properties.
+			args = append(args, "-w", "-p")
 		}
 
 		if parent != "" {
@@ -3068,6 +3188,13 @@ func (d *zfs) mountVolumeSnapshot(snapVol Volume, snapshotDataset string, mountP
 				return nil, err
 			}
 
+			if util.IsTrue(snapVol.config["zfs.encryption"]) {
+				err = d.loadEncryptionKey(snapshotDataset)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			// Mount the snapshot directly (not possible through tools).
 			err = TryMount(snapshotDataset, mountPath, "zfs", unix.MS_RDONLY, "")
 			if err != nil {
@@ -46,6 +46,8 @@ type Driver interface {
 	// Unmount unmounts a storage pool if needed, returns true if unmounted, false if was not mounted.
 	Unmount() (bool, error)
 	GetResources() (*api.ResourcesStoragePool, error)
+	GetHealth() (*api.StoragePoolStateHealth, error)
+	Scrub() error
 	Validate(config map[string]string) error
 	Update(changedConfig map[string]string) error
 	ApplyPatch(name string) error
@@ -71,8 +73,13 @@ type Driver interface {
 	RenameVolume(vol Volume, newName string, op *operations.Operation) error
 	UpdateVolume(vol Volume, changedConfig map[string]string) error
 	GetVolumeUsage(vol Volume) (int64, error)
+	GetVolumeMirror(vol Volume) (*api.StorageVolumeStateMirror, error)
+	PromoteVolume(vol Volume, force bool) error
+	DemoteVolume(vol Volume) error
+	FenceVolume(vol Volume, memberConfig map[string]string) error
 	SetVolumeQuota(vol Volume, size string, allowUnsafeResize bool, op *operations.Operation) error
 	GetVolumeDiskPath(vol Volume) (string, error)
+	GetVolumeIOStats(vol Volume) (*VolumeIOStats, error)
 	ListVolumes() ([]Volume, error)
 
 	// MountVolume mounts a storage volume (if not mounted) and increments reference counter.
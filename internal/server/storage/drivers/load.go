@@ -15,6 +15,9 @@ var drivers = map[string]func() driver{
 	"lvmcluster": func() driver { return &lvm{clustered: true} },
 	"zfs":        func() driver { return &zfs{} },
 	"linstor":    func() driver { return &linstor{} },
+	"nfs":        func() driver { return &nfs{remoteFS{fs: nfsType}} },
+	"glusterfs":  func() driver { return &glusterfs{remoteFS{fs: glusterfsType}} },
+	"san":        func() driver { return &san{lvm: lvm{clustered: true}} },
 }
 
 // Validators contains functions used for validating a drivers's config.
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unsafe"
@@ -24,6 +25,7 @@ import (
 	"github.com/lxc/incus/v6/shared/idmap"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -322,6 +324,66 @@ func ensureSparseFile(filePath string, sizeBytes int64) error {
 	return f.Close()
 }
 
+// growLoopFile grows the loop file backing a storage pool to the given size.
+func growLoopFile(loopPath string, size string) error {
+	sizeBytes, err := units.ParseByteSizeString(size)
+	if err != nil {
+		return err
+	}
+
+	fi, err := os.Stat(loopPath)
+	if err != nil {
+		return err
+	}
+
+	if sizeBytes < fi.Size() {
+		return errors.New("Loop-backed storage pools cannot be shrunk")
+	}
+
+	return ensureSparseFile(loopPath, sizeBytes)
+}
+
+// blockDeviceIOStats returns the I/O counters of the given block device.
+func blockDeviceIOStats(devPath string) (*VolumeIOStats, error) {
+	target, err := filepath.EvalSymlinks(devPath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Join("/sys/class/block", filepath.Base(target), "stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBlockDeviceStat(string(content))
+}
+
+// parseBlockDeviceStat parses the content of a /sys/class/block/<dev>/stat file.
+// Sector counts in that file are always in units of 512 bytes regardless of the device's sector size.
+func parseBlockDeviceStat(content string) (*VolumeIOStats, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 7 {
+		return nil, fmt.Errorf("Unexpected block device stat format %q", strings.TrimSpace(content))
+	}
+
+	values := make([]uint64, 7)
+	for i := range values {
+		value, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing block device stat field %d: %w", i, err)
+		}
+
+		values[i] = value
+	}
+
+	return &VolumeIOStats{
+		ReadsCompleted:  values[0],
+		ReadBytes:       values[2] * 512,
+		WritesCompleted: values[4],
+		WrittenBytes:    values[6] * 512,
+	}, nil
+}
+
 // ensureVolumeBlockFile creates new block file or enlarges the raw block file for a volume to the specified size.
 // Returns true if resize took place, false if not. Requested size is rounded to nearest block size using
 // roundVolumeBlockSizeBytes() before decision whether to resize is taken. Accepts unsupportedResizeTypes
@@ -458,6 +520,56 @@ func filesystemTypeCanBeShrunk(fsType string) bool {
 	return false
 }
 
+// filesystemTypeCanBeShrunkOnline indicates if filesystems of fsType can be shrunk while mounted.
+func filesystemTypeCanBeShrunkOnline(fsType string) bool {
+	if fsType == "" {
+		fsType = DefaultFilesystem
+	}
+
+	return fsType == "btrfs"
+}
+
+// checkFileSystemShrink checks that the filesystem of a block backed volume can be shrunk to byteSize.
+// The volume is mounted temporarily if needed to get its current usage so that the returned error
+// can include it when the requested size is too small.
+func checkFileSystemShrink(fsType string, vol Volume, byteSize int64, inUse bool) error {
+	if fsType == "" {
+		fsType = DefaultFilesystem
+	}
+
+	if !filesystemTypeCanBeShrunk(fsType) {
+		return fmt.Errorf("Filesystem %q cannot be shrunk: %w", fsType, ErrCannotBeShrunk)
+	}
+
+	var usedBytes int64
+	err := vol.MountTask(func(mountPath string, op *operations.Operation) error {
+		var stat unix.Statfs_t
+		err := unix.Statfs(mountPath, &stat)
+		if err != nil {
+			return err
+		}
+
+		usedBytes = int64(stat.Blocks-stat.Bfree) * int64(stat.Bsize)
+
+		return nil
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed getting filesystem usage: %w", err)
+	}
+
+	usage := units.GetByteSizeStringIEC(usedBytes, 2)
+
+	if byteSize <= usedBytes {
+		return fmt.Errorf("Filesystem cannot be shrunk to %s as %s is currently used: %w", units.GetByteSizeStringIEC(byteSize, 2), usage, ErrCannotBeShrunk)
+	}
+
+	if inUse && !filesystemTypeCanBeShrunkOnline(fsType) {
+		return fmt.Errorf("Filesystem %q cannot be shrunk while the volume is in use (%s currently used): %w", fsType, usage, ErrInUse)
+	}
+
+	return nil
+}
+
 // shrinkFileSystem shrinks a filesystem if it is supported.
 // EXT4 volumes will be unmounted temporarily if needed.
 // BTRFS volumes will be mounted temporarily if needed.
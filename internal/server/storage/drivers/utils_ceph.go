@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/lxc/incus/v6/shared/api"
@@ -345,3 +346,44 @@ func EnsureClientPrefix(client string) string {
 
 	return "client." + client
 }
+
+// cephHealth returns the health of the Ceph cluster as a storage pool health.
+func cephHealth(cluster string, client string) (*api.StoragePoolStateHealth, error) {
+	status := struct {
+		Status string `json:"status"`
+		Checks map[string]struct {
+			Summary struct {
+				Message string `json:"message"`
+			} `json:"summary"`
+		} `json:"checks"`
+	}{}
+
+	err := callCephJSON(&status, "--name", fmt.Sprintf("client.%s", client), "--cluster", cluster, "health")
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting Ceph cluster health: %w", err)
+	}
+
+	health := &api.StoragePoolStateHealth{Description: status.Status}
+
+	switch status.Status {
+	case "HEALTH_OK":
+		health.Status = "healthy"
+	case "HEALTH_WARN":
+		health.Status = "degraded"
+	default:
+		health.Status = "failed"
+	}
+
+	// Include the messages of the failed health checks.
+	messages := make([]string, 0, len(status.Checks))
+	for _, check := range status.Checks {
+		messages = append(messages, check.Summary.Message)
+	}
+
+	if len(messages) > 0 {
+		slices.Sort(messages)
+		health.Description = fmt.Sprintf("%s: %s", status.Status, strings.Join(messages, "; "))
+	}
+
+	return health, nil
+}
@@ -35,3 +35,20 @@ func TestGetVolumeMountPath(t *testing.T) {
 	expected = GetPoolMountPath(poolName) + "/virtual-machines/testvol"
 	assert.Equal(t, expected, path)
 }
+
+func TestParseBlockDeviceStat(t *testing.T) {
+	stats, err := parseBlockDeviceStat("    3264     1024   402296     1234    20816    14033   803744    25488        0    27484    31172        0        0        0        0     1568     4449\n")
+	assert.NoError(t, err)
+	assert.Equal(t, &VolumeIOStats{
+		ReadsCompleted:  3264,
+		ReadBytes:       402296 * 512,
+		WritesCompleted: 20816,
+		WrittenBytes:    803744 * 512,
+	}, stats)
+
+	_, err = parseBlockDeviceStat("1 2 3\n")
+	assert.Error(t, err)
+
+	_, err = parseBlockDeviceStat("1 2 3 4 5 six 7\n")
+	assert.Error(t, err)
+}
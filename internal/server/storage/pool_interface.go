@@ -50,6 +50,8 @@ type Pool interface {
 	ToAPI() api.StoragePool
 
 	GetResources() (*api.ResourcesStoragePool, error)
+	GetHealth() (*api.StoragePoolStateHealth, error)
+	Scrub() error
 	IsUsed() (bool, error)
 	Delete(clientType request.ClientType, op *operations.Operation) error
 	Update(clientType request.ClientType, newDesc string, newConfig map[string]string, op *operations.Operation) error
@@ -82,6 +84,7 @@ type Pool interface {
 	BackupInstance(inst instance.Instance, tarWriter *instancewriter.InstanceTarWriter, optimized bool, snapshots bool, op *operations.Operation) error
 
 	GetInstanceUsage(inst instance.Instance) (*VolumeUsage, error)
+	FenceInstance(inst instance.Instance, memberName string) error
 	SetInstanceQuota(inst instance.Instance, size string, vmStateSize string, op *operations.Operation) error
 
 	MountInstance(inst instance.Instance, op *operations.Operation) (*MountInfo, error)
@@ -124,12 +127,14 @@ type Pool interface {
 	DeleteCustomVolume(projectName string, volName string, op *operations.Operation) error
 	GetCustomVolumeDisk(projectName string, volName string) (string, error)
 	GetCustomVolumeUsage(projectName string, volName string) (*VolumeUsage, error)
+	GetCustomVolumeIOStats(projectName string, volName string) (*drivers.VolumeIOStats, error)
 	MountCustomVolume(projectName string, volName string, op *operations.Operation) (*MountInfo, error)
 	UnmountCustomVolume(projectName string, volName string, op *operations.Operation) (bool, error)
 	ImportCustomVolume(projectName string, poolVol *backupConfig.Config, op *operations.Operation) (revert.Hook, error)
 	RefreshCustomVolume(projectName string, srcProjectName string, volName, desc string, config map[string]string, srcPoolName, srcVolName string, snapshots bool, excludeOlder bool, op *operations.Operation) error
 	GenerateCustomVolumeBackupConfig(projectName string, volName string, snapshots bool, op *operations.Operation) (*backupConfig.Config, error)
 	CreateCustomVolumeFromISO(projectName string, volName string, srcData io.ReadSeeker, size int64, op *operations.Operation) error
+	CreateCustomVolumeFromDiskImage(projectName string, volName string, imgPath string, format string, op *operations.Operation) error
 
 	// Custom volume snapshots.
 	CreateCustomVolumeSnapshot(projectName string, volName string, newSnapshotName string, newExpiryDate time.Time, op *operations.Operation) error
@@ -138,6 +143,11 @@ type Pool interface {
 	UpdateCustomVolumeSnapshot(projectName string, volName string, newDesc string, newConfig map[string]string, newExpiryDate time.Time, op *operations.Operation) error
 	RestoreCustomVolume(projectName string, volName string, snapshotName string, op *operations.Operation) error
 
+	// Volume mirroring.
+	GetVolumeMirror(projectName string, volName string, volType drivers.VolumeType) (*api.StorageVolumeStateMirror, error)
+	PromoteVolume(projectName string, volName string, volType drivers.VolumeType, force bool, op *operations.Operation) error
+	DemoteVolume(projectName string, volName string, volType drivers.VolumeType, op *operations.Operation) error
+
 	// Custom volume migration.
 	MigrationTypes(contentType drivers.ContentType, refresh bool, copySnapshots bool, clusterMove bool, storageMove bool) []migration.Type
 	CreateCustomVolumeFromMigration(projectName string, conn io.ReadWriteCloser, args migration.VolumeTargetArgs, op *operations.Operation) error
@@ -149,4 +159,10 @@ type Pool interface {
 
 	// Storage volume recovery.
 	ListUnknownVolumes(op *operations.Operation) (map[string][]*backupConfig.Config, error)
+
+	// Trash.
+	TrashInstance(inst instance.Instance, entryUUID string, op *operations.Operation) error
+	TrashCustomVolume(projectName string, volName string, entryUUID string, op *operations.Operation) error
+	RestoreTrashVolume(projectName string, poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) (revert.Hook, error)
+	DeleteTrashVolume(poolVol *backupConfig.Config, entryUUID string, op *operations.Operation) error
 }
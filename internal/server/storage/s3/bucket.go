@@ -0,0 +1,120 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// bucketLifecycleRuleID is the ID of the lifecycle rule managed through the bucket config.
+const bucketLifecycleRuleID = "incus"
+
+// BucketLifecycle returns the lifecycle configuration matching the "lifecycle.*" bucket config keys.
+// An empty configuration is returned when no expiration is configured.
+func BucketLifecycle(config map[string]string) (*lifecycle.Configuration, error) {
+	lifecycleConfig := lifecycle.NewConfiguration()
+
+	expiry, err := bucketLifecycleDays(config, "lifecycle.expiry")
+	if err != nil {
+		return nil, err
+	}
+
+	noncurrentExpiry, err := bucketLifecycleDays(config, "lifecycle.noncurrent_expiry")
+	if err != nil {
+		return nil, err
+	}
+
+	if expiry == 0 && noncurrentExpiry == 0 {
+		return lifecycleConfig, nil
+	}
+
+	rule := lifecycle.Rule{
+		ID:     bucketLifecycleRuleID,
+		Status: "Enabled",
+		RuleFilter: lifecycle.Filter{
+			Prefix: config["lifecycle.prefix"],
+		},
+	}
+
+	if expiry > 0 {
+		rule.Expiration = lifecycle.Expiration{Days: lifecycle.ExpirationDays(expiry)}
+	}
+
+	if noncurrentExpiry > 0 {
+		rule.NoncurrentVersionExpiration = lifecycle.NoncurrentVersionExpiration{NoncurrentDays: lifecycle.ExpirationDays(noncurrentExpiry)}
+	}
+
+	lifecycleConfig.Rules = append(lifecycleConfig.Rules, rule)
+
+	return lifecycleConfig, nil
+}
+
+// bucketLifecycleDays parses a number of days from the bucket config.
+func bucketLifecycleDays(config map[string]string, key string) (int, error) {
+	if config[key] == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.ParseUint(config[key], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid value for %q: %w", key, err)
+	}
+
+	return int(days), nil
+}
+
+// BucketConfigChanged returns true if changedConfig contains any versioning or lifecycle setting.
+func BucketConfigChanged(changedConfig map[string]string) bool {
+	for k := range changedConfig {
+		if k == "versioning" || strings.HasPrefix(k, "lifecycle.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetBucketConfig applies the versioning and lifecycle settings found in changedConfig to the bucket.
+// The full bucket config is needed as the lifecycle rule is built from all of the "lifecycle.*" keys.
+func SetBucketConfig(ctx context.Context, client *minio.Client, bucketName string, config map[string]string, changedConfig map[string]string) error {
+	versioning, versioningChanged := changedConfig["versioning"]
+	if versioningChanged {
+		var err error
+
+		if util.IsTrue(versioning) {
+			err = client.EnableVersioning(ctx, bucketName)
+		} else {
+			err = client.SuspendVersioning(ctx, bucketName)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed setting bucket versioning: %w", err)
+		}
+	}
+
+	for k := range changedConfig {
+		if !strings.HasPrefix(k, "lifecycle.") {
+			continue
+		}
+
+		lifecycleConfig, err := BucketLifecycle(config)
+		if err != nil {
+			return err
+		}
+
+		err = client.SetBucketLifecycle(ctx, bucketName, lifecycleConfig)
+		if err != nil {
+			return fmt.Errorf("Failed setting bucket lifecycle: %w", err)
+		}
+
+		break
+	}
+
+	return nil
+}
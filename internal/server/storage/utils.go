@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"slices"
@@ -500,22 +501,40 @@ func poolAndVolumeCommonRules(vol *drivers.Volume) map[string]func(string) error
 		rules["initial.mode"] = validate.Optional(validate.IsInt64)
 	}
 
-	// security.shared is only relevant for custom block volumes.
+	// security.shared is only relevant for custom volumes (filesystem volumes only support the read-only mode).
 	if (vol == nil) || (vol != nil && vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeBlock) {
-		rules["security.shared"] = validate.Optional(validate.IsBool)
+		rules["security.shared"] = validate.Optional(isSharedMode)
+	} else if vol.Type() == drivers.VolumeTypeCustom && vol.ContentType() == drivers.ContentTypeFS {
+		rules["security.shared"] = validate.Optional(validate.IsOneOf("readonly"))
 	}
 
 	return rules
 }
 
+// isSharedMode validates the security.shared volume setting, which is either a boolean or "readonly".
+func isSharedMode(value string) error {
+	if value == "readonly" {
+		return nil
+	}
+
+	return validate.IsBool(value)
+}
+
 // validatePoolCommonRules returns a map of pool config rules common to all drivers.
 func validatePoolCommonRules() map[string]func(string) error {
 	rules := map[string]func(string) error{
-		"source":                  validate.IsAny,
-		"source.wipe":             validate.Optional(validate.IsBool),
-		"volatile.initial_source": validate.IsAny,
-		"rsync.bwlimit":           validate.Optional(validate.IsSize),
-		"rsync.compression":       validate.Optional(validate.IsBool),
+		"source":                        validate.IsAny,
+		"source.wipe":                   validate.Optional(validate.IsBool),
+		"volatile.initial_source":       validate.IsAny,
+		"rsync.bwlimit":                 validate.Optional(validate.IsSize),
+		"rsync.compression":             validate.Optional(validate.IsBool),
+		"backups.compression_algorithm": validate.Optional(validate.IsCompressionAlgorithm),
+		"snapshots.expiry": func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		},
+		"snapshots.pattern": validate.IsAny,
 	}
 
 	// Add to pool config rules (prefixed with volume.*) which are common for pool and volume.
@@ -526,6 +545,16 @@ func validatePoolCommonRules() map[string]func(string) error {
 	return rules
 }
 
+// isIOLimit validates an I/O limit in bytes per second or, when suffixed with "iops", in I/O operations per second.
+func isIOLimit(value string) error {
+	iops, ok := strings.CutSuffix(value, "iops")
+	if ok {
+		return validate.IsUint32(iops)
+	}
+
+	return validate.IsSize(value)
+}
+
 // validateVolumeCommonRules returns a map of volume config rules common to all drivers.
 func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error {
 	rules := poolAndVolumeCommonRules(&vol)
@@ -547,6 +576,60 @@ func validateVolumeCommonRules(vol drivers.Volume) map[string]func(string) error
 		rules["block.filesystem"] = validate.IsAny
 	}
 
+	// Backup scheduling and retention are only available for custom volumes.
+	if vol.Type() == drivers.VolumeTypeCustom {
+		rules["backups.expiry"] = func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		rules["backups.keep_last"] = validate.Optional(validate.IsInRange(1, math.MaxUint32))
+		rules["backups.retention"] = func(value string) error {
+			// Validate expression
+			_, err := internalInstance.GetExpiry(time.Time{}, value)
+			return err
+		}
+
+		rules["backups.pattern"] = validate.IsAny
+		rules["backups.schedule"] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+		rules["backups.volume_only"] = validate.Optional(validate.IsBool)
+		rules["backups.target.name"] = validate.IsAny
+		rules["backups.target.path"] = validate.IsAny
+		rules["limits.read"] = validate.Optional(isIOLimit)
+		rules["limits.write"] = validate.Optional(isIOLimit)
+		rules["limits.max.iops"] = validate.Optional(validate.IsUint32)
+
+		// Named snapshot schedules, each with its own optional expiry and pattern.
+		for k := range vol.Config() {
+			name, ok := strings.CutPrefix(k, "snapshots.schedule.")
+			if !ok {
+				continue
+			}
+
+			if name == "" || strings.Contains(name, ".") {
+				continue // Leave it to be rejected as an unknown key.
+			}
+
+			rules[k] = validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"}))
+			rules["snapshots.expiry."+name] = func(value string) error {
+				// Validate expression
+				_, err := internalInstance.GetExpiry(time.Time{}, value)
+				return err
+			}
+
+			rules["snapshots.pattern."+name] = validate.IsAny
+		}
+	}
+
+	// Object versioning and lifecycle expiration are only available for buckets.
+	if vol.Type() == drivers.VolumeTypeBucket {
+		rules["versioning"] = validate.Optional(validate.IsBool)
+		rules["lifecycle.expiry"] = validate.Optional(validate.IsUint32)
+		rules["lifecycle.noncurrent_expiry"] = validate.Optional(validate.IsUint32)
+		rules["lifecycle.prefix"] = validate.IsAny
+	}
+
 	// volatile.rootfs.size is only used for image volumes.
 	if vol.Type() == drivers.VolumeTypeImage {
 		rules["volatile.rootfs.size"] = validate.Optional(validate.IsInt64)
@@ -784,6 +867,92 @@ func ImageUnpack(imageFile string, vol drivers.Volume, destBlockFile string, sys
 	return imgSize, nil
 }
 
+// DiskImageFormats are the disk image formats which can be imported as custom block volumes.
+var DiskImageFormats = []string{"raw", "qcow2", "vmdk"}
+
+// DiskImageSize returns the virtual size of a disk image in bytes after checking it is of the expected format.
+func DiskImageSize(sysOS *sys.OS, imgPath string, format string) (int64, error) {
+	if !slices.Contains(DiskImageFormats, format) {
+		return -1, fmt.Errorf("Unsupported disk image format %q", format)
+	}
+
+	// Force the input format so we don't rely on qemu-img's detection logic and limit the resources
+	// qemu-img can use as the image comes from an untrusted source (see ImageUnpack).
+	cmd := []string{"prlimit", "--cpu=2", "--as=1073741824", "qemu-img", "info", "-f", format, "--output=json", imgPath}
+	imgJSON, err := apparmor.QemuImg(sysOS, cmd, imgPath, "", nil)
+	if err != nil {
+		return -1, fmt.Errorf("Failed reading disk image info %q: %w", imgPath, err)
+	}
+
+	imgInfo := struct {
+		Format      string `json:"format"`
+		VirtualSize int64  `json:"virtual-size"`
+	}{}
+
+	err = json.Unmarshal([]byte(imgJSON), &imgInfo)
+	if err != nil {
+		return -1, fmt.Errorf("Failed unmarshalling disk image info %q: %w (%q)", imgPath, err, imgJSON)
+	}
+
+	if imgInfo.Format != format {
+		return -1, fmt.Errorf("Unexpected disk image format %q", imgInfo.Format)
+	}
+
+	if imgInfo.VirtualSize <= 0 {
+		return -1, errors.New("Disk image is empty")
+	}
+
+	return imgInfo.VirtualSize, nil
+}
+
+// DiskImageConvert converts a disk image of the given format into a raw disk at dstPath.
+func DiskImageConvert(sysOS *sys.OS, imgPath string, format string, dstPath string, tracker *ioprogress.ProgressTracker) error {
+	cmd := []string{
+		"nice", "-n19", // Run with low priority to reduce CPU impact on other processes.
+		"qemu-img", "convert", "-p", "-f", format, "-O", "raw", "-t", "writeback",
+	}
+
+	// Extra options when dealing with block devices.
+	if linux.IsBlockdevPath(dstPath) {
+		// Parallel conversion.
+		cmd = append(cmd, "-W")
+
+		// Our block devices are clean, so skip zeroes.
+		cmd = append(cmd, "-n", "--target-is-zero")
+	}
+
+	cmd = append(cmd, imgPath, dstPath)
+
+	_, err := apparmor.QemuImg(sysOS, cmd, imgPath, dstPath, tracker)
+	if err != nil {
+		return fmt.Errorf("Failed converting disk image to raw at %q: %w", dstPath, err)
+	}
+
+	return nil
+}
+
+// DiskImageExportFormats are the disk image formats which block volumes can be exported as.
+var DiskImageExportFormats = []string{"qcow2", "vmdk"}
+
+// DiskImageExport converts the raw disk at srcPath into a disk image of the given format at imgPath.
+func DiskImageExport(sysOS *sys.OS, srcPath string, format string, imgPath string) error {
+	if !slices.Contains(DiskImageExportFormats, format) {
+		return fmt.Errorf("Unsupported disk image format %q", format)
+	}
+
+	cmd := []string{
+		"nice", "-n19", // Run with low priority to reduce CPU impact on other processes.
+		"qemu-img", "convert", "-f", "raw", "-O", format, "-t", "writeback", srcPath, imgPath,
+	}
+
+	_, err := apparmor.QemuImg(sysOS, cmd, srcPath, imgPath, nil)
+	if err != nil {
+		return fmt.Errorf("Failed converting disk to %s at %q: %w", format, imgPath, err)
+	}
+
+	return nil
+}
+
 // InstanceContentType returns the instance's content type.
 func InstanceContentType(inst instance.ConfigReader) drivers.ContentType {
 	contentType := drivers.ContentTypeFS
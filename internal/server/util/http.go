@@ -165,20 +165,32 @@ func CheckTrustState(cert x509.Certificate, trustedCerts map[string]x509.Certifi
 	}
 
 	if networkCert != nil && trustCACertificates {
-		ca := networkCert.CA()
+		// The CA may be an intermediate one.
+		ca := networkCert.IssuingCA(&cert)
 
-		if ca != nil && cert.CheckSignatureFrom(ca) == nil {
+		if ca != nil {
 			// Check whether the certificate has been revoked.
 			crl := networkCert.CRL()
 
 			if crl != nil {
-				if crl.CheckSignatureFrom(ca) != nil {
+				crlSigned := false
+				for _, other := range networkCert.CAs() {
+					if crl.CheckSignatureFrom(other) == nil {
+						crlSigned = true
+						break
+					}
+				}
+
+				if !crlSigned {
 					return false, "" // CRL not signed by CA
 				}
 
-				for _, revoked := range crl.RevokedCertificates {
-					if cert.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
-						return false, "" // Certificate is revoked, so not trusted anymore.
+				// The CRL only applies to the certificates issued by the CA which signed it.
+				if crl.CheckSignatureFrom(ca) == nil {
+					for _, revoked := range crl.RevokedCertificates {
+						if cert.SerialNumber.Cmp(revoked.SerialNumber) == 0 {
+							return false, "" // Certificate is revoked, so not trusted anymore.
+						}
 					}
 				}
 			}
@@ -212,6 +224,42 @@ func IsRecursionRequest(r *http.Request) bool {
 	return recursion != 0
 }
 
+// PaginationRequest returns the offset and limit requested through the "offset" and "limit" form values.
+// A limit of zero means that all entries past the offset are requested.
+func PaginationRequest(r *http.Request) (int, int, error) {
+	values := map[string]int{"offset": 0, "limit": 0}
+
+	for key := range values {
+		valueStr := r.FormValue(key)
+		if valueStr == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value < 0 {
+			return -1, -1, fmt.Errorf("Invalid %s %q", key, valueStr)
+		}
+
+		values[key] = value
+	}
+
+	return values["offset"], values["limit"], nil
+}
+
+// Paginate returns the entries of the list that fall within the given offset and limit.
+func Paginate[T any](list []T, offset int, limit int) []T {
+	if offset >= len(list) {
+		return []T{}
+	}
+
+	list = list[offset:]
+	if limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+
+	return list
+}
+
 // ListenAddresses returns a list of <host>:<port> combinations at which this machine can be reached.
 // It accepts the configured listen address in the following formats: <host>, <host>:<port> or :<port>.
 // If a listen port is not specified then then ports.HTTPSDefaultPort is used instead.
@@ -38,3 +38,17 @@ func ExampleListenAddresses() {
 	// "foo:8000:9000": [] address foo:8000:9000: too many colons in address
 	// ":::8000": [] address :::8000: too many colons in address
 }
+
+func ExamplePaginate() {
+	list := []string{"backup0", "backup1", "backup2", "backup3", "backup4"}
+
+	for _, page := range [][2]int{{0, 0}, {0, 2}, {2, 2}, {4, 2}, {6, 2}} {
+		fmt.Printf("offset=%d limit=%d: %v\n", page[0], page[1], Paginate(list, page[0], page[1]))
+	}
+
+	// Output: offset=0 limit=0: [backup0 backup1 backup2 backup3 backup4]
+	// offset=0 limit=2: [backup0 backup1]
+	// offset=2 limit=2: [backup2 backup3]
+	// offset=4 limit=2: [backup4]
+	// offset=6 limit=2: []
+}
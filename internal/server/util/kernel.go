@@ -6,6 +6,8 @@ import (
 	"os"
 	"slices"
 	"strings"
+
+	"github.com/lxc/incus/v6/shared/units"
 )
 
 // SupportsFilesystem checks whether a given filesystem is already supported
@@ -70,3 +72,36 @@ func HugepagesPath() (string, error) {
 
 	return matches[0], nil
 }
+
+// HugepagesPathForSize attempts to locate the mount point of a hugepages filesystem using the given page size.
+func HugepagesPathForSize(pageSize int64) (string, error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		cols := strings.Fields(scanner.Text())
+		if len(cols) < 4 || cols[2] != "hugetlbfs" {
+			continue
+		}
+
+		for _, opt := range strings.Split(cols[3], ",") {
+			value, ok := strings.CutPrefix(opt, "pagesize=")
+			if !ok {
+				continue
+			}
+
+			// The kernel reports the size with a K, M or G suffix.
+			size, err := units.ParseByteSizeString(value + "iB")
+			if err == nil && size == pageSize {
+				return cols[1], nil
+			}
+		}
+	}
+
+	return "", os.ErrNotExist
+}
@@ -80,7 +80,10 @@ func ServerTLSConfig(cert *localtls.CertInfo) *tls.Config {
 
 	if cert.CA() != nil {
 		pool := x509.NewCertPool()
-		pool.AddCert(cert.CA())
+		for _, ca := range cert.CAs() {
+			pool.AddCert(ca)
+		}
+
 		config.RootCAs = pool
 		config.ClientCAs = pool
 
@@ -1,10 +1,13 @@
 package util
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	incus "github.com/lxc/incus/v6/client"
 	localtls "github.com/lxc/incus/v6/shared/tls"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -13,13 +16,15 @@ import (
 //
 // If a cluster certificate is found it will be loaded instead.
 // If neither a server or cluster certfificate exists, a new server certificate will be generated.
-func LoadCert(dir string) (*localtls.CertInfo, error) {
+// If keyURI isn't empty, the private key of the server certificate is the PKCS#11 or TPM key it references.
+func LoadCert(dir string, keyURI string) (*localtls.CertInfo, error) {
 	prefix := "server"
 	if util.PathExists(filepath.Join(dir, "cluster.crt")) {
 		prefix = "cluster"
+		keyURI = ""
 	}
 
-	cert, err := localtls.KeyPairAndCA(dir, prefix, localtls.CertServer, true)
+	cert, err := loadKeyPairAndCA(dir, prefix, keyURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
 	}
@@ -42,9 +47,10 @@ func LoadClusterCert(dir string) (*localtls.CertInfo, error) {
 }
 
 // LoadServerCert reads the server certificate from the given var dir.
-func LoadServerCert(dir string) (*localtls.CertInfo, error) {
+// If keyURI isn't empty, the private key is the PKCS#11 or TPM key it references.
+func LoadServerCert(dir string, keyURI string) (*localtls.CertInfo, error) {
 	prefix := "server"
-	cert, err := localtls.KeyPairAndCA(dir, prefix, localtls.CertServer, true)
+	cert, err := loadKeyPairAndCA(dir, prefix, keyURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
 	}
@@ -52,6 +58,31 @@ func LoadServerCert(dir string) (*localtls.CertInfo, error) {
 	return cert, nil
 }
 
+// loadKeyPairAndCA loads a server key pair, using an external signer if a PKCS#11 or TPM key URI is given.
+func loadKeyPairAndCA(dir string, prefix string, keyURI string) (*localtls.CertInfo, error) {
+	if keyURI == "" {
+		return localtls.KeyPairAndCA(dir, prefix, localtls.CertServer, true)
+	}
+
+	cert, err := localtls.ReadCert(filepath.Join(dir, prefix+".crt"))
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := NewExternalSigner(keyURI, cert.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catch a wrong URI or a replaced certificate now rather than through failing TLS handshakes.
+	err = checkSignerPublicKey(signer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed checking private key %q against %q: %w", keyURI, prefix+".crt", err)
+	}
+
+	return localtls.KeyPairAndCAFromSigner(dir, prefix, signer)
+}
+
 // WriteCert writes the given material to the appropriate certificate files in
 // the given directory.
 func WriteCert(dir, prefix string, cert, key, ca []byte) error {
@@ -74,3 +105,42 @@ func WriteCert(dir, prefix string, cert, key, ca []byte) error {
 
 	return nil
 }
+
+// keyPairSessionCache is the TLS session cache shared by the connections using a key pair which can't be exported.
+var keyPairSessionCache = tls.NewLRUClientSessionCache(0)
+
+// keyPairTransport is an incus.HTTPTransporter which authenticates using a key pair directly
+// rather than through PEM encoded material.
+type keyPairTransport struct {
+	transport *http.Transport
+}
+
+// RoundTrip executes the request using the wrapped transport.
+func (t *keyPairTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport.RoundTrip(req)
+}
+
+// Transport returns the wrapped transport.
+func (t *keyPairTransport) Transport() *http.Transport {
+	return t.transport
+}
+
+// SetClientCertificate configures the connection arguments to authenticate using the given
+// certificate. This also works for private keys which can't be exported, such as keys held
+// in a PKCS#11 token or TPM.
+func SetClientCertificate(args *incus.ConnectionArgs, cert *localtls.CertInfo) {
+	key := cert.PrivateKey()
+	if key != nil {
+		args.TLSClientCert = string(cert.PublicKey())
+		args.TLSClientKey = string(key)
+		return
+	}
+
+	// Resume TLS sessions across connections to avoid a signing operation on every handshake.
+	keypair := cert.KeyPair()
+	args.TransportWrapper = func(t *http.Transport) incus.HTTPTransporter {
+		t.TLSClientConfig.Certificates = []tls.Certificate{keypair}
+		t.TLSClientConfig.ClientSessionCache = keyPairSessionCache
+		return &keyPairTransport{transport: t}
+	}
+}
@@ -0,0 +1,59 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+func TestLoadServerCertKeyURI(t *testing.T) {
+	dir := t.TempDir()
+
+	// Without a certificate, a key URI can't be used.
+	_, err := internalUtil.LoadServerCert(dir, "pkcs11:token=incus;object=server")
+	assert.Error(t, err)
+
+	certPEM, _, err := localtls.GenerateMemCert(false, false)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "server.crt"), certPEM, 0o644)
+	require.NoError(t, err)
+
+	// The private key is checked against the certificate when loading, so a missing token is caught right away.
+	// No key is generated on disk in its place.
+	_, err = internalUtil.LoadServerCert(dir, "pkcs11:token=missing;object=server")
+	assert.ErrorContains(t, err, "server.crt")
+	assert.NoFileExists(t, filepath.Join(dir, "server.key"))
+
+	// Unsupported URIs are rejected.
+	_, err = internalUtil.LoadServerCert(dir, "file:/var/lib/incus/server.key")
+	assert.Error(t, err)
+}
+
+func TestLoadCertKeyURI(t *testing.T) {
+	dir := t.TempDir()
+
+	// Without a key URI, a new key pair is generated on disk.
+	cert, err := internalUtil.LoadCert(dir, "")
+	require.NoError(t, err)
+	assert.NotNil(t, cert.PrivateKey())
+	assert.FileExists(t, filepath.Join(dir, "server.key"))
+
+	// Once clustered, the cluster key pair on disk is used instead.
+	err = os.Rename(filepath.Join(dir, "server.crt"), filepath.Join(dir, "cluster.crt"))
+	require.NoError(t, err)
+
+	err = os.Rename(filepath.Join(dir, "server.key"), filepath.Join(dir, "cluster.key"))
+	require.NoError(t, err)
+
+	clusterCert, err := internalUtil.LoadCert(dir, "pkcs11:token=incus;object=server")
+	require.NoError(t, err)
+	assert.NotNil(t, clusterCert.PrivateKey())
+	assert.Equal(t, cert.Fingerprint(), clusterCert.Fingerprint())
+}
@@ -0,0 +1,60 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// namePatternCounter matches the counter placeholder of name patterns, either `%d` or zero-padded like `%03d`.
+var namePatternCounter = regexp.MustCompile(`%(0[1-9][0-9]*)?d`)
+
+// NamePatternCounters returns the number of counter placeholders in a name pattern.
+func NamePatternCounters(pattern string) int {
+	return len(namePatternCounter.FindAllStringIndex(pattern, -1))
+}
+
+// NamePatternNextIndex returns the counter value following the highest one found in the names matching the pattern.
+func NamePatternNextIndex(pattern string, names []string) int {
+	loc := namePatternCounter.FindStringIndex(pattern)
+	if loc == nil {
+		return 0
+	}
+
+	prefix := pattern[:loc[0]]
+	suffix := pattern[loc[1]:]
+
+	next := 0
+	for _, name := range names {
+		if len(name) <= len(prefix)+len(suffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+
+		digits := name[len(prefix) : len(name)-len(suffix)]
+		if strings.Trim(digits, "0123456789") != "" {
+			continue
+		}
+
+		num, err := strconv.Atoi(digits)
+		if err != nil {
+			continue
+		}
+
+		if num >= next {
+			next = num + 1
+		}
+	}
+
+	return next
+}
+
+// NamePatternFormat replaces the counter placeholder of a name pattern with the given value.
+func NamePatternFormat(pattern string, index int) string {
+	loc := namePatternCounter.FindStringIndex(pattern)
+	if loc == nil {
+		return pattern
+	}
+
+	return pattern[:loc[0]] + fmt.Sprintf(pattern[loc[0]:loc[1]], index) + pattern[loc[1]:]
+}
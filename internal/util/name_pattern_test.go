@@ -0,0 +1,49 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+)
+
+func TestNamePatternNextIndex(t *testing.T) {
+	cases := []struct {
+		pattern string
+		names   []string
+		next    int
+	}{
+		{pattern: "snap%d", names: nil, next: 0},
+		{pattern: "snap%d", names: []string{"snap0", "snap4", "other7"}, next: 5},
+		{pattern: "snap%03d", names: []string{"snap000", "snap012"}, next: 13},
+		{pattern: "daily-%d-x", names: []string{"daily-2-x", "daily-3", "daily--x"}, next: 3},
+		{pattern: "20260101-%d", names: []string{"20260101-0", "20260101"}, next: 1},
+		{pattern: "snap", names: []string{"snap"}, next: 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.pattern, func(t *testing.T) {
+			assert.Equal(t, c.next, internalUtil.NamePatternNextIndex(c.pattern, c.names))
+		})
+	}
+}
+
+func TestNamePatternFormat(t *testing.T) {
+	assert.Equal(t, "snap7", internalUtil.NamePatternFormat("snap%d", 7))
+	assert.Equal(t, "snap-007-x", internalUtil.NamePatternFormat("snap-%03d-x", 7))
+	assert.Equal(t, "snap", internalUtil.NamePatternFormat("snap", 7))
+	assert.Equal(t, 2, internalUtil.NamePatternCounters("%d-%05d"))
+}
+
+func TestStrftime(t *testing.T) {
+	date := time.Date(2026, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	assert.Equal(t, "20260304-050607", internalUtil.Strftime(date, "%Y%m%d-%H%M%S"))
+	assert.Equal(t, "Wed 04 Mar %q 100%", internalUtil.Strftime(date, "%a %d %b %q 100%%"))
+
+	out, err := internalUtil.RenderTemplate("snap-{{ creation_date|strftime:'%Y-%m-%d' }}-%d", map[string]any{"creation_date": date})
+	assert.NoError(t, err)
+	assert.Equal(t, "snap-2026-03-04-%d", out)
+}
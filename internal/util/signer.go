@@ -0,0 +1,159 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// keyURIPrefixes maps the supported private key URI prefixes to the OpenSSL provider handling them.
+var keyURIPrefixes = map[string]string{
+	"pkcs11:": "pkcs11",
+	"handle:": "tpm2",
+}
+
+// externalSignTimeout is the maximum time spent on a single signing operation, including waiting for a slot.
+const externalSignTimeout = 30 * time.Second
+
+// externalSignSlots limits the number of concurrent signing operations.
+// Each of them runs an OpenSSL process and TLS handshakes, which unauthenticated clients can trigger, need one.
+var externalSignSlots = make(chan struct{}, 4)
+
+// ValidateKeyURI checks that the URI references a private key held in a PKCS#11 token or TPM.
+func ValidateKeyURI(uri string) error {
+	for prefix := range keyURIPrefixes {
+		if !strings.HasPrefix(uri, prefix) {
+			continue
+		}
+
+		// The URI is exposed through the server configuration, so it mustn't hold the token PIN.
+		if strings.Contains(uri, "pin-value=") {
+			return errors.New("The private key URI mustn't include the PIN, use pin-source instead")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported private key URI %q", uri)
+}
+
+// externalSigner is a crypto.Signer for a private key held in a PKCS#11 token or TPM.
+// Signing is delegated to OpenSSL and its pkcs11 or tpm2 provider.
+type externalSigner struct {
+	uri      string
+	provider string
+	public   crypto.PublicKey
+}
+
+// NewExternalSigner returns a crypto.Signer for the private key referenced by the URI, matching the given public key.
+func NewExternalSigner(uri string, public crypto.PublicKey) (crypto.Signer, error) {
+	err := ValidateKeyURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	for prefix, provider := range keyURIPrefixes {
+		if strings.HasPrefix(uri, prefix) {
+			return &externalSigner{uri: uri, provider: provider, public: public}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unsupported private key URI %q", uri)
+}
+
+// Public returns the public key matching the private key.
+func (s *externalSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign signs the digest using the external private key.
+func (s *externalSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hashNames := map[crypto.Hash]string{
+		crypto.SHA256: "sha256",
+		crypto.SHA384: "sha384",
+		crypto.SHA512: "sha512",
+	}
+
+	hashName, ok := hashNames[opts.HashFunc()]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported signature hash %q", opts.HashFunc())
+	}
+
+	args := []string{"pkeyutl", "-sign", "-provider", s.provider, "-provider", "default", "-inkey", s.uri, "-pkeyopt", "digest:" + hashName}
+
+	pssOpts, ok := opts.(*rsa.PSSOptions)
+	if ok {
+		saltLength := "digest"
+		if pssOpts.SaltLength == rsa.PSSSaltLengthAuto {
+			saltLength = "max"
+		} else if pssOpts.SaltLength > 0 {
+			saltLength = fmt.Sprintf("%d", pssOpts.SaltLength)
+		}
+
+		args = append(args, "-pkeyopt", "rsa_padding_mode:pss", "-pkeyopt", "rsa_pss_saltlen:"+saltLength)
+	}
+
+	// Signing happens during TLS handshakes, don't let an unresponsive token or TPM block them.
+	ctx, cancel := context.WithTimeout(context.Background(), externalSignTimeout)
+	defer cancel()
+
+	select {
+	case externalSignSlots <- struct{}{}:
+		defer func() { <-externalSignSlots }()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("Failed signing with %q: Too many concurrent signing operations", s.uri)
+	}
+
+	var stdout bytes.Buffer
+	err := subprocess.RunCommandWithFds(ctx, bytes.NewReader(digest), &stdout, "openssl", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing with %q: %w", s.uri, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// checkSignerPublicKey checks that the private key of the signer matches its public key by verifying a test signature.
+func checkSignerPublicKey(signer crypto.Signer) error {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(data)
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return err
+	}
+
+	switch public := signer.Public().(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature)
+		if err != nil {
+			return errors.New("The private key doesn't match the public key")
+		}
+
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(public, digest[:], signature) {
+			return errors.New("The private key doesn't match the public key")
+		}
+
+	default:
+		return fmt.Errorf("Unsupported public key type %T", public)
+	}
+
+	return nil
+}
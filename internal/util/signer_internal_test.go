@@ -0,0 +1,175 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateKeyURI(t *testing.T) {
+	tests := []struct {
+		uri         string
+		expectedErr bool
+	}{
+		{uri: "pkcs11:token=incus;object=server;type=private"},
+		{uri: "pkcs11:token=incus;object=server;type=private?pin-source=file:/etc/incus/pin"},
+		{uri: "handle:0x81000001"},
+		{uri: "pkcs11:token=incus;object=server;type=private?pin-value=1234", expectedErr: true},
+		{uri: "/var/lib/incus/server.key", expectedErr: true},
+		{uri: "file:/var/lib/incus/server.key", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			err := ValidateKeyURI(tt.uri)
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewExternalSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signer, err := NewExternalSigner("pkcs11:token=incus;object=server", key.Public())
+	require.NoError(t, err)
+	assert.Equal(t, "pkcs11", signer.(*externalSigner).provider)
+	assert.Equal(t, key.Public(), signer.Public())
+
+	signer, err = NewExternalSigner("handle:0x81000001", key.Public())
+	require.NoError(t, err)
+	assert.Equal(t, "tpm2", signer.(*externalSigner).provider)
+
+	_, err = NewExternalSigner("file:/var/lib/incus/server.key", key.Public())
+	assert.Error(t, err)
+}
+
+// writeKey writes the private key to a PEM file which OpenSSL's default provider can use in place of a token.
+func writeKey(t *testing.T, key crypto.Signer) string {
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "server.key")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data}), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestExternalSignerSign(t *testing.T) {
+	_, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("openssl is required")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	sha256Digest := sha256.Sum256([]byte("message"))
+	sha384Digest := sha512.Sum384([]byte("message"))
+
+	tests := []struct {
+		name   string
+		key    crypto.Signer
+		digest []byte
+		opts   crypto.SignerOpts
+		verify func(public crypto.PublicKey, digest []byte, signature []byte) error
+	}{
+		{
+			name:   "RSA PKCS#1 v1.5",
+			key:    rsaKey,
+			digest: sha256Digest[:],
+			opts:   crypto.SHA256,
+			verify: func(public crypto.PublicKey, digest []byte, signature []byte) error {
+				return rsa.VerifyPKCS1v15(public.(*rsa.PublicKey), crypto.SHA256, digest, signature)
+			},
+		},
+		{
+			name:   "RSA PSS",
+			key:    rsaKey,
+			digest: sha256Digest[:],
+			opts:   &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash},
+			verify: func(public crypto.PublicKey, digest []byte, signature []byte) error {
+				return rsa.VerifyPSS(public.(*rsa.PublicKey), crypto.SHA256, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			},
+		},
+		{
+			name:   "ECDSA",
+			key:    ecKey,
+			digest: sha384Digest[:],
+			opts:   crypto.SHA384,
+			verify: func(public crypto.PublicKey, digest []byte, signature []byte) error {
+				if !ecdsa.VerifyASN1(public.(*ecdsa.PublicKey), digest, signature) {
+					return rsa.ErrVerification
+				}
+
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := &externalSigner{uri: writeKey(t, tt.key), provider: "default", public: tt.key.Public()}
+
+			signature, err := signer.Sign(rand.Reader, tt.digest, tt.opts)
+			require.NoError(t, err)
+			assert.NoError(t, tt.verify(signer.Public(), tt.digest, signature))
+		})
+	}
+
+	// Unsupported hashes are rejected without calling OpenSSL.
+	signer := &externalSigner{uri: "pkcs11:token=incus", provider: "pkcs11", public: ecKey.Public()}
+	_, err = signer.Sign(rand.Reader, make([]byte, 20), crypto.SHA1)
+	assert.Error(t, err)
+}
+
+func TestCheckSignerPublicKey(t *testing.T) {
+	_, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("openssl is required")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherECKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// Matching keys.
+	for _, key := range []crypto.Signer{rsaKey, ecKey} {
+		signer := &externalSigner{uri: writeKey(t, key), provider: "default", public: key.Public()}
+		assert.NoError(t, checkSignerPublicKey(signer))
+	}
+
+	// Certificate of another key.
+	signer := &externalSigner{uri: writeKey(t, ecKey), provider: "default", public: otherECKey.Public()}
+	assert.Error(t, checkSignerPublicKey(signer))
+
+	// Unusable key.
+	signer = &externalSigner{uri: filepath.Join(t.TempDir(), "missing.key"), provider: "default", public: ecKey.Public()}
+	assert.Error(t, checkSignerPublicKey(signer))
+}
@@ -1,11 +1,83 @@
 package util
 
 import (
+	"errors"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/flosch/pongo2/v6"
 )
 
+// errStrftimeNotTime is returned when the strftime template filter is applied to something else than a date.
+var errStrftimeNotTime = errors.New("The strftime filter can only be applied to dates")
+
+// strftimeLayouts maps the supported strftime directives to their Go time layout.
+var strftimeLayouts = map[byte]string{
+	'a': "Mon",
+	'A': "Monday",
+	'b': "Jan",
+	'B': "January",
+	'd': "02",
+	'H': "15",
+	'I': "03",
+	'j': "002",
+	'm': "01",
+	'M': "04",
+	'p': "PM",
+	'S': "05",
+	'y': "06",
+	'Y': "2006",
+	'z': "-0700",
+	'Z': "MST",
+}
+
+func init() {
+	// Allow formatting dates with the strftime syntax, e.g. {{ creation_date|strftime:"%Y%m%d-%H%M" }}.
+	_ = pongo2.RegisterFilter("strftime", func(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+		t, ok := in.Interface().(time.Time)
+		if !ok {
+			return nil, &pongo2.Error{OrigError: errStrftimeNotTime, Sender: "filter:strftime"}
+		}
+
+		return pongo2.AsValue(Strftime(t, param.String())), nil
+	})
+}
+
+// Strftime formats the time using the strftime directives (%Y, %m, %d, %H, %M, %S, ...).
+// Unknown directives are kept as is.
+func Strftime(t time.Time, format string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+
+		i++
+		directive := format[i]
+
+		switch directive {
+		case '%':
+			sb.WriteByte('%')
+		case 's':
+			sb.WriteString(strconv.FormatInt(t.Unix(), 10))
+		default:
+			layout, ok := strftimeLayouts[directive]
+			if !ok {
+				sb.WriteByte('%')
+				sb.WriteByte(directive)
+				continue
+			}
+
+			sb.WriteString(t.Format(layout))
+		}
+	}
+
+	return sb.String()
+}
+
 // RenderTemplate renders a pongo2 template.
 func RenderTemplate(template string, ctx pongo2.Context) (string, error) {
 	// Load template from string
@@ -487,6 +487,96 @@ var APIExtensions = []string{
 	"network_ovn_external_nic_address",
 	"network_physical_gateway_hwaddr",
 	"backup_s3_upload",
+	"certificate_expiry",
+	"certificate_revocation",
+	"server_key_uri",
+	"certificate_groups",
+	"auth_tokens",
+	"certificate_tokens",
+	"certificate_labels",
+	"certificate_rotation",
+	"auth_ssh",
+	"openfga_ldap",
+	"auth_kerberos",
+	"auth_audit",
+	"certificate_instances",
+	"certificates_bundle",
+	"certificate_token_expiry",
+	"certificate_ca",
+	"certificate_allowed_networks",
+	"certificate_last_used",
+	"custom_volume_backup_schedule",
+	"backup_retention",
+	"custom_volume_backup_streaming",
+	"custom_volume_backup_import_remap",
+	"backup_export_resume",
+	"backup_groups",
+	"backup_import_url",
+	"custom_volume_backup_list_filter",
+	"storage_pool_backups_compression",
+	"custom_volume_snapshot_schedules",
+	"custom_volume_io_limits",
+	"storage_driver_nfs",
+	"storage_driver_san",
+	"storage_driver_glusterfs",
+	"storage_zfs_send_raw",
+	"storage_zfs_encryption",
+	"storage_ceph_rbd_mirror",
+	"storage_cephfs_subvolumes",
+	"storage_pool_resize_cluster",
+	"storage_pool_state",
+	"metrics_storage_volumes",
+	"backup_secret",
+	"custom_volume_disk_import",
+	"trash",
+	"storage_zfs_volume_properties",
+	"storage_bucket_versioning",
+	"storage_volume_shared_readonly",
+	"backup_targets",
+	"backup_files",
+	"backup_file_restore",
+	"backup_disk_export",
+	"storage_pool_snapshot_defaults",
+	"backup_throttle",
+	"instance_snapshot_retention",
+	"instance_state_pressure",
+	"instance_boot_depends_on",
+	"console_vnc_type",
+	"instance_exec_audit",
+	"instance_file_recursive",
+	"projects_restricted_files_paths",
+	"instance_blueprints",
+	"instance_secureboot_keys",
+	"device_usb_port",
+	"resources_gpu_mdev_used_by",
+	"instance_rebuild_backup",
+	"instance_copy_allow_running",
+	"migration_incremental_memory_timeout",
+	"instance_restart_policy",
+	"instance_crash_events",
+	"ovn_nic_limits",
+	"instance_boot_order",
+	"instance_cloud_init_status",
+	"instance_windows_drivers",
+	"resources_cpu_virtualization",
+	"instance_hugepages_size",
+	"snapshot_pattern_strftime",
+	"instance_publish_stateful",
+	"instance_lifecycle_hooks",
+	"instance_console_syslog",
+	"vm_disk_hotremove",
+	"network_bridge_metadata",
+	"instances_state_bulk",
+	"instance_processes",
+	"instance_state_disk_io",
+	"instance_clock",
+	"migration_state_progress",
+	"instance_security_delegate_cgroup",
+	"instance_cpu_isolation",
+	"cluster_healing_fencing",
+	"instances_placement_policy",
+	"instances_placement_scriptlet_metrics",
+	"cluster_rebalance_operation",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
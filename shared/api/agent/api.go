@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // API10Put contains the fields which are needed for the incus-agent to connect to Incus.
 type API10Put struct {
 	// Context ID
@@ -18,3 +22,10 @@ type API10Put struct {
 	// Example: true
 	DevIncus bool `json:"dev_incus" yaml:"dev_incus"`
 }
+
+// TimePut contains the time the incus-agent should set the guest clock to.
+type TimePut struct {
+	// Current time on the host
+	// Example: 2024-01-01T12:00:00Z
+	Time time.Time `json:"time" yaml:"time"`
+}
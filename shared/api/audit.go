@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+)
+
+const (
+	// AuditTypeRequest is the audit entry type for the authentication of an API request.
+	AuditTypeRequest = "request"
+
+	// AuditTypeTrustToken is the audit entry type for the redemption of a trust token.
+	AuditTypeTrustToken = "trust-token"
+)
+
+// AuditEntry represents an authentication decision.
+//
+// swagger:model
+//
+// API extension: auth_audit.
+type AuditEntry struct {
+	// When the decision was made
+	// Example: 2025-05-12T10:04:51.173468523Z
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// What was authenticated (request or trust-token)
+	// Example: request
+	Type string `json:"type" yaml:"type"`
+
+	// Whether the authentication succeeded
+	// Example: true
+	Success bool `json:"success" yaml:"success"`
+
+	// Authentication method (unix, tls, oidc, token, ssh or kerberos)
+	// Example: tls
+	Method string `json:"method" yaml:"method"`
+
+	// Identity of the client (username or certificate fingerprint)
+	// Example: 2de2a2d6c5dfb8c0a6e6b9b6c7a2f5c8f3b3a2f3b3a2f3b3a2f3b3a2f3b3a2f3
+	Identity string `json:"identity" yaml:"identity"`
+
+	// Address of the client
+	// Example: 10.0.0.10:48920
+	Address string `json:"address" yaml:"address"`
+
+	// Method and path of the request
+	// Example: GET /1.0/instances
+	Request string `json:"request" yaml:"request"`
+
+	// Reason for a failure
+	// Example: Client certificate isn't trusted
+	Reason string `json:"reason" yaml:"reason"`
+}
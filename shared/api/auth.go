@@ -6,4 +6,19 @@ const (
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodToken is the authentication method for API tokens issued by Incus.
+	//
+	// API extension: auth_tokens.
+	AuthenticationMethodToken = "token"
+
+	// AuthenticationMethodSSH is the authentication method for sessions obtained with a trusted SSH public key.
+	//
+	// API extension: auth_ssh.
+	AuthenticationMethodSSH = "ssh"
+
+	// AuthenticationMethodKerberos is the authentication method for Kerberos tickets sent through SPNEGO.
+	//
+	// API extension: auth_kerberos.
+	AuthenticationMethodKerberos = "kerberos"
 )
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"time"
+)
+
+// AuthSSHPost represents the fields of an SSH authentication request.
+// Without a signature, a new challenge is issued for the key.
+//
+// swagger:model
+//
+// API extension: auth_ssh.
+type AuthSSHPost struct {
+	// The SSH public key, in authorized_keys format
+	// Example: ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGxq4Kz5k0p3wq7BwS7n0YVmJ7JDy5sVx0hP0X8m3a8k user@host
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// The challenge previously issued for the key
+	// Example: 3e0d9dfb8e4a1c5f7b2e6a9d0c4f8b1a5e9d3c7f2b6a0e4d8c2f6b0a4e8d2c6f
+	Challenge string `json:"challenge" yaml:"challenge"`
+
+	// The signature of the challenge by the key
+	Signature *AuthSSHSignature `json:"signature" yaml:"signature"`
+}
+
+// AuthSSHSignature represents an SSH signature.
+//
+// swagger:model
+//
+// API extension: auth_ssh.
+type AuthSSHSignature struct {
+	// The signature format
+	// Example: ssh-ed25519
+	Format string `json:"format" yaml:"format"`
+
+	// The signature blob
+	// Example: base64 encoded signature
+	Blob []byte `json:"blob" yaml:"blob"`
+}
+
+// AuthSSHChallenge represents a challenge to be signed by an SSH key.
+//
+// swagger:model
+//
+// API extension: auth_ssh.
+type AuthSSHChallenge struct {
+	// The challenge to sign
+	// Example: 3e0d9dfb8e4a1c5f7b2e6a9d0c4f8b1a5e9d3c7f2b6a0e4d8c2f6b0a4e8d2c6f
+	Challenge string `json:"challenge" yaml:"challenge"`
+
+	// When the challenge expires
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthSSHSession represents a session obtained by signing a challenge with an SSH key.
+// The token is to be used as a bearer token.
+//
+// swagger:model
+//
+// API extension: auth_ssh.
+type AuthSSHSession struct {
+	// The session token
+	// Example: 5b3d6f5a8e1c4f2a9d7b0e3c6a1f8d4b2e9c7a0f3d6b1e8c4a2f9d7b0e3c6a1f
+	Token string `json:"token" yaml:"token"`
+
+	// When the session expires
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"time"
+)
+
+// AuthTokensPost represents the fields available for a new API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokensPost struct {
+	AuthTokenPut `yaml:",inline"`
+
+	// The name of the new API token
+	// Example: ci-runner
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthTokenPut represents the modifiable fields of an API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenPut struct {
+	// Description of the API token
+	// Example: Token used by the CI runners
+	Description string `json:"description" yaml:"description"`
+
+	// Whether to limit the token to the listed projects
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of allowed projects (applies when restricted)
+	// Example: ["default", "foo", "bar"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// When the token expires (never if unset)
+	// Example: 2031-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthToken represents an API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthToken struct {
+	AuthTokenPut `yaml:",inline"`
+
+	// The name of the API token
+	// Read only: true
+	// Example: ci-runner
+	Name string `json:"name" yaml:"name"`
+
+	// When the token was created
+	// Read only: true
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// Writable converts a full AuthToken struct into an AuthTokenPut struct (filters read-only fields).
+func (t *AuthToken) Writable() AuthTokenPut {
+	return t.AuthTokenPut
+}
+
+// URL returns the URL for the API token.
+func (t *AuthToken) URL(apiVersion string) *URL {
+	return NewURL().Path(apiVersion, "auth", "tokens", t.Name)
+}
+
+// AuthTokenSecret represents a newly issued API token along with its secret.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenSecret struct {
+	// The name of the API token
+	// Example: ci-runner
+	Name string `json:"name" yaml:"name"`
+
+	// The secret to send as a bearer token (only returned on creation)
+	// Example: 8f4c0a1b5ed7f0e6ddf0c7f5b0b6b0c5a0e1d4b2c9f3e8a7d6c5b4a3f2e1d0c9
+	Secret string `json:"secret" yaml:"secret"`
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"time"
+)
+
+// BackupGroup represents a set of backups taken at the same point in time
+//
+// swagger:model
+//
+// API extension: backup_groups.
+type BackupGroup struct {
+	// Backup group name
+	// Example: nightly
+	Name string `json:"name" yaml:"name"`
+
+	// When the backup group was created
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Name of the snapshot taken on all members of the group at the same point in time
+	// Example: nightly
+	Snapshot string `json:"snapshot" yaml:"snapshot"`
+
+	// Instance included in the group (if any)
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Custom volumes included in the group
+	Volumes []BackupGroupVolume `json:"volumes" yaml:"volumes"`
+
+	// What cluster member the backup group is stored on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+}
+
+// BackupGroupVolume represents a custom volume included in a backup group
+//
+// swagger:model
+//
+// API extension: backup_groups.
+type BackupGroupVolume struct {
+	// Storage pool name
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Custom volume name
+	// Example: data
+	Name string `json:"name" yaml:"name"`
+}
+
+// BackupGroupsPost represents the fields available for a new backup group
+//
+// swagger:model
+//
+// API extension: backup_groups.
+type BackupGroupsPost struct {
+	// Backup group name
+	// Example: nightly
+	Name string `json:"name" yaml:"name"`
+
+	// Instance to include, along with the custom volumes attached to it
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Custom volumes to include
+	Volumes []BackupGroupVolume `json:"volumes" yaml:"volumes"`
+
+	// What compression algorithm to use
+	// Example: gzip
+	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
+}
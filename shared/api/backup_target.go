@@ -0,0 +1,52 @@
+package api
+
+// NamedBackupTargetPost is used for renaming a server-side backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type NamedBackupTargetPost struct {
+	// The new name for the backup target
+	// Example: offsite
+	Name string `json:"name" yaml:"name"`
+}
+
+// NamedBackupTargetPut is used for updating a server-side backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type NamedBackupTargetPut struct {
+	// Description of the backup target
+	// Example: Off-site S3 storage
+	Description string `json:"description" yaml:"description"`
+
+	// Backup target configuration map (refer to doc/backup.md)
+	// Example: {"protocol": "s3", "url": "https://s3.example.net", "s3.bucket": "backups"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// NamedBackupTarget represents a server-side backup target which backups can be uploaded to by name.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type NamedBackupTarget struct {
+	NamedBackupTargetPost `yaml:",inline"`
+	NamedBackupTargetPut  `yaml:",inline"`
+}
+
+// Writable converts a full NamedBackupTarget struct into a NamedBackupTargetPut struct (filters read-only fields).
+func (t *NamedBackupTarget) Writable() NamedBackupTargetPut {
+	return t.NamedBackupTargetPut
+}
+
+// NamedBackupTargetsPost is used for creating a server-side backup target.
+//
+// swagger:model
+//
+// API extension: backup_targets.
+type NamedBackupTargetsPost struct {
+	NamedBackupTargetPost `yaml:",inline"`
+	NamedBackupTargetPut  `yaml:",inline"`
+}
@@ -0,0 +1,125 @@
+package api
+
+// BlueprintsPost represents the fields of a new blueprint
+//
+// swagger:model
+//
+// API extension: instance_blueprints.
+type BlueprintsPost struct {
+	BlueprintPut `yaml:",inline"`
+
+	// The name of the new blueprint
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+}
+
+// BlueprintPost represents the fields required to rename a blueprint
+//
+// swagger:model
+//
+// API extension: instance_blueprints.
+type BlueprintPost struct {
+	// The new name for the blueprint
+	// Example: web-server
+	Name string `json:"name" yaml:"name"`
+}
+
+// BlueprintPut represents the modifiable fields of a blueprint
+//
+// swagger:model
+//
+// API extension: instance_blueprints.
+type BlueprintPut struct {
+	// Description of the blueprint
+	// Example: Web server
+	Description string `json:"description" yaml:"description"`
+
+	// Type of instance (container or virtual-machine)
+	// Example: container
+	Type InstanceType `json:"type" yaml:"type"`
+
+	// Image to create the instance from
+	Source BlueprintSource `json:"source" yaml:"source"`
+
+	// List of profiles applied to the instance
+	// Example: ["default"]
+	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Instance configuration map (refer to doc/instances.md)
+	// Example: {"limits.cpu": "4", "limits.memory": "4GiB"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// List of devices
+	// Example: {"root": {"type": "disk", "pool": "default", "path": "/"}, "eth0": {"type": "nic", "network": "mybr0", "name": "eth0"}}
+	Devices map[string]map[string]string `json:"devices" yaml:"devices"`
+}
+
+// BlueprintSource represents the image an instance created from a blueprint is based on
+//
+// swagger:model
+//
+// API extension: instance_blueprints.
+type BlueprintSource struct {
+	// Source type (image or none)
+	// Example: image
+	Type string `json:"type" yaml:"type"`
+
+	// Image alias name
+	// Example: ubuntu/22.04
+	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"`
+
+	// Image fingerprint
+	// Example: ed56997f7c5b48e8d78986d2467a26109be6fb9f2d92e8c7b08eb8b6cec7629a
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+
+	// Image filters
+	// Example: {"os": "Ubuntu", "release": "jammy", "variant": "cloud"}
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+
+	// Remote server URL (for remote images)
+	// Example: https://images.linuxcontainers.org
+	Server string `json:"server,omitempty" yaml:"server,omitempty"`
+
+	// Protocol name (for remote images)
+	// Example: simplestreams
+	Protocol string `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+}
+
+// Blueprint represents a blueprint
+//
+// swagger:model
+//
+// API extension: instance_blueprints.
+type Blueprint struct {
+	BlueprintPut `yaml:",inline"`
+
+	// The blueprint name
+	// Read only: true
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+
+	// Version of the blueprint, incremented on every change
+	// Read only: true
+	// Example: 3
+	Version int64 `json:"version" yaml:"version"`
+
+	// Versions of the blueprint which can be selected, the current one being the last
+	// Read only: true
+	// Example: [1, 2, 3]
+	Versions []int64 `json:"versions" yaml:"versions"`
+
+	// Project name
+	// Read only: true
+	// Example: project1
+	Project string `json:"project" yaml:"project"`
+}
+
+// Writable converts a full Blueprint struct into a BlueprintPut struct (filters read-only fields).
+func (blueprint *Blueprint) Writable() BlueprintPut {
+	return blueprint.BlueprintPut
+}
+
+// URL returns the URL for the blueprint.
+func (blueprint *Blueprint) URL(apiVersion string, projectName string) *URL {
+	return NewURL().Path(apiVersion, "blueprints", blueprint.Name).Project(projectName)
+}
@@ -15,6 +15,16 @@ const CertificateTypeServer = "server"
 // CertificateTypeMetrics indicates a metrics certificate type.
 const CertificateTypeMetrics = "metrics"
 
+// CertificateTypeSSH indicates an SSH public key.
+//
+// API extension: auth_ssh.
+const CertificateTypeSSH = "ssh"
+
+// CertificateTypeCA indicates a trusted certificate authority.
+//
+// API extension: certificate_ca.
+const CertificateTypeCA = "ca"
+
 // CertificateTypeUnknown indicates an unknown certificate type.
 const CertificateTypeUnknown = "unknown"
 
@@ -33,6 +43,12 @@ type CertificatesPost struct {
 	//
 	// API extension: certificate_token
 	Token bool `json:"token" yaml:"token"`
+
+	// Time after which the certificate add token expires, overriding core.remote_token_expiry
+	// Example: 1H
+	//
+	// API extension: certificate_token_expiry
+	TokenExpiry string `json:"token_expiry" yaml:"token_expiry"`
 }
 
 // CertificatePut represents the modifiable fields of a certificate
@@ -72,6 +88,24 @@ type CertificatePut struct {
 	//
 	// API extension: certificate_description
 	Description string `json:"description" yaml:"description"`
+
+	// Free-form labels for the certificate
+	// Example: {"team": "web", "environment": "production"}
+	//
+	// API extension: certificate_labels
+	Labels map[string]string `json:"labels" yaml:"labels"`
+
+	// List of instance name patterns the certificate is limited to (applies when restricted)
+	// Example: ["ci-*"]
+	//
+	// API extension: certificate_instances
+	Instances []string `json:"instances" yaml:"instances"`
+
+	// List of networks (CIDR) the certificate can be used from (any if empty)
+	// Example: ["10.0.0.0/8"]
+	//
+	// API extension: certificate_allowed_networks
+	AllowedNetworks []string `json:"allowed_networks" yaml:"allowed_networks"`
 }
 
 // Certificate represents a certificate
@@ -84,6 +118,20 @@ type Certificate struct {
 	// Read only: true
 	// Example: fd200419b271f1dc2a5591b693cc5774b7f234e1ff8c6b78ad703b6888fe2b69
 	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// When the certificate expires
+	// Read only: true
+	// Example: 2031-03-23T17:38:37.753398689-04:00
+	//
+	// API extension: certificate_expiry
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// When the certificate was last used to authenticate (updated periodically)
+	// Read only: true
+	// Example: 2025-03-23T17:38:37.753398689-04:00
+	//
+	// API extension: certificate_last_used
+	LastUsedAt time.Time `json:"last_used_at" yaml:"last_used_at"`
 }
 
 // Writable converts a full Certificate struct into a CertificatePut struct (filters read-only fields).
@@ -96,6 +144,61 @@ func (c *Certificate) URL(apiVersion string) *URL {
 	return NewURL().Path(apiVersion, "certificates", c.Fingerprint)
 }
 
+// CertificateRotatePost represents the fields required to rotate a certificate
+//
+// swagger:model
+//
+// API extension: certificate_rotation.
+type CertificateRotatePost struct {
+	// The new certificate, as PEM encoded X509 (or as base64 encoded X509)
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// Base64 encoded signature proving possession of the new certificate's private key
+	// Example: MEUCIQDk...
+	Signature string `json:"signature" yaml:"signature"`
+}
+
+// CertificateToken represents a pending certificate add token.
+//
+// swagger:model
+//
+// API extension: certificate_tokens.
+type CertificateToken struct {
+	// ID of the token (matches the ID of the backing operation)
+	// Example: b043d632-5c48-44b3-983c-a25660d61164
+	ID string `json:"id" yaml:"id"`
+
+	// Name of the client the token was issued for
+	// Example: user@host
+	Name string `json:"name" yaml:"name"`
+
+	// Whether the certificate added with the token will be restricted
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of projects the certificate added with the token will be restricted to
+	// Example: ["default", "foo", "bar"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// Description of the certificate added with the token
+	// Example: X509 certificate
+	Description string `json:"description" yaml:"description"`
+
+	// When the token was issued
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// When the token expires (never if unset)
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// URL returns the URL for the certificate token.
+func (t *CertificateToken) URL(apiVersion string) *URL {
+	return NewURL().Path(apiVersion, "certificates", "tokens", t.ID)
+}
+
 // CertificateAddToken represents the fields contained within an encoded certificate add token.
 //
 // swagger:model
@@ -132,3 +235,45 @@ func (t *CertificateAddToken) String() string {
 
 	return base64.StdEncoding.EncodeToString(joinTokenJSON)
 }
+
+// CertificatesBundle represents a set of trusted certificates to be imported or exported.
+//
+// swagger:model
+//
+// API extension: certificates_bundle.
+type CertificatesBundle struct {
+	// List of certificates (PEM encoded X509 or SSH public keys) along with their metadata
+	Certificates []CertificatePut `json:"certificates" yaml:"certificates"`
+}
+
+// CertificatesImportPost represents the fields required to import a bundle of certificates.
+//
+// swagger:model
+//
+// API extension: certificates_bundle.
+type CertificatesImportPost struct {
+	CertificatesBundle `yaml:",inline"`
+
+	// What to do with certificates already in the trust store (skip, replace or fail)
+	// Example: skip
+	OnConflict string `json:"on_conflict" yaml:"on_conflict"`
+}
+
+// CertificatesImportResult represents the outcome of a certificates import.
+//
+// swagger:model
+//
+// API extension: certificates_bundle.
+type CertificatesImportResult struct {
+	// Fingerprints of the added certificates
+	// Example: ["fd200419b271f1dc2a5591b693cc5774b7f234e1ff8c6b78ad703b6888fe2b69"]
+	Added []string `json:"added" yaml:"added"`
+
+	// Fingerprints of the existing certificates which were replaced
+	// Example: []
+	Replaced []string `json:"replaced" yaml:"replaced"`
+
+	// Fingerprints of the existing certificates which were left untouched
+	// Example: []
+	Skipped []string `json:"skipped" yaml:"skipped"`
+}
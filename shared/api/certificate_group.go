@@ -0,0 +1,72 @@
+package api
+
+// CertificateGroupsPost represents the fields available for a new certificate group.
+//
+// swagger:model
+//
+// API extension: certificate_groups.
+type CertificateGroupsPost struct {
+	CertificateGroupPut `yaml:",inline"`
+
+	// The name of the new certificate group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// CertificateGroupPost represents the fields required to rename a certificate group.
+//
+// swagger:model
+//
+// API extension: certificate_groups.
+type CertificateGroupPost struct {
+	// The new name of the certificate group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// CertificateGroupPut represents the modifiable fields of a certificate group.
+//
+// swagger:model
+//
+// API extension: certificate_groups.
+type CertificateGroupPut struct {
+	// Description of the certificate group
+	// Example: Operations team
+	Description string `json:"description" yaml:"description"`
+
+	// Whether the group grants access to the listed projects (defaults to true)
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of projects granted to the group members (applies when restricted)
+	// Example: ["default", "foo", "bar"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// Fingerprints of the trusted certificates in the group
+	// Example: ["fd200419b271f1dc2a5591b693cc5774b7f234e1ff8c6b78ad703b6888fe2b69"]
+	Certificates []string `json:"certificates" yaml:"certificates"`
+}
+
+// CertificateGroup represents a certificate group.
+//
+// swagger:model
+//
+// API extension: certificate_groups.
+type CertificateGroup struct {
+	CertificateGroupPut `yaml:",inline"`
+
+	// The name of the certificate group
+	// Read only: true
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full CertificateGroup struct into a CertificateGroupPut struct (filters read-only fields).
+func (g *CertificateGroup) Writable() CertificateGroupPut {
+	return g.CertificateGroupPut
+}
+
+// URL returns the URL for the certificate group.
+func (g *CertificateGroup) URL(apiVersion string) *URL {
+	return NewURL().Path(apiVersion, "certificates", "groups", g.Name)
+}
@@ -256,6 +256,58 @@ type ClusterMemberStatePost struct {
 	Mode string `json:"mode" yaml:"mode"`
 }
 
+// ClusterRebalancePost represents the fields used to rebalance instances across the cluster.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_operation.
+type ClusterRebalancePost struct {
+	// Only compute the redistribution plan, without moving any instance
+	// Example: true
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+
+	// Restrict the rebalance to the members of a cluster group
+	// Example: default
+	Group string `json:"group" yaml:"group"`
+}
+
+// ClusterRebalancePlan represents an instance redistribution plan.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_operation.
+type ClusterRebalancePlan struct {
+	// Instance moves, in the order they are performed
+	Moves []ClusterRebalanceMove `json:"moves" yaml:"moves"`
+}
+
+// ClusterRebalanceMove represents the move of an instance between cluster members.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_operation.
+type ClusterRebalanceMove struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Cluster member the instance is moved from
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance is moved to
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated (false for stopped instances)
+	// Example: true
+	Live bool `json:"live" yaml:"live"`
+}
+
 // ClusterGroupsPost represents the fields available for a new cluster group.
 //
 // swagger:model
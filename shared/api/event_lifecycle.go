@@ -2,8 +2,24 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthTokenCreated                  = "auth-token-created"
+	EventLifecycleAuthTokenDeleted                  = "auth-token-deleted"
+	EventLifecycleAuthTokenUpdated                  = "auth-token-updated"
+	EventLifecycleBackupTargetCreated               = "backup-target-created"
+	EventLifecycleBackupTargetDeleted               = "backup-target-deleted"
+	EventLifecycleBackupTargetRenamed               = "backup-target-renamed"
+	EventLifecycleBackupTargetUpdated               = "backup-target-updated"
+	EventLifecycleBlueprintCreated                  = "blueprint-created"
+	EventLifecycleBlueprintDeleted                  = "blueprint-deleted"
+	EventLifecycleBlueprintRenamed                  = "blueprint-renamed"
+	EventLifecycleBlueprintUpdated                  = "blueprint-updated"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
+	EventLifecycleCertificateGroupCreated           = "certificate-group-created"
+	EventLifecycleCertificateGroupDeleted           = "certificate-group-deleted"
+	EventLifecycleCertificateGroupRenamed           = "certificate-group-renamed"
+	EventLifecycleCertificateGroupUpdated           = "certificate-group-updated"
+	EventLifecycleCertificateRotated                = "certificate-rotated"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
 	EventLifecycleClusterCertificateUpdated         = "cluster-certificate-updated"
 	EventLifecycleClusterDisabled                   = "cluster-disabled"
@@ -41,6 +57,7 @@ const (
 	EventLifecycleInstanceCreated                   = "instance-created"
 	EventLifecycleInstanceDeleted                   = "instance-deleted"
 	EventLifecycleInstanceExec                      = "instance-exec"
+	EventLifecycleInstanceExecFinished              = "instance-exec-finished"
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
@@ -52,6 +69,8 @@ const (
 	EventLifecycleInstanceMetadataTemplateRetrieved = "instance-metadata-template-retrieved"
 	EventLifecycleInstanceMetadataUpdated           = "instance-metadata-updated"
 	EventLifecycleInstanceMigrated                  = "instance-migrated"
+	EventLifecycleInstanceOOMKilled                 = "instance-oom-killed"
+	EventLifecycleInstancePanicked                  = "instance-panicked"
 	EventLifecycleInstancePaused                    = "instance-paused"
 	EventLifecycleInstanceReady                     = "instance-ready"
 	EventLifecycleInstanceRenamed                   = "instance-renamed"
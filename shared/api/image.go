@@ -68,6 +68,12 @@ type ImagesPost struct {
 	//
 	// API extension: image_create_aliases
 	Aliases []ImageAlias `json:"aliases" yaml:"aliases"`
+
+	// Whether to include a checkpoint of the running container in the image
+	// Example: true
+	//
+	// API extension: instance_publish_stateful
+	Stateful bool `json:"stateful" yaml:"stateful"`
 }
 
 // ImagesPostSource represents the source of a new image
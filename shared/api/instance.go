@@ -55,6 +55,18 @@ type InstancesPost struct {
 	//
 	// API extension: instance_create_start
 	Start bool `json:"start" yaml:"start"`
+
+	// Name of the blueprint to base the instance on
+	// Example: web
+	//
+	// API extension: instance_blueprints
+	Blueprint string `json:"blueprint,omitempty" yaml:"blueprint,omitempty"`
+
+	// Version of the blueprint to base the instance on (defaults to the current one)
+	// Example: 2
+	//
+	// API extension: instance_blueprints
+	BlueprintVersion int64 `json:"blueprint_version,omitempty" yaml:"blueprint_version,omitempty"`
 }
 
 // InstancesPut represents the fields available for a mass update.
@@ -346,7 +358,7 @@ type InstanceSource struct {
 	// Example: {"criu": "RANDOM-STRING", "rsync": "RANDOM-STRING"}
 	Websockets map[string]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
 
-	// Existing instance name or snapshot (for copy)
+	// Existing instance name or snapshot (for copy) or backup name (for rebuild)
 	// Example: foo/snap0
 	Source string `json:"source,omitempty" yaml:"source,omitempty"`
 
@@ -377,4 +389,28 @@ type InstanceSource struct {
 	//
 	// API extension: instance_allow_inconsistent_copy
 	AllowInconsistent bool `json:"allow_inconsistent" yaml:"allow_inconsistent"`
+
+	// Whether to copy a running instance from a quiesced temporary snapshot (for copy)
+	// Example: false
+	//
+	// API extension: instance_copy_allow_running
+	AllowRunning bool `json:"allow_running,omitempty" yaml:"allow_running,omitempty"`
+
+	// URL to download the backup from (for backup)
+	// Example: https://backups.example.com/c1.tar.gz
+	//
+	// API extension: backup_import_url
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// HTTP headers to send when downloading the backup (for backup)
+	// Example: {"Authorization": "Bearer 0123456789abcdef"}
+	//
+	// API extension: backup_import_url
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Expected SHA256 checksum of the backup (for backup)
+	// Example: sha256:2b4e7c5ba3a5b2c8ae4d3f6c8bf9ad1f2b4e7c5ba3a5b2c8ae4d3f6c8bf9ad1f
+	//
+	// API extension: backup_import_url
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
 }
@@ -10,6 +10,12 @@ import (
 //
 // API extension: backup_s3_upload.
 type BackupTarget struct {
+	// Name of a server-side backup target to use instead of the connection details below
+	// Example: offsite
+	//
+	// API extension: backup_targets
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
 	// Protocol is the upload protocol.
 	// Example: S3
 	Protocol string `json:"protocol" yaml:"protocol"`
@@ -35,6 +41,59 @@ type BackupTarget struct {
 	SecretKey string `json:"secret_key" yaml:"secret_key"`
 }
 
+// BackupFile represents an entry of a backup tarball.
+//
+// swagger:model
+//
+// API extension: backup_files.
+type BackupFile struct {
+	// Path of the entry within the backup tarball
+	// Example: backup/container/rootfs/etc/hostname
+	Path string `json:"path" yaml:"path"`
+
+	// Type of entry (file, directory, symlink, hardlink or other)
+	// Example: file
+	Type string `json:"type" yaml:"type"`
+
+	// Size of the file in bytes
+	// Example: 7
+	Size int64 `json:"size" yaml:"size"`
+
+	// Last modification time of the entry
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	ModifiedAt time.Time `json:"modified_at" yaml:"modified_at"`
+
+	// Target of the link (for symlinks and hardlinks)
+	// Example: /usr/share/zoneinfo/UTC
+	LinkTarget string `json:"link_target,omitempty" yaml:"link_target,omitempty"`
+}
+
+// BackupFilesPost represents a request to restore a path from a backup without restoring the whole backup.
+//
+// swagger:model
+//
+// API extension: backup_file_restore.
+type BackupFilesPost struct {
+	// Path of the file, symlink or directory within the backup tarball
+	// Example: backup/container/rootfs/etc/hostname
+	Source string `json:"source" yaml:"source"`
+
+	// Path to restore it to within the instance or volume (defaults to its original location)
+	// Example: /etc/hostname
+	Target string `json:"target" yaml:"target"`
+}
+
+// BackupSecretPost represents the fields available for a new instance or volume backup download secret.
+//
+// swagger:model
+//
+// API extension: backup_secret.
+type BackupSecretPost struct {
+	// How long the secret remains valid for (defaults to 1 hour)
+	// Example: 6H
+	Expiry string `json:"expiry" yaml:"expiry"`
+}
+
 // InstanceBackupsPost represents the fields available for a new instance backup.
 //
 // swagger:model
@@ -68,6 +127,12 @@ type InstanceBackupsPost struct {
 	//
 	// API extension: backup_s3_upload
 	Target *BackupTarget `json:"target" yaml:"target"`
+
+	// Rate limit for the backup creation and upload in bytes per second (overrides the server setting)
+	// Example: 50MB
+	//
+	// API extension: backup_throttle
+	Throttle string `json:"throttle" yaml:"throttle"`
 }
 
 // InstanceBackup represents an instance backup.
@@ -0,0 +1,20 @@
+package api
+
+// InstanceCloudInit represents the cloud-init status of an instance.
+//
+// swagger:model
+//
+// API extension: instance_cloud_init_status.
+type InstanceCloudInit struct {
+	// Current cloud-init status (not-started, running, done or error)
+	// Example: done
+	Status string `json:"status" yaml:"status"`
+
+	// Datasource used by cloud-init
+	// Example: DataSourceNoCloud [seed=/dev/sr0]
+	Datasource string `json:"datasource" yaml:"datasource"`
+
+	// Errors reported by cloud-init
+	// Example: ["Failed to run module scripts_user"]
+	Errors []string `json:"errors" yaml:"errors"`
+}
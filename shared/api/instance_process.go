@@ -0,0 +1,44 @@
+package api
+
+// InstanceProcess represents a process running inside an instance.
+//
+// swagger:model
+//
+// API extension: instance_processes.
+type InstanceProcess struct {
+	// Process ID (as seen from inside the instance)
+	// Example: 1234
+	PID int64 `json:"pid" yaml:"pid"`
+
+	// Parent process ID (as seen from inside the instance)
+	// Example: 1
+	ParentPID int64 `json:"parent_pid" yaml:"parent_pid"`
+
+	// User ID the process runs as (as seen from inside the instance)
+	// Example: 0
+	UID int64 `json:"uid" yaml:"uid"`
+
+	// Process name
+	// Example: sshd
+	Command string `json:"command" yaml:"command"`
+
+	// Full command line
+	// Example: ["/usr/sbin/sshd", "-D"]
+	CommandLine []string `json:"command_line" yaml:"command_line"`
+
+	// Process state (R, S, D, Z, ...)
+	// Example: S
+	State string `json:"state" yaml:"state"`
+
+	// Number of threads
+	// Example: 1
+	Threads int64 `json:"threads" yaml:"threads"`
+
+	// CPU time consumed (in nanoseconds)
+	// Example: 3140000000
+	CPUUsage int64 `json:"cpu_usage" yaml:"cpu_usage"`
+
+	// Resident memory (in bytes)
+	// Example: 7340032
+	MemoryUsage int64 `json:"memory_usage" yaml:"memory_usage"`
+}
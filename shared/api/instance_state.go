@@ -10,7 +10,7 @@ import (
 //
 // API extension: instances.
 type InstanceStatePut struct {
-	// State change action (start, stop, restart, freeze, unfreeze)
+	// State change action (start, stop, restart, freeze, unfreeze, sync-time)
 	// Example: start
 	Action string `json:"action" yaml:"action"`
 
@@ -70,6 +70,17 @@ type InstanceState struct {
 	//
 	// API extension: instances_state_os_info.
 	OSInfo *InstanceStateOSInfo `json:"os_info" yaml:"os_info"`
+
+	// Pressure stall information (PSI)
+	//
+	// API extension: instance_state_pressure.
+	Pressure *InstanceStatePressure `json:"pressure,omitempty" yaml:"pressure,omitempty"`
+
+	// Names of the devices the virtual machine will attempt to boot from, in order
+	// Example: ["eth0", "root"]
+	//
+	// API extension: instance_boot_order
+	BootOrder []string `json:"boot_order,omitempty" yaml:"boot_order,omitempty"`
 }
 
 // InstanceStateDisk represents the disk information section of an instance's state.
@@ -87,6 +98,42 @@ type InstanceStateDisk struct {
 	//
 	// API extension: instances_state_total
 	Total int64 `json:"total" yaml:"total"`
+
+	// Number of bytes read
+	// Example: 104857600
+	//
+	// API extension: instance_state_disk_io
+	ReadBytes int64 `json:"read_bytes" yaml:"read_bytes"`
+
+	// Number of completed read operations
+	// Example: 2048
+	//
+	// API extension: instance_state_disk_io
+	ReadOps int64 `json:"read_ops" yaml:"read_ops"`
+
+	// Total time spent on read operations in nanoseconds (0 if not available)
+	// Example: 1500000000
+	//
+	// API extension: instance_state_disk_io
+	ReadTime int64 `json:"read_time" yaml:"read_time"`
+
+	// Number of bytes written
+	// Example: 52428800
+	//
+	// API extension: instance_state_disk_io
+	WriteBytes int64 `json:"write_bytes" yaml:"write_bytes"`
+
+	// Number of completed write operations
+	// Example: 1024
+	//
+	// API extension: instance_state_disk_io
+	WriteOps int64 `json:"write_ops" yaml:"write_ops"`
+
+	// Total time spent on write operations in nanoseconds (0 if not available)
+	// Example: 3000000000
+	//
+	// API extension: instance_state_disk_io
+	WriteTime int64 `json:"write_time" yaml:"write_time"`
 }
 
 // InstanceStateCPU represents the cpu information section of an instance's state.
@@ -106,6 +153,58 @@ type InstanceStateCPU struct {
 	AllocatedTime int64 `json:"allocated_time" yaml:"allocated_time"`
 }
 
+// InstanceStatePressure represents the pressure stall information (PSI) section of an instance's state.
+//
+// swagger:model
+//
+// API extension: instance_state_pressure.
+type InstanceStatePressure struct {
+	// CPU pressure
+	CPU *InstanceStatePressureResource `json:"cpu,omitempty" yaml:"cpu,omitempty"`
+
+	// Memory pressure
+	Memory *InstanceStatePressureResource `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// IO pressure
+	IO *InstanceStatePressureResource `json:"io,omitempty" yaml:"io,omitempty"`
+}
+
+// InstanceStatePressureResource represents the pressure stall information for a single resource.
+//
+// swagger:model
+//
+// API extension: instance_state_pressure.
+type InstanceStatePressureResource struct {
+	// Time during which at least some tasks were stalled on the resource
+	Some InstanceStatePressureStats `json:"some" yaml:"some"`
+
+	// Time during which all non-idle tasks were stalled on the resource
+	Full InstanceStatePressureStats `json:"full" yaml:"full"`
+}
+
+// InstanceStatePressureStats represents the stall averages and total stall time of a resource.
+//
+// swagger:model
+//
+// API extension: instance_state_pressure.
+type InstanceStatePressureStats struct {
+	// Percentage of stalled time over the last 10 seconds
+	// Example: 1.25
+	Avg10 float64 `json:"avg10" yaml:"avg10"`
+
+	// Percentage of stalled time over the last 60 seconds
+	// Example: 0.8
+	Avg60 float64 `json:"avg60" yaml:"avg60"`
+
+	// Percentage of stalled time over the last 300 seconds
+	// Example: 0.31
+	Avg300 float64 `json:"avg300" yaml:"avg300"`
+
+	// Total stalled time in microseconds
+	// Example: 3154831
+	Total uint64 `json:"total" yaml:"total"`
+}
+
 // InstanceStateMemory represents the memory information section of an instance's state.
 //
 // swagger:model
@@ -133,6 +232,18 @@ type InstanceStateMemory struct {
 	// Peak SWAP usage in bytes
 	// Example: 12297557
 	SwapUsagePeak int64 `json:"swap_usage_peak" yaml:"swap_usage_peak"`
+
+	// Number of processes killed for running out of memory
+	// Example: 0
+	//
+	// API extension: instance_crash_events
+	OOMKills int64 `json:"oom_kills" yaml:"oom_kills"`
+
+	// Huge pages memory usage in bytes (virtual machines only)
+	// Example: 2147483648
+	//
+	// API extension: instance_hugepages_size
+	HugepagesUsage int64 `json:"hugepages_usage,omitempty" yaml:"hugepages_usage,omitempty"`
 }
 
 // InstanceStateNetwork represents the network information section of an instance's state.
@@ -8,3 +8,39 @@ const SecretNameFilesystem = "fs"
 
 // SecretNameState is the secret name used for the migration state connection.
 const SecretNameState = "criu" // Legacy value used for backward compatibility for clients.
+
+// MigrationStateProgress represents the progress of the transfer of the running state of an instance
+// during a live migration, as found in the "state_transfer" field of the operation metadata.
+//
+// swagger:model
+//
+// API extension: migration_state_progress.
+type MigrationStateProgress struct {
+	// Current iteration of the memory transfer (pre-copy pass for virtual machines, pre-dump for containers)
+	// Example: 3
+	Iteration int64 `json:"iteration" yaml:"iteration"`
+
+	// Number of bytes transferred so far
+	// Example: 1073741824
+	TransferredBytes int64 `json:"transferred_bytes" yaml:"transferred_bytes"`
+
+	// Number of bytes left to transfer in the current iteration
+	// Example: 268435456
+	RemainingBytes int64 `json:"remaining_bytes" yaml:"remaining_bytes"`
+
+	// Total memory size of the instance in bytes (0 if unknown)
+	// Example: 4294967296
+	TotalBytes int64 `json:"total_bytes" yaml:"total_bytes"`
+
+	// Rate at which the instance dirties its memory in bytes per second
+	// Example: 10485760
+	DirtyRate int64 `json:"dirty_rate" yaml:"dirty_rate"`
+
+	// Transfer speed in bytes per second
+	// Example: 125000000
+	Speed int64 `json:"speed" yaml:"speed"`
+
+	// Estimated time to transfer the remaining bytes at the current speed in seconds (-1 if unknown)
+	// Example: 2
+	ETA int64 `json:"eta" yaml:"eta"`
+}
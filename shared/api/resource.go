@@ -66,6 +66,26 @@ type ResourcesCPU struct {
 	// Total number of CPU threads (from all sockets and cores)
 	// Example: 1
 	Total uint64 `json:"total" yaml:"total"`
+
+	// Hardware virtualization support (nil if not available)
+	//
+	// API extension: resources_cpu_virtualization
+	Virtualization *ResourcesCPUVirtualization `json:"virtualization,omitempty" yaml:"virtualization,omitempty"`
+}
+
+// ResourcesCPUVirtualization represents the hardware virtualization support of the system
+//
+// swagger:model
+//
+// API extension: resources_cpu_virtualization.
+type ResourcesCPUVirtualization struct {
+	// Hardware virtualization extension
+	// Example: vmx
+	Extension string `json:"extension" yaml:"extension"`
+
+	// Whether nested virtualization is available to virtual machines
+	// Example: true
+	Nested bool `json:"nested" yaml:"nested"`
 }
 
 // ResourcesCPUSocket represents a CPU socket on the system
@@ -379,6 +399,12 @@ type ResourcesGPUCardMdev struct {
 	// List of active devices (UUIDs)
 	// Example: ["42200aac-0977-495c-8c9e-6c51b9092a01", "b4950c00-1437-41d9-88f6-28d61cf9b9ef"]
 	Devices []string `json:"devices" yaml:"devices"`
+
+	// List of instances using the devices of this profile
+	// Example: ["/1.0/instances/vm01"]
+	//
+	// API extension: resources_gpu_mdev_used_by
+	UsedBy []string `json:"used_by,omitempty" yaml:"used_by,omitempty"`
 }
 
 // ResourcesNetwork represents the network cards available on the system
@@ -31,3 +31,20 @@ type InstancePlacement struct {
 	Reason  string `json:"reason" yaml:"reason"`
 	Project string `json:"project" yaml:"project"`
 }
+
+// ClusterMemberMetrics represents the live metrics of a cluster member.
+//
+// API extension: instances_placement_scriptlet_metrics.
+type ClusterMemberMetrics struct {
+	CPUThreads       uint64  `json:"cpu_threads" yaml:"cpu_threads"`
+	LoadAverage1Min  float64 `json:"load_average_1min" yaml:"load_average_1min"`
+	LoadAverage5Min  float64 `json:"load_average_5min" yaml:"load_average_5min"`
+	LoadAverage10Min float64 `json:"load_average_10min" yaml:"load_average_10min"`
+	MemoryUsed       uint64  `json:"memory_used" yaml:"memory_used"`
+	MemoryTotal      uint64  `json:"memory_total" yaml:"memory_total"`
+	Processes        int     `json:"processes" yaml:"processes"`
+
+	Instances          int    `json:"instances" yaml:"instances"`
+	ReservedCPUCores   uint64 `json:"reserved_cpu_cores" yaml:"reserved_cpu_cores"`
+	ReservedMemorySize uint64 `json:"reserved_memory_size" yaml:"reserved_memory_size"`
+}
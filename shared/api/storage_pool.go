@@ -97,4 +97,43 @@ func (storagePool *StoragePool) Writable() StoragePoolPut {
 // API extension: cluster_member_state.
 type StoragePoolState struct {
 	ResourcesStoragePool `yaml:",inline"`
+
+	// Health of the storage pool
+	//
+	// API extension: storage_pool_state
+	Health *StoragePoolStateHealth `json:"health,omitempty" yaml:"health,omitempty"`
+}
+
+// StoragePoolStatePut represents the modifiable fields of a storage pool's state
+//
+// swagger:model
+//
+// API extension: storage_pool_state.
+type StoragePoolStatePut struct {
+	// State change action (scrub)
+	// Example: scrub
+	Action string `json:"action" yaml:"action"`
+}
+
+// StoragePoolStateHealth represents the health of a storage pool
+//
+// swagger:model
+//
+// API extension: storage_pool_state.
+type StoragePoolStateHealth struct {
+	// Health status (healthy, degraded or failed)
+	// Example: healthy
+	Status string `json:"status" yaml:"status"`
+
+	// Driver specific description of the health
+	// Example: ONLINE
+	Description string `json:"description" yaml:"description"`
+
+	// Number of I/O and checksum errors reported by the storage
+	// Example: 0
+	Errors uint64 `json:"errors" yaml:"errors"`
+
+	// Status of the last scrub (none, running, finished or canceled, empty if not reported)
+	// Example: finished
+	ScrubStatus string `json:"scrub_status" yaml:"scrub_status"`
 }
@@ -248,6 +248,24 @@ type StorageVolumeSource struct {
 	//
 	// API extension: cluster_internal_custom_volume_copy
 	Location string `json:"location" yaml:"location"`
+
+	// URL to download the backup from (for backup)
+	// Example: https://backups.example.com/foo.tar.gz
+	//
+	// API extension: backup_import_url
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// HTTP headers to send when downloading the backup (for backup)
+	// Example: {"Authorization": "Bearer 0123456789abcdef"}
+	//
+	// API extension: backup_import_url
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Expected SHA256 checksum of the backup (for backup)
+	// Example: sha256:2b4e7c5ba3a5b2c8ae4d3f6c8bf9ad1f2b4e7c5ba3a5b2c8ae4d3f6c8bf9ad1f
+	//
+	// API extension: backup_import_url
+	Checksum string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
 }
 
 // Writable converts a full StorageVolume struct into a StorageVolumePut struct (filters read-only fields).
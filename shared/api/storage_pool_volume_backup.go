@@ -58,10 +58,16 @@ type StorageVolumeBackupsPost struct {
 	CompressionAlgorithm string `json:"compression_algorithm" yaml:"compression_algorithm"`
 
 	// External upload target
-	// The backup will be uploaded and then deleted from local storage.
+	// The backup is streamed to the target and never stored locally.
 	//
 	// API extension: backup_s3_upload
 	Target *BackupTarget `json:"target" yaml:"target"`
+
+	// Rate limit for the backup creation and upload in bytes per second (overrides the server setting)
+	// Example: 50MB
+	//
+	// API extension: backup_throttle
+	Throttle string `json:"throttle" yaml:"throttle"`
 }
 
 // StorageVolumeBackupPost represents the fields available for the renaming of a volume backup
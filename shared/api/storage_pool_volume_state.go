@@ -1,5 +1,20 @@
 package api
 
+// StorageVolumeStatePut represents the modifiable fields of a volume's state
+//
+// swagger:model
+//
+// API extension: storage_ceph_rbd_mirror.
+type StorageVolumeStatePut struct {
+	// State change action (promote or demote)
+	// Example: promote
+	Action string `json:"action" yaml:"action"`
+
+	// Whether to force the action (for promote)
+	// Example: false
+	Force bool `json:"force" yaml:"force"`
+}
+
 // StorageVolumeState represents the live state of the volume
 //
 // swagger:model
@@ -8,6 +23,11 @@ package api
 type StorageVolumeState struct {
 	// Volume usage
 	Usage *StorageVolumeStateUsage `json:"usage" yaml:"usage"`
+
+	// Volume mirroring state
+	//
+	// API extension: storage_ceph_rbd_mirror
+	Mirror *StorageVolumeStateMirror `json:"mirror,omitempty" yaml:"mirror,omitempty"`
 }
 
 // StorageVolumeStateUsage represents the disk usage of a volume
@@ -26,3 +46,26 @@ type StorageVolumeStateUsage struct {
 	// API extension: storage_volume_state_total
 	Total int64 `json:"total" yaml:"total"`
 }
+
+// StorageVolumeStateMirror represents the mirroring state of a volume
+//
+// swagger:model
+//
+// API extension: storage_ceph_rbd_mirror.
+type StorageVolumeStateMirror struct {
+	// Mirroring mode (journal or snapshot)
+	// Example: snapshot
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Whether this copy of the volume is the primary one
+	// Example: true
+	Primary bool `json:"primary" yaml:"primary"`
+
+	// Replication state
+	// Example: up+stopped
+	State string `json:"state" yaml:"state"`
+
+	// Description of the replication state
+	// Example: local image is primary
+	Description string `json:"description" yaml:"description"`
+}
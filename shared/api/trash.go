@@ -0,0 +1,61 @@
+package api
+
+import (
+	"time"
+)
+
+// TrashEntryTypeInstance is the type of trash entries holding an instance.
+const TrashEntryTypeInstance = "instance"
+
+// TrashEntryTypeStorageVolume is the type of trash entries holding a custom storage volume.
+const TrashEntryTypeStorageVolume = "storage-volume"
+
+// TrashEntry represents a deleted instance or custom storage volume kept in the trash.
+//
+// swagger:model
+//
+// API extension: trash.
+type TrashEntry struct {
+	// UUID of the trash entry
+	// Example: 5d9b3a6c-1a2f-4c1e-9d0c-6f0e2e8a3b71
+	UUID string `json:"uuid" yaml:"uuid"`
+
+	// Type of the deleted entity (instance or storage-volume)
+	// Example: instance
+	Type string `json:"type" yaml:"type"`
+
+	// Name of the deleted entity
+	// Example: c1
+	Name string `json:"name" yaml:"name"`
+
+	// The project the entity was deleted from
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// The storage pool the entity was deleted from
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+
+	// What cluster member the entry is stored on
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// When the entity was deleted
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	DeletedAt time.Time `json:"deleted_at" yaml:"deleted_at"`
+
+	// When the entry will be purged from the trash
+	// Example: 2021-03-30T17:38:37.753398689-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// TrashEntryPost represents the fields required to restore an entry from the trash.
+//
+// swagger:model
+//
+// API extension: trash.
+type TrashEntryPost struct {
+	// Name to restore the entity as (defaults to its original name)
+	// Example: c1-restored
+	Name string `json:"name" yaml:"name"`
+}
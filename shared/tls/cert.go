@@ -6,7 +6,9 @@
 package tls
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -40,7 +42,7 @@ import (
 //
 // <prefix>.crt -> public key
 // <prefix>.key -> private key
-// <prefix>.ca  -> CA certificate (optional)
+// <prefix>.ca  -> CA certificate and intermediates (optional)
 // ca.crl       -> CA certificate revocation list (optional)
 //
 // If no public/private key files are found, a new key pair will be generated
@@ -65,13 +67,69 @@ func KeyPairAndCA(dir, prefix string, kind CertKind, addHosts bool) (*CertInfo,
 		return nil, err
 	}
 
+	cas, crl, err := loadCAAndCRL(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CertInfo{
+		keypair: keypair,
+		cas:     cas,
+		crl:     crl,
+	}
+
+	return info, nil
+}
+
+// KeyPairAndCAFromSigner is similar to KeyPairAndCA but rather than reading
+// the private key from <prefix>.key, it uses the provided signer. This allows
+// for private keys that can't be exported, such as keys stored in a hardware
+// token.
+//
+// The <prefix>.crt file must already exist and match the signer's public key.
+func KeyPairAndCAFromSigner(dir string, prefix string, signer crypto.Signer) (*CertInfo, error) {
+	certFilename := filepath.Join(dir, prefix+".crt")
+
+	cert, err := ReadCert(certFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pubKey.Equal(cert.PublicKey) {
+		return nil, fmt.Errorf("Private key doesn't match certificate %q", certFilename)
+	}
+
+	cas, crl, err := loadCAAndCRL(dir, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CertInfo{
+		keypair: tls.Certificate{
+			Certificate: [][]byte{cert.Raw},
+			PrivateKey:  signer,
+			Leaf:        cert,
+		},
+		cas: cas,
+		crl: crl,
+	}
+
+	return info, nil
+}
+
+// loadCAAndCRL loads the optional <prefix>.ca and ca.crl files from the given directory.
+// The CA file may contain several certificates, typically a CA along with its intermediates.
+func loadCAAndCRL(dir string, prefix string) ([]*x509.Certificate, *x509.RevocationList, error) {
+	var err error
+
 	// If available, load the CA data as well.
 	caFilename := filepath.Join(dir, prefix+".ca")
-	var ca *x509.Certificate
+	var cas []*x509.Certificate
 	if util.PathExists(caFilename) {
-		ca, err = ReadCert(caFilename)
+		cas, err = ReadCerts(caFilename)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -80,27 +138,21 @@ func KeyPairAndCA(dir, prefix string, kind CertKind, addHosts bool) (*CertInfo,
 	if util.PathExists(crlFilename) {
 		data, err := os.ReadFile(crlFilename)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		pemData, _ := pem.Decode(data)
 		if pemData == nil {
-			return nil, errors.New("Invalid revocation list")
+			return nil, nil, errors.New("Invalid revocation list")
 		}
 
 		crl, err = x509.ParseRevocationList(pemData.Bytes)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	info := &CertInfo{
-		keypair: keypair,
-		ca:      ca,
-		crl:     crl,
-	}
-
-	return info, nil
+	return cas, crl, nil
 }
 
 // KeyPairFromRaw returns a CertInfo from the raw certificate and key.
@@ -123,7 +175,7 @@ func KeyPairFromRaw(certificate []byte, key []byte) (*CertInfo, error) {
 // (see doc/security.md for more details).
 type CertInfo struct {
 	keypair tls.Certificate
-	ca      *x509.Certificate
+	cas     []*x509.Certificate
 	crl     *x509.RevocationList
 }
 
@@ -134,7 +186,27 @@ func (c *CertInfo) KeyPair() tls.Certificate {
 
 // CA returns the CA certificate.
 func (c *CertInfo) CA() *x509.Certificate {
-	return c.ca
+	if len(c.cas) == 0 {
+		return nil
+	}
+
+	return c.cas[0]
+}
+
+// CAs returns all the CA certificates, including intermediates.
+func (c *CertInfo) CAs() []*x509.Certificate {
+	return c.cas
+}
+
+// IssuingCA returns the CA certificate which signed the given certificate, or nil if none did.
+func (c *CertInfo) IssuingCA(cert *x509.Certificate) *x509.Certificate {
+	for _, ca := range c.cas {
+		if cert.CheckSignatureFrom(ca) == nil {
+			return ca
+		}
+	}
+
+	return nil
 }
 
 // PublicKey is a convenience to encode the underlying public key to ASCII.
@@ -386,6 +458,46 @@ func ReadCert(fpath string) (*x509.Certificate, error) {
 	return x509.ParseCertificate(certBlock.Bytes)
 }
 
+// ReadCerts reads all the PEM encoded certificates of a file.
+func ReadCerts(fpath string) ([]*x509.Certificate, error) {
+	cf, err := os.ReadFile(fpath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCerts(cf)
+}
+
+// ParseCerts parses a list of PEM encoded certificates, such as a certificate chain.
+func ParseCerts(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var certBlock *pem.Block
+		certBlock, data = pem.Decode(data)
+		if certBlock == nil {
+			break
+		}
+
+		if certBlock.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("Invalid certificate file")
+	}
+
+	return certs, nil
+}
+
 // CertFingerprint returns the SHA256 fingerprint string of an x509 certificate.
 func CertFingerprint(cert *x509.Certificate) string {
 	return fmt.Sprintf("%x", sha256.Sum256(cert.Raw))
@@ -406,6 +518,45 @@ func CertFingerprintStr(c string) (string, error) {
 	return CertFingerprint(cert), nil
 }
 
+// certificateRotationMessage returns the message signed with the key of the new certificate when rotating a trusted certificate.
+func certificateRotationMessage(oldFingerprint string, newCert *x509.Certificate) []byte {
+	return []byte("incus-certificate-rotation:" + oldFingerprint + ":" + CertFingerprint(newCert))
+}
+
+// SignCertificateRotation proves the possession of the private key of the new certificate
+// when rotating the trusted certificate with the given fingerprint.
+func SignCertificateRotation(oldFingerprint string, newCert *x509.Certificate, key crypto.Signer) ([]byte, error) {
+	message := certificateRotationMessage(oldFingerprint, newCert)
+
+	_, isEd25519 := key.Public().(ed25519.PublicKey)
+	if isEd25519 {
+		return key.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+
+	digest := sha256.Sum256(message)
+
+	return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// CheckCertificateRotation verifies that the signature of the rotation of the trusted certificate with the
+// given fingerprint was made with the private key of the new certificate.
+func CheckCertificateRotation(oldFingerprint string, newCert *x509.Certificate, signature []byte) error {
+	var algorithm x509.SignatureAlgorithm
+
+	switch newCert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	case ed25519.PublicKey:
+		algorithm = x509.PureEd25519
+	default:
+		return errors.New("Unsupported public key type")
+	}
+
+	return newCert.CheckSignature(algorithm, certificateRotationMessage(oldFingerprint, newCert), signature)
+}
+
 // GetRemoteCertificate gets the x509 certificate from a remote HTTPS server.
 func GetRemoteCertificate(address string, useragent string) (*x509.Certificate, error) {
 	// Setup a permissive TLS config
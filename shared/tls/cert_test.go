@@ -1,6 +1,8 @@
 package tls
 
 import (
+	"crypto"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"os"
@@ -100,3 +102,56 @@ func TestGenerateMemCert(t *testing.T) {
 		t.Errorf("GenerateMemCert returned a cert with Type %q not \"EC PRIVATE KEY\"", block.Type)
 	}
 }
+
+func TestCertificateRotationSignature(t *testing.T) {
+	certPEM, keyPEM, err := GenerateMemCert(true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		t.Fatal("Private key isn't a signer")
+	}
+
+	signature, err := SignCertificateRotation("abcd", newCert, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = CheckCertificateRotation("abcd", newCert, signature)
+	if err != nil {
+		t.Errorf("Valid rotation signature was rejected: %v", err)
+	}
+
+	err = CheckCertificateRotation("efgh", newCert, signature)
+	if err == nil {
+		t.Error("Rotation signature for another certificate was accepted")
+	}
+
+	otherPEM, _, err := GenerateMemCert(true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := pem.Decode(otherPEM)
+	otherCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = CheckCertificateRotation("abcd", otherCert, signature)
+	if err == nil {
+		t.Error("Rotation signature made with another key was accepted")
+	}
+}
@@ -2,6 +2,9 @@ package validate
 
 import (
 	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
@@ -814,6 +817,69 @@ func IsYAML(value string) error {
 	return nil
 }
 
+// IsPEMCertificates checks value contains one or more PEM encoded X.509 certificates.
+func IsPEMCertificates(value string) error {
+	rest := []byte(value)
+	count := 0
+
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("Unexpected PEM block type %q", block.Type)
+		}
+
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("Invalid certificate: %w", err)
+		}
+
+		count++
+	}
+
+	if count == 0 {
+		return errors.New("No PEM encoded certificate found")
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return errors.New("Unexpected data after the PEM encoded certificates")
+	}
+
+	return nil
+}
+
+// IsPEMCertificate checks value contains exactly one PEM encoded X.509 certificate.
+func IsPEMCertificate(value string) error {
+	err := IsPEMCertificates(value)
+	if err != nil {
+		return err
+	}
+
+	block, rest := pem.Decode([]byte(value))
+	if block != nil {
+		next, _ := pem.Decode(rest)
+		if next != nil {
+			return errors.New("Only a single certificate is allowed")
+		}
+	}
+
+	return nil
+}
+
+// IsBase64 checks value is valid base64 encoded data.
+func IsBase64(value string) error {
+	_, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("Invalid base64 data: %w", err)
+	}
+
+	return nil
+}
+
 // IsValidCPUSet checks value is a valid CPU set.
 func IsValidCPUSet(value string) error {
 	// Validate the CPU set syntax.
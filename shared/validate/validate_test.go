@@ -140,3 +140,21 @@ func ExampleIsValidCPUSet() {
 	// Cannot define CPU multiple times
 	// Cannot define CPU multiple times
 }
+
+func ExampleIsPEMCertificates() {
+	tests := []string{
+		"",
+		"foo",
+		"-----BEGIN PUBLIC KEY-----\nZm9v\n-----END PUBLIC KEY-----\n",
+		"-----BEGIN CERTIFICATE-----\nZm9v\n-----END CERTIFICATE-----\n",
+	}
+
+	for _, v := range tests {
+		fmt.Println(validate.IsPEMCertificates(v))
+	}
+
+	// Output: No PEM encoded certificate found
+	// No PEM encoded certificate found
+	// Unexpected PEM block type "PUBLIC KEY"
+	// Invalid certificate: x509: malformed certificate
+}